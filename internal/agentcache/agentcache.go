@@ -0,0 +1,141 @@
+// Package agentcache caches one-shot agent replies by prompt, so formulas
+// that opt in don't pay for identical agent calls on unchanged input.
+package agentcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+)
+
+// Dir returns the town's agent reply cache directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "cache", "agent")
+}
+
+// ParseTTL parses a cache_ttl value such as "30m", "6h", or "7d".
+func ParseTTL(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		if _, err = fmt.Sscanf(days, "%d", &n); err == nil {
+			d = time.Duration(n) * 24 * time.Hour
+		}
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid cache_ttl %q (want a positive duration like 30m, 6h, or 7d)", s)
+	}
+	return d, nil
+}
+
+// entry is one cached reply.
+type entry struct {
+	Agent     string    `json:"agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Reply     string    `json:"reply"`
+}
+
+// Stats counts a run's cache lookups.
+type Stats struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// Cache stores agent replies in a directory, one file per prompt. Entries
+// expire TTL after they are written. It is safe for concurrent use.
+type Cache struct {
+	dir    string
+	ttl    time.Duration
+	now    func() time.Time
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// New returns a cache in dir whose new entries live for ttl.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// Key hashes an agent and prompt into a cache key.
+func Key(agent, prompt string) string {
+	sum := sha256.Sum256([]byte(agent + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) path(agent, prompt string) string {
+	return filepath.Join(c.dir, Key(agent, prompt)+".json")
+}
+
+// Get returns the cached reply for a prompt sent to agent, if one has not
+// expired. Every call counts as a hit or a miss.
+func (c *Cache) Get(agent, prompt string) (string, bool) {
+	e, err := readEntry(c.path(agent, prompt))
+	if err != nil || !c.now().Before(e.ExpiresAt) {
+		c.misses.Add(1)
+		return "", false
+	}
+	c.hits.Add(1)
+	return e.Reply, true
+}
+
+// Put stores agent's reply to a prompt.
+func (c *Cache) Put(agent, prompt, reply string) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	now := c.now().UTC()
+	data, err := json.Marshal(entry{Agent: agent, CreatedAt: now, ExpiresAt: now.Add(c.ttl), Reply: reply})
+	if err != nil {
+		return err
+	}
+	return fsx.WriteFile(c.path(agent, prompt), data, 0644)
+}
+
+// Stats returns the hits and misses counted so far.
+func (c *Cache) Stats() Stats {
+	return Stats{Hits: int(c.hits.Load()), Misses: int(c.misses.Load())}
+}
+
+// Prune removes expired and unreadable entries from dir and returns how
+// many it removed.
+func Prune(dir string, now time.Time) int {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0
+	}
+	removed := 0
+	for _, path := range files {
+		if e, err := readEntry(path); err == nil && now.Before(e.ExpiresAt) {
+			continue
+		}
+		if os.Remove(path) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+func readEntry(path string) (*entry, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town's cache directory
+	if err != nil {
+		return nil, err
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
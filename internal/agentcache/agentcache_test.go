@@ -0,0 +1,73 @@
+package agentcache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCacheGetPut(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := New(dir, time.Hour)
+	c.now = func() time.Time { return now }
+
+	if _, ok := c.Get("claude", "review this"); ok {
+		t.Fatal("Get() hit on an empty cache")
+	}
+	if err := c.Put("claude", "review this", "LGTM"); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if reply, ok := c.Get("claude", "review this"); !ok || reply != "LGTM" {
+		t.Errorf("Get() = %q, %v; want LGTM", reply, ok)
+	}
+	if _, ok := c.Get("codex", "review this"); ok {
+		t.Error("Get() hit for a different agent")
+	}
+	if _, ok := c.Get("claude", "review that"); ok {
+		t.Error("Get() hit for a different prompt")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok := c.Get("claude", "review this"); ok {
+		t.Error("Get() hit after the entry expired")
+	}
+
+	if got := c.Stats(); got != (Stats{Hits: 1, Misses: 4}) {
+		t.Errorf("Stats() = %+v, want 1 hit, 4 misses", got)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	short := New(dir, time.Minute)
+	short.now = func() time.Time { return now }
+	long := New(dir, 24*time.Hour)
+	long.now = func() time.Time { return now }
+	_ = short.Put("claude", "old", "a")
+	_ = long.Put("claude", "fresh", "b")
+	if err := os.WriteFile(dir+"/junk.json", []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := Prune(dir, now.Add(time.Hour)); n != 2 {
+		t.Errorf("Prune() removed %d entries, want 2", n)
+	}
+	if _, ok := long.Get("claude", "fresh"); !ok {
+		t.Error("Prune() removed an unexpired entry")
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	for in, want := range map[string]time.Duration{"30m": 30 * time.Minute, "6h": 6 * time.Hour, "7d": 7 * 24 * time.Hour} {
+		if got, err := ParseTTL(in); err != nil || got != want {
+			t.Errorf("ParseTTL(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "soon", "0s", "-1h", "xd"} {
+		if _, err := ParseTTL(in); err == nil {
+			t.Errorf("ParseTTL(%q) succeeded, want error", in)
+		}
+	}
+}
@@ -0,0 +1,453 @@
+// Package backup archives and restores the durable state of a town:
+// settings, the rig registry, beads databases (including formula
+// overrides), and rig audit logs. Runtime state (.runtime/) and git clones
+// are never included.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// ManifestName is the manifest entry at the root of every backup archive.
+const ManifestName = "gastown-backup.json"
+
+// CurrentManifestVersion is the backup format version.
+const CurrentManifestVersion = 1
+
+// Manifest describes the contents of a backup archive.
+type Manifest struct {
+	Version   int         `json:"version"`
+	CreatedAt time.Time   `json:"created_at"`
+	TownRoot  string      `json:"town_root"`
+	Rigs      []string    `json:"rigs"`
+	Files     []FileEntry `json:"files"`
+}
+
+// FileEntry is one file in a backup.
+type FileEntry struct {
+	Path   string `json:"path"` // Slash-separated, relative to the town root
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	Mode   uint32 `json:"mode"`
+}
+
+// Sources returns the town-relative paths (files or directories) a backup
+// captures for a town with the given rigs.
+func Sources(rigs []string) []string {
+	sources := []string{
+		"settings",
+		filepath.Join(constants.DirMayor, constants.FileRigsJSON),
+		filepath.Join(constants.DirMayor, "town.json"),
+		filepath.Join(constants.DirMayor, "config.json"),
+		".beads",
+	}
+	for _, rig := range rigs {
+		sources = append(sources,
+			filepath.Join(rig, "config.json"),
+			filepath.Join(rig, "settings"),
+			filepath.Join(rig, ".beads"),
+			filepath.Join(rig, "witness", witness.AuditLogFile),
+		)
+	}
+	return sources
+}
+
+// beadsDaemonFiles are a bd daemon's runtime files in a .beads directory.
+var beadsDaemonFiles = map[string]bool{"daemon.lock": true, "daemon.log": true, "daemon.pid": true, "bd.sock": true}
+
+// excluded reports whether a town-relative path is left out of backups.
+func excluded(rel string) bool {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for _, part := range parts {
+		if part == constants.DirRuntime {
+			return true
+		}
+	}
+	n := len(parts)
+	return n >= 2 && parts[n-2] == ".beads" && beadsDaemonFiles[parts[n-1]]
+}
+
+// runningBeadsDaemon returns the PID of a live bd daemon serving beadsDir.
+func runningBeadsDaemon(beadsDir string) (int, bool) {
+	data, err := os.ReadFile(filepath.Join(beadsDir, "daemon.pid")) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil || process.Signal(syscall.Signal(0)) != nil {
+		return 0, false
+	}
+	return pid, true
+}
+
+// Create writes a gzipped tar of the town's durable state to w and returns
+// its manifest. Missing sources are skipped; symlinks, sockets, and other
+// non-regular files are not followed.
+//
+// Beads databases are copied file by file (SQLite database, WAL and all),
+// which is only consistent while nothing writes to them, so Create refuses
+// to run while a bd daemon serves any of them. Stop the town (gt down)
+// before backing it up.
+func Create(w io.Writer, townRoot string, rigs []string) (*Manifest, error) {
+	for _, src := range Sources(rigs) {
+		if filepath.Base(src) != ".beads" {
+			continue
+		}
+		if pid, ok := runningBeadsDaemon(filepath.Join(townRoot, src)); ok {
+			return nil, fmt.Errorf("bd daemon (PID %d) is running for %s; stop the town with 'gt down' first so the database isn't copied mid-write", pid, src)
+		}
+	}
+
+	manifest := &Manifest{
+		Version:   CurrentManifestVersion,
+		CreatedAt: time.Now().UTC(),
+		TownRoot:  townRoot,
+		Rigs:      append([]string(nil), rigs...),
+	}
+
+	var paths []string
+	for _, src := range Sources(rigs) {
+		root := filepath.Join(townRoot, src)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			rel, _ := filepath.Rel(townRoot, path)
+			if excluded(rel) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() {
+				paths = append(paths, rel)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", src, err)
+		}
+	}
+	sort.Strings(paths)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	contents := make(map[string][]byte, len(paths))
+	for _, rel := range paths {
+		path := filepath.Join(townRoot, rel)
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", rel, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		entry := FileEntry{
+			Path:   filepath.ToSlash(rel),
+			Size:   int64(len(data)),
+			SHA256: hex.EncodeToString(sum[:]),
+			Mode:   uint32(info.Mode().Perm()),
+		}
+		manifest.Files = append(manifest.Files, entry)
+		contents[entry.Path] = data
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tw, ManifestName, manifestData, 0644, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, f := range manifest.Files {
+		if err := writeTarFile(tw, f.Path, contents[f.Path], int64(f.Mode), manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, mode int64, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    mode,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// Archive is a backup read into memory for restore.
+type Archive struct {
+	Manifest *Manifest
+	files    map[string][]byte
+}
+
+// maxManifestSize bounds the manifest entry, which is read before the
+// sizes it records can bound anything else.
+const maxManifestSize = 64 << 20
+
+// Read loads and verifies a backup archive. The manifest must be the first
+// entry; every other entry must be listed in it, and is read no further
+// than its recorded size, so a crafted archive can't exhaust memory.
+func Read(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzip archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	m, err := readManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]FileEntry, len(m.Files))
+	for _, f := range m.Files {
+		if !safeRelPath(f.Path) {
+			return nil, fmt.Errorf("unsafe path in backup: %s", f.Path)
+		}
+		listed[f.Path] = f
+	}
+
+	a := &Archive{files: make(map[string][]byte)}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		f, ok := listed[hdr.Name]
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s in backup: not in its manifest", hdr.Name)
+		}
+		content, err := io.ReadAll(io.LimitReader(tr, f.Size+1))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+		if int64(len(content)) != f.Size {
+			return nil, fmt.Errorf("size mismatch for %s: backup is corrupt", f.Path)
+		}
+		a.files[hdr.Name] = content
+	}
+
+	for _, f := range m.Files {
+		content, ok := a.files[f.Path]
+		if !ok {
+			return nil, fmt.Errorf("backup is missing %s", f.Path)
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s: backup is corrupt", f.Path)
+		}
+	}
+	a.Manifest = m
+	return a, nil
+}
+
+// readManifest reads the manifest from the archive's first entry.
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	hdr, err := tr.Next()
+	if err == io.EOF || (err == nil && hdr.Name != ManifestName) {
+		return nil, fmt.Errorf("missing %s: not a gt backup", ManifestName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	if hdr.Size > maxManifestSize {
+		return nil, fmt.Errorf("%s is too large: not a gt backup", ManifestName)
+	}
+	data, err := io.ReadAll(io.LimitReader(tr, maxManifestSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ManifestName, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if m.Version > CurrentManifestVersion {
+		return nil, fmt.Errorf("backup format %d is newer than this gt supports (%d)", m.Version, CurrentManifestVersion)
+	}
+	return &m, nil
+}
+
+// safeRelPath rejects absolute paths and paths that escape the town.
+func safeRelPath(p string) bool {
+	if p == "" || strings.HasPrefix(p, "/") || filepath.IsAbs(p) {
+		return false
+	}
+	for _, part := range strings.Split(p, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// Restore actions for a planned file.
+const (
+	ActionCreate    = "create"
+	ActionUnchanged = "unchanged"
+	ActionConflict  = "conflict"
+	// ActionRemove is a SQLite -wal or -shm file in the target that the
+	// backup doesn't have. Left beside the restored database, SQLite would
+	// replay it on open, so restore deletes it.
+	ActionRemove = "remove"
+)
+
+// sqliteSidecars are the suffixes of the files SQLite keeps beside a
+// database in WAL mode.
+var sqliteSidecars = []string{"-wal", "-shm"}
+
+// PlannedFile is what restore would do with one file.
+type PlannedFile struct {
+	Path   string
+	Action string
+}
+
+// Plan compares the archive against the target town directory. A conflict
+// is an existing file whose content differs from the backup; a removal is
+// a stale SQLite sidecar beside one of the backup's beads files.
+func (a *Archive) Plan(townRoot string) []PlannedFile {
+	plan := make([]PlannedFile, 0, len(a.Manifest.Files))
+	for _, f := range a.Manifest.Files {
+		if path.Base(path.Dir(f.Path)) == ".beads" {
+			for _, suffix := range sqliteSidecars {
+				sidecar := f.Path + suffix
+				if _, archived := a.files[sidecar]; archived {
+					continue
+				}
+				if _, err := os.Lstat(filepath.Join(townRoot, filepath.FromSlash(sidecar))); err == nil {
+					plan = append(plan, PlannedFile{Path: sidecar, Action: ActionRemove})
+				}
+			}
+		}
+		p := PlannedFile{Path: f.Path, Action: ActionCreate}
+		existing, err := os.ReadFile(filepath.Join(townRoot, filepath.FromSlash(f.Path))) //nolint:gosec // G304: path validated by safeRelPath
+		if err == nil {
+			if bytes.Equal(existing, a.files[f.Path]) {
+				p.Action = ActionUnchanged
+			} else {
+				p.Action = ActionConflict
+			}
+		}
+		plan = append(plan, p)
+	}
+	return plan
+}
+
+// Restore writes the archive into townRoot. Conflicting files and stale
+// SQLite sidecars are only overwritten or removed with overwrite set;
+// otherwise Restore fails before writing anything. Like Create, it refuses
+// to run while a bd daemon serves any of the beads databases it would
+// replace. Returns the number of files written.
+func (a *Archive) Restore(townRoot string, overwrite bool) (int, error) {
+	for _, src := range Sources(a.Manifest.Rigs) {
+		if filepath.Base(src) != ".beads" {
+			continue
+		}
+		if pid, ok := runningBeadsDaemon(filepath.Join(townRoot, src)); ok {
+			return 0, fmt.Errorf("bd daemon (PID %d) is running for %s; stop the town with 'gt down' first so the database isn't replaced under it", pid, src)
+		}
+	}
+
+	plan := a.Plan(townRoot)
+	if !overwrite {
+		var conflicts []string
+		for _, p := range plan {
+			if p.Action == ActionConflict || p.Action == ActionRemove {
+				conflicts = append(conflicts, p.Path)
+			}
+		}
+		if len(conflicts) > 0 {
+			return 0, &ConflictError{Paths: conflicts}
+		}
+	}
+
+	modes := make(map[string]uint32, len(a.Manifest.Files))
+	for _, f := range a.Manifest.Files {
+		modes[f.Path] = f.Mode
+	}
+
+	written := 0
+	for _, p := range plan {
+		dest := filepath.Join(townRoot, filepath.FromSlash(p.Path))
+		switch p.Action {
+		case ActionUnchanged:
+			continue
+		case ActionRemove:
+			if err := os.Remove(dest); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return written, fmt.Errorf("removing %s: %w", p.Path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return written, fmt.Errorf("creating %s: %w", filepath.Dir(dest), err)
+		}
+		mode := fs.FileMode(modes[p.Path])
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := fsx.WriteFile(dest, a.files[p.Path], mode); err != nil {
+			return written, fmt.Errorf("writing %s: %w", p.Path, err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// ConflictError lists files that exist with different content.
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%d file(s) differ from the backup", len(e.Paths))
+}
@@ -0,0 +1,252 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTown(t *testing.T) string {
+	t.Helper()
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, "settings", "config.json"), `{"type":"town-settings"}`)
+	writeFile(t, filepath.Join(town, "mayor", "rigs.json"), `{"rigs":{"gastown":{}}}`)
+	writeFile(t, filepath.Join(town, ".beads", "issues.jsonl"), `{"id":"hq-1"}`)
+	writeFile(t, filepath.Join(town, ".beads", ".runtime", "daemon.pid"), "123")
+	writeFile(t, filepath.Join(town, "gastown", "config.json"), `{"name":"gastown"}`)
+	writeFile(t, filepath.Join(town, "gastown", ".beads", "issues.jsonl"), `{"id":"gt-1"}`)
+	writeFile(t, filepath.Join(town, "gastown", "witness", "audit.jsonl"), `{"action":"nudge"}`)
+	writeFile(t, filepath.Join(town, "gastown", ".runtime", "locks", "x"), "lock")
+	writeFile(t, filepath.Join(town, "gastown", "mayor", "rig", "main.go"), "package main")
+	return town
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	town := newTown(t)
+
+	var buf bytes.Buffer
+	manifest, err := Create(&buf, town, []string{"gastown"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var paths []string
+	for _, f := range manifest.Files {
+		paths = append(paths, f.Path)
+	}
+	want := []string{
+		".beads/issues.jsonl",
+		"gastown/.beads/issues.jsonl",
+		"gastown/config.json",
+		"gastown/witness/audit.jsonl",
+		"mayor/rigs.json",
+		"settings/config.json",
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", paths, want)
+	}
+
+	archive, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	dest := t.TempDir()
+	n, err := archive.Restore(dest, false)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if n != len(want) {
+		t.Errorf("wrote %d files, want %d", n, len(want))
+	}
+	got, err := os.ReadFile(filepath.Join(dest, "gastown", "witness", "audit.jsonl"))
+	if err != nil || string(got) != `{"action":"nudge"}` {
+		t.Errorf("restored audit log = %q, %v", got, err)
+	}
+
+	// Restoring again is a no-op.
+	n, err = archive.Restore(dest, false)
+	if err != nil || n != 0 {
+		t.Errorf("second Restore = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestRestoreConflicts(t *testing.T) {
+	town := newTown(t)
+	var buf bytes.Buffer
+	if _, err := Create(&buf, town, []string{"gastown"}); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	writeFile(t, filepath.Join(dest, "mayor", "rigs.json"), `{"rigs":{}}`)
+
+	_, err = archive.Restore(dest, false)
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Restore error = %v, want ConflictError", err)
+	}
+	if len(conflictErr.Paths) != 1 || conflictErr.Paths[0] != "mayor/rigs.json" {
+		t.Errorf("conflicts = %v", conflictErr.Paths)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "settings", "config.json")); !os.IsNotExist(err) {
+		t.Error("Restore wrote files despite conflicts")
+	}
+
+	if _, err := archive.Restore(dest, true); err != nil {
+		t.Fatalf("Restore with overwrite: %v", err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dest, "mayor", "rigs.json"))
+	if string(got) != `{"rigs":{"gastown":{}}}` {
+		t.Errorf("rigs.json = %q after overwrite", got)
+	}
+}
+
+// buildArchive writes a raw archive with the given manifest and entries.
+func buildArchive(t *testing.T, m Manifest, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	data, _ := json.Marshal(m)
+	if err := writeTarFile(tw, ManifestName, data, 0644, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := writeTarFile(tw, name, []byte(content), 0644, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+	return buf.Bytes()
+}
+
+func TestReadRejectsBadArchives(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []FileEntry
+		content map[string]string
+		wantErr string
+	}{
+		{
+			name:    "path traversal",
+			files:   []FileEntry{{Path: "../evil", SHA256: "x"}},
+			content: map[string]string{"../evil": "x"},
+			wantErr: "unsafe path",
+		},
+		{
+			name:    "checksum mismatch",
+			files:   []FileEntry{{Path: "settings/config.json", Size: 2, SHA256: "deadbeef"}},
+			content: map[string]string{"settings/config.json": "{}"},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "larger than recorded",
+			files:   []FileEntry{{Path: "settings/config.json", Size: 2, SHA256: "x"}},
+			content: map[string]string{"settings/config.json": strings.Repeat("x", 1<<20)},
+			wantErr: "size mismatch",
+		},
+		{
+			name:    "unlisted entry",
+			content: map[string]string{"settings/config.json": "{}"},
+			wantErr: "not in its manifest",
+		},
+		{
+			name:    "missing file",
+			files:   []FileEntry{{Path: "settings/config.json", SHA256: "x"}},
+			wantErr: "missing settings/config.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildArchive(t, Manifest{Version: CurrentManifestVersion, Files: tt.files}, tt.content)
+			_, err := Read(bytes.NewReader(data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateRefusesWhileBeadsDaemonRuns(t *testing.T) {
+	town := newTown(t)
+	writeFile(t, filepath.Join(town, "gastown", ".beads", "daemon.pid"), strconv.Itoa(os.Getpid()))
+
+	_, err := Create(&bytes.Buffer{}, town, []string{"gastown"})
+	if err == nil || !strings.Contains(err.Error(), "bd daemon") {
+		t.Fatalf("Create error = %v, want a running bd daemon refusal", err)
+	}
+}
+
+func TestRestoreStaleSQLiteSidecars(t *testing.T) {
+	town := newTown(t)
+	writeFile(t, filepath.Join(town, "gastown", ".beads", "beads.db"), "backup db")
+	var buf bytes.Buffer
+	if _, err := Create(&buf, town, []string{"gastown"}); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	wal := filepath.Join(dest, "gastown", ".beads", "beads.db-wal")
+	writeFile(t, wal, "newer pages")
+
+	_, err = archive.Restore(dest, false)
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) || strings.Join(conflictErr.Paths, ",") != "gastown/.beads/beads.db-wal" {
+		t.Fatalf("Restore error = %v, want the stale WAL as a conflict", err)
+	}
+	if _, err := archive.Restore(dest, true); err != nil {
+		t.Fatalf("Restore with overwrite: %v", err)
+	}
+	if _, err := os.Stat(wal); !os.IsNotExist(err) {
+		t.Errorf("stale WAL survived the restore: %v", err)
+	}
+}
+
+func TestRestoreRefusesWhileBeadsDaemonRuns(t *testing.T) {
+	town := newTown(t)
+	var buf bytes.Buffer
+	if _, err := Create(&buf, town, []string{"gastown"}); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	writeFile(t, filepath.Join(dest, ".beads", "daemon.pid"), strconv.Itoa(os.Getpid()))
+	if _, err := archive.Restore(dest, true); err == nil || !strings.Contains(err.Error(), "bd daemon") {
+		t.Fatalf("Restore error = %v, want a running bd daemon refusal", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "settings", "config.json")); !os.IsNotExist(err) {
+		t.Error("Restore wrote files while a bd daemon was running")
+	}
+}
@@ -0,0 +1,333 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// BatchIssue is an issue to create as part of a Batch. Batched issues
+// need explicit IDs so later operations in the batch can refer to them.
+type BatchIssue struct {
+	ID          string
+	Title       string
+	Description string
+	Type        string // Issue type ("task", "convoy", ...); defaults to "task"
+	Priority    int    // 0-4; negative uses bd's default (2)
+	Labels      []string
+}
+
+// Batch collects issue creations and dependency edges and applies them with
+// a single 'bd import' of the same JSONL format bd exports, instead of one
+// bd process per operation. Each operation carries a key (typically a
+// convoy leg ID) so failures can be mapped back to what asked for them.
+//
+// Edges whose dependent issue is created in the batch are written into that
+// issue's record; edges from existing issues go through 'bd dep add'.
+type Batch struct {
+	b       *Beads
+	creates []batchCreate
+	deps    []batchDep
+}
+
+type batchCreate struct {
+	key   string
+	issue BatchIssue
+}
+
+type batchDep struct {
+	key       string
+	issue     string
+	dependsOn string
+	depType   string
+}
+
+// NewBatch starts an empty batch against this beads directory.
+func (b *Beads) NewBatch() *Batch {
+	return &Batch{b: b}
+}
+
+// Create queues creation of an issue on behalf of key.
+func (bt *Batch) Create(key string, issue BatchIssue) *Batch {
+	bt.creates = append(bt.creates, batchCreate{key: key, issue: issue})
+	return bt
+}
+
+// AddDependency queues an edge on behalf of key: issue depends on dependsOn
+// with the given type ("blocks" if empty, "tracks", "parent-child", ...).
+func (bt *Batch) AddDependency(key, issue, dependsOn, depType string) *Batch {
+	if depType == "" {
+		depType = "blocks"
+	}
+	bt.deps = append(bt.deps, batchDep{key: key, issue: issue, dependsOn: dependsOn, depType: depType})
+	return bt
+}
+
+// Len returns the number of queued operations.
+func (bt *Batch) Len() int {
+	return len(bt.creates) + len(bt.deps)
+}
+
+// BatchFailure is one operation of a Batch that did not apply.
+type BatchFailure struct {
+	Key string // Key the operation was queued with
+	Op  string // e.g. "create hq-leg-abc" or "dep add hq-cv-abc gt-123"
+	Err error
+}
+
+// BatchError reports every failed operation of a Batch. Operations not
+// listed succeeded.
+type BatchError struct {
+	Total    int
+	Failures []BatchFailure
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		msgs = append(msgs, fmt.Sprintf("%s: %s: %v", f.Key, f.Op, f.Err))
+	}
+	return fmt.Sprintf("%d of %d batch operations failed: %s", len(e.Failures), e.Total, strings.Join(msgs, "; "))
+}
+
+// Failed returns the first failure queued under key, or nil. It is safe to
+// call on a nil *BatchError.
+func (e *BatchError) Failed(key string) error {
+	if e == nil {
+		return nil
+	}
+	for _, f := range e.Failures {
+		if f.Key == key {
+			return fmt.Errorf("%s: %w", f.Op, f.Err)
+		}
+	}
+	return nil
+}
+
+// CreateFailed returns why the issue with the given ID was not created, or
+// nil if it was. It is safe to call on a nil *BatchError.
+func (e *BatchError) CreateFailed(id string) error {
+	if e == nil {
+		return nil
+	}
+	for _, f := range e.Failures {
+		if f.Op == "create "+id {
+			return f.Err
+		}
+	}
+	return nil
+}
+
+// Apply runs the batch. If the bulk import is rejected, Apply falls back to
+// applying each operation on its own, skipping issues the import did create,
+// so one bad operation does not sink the rest. The returned error, if any,
+// is a *BatchError.
+func (bt *Batch) Apply() error {
+	if bt.Len() == 0 {
+		return nil
+	}
+
+	created := make(map[string]bool, len(bt.creates))
+	for _, c := range bt.creates {
+		created[c.issue.ID] = true
+	}
+	var embedded, separate []batchDep
+	for _, d := range bt.deps {
+		if created[d.issue] {
+			embedded = append(embedded, d)
+		} else {
+			separate = append(separate, d)
+		}
+	}
+
+	batchErr := &BatchError{Total: bt.Len()}
+	if len(bt.creates) > 0 {
+		records := bt.records(embedded, bt.b.getActor(), time.Now().UTC())
+		if err := bt.importRecords(records); err != nil {
+			bt.applyEach(embedded, batchErr)
+		}
+	}
+	for _, d := range separate {
+		bt.addDep(d, batchErr)
+	}
+
+	if len(batchErr.Failures) > 0 {
+		return batchErr
+	}
+	return nil
+}
+
+// jsonlIssue is one line of the JSONL format bd exports, written for bd
+// import by Batch.
+type jsonlIssue struct {
+	ID           string            `json:"id"`
+	Title        string            `json:"title"`
+	Description  string            `json:"description,omitempty"`
+	Status       string            `json:"status"`
+	Priority     int               `json:"priority"`
+	Type         string            `json:"issue_type"`
+	Assignee     string            `json:"assignee,omitempty"`
+	CreatedAt    string            `json:"created_at"`
+	CreatedBy    string            `json:"created_by,omitempty"`
+	UpdatedAt    string            `json:"updated_at"`
+	ClosedAt     string            `json:"closed_at,omitempty"`
+	Labels       []string          `json:"labels,omitempty"`
+	Dependencies []jsonlDependency `json:"dependencies,omitempty"`
+}
+
+type jsonlDependency struct {
+	IssueID     string `json:"issue_id"`
+	DependsOnID string `json:"depends_on_id"`
+	Type        string `json:"type"`
+}
+
+// records builds the import lines for the batch's creates, with their
+// embedded dependency edges.
+func (bt *Batch) records(embedded []batchDep, actor string, now time.Time) []*jsonlIssue {
+	stamp := now.Format(time.RFC3339)
+	byID := make(map[string]*jsonlIssue, len(bt.creates))
+	records := make([]*jsonlIssue, 0, len(bt.creates))
+	for _, c := range bt.creates {
+		priority := c.issue.Priority
+		if priority < 0 {
+			priority = 2
+		}
+		issueType := c.issue.Type
+		if issueType == "" {
+			issueType = "task"
+		}
+		rec := &jsonlIssue{
+			ID:          c.issue.ID,
+			Title:       c.issue.Title,
+			Description: c.issue.Description,
+			Status:      "open",
+			Priority:    priority,
+			Type:        issueType,
+			CreatedAt:   stamp,
+			CreatedBy:   actor,
+			UpdatedAt:   stamp,
+			Labels:      c.issue.Labels,
+		}
+		byID[rec.ID] = rec
+		records = append(records, rec)
+	}
+	for _, d := range embedded {
+		rec := byID[d.issue]
+		rec.Dependencies = append(rec.Dependencies, jsonlDependency{
+			IssueID:     d.issue,
+			DependsOnID: d.dependsOn,
+			Type:        d.depType,
+		})
+	}
+	return records
+}
+
+// importRecords writes records to a temporary JSONL file and runs
+// 'bd import' on it.
+func (bt *Batch) importRecords(records []*jsonlIssue) error {
+	f, err := os.CreateTemp("", "gt-batch-*.jsonl")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	enc := json.NewEncoder(f)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	_, err = bt.b.run("import", "-i", f.Name())
+	return err
+}
+
+// applyEach creates the batch's issues and embedded edges one at a time,
+// skipping issues that already exist (a partially applied import).
+func (bt *Batch) applyEach(embedded []batchDep, batchErr *BatchError) {
+	ids := make([]string, 0, len(bt.creates))
+	for _, c := range bt.creates {
+		ids = append(ids, c.issue.ID)
+	}
+	existing, _ := bt.b.ShowMultiple(ids)
+
+	failed := make(map[string]error)
+	for _, c := range bt.creates {
+		if existing[c.issue.ID] != nil {
+			continue
+		}
+		if err := bt.createOne(c.issue); err != nil {
+			failed[c.issue.ID] = err
+			batchErr.Failures = append(batchErr.Failures, BatchFailure{Key: c.key, Op: "create " + c.issue.ID, Err: err})
+		}
+	}
+	for _, d := range embedded {
+		if err, ok := failed[d.issue]; ok {
+			batchErr.Failures = append(batchErr.Failures, BatchFailure{Key: d.key, Op: depOp(d), Err: err})
+			continue
+		}
+		if existing[d.issue] != nil && hasDep(existing[d.issue], d.dependsOn) {
+			continue
+		}
+		bt.addDep(d, batchErr)
+	}
+}
+
+func (bt *Batch) createOne(issue BatchIssue) error {
+	issueType := issue.Type
+	if issueType == "" {
+		issueType = "task"
+	}
+	args := []string{"create", "--json", "--id=" + issue.ID, "--type=" + issueType, "--title=" + issue.Title}
+	if NeedsForceForID(issue.ID) {
+		args = append(args, "--force")
+	}
+	if issue.Description != "" {
+		args = append(args, "--description="+issue.Description)
+	}
+	if issue.Priority >= 0 {
+		args = append(args, fmt.Sprintf("--priority=%d", issue.Priority))
+	}
+	if len(issue.Labels) > 0 {
+		args = append(args, "--labels="+strings.Join(issue.Labels, ","))
+	}
+	if actor := bt.b.getActor(); actor != "" {
+		args = append(args, "--actor="+actor)
+	}
+	_, err := bt.b.run(args...)
+	return err
+}
+
+func (bt *Batch) addDep(d batchDep, batchErr *BatchError) {
+	if _, err := bt.b.run("dep", "add", d.issue, d.dependsOn, "--type="+d.depType); err != nil {
+		batchErr.Failures = append(batchErr.Failures, BatchFailure{Key: d.key, Op: depOp(d), Err: err})
+	}
+}
+
+func depOp(d batchDep) string {
+	return fmt.Sprintf("dep add %s %s", d.issue, d.dependsOn)
+}
+
+func hasDep(issue *Issue, dependsOn string) bool {
+	for _, dep := range issue.Dependencies {
+		if dep.ID == dependsOn {
+			return true
+		}
+	}
+	return containsString(issue.DependsOn, dependsOn)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package beads
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// installStubBd puts a bd on PATH that logs its arguments to bd.log and
+// dispatches on the subcommand with the given case clauses.
+func installStubBd(t *testing.T, cases string) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := `#!/bin/sh
+echo "$*" >> "` + filepath.Join(dir, "bd.log") + `"
+while [ "${1#--}" != "$1" ]; do
+  if [ "$1" = "--db" ]; then shift; fi
+  shift
+done
+cmd="$1"; shift
+case "$cmd" in
+` + cases + `
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func bdLog(t *testing.T, dir string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "bd.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestBatchRecords(t *testing.T) {
+	bt := (&Beads{}).NewBatch()
+	bt.Create("cv", BatchIssue{ID: "hq-cv-1", Type: "convoy", Title: "Review", Priority: -1})
+	bt.Create("a", BatchIssue{ID: "hq-leg-a", Title: "Leg A", Priority: 1, Labels: []string{"gt:task"}})
+	embedded := []batchDep{
+		{key: "a", issue: "hq-cv-1", dependsOn: "hq-leg-a", depType: "tracks"},
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	records := bt.records(embedded, "mayor", now)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	cv := records[0]
+	if cv.Type != "convoy" || cv.Priority != 2 || cv.Status != "open" {
+		t.Errorf("convoy record = %+v", cv)
+	}
+	if cv.CreatedAt != "2026-01-02T03:04:05Z" || cv.CreatedBy != "mayor" {
+		t.Errorf("convoy created = %q by %q", cv.CreatedAt, cv.CreatedBy)
+	}
+	if len(cv.Dependencies) != 1 || cv.Dependencies[0].DependsOnID != "hq-leg-a" || cv.Dependencies[0].Type != "tracks" {
+		t.Errorf("convoy dependencies = %+v", cv.Dependencies)
+	}
+
+	leg := records[1]
+	if leg.Type != "task" || leg.Priority != 1 {
+		t.Errorf("leg record = %+v", leg)
+	}
+
+	line, err := json.Marshal(leg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(line), "closed_at") {
+		t.Errorf("import line should omit empty closed_at: %s", line)
+	}
+}
+
+func TestBatchApplyImportsOnce(t *testing.T) {
+	dir := installStubBd(t, `  import) ;;`)
+
+	b := NewIsolated(t.TempDir())
+	bt := b.NewBatch()
+	bt.Create("cv", BatchIssue{ID: "hq-cv-1", Type: "convoy", Title: "Review", Priority: -1})
+	bt.Create("a", BatchIssue{ID: "hq-leg-a", Title: "Leg A", Priority: -1})
+	bt.Create("b", BatchIssue{ID: "hq-leg-b", Title: "Leg B", Priority: -1})
+	bt.AddDependency("a", "hq-cv-1", "hq-leg-a", "tracks")
+	bt.AddDependency("b", "hq-cv-1", "hq-leg-b", "tracks")
+	bt.AddDependency("ext", "gt-existing", "hq-leg-a", "")
+
+	if err := bt.Apply(); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+
+	log := bdLog(t, dir)
+	if len(log) != 2 {
+		t.Fatalf("bd ran %d times, want import + one dep add:\n%s", len(log), strings.Join(log, "\n"))
+	}
+	if !strings.Contains(log[0], "import -i") {
+		t.Errorf("first call = %q, want import", log[0])
+	}
+	if !strings.Contains(log[1], "dep add gt-existing hq-leg-a --type=blocks") {
+		t.Errorf("second call = %q, want dep add for existing issue", log[1])
+	}
+}
+
+func TestBatchApplyFallbackReportsKeys(t *testing.T) {
+	dir := installStubBd(t, `  import) echo "import rejected" >&2; exit 1 ;;
+  show) echo '[]' ;;
+  create)
+    case "$*" in
+      *--id=hq-leg-bad*) echo "bad title" >&2; exit 1 ;;
+    esac
+    echo '{"id":"x"}' ;;
+  dep) ;;`)
+
+	b := NewIsolated(t.TempDir())
+	bt := b.NewBatch()
+	bt.Create("cv", BatchIssue{ID: "hq-cv-1", Type: "convoy", Title: "Review", Priority: -1})
+	bt.Create("good", BatchIssue{ID: "hq-leg-good", Title: "Good", Priority: -1})
+	bt.Create("bad", BatchIssue{ID: "hq-leg-bad", Title: "Bad", Priority: -1})
+	bt.AddDependency("good", "hq-cv-1", "hq-leg-good", "tracks")
+	bt.AddDependency("bad", "hq-leg-bad", "hq-leg-good", "")
+
+	err := bt.Apply()
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("Apply() = %v, want *BatchError", err)
+	}
+	if batchErr.Total != 5 || len(batchErr.Failures) != 2 {
+		t.Errorf("BatchError = %v", batchErr)
+	}
+	if batchErr.CreateFailed("hq-leg-bad") == nil {
+		t.Error("CreateFailed(hq-leg-bad) = nil")
+	}
+	if batchErr.CreateFailed("hq-leg-good") != nil || batchErr.Failed("good") != nil || batchErr.Failed("cv") != nil {
+		t.Errorf("good operations reported as failed: %v", batchErr)
+	}
+	if batchErr.Failed("bad") == nil {
+		t.Error("Failed(bad) = nil")
+	}
+
+	var creates int
+	for _, line := range bdLog(t, dir) {
+		if strings.Contains(line, "create") {
+			creates++
+		}
+	}
+	if creates != 3 {
+		t.Errorf("ran %d creates after fallback, want 3", creates)
+	}
+}
+
+func TestBatchErrorNil(t *testing.T) {
+	var e *BatchError
+	if e.Failed("x") != nil || e.CreateFailed("x") != nil {
+		t.Error("nil *BatchError should report no failures")
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/readonly"
 	"github.com/steveyegge/gastown/internal/runtime"
 )
 
@@ -115,6 +117,7 @@ type Beads struct {
 	workDir  string
 	beadsDir string // Optional BEADS_DIR override for cross-database access
 	isolated bool   // If true, suppress inherited beads env vars (for test isolation)
+	daemon   bool   // If true, let bd route operations through its daemon
 
 	// Lazy-cached town root for routing resolution.
 	// Populated on first call to getTownRoot() to avoid filesystem walk on every operation.
@@ -134,6 +137,13 @@ func NewIsolated(workDir string) *Beads {
 	return &Beads{workDir: workDir, isolated: true}
 }
 
+// NewDaemon creates a Beads wrapper whose bd operations go through the
+// bd daemon when one is running, instead of opening the database in every
+// bd process.
+func NewDaemon(workDir string) *Beads {
+	return &Beads{workDir: workDir, daemon: true}
+}
+
 // NewWithBeadsDir creates a Beads wrapper with an explicit BEADS_DIR.
 // This is needed when running from a polecat worktree but accessing town-level beads.
 func NewWithBeadsDir(workDir, beadsDir string) *Beads {
@@ -179,11 +189,20 @@ func (b *Beads) Init(prefix string) error {
 
 // run executes a bd command and returns stdout.
 func (b *Beads) run(args ...string) ([]byte, error) {
+	if !isReadCommand(args) {
+		if err := readonly.Check("run bd " + strings.Join(args, " ")); err != nil {
+			return nil, err
+		}
+	}
+
 	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
 	// The daemon is primarily useful for write coalescing, not reads.
 	// Use --allow-stale to prevent failures when db is out of sync with JSONL
 	// (e.g., after daemon is killed during shutdown before syncing).
 	fullArgs := append([]string{"--no-daemon", "--allow-stale"}, args...)
+	if b.daemon {
+		fullArgs = fullArgs[1:]
+	}
 
 	// Always explicitly set BEADS_DIR to prevent inherited env vars from
 	// causing prefix mismatches. Use explicit beadsDir if set, otherwise
@@ -221,7 +240,7 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 
 	// Limit concurrent bd processes to prevent dolt embedded lock contention.
 	AcquireBd()
-	err := cmd.Run()
+	err := cmdtrace.Run(cmd)
 	ReleaseBd()
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
@@ -630,6 +649,15 @@ func (b *Beads) Close(ids ...string) error {
 	return err
 }
 
+// Delete permanently deletes one or more issues (no tombstones).
+func (b *Beads) Delete(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := b.run(append(append([]string{"delete"}, ids...), "--hard", "--force")...)
+	return err
+}
+
 // CloseWithReason closes one or more issues with a reason.
 // If a runtime session ID is set in the environment, it is passed to bd close
 // for work attribution tracking (see decision 009-session-events-architecture.md).
@@ -832,3 +860,44 @@ func ProvisionPrimeMDForWorktree(worktreePath string) error {
 	// Provision PRIME.md in the target directory
 	return ProvisionPrimeMD(beadsDir)
 }
+
+// readCommands are the bd commands that only read, by subcommand; nil
+// allows every form of the command. Everything else is refused in
+// read-only mode.
+var readCommands = map[string][]string{
+	"list":       nil,
+	"show":       nil,
+	"ready":      nil,
+	"blocked":    nil,
+	"stats":      nil,
+	"search":     nil,
+	"count":      nil,
+	"version":    nil,
+	"info":       nil,
+	"where":      nil,
+	"dep":        {"list", "tree"},
+	"slot":       {"get"},
+	"merge-slot": {"check"},
+	"sync":       {"--status"},
+	"config":     {"get", "list"},
+}
+
+// isReadCommand reports whether a bd invocation only reads.
+func isReadCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	subs, ok := readCommands[args[0]]
+	if !ok {
+		return false
+	}
+	if subs == nil {
+		return true
+	}
+	for _, sub := range subs {
+		if len(args) > 1 && args[1] == sub {
+			return true
+		}
+	}
+	return false
+}
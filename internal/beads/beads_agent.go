@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // runSlotSet runs `bd slot set` from a specific directory.
@@ -15,7 +17,7 @@ import (
 func runSlotSet(workDir, beadID, slotName, slotValue string) error {
 	cmd := exec.Command("bd", "slot", "set", beadID, slotName, slotValue)
 	cmd.Dir = workDir
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("%s: %w", strings.TrimSpace(string(output)), err)
 	}
 	return nil
@@ -25,7 +27,7 @@ func runSlotSet(workDir, beadID, slotName, slotValue string) error {
 func runSlotClear(workDir, beadID, slotName string) error {
 	cmd := exec.Command("bd", "slot", "clear", beadID, slotName)
 	cmd.Dir = workDir
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("%s: %w", strings.TrimSpace(string(output)), err)
 	}
 	return nil
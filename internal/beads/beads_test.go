@@ -2,12 +2,15 @@ package beads
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/readonly"
 )
 
 // TestNew verifies the constructor.
@@ -787,11 +790,25 @@ attached_at: 2025-12-21T15:30:00Z`,
 			},
 			want: "attached_molecule: mol-abc",
 		},
+		{
+			name: "args and context",
+			fields: &AttachmentFields{
+				AttachedArgs:    "focus on security",
+				AttachedContext: "/town/.runtime/sling-context/hq-leg-abc.json",
+			},
+			want: `attached_args: focus on security
+attached_context: /town/.runtime/sling-context/hq-leg-abc.json`,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := FormatAttachmentFields(tt.fields)
+			if tt.fields != nil {
+				if parsed := ParseAttachmentFields(&Issue{Description: got}); parsed != nil && *parsed != *tt.fields {
+					t.Errorf("round trip = %+v, want %+v", parsed, tt.fields)
+				}
+			}
 			if got != tt.want {
 				t.Errorf("FormatAttachmentFields() =\n%q\nwant\n%q", got, tt.want)
 			}
@@ -2549,3 +2566,21 @@ func TestCloseAndClearAgentBead_ReasonVariations(t *testing.T) {
 		})
 	}
 }
+
+func TestRunReadOnly(t *testing.T) {
+	for _, args := range [][]string{{"list", "--json"}, {"show", "gt-1"}, {"dep", "list", "gt-1"}, {"sync", "--status"}} {
+		if !isReadCommand(args) {
+			t.Errorf("isReadCommand(%q) = false, want true", args)
+		}
+	}
+	for _, args := range [][]string{{"create", "--title=x"}, {"dep", "add", "a", "b"}, {"sync"}, {"slot", "set", "a", "b", "c"}, {}} {
+		if isReadCommand(args) {
+			t.Errorf("isReadCommand(%q) = true, want false", args)
+		}
+	}
+
+	t.Setenv(readonly.EnvVar, "1")
+	if _, err := New(t.TempDir()).run("close", "gt-1"); !errors.Is(err, readonly.ErrReadOnly) {
+		t.Errorf("run(close) in read-only mode = %v, want ErrReadOnly", err)
+	}
+}
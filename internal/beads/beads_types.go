@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/constants"
 )
 
@@ -109,7 +110,7 @@ func EnsureCustomTypes(beadsDir string) error {
 	cmd.Dir = beadsDir
 	// Set BEADS_DIR explicitly to ensure bd operates on the correct database
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("configure custom types in %s: %s: %w",
 			beadsDir, strings.TrimSpace(string(output)), err)
 	}
@@ -0,0 +1,78 @@
+package beads
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Store is the set of issue operations gt needs from a beads database.
+// *Beads implements it by running the bd CLI.
+type Store interface {
+	List(opts ListOptions) ([]*Issue, error)
+	Show(id string) (*Issue, error)
+	ShowMultiple(ids []string) (map[string]*Issue, error)
+	Ready() ([]*Issue, error)
+	Create(opts CreateOptions) (*Issue, error)
+	Update(id string, opts UpdateOptions) error
+	CloseWithReason(reason string, ids ...string) error
+	AddDependency(issue, dependsOn string) error
+	NewBatch() *Batch
+}
+
+var _ Store = (*Beads)(nil)
+
+// Backend selects how gt talks to beads.
+type Backend string
+
+const (
+	// BackendCLI runs a standalone bd process, with --no-daemon, for every
+	// operation (the default).
+	BackendCLI Backend = "cli"
+	// BackendDaemon runs bd without --no-daemon, so operations go through
+	// the bd daemon when one is running and bd doesn't open the database
+	// in every process.
+	BackendDaemon Backend = "daemon"
+)
+
+// ParseBackend validates a backend name. Empty means BackendCLI.
+func ParseBackend(s string) (Backend, error) {
+	switch Backend(s) {
+	case "", BackendCLI:
+		return BackendCLI, nil
+	case BackendDaemon:
+		return BackendDaemon, nil
+	case "direct":
+		return "", fmt.Errorf("beads backend %q is not supported: gt only reads and writes beads through bd (want cli or daemon)", s)
+	}
+	return "", fmt.Errorf("unknown beads backend %q (want cli or daemon)", s)
+}
+
+// BackendFor returns the beads backend configured for the town containing
+// workDir: GT_BEADS_BACKEND if set, else the town's beads_backend setting.
+// Unknown values fall back to BackendCLI.
+func BackendFor(workDir string) Backend {
+	name := os.Getenv("GT_BEADS_BACKEND")
+	if name == "" {
+		if townRoot := FindTownRoot(workDir); townRoot != "" {
+			if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+				name = settings.BeadsBackend
+			}
+		}
+	}
+	backend, err := ParseBackend(name)
+	if err != nil {
+		return BackendCLI
+	}
+	return backend
+}
+
+// OpenStore returns the beads store for workDir using the configured
+// backend.
+func OpenStore(workDir string) Store {
+	if BackendFor(workDir) == BackendDaemon {
+		return NewDaemon(workDir)
+	}
+	return New(workDir)
+}
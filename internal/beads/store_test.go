@@ -0,0 +1,40 @@
+package beads
+
+import (
+	"testing"
+)
+
+func TestParseBackend(t *testing.T) {
+	for in, want := range map[string]Backend{"": BackendCLI, "cli": BackendCLI, "daemon": BackendDaemon} {
+		if got, err := ParseBackend(in); err != nil || got != want {
+			t.Errorf("ParseBackend(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"direct", "sqlite"} {
+		if _, err := ParseBackend(in); err == nil {
+			t.Errorf("ParseBackend(%q) = nil error", in)
+		}
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	dir := installStubBd(t, `show) echo '[{"id":"gt-a","title":"A"}]' ;;`)
+
+	for _, tt := range []struct {
+		backend string
+		want    string
+	}{
+		{"cli", "--no-daemon --allow-stale show gt-a --json"},
+		{"daemon", "--allow-stale show gt-a --json"},
+	} {
+		t.Setenv("GT_BEADS_BACKEND", tt.backend)
+		store := OpenStore(t.TempDir())
+		if issue, err := store.Show("gt-a"); err != nil || issue.Title != "A" {
+			t.Fatalf("%s: Show() = %+v, %v", tt.backend, issue, err)
+		}
+		log := bdLog(t, dir)
+		if got := log[len(log)-1]; got != tt.want {
+			t.Errorf("%s: bd %s, want bd %s", tt.backend, got, tt.want)
+		}
+	}
+}
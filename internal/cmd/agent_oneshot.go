@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/readonly"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// agentOneShotTimeout bounds a single non-interactive agent call.
+const agentOneShotTimeout = 10 * time.Minute
+
+// simulateAgent is a built-in one-shot agent that answers every prompt with
+// canned findings instead of calling a model, so a convoy can be run
+// offline, instantly, and for free (gt formula run --local-agent --agent
+// simulate). gt tour uses it for its demo convoy.
+const simulateAgent = "simulate"
+
+// simulatedFindings are the findings the simulate agent picks from.
+var simulatedFindings = []string{
+	"[high] Error returned by Divide is ignored by its caller",
+	"[medium] Loop re-reads the config file on every iteration",
+	"[medium] Public function has no doc comment",
+	"[low] Variable name `tmp2` doesn't say what it holds",
+	"[low] Test covers the happy path only",
+	"[info] Consider table-driven tests for the parser",
+}
+
+// agentOneShotArgs builds the argv for running an agent non-interactively
+// with a single prompt. Claude is natively non-interactive via --print;
+// other presets use their configured subcommand and prompt flag; unknown
+// agents receive the prompt as the final argument.
+func agentOneShotArgs(agentName string, rc *config.RuntimeConfig, prompt string) []string {
+	if agentName == "" {
+		agentName = filepath.Base(rc.Command)
+	}
+
+	var ni *config.NonInteractiveConfig
+	if preset := config.GetAgentPresetByName(agentName); preset != nil {
+		ni = preset.NonInteractive
+	}
+
+	args := []string{rc.Command}
+	if ni != nil && ni.Subcommand != "" {
+		args = append(args, ni.Subcommand)
+	}
+	args = append(args, rc.Args...)
+
+	switch {
+	case ni == nil && agentName == string(config.AgentClaude):
+		args = append(args, "--print")
+	case ni != nil && ni.PromptFlag != "":
+		args = append(args, ni.PromptFlag)
+	}
+	return append(args, prompt)
+}
+
+// runAgentOneShot sends a prompt to the configured agent (or agentOverride)
+// non-interactively and returns its stdout.
+func runAgentOneShot(townRoot, rigPath, agentOverride, prompt string) (string, error) {
+	if agentOverride == simulateAgent {
+		return simulatedReply(prompt), nil
+	}
+	if rigPath == "" {
+		rigPath = townRoot
+	}
+	rc, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
+	if err != nil {
+		return "", fmt.Errorf("resolving agent: %w", err)
+	}
+	if err := readonly.Check("call agent " + agentName); err != nil {
+		return "", err
+	}
+
+	argv := agentOneShotArgs(agentName, rc, prompt)
+
+	ctx, cancel := context.WithTimeout(context.Background(), agentOneShotTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec // G204: agent command comes from town/rig config
+	cmd.Dir = townRoot
+	cmd.Env = os.Environ()
+	for k, v := range rc.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", &agentTimeoutError{fmt.Sprintf("agent %s timed out after %s", argv[0], agentOneShotTimeout)}
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("running agent %s: %w", argv[0], err)
+		}
+		return "", fmt.Errorf("running agent %s: %w\n%s", argv[0], err, msg)
+	}
+	return stdout.String(), nil
+}
+
+// simulatedReply is the simulate agent's reply to prompt: two findings
+// chosen by a hash of the prompt, so the same prompt gets the same reply.
+func simulatedReply(prompt string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(prompt))
+	i := int(h.Sum32() % uint32(len(simulatedFindings)))
+	j := (i + 1 + int(h.Sum32()>>16)%(len(simulatedFindings)-1)) % len(simulatedFindings)
+
+	title, _, _ := strings.Cut(strings.TrimSpace(prompt), "\n")
+	title = strings.TrimLeft(title, "# ")
+	return fmt.Sprintf("# %s\n\n_Simulated reply: no agent was called._\n\n## Findings\n\n- %s\n- %s\n",
+		truncateStr(title, 80), simulatedFindings[i], simulatedFindings[j])
+}
+
+// agentChain returns the agents a one-shot call tries in turn: agent, or
+// the rig's default agent if empty, followed by the agent registry's
+// fallback order.
+func agentChain(townRoot, rigPath, agent string) []string {
+	if rigPath == "" {
+		rigPath = townRoot
+	}
+	primary := agent
+	if primary == "" {
+		if _, name, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, ""); err == nil {
+			primary = name
+		}
+	}
+	return config.AgentFallbackChain(primary, config.LoadAgentOrder(townRoot, rigPath))
+}
+
+// runAgentChain calls try with each agent of chain until one replies,
+// noting each fallback on log. It returns the reply and the agent that gave
+// it. Read-only refusals are not retried.
+func runAgentChain(chain []string, log io.Writer, try func(agent string) (string, error)) (string, string, error) {
+	var failures []string
+	timedOut := true
+	for i, agent := range chain {
+		reply, err := try(agent)
+		if err == nil {
+			return reply, agent, nil
+		}
+		if len(chain) == 1 || errors.Is(err, readonly.ErrReadOnly) {
+			return "", "", err
+		}
+		var te *agentTimeoutError
+		timedOut = timedOut && errors.As(err, &te)
+		reason := truncateStr(strings.Join(strings.Fields(err.Error()), " "), 160)
+		failures = append(failures, agentLabel(agent)+": "+reason)
+		if i+1 < len(chain) {
+			fmt.Fprintf(log, "  %s Agent %s failed (%s); falling back to %s\n",
+				style.Warning.Render("⚠"), agentLabel(agent), reason, agentLabel(chain[i+1]))
+		}
+	}
+	if timedOut {
+		return "", "", &agentTimeoutError{"all agents timed out: " + strings.Join(failures, "; ")}
+	}
+	return "", "", fmt.Errorf("all agents failed: %s", strings.Join(failures, "; "))
+}
+
+// agentTimeoutError is a one-shot agent call that ran past
+// agentOneShotTimeout, told apart from other failures for leg retries.
+type agentTimeoutError struct {
+	msg string
+}
+
+func (e *agentTimeoutError) Error() string {
+	return e.msg
+}
+
+// runAgentWithFallback is runAgentOneShot through the agent's fallback
+// chain. It returns the reply and the agent that gave it.
+func runAgentWithFallback(townRoot, rigPath, agent, prompt string, log io.Writer) (string, string, error) {
+	return runAgentChain(agentChain(townRoot, rigPath, agent), log, func(a string) (string, error) {
+		return runAgentOneShot(townRoot, rigPath, a, prompt)
+	})
+}
+
+// agentLabel names an agent for messages; the empty name is the rig's
+// configured runtime.
+func agentLabel(agent string) string {
+	if agent == "" {
+		return "(rig runtime)"
+	}
+	return agent
+}
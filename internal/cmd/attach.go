@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var attachCmd = &cobra.Command{
+	Use:     "attach <leg-bead-id|convoy-id>",
+	GroupID: GroupAgents,
+	Short:   "Attach to the session working on a bead or convoy",
+	Long: `Attach to the polecat session working on a leg bead, or on any leg of
+a convoy.
+
+The bead's assignee (or the agent hooked to it) names the session. For a
+convoy, every open tracked bead with a running session is a candidate;
+when more than one matches, pick one from the list. Inside tmux the
+current client switches to the session, otherwise the terminal attaches.
+Detach with Ctrl-B D.
+
+Examples:
+  gt attach gt-leg-abc12     # The polecat working on this leg
+  gt attach hq-cv-xyz        # Choose among the convoy's running legs`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAttach,
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+}
+
+// attachTarget is a running session working on a bead.
+type attachTarget struct {
+	BeadID  string
+	Title   string
+	Agent   string // Agent address, e.g. gastown/polecats/Toast
+	Session string
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	issue, err := beads.New(resolveBeadDir(id)).Show(id)
+	if err != nil {
+		return fmt.Errorf("bead %s not found: %w", id, err)
+	}
+
+	var work []trackedIssueInfo
+	if issue.Type == "convoy" {
+		townBeads, err := getTownBeadsDir()
+		if err != nil {
+			return err
+		}
+		for _, t := range getTrackedIssues(townBeads, id) {
+			if t.Status != "closed" {
+				work = append(work, t)
+			}
+		}
+	} else {
+		info := trackedIssueInfo{ID: issue.ID, Title: issue.Title, Status: issue.Status, Assignee: issue.Assignee}
+		if w, ok := getWorkersForIssues([]string{issue.ID})[issue.ID]; ok {
+			info.Worker = w.Worker
+		}
+		work = append(work, info)
+	}
+
+	backend := session.OpenBackend(townRoot, nil)
+	targets := attachTargets(work, func(name string) bool {
+		running, _ := backend.HasSession(name)
+		return running
+	})
+
+	var target attachTarget
+	switch {
+	case len(targets) == 0:
+		if issue.Type == "convoy" {
+			return fmt.Errorf("no running sessions for convoy %s", id)
+		}
+		return fmt.Errorf("no running session for %s", id)
+	case len(targets) == 1:
+		target = targets[0]
+	case !term.IsTerminal(int(os.Stdin.Fd())):
+		printAttachTargets(os.Stdout, targets)
+		return fmt.Errorf("%d sessions match %s; pass a leg bead id", len(targets), id)
+	default:
+		if target, err = pickAttachTarget(bufio.NewReader(os.Stdin), os.Stdout, targets); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s Attaching to %s (%s)\n", style.Bold.Render("→"), target.Session, target.BeadID)
+	if backend.Type() == session.BackendTmux {
+		return attachToTmuxSession(target.Session)
+	}
+	return backend.Attach(target.Session)
+}
+
+// attachTargets maps beads to the running sessions of the agents working
+// on them: the assignee, else the agent hooked to the bead. A session is
+// listed once, under the first bead that names it.
+func attachTargets(work []trackedIssueInfo, running func(session string) bool) []attachTarget {
+	var targets []attachTarget
+	seen := make(map[string]bool)
+	for _, w := range work {
+		for _, agent := range []string{w.Assignee, w.Worker} {
+			if agent == "" {
+				continue
+			}
+			identity, err := session.ParseAddress(agent)
+			if err != nil {
+				continue
+			}
+			name := identity.SessionName()
+			if name == "" || seen[name] || !running(name) {
+				continue
+			}
+			seen[name] = true
+			targets = append(targets, attachTarget{BeadID: w.ID, Title: w.Title, Agent: agent, Session: name})
+			break
+		}
+	}
+	return targets
+}
+
+func printAttachTargets(out io.Writer, targets []attachTarget) {
+	for i, t := range targets {
+		fmt.Fprintf(out, "  %d. %-28s %s  %s\n", i+1, t.Session, t.BeadID, style.Dim.Render(t.Title))
+	}
+}
+
+// pickAttachTarget lists targets and reads the chosen number from in.
+func pickAttachTarget(in *bufio.Reader, out io.Writer, targets []attachTarget) (attachTarget, error) {
+	fmt.Fprintf(out, "%d sessions match:\n", len(targets))
+	printAttachTargets(out, targets)
+	for {
+		fmt.Fprintf(out, "Attach to [1-%d]: ", len(targets))
+		line, err := in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(targets) {
+			return targets[n-1], nil
+		}
+		for _, t := range targets {
+			if answer != "" && (answer == t.Session || answer == t.BeadID) {
+				return t, nil
+			}
+		}
+		if err != nil {
+			return attachTarget{}, fmt.Errorf("no session chosen")
+		}
+		fmt.Fprintln(out, "  Enter a number from the list.")
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAttachTargets(t *testing.T) {
+	work := []trackedIssueInfo{
+		{ID: "gt-leg-1", Title: "Security", Assignee: "gastown/polecats/Toast"},
+		{ID: "gt-leg-2", Title: "Perf", Worker: "gastown/Nux"},
+		{ID: "gt-leg-3", Title: "Docs", Assignee: "gastown/polecats/Gone"},
+		{ID: "gt-leg-4", Title: "Unassigned"},
+		{ID: "gt-leg-5", Title: "Same polecat", Assignee: "gastown/polecats/Toast"},
+	}
+	running := map[string]bool{"gt-gastown-Toast": true, "gt-gastown-Nux": true}
+
+	targets := attachTargets(work, func(s string) bool { return running[s] })
+	if len(targets) != 2 {
+		t.Fatalf("targets = %+v, want 2", targets)
+	}
+	if targets[0].Session != "gt-gastown-Toast" || targets[0].BeadID != "gt-leg-1" {
+		t.Errorf("targets[0] = %+v", targets[0])
+	}
+	if targets[1].Session != "gt-gastown-Nux" || targets[1].BeadID != "gt-leg-2" {
+		t.Errorf("targets[1] = %+v", targets[1])
+	}
+}
+
+func TestPickAttachTarget(t *testing.T) {
+	targets := []attachTarget{
+		{BeadID: "gt-leg-1", Session: "gt-gastown-Toast"},
+		{BeadID: "gt-leg-2", Session: "gt-gastown-Nux"},
+	}
+
+	var out bytes.Buffer
+	got, err := pickAttachTarget(bufio.NewReader(strings.NewReader("7\n2\n")), &out, targets)
+	if err != nil {
+		t.Fatalf("pickAttachTarget: %v", err)
+	}
+	if got.Session != "gt-gastown-Nux" {
+		t.Errorf("picked %s, want gt-gastown-Nux", got.Session)
+	}
+	if !strings.Contains(out.String(), "Enter a number") {
+		t.Errorf("expected a retry prompt after an out-of-range answer:\n%s", out.String())
+	}
+
+	got, err = pickAttachTarget(bufio.NewReader(strings.NewReader("gt-leg-1\n")), &out, targets)
+	if err != nil || got.Session != "gt-gastown-Toast" {
+		t.Errorf("pick by bead id = %+v, %v", got, err)
+	}
+
+	if _, err := pickAttachTarget(bufio.NewReader(strings.NewReader("")), &out, targets); err == nil {
+		t.Error("expected an error when input ends without a choice")
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/backup"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	backupCreateOut    string
+	backupRestoreInto  string
+	backupRestoreForce bool
+	backupRestoreDry   bool
+)
+
+var backupCmd = &cobra.Command{
+	Use:     "backup",
+	GroupID: GroupWorkspace,
+	Short:   "Back up and restore town state",
+	RunE:    requireSubcommand,
+	Long: `Back up and restore the durable state of a town.
+
+A backup captures:
+  - settings/ (town and rig)
+  - mayor/rigs.json, mayor/town.json, mayor/config.json
+  - .beads/ databases (town and rig), including formula overrides
+  - rig config.json and witness audit logs (run history)
+
+Runtime state (.runtime/) and git clones are not included; clones are
+recreated from the rig's git_url.
+
+Commands:
+  create   Write a backup archive
+  restore  Restore a backup into a town`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Write a backup archive of the town",
+	Long: `Write a gzipped tar of the town's durable state.
+
+Beads databases are copied as files, so stop the town first (gt down):
+create refuses to run while a bd daemon is serving any of them.
+
+Examples:
+  gt backup create                         # gastown-backup-<timestamp>.tgz
+  gt backup create --out ~/town.tgz`,
+	RunE: runBackupCreate,
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore a backup archive into a town",
+	Long: `Restore a backup archive into the current town (or --into DIR).
+
+Files that already exist with the same content are left alone. Files that
+exist with different content are conflicts: restore lists them and stops
+without writing anything unless --force is given. SQLite -wal and -shm
+files the backup doesn't have count as conflicts too, and --force deletes
+them so they aren't replayed into the restored database.
+
+Like create, restore refuses to run while a bd daemon is serving any of
+the town's beads databases; stop the town first (gt down).
+
+Examples:
+  gt backup restore town.tgz --dry-run       # Show what would change
+  gt backup restore town.tgz --into ~/gt     # Restore onto a new machine
+  gt backup restore town.tgz --force         # Overwrite conflicting files`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackupRestore,
+}
+
+func init() {
+	backupCreateCmd.Flags().StringVarP(&backupCreateOut, "out", "o", "", "Output file (default: gastown-backup-<timestamp>.tgz)")
+	backupRestoreCmd.Flags().StringVar(&backupRestoreInto, "into", "", "Town directory to restore into (default: current town)")
+	backupRestoreCmd.Flags().BoolVarP(&backupRestoreForce, "force", "f", false, "Overwrite files that differ from the backup")
+	backupRestoreCmd.Flags().BoolVarP(&backupRestoreDry, "dry-run", "n", false, "Show what would change without writing")
+
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	rootCmd.AddCommand(backupCmd)
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var rigs []string
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+		sort.Strings(rigs)
+	}
+
+	out := backupCreateOut
+	if out == "" {
+		out = fmt.Sprintf("gastown-backup-%s.tgz", time.Now().Format("20060102-150405"))
+	}
+
+	// Write atomically so an interrupted backup never looks complete.
+	var manifest *backup.Manifest
+	err = fsx.WriteStream(out, 0600, func(w io.Writer) error {
+		var err error
+		manifest, err = backup.Create(w, townRoot, rigs)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("creating backup %s: %w", out, err)
+	}
+
+	var size int64
+	for _, file := range manifest.Files {
+		size += file.Size
+	}
+	fmt.Printf("%s Backed up %d file(s) (%s) from %d rig(s) to %s\n",
+		style.Success.Render("✓"), len(manifest.Files), formatBytes(size), len(rigs), out)
+	return nil
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	target := backupRestoreInto
+	if target == "" {
+		townRoot, err := workspace.FindFromCwd()
+		if err != nil || townRoot == "" {
+			return fmt.Errorf("not in a Gas Town workspace; use --into DIR")
+		}
+		target = townRoot
+	}
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(args[0]) //nolint:gosec // G304: archive path is user-provided
+	if err != nil {
+		return fmt.Errorf("opening backup: %w", err)
+	}
+	defer f.Close()
+	archive, err := backup.Read(f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup from %s (%s), %d file(s)\n", archive.Manifest.TownRoot,
+		archive.Manifest.CreatedAt.Local().Format("2006-01-02 15:04"), len(archive.Manifest.Files))
+
+	plan := archive.Plan(target)
+	counts := make(map[string]int)
+	for _, p := range plan {
+		counts[p.Action]++
+		if p.Action == backup.ActionConflict {
+			fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), p.Path)
+		} else if p.Action == backup.ActionRemove {
+			fmt.Printf("  %s %s (stale, not in the backup)\n", style.Warning.Render("-"), p.Path)
+		} else if backupRestoreDry && p.Action == backup.ActionCreate {
+			fmt.Printf("  %s %s\n", style.Dim.Render("+"), p.Path)
+		}
+	}
+	fmt.Printf("  %d new, %d unchanged, %d conflicting, %d stale to remove\n",
+		counts[backup.ActionCreate], counts[backup.ActionUnchanged], counts[backup.ActionConflict], counts[backup.ActionRemove])
+
+	if backupRestoreDry {
+		return nil
+	}
+
+	written, err := archive.Restore(target, backupRestoreForce)
+	var conflictErr *backup.ConflictError
+	if errors.As(err, &conflictErr) {
+		return fmt.Errorf("%w; nothing restored (use --force to overwrite, or --dry-run to review)", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Restored %d file(s) into %s\n", style.Success.Render("✓"), written, target)
+	fmt.Printf("  Next: gt doctor --fix   # recreate clones and runtime state\n")
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -107,7 +108,7 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 
 	// Get source bead details
 	showCmd := exec.Command("bd", "show", sourceID, "--json")
-	output, err := showCmd.Output()
+	output, err := cmdtrace.Output(showCmd)
 	if err != nil {
 		return fmt.Errorf("getting bead %s: %w", sourceID, err)
 	}
@@ -161,7 +162,7 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 	// Create the new bead
 	createCmd := exec.Command("bd", createArgs...)
 	createCmd.Stderr = os.Stderr
-	newIDBytes, err := createCmd.Output()
+	newIDBytes, err := cmdtrace.Output(createCmd)
 	if err != nil {
 		return fmt.Errorf("creating new bead: %w", err)
 	}
@@ -173,7 +174,7 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 	closeReason := fmt.Sprintf("Moved to %s", newID)
 	closeCmd := exec.Command("bd", "close", sourceID, "--reason", closeReason)
 	closeCmd.Stderr = os.Stderr
-	if err := closeCmd.Run(); err != nil {
+	if err := cmdtrace.Run(closeCmd); err != nil {
 		// Try to clean up the new bead if close fails
 		fmt.Fprintf(os.Stderr, "Warning: failed to close source bead: %v\n", err)
 		fmt.Fprintf(os.Stderr, "New bead %s was created but source %s remains open\n", newID, sourceID)
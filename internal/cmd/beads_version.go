@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // MinBeadsVersion is the minimum required beads version for Gas Town.
@@ -97,7 +99,7 @@ func getBeadsVersion() (string, error) {
 	// Use --no-daemon to avoid contention when multiple agents start simultaneously.
 	// Version check doesn't need database access, so direct mode is faster and more reliable.
 	cmd := exec.CommandContext(ctx, "bd", "version", "--no-daemon")
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return "", fmt.Errorf("bd version check timed out")
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -347,7 +348,7 @@ func runDegradedTriage(b *boot.Boot) (action, target string, err error) {
 func getDeaconHookBead() string {
 	// The deacon agent bead is hq-deacon (town-level)
 	cmd := exec.Command("bd", "slot", "show", "hq-deacon", "--json")
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		// If we can't check, assume no hook (may false-positive nudge on bd failure)
 		return ""
@@ -376,7 +377,7 @@ func getDeaconHookBead() string {
 // TODO(steveyegge/beads#1456): Replace with `bd mol last-activity` when available.
 func getMoleculeLastActivity(molID string) (time.Time, error) {
 	cmd := exec.Command("bd", "mol", "current", molID, "--json")
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return time.Time{}, err
 	}
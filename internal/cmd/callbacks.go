@@ -482,5 +482,6 @@ func handleSling(townRoot string, msg *mail.Message, dryRun bool) (string, error
 // logCallback logs a callback processing event to the town log.
 func logCallback(townRoot, context string) {
 	logger := townlog.NewLogger(townRoot)
+	defer logger.Close()
 	_ = logger.Log(townlog.EventCallback, "mayor/", context)
 }
@@ -9,7 +9,11 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -29,7 +33,8 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config resolve [--rig NAME]     Show effective settings after includes`,
 }
 
 // Agent subcommands
@@ -140,6 +145,73 @@ Examples:
 	RunE: runConfigAgentEmailDomain,
 }
 
+var configLocaleCmd = &cobra.Command{
+	Use:   "locale [locale]",
+	Short: "Get or set the output language",
+	Long: `Get or set the language gt uses for its output in this town.
+
+With no arguments, shows the configured locale and the language in effect.
+With an argument, sets the locale; use "" to follow the environment again.
+
+The language is chosen from, in order: the GT_LOCALE environment variable,
+this setting, then LC_ALL, LC_MESSAGES, or LANG. Locales may be given as
+"de", "de-AT", or "de_AT.UTF-8"; unsupported languages fall back to English.
+
+Translated so far: formula and doctor output.
+
+Examples:
+  gt config locale              # Show current locale
+  gt config locale de           # German output for everyone in this town
+  gt config locale ""           # Follow LANG again
+  GT_LOCALE=es gt doctor        # Override for a single command`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigLocale,
+}
+
+var configBeadsBackendCmd = &cobra.Command{
+	Use:   "beads-backend [cli|daemon]",
+	Short: "Get or set how gt talks to beads",
+	Long: `Get or set how gt talks to beads in this town.
+
+  cli     Run a standalone bd process (--no-daemon) per operation (default)
+  daemon  Run bd through its daemon when one is running
+
+Daemon mode saves each bd process from opening the database, which speeds
+up commands that make many bd calls (convoy create and status). Either way
+gt reads and writes beads only through bd. The GT_BEADS_BACKEND
+environment variable overrides this setting.
+
+Examples:
+  gt config beads-backend               # Show current backend
+  gt config beads-backend daemon        # Go through the bd daemon
+  GT_BEADS_BACKEND=cli gt convoy list   # Override for a single command`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigBeadsBackend,
+}
+
+var configSessionBackendCmd = &cobra.Command{
+	Use:   "session-backend [tmux|screen|process]",
+	Short: "Get or set what polecat sessions run in",
+	Long: `Get or set what polecat sessions run in for this town.
+
+  tmux     tmux sessions (default)
+  screen   GNU screen sessions, for hosts without tmux
+  process  A detached background process per polecat, for containers and
+           servers where nobody attaches
+
+Process sessions can't be attached or typed into: the agent finds its work
+through gt prime, and its output goes to the session log (gt logs). Themes,
+crash hooks, and readiness checks need tmux. The GT_SESSION_BACKEND
+environment variable overrides this setting.
+
+Examples:
+  gt config session-backend              # Show current backend
+  gt config session-backend process      # Run polecats as background processes
+  GT_SESSION_BACKEND=screen gt sling ... # Override for a single command`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigSessionBackend,
+}
+
 // Flags
 var (
 	configAgentListJSON bool
@@ -319,8 +391,13 @@ func runConfigAgentSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 
-	// Load town settings
+	// Load town settings, holding the lock until they are saved
 	settingsPath := config.TownSettingsPath(townRoot)
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
@@ -378,8 +455,13 @@ func runConfigAgentRemove(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Load town settings
+	// Load town settings, holding the lock until they are saved
 	settingsPath := config.TownSettingsPath(townRoot)
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
@@ -408,8 +490,13 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 
-	// Load town settings
+	// Load town settings, holding the lock until they are saved
 	settingsPath := config.TownSettingsPath(townRoot)
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
@@ -471,8 +558,13 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 
-	// Load town settings
+	// Load town settings, holding the lock until they are saved
 	settingsPath := config.TownSettingsPath(townRoot)
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
@@ -513,6 +605,151 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigLocale(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	// Load town settings, holding the lock until they are saved
+	settingsPath := config.TownSettingsPath(townRoot)
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	if len(args) == 0 {
+		configured := townSettings.Locale
+		if configured == "" {
+			configured = "(not set)"
+		}
+		fmt.Printf("Locale: %s\n", style.Bold.Render(configured))
+		fmt.Printf("Output language: %s\n", i18n.Match(i18n.Resolve(townSettings.Locale)))
+		return nil
+	}
+
+	locale := args[0]
+	parsed, err := i18n.Parse(locale)
+	if err != nil {
+		return err
+	}
+	townSettings.Locale = locale
+
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	if locale == "" {
+		fmt.Println("Locale cleared; output follows LC_ALL/LC_MESSAGES/LANG")
+		return nil
+	}
+	fmt.Printf("Locale set to '%s'\n", style.Bold.Render(locale))
+	want, _ := parsed.Base()
+	if got, _ := i18n.Match(locale).Base(); got != want {
+		fmt.Println(style.Dim.Render(fmt.Sprintf("(no %s translation yet; output stays in English)", want)))
+	}
+	return nil
+}
+
+func runConfigBeadsBackend(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	// Load town settings, holding the lock until they are saved
+	settingsPath := config.TownSettingsPath(townRoot)
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	if len(args) == 0 {
+		configured, err := beads.ParseBackend(townSettings.BeadsBackend)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Beads backend: %s\n", style.Bold.Render(string(configured)))
+		if env := os.Getenv("GT_BEADS_BACKEND"); env != "" {
+			fmt.Printf("Overridden by GT_BEADS_BACKEND: %s\n", style.Bold.Render(string(beads.BackendFor(townRoot))))
+		}
+		return nil
+	}
+
+	backend, err := beads.ParseBackend(args[0])
+	if err != nil {
+		return err
+	}
+	townSettings.BeadsBackend = string(backend)
+	if backend == beads.BackendCLI {
+		townSettings.BeadsBackend = "" // Default; keep settings minimal
+	}
+
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	fmt.Printf("Beads backend set to '%s'\n", style.Bold.Render(string(backend)))
+	return nil
+}
+
+func runConfigSessionBackend(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	// Load town settings, holding the lock until they are saved
+	settingsPath := config.TownSettingsPath(townRoot)
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	if len(args) == 0 {
+		configured, err := session.ParseBackend(townSettings.SessionBackend)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Session backend: %s\n", style.Bold.Render(string(configured)))
+		if env := os.Getenv("GT_SESSION_BACKEND"); env != "" {
+			fmt.Printf("Overridden by GT_SESSION_BACKEND: %s\n", style.Bold.Render(string(session.BackendTypeFor(townRoot))))
+		}
+		return nil
+	}
+
+	backend, err := session.ParseBackend(args[0])
+	if err != nil {
+		return err
+	}
+	townSettings.SessionBackend = string(backend)
+	if backend == session.BackendTmux {
+		townSettings.SessionBackend = "" // Default; keep settings minimal
+	}
+
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	fmt.Printf("Session backend set to '%s'\n", style.Bold.Render(string(backend)))
+	return nil
+}
+
 func init() {
 	// Add flags
 	configAgentListCmd.Flags().BoolVar(&configAgentListJSON, "json", false, "Output as JSON")
@@ -532,6 +769,9 @@ func init() {
 	configCmd.AddCommand(configAgentCmd)
 	configCmd.AddCommand(configDefaultAgentCmd)
 	configCmd.AddCommand(configAgentEmailDomainCmd)
+	configCmd.AddCommand(configLocaleCmd)
+	configCmd.AddCommand(configBeadsBackendCmd)
+	configCmd.AddCommand(configSessionBackendCmd)
 
 	// Register with root
 	rootCmd.AddCommand(configCmd)
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	configResolveRig  string
+	configResolveJSON bool
+)
+
+var configResolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Show the effective settings after includes are applied",
+	Long: `Show the flattened town (and optionally rig) settings after resolving
+"include" lists, along with every file that contributed.
+
+A settings/config.json can layer other files underneath it:
+
+  {"include": ["config.base.json", "config.dev.json"], "default_agent": "claude"}
+
+Merge semantics:
+  - Include paths are relative to the including file
+  - Includes merge in order; later files override earlier ones
+  - The including file overrides all of its includes
+  - Objects merge key by key; arrays and scalars replace
+  - An explicit null removes an inherited key
+  - Included files may include others; cycles are an error
+
+Examples:
+  gt config resolve                  # Town settings
+  gt config resolve --rig gastown    # Town and rig settings
+  gt config resolve --rig gastown --json`,
+	RunE: runConfigResolve,
+}
+
+func init() {
+	configResolveCmd.Flags().StringVar(&configResolveRig, "rig", "", "Also resolve this rig's settings")
+	configResolveCmd.Flags().BoolVar(&configResolveJSON, "json", false, "Output as JSON")
+	configCmd.AddCommand(configResolveCmd)
+}
+
+// resolvedSettings is one flattened settings file.
+type resolvedSettings struct {
+	Path     string                 `json:"path"`
+	Sources  []string               `json:"sources"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+func runConfigResolve(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	result := make(map[string]*resolvedSettings)
+	town, err := resolveSettingsFile(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("resolving town settings: %w", err)
+	}
+	result["town"] = town
+
+	if configResolveRig != "" {
+		_, r, err := getRig(configResolveRig)
+		if err != nil {
+			return err
+		}
+		rigSettings, err := resolveSettingsFile(config.RigSettingsPath(r.Path))
+		if err != nil {
+			return fmt.Errorf("resolving rig settings: %w", err)
+		}
+		result["rig"] = rigSettings
+	}
+
+	if configResolveJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	printResolvedSettings("Town settings", town)
+	if rigSettings, ok := result["rig"]; ok {
+		fmt.Println()
+		printResolvedSettings(fmt.Sprintf("Rig settings (%s)", configResolveRig), rigSettings)
+	}
+	return nil
+}
+
+// resolveSettingsFile flattens one settings file. A missing file resolves
+// to empty settings with no sources.
+func resolveSettingsFile(path string) (*resolvedSettings, error) {
+	res := &resolvedSettings{Path: path, Sources: []string{}, Settings: map[string]interface{}{}}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return res, nil
+	}
+	settings, sources, err := config.ResolveLayeredConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	res.Settings = settings
+	res.Sources = sources
+	return res, nil
+}
+
+func printResolvedSettings(title string, res *resolvedSettings) {
+	fmt.Printf("%s\n", style.Bold.Render(title))
+	if len(res.Sources) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no settings file at "+res.Path+")"))
+		return
+	}
+	fmt.Println("  Sources (lowest priority first):")
+	for _, src := range res.Sources {
+		fmt.Printf("    %s\n", src)
+	}
+	data, err := json.MarshalIndent(res.Settings, "  ", "  ")
+	if err != nil {
+		return
+	}
+	fmt.Printf("  %s\n", data)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,6 +18,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -113,7 +116,9 @@ COMMANDS:
   add       Add issues to an existing convoy (reopens if closed)
   close     Close a convoy (manually, regardless of tracked issue status)
   status    Show convoy progress, tracked issues, and active workers
-  list      List convoys (the dashboard view)`,
+  list      List convoys (the dashboard view)
+  report    Export convoy results (JUnit XML, JSON) for CI
+  clone     Run a formula convoy's review again on another PR or rig`,
 }
 
 var convoyCreateCmd = &cobra.Command{
@@ -336,49 +341,34 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	// Generate convoy ID with cv- prefix
 	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
 
-	createArgs := []string{
-		"create",
-		"--type=convoy",
-		"--id=" + convoyID,
-		"--title=" + name,
-		"--description=" + description,
-		"--json",
-	}
-	if beads.NeedsForceForID(convoyID) {
-		createArgs = append(createArgs, "--force")
-	}
-
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	createCmd.Stdout = &stdout
-	createCmd.Stderr = &stderr
-
-	if err := createCmd.Run(); err != nil {
-		return fmt.Errorf("creating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
-	}
-
-	// Notify address is stored in description (line 166-168) and read from there
-
-	// Add 'tracks' relations for each tracked issue
-	trackedCount := 0
+	// Create the convoy and its 'tracks' relations (non-blocking) in one
+	// batched bd operation
+	batch := beads.OpenStore(townBeads).NewBatch()
+	batch.Create(convoyID, beads.BatchIssue{
+		ID:          convoyID,
+		Type:        "convoy",
+		Title:       name,
+		Description: description,
+		Priority:    -1,
+	})
 	for _, issueID := range trackedIssues {
-		// Use --type=tracks for non-blocking tracking relation
-		depArgs := []string{"dep", "add", convoyID, issueID, "--type=tracks"}
-		depCmd := exec.Command("bd", depArgs...)
-		depCmd.Dir = townBeads
-		var depStderr bytes.Buffer
-		depCmd.Stderr = &depStderr
+		batch.AddDependency(issueID, convoyID, issueID, "tracks")
+	}
 
-		if err := depCmd.Run(); err != nil {
-			errMsg := strings.TrimSpace(depStderr.String())
-			if errMsg == "" {
-				errMsg = err.Error()
+	trackedCount := len(trackedIssues)
+	if err := batch.Apply(); err != nil {
+		batchErr, ok := err.(*beads.BatchError)
+		if !ok {
+			return fmt.Errorf("creating convoy: %w", err)
+		}
+		if createErr := batchErr.CreateFailed(convoyID); createErr != nil {
+			return fmt.Errorf("creating convoy: %w", createErr)
+		}
+		for _, issueID := range trackedIssues {
+			if trackErr := batchErr.Failed(issueID); trackErr != nil {
+				style.PrintWarning("couldn't track %s: %v", issueID, trackErr)
+				trackedCount--
 			}
-			style.PrintWarning("couldn't track %s: %s", issueID, errMsg)
-		} else {
-			trackedCount++
 		}
 	}
 
@@ -420,7 +410,7 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -451,7 +441,7 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		reopenArgs := []string{"update", convoyID, "--status=open"}
 		reopenCmd := exec.Command("bd", reopenArgs...)
 		reopenCmd.Dir = townBeads
-		if err := reopenCmd.Run(); err != nil {
+		if err := cmdtrace.Run(reopenCmd); err != nil {
 			return fmt.Errorf("couldn't reopen convoy: %w", err)
 		}
 		reopened = true
@@ -467,7 +457,7 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		var depStderr bytes.Buffer
 		depCmd.Stderr = &depStderr
 
-		if err := depCmd.Run(); err != nil {
+		if err := cmdtrace.Run(depCmd); err != nil {
 			errMsg := strings.TrimSpace(depStderr.String())
 			if errMsg == "" {
 				errMsg = err.Error()
@@ -496,6 +486,10 @@ func runConvoyCheck(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Legs queued by the check, or waiting since an earlier one, go to
+	// polecats freed by the legs that just closed
+	defer dispatchLegQueue(filepath.Dir(townBeads), legDispatchOptions{DryRun: convoyCheckDryRun})
+
 	// If a specific convoy ID is provided, check only that convoy
 	if len(args) == 1 {
 		convoyID := args[0]
@@ -533,7 +527,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -572,6 +566,17 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 		return nil
 	}
 
+	// Failed legs with a retry policy go back in the queue, and keep the
+	// convoy open
+	if retrying := retryFailedLegs(townBeads, convoy.Description, tracked, dryRun); len(retrying) > 0 {
+		tracked = slices.Clone(tracked)
+		for i := range tracked {
+			if retrying[tracked[i].ID] {
+				tracked[i].Status = "open"
+			}
+		}
+	}
+
 	// Check if all tracked issues are closed
 	allClosed := true
 	openCount := 0
@@ -584,6 +589,8 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 
 	if !allClosed {
 		fmt.Printf("%s Convoy %s has %d open issue(s) remaining\n", style.Dim.Render("○"), convoyID, openCount)
+		// Formula legs waiting on other legs may be ready now
+		dispatchReadyLegs(townBeads, convoy.Description, tracked, dryRun)
 		return nil
 	}
 
@@ -598,11 +605,12 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
 
-	if err := closeCmd.Run(); err != nil {
+	if err := cmdtrace.Run(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
 	fmt.Printf("%s Auto-closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	closeFormulaMolecule(townBeads, convoyID, convoy.Description)
 
 	// Send completion notification
 	notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
@@ -625,7 +633,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -668,11 +676,13 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
 
-	if err := closeCmd.Run(); err != nil {
+	if err := cmdtrace.Run(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
 	fmt.Printf("%s Closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	closeFormulaMolecule(townBeads, convoyID, convoy.Description)
+	dropPendingApproval(filepath.Dir(townBeads), convoyID)
 	if convoyCloseReason != "" {
 		fmt.Printf("  Reason: %s\n", convoyCloseReason)
 	}
@@ -695,7 +705,7 @@ func sendCloseNotification(addr, convoyID, title, reason string) {
 
 	mailArgs := []string{"mail", "send", addr, "-s", subject, "-m", body}
 	mailCmd := exec.Command("gt", mailArgs...)
-	if err := mailCmd.Run(); err != nil {
+	if err := cmdtrace.Run(mailCmd); err != nil {
 		style.PrintWarning("couldn't send notification: %v", err)
 	} else {
 		fmt.Printf("  Notified: %s\n", addr)
@@ -771,7 +781,7 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := cmdtrace.Run(listCmd); err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -820,7 +830,7 @@ func getBlockedIssueIDs() map[string]bool {
 	var stdout bytes.Buffer
 	blockedCmd.Stdout = &stdout
 
-	if err := blockedCmd.Run(); err != nil {
+	if err := cmdtrace.Run(blockedCmd); err != nil {
 		return blocked // Return empty set on error
 	}
 
@@ -899,13 +909,14 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := cmdtrace.Run(listCmd); err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
 	var convoys []struct {
-		ID    string `json:"id"`
-		Title string `json:"title"`
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
 		return nil, fmt.Errorf("parsing convoy list: %w", err)
@@ -927,6 +938,11 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			}
 		}
 
+		// A failed leg going back in the queue keeps the convoy open
+		if allClosed && len(retryFailedLegs(townBeads, convoy.Description, tracked, dryRun)) > 0 {
+			continue
+		}
+
 		if allClosed {
 			if dryRun {
 				// In dry-run mode, just record what would be closed
@@ -939,12 +955,13 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			closeCmd := exec.Command("bd", closeArgs...)
 			closeCmd.Dir = townBeads
 
-			if err := closeCmd.Run(); err != nil {
+			if err := cmdtrace.Run(closeCmd); err != nil {
 				style.PrintWarning("couldn't close convoy %s: %v", convoy.ID, err)
 				continue
 			}
 
 			closed = append(closed, struct{ ID, Title string }{convoy.ID, convoy.Title})
+			closeFormulaMolecule(townBeads, convoy.ID, convoy.Description)
 
 			// Check if convoy has notify address and send notification
 			notifyConvoyCompletion(townBeads, convoy.ID, convoy.Title)
@@ -954,8 +971,14 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 	return closed, nil
 }
 
-// notifyConvoyCompletion sends notifications to owner and any notify addresses.
+// notifyConvoyCompletion sends notifications to owner and any notify
+// addresses, and runs the town's on_convoy_complete hooks.
 func notifyConvoyCompletion(townBeads, convoyID, title string) {
+	fireLifecycleHook(filepath.Dir(townBeads), config.HookConvoyComplete, map[string]string{
+		"convoy_id": convoyID,
+		"title":     title,
+	})
+
 	// Get convoy description to find owner and notify addresses
 	showArgs := []string{"show", convoyID, "--json"}
 	showCmd := exec.Command("bd", showArgs...)
@@ -963,7 +986,7 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return
 	}
 
@@ -992,7 +1015,7 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 				"-s", fmt.Sprintf("🚚 Convoy landed: %s", title),
 				"-m", fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.", convoyID)}
 			mailCmd := exec.Command("gt", mailArgs...)
-			_ = mailCmd.Run() // Best effort, ignore errors
+			_ = cmdtrace.Run(mailCmd) // Best effort, ignore errors
 			notified[addr] = true
 		}
 	}
@@ -1027,7 +1050,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -1146,7 +1169,7 @@ func showAllConvoyStatus(townBeads string) error {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := cmdtrace.Run(listCmd); err != nil {
 		return fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -1200,7 +1223,7 @@ func runConvoyList(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := cmdtrace.Run(listCmd); err != nil {
 		return fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -1332,7 +1355,7 @@ func getTrackedIssues(townBeads, convoyID string) []trackedIssueInfo {
 
 	var stdout bytes.Buffer
 	depCmd.Stdout = &stdout
-	if err := depCmd.Run(); err != nil {
+	if err := cmdtrace.Run(depCmd); err != nil {
 		return nil
 	}
 
@@ -1404,7 +1427,7 @@ func getExternalIssueDetails(townBeads, rigName, issueID string) *issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return nil
 	}
 	if stdout.Len() == 0 {
@@ -1442,6 +1465,7 @@ type issueDetails struct {
 	Status    string
 	IssueType string
 	Assignee  string
+	Labels    []string
 }
 
 // getIssueDetailsBatch fetches details for multiple issues in a single bd show call.
@@ -1461,7 +1485,7 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		// Batch failed - fall back to individual lookups for robustness
 		// This handles cases where some IDs are invalid/missing
 		for _, id := range issueIDs {
@@ -1505,7 +1529,7 @@ func getIssueDetails(issueID string) *issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return nil
 	}
 	// Handle bd --no-daemon exit 0 bug: empty stdout means not found
@@ -1514,11 +1538,12 @@ func getIssueDetails(issueID string) *issueDetails {
 	}
 
 	var issues []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		IssueType string `json:"issue_type"`
-		Assignee  string `json:"assignee"`
+		ID        string   `json:"id"`
+		Title     string   `json:"title"`
+		Status    string   `json:"status"`
+		IssueType string   `json:"issue_type"`
+		Assignee  string   `json:"assignee"`
+		Labels    []string `json:"labels"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil || len(issues) == 0 {
 		return nil
@@ -1530,6 +1555,7 @@ func getIssueDetails(issueID string) *issueDetails {
 		Status:    issues[0].Status,
 		IssueType: issues[0].IssueType,
 		Assignee:  issues[0].Assignee,
+		Labels:    issues[0].Labels,
 	}
 }
 
@@ -1598,7 +1624,7 @@ func getWorkersForIssues(issueIDs []string) map[string]*workerInfo {
 			cmd.Dir = beadsDir
 			var stdout bytes.Buffer
 			cmd.Stdout = &stdout
-			if err := cmd.Run(); err != nil {
+			if err := cmdtrace.Run(cmd); err != nil {
 				resultChan <- rigResult{}
 				return
 			}
@@ -1709,7 +1735,7 @@ func resolveConvoyNumber(townBeads string, n int) (string, error) {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := cmdtrace.Run(listCmd); err != nil {
 		return "", fmt.Errorf("listing convoys: %w", err)
 	}
 
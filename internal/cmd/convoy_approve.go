@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// ApprovalPendingLabel marks a formula convoy whose legs wait for
+// gt convoy approve.
+const ApprovalPendingLabel = "approval:pending"
+
+// approvalMailFn sends approval requests; a seam for tests.
+var approvalMailFn = sendMail
+
+var convoyApproveCmd = &cobra.Command{
+	Use:   "approve [convoy-id]",
+	Short: "Approve a formula convoy held for approval",
+	Long: `Release a formula convoy held for approval, so its legs dispatch.
+
+Formulas with approval = true, and every formula run on a rig whose
+settings set approval.required, create their convoy and beads but dispatch
+nothing until approved. Approvers are notified by mail when such a run is
+created. If the rig lists approval.approvers, only those addresses may
+approve; otherwise anyone may.
+
+Without a convoy ID, lists the convoys waiting for approval.
+
+Examples:
+  gt convoy approve
+  gt convoy approve hq-cv-abc12`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConvoyApprove,
+}
+
+func init() {
+	convoyCmd.AddCommand(convoyApproveCmd)
+}
+
+func runConvoyApprove(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	townRoot := filepath.Dir(townBeads)
+	if len(args) == 0 {
+		return listPendingApprovals(townRoot)
+	}
+
+	convoyID := args[0]
+	approver := detectSender()
+	p, err := approveConvoy(townRoot, convoyID, approver)
+	if err != nil {
+		return err
+	}
+
+	bd := beads.New(townBeads)
+	if err := bd.Update(convoyID, beads.UpdateOptions{RemoveLabels: []string{ApprovalPendingLabel}}); err != nil {
+		fmt.Printf("%s Failed to clear %s on %s: %v\n", style.Dim.Render("Warning:"), ApprovalPendingLabel, convoyID, err)
+	}
+	commentCmd := exec.Command("bd", "comment", convoyID, "Approved by "+approver)
+	commentCmd.Dir = townBeads
+	_ = cmdtrace.Run(commentCmd) // Best effort; the audit log has the record
+	recordAudit(townRoot, p.Rig, witness.AuditEntry{
+		Action:  witness.ActionConvoyApprove,
+		Subject: convoyID,
+		Details: map[string]string{
+			"formula":  p.Formula,
+			"approver": approver,
+			"legs":     fmt.Sprintf("%d queued", len(p.Legs)),
+		},
+	})
+
+	fmt.Printf("%s Approved %s (%s): %d leg(s) queued\n", style.Bold.Render("✓"), convoyID, p.Formula, len(p.Legs))
+	if paused, _, _ := mayor.IsTownPaused(townRoot); paused {
+		fmt.Println("Town is paused; legs stay queued until gt resume --town.")
+		return nil
+	}
+	if err := maintenanceWindowError(townRoot); err != nil {
+		fmt.Printf("%s; legs stay queued until it closes.\n", capitalizeFirst(err.Error()))
+		return nil
+	}
+	dispatched := dispatchLegQueue(townRoot, legDispatchOptions{})
+	fmt.Printf("  Dispatched: %d (the rest wait in gt queue list)\n", len(dispatched))
+	return nil
+}
+
+// approveConvoy checks that approver may approve a pending convoy, then
+// moves its legs from pending approval to the leg queue.
+func approveConvoy(townRoot, convoyID, approver string) (convoy.PendingApproval, error) {
+	var p convoy.PendingApproval
+	err := convoy.UpdateApprovals(townRoot, func(a *convoy.Approvals) error {
+		var ok bool
+		if p, ok = a.Lookup(convoyID); !ok {
+			return fmt.Errorf("convoy %s is not awaiting approval (see gt convoy approve)", convoyID)
+		}
+		approval := loadRigApproval(filepath.Join(townRoot, p.Rig))
+		if !approval.MayApprove(approver) {
+			return fmt.Errorf("%s may not approve runs on rig %s (approvers: %s)",
+				approver, p.Rig, strings.Join(approval.Approvers, ", "))
+		}
+		if err := enqueueLegs(townRoot, p.Legs); err != nil {
+			return fmt.Errorf("queueing legs: %w", err)
+		}
+		a.Take(convoyID)
+		return nil
+	})
+	return p, err
+}
+
+// listPendingApprovals prints the convoys waiting for approval.
+func listPendingApprovals(townRoot string) error {
+	a, err := convoy.LoadApprovals(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading approvals: %w", err)
+	}
+	if len(a.Pending) == 0 {
+		fmt.Println("No convoys awaiting approval.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONVOY\tRIG\tFORMULA\tLEGS\tREQUESTED BY\tAGE")
+	for _, p := range a.Pending {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", p.ConvoyID, p.Rig, p.Formula, len(p.Legs),
+			p.RequestedBy, formatWorkerAge(time.Since(p.RequestedAt)))
+	}
+	return tw.Flush()
+}
+
+// loadRigApproval returns a rig's approval settings, or nil if it has none.
+func loadRigApproval(rigPath string) *config.ApprovalConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Approval
+}
+
+// formulaRunNeedsApproval reports whether a run of f on a rig must be
+// approved before its legs dispatch.
+func formulaRunNeedsApproval(f *formulaData, townRoot, rigName string) bool {
+	if f.Approval {
+		return true
+	}
+	approval := loadRigApproval(filepath.Join(townRoot, rigName))
+	return approval != nil && approval.Required
+}
+
+// holdForApproval records a new convoy's ready legs as pending approval
+// instead of queueing them, labels the convoy, and asks its approvers.
+func holdForApproval(townRoot, convoyID, title, formulaName, rigName string, legs []convoy.QueuedLeg) error {
+	p := convoy.PendingApproval{
+		ConvoyID:    convoyID,
+		Rig:         rigName,
+		Formula:     formulaName,
+		RequestedBy: detectSender(),
+		Legs:        legs,
+	}
+	if err := convoy.UpdateApprovals(townRoot, func(a *convoy.Approvals) error {
+		a.Add(p)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("recording pending approval: %w", err)
+	}
+	bd := beads.New(filepath.Join(townRoot, ".beads"))
+	if err := bd.Update(convoyID, beads.UpdateOptions{AddLabels: []string{ApprovalPendingLabel}}); err != nil {
+		fmt.Printf("%s Failed to label %s %s: %v\n", style.Dim.Render("Warning:"), convoyID, ApprovalPendingLabel, err)
+	}
+	notifyApprovers(townRoot, loadRigApproval(filepath.Join(townRoot, rigName)), p, title)
+	return nil
+}
+
+// notifyApprovers mails a pending run to the rig's approvers, or to the
+// overseer if the rig names none.
+func notifyApprovers(townRoot string, approval *config.ApprovalConfig, p convoy.PendingApproval, title string) {
+	approvers := []string{"overseer"}
+	if approval != nil && len(approval.Approvers) > 0 {
+		approvers = approval.Approvers
+	}
+	subject := fmt.Sprintf("🚦 Approval needed: %s", title)
+	body := fmt.Sprintf("Formula %s on rig %s was started by %s and is waiting for approval.\n\n"+
+		"Convoy: %s\nLegs ready to dispatch: %d\n\nApprove with: gt convoy approve %s\nOr close it: gt convoy close %s",
+		p.Formula, p.Rig, p.RequestedBy, p.ConvoyID, len(p.Legs), p.ConvoyID, p.ConvoyID)
+	for _, addr := range approvers {
+		approvalMailFn(townRoot, addr, subject, body)
+	}
+}
+
+// dropPendingApproval forgets a convoy's pending approval, e.g. when it is
+// closed unapproved.
+func dropPendingApproval(townRoot, convoyID string) {
+	if a, err := convoy.LoadApprovals(townRoot); err != nil || len(a.Pending) == 0 {
+		return
+	}
+	_ = convoy.UpdateApprovals(townRoot, func(a *convoy.Approvals) error {
+		a.Take(convoyID)
+		return nil
+	})
+}
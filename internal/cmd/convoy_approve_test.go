@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/convoy"
+)
+
+func writeRigApproval(t *testing.T, townRoot, rigName string, approval *config.ApprovalConfig) {
+	t.Helper()
+	settings := config.NewRigSettings()
+	settings.Approval = approval
+	if err := config.SaveRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)), settings); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+}
+
+func TestFormulaRunNeedsApproval(t *testing.T) {
+	town := t.TempDir()
+	writeRigApproval(t, town, "gastown", &config.ApprovalConfig{Required: true})
+	writeRigApproval(t, town, "beads", &config.ApprovalConfig{Approvers: []string{"overseer"}})
+
+	if !formulaRunNeedsApproval(&formulaData{}, town, "gastown") {
+		t.Error("rig with approval.required should hold every run")
+	}
+	if formulaRunNeedsApproval(&formulaData{}, town, "beads") {
+		t.Error("approvers alone should not hold runs")
+	}
+	if !formulaRunNeedsApproval(&formulaData{Approval: true}, town, "beads") {
+		t.Error("formula with approval = true should be held")
+	}
+}
+
+func TestApproveConvoy(t *testing.T) {
+	town := t.TempDir()
+	writeRigApproval(t, town, "gastown", &config.ApprovalConfig{Approvers: []string{"overseer"}})
+
+	var mailed []string
+	approvalMailFn = func(_, to, subject, _ string) { mailed = append(mailed, to+": "+subject) }
+	defer func() { approvalMailFn = sendMail }()
+
+	legs := []convoy.QueuedLeg{{BeadID: "hq-leg-1", Rig: "gastown", ConvoyID: "hq-cv-1"}}
+	if err := holdForApproval(town, "hq-cv-1", "deploy: ship it", "deploy", "gastown", legs); err != nil {
+		t.Fatalf("holdForApproval() = %v", err)
+	}
+	if len(mailed) != 1 || !strings.HasPrefix(mailed[0], "overseer: ") || !strings.Contains(mailed[0], "deploy: ship it") {
+		t.Errorf("mailed = %q, want one request to the overseer", mailed)
+	}
+	if q, _ := convoy.LoadQueue(town); !q.Empty() {
+		t.Errorf("held legs were queued: %+v", q)
+	}
+
+	if _, err := approveConvoy(town, "hq-cv-1", "gastown/crew/joe"); err == nil || !strings.Contains(err.Error(), "may not approve") {
+		t.Errorf("approveConvoy(non-approver) = %v, want refusal", err)
+	}
+	p, err := approveConvoy(town, "hq-cv-1", "overseer")
+	if err != nil {
+		t.Fatalf("approveConvoy(overseer) = %v", err)
+	}
+	if p.Formula != "deploy" {
+		t.Errorf("approved %+v", p)
+	}
+	q, _ := convoy.LoadQueue(town)
+	if !q.Has("hq-leg-1") {
+		t.Errorf("queue = %+v, want the approved leg", q)
+	}
+	if _, err := approveConvoy(town, "hq-cv-1", "overseer"); err == nil {
+		t.Error("approveConvoy() approved the same convoy twice")
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Convoy clone flags
+var (
+	convoyClonePR     int
+	convoyCloneRig    string
+	convoyCloneDryRun bool
+)
+
+// Set while gt convoy clone dispatches; recorded in the new convoy's
+// description and audit entry, and linked with a "related" dependency.
+var formulaRunClonedFrom string
+
+var convoyCloneCmd = &cobra.Command{
+	Use:   "clone <convoy-id>",
+	Short: "Run a convoy's formula again on a different target",
+	Long: `Run the formula behind a convoy again, on a different PR or rig.
+
+The formula is looked up by the name recorded on the original convoy, so
+the clone picks up any edits made to it since. The PR and rig default to
+the original's; pass --pr and/or --rig to retarget.
+
+The new convoy records "cloned_from: <convoy-id>" and is linked to the
+original with a "related" dependency, so 'bd show' on either finds the
+other.
+
+Examples:
+  gt convoy clone hq-cv-abc --pr 456       # Same review, another PR
+  gt convoy clone hq-cv-abc --rig beads    # Same review, another rig
+  gt convoy clone hq-cv-abc --dry-run      # Preview the new run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyClone,
+}
+
+func init() {
+	convoyCloneCmd.Flags().IntVar(&convoyClonePR, "pr", 0, "GitHub PR number to run on (default: the original's)")
+	convoyCloneCmd.Flags().StringVar(&convoyCloneRig, "rig", "", "Target rig (default: the original's)")
+	convoyCloneCmd.Flags().BoolVar(&convoyCloneDryRun, "dry-run", false, "Preview execution without running")
+	convoyCloneCmd.Flags().BoolVar(&formulaRunOverridePause, "override-pause", false, "Run even though the town is paused (gt pause) or in a maintenance window")
+
+	convoyCmd.AddCommand(convoyCloneCmd)
+}
+
+func runConvoyClone(cmd *cobra.Command, args []string) error {
+	meta, err := getConvoyMeta(args[0])
+	if err != nil {
+		return err
+	}
+	pr, targetRig, err := cloneTarget(meta, convoyClonePR, convoyCloneRig)
+	if err != nil {
+		return err
+	}
+
+	formulaPath, err := findFormulaFile(meta.Formula)
+	if err != nil {
+		return fmt.Errorf("finding formula: %w", err)
+	}
+	f, err := parseFormulaFile(formulaPath)
+	if err != nil {
+		return fmt.Errorf("parsing formula: %w", err)
+	}
+	warnFormulaSchema(f)
+	if f.Type != "convoy" {
+		return fmt.Errorf("formula %s is now type %q; only convoy formulas can be cloned", meta.Formula, f.Type)
+	}
+
+	formulaRunPR = pr
+	formulaRunClonedFrom = meta.ID
+	if convoyCloneDryRun {
+		fmt.Printf("%s Cloning %s\n", style.Dim.Render("[dry-run]"), meta.ID)
+		return dryRunFormula(f, meta.Formula, targetRig)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	hold, ok, err := formulaPreflight{
+		TownRoot:      townRoot,
+		Rig:           targetRig,
+		Formula:       meta.Formula,
+		F:             f,
+		OverridePause: formulaRunOverridePause,
+	}.check()
+	if !ok {
+		return err
+	}
+	defer hold.release()
+	if err := checkDuplicatePRRun(townRoot, meta.Formula, targetRig); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Cloning %s\n", style.Bold.Render("⧉"), meta.ID)
+	_, err = executeConvoyFormula(f, meta.Formula, targetRig, hold)
+	return err
+}
+
+// cloneTarget returns the PR and rig a clone of meta runs on: the
+// overrides if given, else the original's.
+func cloneTarget(meta *ConvoyMeta, pr int, rig string) (int, string, error) {
+	if meta.Formula == "" {
+		return 0, "", fmt.Errorf("convoy %s was not created by a formula run; nothing to clone", meta.ID)
+	}
+	if pr <= 0 {
+		pr = meta.PR
+	}
+	if rig == "" {
+		rig = meta.Rig
+	}
+	if rig == "" {
+		return 0, "", fmt.Errorf("convoy %s does not record its rig; pass --rig", meta.ID)
+	}
+	return pr, rig, nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import "testing"
+
+func TestCloneTarget(t *testing.T) {
+	meta := &ConvoyMeta{ID: "hq-cv-abc", Formula: "code-review", Rig: "gastown", PR: 12}
+
+	tests := []struct {
+		name    string
+		meta    *ConvoyMeta
+		pr      int
+		rig     string
+		wantPR  int
+		wantRig string
+		wantErr bool
+	}{
+		{"defaults to original", meta, 0, "", 12, "gastown", false},
+		{"new PR", meta, 34, "", 34, "gastown", false},
+		{"new rig", meta, 0, "beads", 12, "beads", false},
+		{"not a formula convoy", &ConvoyMeta{ID: "hq-cv-x", Rig: "gastown"}, 0, "", 0, "", true},
+		{"no rig recorded", &ConvoyMeta{ID: "hq-cv-x", Formula: "code-review"}, 5, "", 0, "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr, rig, err := cloneTarget(tc.meta, tc.pr, tc.rig)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("cloneTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if pr != tc.wantPR || rig != tc.wantRig {
+				t.Errorf("cloneTarget() = %d, %q; want %d, %q", pr, rig, tc.wantPR, tc.wantRig)
+			}
+		})
+	}
+}
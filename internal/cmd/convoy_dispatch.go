@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mayor"
+)
+
+// Convoy dispatch flags
+var (
+	convoyDispatchDryRun  bool
+	convoyDispatchPreempt bool
+)
+
+var convoyDispatchCmd = &cobra.Command{
+	Use:   "dispatch",
+	Short: "Dispatch queued formula legs to free polecats",
+	Long: `Hand free polecat slots to queued formula legs, in priority order.
+
+The queue (see gt queue list) is also serviced by gt formula run,
+gt convoy check, and the daemon, so this is rarely needed by hand.
+Nothing is dispatched while the town is paused or in a maintenance window.
+
+With --preempt, running low priority legs are paused to make room for
+waiting high priority ones.
+
+Examples:
+  gt convoy dispatch
+  gt convoy dispatch --dry-run
+  gt convoy dispatch --preempt`,
+	Args: cobra.NoArgs,
+	RunE: runConvoyDispatch,
+}
+
+func init() {
+	convoyDispatchCmd.Flags().BoolVar(&convoyDispatchDryRun, "dry-run", false, "Show what would be dispatched without doing it")
+	convoyDispatchCmd.Flags().BoolVar(&convoyDispatchPreempt, "preempt", false, "Pause running low priority legs for waiting high priority ones")
+
+	convoyCmd.AddCommand(convoyDispatchCmd)
+}
+
+func runConvoyDispatch(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	townRoot := filepath.Dir(townBeads)
+	if convoyDispatchPreempt {
+		if err := checkPreemptBackend(townRoot); err != nil {
+			return err
+		}
+	}
+	if paused, _, _ := mayor.IsTownPaused(townRoot); paused {
+		fmt.Println("Town is paused; legs stay queued until gt resume --town.")
+		return nil
+	}
+	if err := maintenanceWindowError(townRoot); err != nil {
+		fmt.Printf("%s; legs stay queued until it closes.\n", capitalizeFirst(err.Error()))
+		return nil
+	}
+	dispatched := dispatchLegQueue(townRoot, legDispatchOptions{
+		DryRun:  convoyDispatchDryRun,
+		Preempt: convoyDispatchPreempt,
+	})
+	if len(dispatched) == 0 && !convoyDispatchDryRun {
+		fmt.Println("Nothing dispatched.")
+	}
+	return nil
+}
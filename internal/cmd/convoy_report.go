@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// Convoy report flags
+var (
+	convoyReportFormat string
+	convoyReportOutput string
+)
+
+var convoyReportCmd = &cobra.Command{
+	Use:   "report <convoy-id>",
+	Short: "Export convoy results for CI",
+	Long: `Export convoy results in a machine-readable format.
+
+Each tracked leg becomes a test case. A leg passes when it is closed and
+its findings contain no critical or major issues. Incomplete legs are
+reported as failures so pipelines don't silently pass half-finished reviews.
+
+Findings are counted from the leg's output file using the standard review
+sections (Critical Issues, Major Issues, Minor Issues, Observations).
+
+Formats:
+  junit   JUnit XML (Jenkins, GitLab, GitHub Actions test reporters)
+  json    Structured JSON with per-leg finding counts
+
+Examples:
+  gt convoy report hq-cv-abc --format junit > convoy.xml
+  gt convoy report hq-cv-abc --format json
+  gt convoy report hq-cv-abc -o reports/convoy.xml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyReport,
+}
+
+func init() {
+	convoyReportCmd.Flags().StringVar(&convoyReportFormat, "format", "junit", "Output format: junit or json")
+	convoyReportCmd.Flags().StringVarP(&convoyReportOutput, "output", "o", "", "Write report to file instead of stdout")
+
+	convoyCmd.AddCommand(convoyReportCmd)
+}
+
+// FindingCounts tallies findings by review section.
+type FindingCounts struct {
+	Critical     int `json:"critical"`
+	Major        int `json:"major"`
+	Minor        int `json:"minor"`
+	Observations int `json:"observations"`
+}
+
+// Total returns the total number of findings across all sections.
+func (c FindingCounts) Total() int {
+	return c.Critical + c.Major + c.Minor + c.Observations
+}
+
+// Blocking returns true if any finding should block a merge.
+func (c FindingCounts) Blocking() bool {
+	return c.Critical > 0 || c.Major > 0
+}
+
+// String formats the counts for display.
+func (c FindingCounts) String() string {
+	return fmt.Sprintf("%d critical, %d major, %d minor, %d observations",
+		c.Critical, c.Major, c.Minor, c.Observations)
+}
+
+// countFindings counts top-level list items under the standard review
+// section headings of a leg findings document.
+func countFindings(content string) FindingCounts {
+	var counts FindingCounts
+	var section *int
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			heading := strings.ToLower(strings.TrimSpace(strings.TrimLeft(line, "#")))
+			switch {
+			case strings.HasPrefix(heading, "critical"):
+				section = &counts.Critical
+			case strings.HasPrefix(heading, "major"):
+				section = &counts.Major
+			case strings.HasPrefix(heading, "minor"):
+				section = &counts.Minor
+			case strings.HasPrefix(heading, "observation"):
+				section = &counts.Observations
+			default:
+				section = nil
+			}
+			continue
+		}
+		if section == nil {
+			continue
+		}
+		// Only top-level items count; nested bullets are supporting detail
+		if !strings.HasPrefix(line, "- ") && !strings.HasPrefix(line, "* ") {
+			continue
+		}
+		item := strings.TrimSpace(line[2:])
+		if item == "" || item == "..." || strings.EqualFold(item, "none") {
+			continue
+		}
+		*section++
+	}
+
+	return counts
+}
+
+// convoyReportLeg is a single leg entry in a convoy report.
+type convoyReportLeg struct {
+	ID       string        `json:"id"`
+	Title    string        `json:"title"`
+	Status   string        `json:"status"`
+	Output   string        `json:"output,omitempty"`
+	Findings FindingCounts `json:"findings"`
+	Passed   bool          `json:"passed"`
+	Reason   string        `json:"reason,omitempty"`
+	content  string
+}
+
+// convoyReport is the format-independent report model.
+type convoyReport struct {
+	ID       string            `json:"id"`
+	Title    string            `json:"title"`
+	Status   string            `json:"status"`
+	Formula  string            `json:"formula,omitempty"`
+	ReviewID string            `json:"review_id,omitempty"`
+	Legs     []convoyReportLeg `json:"legs"`
+	Failed   int               `json:"failed"`
+}
+
+// buildConvoyReport converts collected leg outputs into a report.
+func buildConvoyReport(meta *ConvoyMeta, outputs []LegOutput) *convoyReport {
+	report := &convoyReport{
+		ID:       meta.ID,
+		Title:    meta.Title,
+		Status:   meta.Status,
+		Formula:  meta.Formula,
+		ReviewID: meta.ReviewID,
+	}
+
+	for _, out := range outputs {
+		leg := convoyReportLeg{
+			ID:       out.LegID,
+			Title:    out.Title,
+			Status:   out.Status,
+			Output:   out.FilePath,
+			Findings: countFindings(out.Content),
+			content:  out.Content,
+		}
+
+		switch {
+		case leg.Status != "closed":
+			leg.Reason = fmt.Sprintf("leg incomplete (status: %s)", valueOrDefault(leg.Status, "unknown"))
+		case leg.Findings.Blocking():
+			leg.Reason = fmt.Sprintf("blocking findings: %s", leg.Findings)
+		default:
+			leg.Passed = true
+		}
+		if !leg.Passed {
+			report.Failed++
+		}
+
+		report.Legs = append(report.Legs, leg)
+	}
+
+	return report
+}
+
+// JUnit XML schema (the subset understood by common CI test reporters).
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	ID         string          `xml:"id,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitFailure   `xml:"failure,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// writeConvoyJUnit renders the report as JUnit XML.
+func writeConvoyJUnit(w io.Writer, report *convoyReport) error {
+	className := "convoy." + report.ID
+	if report.Formula != "" {
+		className = "convoy." + report.Formula
+	}
+
+	suite := junitTestSuite{
+		Name:     report.Title,
+		ID:       report.ID,
+		Tests:    len(report.Legs),
+		Failures: report.Failed,
+		Properties: []junitProperty{
+			{Name: "convoy", Value: report.ID},
+			{Name: "status", Value: report.Status},
+		},
+	}
+	if report.Formula != "" {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "formula", Value: report.Formula})
+	}
+	if report.ReviewID != "" {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "review_id", Value: report.ReviewID})
+	}
+
+	for _, leg := range report.Legs {
+		tc := junitTestCase{
+			Name:      fmt.Sprintf("%s: %s", leg.ID, leg.Title),
+			ClassName: className,
+			Properties: []junitProperty{
+				{Name: "findings.critical", Value: fmt.Sprint(leg.Findings.Critical)},
+				{Name: "findings.major", Value: fmt.Sprint(leg.Findings.Major)},
+				{Name: "findings.minor", Value: fmt.Sprint(leg.Findings.Minor)},
+				{Name: "findings.observations", Value: fmt.Sprint(leg.Findings.Observations)},
+			},
+			SystemOut: leg.content,
+		}
+		if !leg.Passed {
+			tc.Failure = &junitFailure{
+				Message: leg.Reason,
+				Type:    "convoy-leg",
+				Body:    fmt.Sprintf("Leg %s (%s)\nStatus: %s\nFindings: %s", leg.ID, leg.Title, leg.Status, leg.Findings),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	suites := junitTestSuites{
+		Name:     "gastown",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return fmt.Errorf("encoding junit: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func runConvoyReport(cmd *cobra.Command, args []string) error {
+	convoyID := args[0]
+
+	if convoyReportFormat != "junit" && convoyReportFormat != "json" {
+		return fmt.Errorf("unknown format %q (use: junit or json)", convoyReportFormat)
+	}
+
+	meta, err := getConvoyMeta(convoyID)
+	if err != nil {
+		return fmt.Errorf("getting convoy metadata: %w", err)
+	}
+
+	var f *formula.Formula
+	if meta.FormulaPath != "" {
+		f, _ = formula.ParseFile(meta.FormulaPath, formulaIncludeDirs()...)
+	} else if meta.Formula != "" {
+		if path, err := findFormula(meta.Formula); err == nil {
+			f, _ = formula.ParseFile(path, formulaIncludeDirs()...)
+		}
+	}
+
+	outputs, _, err := collectLegOutputs(meta, f)
+	if err != nil {
+		return fmt.Errorf("collecting leg outputs: %w", err)
+	}
+
+	report := buildConvoyReport(meta, outputs)
+
+	w := io.Writer(os.Stdout)
+	if convoyReportOutput != "" {
+		file, err := os.Create(convoyReportOutput)
+		if err != nil {
+			return fmt.Errorf("creating report file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch convoyReportFormat {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	default:
+		return writeConvoyJUnit(w, report)
+	}
+}
+
+// valueOrDefault returns v, or def if v is empty.
+func valueOrDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
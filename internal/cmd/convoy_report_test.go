@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestCountFindings(t *testing.T) {
+	content := `# Security Review
+
+## Summary
+- Overall the change looks fine
+
+## Critical Issues
+- SQL injection in handler.go:42
+  - Use parameterized queries
+
+## Major Issues
+- Missing auth check on /admin
+- Token logged at info level
+
+## Minor Issues
+- ...
+
+## Observations
+- None
+* Consider rate limiting
+`
+	got := countFindings(content)
+	want := FindingCounts{Critical: 1, Major: 2, Minor: 0, Observations: 1}
+	if got != want {
+		t.Errorf("countFindings() = %+v, want %+v", got, want)
+	}
+	if !got.Blocking() {
+		t.Error("Blocking() = false, want true")
+	}
+	if got.Total() != 4 {
+		t.Errorf("Total() = %d, want 4", got.Total())
+	}
+}
+
+func TestCountFindingsEmpty(t *testing.T) {
+	if got := countFindings(""); got.Total() != 0 {
+		t.Errorf("countFindings(\"\") = %+v, want zero", got)
+	}
+}
+
+func TestBuildConvoyReport(t *testing.T) {
+	meta := &ConvoyMeta{ID: "hq-cv-abc", Title: "code-review", Status: "open", Formula: "code-review"}
+	outputs := []LegOutput{
+		{LegID: "hq-leg-1", Title: "Correctness", Status: "closed", Content: "## Minor Issues\n- nit\n"},
+		{LegID: "hq-leg-2", Title: "Security", Status: "closed", Content: "## Critical Issues\n- bad\n"},
+		{LegID: "hq-leg-3", Title: "Style", Status: "in_progress"},
+	}
+
+	report := buildConvoyReport(meta, outputs)
+	if len(report.Legs) != 3 {
+		t.Fatalf("len(Legs) = %d, want 3", len(report.Legs))
+	}
+	if report.Failed != 2 {
+		t.Errorf("Failed = %d, want 2", report.Failed)
+	}
+	if !report.Legs[0].Passed {
+		t.Error("leg with only minor findings should pass")
+	}
+	if report.Legs[1].Passed {
+		t.Error("leg with critical findings should fail")
+	}
+	if !strings.Contains(report.Legs[2].Reason, "incomplete") {
+		t.Errorf("Reason = %q, want incomplete", report.Legs[2].Reason)
+	}
+}
+
+func TestWriteConvoyJUnit(t *testing.T) {
+	meta := &ConvoyMeta{ID: "hq-cv-abc", Title: "code-review", Status: "closed", Formula: "code-review"}
+	report := buildConvoyReport(meta, []LegOutput{
+		{LegID: "hq-leg-1", Title: "Correctness", Status: "closed"},
+		{LegID: "hq-leg-2", Title: "Security", Status: "open"},
+	})
+
+	var buf bytes.Buffer
+	if err := writeConvoyJUnit(&buf, report); err != nil {
+		t.Fatalf("writeConvoyJUnit() error = %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if suites.Tests != 2 || suites.Failures != 1 {
+		t.Errorf("tests=%d failures=%d, want 2/1", suites.Tests, suites.Failures)
+	}
+	cases := suites.Suites[0].Cases
+	if cases[0].Failure != nil {
+		t.Error("closed leg without findings should not have a failure")
+	}
+	if cases[1].Failure == nil {
+		t.Error("open leg should have a failure")
+	}
+	if cases[0].ClassName != "convoy.code-review" {
+		t.Errorf("ClassName = %q, want convoy.code-review", cases[0].ClassName)
+	}
+}
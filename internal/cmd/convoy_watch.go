@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/logs"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Convoy watch flags
+var (
+	convoyWatchLeg  string
+	convoyWatchTail int
+)
+
+// convoyWatchStatusEvery is how often gt convoy watch checks whether the
+// convoy or followed leg has closed.
+const convoyWatchStatusEvery = 10 * time.Second
+
+var convoyWatchCmd = &cobra.Command{
+	Use:   "watch <convoy-id>",
+	Short: "Stream the output of a convoy's legs",
+	Long: `Stream what a formula convoy's legs are doing, live, without attaching
+to their sessions.
+
+Polecats working a convoy leg copy their session output to
+.runtime/convoys/<convoy-id>/<leg>.log. Watch prints the last lines of
+each leg's output, prefixed with the leg, then follows new output until
+the convoy closes. Legs that start while watching are picked up.
+
+With --follow-leg, only that leg is streamed, without prefixes, until its
+bead closes. The leg is given by its formula leg ID (e.g. security) or its
+bead ID (hq-leg-...).
+
+Output is the raw terminal output of the agent, as in gt logs. Legs run
+on the screen session backend are not copied.
+
+Examples:
+  gt convoy watch hq-cv-abc12
+  gt convoy watch hq-cv-abc12 --follow-leg security
+  gt convoy watch hq-cv-abc12 --follow-leg hq-leg-x7k2m -n 0`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyWatch,
+}
+
+func init() {
+	convoyWatchCmd.Flags().StringVar(&convoyWatchLeg, "follow-leg", "", "Only stream this leg (leg ID or bead ID)")
+	convoyWatchCmd.Flags().IntVarP(&convoyWatchTail, "tail", "n", 20, "Lines of earlier output to show per leg (0 for none)")
+	convoyCmd.AddCommand(convoyWatchCmd)
+}
+
+func runConvoyWatch(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	townRoot := filepath.Dir(townBeads)
+	convoyID := args[0]
+	b := beads.New(townBeads)
+	if _, err := b.Show(convoyID); err != nil {
+		return fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+
+	legs := convoyLegIDs(townRoot, getTrackedIssues(townBeads, convoyID))
+	if len(legs) == 0 {
+		return fmt.Errorf("convoy %s has no formula legs to watch", convoyID)
+	}
+
+	// Stop when the convoy closes, or the followed leg's bead does
+	var filter logs.Filter
+	watched := convoyID
+	if convoyWatchLeg != "" {
+		legID, beadID, err := resolveWatchLeg(legs, convoyWatchLeg)
+		if err != nil {
+			return fmt.Errorf("%w in convoy %s", err, convoyID)
+		}
+		filter.Agents = []string{legID}
+		watched = beadID
+	}
+
+	width := 0
+	for legID := range legs {
+		width = max(width, len(legID))
+	}
+	printLine := func(line logs.Line) {
+		if convoyWatchLeg != "" {
+			fmt.Println(line.Text)
+			return
+		}
+		printLogLine(line, width)
+	}
+
+	sources, err := logs.LegSources(townRoot, convoyID)
+	if err != nil {
+		return fmt.Errorf("listing leg logs: %w", err)
+	}
+	if convoyWatchTail > 0 {
+		lines, err := logs.Read(sources, filter)
+		if err != nil {
+			return fmt.Errorf("reading leg logs: %w", err)
+		}
+		for _, line := range tailPerSource(lines, convoyWatchTail) {
+			printLine(line)
+		}
+	}
+	if len(sources) == 0 {
+		fmt.Printf("%s No leg has written output yet; waiting\n", style.Dim.Render("○"))
+	}
+
+	tailer, err := logs.NewLegTailer(townRoot, convoyID, filter)
+	if err != nil {
+		return err
+	}
+	lastCheck := time.Now()
+	for {
+		time.Sleep(500 * time.Millisecond)
+		lines, err := tailer.Poll()
+		if err != nil {
+			return fmt.Errorf("following leg logs: %w", err)
+		}
+		for _, line := range lines {
+			printLine(line)
+		}
+		if time.Since(lastCheck) < convoyWatchStatusEvery {
+			continue
+		}
+		lastCheck = time.Now()
+		if issue, err := b.Show(watched); err == nil && issue.Status == "closed" {
+			fmt.Printf("%s %s closed\n", style.Bold.Render("✓"), watched)
+			return nil
+		}
+	}
+}
+
+// convoyLegIDs maps the formula leg IDs of a convoy's tracked beads to the
+// bead IDs, from the sling payloads the legs were dispatched with.
+func convoyLegIDs(townRoot string, tracked []trackedIssueInfo) map[string]string {
+	legs := make(map[string]string)
+	for _, t := range tracked {
+		p, err := loadSlingPayload(slingPayloadPath(townRoot, t.ID))
+		if err != nil {
+			continue
+		}
+		if legID := p.Env["GT_LEG"]; legID != "" {
+			legs[legID] = t.ID
+		}
+	}
+	return legs
+}
+
+// resolveWatchLeg finds the leg named by arg, a leg ID or bead ID, and
+// returns both.
+func resolveWatchLeg(legs map[string]string, arg string) (legID, beadID string, err error) {
+	if beadID, ok := legs[arg]; ok {
+		return arg, beadID, nil
+	}
+	for legID, beadID := range legs {
+		if beadID == arg {
+			return legID, beadID, nil
+		}
+	}
+	names := make([]string, 0, len(legs))
+	for legID := range legs {
+		names = append(names, legID)
+	}
+	sort.Strings(names)
+	return "", "", fmt.Errorf("no leg %q (legs: %s)", arg, strings.Join(names, ", "))
+}
+
+// tailPerSource keeps the last n lines of each source, in order.
+func tailPerSource(lines []logs.Line, n int) []logs.Line {
+	seen := make(map[string]int)
+	keep := make([]bool, len(lines))
+	for i := len(lines) - 1; i >= 0; i-- {
+		if seen[lines[i].Source] < n {
+			seen[lines[i].Source]++
+			keep[i] = true
+		}
+	}
+	var out []logs.Line
+	for i, line := range lines {
+		if keep[i] {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/logs"
+)
+
+func TestConvoyLegIDs(t *testing.T) {
+	townRoot := t.TempDir()
+	for bead, leg := range map[string]string{"hq-leg-a": "security", "hq-leg-b": "style"} {
+		p := &slingPayload{Env: map[string]string{"GT_CONVOY": "hq-cv-x", "GT_LEG": leg}}
+		if _, err := saveSlingPayload(townRoot, bead, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tracked := []trackedIssueInfo{{ID: "hq-leg-a"}, {ID: "hq-leg-b"}, {ID: "gt-plain"}}
+
+	legs := convoyLegIDs(townRoot, tracked)
+	if len(legs) != 2 || legs["security"] != "hq-leg-a" || legs["style"] != "hq-leg-b" {
+		t.Fatalf("convoyLegIDs = %v", legs)
+	}
+
+	for _, arg := range []string{"security", "hq-leg-a"} {
+		legID, beadID, err := resolveWatchLeg(legs, arg)
+		if err != nil || legID != "security" || beadID != "hq-leg-a" {
+			t.Errorf("resolveWatchLeg(%q) = %q, %q, %v", arg, legID, beadID, err)
+		}
+	}
+	if _, _, err := resolveWatchLeg(legs, "perf"); err == nil {
+		t.Error("resolveWatchLeg(perf) succeeded; want an error naming the legs")
+	}
+}
+
+func TestConvoyWatchFollowsLegLogs(t *testing.T) {
+	townRoot := t.TempDir()
+	path := logs.LegLogPath(townRoot, "hq-cv-x", "security")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("old 1\nold 2\nold 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := logs.LegSources(townRoot, "hq-cv-x")
+	if err != nil || len(sources) != 1 || sources[0].Name != "security" {
+		t.Fatalf("LegSources = %v, %v", sources, err)
+	}
+	lines, err := logs.Read(sources, logs.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tailPerSource(lines, 2); len(got) != 2 || got[0].Text != "old 2" {
+		t.Errorf("tailPerSource = %v; want the last 2 lines", got)
+	}
+
+	tailer, err := logs.NewLegTailer(townRoot, "hq-cv-x", logs.Filter{Agents: []string{"style"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("security new\n")
+	_ = f.Close()
+	if err := os.WriteFile(logs.LegLogPath(townRoot, "hq-cv-x", "style"), []byte("style first\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tailer.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Source != "style" || got[0].Text != "style first" {
+		t.Errorf("Poll = %v; want only the new style leg's line", got)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/style"
@@ -490,7 +491,7 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 	listCmd := exec.Command("bd", listArgs...)
 	listCmd.Dir = location
-	listOutput, err := listCmd.Output()
+	listOutput, err := cmdtrace.Output(listCmd)
 	if err != nil {
 		// If bd fails (e.g., no beads database), return empty list
 		return nil, nil
@@ -514,7 +515,7 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 	showCmd := exec.Command("bd", showArgs...)
 	showCmd.Dir = location
-	showOutput, err := showCmd.Output()
+	showOutput, err := cmdtrace.Output(showCmd)
 	if err != nil {
 		return nil, fmt.Errorf("showing events: %w", err)
 	}
@@ -573,7 +574,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	listCmd := exec.Command("bd", listArgs...)
-	listOutput, err := listCmd.Output()
+	listOutput, err := cmdtrace.Output(listCmd)
 	if err != nil {
 		return nil, nil
 	}
@@ -594,7 +595,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	showCmd := exec.Command("bd", showArgs...)
-	showOutput, err := showCmd.Output()
+	showOutput, err := cmdtrace.Output(showCmd)
 	if err != nil {
 		return nil, fmt.Errorf("showing events: %w", err)
 	}
@@ -1322,7 +1323,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating digest bead: %w\nOutput: %s", err, string(output))
 	}
@@ -1331,7 +1332,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 
 	// Auto-close the digest (it's an audit record, not work)
 	closeCmd := exec.Command("bd", "close", digestID, "--reason=daily cost digest")
-	_ = closeCmd.Run() // Best effort
+	_ = cmdtrace.Run(closeCmd) // Best effort
 
 	return digestID, nil
 }
@@ -1407,7 +1408,7 @@ func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	}
 
 	listCmd := exec.Command("bd", listArgs...)
-	listOutput, err := listCmd.Output()
+	listOutput, err := cmdtrace.Output(listCmd)
 	if err != nil {
 		fmt.Println(style.Dim.Render("No events found or bd command failed"))
 		return nil
@@ -1430,7 +1431,7 @@ func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	}
 
 	showCmd := exec.Command("bd", showArgs...)
-	showOutput, err := showCmd.Output()
+	showOutput, err := cmdtrace.Output(showCmd)
 	if err != nil {
 		return fmt.Errorf("showing events: %w", err)
 	}
@@ -1475,7 +1476,7 @@ func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	closedMigrated := 0
 	for _, event := range openEvents {
 		closeCmd := exec.Command("bd", "close", event.ID, "--reason=migrated to log-file architecture")
-		if err := closeCmd.Run(); err != nil {
+		if err := cmdtrace.Run(closeCmd); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: could not close %s: %v\n", event.ID, err)
 			continue
 		}
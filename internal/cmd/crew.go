@@ -52,6 +52,7 @@ Commands:
   gt crew list             List workspaces with status
   gt crew at <name>        Attach to session
   gt crew remove <name>    Remove workspace
+  gt crew sync [name]      Re-apply standard .gitignore/settings
   gt crew refresh <name>   Context cycle with handoff mail
   gt crew restart <name>   Kill and restart session fresh`,
 }
@@ -64,6 +65,7 @@ var crewAddCmd = &cobra.Command{
 Each workspace is created at <rig>/crew/<name>/ with:
 - A full git clone of the project repository
 - Mail directory for message delivery
+- Gas Town .gitignore block, shared beads redirect, and PRIME.md
 - CLAUDE.md with crew worker prompting
 - Optional feature branch (crew/<name>)
 
@@ -247,6 +249,29 @@ Examples:
 	RunE: runCrewPristine,
 }
 
+var crewSyncCmd = &cobra.Command{
+	Use:   "sync [<name>...]",
+	Short: "Re-apply standard files to crew workspaces",
+	Long: `Re-apply the standard Gas Town files to crew workspace(s).
+
+For each workspace this:
+- Updates the gt-managed .gitignore block
+- Restores the shared beads redirect and PRIME.md
+- Copies overlay files from .runtime/overlay/
+- Ensures the shared crew runtime settings exist
+- Registers the workspace (state.json) if it was cloned by hand
+
+Safe to run repeatedly. Does not touch git history or uncommitted work;
+use 'gt crew pristine' to pull from the remote.
+
+Examples:
+  gt crew sync                    # Sync all crew in current rig
+  gt crew sync dave               # Sync specific worker
+  gt crew sync --rig greenplace   # Sync all crew in a rig
+  gt crew sync --json             # JSON output`,
+	RunE: runCrewSync,
+}
+
 var crewNextCmd = &cobra.Command{
 	Use:    "next",
 	Short:  "Switch to next crew session in same rig",
@@ -357,6 +382,9 @@ func init() {
 	crewPristineCmd.Flags().StringVar(&crewRig, "rig", "", "Filter by rig name")
 	crewPristineCmd.Flags().BoolVar(&crewJSON, "json", false, "Output as JSON")
 
+	crewSyncCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use")
+	crewSyncCmd.Flags().BoolVar(&crewJSON, "json", false, "Output as JSON")
+
 	crewRestartCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use (filter when using --all)")
 	crewRestartCmd.Flags().BoolVar(&crewAll, "all", false, "Restart all running crew sessions")
 	crewRestartCmd.Flags().BoolVar(&crewDryRun, "dry-run", false, "Show what would be restarted without restarting")
@@ -379,6 +407,7 @@ func init() {
 	crewCmd.AddCommand(crewStatusCmd)
 	crewCmd.AddCommand(crewRenameCmd)
 	crewCmd.AddCommand(crewPristineCmd)
+	crewCmd.AddCommand(crewSyncCmd)
 	crewCmd.AddCommand(crewRestartCmd)
 
 	// Add --session flag to next/prev commands for tmux key binding support
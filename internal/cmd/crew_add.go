@@ -111,22 +111,7 @@ func runCrewAdd(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Branch: %s\n", worker.Branch)
 
 		// Create agent bead for the crew worker
-		prefix := beads.GetPrefixForRig(townRoot, rigName)
-		crewID := beads.CrewBeadIDWithPrefix(prefix, rigName, name)
-		if _, err := bd.Show(crewID); err != nil {
-			// Agent bead doesn't exist, create it
-			fields := &beads.AgentFields{
-				RoleType:   "crew",
-				Rig:        rigName,
-				AgentState: "idle",
-			}
-			desc := fmt.Sprintf("Crew worker %s in %s - human-managed persistent workspace.", name, rigName)
-			if _, err := bd.CreateAgentBead(crewID, desc, fields); err != nil {
-				style.PrintWarning("could not create agent bead for %s: %v", name, err)
-			} else {
-				fmt.Printf("  Agent bead: %s\n", crewID)
-			}
-		}
+		ensureCrewAgentBead(townRoot, rigName, name, bd)
 
 		created = append(created, name)
 		lastWorker = worker
@@ -153,3 +138,24 @@ func runCrewAdd(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// ensureCrewAgentBead creates the agent bead for a crew worker if it does
+// not exist yet. Failures are reported as warnings.
+func ensureCrewAgentBead(townRoot, rigName, name string, bd *beads.Beads) {
+	prefix := beads.GetPrefixForRig(townRoot, rigName)
+	crewID := beads.CrewBeadIDWithPrefix(prefix, rigName, name)
+	if _, err := bd.Show(crewID); err == nil {
+		return
+	}
+	fields := &beads.AgentFields{
+		RoleType:   "crew",
+		Rig:        rigName,
+		AgentState: "idle",
+	}
+	desc := fmt.Sprintf("Crew worker %s in %s - human-managed persistent workspace.", name, rigName)
+	if _, err := bd.CreateAgentBead(crewID, desc, fields); err != nil {
+		style.PrintWarning("could not create agent bead for %s: %v", name, err)
+	} else {
+		fmt.Printf("  Agent bead: %s\n", crewID)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
@@ -129,7 +130,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			deleteArgs := []string{"delete", agentBeadID, "--force"}
 			deleteCmd := exec.Command("bd", deleteArgs...)
 			deleteCmd.Dir = r.Path
-			if output, err := deleteCmd.CombinedOutput(); err != nil {
+			if output, err := cmdtrace.CombinedOutput(deleteCmd); err != nil {
 				// Non-fatal: bead might not exist
 				if !strings.Contains(string(output), "no issue found") &&
 					!strings.Contains(string(output), "not found") {
@@ -144,7 +145,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			unassignArgs := []string{"list", "--assignee=" + agentAddr, "--format=id"}
 			unassignCmd := exec.Command("bd", unassignArgs...)
 			unassignCmd.Dir = r.Path
-			if output, err := unassignCmd.CombinedOutput(); err == nil {
+			if output, err := cmdtrace.CombinedOutput(unassignCmd); err == nil {
 				ids := strings.Fields(strings.TrimSpace(string(output)))
 				for _, id := range ids {
 					if id == "" {
@@ -152,7 +153,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 					}
 					updateCmd := exec.Command("bd", "update", id, "--unassign")
 					updateCmd.Dir = r.Path
-					if _, err := updateCmd.CombinedOutput(); err == nil {
+					if _, err := cmdtrace.CombinedOutput(updateCmd); err == nil {
 						fmt.Printf("Unassigned: %s\n", id)
 					}
 				}
@@ -172,7 +173,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			}
 			closeCmd := exec.Command("bd", closeArgs...)
 			closeCmd.Dir = r.Path
-			if output, err := closeCmd.CombinedOutput(); err != nil {
+			if output, err := cmdtrace.CombinedOutput(closeCmd); err != nil {
 				// Non-fatal: bead might not exist or already be closed
 				if !strings.Contains(string(output), "no issue found") &&
 					!strings.Contains(string(output), "already closed") {
@@ -611,6 +612,7 @@ func runCrewStop(cmd *cobra.Command, args []string) error {
 			agent := fmt.Sprintf("%s/crew/%s", r.Name, name)
 			logger := townlog.NewLogger(townRoot)
 			_ = logger.Log(townlog.EventKill, agent, "gt crew stop")
+			_ = logger.Close()
 		}
 
 		// Log captured output (truncated)
@@ -697,6 +699,7 @@ func runCrewStopAll() error {
 		if townRoot != "" {
 			logger := townlog.NewLogger(townRoot)
 			_ = logger.Log(townlog.EventKill, agentName, "gt crew stop --all")
+			_ = logger.Close()
 		}
 
 		// Log captured output (truncated)
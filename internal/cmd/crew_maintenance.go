@@ -126,3 +126,70 @@ func runCrewPristine(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runCrewSync(cmd *cobra.Command, args []string) error {
+	// Parse rig/name format from the first argument (e.g., "beads/emma")
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		if rig, crewName, ok := parseRigSlashName(arg); ok {
+			if crewRig == "" {
+				crewRig = rig
+			}
+			arg = crewName
+		}
+		names = append(names, arg)
+	}
+
+	crewMgr, r, err := getCrewManager(crewRig)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		workers, err := crewMgr.List()
+		if err != nil {
+			return fmt.Errorf("listing crew workers: %w", err)
+		}
+		for _, w := range workers {
+			names = append(names, w.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		fmt.Println("No crew workspaces found.")
+		return nil
+	}
+
+	var results []*crew.SyncResult
+	for _, name := range names {
+		result, err := crewMgr.Sync(name)
+		if err != nil {
+			if err == crew.ErrCrewNotFound {
+				return fmt.Errorf("crew workspace '%s' not found", name)
+			}
+			return fmt.Errorf("sync %s: %w", name, err)
+		}
+		results = append(results, result)
+	}
+
+	if crewJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	for _, result := range results {
+		fmt.Printf("%s %s/%s\n", style.Bold.Render("→"), r.Name, result.Name)
+		if result.Registered {
+			fmt.Printf("  %s registered workspace\n", style.Dim.Render("✓"))
+		}
+		for _, warning := range result.Warnings {
+			fmt.Printf("  %s %s\n", style.Bold.Render("⚠"), warning)
+		}
+		if len(result.Warnings) == 0 {
+			fmt.Printf("  %s standard files up to date\n", style.Dim.Render("✓"))
+		}
+	}
+
+	return nil
+}
@@ -25,7 +25,18 @@ The daemon is a simple Go process that:
 - Processes lifecycle requests (cycle, restart, shutdown)
 - Restarts sessions when agents request cycling
 
-The daemon is a "dumb scheduler" - all intelligence is in agents.`,
+The daemon is a "dumb scheduler" - all intelligence is in agents.
+
+For supervision under systemd or Kubernetes, set "health" in
+mayor/daemon.json to serve liveness and readiness probes:
+
+  "health": {"listen": "127.0.0.1:9464", "max_queue_depth": 50}
+
+  /healthz  scheduler heartbeat is recent
+  /readyz   also: beads reachable, default agent installed, and hooked
+            (dispatched but unstarted) work within max_queue_depth
+
+Both return JSON with status 200, or 503 when a check fails.`,
 }
 
 var daemonStartCmd = &cobra.Command{
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -29,6 +30,8 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+Inside a town it also serves /healthz and /readyz (see gt daemon).
+
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
@@ -42,12 +45,28 @@ func init() {
 	rootCmd.AddCommand(dashboardCmd)
 }
 
+// withHealthEndpoints serves the town's /healthz and /readyz next to the
+// dashboard. The scheduler check follows the daemon's heartbeat.
+func withHealthEndpoints(townRoot string, dashboard http.Handler) http.Handler {
+	maxQueue := 0
+	if pc := daemon.LoadPatrolConfig(townRoot); pc != nil && pc.Health != nil {
+		maxQueue = pc.Health.MaxQueueDepth
+	}
+	health := daemon.NewHealthChecker(townRoot, maxQueue, daemon.StateLastTick(townRoot)).Handler()
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", health)
+	mux.Handle("/readyz", health)
+	mux.Handle("/", dashboard)
+	return mux
+}
+
 func runDashboard(cmd *cobra.Command, args []string) error {
 	// Check if we're in a workspace - if not, run in setup mode
 	var handler http.Handler
 	var err error
 
-	if _, wsErr := workspace.FindFromCwdOrError(); wsErr != nil {
+	townRoot, wsErr := workspace.FindFromCwdOrError()
+	if wsErr != nil {
 		// No workspace - run in setup mode
 		handler, err = web.NewSetupMux()
 		if err != nil {
@@ -64,6 +83,7 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("creating dashboard handler: %w", err)
 		}
+		handler = withHealthEndpoints(townRoot, handler)
 	}
 
 	// Build the URL
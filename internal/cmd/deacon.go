@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
@@ -997,7 +998,7 @@ func getAgentBeadUpdateTime(townRoot, beadID string) (time.Time, error) {
 	cmd := exec.Command("bd", "show", beadID, "--json")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -1020,7 +1021,7 @@ func getAgentBeadUpdateTime(townRoot, beadID string) (time.Time, error) {
 func sendMail(townRoot, to, subject, body string) {
 	cmd := exec.Command("gt", "mail", "send", to, "-s", subject, "-m", body)
 	cmd.Dir = townRoot
-	_ = cmd.Run() // Best effort
+	_ = cmdtrace.Run(cmd) // Best effort
 }
 
 // updateAgentBeadState updates an agent bead's state.
@@ -1033,7 +1034,7 @@ func updateAgentBeadState(townRoot, agent, state, _ string) { // reason unused b
 	// Use bd agent state command
 	cmd := exec.Command("bd", "agent", "state", beadID, state)
 	cmd.Dir = townRoot
-	_ = cmd.Run() // Best effort
+	_ = cmdtrace.Run(cmd) // Best effort
 }
 
 // runDeaconStaleHooks finds and unhooks stale hooked beads.
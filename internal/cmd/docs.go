@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/docgen"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+// Docs flags
+var (
+	docsOut    string
+	docsFormat string
+	docsManDir string
+)
+
+var docsCmd = &cobra.Command{
+	Use:     "docs",
+	GroupID: GroupDiag,
+	Short:   "Generate man pages and a markdown reference",
+	Long: `Generate offline documentation from this gt binary: a man page and a
+markdown page for every command, plus reference pages for the formula file
+schema, the template context formulas and role templates are rendered
+with, and the town and rig config files.
+
+The references are generated from the types gt itself reads, so they are
+always in step with the binary.
+
+Output layout under --out:
+  man1/       gt.1, gt-formula.1, gt-formula-list.1, ...
+  man5/       gt-formula-schema.5, gt-template-context.5, gt-config-files.5
+  markdown/   README.md index and one page per command and reference
+
+Examples:
+  gt docs                          # man pages and markdown in ./gt-docs
+  gt docs --format markdown --out docs/reference
+  gt docs install                  # install man pages, then 'man gt-formula-schema'`,
+	Args: cobra.NoArgs,
+	RunE: runDocs,
+}
+
+var docsInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install gt man pages for the current user",
+	Long: `Install gt's man pages into a man directory, by default
+$XDG_DATA_HOME/man (~/.local/share/man), which man searches on most
+systems. Re-run after upgrading gt to refresh them.
+
+Examples:
+  gt docs install
+  gt docs install --man-dir /usr/local/share/man`,
+	Args: cobra.NoArgs,
+	RunE: runDocsInstall,
+}
+
+func init() {
+	docsCmd.Flags().StringVarP(&docsOut, "out", "o", "gt-docs", "Directory to write documentation to")
+	docsCmd.Flags().StringVar(&docsFormat, "format", "all", "What to generate: man, markdown, or all")
+	docsInstallCmd.Flags().StringVar(&docsManDir, "man-dir", "", "Man directory to install into (default: $XDG_DATA_HOME/man)")
+
+	docsCmd.AddCommand(docsInstallCmd)
+	rootCmd.AddCommand(docsCmd)
+}
+
+func runDocs(cmd *cobra.Command, args []string) error {
+	var format docgen.Format
+	switch docsFormat {
+	case "man":
+		format = docgen.FormatMan
+	case "markdown", "md":
+		format = docgen.FormatMarkdown
+	case "all":
+		format = docgen.FormatAll
+	default:
+		return fmt.Errorf("unknown format %q (want man, markdown, or all)", docsFormat)
+	}
+
+	written, err := docgen.Generate(rootCmd, docReferences(), docsOut, format, Version)
+	if err != nil {
+		return fmt.Errorf("generating docs: %w", err)
+	}
+	fmt.Printf("%s Wrote %d pages to %s\n", style.Success.Render("✓"), len(written), docsOut)
+	if format&docgen.FormatMarkdown != 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Start at "+filepath.Join(docsOut, "markdown", "README.md")))
+	}
+	return nil
+}
+
+func runDocsInstall(cmd *cobra.Command, args []string) error {
+	manDir := docsManDir
+	if manDir == "" {
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("finding home directory: %w", err)
+			}
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		manDir = filepath.Join(dataHome, "man")
+	}
+
+	written, err := docgen.Generate(rootCmd, docReferences(), manDir, docgen.FormatMan, Version)
+	if err != nil {
+		return fmt.Errorf("installing man pages: %w", err)
+	}
+	fmt.Printf("%s Installed %d man pages in %s\n", style.Success.Render("✓"), len(written), manDir)
+	if !onManPath(manDir) {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("If 'man gt' finds nothing, add %s to MANPATH", manDir)))
+	}
+	return nil
+}
+
+// onManPath reports whether dir is listed in MANPATH. An unset MANPATH
+// means man's default search, which usually covers ~/.local/share/man.
+func onManPath(dir string) bool {
+	manpath := os.Getenv("MANPATH")
+	if manpath == "" {
+		home, _ := os.UserHomeDir()
+		return home != "" && samePath(dir, filepath.Join(home, ".local", "share", "man"))
+	}
+	for _, p := range filepath.SplitList(manpath) {
+		if p != "" && samePath(p, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// docReferences returns the non-command reference pages, generated from
+// the types and contexts gt actually uses.
+func docReferences() []docgen.Reference {
+	return []docgen.Reference{
+		{
+			Name:  "gt-formula-schema",
+			Title: "formula file schema",
+			Intro: `Formulas are TOML files named <name>.formula.toml, resolved from
+.beads/formulas/ in the current directory, the rig, the town, and
+~/.beads/formulas/, falling back to the formulas embedded in gt.
+Run 'gt formula which <name>' to see which copy is used.
+
+The schema key declares the file format (1 if absent). gt reads older
+schemas through adapters and refuses newer ones.`,
+			Sections: []docgen.Section{{
+				Heading: "Keys",
+				Text:    "Which sections apply depends on type: convoy (inputs, prompts, output, legs, synthesis), workflow (steps, vars), expansion (template), or aspect (aspects).",
+				Fields:  docgen.Fields(formula.Formula{}, "toml"),
+			}},
+		},
+		{
+			Name:  "gt-template-context",
+			Title: "values available to formula and role templates",
+			Intro: `Formula prompts, output paths, and leg workdir and branch settings
+are Go text/template strings, e.g. {{.leg.id}} or {{.review_id}}.`,
+			Sections: []docgen.Section{
+				{
+					Heading: "Leg prompts",
+					Text:    "Used for prompts.base, output.leg_pattern, and leg workdir, branch, and expect settings. output and output_path are only set when the formula has an [output] section.",
+					Fields:  docgen.MapFields(sampleLegContext()),
+				},
+				{
+					Heading: "Output directory",
+					Text:    "Used for output.directory.",
+					Fields:  docgen.MapFields(map[string]any{"review_id": "", "formula_name": ""}),
+				},
+				{
+					Heading: "Role templates",
+					Text:    "Role context templates are rendered with these fields, e.g. {{.RigName}}; {{cmd}} is the gt command name.",
+					Fields:  docgen.Fields(templates.RoleData{}, ""),
+				},
+			},
+		},
+		{
+			Name:  "gt-config-files",
+			Title: "town and rig configuration files",
+			Intro: `Gas Town keeps configuration as JSON files in the town and each rig.
+Use 'gt config' and 'gt rig settings' to change them safely.`,
+			Sections: []docgen.Section{
+				configSection("settings/config.json", "Town settings.", config.TownSettings{}),
+				configSection("<rig>/settings/config.json", "Rig settings; override the town settings for one rig.", config.RigSettings{}),
+				configSection("mayor/town.json", "Town identity.", config.TownConfig{}),
+				configSection("mayor/config.json", "Mayor behavior.", config.MayorConfig{}),
+				configSection("mayor/daemon.json", "Daemon patrols.", config.DaemonPatrolConfig{}),
+				configSection("settings/escalation.json", "Escalation routing.", config.EscalationConfig{}),
+				configSection("config/messaging.json", "Mail lists, queues, and announce channels.", config.MessagingConfig{}),
+			},
+		},
+	}
+}
+
+func configSection(file, text string, v any) docgen.Section {
+	return docgen.Section{Heading: file, Text: text, Fields: docgen.Fields(v, "json")}
+}
+
+// sampleLegContext is a leg prompt context with every optional key set, so
+// its shape can be documented.
+func sampleLegContext() map[string]any {
+	changed := []map[string]interface{}{{"path": "", "additions": 0, "deletions": 0}}
+	ctx := legPromptContext("", formulaLeg{}, "", "", 0, "", changed)
+	addLegOutputContext(&formulaData{Output: &formulaOutput{}}, ctx, formulaLeg{}, "")
+	return ctx
+}
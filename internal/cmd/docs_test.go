@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/docgen"
+)
+
+func TestDocReferences(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := docgen.Generate(rootCmd, docReferences(), dir, docgen.FormatMarkdown, Version); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	ctx, err := os.ReadFile(filepath.Join(dir, "markdown", "gt-template-context.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"`leg.id`", "`output_path`", "`changed_files[].path`", "`RigName`"} {
+		if !strings.Contains(string(ctx), key) {
+			t.Errorf("template context reference missing %s", key)
+		}
+	}
+
+	cfg, err := os.ReadFile(filepath.Join(dir, "markdown", "gt-config-files.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(cfg), "`locale`") {
+		t.Error("config reference missing town settings key locale")
+	}
+}
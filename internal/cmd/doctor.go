@@ -4,15 +4,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/optree"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
 	doctorFix             bool
+	doctorForce           bool
 	doctorVerbose         bool
 	doctorRig             string
 	doctorRestartSessions bool
@@ -33,9 +39,11 @@ and other problems that could affect workspace operation.
 Workspace checks:
   - town-config-exists       Check mayor/town.json exists
   - town-config-valid        Check mayor/town.json is valid
+  - town-marker              Check the town root has .gastown-town.json (fixable)
   - rigs-registry-exists     Check mayor/rigs.json exists (fixable)
   - rigs-registry-valid      Check registered rigs exist (fixable)
   - mayor-exists             Check mayor/ directory structure
+  - config-schema            Detect config schema drift after upgrades (fixable)
 
 Town root protection:
   - town-git                 Verify town root is under version control
@@ -47,6 +55,7 @@ Infrastructure checks:
   - daemon                   Check if daemon is running (fixable)
   - repo-fingerprint         Check database has valid repo fingerprint (fixable)
   - boot-health              Check Boot watchdog health (vet mode)
+  - session-backend          Check tmux version, server, socket, and orphans (fixable)
 
 Cleanup checks (fixable):
   - orphan-sessions          Detect orphaned tmux sessions
@@ -90,16 +99,26 @@ Migration readiness checks (--migrate):
   - migration-readiness      Overall migration readiness status
   - unmigrated-rigs          Detect rigs still using SQLite backend
 
-Use --fix to attempt automatic fixes for issues that support it.
+Use --fix to attempt automatic fixes for issues that support it. Fixes are
+applied in dependency order (e.g. rigs-registry-exists before rigs-registry-valid),
+and dependent checks are re-fixed once their dependencies are repaired.
+Fixes that delete directories (legacy-gastown, beads-redirect,
+beads-sync-worktree) are protected operations: you are asked to type the
+check name unless --force is given.
 Use --rig to check a specific rig instead of the entire workspace.
 Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
 Use --migrate to check migration readiness (SQLite to Dolt).
-Use --json with --migrate for machine-parseable output.`,
+Use --json with --migrate for machine-parseable output.
+
+Each run is given a health score (0-100) and recorded; the summary notes
+the change since the previous run and any newly appearing issues. Use
+gt doctor trend to see the score over recent runs.`,
 	RunE: runDoctor,
 }
 
 func init() {
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix issues")
+	doctorCmd.Flags().BoolVar(&doctorForce, "force", false, "Run protected (destructive) fixes without confirmation")
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
@@ -124,6 +143,9 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		RigName:         doctorRig,
 		Verbose:         doctorVerbose,
 		RestartSessions: doctorRestartSessions,
+		ConfirmFix: func(checkName string) bool {
+			return confirmProtected(townRoot, "doctor.fix", checkName, checkName, doctorForce) == nil
+		},
 	}
 
 	// Handle --migrate mode (focused migration readiness check)
@@ -148,10 +170,15 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewDaemonCheck())
 	d.Register(doctor.NewRepoFingerprintCheck())
 	d.Register(doctor.NewBootHealthCheck())
+	d.Register(doctor.NewSessionBackendCheck())
 	d.Register(doctor.NewBeadsDatabaseCheck())
 	d.Register(doctor.NewCustomTypesCheck())
 	d.Register(doctor.NewRoleLabelCheck())
 	d.Register(doctor.NewFormulaCheck())
+	d.Register(doctor.NewFormulaRequiresCheck())
+	d.Register(doctor.NewFormulaParityCheck())
+	d.Register(doctor.NewFormulaShellCheck())
+	d.Register(doctor.NewFormulaOutputCheck())
 	d.Register(doctor.NewPrefixConflictCheck())
 	d.Register(doctor.NewRigNameMismatchCheck())
 	d.Register(doctor.NewPrefixMismatchCheck())
@@ -172,6 +199,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewLinkedPaneCheck())
 	d.Register(doctor.NewThemeCheck())
 	d.Register(doctor.NewCrashReportCheck())
+	d.Register(doctor.NewDiskUsageCheck())
 	d.Register(doctor.NewEnvVarsCheck())
 
 	// Patrol system checks
@@ -189,8 +217,10 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Config architecture checks
 	d.Register(doctor.NewSettingsCheck())
+	d.Register(doctor.NewConfigSchemaCheck())
 	d.Register(doctor.NewSessionHookCheck())
 	d.Register(doctor.NewRuntimeGitignoreCheck())
+	d.Register(doctor.NewTrackedOutputsCheck())
 	d.Register(doctor.NewLegacyGastownCheck())
 	d.Register(doctor.NewClaudeSettingsCheck())
 
@@ -229,6 +259,15 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// With --explain, list the fixes and the commands they run before fixing
+	if explainFlag {
+		plan := optree.New("gt doctor --fix")
+		doctor.ExplainPlan(plan, d.PlanFix(ctx))
+		if !confirmExplained(plan) {
+			return nil
+		}
+	}
+
 	// Run checks with streaming output
 	fmt.Println() // Initial blank line
 	var report *doctor.Report
@@ -238,9 +277,42 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
 	}
 
+	// Record applied fixes in each affected rig's audit log
+	if doctorFix {
+		for _, result := range report.Checks {
+			if !strings.HasSuffix(result.Message, "(fixed)") {
+				continue
+			}
+			entry := witness.AuditEntry{
+				Action:  witness.ActionDoctorFix,
+				Subject: result.Name,
+				Details: map[string]string{"message": result.Message},
+			}
+			if doctorRig != "" {
+				recordAudit(townRoot, doctorRig, entry)
+			} else {
+				recordAuditAllRigs(townRoot, entry)
+			}
+		}
+	}
+
 	// Print summary (checks were already printed during streaming)
 	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
 
+	// Score the run against the previous one and record it for gt doctor trend
+	entry := doctor.NewHistoryEntry(report, doctorRig, doctorFix)
+	var prev *doctor.HistoryEntry
+	if runs, err := doctorRuns(townRoot, doctorRig); err == nil && len(runs) > 0 {
+		prev = &runs[len(runs)-1]
+	}
+	fmt.Println()
+	printDoctorScore(entry, prev)
+	if err := doctor.AppendHistory(townRoot, entry); err != nil {
+		style.PrintWarning("could not record doctor history: %v", err)
+	}
+
+	fireDoctorWarningHooks(townRoot, report)
+
 	// Exit with error code if there are errors
 	if report.HasErrors() {
 		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
@@ -318,3 +390,31 @@ func runMigrationCheck(ctx *doctor.CheckContext) error {
 	}
 	return nil
 }
+
+// fireDoctorWarningHooks runs the town's on_doctor_warning hooks with the
+// checks that warned or failed, if any did.
+func fireDoctorWarningHooks(townRoot string, report *doctor.Report) {
+	type finding struct {
+		Name    string   `json:"name"`
+		Status  string   `json:"status"`
+		Message string   `json:"message"`
+		Details []string `json:"details,omitempty"`
+		FixHint string   `json:"fix_hint,omitempty"`
+	}
+	var findings []finding
+	for _, r := range report.Checks {
+		if r.Status == doctor.StatusWarning || r.Status == doctor.StatusError {
+			findings = append(findings, finding{Name: r.Name, Status: strings.ToLower(r.Status.String()), Message: r.Message, Details: r.Details, FixHint: r.FixHint})
+		}
+	}
+	if len(findings) == 0 {
+		return
+	}
+	fireLifecycleHook(townRoot, config.HookDoctorWarning, map[string]interface{}{
+		"rig":      doctorRig,
+		"fix":      doctorFix,
+		"warnings": report.Summary.Warnings,
+		"errors":   report.Summary.Errors,
+		"checks":   findings,
+	})
+}
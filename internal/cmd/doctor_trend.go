@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Doctor trend command flags
+var (
+	doctorTrendLast int
+	doctorTrendRig  string
+	doctorTrendJSON bool
+)
+
+var doctorTrendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Show health scores of recent doctor runs",
+	Long: `Show whether the town has been getting healthier over recent doctor runs.
+
+Every gt doctor run is scored from 0 to 100 (passing checks count fully,
+warnings half, errors nothing) and recorded in .runtime/doctor/history.jsonl.
+This lists the last N runs with their score, the change from the run
+before, and the issues that first appeared in each run.
+
+Runs with --rig are tracked separately from town-wide runs.
+
+Examples:
+  gt doctor trend                # Last 10 town-wide runs
+  gt doctor trend --last 30
+  gt doctor trend --rig gastown
+  gt doctor trend --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorTrend,
+}
+
+func init() {
+	doctorTrendCmd.Flags().IntVarP(&doctorTrendLast, "last", "n", 10, "Number of runs to show")
+	doctorTrendCmd.Flags().StringVar(&doctorTrendRig, "rig", "", "Show runs of gt doctor --rig <name>")
+	doctorTrendCmd.Flags().BoolVar(&doctorTrendJSON, "json", false, "Output as JSON")
+	doctorCmd.AddCommand(doctorTrendCmd)
+}
+
+// doctorTrendRun is one run in the trend view.
+type doctorTrendRun struct {
+	doctor.HistoryEntry
+	Delta     int                   `json:"delta"`
+	NewIssues []doctor.HistoryIssue `json:"new_issues,omitempty"`
+}
+
+// doctorRuns returns the recorded runs for a rig ("" for town-wide runs).
+func doctorRuns(townRoot, rig string) ([]doctor.HistoryEntry, error) {
+	entries, err := doctor.ReadHistory(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var runs []doctor.HistoryEntry
+	for _, e := range entries {
+		if e.Rig == rig {
+			runs = append(runs, e)
+		}
+	}
+	return runs, nil
+}
+
+// doctorTrend pairs each of the last n runs with the run before it.
+func doctorTrend(runs []doctor.HistoryEntry, n int) []doctorTrendRun {
+	start := 0
+	if n > 0 && len(runs) > n {
+		start = len(runs) - n
+	}
+	trend := make([]doctorTrendRun, 0, len(runs)-start)
+	for i := start; i < len(runs); i++ {
+		run := doctorTrendRun{HistoryEntry: runs[i]}
+		if i > 0 {
+			run.Delta = runs[i].Score - runs[i-1].Score
+			run.NewIssues = runs[i].NewIssues(runs[i-1])
+		}
+		trend = append(trend, run)
+	}
+	return trend
+}
+
+// formatScoreDelta renders a score change, e.g. "+5".
+func formatScoreDelta(delta int) string {
+	switch {
+	case delta > 0:
+		return style.Success.Render(fmt.Sprintf("+%d", delta))
+	case delta < 0:
+		return style.Error.Render(fmt.Sprintf("%d", delta))
+	default:
+		return style.Dim.Render("=")
+	}
+}
+
+// printDoctorScore prints a run's score and what changed since prev.
+func printDoctorScore(entry doctor.HistoryEntry, prev *doctor.HistoryEntry) {
+	line := i18n.Sprintf("Health score: %s", style.Bold.Render(fmt.Sprintf("%d/100", entry.Score)))
+	if prev == nil {
+		fmt.Println(line)
+		return
+	}
+	fmt.Println(i18n.Sprintf("%s  %s since last run", line, formatScoreDelta(entry.Score-prev.Score)))
+	for _, issue := range entry.NewIssues(*prev) {
+		icon := ui.RenderWarnIcon()
+		if issue.Status == doctor.StatusError.String() {
+			icon = ui.RenderFailIcon()
+		}
+		fmt.Println(i18n.Sprintf("  %s new: %s: %s", icon, issue.Check, issue.Message))
+	}
+}
+
+func runDoctorTrend(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	runs, err := doctorRuns(townRoot, doctorTrendRig)
+	if err != nil {
+		return err
+	}
+	trend := doctorTrend(runs, doctorTrendLast)
+
+	if doctorTrendJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(trend)
+	}
+
+	if len(trend) == 0 {
+		fmt.Println(i18n.Sprintf("No doctor runs recorded yet. Run gt doctor to start tracking."))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "WHEN", Width: 17},
+		style.Column{Name: "SCORE", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "CHANGE", Width: 6},
+		style.Column{Name: "OK", Width: 4, Align: style.AlignRight},
+		style.Column{Name: "WARN", Width: 4, Align: style.AlignRight},
+		style.Column{Name: "ERR", Width: 4, Align: style.AlignRight},
+		style.Column{Name: "NEW ISSUES", Width: 40},
+	)
+	for i, run := range trend {
+		change := ""
+		if i > 0 || len(runs) > len(trend) {
+			change = formatScoreDelta(run.Delta)
+		}
+		when := run.Time.Local().Format("2006-01-02 15:04")
+		if run.Fix {
+			when += "*"
+		}
+		var newIssues string
+		for j, issue := range run.NewIssues {
+			if j > 0 {
+				newIssues += ", "
+			}
+			newIssues += issue.Check
+		}
+		table.AddRow(when, fmt.Sprintf("%d", run.Score), change,
+			fmt.Sprintf("%d", run.OK), fmt.Sprintf("%d", run.Warnings), fmt.Sprintf("%d", run.Errors), newIssues)
+	}
+	fmt.Print(table.Render())
+
+	first, last := trend[0], trend[len(trend)-1]
+	fmt.Println()
+	switch {
+	case last.Score > first.Score:
+		fmt.Println(i18n.Sprintf("%s Improving: %d → %d over %d runs", style.Success.Render("▲"), first.Score, last.Score, len(trend)))
+	case last.Score < first.Score:
+		fmt.Println(i18n.Sprintf("%s Declining: %d → %d over %d runs", style.Error.Render("▼"), first.Score, last.Score, len(trend)))
+	default:
+		fmt.Println(i18n.Sprintf("%s Steady at %d over %d runs", style.Dim.Render("="), last.Score, len(trend)))
+	}
+	if last.Warnings > first.Warnings {
+		fmt.Println(i18n.Sprintf("  %s warnings accumulating: %d → %d", ui.RenderWarnIcon(), first.Warnings, last.Warnings))
+	}
+	for _, run := range trend {
+		if run.Fix {
+			fmt.Println(style.Dim.Render("  * run with --fix"))
+			break
+		}
+	}
+	return nil
+}
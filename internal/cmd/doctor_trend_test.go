@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doctor"
+)
+
+func TestDoctorTrend(t *testing.T) {
+	runs := []doctor.HistoryEntry{
+		{Score: 80},
+		{Score: 70, Issues: []doctor.HistoryIssue{{Check: "daemon", Status: "Warning"}}},
+		{Score: 90},
+	}
+
+	trend := doctorTrend(runs, 2)
+	if len(trend) != 2 {
+		t.Fatalf("got %d runs, want 2", len(trend))
+	}
+	if trend[0].Delta != -10 || len(trend[0].NewIssues) != 1 || trend[0].NewIssues[0].Check != "daemon" {
+		t.Errorf("trend[0] = %+v, want delta -10 and new issue daemon", trend[0])
+	}
+	if trend[1].Delta != 20 || len(trend[1].NewIssues) != 0 {
+		t.Errorf("trend[1] = %+v, want delta +20 and no new issues", trend[1])
+	}
+
+	if all := doctorTrend(runs, 0); len(all) != 3 || all[0].Delta != 0 {
+		t.Errorf("doctorTrend(runs, 0) = %+v, want all runs", all)
+	}
+}
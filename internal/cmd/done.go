@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
@@ -235,6 +236,24 @@ func runDone(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Convoy legs may declare an output contract. A leg whose outputs
+	// violate it is marked failed so synthesis doesn't trust empty results.
+	var legViolations []string
+	if exitType == ExitCompleted && issueID != "" {
+		legViolations = checkLegContract(townRoot, issueID)
+		if len(legViolations) > 0 {
+			style.PrintWarning("leg output contract violated:")
+			for _, v := range legViolations {
+				fmt.Printf("  - %s\n", v)
+			}
+			if err := markLegFailed(cwd, issueID, legViolations); err != nil {
+				style.PrintWarning("could not mark leg %s failed: %v", issueID, err)
+			} else {
+				fmt.Printf("%s Leg %s marked failed\n", style.Bold.Render("✗"), issueID)
+			}
+		}
+	}
+
 	// Get configured default branch for this rig
 	defaultBranch := "main" // fallback
 	if rigCfg, err := rig.LoadRigConfig(filepath.Join(townRoot, rigName)); err == nil && rigCfg.DefaultBranch != "" {
@@ -508,6 +527,9 @@ notifyWitness:
 	if doneGate != "" {
 		bodyLines = append(bodyLines, fmt.Sprintf("Gate: %s", doneGate))
 	}
+	if len(legViolations) > 0 {
+		bodyLines = append(bodyLines, fmt.Sprintf("Leg-Failed: %s", strings.Join(legViolations, "; ")))
+	}
 	bodyLines = append(bodyLines, fmt.Sprintf("Branch: %s", branch))
 
 	doneNotification := &mail.Message{
@@ -868,6 +890,7 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 	if townRoot != "" {
 		logger := townlog.NewLogger(townRoot)
 		_ = logger.Log(townlog.EventKill, agentID, "self-clean: done means gone")
+		_ = logger.Close()
 	}
 
 	// Log to events (JSON audit log with structured payload)
@@ -880,6 +903,13 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 	// while excluding our own PID to avoid killing ourselves before cleanup completes.
 	// The tmux kill-session at the end will terminate us along with the session.
 	t := tmux.NewTmux()
+	if backend := session.OpenBackend(townRoot, t); backend.Type() != session.BackendTmux {
+		// Other backends end the session's whole process group, us included
+		if err := backend.KillSession(sessionName); err != nil {
+			return fmt.Errorf("killing session %s: %w", sessionName, err)
+		}
+		return nil
+	}
 	myPID := strconv.Itoa(os.Getpid())
 	if err := t.KillSessionWithProcessesExcluding(sessionName, []string{myPID}); err != nil {
 		return fmt.Errorf("killing session %s: %w", sessionName, err)
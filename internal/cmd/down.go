@@ -40,6 +40,9 @@ Shutdown levels (progressively more aggressive):
   gt down --all              Also stop bd daemons/activity
   gt down --nuke             Also kill the tmux server (DESTRUCTIVE)
 
+--nuke is a protected operation: it asks you to type "nuke" unless --force
+(or GT_NUKE_ACKNOWLEDGED=1) is given. See "protected" in settings/config.json.
+
 Infrastructure agents stopped:
   • Refineries - Per-rig work processors
   • Witnesses  - Per-rig polecat managers
@@ -69,7 +72,7 @@ var (
 
 func init() {
 	downCmd.Flags().BoolVarP(&downQuiet, "quiet", "q", false, "Only show errors")
-	downCmd.Flags().BoolVarP(&downForce, "force", "f", false, "Force kill without graceful shutdown")
+	downCmd.Flags().BoolVarP(&downForce, "force", "f", false, "Force kill without graceful shutdown (also skips --nuke confirmation)")
 	downCmd.Flags().BoolVarP(&downPolecats, "polecats", "p", false, "Also stop all polecat sessions")
 	downCmd.Flags().BoolVarP(&downAll, "all", "a", false, "Stop bd daemons/activity and verify shutdown")
 	downCmd.Flags().BoolVar(&downNuke, "nuke", false, "Kill entire tmux server (DESTRUCTIVE - kills non-GT sessions!)")
@@ -88,6 +91,15 @@ func runDown(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("tmux not available (is tmux installed and on PATH?)")
 	}
 
+	// --nuke kills ALL tmux sessions, not just Gas Town (vim sessions,
+	// running builds, SSH connections). Confirm before stopping anything.
+	if downNuke && !downDryRun {
+		force := downForce || os.Getenv("GT_NUKE_ACKNOWLEDGED") != ""
+		if err := confirmProtected(townRoot, "down.nuke", "", "nuke", force); err != nil {
+			return err
+		}
+	}
+
 	// Phase 0: Acquire shutdown lock (skip for dry-run)
 	if !downDryRun {
 		lock, err := acquireShutdownLock(townRoot)
@@ -239,19 +251,10 @@ func runDown(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Phase 6: Nuke tmux server (--nuke only, DESTRUCTIVE)
+	// Phase 6: Nuke tmux server (--nuke only, DESTRUCTIVE; confirmed above)
 	if downNuke {
 		if downDryRun {
 			printDownStatus("Tmux server", true, "would kill (DESTRUCTIVE)")
-		} else if os.Getenv("GT_NUKE_ACKNOWLEDGED") == "" {
-			// Require explicit acknowledgement for destructive operation
-			fmt.Println()
-			fmt.Printf("%s The --nuke flag kills ALL tmux sessions, not just Gas Town.\n",
-				style.Bold.Render("⚠ BLOCKED:"))
-			fmt.Printf("This includes vim sessions, running builds, SSH connections, etc.\n")
-			fmt.Println()
-			fmt.Printf("To proceed, run with: %s\n", style.Bold.Render("GT_NUKE_ACKNOWLEDGED=1 gt down --nuke"))
-			allOK = false
 		} else {
 			if err := t.KillServer(); err != nil {
 				printDownStatus("Tmux server", false, err.Error())
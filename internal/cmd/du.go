@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/du"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Du flags
+var duJSON bool
+
+var duCmd = &cobra.Command{
+	Use:     "du [rig...]",
+	GroupID: GroupDiag,
+	Short:   "Show disk usage by subsystem",
+	Long: `Show the disk space the town and each rig use, by subsystem:
+
+  beads      .beads/ databases
+  formulas   .beads/formulas/ overrides
+  repo       The rig's shared repository (.repo.git)
+  crew       Crew clones
+  polecats   Polecat worktrees and the worktree pool
+  reviews    Review outputs (.reviews/)
+  runtime    .runtime/ state and caches, and logs/
+  other      Everything else (mayor and refinery clones, agent homes)
+
+With rigs given, only those rigs are measured. Limits per category are set
+in the town's settings/config.json; gt doctor warns when one is exceeded
+(in the town or any rig):
+
+  "disk_usage": {"limits_mb": {"crew": 20000, "runtime": 2000}}
+
+Examples:
+  gt du
+  gt du gastown
+  gt du --json`,
+	RunE: runDu,
+}
+
+func init() {
+	duCmd.Flags().BoolVar(&duJSON, "json", false, "Output in JSON format")
+	rootCmd.AddCommand(duCmd)
+}
+
+func runDu(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+
+	var usages []du.Usage
+	if len(args) == 0 {
+		var rigs []string
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+		usages = du.Measure(townRoot, rigs)
+	} else {
+		for _, name := range args {
+			if _, ok := rigsConfig.Rigs[name]; !ok {
+				return fmt.Errorf("rig %q not found", name)
+			}
+			usages = append(usages, du.MeasureRig(townRoot, name))
+		}
+	}
+
+	var limits *config.DiskUsageConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		limits = settings.DiskUsage
+	}
+	over := du.Exceeded(usages, limits)
+
+	if duJSON {
+		out := struct {
+			Usage    []du.Usage  `json:"usage"`
+			Exceeded []du.Excess `json:"exceeded"`
+		}{usages, over}
+		if out.Exceeded == nil {
+			out.Exceeded = []du.Excess{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	// Only show categories something uses.
+	var categories []string
+	for _, category := range config.DiskUsageCategories {
+		for _, u := range usages {
+			if u.Bytes[category] > 0 {
+				categories = append(categories, category)
+				break
+			}
+		}
+	}
+	isOver := make(map[string]bool, len(over))
+	for _, e := range over {
+		isOver[e.Scope+"/"+e.Category] = true
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "SCOPE\t%s\tTOTAL\n", strings.ToUpper(strings.Join(categories, "\t")))
+	var total int64
+	for _, u := range usages {
+		cells := []string{u.Scope}
+		for _, category := range categories {
+			cell := formatBytes(u.Bytes[category])
+			if isOver[u.Scope+"/"+category] {
+				cell += " !"
+			}
+			cells = append(cells, cell)
+		}
+		cells = append(cells, formatBytes(u.Total()))
+		total += u.Total()
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	_ = w.Flush()
+	fmt.Printf("\nTotal: %s\n", style.Bold.Render(formatBytes(total)))
+
+	for _, e := range over {
+		fmt.Printf("%s %s %s: %s exceeds limit of %s\n", style.Warning.Render("⚠"),
+			e.Scope, e.Category, formatBytes(e.Bytes), formatBytes(e.Limit))
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/version"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var envJSON bool
+
+var envCmd = &cobra.Command{
+	Use:     "env",
+	GroupID: GroupDiag,
+	Short:   "Print the effective runtime environment",
+	Long: `Print what gt resolves from the current directory and environment:
+the town root, the current rig, formula directories in search order, the
+runtime directory, the config files in use, the versions of the tools gt
+depends on, and the environment variables that override gt's behaviour.
+
+Secrets in environment variables are redacted, so the output can be
+pasted into a support ticket.
+
+Examples:
+  gt env
+  gt env --json`,
+	Args: cobra.NoArgs,
+	RunE: runEnv,
+}
+
+func init() {
+	envCmd.Flags().BoolVar(&envJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(envCmd)
+}
+
+// envOverridePrefixes are the environment variable prefixes gt and bd read
+// settings from.
+var envOverridePrefixes = []string{"GT_", "BD_", "BEADS_", "GASTOWN_"}
+
+// envTools are the tools gt shells out to, with the arguments that make
+// them print their version.
+var envTools = []struct {
+	Name string
+	Args []string
+}{
+	{"git", []string{"--version"}},
+	{"tmux", []string{"-V"}},
+	{"dolt", []string{"version"}},
+}
+
+// envPath is a path gt resolved, and whether it exists.
+type envPath struct {
+	Label  string `json:"label"`
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+// envTool is a dependent tool's location and reported version.
+type envTool struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// envVar is an environment variable that overrides gt's behaviour.
+type envVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// envReport is the output of gt env.
+type envReport struct {
+	TownRoot    string    `json:"town_root,omitempty"`
+	Rig         string    `json:"rig,omitempty"`
+	RigPath     string    `json:"rig_path,omitempty"`
+	Cwd         string    `json:"cwd"`
+	FormulaDirs []envPath `json:"formula_dirs"`
+	RuntimeDir  *envPath  `json:"runtime_dir,omitempty"`
+	ConfigFiles []envPath `json:"config_files"`
+	Tools       []envTool `json:"tools"`
+	Overrides   []envVar  `json:"overrides"`
+	Platform    string    `json:"platform"`
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	report := collectEnvReport()
+	if envJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printEnvReport(report)
+	return nil
+}
+
+// collectEnvReport resolves the environment from the current directory.
+func collectEnvReport() *envReport {
+	r := &envReport{
+		FormulaDirs: []envPath{},
+		ConfigFiles: []envPath{},
+		Overrides:   []envVar{},
+		Platform:    runtime.GOOS + "/" + runtime.GOARCH + ", " + runtime.Version(),
+	}
+	r.Cwd, _ = os.Getwd()
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		r.TownRoot = townRoot
+		if rigName, err := inferRigFromCwd(townRoot); err == nil {
+			rigPath := filepath.Join(townRoot, rigName)
+			if _, err := os.Stat(filepath.Join(rigPath, "config.json")); err == nil {
+				r.Rig, r.RigPath = rigName, rigPath
+			}
+		}
+	}
+
+	for _, sp := range formulaSearchPaths() {
+		r.FormulaDirs = append(r.FormulaDirs, newEnvPath(sp.Label, sp.Dir))
+	}
+
+	if r.TownRoot != "" {
+		runtimeDir := newEnvPath("town", constants.TownRuntimePath(r.TownRoot))
+		r.RuntimeDir = &runtimeDir
+		r.ConfigFiles = append(r.ConfigFiles,
+			newEnvPath("town marker", filepath.Join(r.TownRoot, workspace.TownMarker)),
+			newEnvPath("town config", filepath.Join(r.TownRoot, workspace.PrimaryMarker)),
+			newEnvPath("rigs registry", filepath.Join(r.TownRoot, "mayor", "rigs.json")),
+			newEnvPath("town settings", config.TownSettingsPath(r.TownRoot)),
+			newEnvPath("agent registry", config.DefaultAgentRegistryPath(r.TownRoot)),
+		)
+	}
+	if r.RigPath != "" {
+		r.ConfigFiles = append(r.ConfigFiles,
+			newEnvPath("rig config", filepath.Join(r.RigPath, "config.json")),
+			newEnvPath("rig settings", config.RigSettingsPath(r.RigPath)),
+			newEnvPath("rig agent registry", config.DefaultRigAgentRegistryPath(r.RigPath)),
+		)
+	}
+
+	r.Tools = collectEnvTools()
+	r.Overrides = envOverrides(os.Environ())
+	return r
+}
+
+// newEnvPath describes path, noting whether it exists.
+func newEnvPath(label, path string) envPath {
+	_, err := os.Stat(path)
+	return envPath{Label: label, Path: path, Exists: err == nil}
+}
+
+// collectEnvTools reports gt itself and probes bd and envTools for their
+// versions, in parallel. Tools that aren't installed have no path.
+func collectEnvTools() []envTool {
+	gt := envTool{Name: "gt", Version: Version + " (" + Build + ")"}
+	if commit := resolveCommitHash(); commit != "" {
+		gt.Version += " " + version.ShortCommit(commit)
+	}
+	if exe, err := os.Executable(); err == nil {
+		gt.Path = exe
+	}
+
+	tools := make([]envTool, 2+len(envTools))
+	tools[0] = gt
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		tools[1] = envTool{Name: "bd"}
+		if path, err := exec.LookPath("bd"); err == nil {
+			tools[1].Path = path
+			tools[1].Version, _ = getBeadsVersion()
+		}
+	}()
+	for i, t := range envTools {
+		wg.Add(1)
+		go func(i int, name string, args []string) {
+			defer wg.Done()
+			tools[i] = envTool{Name: name}
+			path, err := exec.LookPath(name)
+			if err != nil {
+				return
+			}
+			tools[i].Path = path
+			ctx, cancel := context.WithTimeout(context.Background(), fingerprintProbeTimeout)
+			defer cancel()
+			out, err := exec.CommandContext(ctx, path, args...).Output() //nolint:gosec // G204: fixed tool names and arguments
+			if err == nil {
+				line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+				tools[i].Version = strings.TrimSpace(line)
+			}
+		}(i+2, t.Name, t.Args)
+	}
+	wg.Wait()
+	return tools
+}
+
+// envOverrides returns the variables in environ that override gt or bd
+// settings, sorted by name, with secrets redacted.
+func envOverrides(environ []string) []envVar {
+	vars := []envVar{}
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		for _, prefix := range envOverridePrefixes {
+			if strings.HasPrefix(name, prefix) {
+				vars = append(vars, envVar{Name: name, Value: cmdtrace.RedactEnv(name, value)})
+				break
+			}
+		}
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+func printEnvReport(r *envReport) {
+	missing := style.Dim.Render("(missing)")
+	printPath := func(p envPath) {
+		suffix := ""
+		if !p.Exists {
+			suffix = " " + missing
+		}
+		fmt.Printf("  %-20s %s%s\n", p.Label, p.Path, suffix)
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Workspace"))
+	if r.TownRoot == "" {
+		fmt.Printf("  %-20s %s\n", "town root", style.Dim.Render("(not in a Gas Town workspace)"))
+	} else {
+		fmt.Printf("  %-20s %s\n", "town root", r.TownRoot)
+	}
+	if r.Rig != "" {
+		fmt.Printf("  %-20s %s (%s)\n", "rig", r.Rig, r.RigPath)
+	}
+	fmt.Printf("  %-20s %s\n", "cwd", r.Cwd)
+	if r.RuntimeDir != nil {
+		printPath(envPath{Label: "runtime dir", Path: r.RuntimeDir.Path, Exists: r.RuntimeDir.Exists})
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Formula directories (search order)"))
+	for _, p := range r.FormulaDirs {
+		printPath(p)
+	}
+	fmt.Printf("  %-20s %s\n", "embedded", "built into gt")
+
+	if len(r.ConfigFiles) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Config files"))
+		for _, p := range r.ConfigFiles {
+			printPath(p)
+		}
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Tools"))
+	for _, t := range r.Tools {
+		switch {
+		case t.Path == "":
+			fmt.Printf("  %-20s %s\n", t.Name, style.Dim.Render("(not found)"))
+		case t.Version == "":
+			fmt.Printf("  %-20s %s %s\n", t.Name, style.Dim.Render("(version unknown)"), t.Path)
+		default:
+			fmt.Printf("  %-20s %s  %s\n", t.Name, t.Version, style.Dim.Render(t.Path))
+		}
+	}
+	fmt.Printf("  %-20s %s\n", "platform", r.Platform)
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Environment overrides"))
+	if len(r.Overrides) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+	}
+	for _, v := range r.Overrides {
+		fmt.Printf("  %s=%s\n", v.Name, v.Value)
+	}
+}
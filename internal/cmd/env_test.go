@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	got := envOverrides([]string{
+		"PATH=/usr/bin",
+		"GT_TOWN_ROOT=/town",
+		"BEADS_DIR=/town/.beads",
+		"GT_GITHUB_TOKEN=ghp_secret",
+		"HOME=/root",
+	})
+	want := []envVar{
+		{Name: "BEADS_DIR", Value: "/town/.beads"},
+		{Name: "GT_GITHUB_TOKEN", Value: "***"},
+		{Name: "GT_TOWN_ROOT", Value: "/town"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("envOverrides() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("envOverrides()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCollectEnvReport(t *testing.T) {
+	townRoot, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workspace.WriteMarker(townRoot, "test"); err != nil {
+		t.Fatal(err)
+	}
+	rigDir := filepath.Join(townRoot, "gastown", "refinery", "rig")
+	if err := os.MkdirAll(rigDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "gastown", "config.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(rigDir)
+
+	r := collectEnvReport()
+	if r.TownRoot != townRoot || r.Rig != "gastown" {
+		t.Errorf("town root %q, rig %q; want %q, gastown", r.TownRoot, r.Rig, townRoot)
+	}
+	var labels []string
+	for _, p := range r.FormulaDirs {
+		labels = append(labels, p.Label)
+	}
+	if len(labels) < 3 || labels[0] != "project" || labels[1] != "rig" || labels[2] != "town" {
+		t.Errorf("formula dirs = %v, want project, rig, then town first", labels)
+	}
+	found := false
+	for _, p := range r.ConfigFiles {
+		if p.Label == "town marker" {
+			found = p.Exists
+		}
+		if p.Label == "town settings" && p.Exists {
+			t.Error("town settings reported as existing")
+		}
+	}
+	if !found {
+		t.Error("town marker not reported as existing")
+	}
+	if len(r.Tools) == 0 || r.Tools[0].Name != "gt" {
+		t.Errorf("tools = %v, want gt first", r.Tools)
+	}
+}
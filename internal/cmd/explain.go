@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/optree"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Explain flags
+var (
+	explainFlag bool
+)
+
+// explainCommands are the commands that honour --explain, by command path.
+var explainCommands = map[string]bool{
+	"gt formula run": true,
+	"gt doctor":      true, // With --fix
+	"gt rig restart": true,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&explainFlag, "explain", false,
+		"Show the operations and subprocesses the command will run, then ask before running them (formula run, doctor --fix, rig restart)")
+}
+
+// checkExplainSupported rejects --explain on commands that don't build a
+// plan, rather than silently running them unexplained.
+func checkExplainSupported(cmd *cobra.Command) error {
+	if !explainFlag {
+		return nil
+	}
+	path := buildCommandPath(cmd)
+	if !explainCommands[path] {
+		supported := make([]string, 0, len(explainCommands))
+		for p := range explainCommands {
+			supported = append(supported, p)
+		}
+		sort.Strings(supported)
+		return fmt.Errorf("--explain is not supported by %s (supported: %s)", path, strings.Join(supported, ", "))
+	}
+	if path == "gt doctor" && !doctorFix {
+		return fmt.Errorf("--explain applies to gt doctor --fix; plain gt doctor changes nothing")
+	}
+	return nil
+}
+
+// confirmExplained prints plan and asks whether to carry it out. A
+// declined plan is reported so the caller can return without changes.
+func confirmExplained(plan *optree.Node) bool {
+	fmt.Printf("%s Planned operations (%d subprocess call(s)):\n\n", style.Bold.Render("🔎"), plan.Commands())
+	plan.Render(os.Stdout)
+	fmt.Println()
+	if !promptYesNo("Proceed?") {
+		fmt.Println("Aborted; nothing was changed.")
+		return false
+	}
+	fmt.Println()
+	return true
+}
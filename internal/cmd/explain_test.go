@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckExplainSupported(t *testing.T) {
+	defer func(explain, fix bool) { explainFlag, doctorFix = explain, fix }(explainFlag, doctorFix)
+
+	explainFlag = false
+	if err := checkExplainSupported(statusCmd); err != nil {
+		t.Errorf("without --explain: %v", err)
+	}
+
+	explainFlag = true
+	if err := checkExplainSupported(formulaRunCmd); err != nil {
+		t.Errorf("gt formula run --explain: %v", err)
+	}
+	if err := checkExplainSupported(statusCmd); err == nil || !strings.Contains(err.Error(), "not supported by gt status") {
+		t.Errorf("gt status --explain = %v, want unsupported error", err)
+	}
+	doctorFix = false
+	if err := checkExplainSupported(doctorCmd); err == nil || !strings.Contains(err.Error(), "--fix") {
+		t.Errorf("gt doctor --explain = %v, want --fix error", err)
+	}
+	doctorFix = true
+	if err := checkExplainSupported(doctorCmd); err != nil {
+		t.Errorf("gt doctor --fix --explain: %v", err)
+	}
+}
+
+func TestExplainFormulaRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+	f := &formulaData{
+		Description: "Review",
+		Legs: []formulaLeg{
+			{ID: "security", Title: "Security"},
+			{ID: "summary", Title: "Summary", Needs: []string{"security"}},
+		},
+		Synthesis: &formulaSynthesis{Title: "Report"},
+	}
+
+	var b strings.Builder
+	explainFormulaRun(f, "code-review", "gastown").Render(&b)
+	out := b.String()
+	for _, want := range []string{
+		"$ bd create --type=convoy --id=hq-cv-<id>",
+		"$ bd import -i <batch>.jsonl",
+		"$ gt sling hq-leg-<security> gastown --context-file",
+		"leg summary waits (after security)",
+		`synthesis: hq-syn-<id> "Report"`,
+		"$ bd cook code-review",
+		"molecule: hq-mol-<id>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plan missing %q:\n%s", want, out)
+		}
+	}
+
+	formulaRunLabelArgs = []string{"team=infra"}
+	defer func() { formulaRunLabelArgs = nil }()
+	b.Reset()
+	explainFormulaRun(f, "code-review", "gastown").Render(&b)
+	if out := b.String(); !strings.Contains(out, "--labels=team:infra") {
+		t.Errorf("plan doesn't label the convoy:\n%s", out)
+	}
+
+	formulaRunNoMolecule = true
+	defer func() { formulaRunNoMolecule = false }()
+	b.Reset()
+	explainFormulaRun(f, "code-review", "gastown").Render(&b)
+	if out := b.String(); strings.Contains(out, "bd cook") || strings.Contains(out, "hq-mol-") {
+		t.Errorf("--no-molecule plan pours a molecule:\n%s", out)
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	exportManifestFormat string
+	exportManifestOut    string
+)
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	GroupID: GroupWorkspace,
+	Short:   "Export town descriptions",
+	RunE:    requireSubcommand,
+	Long: `Export descriptions of the town for sharing or reproducing it.
+
+Commands:
+  manifest   Describe the town as a committable JSON/TOML document`,
+}
+
+var exportManifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Export a reproducible town manifest",
+	Long: `Export a single document describing the town:
+
+  - gt version the manifest was exported with
+  - rigs: git remote, default branch, beads prefix, and the commit the
+    canonical clone (mayor/rig) is at, which gt init checks out
+  - formula overrides (.beads/formulas) with content hashes
+  - agent config: default agent, role agents, custom agents (without their
+    env, which may hold API keys)
+  - profiles: account handles and emails (no credentials or paths)
+
+The manifest is safe to commit. Recreate an equivalent town elsewhere with:
+
+  gt install ~/gt && cd ~/gt && gt init --from-manifest town.json
+
+Examples:
+  gt export manifest                          # JSON to stdout
+  gt export manifest --format toml -o town.toml`,
+	RunE: runExportManifest,
+}
+
+func init() {
+	exportManifestCmd.Flags().StringVar(&exportManifestFormat, "format", "json", "Output format: json or toml")
+	exportManifestCmd.Flags().StringVarP(&exportManifestOut, "out", "o", "", "Write to file instead of stdout")
+
+	exportCmd.AddCommand(exportManifestCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExportManifest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	m, err := buildTownManifest(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(m.strippedEnv) > 0 {
+		fmt.Fprintf(os.Stderr, "%s Left out env of agent(s) %s; set it again after gt init --from-manifest\n",
+			style.Dim.Render("Note:"), strings.Join(m.strippedEnv, ", "))
+	}
+	data, err := encodeTownManifest(m, exportManifestFormat)
+	if err != nil {
+		return err
+	}
+
+	if exportManifestOut == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := util.AtomicWriteFile(exportManifestOut, data, 0644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "%s Wrote manifest (%d rig(s), %d formula override(s)) to %s\n",
+		style.Success.Render("✓"), len(m.Rigs), len(m.Formulas), exportManifestOut)
+	return nil
+}
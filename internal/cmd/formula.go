@@ -3,8 +3,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
-	"crypto/rand"
-	"encoding/base32"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,11 +11,21 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentcache"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/review"
+	"github.com/steveyegge/gastown/internal/scm"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/suggest"
+	"github.com/steveyegge/gastown/internal/tokens"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -25,11 +34,14 @@ import (
 // Formula command flags
 var (
 	formulaListJSON   bool
+	formulaListType   string
 	formulaShowJSON   bool
 	formulaRunPR      int
 	formulaRunRig     string
 	formulaRunDryRun  bool
 	formulaCreateType string
+
+	formulaRunOverridePause bool
 )
 
 var formulaCmd = &cobra.Command{
@@ -49,6 +61,13 @@ Commands:
   show    Display formula details (steps, variables, composition)
   run     Execute a formula (pour and dispatch)
   create  Create a new formula template
+  edit    Edit a formula by instruction (--ai)
+  render  Render leg prompts with token estimates
+  diff    Show differences between formulas
+  which   Explain which file a formula name resolves to
+  reset   Restore a formula to the shipped version
+  promote Move an override to a broader level (demote: narrower)
+  modify  Record who owns an override and why (--owner, --reason)
 
 Search paths (in order):
   1. .beads/formulas/ (project)
@@ -59,22 +78,32 @@ Examples:
   gt formula list                    # List all formulas
   gt formula show shiny              # Show formula details
   gt formula run shiny --pr=123      # Run formula on PR #123
-  gt formula create my-workflow      # Create new formula template`,
+  gt formula create my-workflow      # Create new formula template
+  gt formula edit shiny --ai "..."    # Edit a formula with an agent
+  gt formula diff shiny              # Local edits vs shipped version`,
 }
 
 var formulaListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available formulas",
-	Long: `List available formulas from all search paths.
+	Long: `List available formulas as a catalog grouped by category.
 
 Searches for formula files (.formula.toml, .formula.json) in:
   1. .beads/formulas/ (project)
   2. ~/.beads/formulas/ (user)
   3. $GT_ROOT/.beads/formulas/ (orchestrator)
 
+Formulas embedded in gt are listed too, unless a file of the same name
+shadows them. Each entry shows its type, size (legs or steps), where it
+comes from, and the first line of its description.
+
+A formula's category is its "category" key, or its type if unset. Optional
+"tags" are shown after the description.
+
 Examples:
-  gt formula list            # List all formulas
-  gt formula list --json     # JSON output`,
+  gt formula list                # List all formulas
+  gt formula list --type convoy  # Only convoy formulas
+  gt formula list --json         # JSON output`,
 	RunE: runFormulaList,
 }
 
@@ -106,22 +135,36 @@ This command:
   2. Pours it to create a molecule (or uses existing proto)
   3. Dispatches the molecule to available workers
 
-For PR-based workflows, use --pr to specify the GitHub PR number.
+For PR-based workflows, use --pr to specify the PR: its number, or its URL
+as copied from the browser (GitHub, Gitea, or Bitbucket). A URL names the
+host and repository, and selects the rig tracking that repository (by its
+scm settings or git_url) unless --rig is given.
 
 If no formula name is provided, uses the default formula configured in
 the rig's settings/config.json under workflow.default_formula.
 
 Options:
-  --pr=N      Run formula on GitHub PR #N
-  --rig=NAME  Target specific rig (default: current or gastown)
-  --dry-run   Show what would happen without executing
+  --pr=N|URL     Run formula on PR #N, or on the PR at URL
+  --rig=NAME     Target specific rig (default: current or gastown)
+  --dry-run      Show what would happen without executing
+  --override-pause  Run even while the town is paused (gt pause) or in
+                    a maintenance window (runs are otherwise queued)
+  --local-agent  Run legs inline through the agent's non-interactive mode,
+                 writing outputs directly (no beads or polecats)
 
 Examples:
   gt formula run shiny                    # Run formula in current rig
   gt formula run                          # Run default formula from rig config
   gt formula run shiny --pr=123           # Run on PR #123
+  gt formula run shiny --pr https://github.com/org/repo/pull/123
   gt formula run security-audit --rig=beads  # Run in specific rig
-  gt formula run release --dry-run        # Preview execution`,
+  gt formula run release --dry-run        # Preview execution
+  gt formula run code-review --local-agent --parallel 2  # No polecats
+  gt formula run code-review --pr=123 --output - | less  # One document on stdout
+  gt formula run code-review --local-agent --fail-on high  # Fail CI on high findings
+  gt formula run code-review --pr=123 --watch-pr  # Re-run on new commits
+  gt formula run code-review --pr=123 --force     # Run again on the same head
+  gt formula run code-review --pr=123 --label team=infra  # Label the run's beads`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFormulaRun,
 }
@@ -134,6 +177,10 @@ var formulaCreateCmd = &cobra.Command{
 Creates a starter formula file in .beads/formulas/ with the given name.
 The template includes common sections that you can customize.
 
+With --interactive, asks for the type, legs or steps, variables, output
+layout, and target agent instead, and writes a formula that validates and
+runs as-is, optionally opening it in $EDITOR afterwards.
+
 Formula types:
   task      Single-step task formula (default)
   workflow  Multi-step workflow with dependencies
@@ -142,7 +189,8 @@ Formula types:
 Examples:
   gt formula create my-task                  # Create task formula
   gt formula create my-workflow --type=workflow
-  gt formula create nightly-check --type=patrol`,
+  gt formula create nightly-check --type=patrol
+  gt formula create my-review --interactive  # Guided convoy/workflow setup`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaCreate,
 }
@@ -150,14 +198,16 @@ Examples:
 func init() {
 	// List flags
 	formulaListCmd.Flags().BoolVar(&formulaListJSON, "json", false, "Output as JSON")
+	formulaListCmd.Flags().StringVar(&formulaListType, "type", "", "Only list formulas of this type (convoy, workflow, expansion, aspect)")
 
 	// Show flags
 	formulaShowCmd.Flags().BoolVar(&formulaShowJSON, "json", false, "Output as JSON")
 
 	// Run flags
-	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run formula on")
+	formulaRunCmd.Flags().StringVar(&formulaRunPRArg, "pr", "", "PR to run formula on: a number or a PR URL")
 	formulaRunCmd.Flags().StringVar(&formulaRunRig, "rig", "", "Target rig (default: current or gastown)")
 	formulaRunCmd.Flags().BoolVar(&formulaRunDryRun, "dry-run", false, "Preview execution without running")
+	formulaRunCmd.Flags().BoolVar(&formulaRunOverridePause, "override-pause", false, "Run even though the town is paused (gt pause) or in a maintenance window")
 
 	// Create flags
 	formulaCreateCmd.Flags().StringVar(&formulaCreateType, "type", "task", "Formula type: task, workflow, or patrol")
@@ -171,19 +221,6 @@ func init() {
 	rootCmd.AddCommand(formulaCmd)
 }
 
-// runFormulaList delegates to bd formula list
-func runFormulaList(cmd *cobra.Command, args []string) error {
-	bdArgs := []string{"formula", "list"}
-	if formulaListJSON {
-		bdArgs = append(bdArgs, "--json")
-	}
-
-	bdCmd := exec.Command("bd", bdArgs...)
-	bdCmd.Stdout = os.Stdout
-	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
-}
-
 // runFormulaShow delegates to bd formula show
 func runFormulaShow(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
@@ -195,15 +232,29 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 	bdCmd := exec.Command("bd", bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	return cmdtrace.Run(bdCmd)
 }
 
 // runFormulaRun executes a formula by spawning a convoy of polecats.
 // For convoy-type formulas, it creates a convoy bead, creates leg beads,
 // and slings each leg to a separate polecat with leg-specific prompts.
 func runFormulaRun(cmd *cobra.Command, args []string) error {
+	// A PR URL can name the rig; parse --pr before picking one.
+	prRig, err := resolveFormulaRunPR()
+	if err != nil {
+		return err
+	}
+	labels, err := formulaRunBeadLabels()
+	if err != nil {
+		return err
+	}
+	historyRunLabels = labels
+
 	// Determine target rig first (needed for default formula lookup)
 	targetRig := formulaRunRig
+	if targetRig == "" {
+		targetRig = prRig
+	}
 	var rigPath string
 	if targetRig == "" {
 		// Try to detect from current directory
@@ -247,7 +298,6 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("%s Using default formula: %s\n", style.Dim.Render("Note:"), formulaName)
 	}
-
 	// Find the formula file
 	formulaPath, err := findFormulaFile(formulaName)
 	if err != nil {
@@ -259,6 +309,12 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("parsing formula: %w", err)
 	}
+	warnFormulaSchema(f)
+	if !formulaRunLocalAgent && formulaRunOutput == "" {
+		if err := checkPolecatLegRetry(f); err != nil {
+			return err
+		}
+	}
 
 	// Handle dry-run mode
 	if formulaRunDryRun {
@@ -278,8 +334,59 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if formulaRunFailOn != "" {
+		if _, err := review.ParseSeverity(formulaRunFailOn); err != nil {
+			return fmt.Errorf("--fail-on: %w", err)
+		}
+		if !formulaRunLocalAgent && formulaRunOutput == "" {
+			return fmt.Errorf("--fail-on needs a foreground run (--local-agent); gate polecat runs with 'gt review gate'")
+		}
+	}
+
+	// Gate the run. With --explain, show what it will do and ask before
+	// taking the quota and class locks, which are held only while the run
+	// is being recorded.
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		townRoot = ""
+	}
+	preflight := formulaPreflight{
+		TownRoot:      townRoot,
+		Rig:           targetRig,
+		Formula:       formulaName,
+		F:             f,
+		OverridePause: formulaRunOverridePause,
+	}
+	if explainFlag {
+		preflight.Confirm = func() bool { return confirmExplained(explainFormulaRun(f, formulaName, targetRig)) }
+	}
+	hold, ok, err := preflight.check()
+	if !ok {
+		return err
+	}
+	defer hold.release()
+
+	// Fingerprint the environment for gt formula history
+	if townRoot != "" {
+		recordFormulaRunEnv(f, formulaName, townRoot, rigPath)
+	}
+
 	// Execute convoy formula
-	return executeConvoyFormula(f, formulaName, targetRig)
+	if formulaRunLocalAgent || formulaRunOutput != "" {
+		return executeConvoyFormulaLocal(f, formulaName, targetRig, hold)
+	}
+	if formulaRunWatchPR {
+		// The watch runs the preflight again before each run
+		hold.release()
+		return watchPRFormula(f, formulaName, targetRig)
+	}
+	if townRoot != "" {
+		if err := checkDuplicatePRRun(townRoot, formulaName, targetRig); err != nil {
+			return err
+		}
+	}
+	_, err = executeConvoyFormula(f, formulaName, targetRig, hold)
+	return err
 }
 
 // dryRunFormula shows what would happen without executing
@@ -288,9 +395,28 @@ func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
 	fmt.Printf("  Formula: %s\n", style.Bold.Render(formulaName))
 	fmt.Printf("  Type:    %s\n", f.Type)
 	fmt.Printf("  Rig:     %s\n", targetRig)
+	if formulaRunLocalAgent || formulaRunOutput != "" {
+		fmt.Printf("  Mode:    local agent (%d at a time, no polecats)\n", max(formulaRunParallel, 1))
+	}
+	if formulaRunOutput != "" {
+		fmt.Printf("  Output:  %s\n", formulaRunOutput)
+	}
 	if formulaRunPR > 0 {
 		fmt.Printf("  PR:      #%d\n", formulaRunPR)
 	}
+	if len(historyRunLabels) > 0 {
+		fmt.Printf("  Labels:  %s\n", strings.Join(historyRunLabels, ", "))
+	}
+	printFormulaRequires(f, targetRig)
+	if !formulaRunLocalAgent && formulaRunOutput == "" {
+		f = expandConsensusLegs(f)
+		if townRoot, err := workspace.FindFromCwd(); err == nil && formulaRunNeedsApproval(f, townRoot, targetRig) {
+			fmt.Printf("  Approval: required; legs are held until gt convoy approve\n")
+		}
+	}
+	if formulaRunCanary > 0 && f.Type == "convoy" {
+		fmt.Printf("  Canary:  %s\n", describeCanary(f, pickCanaryLegs(f, formulaRunCanary)))
+	}
 
 	if f.Type == "convoy" && len(f.Legs) > 0 {
 		// Generate review ID for dry-run display
@@ -308,7 +434,13 @@ func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
 		var prTitle string
 		var changedFiles []map[string]interface{}
 		if formulaRunPR > 0 {
-			prTitle, changedFiles = fetchPRInfo(formulaRunPR)
+			provider, err := formulaRunSCM(targetRig)
+			if err != nil {
+				return err
+			}
+			if prTitle, changedFiles, err = fetchPRInfo(provider, formulaRunPR); err != nil {
+				return err
+			}
 			if prTitle != "" {
 				fmt.Printf("  PR Title: %s\n", prTitle)
 			}
@@ -317,6 +449,12 @@ func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
 			}
 		}
 
+		townRoot, _ := workspace.FindFromCwd()
+		var rigPath string
+		if townRoot != "" {
+			rigPath = filepath.Join(townRoot, targetRig)
+		}
+
 		// Show output directory if configured
 		var outputDir string
 		if f.Output != nil && f.Output.Directory != "" {
@@ -324,33 +462,71 @@ func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
 				"review_id":    reviewID,
 				"formula_name": formulaName,
 			}
-			outputDir = renderTemplateOrDefault(f.Output.Directory, dirCtx, ".reviews/"+reviewID)
+			dir, err := renderOutputDir(f, dirCtx, ".reviews/"+reviewID, formulaOutputRoot(townRoot, rigPath))
+			if err != nil {
+				return err
+			}
+			outputDir = dir
 			fmt.Printf("\n  Output directory: %s\n", outputDir)
 		}
 
-		fmt.Printf("\n  Legs (%d parallel):\n", len(f.Legs))
-		for _, leg := range f.Legs {
+		// Estimate prompt sizes for the agent that will run the legs
+		family, agentName := resolveTokenFamily(townRoot, rigPath)
+		var diff string
+		if formulaRunPR > 0 {
+			provider, err := formulaPRProvider(townRoot, rigPath)
+			if err != nil {
+				return err
+			}
+			if diff, err = fetchPRDiff(provider, formulaRunPR); err != nil {
+				return err
+			}
+		}
+		diffTokens := tokens.Estimate(diff, family)
+
+		fmt.Printf("\n  Legs (%d):\n", len(f.Legs))
+		var estimates []legPromptEstimate
+		built := make(map[string]map[string]interface{})
+		workspaces := make(map[string]*legWorkspace)
+		var wsLegIDs []string
+		for _, leg := range orderedLegs(f) {
+			legCtx := legPromptContext(formulaName, leg, reviewID, targetDescription, formulaRunPR, prTitle, changedFiles)
+			addLegOutputContext(f, legCtx, leg, outputDir)
+			addLegUpstreamContext(legCtx, leg, built)
+			built[leg.ID] = legCtx
+			est := estimateLegPrompt(leg, renderLegDescription(f, leg, legCtx), diffTokens, family)
+			estimates = append(estimates, est)
+
 			// Show rendered output path for each leg
+			fmt.Printf("    • %s: %s\n", leg.ID, leg.Title)
+			if len(leg.Needs) > 0 {
+				fmt.Printf("      %s\n", style.Dim.Render(describeLegNeeds(leg)))
+			}
+			if leg.Consensus != nil {
+				fmt.Printf("      %s\n", style.Dim.Render(describeLegConsensus(leg.Consensus)))
+			}
 			if f.Output != nil && outputDir != "" {
-				legCtx := map[string]interface{}{
-					"formula_name":       formulaName,
-					"target_description": targetDescription,
-					"review_id":          reviewID,
-					"pr_number":          formulaRunPR,
-					"pr_title":           prTitle,
-					"leg": map[string]interface{}{
-						"id":          leg.ID,
-						"title":       leg.Title,
-						"focus":       leg.Focus,
-						"description": leg.Description,
-					},
-					"changed_files": changedFiles,
-				}
-				legPattern := renderTemplateOrDefault(f.Output.LegPattern, legCtx, leg.ID+"-findings.md")
-				outputPath := filepath.Join(outputDir, legPattern)
-				fmt.Printf("    • %s: %s\n      → %s\n", leg.ID, leg.Title, outputPath)
-			} else {
-				fmt.Printf("    • %s: %s\n", leg.ID, leg.Title)
+				fmt.Printf("      → %s\n", legCtx["output_path"])
+			}
+			if ws, err := resolveLegWorkspace(leg, legCtx, rigPath); err != nil {
+				fmt.Printf("      %s %v\n", style.Warning.Render("⚠"), err)
+			} else if ws != nil {
+				fmt.Printf("      ⎇ %s (%s)\n", ws.Path, ws.Branch)
+				workspaces[leg.ID] = ws
+				wsLegIDs = append(wsLegIDs, leg.ID)
+			}
+			fmt.Printf("      %s\n", style.Dim.Render(formatLegEstimate(est)))
+		}
+		if err := checkLegWorkspaces(rigPath, wsLegIDs, workspaces); err != nil {
+			fmt.Printf("\n  %s %v\n", style.Warning.Render("⚠"), err)
+		}
+		if hasLegEstimateWarnings(estimates) {
+			fmt.Printf("\n  Context window (%s):\n", agentName)
+			printLegEstimateWarnings(estimates, agentName)
+		}
+		if formulaRunPR > 0 {
+			if plan := planLegContext(f, family, diff); plan.Oversized() {
+				fmt.Printf("\n  Context: %s\n", describeLegContextPlan(plan))
 			}
 		}
 		if f.Synthesis != nil {
@@ -361,6 +537,9 @@ func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
 			} else {
 				fmt.Printf("    • %s\n", f.Synthesis.Title)
 			}
+			if f.Synthesis.Require.IsPartial() {
+				fmt.Printf("      requires %s legs\n", f.Synthesis.Require)
+			}
 		}
 	}
 
@@ -368,54 +547,78 @@ func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
 }
 
 // executeConvoyFormula spawns a convoy of polecats to execute a convoy formula
-func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
+// and returns the convoy ID. The hold's class is released once the convoy
+// bead exists and its quota once the run is in the rig's audit log.
+func executeConvoyFormula(f *formulaData, formulaName, targetRig string, hold runHold) (string, error) {
+	defer hold.release()
+	f = expandConsensusLegs(f)
 	fmt.Printf("%s Executing convoy formula: %s\n\n",
 		style.Bold.Render("🚚"), formulaName)
 
 	// Get town beads directory for convoy creation
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
-		return fmt.Errorf("finding town root: %w", err)
+		return "", fmt.Errorf("finding town root: %w", err)
 	}
 	townBeads := filepath.Join(townRoot, ".beads")
 
-	// Step 1: Create convoy bead
-	convoyID := fmt.Sprintf("hq-cv-%s", generateFormulaShortID())
-	convoyTitle := fmt.Sprintf("%s: %s", formulaName, f.Description)
-	if len(convoyTitle) > 80 {
-		convoyTitle = convoyTitle[:77] + "..."
-	}
-
-	// Build description with formula context
-	description := fmt.Sprintf("Formula convoy: %s\n\nLegs: %d\nRig: %s",
-		formulaName, len(f.Legs), targetRig)
-	if formulaRunPR > 0 {
-		description += fmt.Sprintf("\nPR: #%d", formulaRunPR)
+	priority, err := formulaRunLegPriority(townRoot)
+	if err != nil {
+		return "", err
 	}
-
-	createArgs := []string{
-		"create",
-		"--type=convoy",
-		"--id=" + convoyID,
-		"--title=" + convoyTitle,
-		"--description=" + description,
+	labels, err := formulaRunBeadLabels()
+	if err != nil {
+		return "", err
 	}
-	if beads.NeedsForceForID(convoyID) {
-		createArgs = append(createArgs, "--force")
+	// Normal priority leaves bd's default in place
+	beadPriority := -1
+	if priority != convoy.PriorityNormal {
+		beadPriority = priority.BeadPriority()
 	}
 
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Dir = townBeads
-	createCmd.Stderr = os.Stderr
-	if err := createCmd.Run(); err != nil {
-		return fmt.Errorf("creating convoy bead: %w", err)
+	// Fetch PR info if --pr flag is set; a rerun reuses what its run saw.
+	// Done first so a failed fetch leaves no convoy behind.
+	var prTitle, diff string
+	var changedFiles []map[string]interface{}
+	if formulaRunReplay != nil {
+		prTitle, changedFiles = formulaRunReplay.PRTitle, formulaRunReplay.ChangedFiles
+	} else if formulaRunPR > 0 {
+		provider, err := formulaPRProvider(townRoot, filepath.Join(townRoot, targetRig))
+		if err != nil {
+			return "", err
+		}
+		if prTitle, changedFiles, err = fetchPRInfo(provider, formulaRunPR); err != nil {
+			return "", err
+		}
+		if diff, err = fetchPRDiff(provider, formulaRunPR); err != nil {
+			return "", err
+		}
 	}
 
-	fmt.Printf("%s Created convoy: %s\n", style.Bold.Render("✓"), convoyID)
+	// Step 1: Create convoy bead
+	convoyID := fmt.Sprintf("hq-cv-%s", generateFormulaShortID())
+	convoyTitle := fmt.Sprintf("%s: %s", formulaName, f.Description)
+	if len(convoyTitle) > 80 {
+		convoyTitle = convoyTitle[:77] + "..."
+	}
 
 	// Generate a unique review ID for this convoy run
 	reviewID := generateFormulaShortID()
 
+	// Render the output directory before creating anything, so a template
+	// that escapes the output root leaves no convoy behind.
+	var outputDir string
+	if f.Output != nil && f.Output.Directory != "" {
+		dirCtx := map[string]interface{}{
+			"review_id":    reviewID,
+			"formula_name": formulaName,
+		}
+		outputDir, err = renderOutputDir(f, dirCtx, ".reviews/"+reviewID, formulaOutputRoot(townRoot, filepath.Join(townRoot, targetRig)))
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// Build target description
 	var targetDescription string
 	if formulaRunPR > 0 {
@@ -424,24 +627,74 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 		targetDescription = "local files"
 	}
 
-	// Fetch PR info if --pr flag is set
-	var prTitle string
-	var changedFiles []map[string]interface{}
+	// Prepare the legs' worktrees before creating anything, so colliding
+	// branches or a failed checkout leave no convoy behind. Workdir and
+	// branch templates render before bead IDs are assigned.
+	rigPath := filepath.Join(townRoot, targetRig)
+	legWorkspaces := make(map[string]*legWorkspace) // leg.ID -> prepared worktree
+	var legIDs []string
+	wsCtx := make(map[string]map[string]interface{})
+	for _, leg := range orderedLegs(f) {
+		legCtx := legPromptContext(formulaName, leg, reviewID, targetDescription, formulaRunPR, prTitle, changedFiles)
+		addLegOutputContext(f, legCtx, leg, outputDir)
+		addLegUpstreamContext(legCtx, leg, wsCtx)
+		wsCtx[leg.ID] = legCtx
+		ws, err := resolveLegWorkspace(leg, legCtx, rigPath)
+		if err != nil {
+			return "", fmt.Errorf("leg %s workspace: %w", leg.ID, err)
+		}
+		if ws != nil {
+			legWorkspaces[leg.ID] = ws
+			legIDs = append(legIDs, leg.ID)
+		}
+	}
+	if err := prepareLegWorkspaces(rigPath, legIDs, legWorkspaces); err != nil {
+		return "", err
+	}
+
+	// Cook the formula's proto and pour the run as a molecule of it
+	mol, err := newFormulaMolecule(townBeads, formulaName, reviewID, labels)
+	if err != nil {
+		return "", err
+	}
+
+	// Build description with formula context. The formula and review_id
+	// fields let synthesis and reporting locate leg outputs later.
+	description := fmt.Sprintf("Formula convoy: %s\n\nformula: %s\nreview_id: %s\nLegs: %d\nRig: %s",
+		formulaName, formulaName, reviewID, len(f.Legs), targetRig)
 	if formulaRunPR > 0 {
-		prTitle, changedFiles = fetchPRInfo(formulaRunPR)
+		description += fmt.Sprintf("\nPR: #%d", formulaRunPR)
+	}
+	description += prRunFields(formulaRunHeadSHA, formulaRunSupersedes)
+	if formulaRunClonedFrom != "" {
+		description += "\ncloned_from: " + formulaRunClonedFrom
 	}
 
-	// Create output directory if configured
-	var outputDir string
-	if f.Output != nil && f.Output.Directory != "" {
-		// Build minimal context for directory rendering
-		dirCtx := map[string]interface{}{
-			"review_id":    reviewID,
-			"formula_name": formulaName,
-		}
-		outputDir = renderTemplateOrDefault(f.Output.Directory, dirCtx, ".reviews/"+reviewID)
+	if formulaRunRerunOf != "" {
+		description += "\nrerun_of: " + formulaRunRerunOf
+	}
+	if priority != convoy.PriorityNormal {
+		description += "\npriority: " + string(priority)
+	}
+	if f.ConcurrencyClass != "" {
+		description += "\nconcurrency_class: " + f.ConcurrencyClass
+	}
+	description += mol.convoyField()
+	needsApproval := formulaRunNeedsApproval(f, townRoot, targetRig)
+	if needsApproval {
+		description += "\napproval: required"
+	}
+
+	if err := createFormulaConvoyBead(townBeads, convoyID, convoyTitle, description, labels); err != nil {
+		mol.discard(townBeads)
+		return "", err
+	}
+	hold.releaseClass()
+
+	fmt.Printf("%s Created convoy: %s\n", style.Bold.Render("✓"), convoyID)
 
-		// Create the directory
+	// Create output directory if configured
+	if outputDir != "" {
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
 			fmt.Printf("%s Failed to create output directory %s: %v\n",
 				style.Dim.Render("Warning:"), outputDir, err)
@@ -450,90 +703,145 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 		}
 	}
 
+	// Trim an oversized PR diff per the formula's context_strategy
+	var legContext string
+	if formulaRunReplay != nil {
+		legContext = formulaRunReplay.LegContext
+	} else if formulaRunPR > 0 {
+		family, _ := resolveTokenFamily(townRoot, rigPath)
+		plan := planLegContext(f, family, diff)
+		if plan.Oversized() {
+			fmt.Printf("  %s Context: %s\n", style.Dim.Render("⚠"), describeLegContextPlan(plan))
+		}
+		if plan.Trims() {
+			contextDir := filepath.Join(outputDir, "context")
+			if outputDir == "" {
+				contextDir = filepath.Join(".reviews", reviewID, "context")
+			}
+			legContext, err = buildLegContext(plan, f, diff, townRoot, rigPath, contextDir, family)
+			if err != nil {
+				fmt.Printf("%s Failed to apply %s: %v\n", style.Dim.Render("Warning:"), plan.Strategy, err)
+			}
+		}
+	}
+
 	// Step 2: Create leg beads and track them
-	legBeads := make(map[string]string) // leg.ID -> bead ID
-	for _, leg := range f.Legs {
+	legBeads := make(map[string]string)            // leg.ID -> bead ID
+	legPayloads := make(map[string]*slingPayload)  // leg.ID -> sling context payload
+	batch := beads.OpenStore(townBeads).NewBatch() // Leg and synthesis beads, keyed by leg.ID
+	if formulaRunClonedFrom != "" {
+		batch.AddDependency("clone", convoyID, formulaRunClonedFrom, "related")
+	}
+	mol.link(batch, convoyID)
+	built := make(map[string]map[string]interface{}) // leg.ID -> template context
+	for _, leg := range orderedLegs(f) {
 		legBeadID := fmt.Sprintf("hq-leg-%s", generateFormulaShortID())
 
-		// Build leg description with prompt if available
-		legDesc := leg.Description
-		if f.Prompts != nil {
-			if basePrompt, ok := f.Prompts["base"]; ok {
-				// Build template context for this leg
-				legCtx := map[string]interface{}{
-					"formula_name":       formulaName,
-					"target_description": targetDescription,
-					"review_id":          reviewID,
-					"pr_number":          formulaRunPR,
-					"pr_title":           prTitle,
-					"leg": map[string]interface{}{
-						"id":          leg.ID,
-						"title":       leg.Title,
-						"focus":       leg.Focus,
-						"description": leg.Description,
-					},
-					"changed_files": changedFiles,
-					"files":         []string{}, // TODO: support --files flag
-				}
-
-				// Compute output path for this leg
-				if f.Output != nil {
-					legPattern := renderTemplateOrDefault(f.Output.LegPattern, legCtx, leg.ID+"-findings.md")
-					outputPath := filepath.Join(outputDir, legPattern)
-					legCtx["output_path"] = outputPath
-					legCtx["output"] = map[string]interface{}{
-						"directory": outputDir,
-						"synthesis": f.Output.Synthesis,
-					}
-				}
-
-				// Render the base prompt with template context
-				renderedPrompt, err := renderTemplate(basePrompt, legCtx)
-				if err != nil {
-					fmt.Printf("%s Failed to render template for %s: %v\n",
-						style.Dim.Render("Warning:"), leg.ID, err)
-					renderedPrompt = basePrompt // Fall back to raw template
-				}
-				legDesc = fmt.Sprintf("%s\n\n---\nBase Prompt:\n%s", leg.Description, renderedPrompt)
+		// A leg can't run without the legs it needs
+		var upstreamBeads []string
+		for _, need := range leg.Needs {
+			if id, ok := legBeads[need]; ok {
+				upstreamBeads = append(upstreamBeads, id)
 			}
 		}
+		if len(upstreamBeads) < len(leg.Needs) {
+			fmt.Printf("%s %s needs a skipped leg, skipping leg\n",
+				style.Dim.Render("Warning:"), leg.ID)
+			continue
+		}
 
-		legArgs := []string{
-			"create",
-			"--type=task",
-			"--id=" + legBeadID,
-			"--title=" + leg.Title,
-			"--description=" + legDesc,
+		// Build leg description with prompt if available
+		legCtx := legPromptContext(formulaName, leg, reviewID, targetDescription, formulaRunPR, prTitle, changedFiles)
+		addLegOutputContext(f, legCtx, leg, outputDir)
+		legCtx["bead_id"] = legBeadID
+		addLegUpstreamContext(legCtx, leg, built)
+		built[leg.ID] = legCtx
+		legDesc := renderLegDescription(f, leg, legCtx)
+		if legContext != "" {
+			legDesc += "\n\n---\nContext:\n" + legContext
 		}
-		if beads.NeedsForceForID(legBeadID) {
-			legArgs = append(legArgs, "--force")
+
+		// Resolve the leg's output contract so gt done can check it
+		contract, err := resolveLegContract(leg, legCtx, f.Path)
+		if err != nil {
+			fmt.Printf("%s Invalid output contract for %s, skipping leg: %v\n",
+				style.Dim.Render("Warning:"), leg.ID, err)
+			continue
 		}
 
-		legCmd := exec.Command("bd", legArgs...)
-		legCmd.Dir = townBeads
-		legCmd.Stderr = os.Stderr
-		if err := legCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to create leg bead for %s: %v\n",
+		// Formula and leg env overrides; secret references stay unresolved
+		// until gt sling starts the polecat
+		legEnv := config.MergeEnv(f.Env, leg.Env)
+		if err := config.ValidateEnv("env", legEnv); err != nil {
+			fmt.Printf("%s Invalid env for %s, skipping leg: %v\n",
 				style.Dim.Render("Warning:"), leg.ID, err)
 			continue
 		}
 
-		// Track the leg with the convoy
-		trackArgs := []string{"dep", "add", convoyID, legBeadID, "--type=tracks"}
-		trackCmd := exec.Command("bd", trackArgs...)
-		trackCmd.Dir = townBeads
-		if err := trackCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to track leg %s: %v\n",
+		// Legs with runner = "docker" run their agent in a container
+		container, err := formula.NewLegContainer(leg.Runner, leg.Image, leg.Mounts)
+		if err != nil {
+			fmt.Printf("%s Invalid runner for %s, skipping leg: %v\n",
 				style.Dim.Render("Warning:"), leg.ID, err)
+			continue
 		}
 
+		// The leg's isolated worktree, if it declares workdir/branch
+		ws := legWorkspaces[leg.ID]
+		if ws != nil {
+			legDesc += "\n\n---\nWorkspace:\n" + legWorkspaceNote(ws)
+		}
+		if contract != nil {
+			legDesc += "\n\n---\nOutput contract:\n" + legContractNote(contract)
+		}
+
+		batch.Create(leg.ID, beads.BatchIssue{
+			ID:          legBeadID,
+			Title:       leg.Title,
+			Description: legDesc,
+			Priority:    beadPriority,
+			Labels:      labels,
+		})
+
+		outputPath, _ := legCtx["output_path"].(string)
+		payload := &slingPayload{
+			ConvoyID:   convoyID,
+			Subject:    leg.Title,
+			Args:       leg.Description,
+			Prompt:     legDesc,
+			OutputPath: outputPath,
+			Agent:      legAgent(f, leg),
+			Expect:     contract,
+			Container:  container,
+			Priority:   priority,
+			Retry:      leg.Retry,
+			Env: config.MergeEnv(legEnv, map[string]string{
+				"GT_CONVOY":    convoyID,
+				"GT_REVIEW_ID": reviewID,
+				"GT_LEG":       leg.ID,
+			}),
+		}
+		if ws != nil {
+			payload.Workdir = ws.Path
+			payload.Branch = ws.Branch
+		}
+		payload.Needs = upstreamBeads
+		legPayloads[leg.ID] = payload
+
+		// Track the leg with the convoy; it is blocked by the legs it needs
+		batch.AddDependency(leg.ID, convoyID, legBeadID, "tracks")
+		mol.adopt(batch, leg.ID, legBeadID)
+		for _, upstream := range upstreamBeads {
+			batch.AddDependency(leg.ID, legBeadID, upstream, "")
+		}
 		legBeads[leg.ID] = legBeadID
-		fmt.Printf("  %s Created leg: %s (%s)\n", style.Dim.Render("○"), leg.ID, legBeadID)
 	}
 
-	// Step 3: Create synthesis bead if defined
+	// Step 3: Create synthesis bead if defined. With a partial
+	// synthesis.require policy the bead can't be blocked on every leg, so
+	// gt synthesis start creates it once enough legs complete.
 	var synthesisBeadID string
-	if f.Synthesis != nil {
+	if f.Synthesis != nil && !f.Synthesis.Require.IsPartial() {
 		synthesisBeadID = fmt.Sprintf("hq-syn-%s", generateFormulaShortID())
 
 		synDesc := f.Synthesis.Description
@@ -541,95 +849,307 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 			synDesc = "Synthesize findings from all legs into unified output"
 		}
 
-		synArgs := []string{
-			"create",
-			"--type=task",
-			"--id=" + synthesisBeadID,
-			"--title=" + f.Synthesis.Title,
-			"--description=" + synDesc,
-		}
-		if beads.NeedsForceForID(synthesisBeadID) {
-			synArgs = append(synArgs, "--force")
+		batch.Create("synthesis", beads.BatchIssue{
+			ID:          synthesisBeadID,
+			Title:       f.Synthesis.Title,
+			Description: synDesc,
+			Priority:    beadPriority,
+			Labels:      labels,
+		})
+		// Track synthesis with convoy; synthesis depends on all legs
+		batch.AddDependency("synthesis", convoyID, synthesisBeadID, "tracks")
+		mol.adopt(batch, "synthesis", synthesisBeadID)
+		for _, leg := range f.Legs {
+			if legBeadID, ok := legBeads[leg.ID]; ok {
+				batch.AddDependency("synthesis", synthesisBeadID, legBeadID, "")
+			}
 		}
+	}
 
-		synCmd := exec.Command("bd", synArgs...)
-		synCmd.Dir = townBeads
-		synCmd.Stderr = os.Stderr
-		if err := synCmd.Run(); err != nil {
+	// Create all leg and synthesis beads with one bd operation, then drop
+	// legs whose bead could not be created
+	var batchErr *beads.BatchError
+	if err := batch.Apply(); err != nil {
+		var ok bool
+		if batchErr, ok = err.(*beads.BatchError); !ok {
+			return "", fmt.Errorf("creating leg beads: %w", err)
+		}
+	}
+	mol.report(batchErr)
+	for _, leg := range orderedLegs(f) {
+		legBeadID, ok := legBeads[leg.ID]
+		if !ok {
+			continue
+		}
+		if err := batchErr.CreateFailed(legBeadID); err != nil {
+			fmt.Printf("%s Failed to create leg bead for %s: %v\n",
+				style.Dim.Render("Warning:"), leg.ID, err)
+			delete(legBeads, leg.ID)
+			delete(legPayloads, leg.ID)
+			continue
+		}
+		if missing := missingNeeds(leg, legBeads); missing != "" {
+			// Its bead exists but can never run; close it so the convoy can land
+			fmt.Printf("%s %s needs %s, which was not created; closing %s\n",
+				style.Dim.Render("Warning:"), leg.ID, missing, legBeadID)
+			_ = beads.New(townBeads).CloseWithReason("needed leg "+missing+" was not created", legBeadID)
+			delete(legBeads, leg.ID)
+			delete(legPayloads, leg.ID)
+			continue
+		}
+		if err := batchErr.Failed(leg.ID); err != nil {
+			fmt.Printf("%s Failed to track leg %s: %v\n",
+				style.Dim.Render("Warning:"), leg.ID, err)
+		}
+		fmt.Printf("  %s Created leg: %s (%s)\n", style.Dim.Render("○"), leg.ID, legBeadID)
+	}
+	if err := batchErr.Failed("clone"); err != nil {
+		fmt.Printf("%s Failed to link %s to %s: %v\n",
+			style.Dim.Render("Warning:"), convoyID, formulaRunClonedFrom, err)
+	}
+	if synthesisBeadID != "" {
+		if err := batchErr.CreateFailed(synthesisBeadID); err != nil {
 			fmt.Printf("%s Failed to create synthesis bead: %v\n",
 				style.Dim.Render("Warning:"), err)
 		} else {
-			// Track synthesis with convoy
-			trackArgs := []string{"dep", "add", convoyID, synthesisBeadID, "--type=tracks"}
-			trackCmd := exec.Command("bd", trackArgs...)
-			trackCmd.Dir = townBeads
-			_ = trackCmd.Run()
-
-			// Add dependencies: synthesis depends on all legs
-			for _, legBeadID := range legBeads {
-				depArgs := []string{"dep", "add", synthesisBeadID, legBeadID}
-				depCmd := exec.Command("bd", depArgs...)
-				depCmd.Dir = townBeads
-				_ = depCmd.Run()
-			}
-
 			fmt.Printf("  %s Created synthesis: %s\n", style.Dim.Render("★"), synthesisBeadID)
 		}
 	}
 
-	// Step 4: Sling each leg to a polecat
-	fmt.Printf("\n%s Dispatching legs to polecats...\n\n", style.Bold.Render("→"))
+	// Step 4: Queue each leg for a polecat; the dispatcher slings them in
+	// priority order as the rig has room. Runs that need approval hold
+	// their legs until gt convoy approve.
+	if needsApproval {
+		fmt.Printf("\n%s Holding legs for approval...\n\n", style.Bold.Render("→"))
+	} else {
+		fmt.Printf("\n%s Dispatching legs to polecats...\n\n", style.Bold.Render("→"))
+	}
+
+	// With --canary, the other legs wait for the canary legs' beads
+	isCanary := make(map[string]bool)
+	var canaryBeads []string
+	for _, id := range pickCanaryLegs(f, formulaRunCanary) {
+		if legBeadID, ok := legBeads[id]; ok {
+			isCanary[id] = true
+			canaryBeads = append(canaryBeads, legBeadID)
+		}
+	}
+	if formulaRunCanary > 0 && len(canaryBeads) == 0 {
+		fmt.Printf("%s %s\n", style.Dim.Render("Note:"), describeCanary(f, nil))
+	}
 
-	slingCount := 0
+	var queued []convoy.QueuedLeg
+	waitCount := 0
 	for _, leg := range f.Legs {
 		legBeadID, ok := legBeads[leg.ID]
 		if !ok {
 			continue
 		}
-
-		// Build context message for the polecat
-		contextMsg := fmt.Sprintf("Convoy leg: %s\nFocus: %s", leg.Title, leg.Focus)
-
-		// Use gt sling with args for leg-specific context
-		slingArgs := []string{
-			"sling", legBeadID, targetRig,
-			"-a", leg.Description,
-			"-s", leg.Title,
+		if len(canaryBeads) > 0 && !isCanary[leg.ID] {
+			legPayloads[leg.ID].Canary = canaryBeads
 		}
 
-		slingCmd := exec.Command("gt", slingArgs...)
-		slingCmd.Stdout = os.Stdout
-		slingCmd.Stderr = os.Stderr
-
-		if err := slingCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to sling leg %s: %v\n",
+		// Hand the leg's context to gt sling as a structured payload
+		payloadPath, err := saveSlingPayload(townRoot, legBeadID, legPayloads[leg.ID])
+		if err != nil {
+			fmt.Printf("%s Failed to write context for leg %s: %v\n",
 				style.Dim.Render("Warning:"), leg.ID, err)
-			// Add comment to bead about failure
-			commentArgs := []string{"comment", legBeadID, fmt.Sprintf("Failed to sling: %v", err)}
-			commentCmd := exec.Command("bd", commentArgs...)
-			commentCmd.Dir = townBeads
-			_ = commentCmd.Run()
 			continue
 		}
 
-		slingCount++
-		_ = contextMsg // Used in future for richer context
+		// Legs with needs wait; gt convoy check queues them once their
+		// upstream legs close
+		if len(leg.Needs) > 0 {
+			fmt.Printf("  %s %s waits (%s)\n", style.Dim.Render("◌"), leg.ID, describeLegNeeds(leg))
+			waitCount++
+			continue
+		}
+		if len(legPayloads[leg.ID].Canary) > 0 {
+			fmt.Printf("  %s %s waits (after canary %s)\n", style.Dim.Render("◌"), leg.ID, strings.Join(legPayloads[leg.ID].Canary, ", "))
+			waitCount++
+			continue
+		}
+		if isCanary[leg.ID] {
+			fmt.Printf("  %s %s is a canary\n", style.Dim.Render("◆"), leg.ID)
+		}
+		queued = append(queued, convoy.QueuedLeg{
+			BeadID:   legBeadID,
+			Rig:      targetRig,
+			ConvoyID: convoyID,
+			Payload:  payloadPath,
+			Priority: priority,
+		})
+	}
+	dispatched := make(map[string]bool)
+	if needsApproval {
+		if err := holdForApproval(townRoot, convoyID, convoyTitle, formulaName, targetRig, queued); err != nil {
+			return "", err
+		}
+	} else {
+		if err := enqueueLegs(townRoot, queued); err != nil {
+			fmt.Printf("%s Failed to queue legs: %v\n", style.Dim.Render("Warning:"), err)
+		}
+		for _, id := range dispatchLegQueue(townRoot, legDispatchOptions{Preempt: formulaRunPreempt}) {
+			dispatched[id] = true
+		}
+	}
+	slingCount, queueCount := 0, 0
+	for _, leg := range queued {
+		if dispatched[leg.BeadID] {
+			slingCount++
+		} else if !needsApproval {
+			queueCount++
+		}
+	}
+
+	// Record the run and the beads it created in the rig's audit log
+	createdBeads := []string{convoyID}
+	for _, leg := range f.Legs {
+		if id, ok := legBeads[leg.ID]; ok {
+			createdBeads = append(createdBeads, id)
+		}
+	}
+	if synthesisBeadID != "" {
+		createdBeads = append(createdBeads, synthesisBeadID)
+	}
+	auditDetails := map[string]string{
+		"formula":   formulaName,
+		"review_id": reviewID,
+		"legs":      fmt.Sprintf("%d/%d dispatched", slingCount, len(f.Legs)),
+		"beads":     auditJoin(createdBeads),
+	}
+	if formulaRunPR > 0 {
+		auditDetails["pr"] = strconv.Itoa(formulaRunPR)
+	}
+	if formulaRunHeadSHA != "" {
+		auditDetails["head_sha"] = formulaRunHeadSHA
+	}
+	if formulaRunSupersedes != "" {
+		auditDetails["supersedes"] = formulaRunSupersedes
+	}
+	if formulaRunClonedFrom != "" {
+		auditDetails["cloned_from"] = formulaRunClonedFrom
+	}
+	if formulaRunRerunOf != "" {
+		auditDetails["rerun_of"] = formulaRunRerunOf
+	}
+	if needsApproval {
+		auditDetails["approval"] = "pending"
 	}
 
+	// Snapshot the resolved run so gt formula rerun can replay it
+	snap := newRunSnapshot(f, formulaName, targetRig, reviewID, convoyID, runInputs{
+		PR:                formulaRunPR,
+		HeadSHA:           formulaRunHeadSHA,
+		PRTitle:           prTitle,
+		TargetDescription: targetDescription,
+		ChangedFiles:      changedFiles,
+		LegContext:        legContext,
+	})
+	snap.RerunOf = formulaRunRerunOf
+	snap.OutputDir = outputDir
+	snap.ConcurrencyClass = f.ConcurrencyClass
+	for _, leg := range orderedLegs(f) {
+		if p, ok := legPayloads[leg.ID]; ok {
+			snap.Legs = append(snap.Legs, runSnapshotLeg{ID: leg.ID, Title: leg.Title, Needs: leg.Needs, Payload: p})
+		}
+	}
+	if synthesisBeadID != "" {
+		synDesc := f.Synthesis.Description
+		if synDesc == "" {
+			synDesc = "Synthesize findings from all legs into unified output"
+		}
+		snap.Synthesis = &runSnapshotSynthesis{Title: f.Synthesis.Title, Description: synDesc}
+	}
+	if err := saveRunSnapshot(townRoot, snap); err != nil {
+		fmt.Printf("%s %v\n", style.Dim.Render("Warning:"), err)
+	}
+	recordAudit(townRoot, targetRig, witness.AuditEntry{
+		Action:  witness.ActionFormulaRun,
+		Subject: convoyID,
+		Details: auditDetails,
+	})
+	hold.releaseQuota()
+
 	// Summary
-	fmt.Printf("\n%s Convoy dispatched!\n", style.Bold.Render("✓"))
-	fmt.Printf("  Convoy:  %s\n", convoyID)
-	fmt.Printf("  Legs:    %d dispatched\n", slingCount)
+	if needsApproval {
+		fmt.Printf("\n%s Convoy awaiting approval\n", style.Bold.Render("✓"))
+		fmt.Printf("  Convoy:  %s\n", convoyID)
+		fmt.Printf("  Legs:    %d held; approvers have been notified\n", len(queued))
+	} else {
+		fmt.Printf("\n%s Convoy dispatched!\n", style.Bold.Render("✓"))
+		fmt.Printf("  Convoy:  %s\n", convoyID)
+		fmt.Printf("  Legs:    %d dispatched\n", slingCount)
+	}
+	if queueCount > 0 {
+		fmt.Printf("  Queued:  %d (waiting for a free polecat)\n", queueCount)
+	}
+	if len(canaryBeads) > 0 {
+		fmt.Printf("  Canary:  %s (the other legs follow once they pass their output contracts)\n", strings.Join(canaryBeads, ", "))
+	}
+	if waitCount > 0 {
+		fmt.Printf("  Waiting: %d (dispatched as the legs they need complete)\n", waitCount)
+	}
+	partial := f.Synthesis != nil && f.Synthesis.Require.IsPartial()
 	if synthesisBeadID != "" {
 		fmt.Printf("  Synthesis: %s (blocked until legs complete)\n", synthesisBeadID)
+	} else if partial {
+		fmt.Printf("  Synthesis: requires %s legs\n", f.Synthesis.Require)
+	}
+	if outputDir != "" {
+		fmt.Printf("  Outputs: %s\n", outputDir)
 	}
-	fmt.Printf("\n  Track progress: gt convoy status %s\n", convoyID)
 
+	next := dispatchState{
+		ConvoyID:  convoyID,
+		ReviewID:  reviewID,
+		Rig:       targetRig,
+		OutputDir: outputDir,
+		Held:      needsApproval,
+		Queued:    queueCount,
+		Partial:   partial,
+	}
+	for _, leg := range queued {
+		if dispatched[leg.BeadID] {
+			next.FirstLeg = leg.BeadID
+			break
+		}
+	}
+	printNextSteps(os.Stdout, dispatchNextSteps(next))
+
+	return convoyID, nil
+}
+
+// createFormulaConvoyBead creates the convoy bead for a formula run, with
+// the run's --label labels.
+func createFormulaConvoyBead(townBeads, convoyID, title, description string, labels []string) error {
+	createArgs := []string{
+		"create",
+		"--type=convoy",
+		"--id=" + convoyID,
+		"--title=" + title,
+		"--description=" + description,
+	}
+	if len(labels) > 0 {
+		createArgs = append(createArgs, "--labels="+strings.Join(labels, ","))
+	}
+	if beads.NeedsForceForID(convoyID) {
+		createArgs = append(createArgs, "--force")
+	}
+
+	createCmd := exec.Command("bd", createArgs...)
+	createCmd.Dir = townBeads
+	createCmd.Stderr = os.Stderr
+	if err := cmdtrace.Run(createCmd); err != nil {
+		return fmt.Errorf("creating convoy bead: %w", err)
+	}
 	return nil
 }
 
 // formulaData holds parsed formula information
 type formulaData struct {
+	Path        string // File the formula was parsed from
+	Schema      int    // Declared file format version (0 if absent)
 	Name        string
 	Description string
 	Type        string
@@ -637,6 +1157,26 @@ type formulaData struct {
 	Synthesis   *formulaSynthesis
 	Prompts     map[string]string
 	Output      *formulaOutput
+	Env         map[string]string     // Injected into every leg's polecat
+	Agent       string                // Agent legs run with (default: rig agent)
+	Requires    []formula.Requirement // Tools checked on the dispatch target before running
+
+	// Context trimming for oversized input (see formula_context.go)
+	ContextStrategy formula.ContextStrategy
+	ContextLimit    int
+	ContextAgent    string
+
+	// Agent reply caching for --local-agent runs; zero disables it
+	CacheTTL time.Duration
+
+	// Approval holds polecat runs until gt convoy approve
+	Approval bool
+
+	// Town-wide class capping this formula's open convoys
+	ConcurrencyClass string
+
+	// How PR titles and file names are placed in prompts
+	Untrusted formula.UntrustedText
 }
 
 type formulaOutput struct {
@@ -650,257 +1190,238 @@ type formulaLeg struct {
 	Title       string
 	Focus       string
 	Description string
+	Workdir     string   // Rig-relative worktree path template
+	Branch      string   // Branch template for the leg's worktree
+	Needs       []string // Legs that must finish before this one is dispatched
+	Expect      *formula.LegExpect
+	Env         map[string]string // Injected into this leg's polecat, over the formula env
+	Runner      string            // "host" (default) or "docker"
+	Image       string            // Container image for runner = "docker"
+	Mounts      []string          // Extra container bind mounts (src:dst[:ro])
+	Agent       string            // Overrides the formula agent (set on a consensus leg's per-agent legs)
+	Consensus   *formula.Consensus
+	Retry       *formula.LegRetry
 }
 
 type formulaSynthesis struct {
 	Title       string
 	Description string
 	DependsOn   []string
+	Require     formula.SynthesisRequire
 }
 
-// findFormulaFile searches for a formula file by name
-func findFormulaFile(name string) (string, error) {
-	// Search paths in order
-	searchPaths := []string{}
+// formulaSearchPath is one directory in the formula search order.
+type formulaSearchPath struct {
+	Label string // project, rig, town, user
+	Dir   string
+}
 
-	// 1. Project .beads/formulas/
-	if cwd, err := os.Getwd(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(cwd, ".beads", "formulas"))
-	}
+// formulaExtensions are the formula file extensions, in preference order.
+var formulaExtensions = []string{".formula.toml", ".formula.json"}
 
-	// 2. Town .beads/formulas/
-	if townRoot, err := workspace.FindFromCwd(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(townRoot, ".beads", "formulas"))
-	}
+// formulaSearchPaths returns the formula directories in search order.
+func formulaSearchPaths() []formulaSearchPath {
+	var paths []formulaSearchPath
 
-	// 3. User ~/.beads/formulas/
-	if home, err := os.UserHomeDir(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(home, ".beads", "formulas"))
+	// 1. Project .beads/formulas/
+	cwd, cwdErr := os.Getwd()
+	if cwdErr == nil {
+		paths = append(paths, formulaSearchPath{"project", filepath.Join(cwd, ".beads", "formulas")})
 	}
 
-	// Try each path with common extensions
-	extensions := []string{".formula.toml", ".formula.json"}
-	for _, basePath := range searchPaths {
-		for _, ext := range extensions {
-			path := filepath.Join(basePath, name+ext)
-			if _, err := os.Stat(path); err == nil {
-				return path, nil
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		// 2. Rig <town>/<rig>/.beads/formulas/, inside a rig
+		if cwdErr == nil {
+			if rigName := detectRigFromPath(townRoot, cwd); rigName != "" {
+				paths = append(paths, formulaSearchPath{"rig", filepath.Join(townRoot, rigName, ".beads", "formulas")})
 			}
 		}
-	}
-
-	return "", fmt.Errorf("formula '%s' not found in search paths", name)
-}
 
-// parseFormulaFile parses a formula file into formulaData
-func parseFormulaFile(path string) (*formulaData, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Use simple TOML parsing for the fields we need
-	// (avoids importing the full formula package which might cause cycles)
-	f := &formulaData{
-		Prompts: make(map[string]string),
+		// 3. Town .beads/formulas/
+		paths = append(paths, formulaSearchPath{"town", filepath.Join(townRoot, ".beads", "formulas")})
 	}
 
-	content := string(data)
-
-	// Parse formula name
-	if match := extractTOMLValue(content, "formula"); match != "" {
-		f.Name = match
+	// 4. User ~/.beads/formulas/
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, formulaSearchPath{"user", filepath.Join(home, ".beads", "formulas")})
 	}
 
-	// Parse description
-	if match := extractTOMLMultiline(content, "description"); match != "" {
-		f.Description = match
-	}
+	return paths
+}
 
-	// Parse type
-	if match := extractTOMLValue(content, "type"); match != "" {
-		f.Type = match
+// formulaIncludeDirs lists the directories searched for fragments named by
+// a formula's include key, after the formula's own directory.
+func formulaIncludeDirs() []string {
+	var dirs []string
+	for _, sp := range formulaSearchPaths() {
+		dirs = append(dirs, sp.Dir)
 	}
-
-	// Parse legs (convoy formulas)
-	f.Legs = extractLegs(content)
-
-	// Parse synthesis
-	f.Synthesis = extractSynthesis(content)
-
-	// Parse prompts
-	f.Prompts = extractPrompts(content)
-
-	// Parse output config
-	f.Output = extractOutput(content)
-
-	return f, nil
+	return dirs
 }
 
-// extractTOMLValue extracts a simple quoted value from TOML
-func extractTOMLValue(content, key string) string {
-	// Match: key = "value" or key = 'value'
-	for _, line := range strings.Split(content, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, key+" =") || strings.HasPrefix(line, key+"=") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				val := strings.TrimSpace(parts[1])
-				// Remove quotes
-				if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') {
-					return val[1 : len(val)-1]
-				}
-				return val
+// findFormulaFile searches for a formula file by name
+func findFormulaFile(name string) (string, error) {
+	// Try each path with common extensions
+	for _, sp := range formulaSearchPaths() {
+		for _, ext := range formulaExtensions {
+			path := filepath.Join(sp.Dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
 			}
 		}
 	}
-	return ""
+
+	return "", formulaNotFoundError(name)
 }
 
-// extractTOMLMultiline extracts a multiline string (""" ... """)
-func extractTOMLMultiline(content, key string) string {
-	// Look for key = """
-	keyPattern := key + ` = """`
-	idx := strings.Index(content, keyPattern)
-	if idx == -1 {
-		// Try single-line
-		return extractTOMLValue(content, key)
+// formulaNotFoundError explains a failed formula lookup: the known names
+// closest to the one given and the directories that were searched.
+func formulaNotFoundError(name string) error {
+	var hint strings.Builder
+	if _, err := formula.EmbeddedFormula(name); err == nil {
+		fmt.Fprintf(&hint, "%s ships with gt but is not installed; restore it with 'gt formula reset %s'\n  ", name, name)
 	}
-
-	start := idx + len(keyPattern)
-	end := strings.Index(content[start:], `"""`)
-	if end == -1 {
-		return ""
+	hint.WriteString("Searched:")
+	for _, sp := range formulaSearchPaths() {
+		fmt.Fprintf(&hint, "\n    %s (%s)", sp.Dir, sp.Label)
 	}
-
-	return strings.TrimSpace(content[start : start+end])
+	suggestions := suggest.Nearest(name, formulaNames(), 3)
+	return fmt.Errorf("%s", suggest.FormatSuggestion("Formula", name, suggestions, hint.String()))
 }
 
-// extractLegs parses [[legs]] sections from TOML
-func extractLegs(content string) []formulaLeg {
-	var legs []formulaLeg
-
-	// Split by [[legs]]
-	sections := strings.Split(content, "[[legs]]")
-	for i, section := range sections {
-		if i == 0 {
-			continue // Skip content before first [[legs]]
-		}
-
-		// Find where this section ends (next [[ or EOF)
-		endIdx := strings.Index(section, "[[")
-		if endIdx == -1 {
-			endIdx = len(section)
-		}
-		section = section[:endIdx]
-
-		leg := formulaLeg{
-			ID:          extractTOMLValue(section, "id"),
-			Title:       extractTOMLValue(section, "title"),
-			Focus:       extractTOMLValue(section, "focus"),
-			Description: extractTOMLMultiline(section, "description"),
+// formulaNames lists every formula name in the search paths and embedded
+// in gt, without parsing the files.
+func formulaNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, sp := range formulaSearchPaths() {
+		entries, _ := os.ReadDir(sp.Dir)
+		for _, entry := range entries {
+			if name, ok := formulaFileName(entry.Name()); ok && !entry.IsDir() && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
 		}
-
-		if leg.ID != "" {
-			legs = append(legs, leg)
+	}
+	for _, m := range formula.EmbeddedCatalog() {
+		if !seen[m.Name] {
+			seen[m.Name] = true
+			names = append(names, m.Name)
 		}
 	}
-
-	return legs
+	return names
 }
 
-// extractSynthesis parses [synthesis] section from TOML
-func extractSynthesis(content string) *formulaSynthesis {
-	idx := strings.Index(content, "[synthesis]")
-	if idx == -1 {
-		return nil
+// parseFormulaFile parses a formula file into formulaData
+func parseFormulaFile(path string) (*formulaData, error) {
+	pf, err := formula.DecodeFile(path, formulaIncludeDirs()...)
+	if err == nil {
+		// gt runs convoy legs itself; bd resolves and checks the steps of
+		// the other types, which may extend formulas gt can't see
+		if pf.Type == formula.TypeConvoy {
+			err = pf.Validate()
+		} else {
+			err = pf.ValidateCommon()
+		}
 	}
-
-	section := content[idx:]
-	// Find where section ends
-	if endIdx := strings.Index(section[1:], "\n["); endIdx != -1 {
-		section = section[:endIdx+1]
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 
-	syn := &formulaSynthesis{
-		Title:       extractTOMLValue(section, "title"),
-		Description: extractTOMLMultiline(section, "description"),
+	f := &formulaData{
+		Path:             path,
+		Schema:           pf.Schema,
+		Name:             pf.Name,
+		Description:      strings.TrimSpace(pf.Description),
+		Type:             string(pf.Type),
+		Prompts:          make(map[string]string),
+		Env:              pf.Env,
+		Agent:            pf.Agent,
+		ContextStrategy:  pf.ContextStrategy,
+		ContextLimit:     pf.ContextLimit,
+		ContextAgent:     pf.ContextAgent,
+		Approval:         pf.Approval,
+		ConcurrencyClass: pf.ConcurrencyClass,
+		Untrusted: formula.UntrustedText{
+			MaxLength: pf.UntrustedMaxLength,
+			Delimit:   pf.UntrustedDelimiters,
+		},
 	}
-
-	// Parse depends_on array
-	if depsLine := extractTOMLValue(section, "depends_on"); depsLine != "" {
-		// Simple array parsing: ["a", "b", "c"]
-		depsLine = strings.Trim(depsLine, "[]")
-		for _, dep := range strings.Split(depsLine, ",") {
-			dep = strings.Trim(strings.TrimSpace(dep), `"'`)
-			if dep != "" {
-				syn.DependsOn = append(syn.DependsOn, dep)
-			}
+	if f.Requires, err = formula.ParseRequirements(pf.Requires); err != nil {
+		return nil, fmt.Errorf("%s: requires: %w", path, err)
+	}
+	if pf.CacheTTL != "" {
+		if f.CacheTTL, err = agentcache.ParseTTL(pf.CacheTTL); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
 		}
 	}
-
-	if syn.Title == "" && syn.Description == "" {
-		return nil
+	for name, prompt := range pf.Prompts {
+		f.Prompts[name] = strings.TrimSpace(prompt)
 	}
 
-	return syn
-}
-
-// extractPrompts parses [prompts] section from TOML
-func extractPrompts(content string) map[string]string {
-	prompts := make(map[string]string)
-
-	idx := strings.Index(content, "[prompts]")
-	if idx == -1 {
-		return prompts
+	for _, leg := range pf.Legs {
+		f.Legs = append(f.Legs, formulaLeg{
+			ID:          leg.ID,
+			Title:       leg.Title,
+			Focus:       leg.Focus,
+			Description: strings.TrimSpace(leg.Description),
+			Workdir:     leg.Workdir,
+			Branch:      leg.Branch,
+			Needs:       leg.Needs,
+			Expect:      leg.Expect,
+			Env:         leg.Env,
+			Runner:      leg.Runner,
+			Image:       leg.Image,
+			Mounts:      leg.Mounts,
+			Consensus:   leg.Consensus,
+			Retry:       leg.Retry,
+		})
 	}
 
-	section := content[idx:]
-	// Find where section ends
-	if endIdx := strings.Index(section[1:], "\n["); endIdx != -1 {
-		section = section[:endIdx+1]
+	if syn := pf.Synthesis; syn != nil && (syn.Title != "" || syn.Description != "") {
+		f.Synthesis = &formulaSynthesis{
+			Title:       syn.Title,
+			Description: strings.TrimSpace(syn.Description),
+			DependsOn:   syn.DependsOn,
+			Require:     syn.Require,
+		}
 	}
 
-	// Extract base prompt
-	if base := extractTOMLMultiline(section, "base"); base != "" {
-		prompts["base"] = base
+	if out := pf.Output; out != nil && (out.Directory != "" || out.LegPattern != "" || out.Synthesis != "") {
+		if out.Synthesis != "" && !filepath.IsLocal(out.Synthesis) {
+			return nil, fmt.Errorf("%s: output synthesis %q must be a relative path inside the output directory", path, out.Synthesis)
+		}
+		f.Output = &formulaOutput{
+			Directory:  out.Directory,
+			LegPattern: out.LegPattern,
+			Synthesis:  out.Synthesis,
+		}
 	}
 
-	return prompts
+	return f, nil
 }
 
-// extractOutput parses [output] section from TOML
-func extractOutput(content string) *formulaOutput {
-	idx := strings.Index(content, "[output]")
-	if idx == -1 {
-		return nil
-	}
-
-	section := content[idx:]
-	// Find where section ends (next [ that isn't part of output)
-	if endIdx := strings.Index(section[1:], "\n["); endIdx != -1 {
-		section = section[:endIdx+1]
-	}
-
-	out := &formulaOutput{
-		Directory:  extractTOMLValue(section, "directory"),
-		LegPattern: extractTOMLValue(section, "leg_pattern"),
-		Synthesis:  extractTOMLValue(section, "synthesis"),
-	}
-
-	if out.Directory == "" && out.LegPattern == "" && out.Synthesis == "" {
-		return nil
+// warnFormulaSchema notes on stderr when a formula file predates the
+// current schema. gt still reads it, through compatibility adapters.
+func warnFormulaSchema(f *formulaData) {
+	if outdated, _ := formula.CheckSchema(f.Schema); !outdated {
+		return
 	}
-
-	return out
+	fmt.Fprintf(os.Stderr, "%s %s uses formula schema %d (current: %d); update it and set 'schema = %d'\n",
+		style.Warning.Render("⚠"), f.Path, max(f.Schema, 1), formula.SchemaVersion, formula.SchemaVersion)
 }
 
-// renderTemplate renders a Go text/template with the given context map
+// renderTemplate renders a Go text/template with the given context map.
+// It refuses to put untrusted context containing shell metacharacters into
+// a shell command (see checkShellInterpolation).
 func renderTemplate(tmplText string, ctx map[string]interface{}) (string, error) {
-	tmpl, err := template.New("prompt").Parse(tmplText)
+	tmpl, err := template.New("prompt").Funcs(templateFuncs).Parse(rewriteUpstreamRefs(tmplText))
 	if err != nil {
 		return "", fmt.Errorf("parsing template: %w", err)
 	}
+	if err := checkShellInterpolation(tmplText, ctx); err != nil {
+		return "", err
+	}
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, ctx); err != nil {
 		return "", fmt.Errorf("executing template: %w", err)
@@ -920,53 +1441,26 @@ func renderTemplateOrDefault(tmplText string, ctx map[string]interface{}, defaul
 	return result
 }
 
-// fetchPRInfo fetches PR title and changed files from GitHub using gh CLI
-func fetchPRInfo(prNumber int) (string, []map[string]interface{}) {
-	var prTitle string
-	var changedFiles []map[string]interface{}
-
-	// Get PR title
-	titleCmd := exec.Command("gh", "pr", "view", fmt.Sprintf("%d", prNumber), "--json", "title", "--jq", ".title")
-	titleOut, err := titleCmd.Output()
-	if err == nil {
-		prTitle = strings.TrimSpace(string(titleOut))
+// fetchPRInfo fetches PR title and changed files from the rig's source
+// host. It fails rather than returning empty context, so legs never review
+// nothing because the host rate limited the fetch.
+func fetchPRInfo(p scm.Provider, prNumber int) (string, []map[string]interface{}, error) {
+	pr, err := p.PR(context.Background(), prNumber)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetching PR #%d: %w", prNumber, err)
 	}
-
-	// Get changed files with stats
-	filesCmd := exec.Command("gh", "pr", "view", fmt.Sprintf("%d", prNumber), "--json", "files", "--jq", ".files[] | \"\\(.path) \\(.additions) \\(.deletions)\"")
-	filesOut, err := filesCmd.Output()
-	if err == nil {
-		for _, line := range strings.Split(strings.TrimSpace(string(filesOut)), "\n") {
-			if line == "" {
-				continue
-			}
-			parts := strings.Fields(line)
-			if len(parts) >= 3 {
-				additions, err := strconv.Atoi(parts[1])
-				if err != nil {
-					continue
-				}
-				deletions, err := strconv.Atoi(parts[2])
-				if err != nil {
-					continue
-				}
-				changedFiles = append(changedFiles, map[string]interface{}{
-					"path":      parts[0],
-					"additions": additions,
-					"deletions": deletions,
-				})
-			}
-		}
+	noteLocalPR(p, prNumber)
+	// Strip control characters the author could use to rewrite the
+	// terminal or smuggle line breaks into prompts.
+	var changedFiles []map[string]interface{}
+	for _, f := range pr.Files {
+		changedFiles = append(changedFiles, map[string]interface{}{
+			"path":      formula.SanitizeLine(f.Path, formula.DefaultPathLimit),
+			"additions": f.Additions,
+			"deletions": f.Deletions,
+		})
 	}
-
-	return prTitle, changedFiles
-}
-
-// generateFormulaShortID generates a short random ID (5 lowercase chars)
-func generateFormulaShortID() string {
-	b := make([]byte, 3)
-	_, _ = rand.Read(b)
-	return strings.ToLower(base32.StdEncoding.EncodeToString(b)[:5])
+	return formula.SanitizeLine(pr.Title, formula.DefaultTitleLimit), changedFiles, nil
 }
 
 // runFormulaCreate creates a new formula template
@@ -974,21 +1468,9 @@ func runFormulaCreate(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
 
 	// Find or create formulas directory
-	formulasDir := ".beads/formulas"
-
-	// Check if we're in a beads-enabled directory
-	if _, err := os.Stat(".beads"); os.IsNotExist(err) {
-		// Try user formulas directory
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("cannot find home directory: %w", err)
-		}
-		formulasDir = filepath.Join(home, ".beads", "formulas")
-	}
-
-	// Ensure directory exists
-	if err := os.MkdirAll(formulasDir, 0755); err != nil {
-		return fmt.Errorf("creating formulas directory: %w", err)
+	formulasDir, err := newFormulaDir()
+	if err != nil {
+		return err
 	}
 
 	// Generate filename
@@ -999,6 +1481,10 @@ func runFormulaCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("formula already exists: %s", filename)
 	}
 
+	if formulaCreateInteractive {
+		return runFormulaCreateWizard(formulaName, filename)
+	}
+
 	// Generate template based on type
 	var template string
 	switch formulaCreateType {
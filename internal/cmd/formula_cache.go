@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentcache"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Agent cache flags
+var formulaRunNoCache bool
+
+func init() {
+	formulaRunCmd.Flags().BoolVar(&formulaRunNoCache, "no-cache", false, "With --local-agent, call the agent even when the formula's cache_ttl has a cached reply (fresh replies are still cached)")
+}
+
+// historyAgentCache is recorded with the command in gt history once a
+// cached run finishes.
+var historyAgentCache agentcache.Stats
+
+// localAgentCache answers a local run's agent calls from the town's agent
+// reply cache, for formulas that set cache_ttl. A nil cache calls the agent
+// every time.
+type localAgentCache struct {
+	cache   *agentcache.Cache
+	refresh bool // --no-cache: skip lookups but store fresh replies
+
+	// scrub hashes out what differs between runs of the same input (the
+	// review ID and output directory), so unchanged input hits the cache.
+	scrub *strings.Replacer
+}
+
+// newLocalAgentCache returns the cache for a local run, or nil if the
+// formula doesn't opt in or the run is outside a town.
+func newLocalAgentCache(f *formulaData, townRoot string, inTown bool, outputDir, reviewID string) *localAgentCache {
+	if f.CacheTTL <= 0 || !inTown {
+		return nil
+	}
+	dir := agentcache.Dir(townRoot)
+	agentcache.Prune(dir, time.Now())
+	return &localAgentCache{
+		cache:   agentcache.New(dir, f.CacheTTL),
+		refresh: formulaRunNoCache,
+		scrub:   strings.NewReplacer(outputDir, "<output_dir>", reviewID, "<review_id>"),
+	}
+}
+
+// run sends a prompt to the agent unless an unexpired reply to the same
+// prompt is cached. It reports whether the reply came from the cache.
+func (c *localAgentCache) run(townRoot, rigPath, agent, prompt string) (string, bool, error) {
+	if c == nil {
+		reply, err := runAgentOneShot(townRoot, rigPath, agent, prompt)
+		return reply, false, err
+	}
+
+	// Key on the resolved agent, so a rig default change misses
+	name := agent
+	if rc, resolved, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, agent); err == nil {
+		name = resolved + "\x00" + strings.Join(append([]string{rc.Command}, rc.Args...), " ")
+	}
+	key := c.scrub.Replace(prompt)
+	if !c.refresh {
+		if reply, ok := c.cache.Get(name, key); ok {
+			return reply, true, nil
+		}
+	}
+	reply, err := runAgentOneShot(townRoot, rigPath, agent, prompt)
+	if err == nil {
+		_ = c.cache.Put(name, key, reply)
+	}
+	return reply, false, err
+}
+
+// summary describes the run's cache use for the run summary.
+func (c *localAgentCache) summary() string {
+	if c.refresh {
+		return "bypassed (--no-cache); replies refreshed"
+	}
+	s := c.cache.Stats()
+	return fmt.Sprintf("%d hit(s), %d miss(es)", s.Hits, s.Misses)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// formulaRunCanary is the number of legs gt formula run dispatches ahead of
+// the rest (--canary).
+var formulaRunCanary int
+
+// canaryPerm shuffles leg indexes when picking canaries; tests replace it.
+var canaryPerm = rand.Perm
+
+func init() {
+	formulaRunCmd.Flags().IntVar(&formulaRunCanary, "canary", 0, "Dispatch this many randomly chosen legs first; the rest follow once they pass their output contracts")
+}
+
+// pickCanaryLegs returns n randomly chosen legs of f to run ahead of the
+// others, in f's leg order. Only legs without needs can be canaries. It
+// returns nil when n is not positive or the canaries would leave no leg
+// to hold back.
+func pickCanaryLegs(f *formulaData, n int) []string {
+	if n <= 0 || n >= len(f.Legs) {
+		return nil
+	}
+	var eligible []int
+	for i, leg := range f.Legs {
+		if len(leg.Needs) == 0 {
+			eligible = append(eligible, i)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil
+	}
+	n = min(n, len(eligible))
+	picked := make([]int, 0, n)
+	for _, j := range canaryPerm(len(eligible))[:n] {
+		picked = append(picked, eligible[j])
+	}
+	sort.Ints(picked)
+	ids := make([]string, 0, n)
+	for _, i := range picked {
+		ids = append(ids, f.Legs[i].ID)
+	}
+	return ids
+}
+
+// describeCanary explains what gt formula run --canary will do with f, for
+// dry runs and the dispatch summary.
+func describeCanary(f *formulaData, canaries []string) string {
+	if len(canaries) == 0 {
+		return fmt.Sprintf("--canary %d covers every leg that can start; dispatching all legs", formulaRunCanary)
+	}
+	return fmt.Sprintf("%s first; the other %d leg(s) follow once the canaries pass", strings.Join(canaries, ", "), len(f.Legs)-len(canaries))
+}
+
+// failedCanary returns the first canary leg bead that closed with its
+// output contract violated, or "" if none did.
+func failedCanary(townBeads string, canaries []string) string {
+	b := beads.New(townBeads)
+	for _, id := range canaries {
+		issue, err := b.Show(id)
+		if err != nil {
+			fmt.Printf("%s Failed to check canary %s: %v\n", style.Dim.Render("Warning:"), id, err)
+			continue
+		}
+		if slices.Contains(issue.Labels, LegFailedLabel) {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPickCanaryLegs(t *testing.T) {
+	defer func(orig func(int) []int) { canaryPerm = orig }(canaryPerm)
+	canaryPerm = func(n int) []int {
+		perm := make([]int, n)
+		for i := range perm {
+			perm[i] = n - 1 - i
+		}
+		return perm
+	}
+
+	f := &formulaData{Legs: []formulaLeg{
+		{ID: "a"},
+		{ID: "b"},
+		{ID: "c", Needs: []string{"a"}},
+		{ID: "d"},
+	}}
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, ""},
+		{1, "d"},
+		{2, "b,d"},
+		{3, "a,b,d"}, // c has needs, so it can't be a canary
+		{4, ""},      // nothing left to hold back
+	}
+	for _, tt := range tests {
+		if got := strings.Join(pickCanaryLegs(f, tt.n), ","); got != tt.want {
+			t.Errorf("pickCanaryLegs(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+
+	allNeed := &formulaData{Legs: []formulaLeg{{ID: "a", Needs: []string{"b"}}, {ID: "b", Needs: []string{"a"}}}}
+	if got := pickCanaryLegs(allNeed, 1); got != nil {
+		t.Errorf("pickCanaryLegs() = %v with no leg free to start, want nil", got)
+	}
+}
+
+func TestExecuteConvoyFormulaLocalCanaryFails(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell script agent stubs")
+	}
+
+	binDir := t.TempDir()
+	calls := filepath.Join(binDir, "calls")
+	script := "#!/bin/sh\necho run >> " + calls + "\necho 'bad config' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	if err := config.SaveAgentRegistry(config.DefaultAgentRegistryPath(workDir), &config.AgentRegistry{
+		Version: config.CurrentAgentRegistryVersion,
+		Order:   []string{"claude"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &formulaData{
+		Type:  "convoy",
+		Agent: "claude",
+		Legs:  []formulaLeg{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}, {ID: "c", Title: "C"}},
+	}
+	formulaRunOutput = filepath.Join(workDir, "report.md")
+	formulaRunCanary = 1
+	formulaRunParallel = 3
+	defer func() { formulaRunOutput, formulaRunCanary, formulaRunParallel = "", 0, 1 }()
+
+	_ = executeConvoyFormulaLocal(f, "review", "gastown", runHold{})
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "run"); n != 1 {
+		t.Errorf("agent ran %d times, want only the canary to run", n)
+	}
+}
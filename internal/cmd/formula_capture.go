@@ -0,0 +1,613 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Formula capture flags
+var (
+	formulaCaptureName   string
+	formulaCaptureDryRun bool
+	formulaCaptureForce  bool
+)
+
+var formulaCaptureCmd = &cobra.Command{
+	Use:   "capture <convoy-id>",
+	Short: "Create a formula from a convoy run",
+	Long: `Reverse-engineer a reusable formula from a convoy run.
+
+Reads the run's leg beads and the context payloads they were slung with:
+leg titles, descriptions, needs, env, worktrees, containers, output
+contracts, and rendered prompts. Values specific to the run (review ID,
+output paths, leg IDs and titles, the PR) are turned back into template
+references. When every leg's prompt renders from one shared template it
+becomes the base prompt, and the one span that differs per leg becomes
+the leg's focus; otherwise each leg keeps its own prompt.
+
+Use this to keep an ad-hoc or hand-tweaked run as a formula. Review the
+result before relying on it.
+
+Examples:
+  gt formula capture hq-cv-abc --name my-review
+  gt formula capture hq-cv-abc --name my-review --dry-run   # Print, don't write`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaCapture,
+}
+
+func init() {
+	formulaCaptureCmd.Flags().StringVar(&formulaCaptureName, "name", "", "Name of the new formula (required)")
+	formulaCaptureCmd.Flags().BoolVar(&formulaCaptureDryRun, "dry-run", false, "Print the formula instead of writing it")
+	formulaCaptureCmd.Flags().BoolVar(&formulaCaptureForce, "force", false, "Overwrite an existing formula file")
+	_ = formulaCaptureCmd.MarkFlagRequired("name")
+
+	formulaCmd.AddCommand(formulaCaptureCmd)
+}
+
+// capturedLeg is one leg of a recorded run: its bead and, if the run saved
+// one, the payload it was slung with.
+type capturedLeg struct {
+	Bead    *beads.Issue
+	Payload *slingPayload
+}
+
+// capturedFormula is a formula reconstructed from a run.
+type capturedFormula struct {
+	*formulaData
+	Source string   // Convoy the formula was captured from
+	FromPR bool     // The run targeted a PR, so the formula takes --pr
+	Notes  []string // What could not be reconstructed
+}
+
+// legNoteMarkers start the sections gt formula run appends after a leg's
+// rendered prompt.
+var legNoteMarkers = []string{"\n\n---\nContext:\n", "\n\n---\nWorkspace:\n", "\n\n---\nOutput contract:\n"}
+
+const basePromptMarker = "\n\n---\nBase Prompt:\n"
+
+// runEnvKeys are set per run by gt formula run, not by the formula.
+var runEnvKeys = map[string]bool{"GT_CONVOY": true, "GT_REVIEW_ID": true, "GT_LEG": true}
+
+func runFormulaCapture(cmd *cobra.Command, args []string) error {
+	if !wizardIDPattern.MatchString(formulaCaptureName) {
+		return fmt.Errorf("invalid formula name %q: use letters, digits, '-' and '_'", formulaCaptureName)
+	}
+	meta, err := getConvoyMeta(args[0])
+	if err != nil {
+		return err
+	}
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	townRoot := filepath.Dir(townBeads)
+
+	var legs []capturedLeg
+	var synthesis *beads.Issue
+	issues, err := beads.New(townBeads).ShowMultiple(meta.LegIssues)
+	if err != nil {
+		return fmt.Errorf("reading convoy legs: %w", err)
+	}
+	for _, id := range meta.LegIssues {
+		issue := issues[id]
+		if issue == nil {
+			continue
+		}
+		if strings.HasPrefix(id, "hq-syn-") {
+			synthesis = issue
+			continue
+		}
+		leg := capturedLeg{Bead: issue}
+		if p, err := loadSlingPayload(slingPayloadPath(townRoot, id)); err == nil {
+			leg.Payload = p
+		}
+		legs = append(legs, leg)
+	}
+	if len(legs) == 0 {
+		return fmt.Errorf("convoy %s has no legs to capture", meta.ID)
+	}
+
+	cf := captureFormula(formulaCaptureName, meta, legs, synthesis)
+	if cf.Synthesis != nil && cf.Output != nil {
+		cf.Output.Synthesis = guessSynthesisFile(legs)
+	}
+	content := capturedFormulaTOML(cf)
+	if _, err := formula.Parse([]byte(content)); err != nil {
+		return fmt.Errorf("captured formula is invalid: %w", err)
+	}
+
+	if meta.Status != "closed" {
+		fmt.Fprintf(os.Stderr, "%s %s is still %s; capturing what has been recorded so far\n", style.Warning.Render("⚠"), meta.ID, meta.Status)
+	}
+	for _, note := range cf.Notes {
+		fmt.Fprintf(os.Stderr, "%s %s\n", style.Dim.Render("Note:"), note)
+	}
+	if formulaCaptureDryRun {
+		fmt.Print(content)
+		return nil
+	}
+
+	formulasDir, err := newFormulaDir()
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(formulasDir, formulaCaptureName+".formula.toml")
+	if _, err := os.Stat(filename); err == nil && !formulaCaptureForce {
+		return fmt.Errorf("formula already exists: %s (use --force to overwrite)", filename)
+	}
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		return fmt.Errorf("writing formula file: %w", err)
+	}
+	fmt.Printf("%s Captured %s as formula: %s\n", style.Bold.Render("✓"), meta.ID, filename)
+	fmt.Printf("  Preview a run: gt formula run %s --dry-run\n", formulaCaptureName)
+	return nil
+}
+
+// captureFormula reconstructs a convoy formula from a run's legs.
+func captureFormula(name string, meta *ConvoyMeta, legs []capturedLeg, synthesis *beads.Issue) *capturedFormula {
+	cf := &capturedFormula{
+		formulaData: &formulaData{Name: name, Type: "convoy", Prompts: make(map[string]string)},
+		Source:      meta.ID,
+		FromPR:      meta.PR > 0,
+	}
+	cf.Description = fmt.Sprintf("Captured from %s (%s).", meta.ID, meta.Title)
+	if meta.Formula != "" {
+		cf.Description = fmt.Sprintf("Captured from %s, a run of %s.", meta.ID, meta.Formula)
+	}
+
+	// Leg IDs: recorded in the payload env, else derived from the title
+	legIDs := make(map[string]string, len(legs)) // bead ID -> leg ID
+	used := make(map[string]bool)
+	for _, l := range legs {
+		id := ""
+		if l.Payload != nil {
+			id = l.Payload.Env["GT_LEG"]
+		}
+		if id == "" {
+			id = legIDFromTitle(l.Bead.Title)
+		}
+		for base, n := id, 2; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[id] = true
+		legIDs[l.Bead.ID] = id
+	}
+
+	var rendered []string // Per-leg rendered base prompt
+	var values []map[string]string
+	var outputs []string
+	for _, l := range legs {
+		leg := formulaLeg{ID: legIDs[l.Bead.ID], Title: l.Bead.Title}
+		prompt := l.Bead.Description
+		outputPath := ""
+		if p := l.Payload; p != nil {
+			if p.Subject != "" {
+				leg.Title = p.Subject
+			}
+			prompt = p.Prompt
+			outputPath = p.OutputPath
+			for _, need := range p.Needs {
+				if id, ok := legIDs[need]; ok {
+					leg.Needs = append(leg.Needs, id)
+				}
+			}
+			for k, v := range p.Env {
+				if runEnvKeys[k] {
+					continue
+				}
+				if leg.Env == nil {
+					leg.Env = make(map[string]string)
+				}
+				leg.Env[k] = v
+			}
+			if p.Agent != "" && cf.Agent == "" {
+				cf.Agent = p.Agent
+			}
+			if p.Container != nil {
+				leg.Runner = formula.RunnerDocker
+				leg.Image = p.Container.Image
+				leg.Mounts = p.Container.Mounts
+			}
+		}
+
+		description, base := splitLegPrompt(prompt)
+		if l.Payload != nil && l.Payload.Args != "" {
+			description = l.Payload.Args
+		}
+		leg.Description = description
+
+		v := map[string]string{
+			"{{.output_path}}":        outputPath,
+			"{{.leg.description}}":    leg.Description,
+			"{{.leg.title}}":          leg.Title,
+			"{{.leg.id}}":             leg.ID,
+			"{{.review_id}}":          meta.ReviewID,
+			"{{.formula_name}}":       meta.Formula,
+			"{{.target_description}}": targetDescriptionFor(meta.PR),
+		}
+		if l.Payload != nil {
+			leg.Workdir = templatize(l.Payload.Workdir, v)
+			leg.Branch = templatize(l.Payload.Branch, v)
+			if e := l.Payload.Expect; e != nil {
+				expect := *e
+				expect.Files = nil
+				for _, file := range e.Files {
+					expect.Files = append(expect.Files, templatize(file, v))
+				}
+				leg.Expect = &expect
+			}
+		}
+
+		cf.Legs = append(cf.Legs, leg)
+		rendered = append(rendered, base)
+		values = append(values, v)
+		outputs = append(outputs, outputPath)
+	}
+
+	cf.captureOutput(meta.ReviewID, outputs)
+	cf.captureBasePrompt(rendered, values)
+
+	if synthesis != nil {
+		cf.Synthesis = &formulaSynthesis{Title: synthesis.Title, Description: synthesis.Description}
+		for _, leg := range cf.Legs {
+			cf.Synthesis.DependsOn = append(cf.Synthesis.DependsOn, leg.ID)
+		}
+	}
+	return cf
+}
+
+// captureOutput recovers the [output] layout from the legs' output paths.
+func (cf *capturedFormula) captureOutput(reviewID string, outputs []string) {
+	dir, pattern := "", ""
+	for i, path := range outputs {
+		if path == "" {
+			continue
+		}
+		legDir := filepath.Dir(path)
+		legPattern := templatize(filepath.Base(path), map[string]string{"{{.leg.id}}": cf.Legs[i].ID})
+		if dir == "" {
+			dir, pattern = legDir, legPattern
+			continue
+		}
+		if legDir != dir || legPattern != pattern {
+			cf.Notes = append(cf.Notes, "legs wrote to differently shaped output paths; using the first leg's layout")
+			break
+		}
+	}
+	if dir == "" {
+		return
+	}
+	cf.Output = &formulaOutput{
+		Directory:  templatize(dir, map[string]string{"{{.review_id}}": reviewID}),
+		LegPattern: pattern,
+	}
+}
+
+// captureBasePrompt turns the legs' rendered base prompts back into one
+// template. If they differ in exactly one span per leg, that span becomes
+// the leg's focus. Otherwise each leg keeps its own rendered prompt.
+func (cf *capturedFormula) captureBasePrompt(rendered []string, values []map[string]string) {
+	templated := make([]string, len(rendered))
+	var have int
+	for i, r := range rendered {
+		if r != "" {
+			templated[i] = templatize(r, values[i])
+			have++
+		}
+	}
+	if have == 0 {
+		return
+	}
+	if have == len(rendered) {
+		if base, ok := sharedTemplate(templated, cf.Legs); ok {
+			cf.Prompts["base"] = base
+			return
+		}
+	}
+
+	cf.Notes = append(cf.Notes, "leg prompts do not share one template; each leg keeps its full prompt")
+	for i := range cf.Legs {
+		if rendered[i] != "" {
+			cf.Legs[i].Description = strings.TrimRight(cf.Legs[i].Description, "\n") + basePromptMarker + rendered[i]
+		}
+	}
+}
+
+// sharedTemplate returns the template every leg's prompt renders from,
+// setting each leg's focus to the span where the prompts differ.
+func sharedTemplate(templated []string, legs []formulaLeg) (string, bool) {
+	same := true
+	for _, t := range templated[1:] {
+		if t != templated[0] {
+			same = false
+			break
+		}
+	}
+	if same {
+		return templated[0], true
+	}
+
+	prefix, suffix := templated[0], templated[0]
+	for _, t := range templated[1:] {
+		prefix = commonPrefix(prefix, t)
+		suffix = commonSuffix(suffix, t)
+	}
+	// Don't split a word: back the shared text off to a word boundary.
+	prefix = strings.TrimRightFunc(prefix, isWordRune)
+	suffix = strings.TrimLeftFunc(suffix, isWordRune)
+
+	for i, t := range templated {
+		if len(prefix)+len(suffix) > len(t) {
+			return "", false
+		}
+		focus := t[len(prefix) : len(t)-len(suffix)]
+		if focus == "" || strings.Contains(focus, "\n") || strings.Contains(focus, "{{") {
+			return "", false
+		}
+		legs[i].Focus = focus
+	}
+	return prefix + "{{.leg.focus}}" + suffix, true
+}
+
+// splitLegPrompt splits a leg's recorded prompt into its description and
+// its rendered base prompt, dropping the notes gt formula run appends.
+func splitLegPrompt(prompt string) (description, base string) {
+	for _, marker := range legNoteMarkers {
+		if i := strings.Index(prompt, marker); i != -1 {
+			prompt = prompt[:i]
+		}
+	}
+	description, base, _ = strings.Cut(prompt, basePromptMarker)
+	return description, base
+}
+
+// templatize replaces rendered values in text with the template references
+// that produce them, longest values first, matching whole words only.
+func templatize(text string, values map[string]string) string {
+	refs := make([]string, 0, len(values))
+	for ref, value := range values {
+		if value != "" {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if len(values[refs[i]]) != len(values[refs[j]]) {
+			return len(values[refs[i]]) > len(values[refs[j]])
+		}
+		return refs[i] < refs[j]
+	})
+	for _, ref := range refs {
+		text = replaceWord(text, values[ref], ref)
+	}
+	return text
+}
+
+// replaceWord replaces occurrences of old that are not part of a longer
+// word or an existing template reference.
+func replaceWord(text, old, replacement string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(text, old)
+		if i == -1 {
+			b.WriteString(text)
+			return b.String()
+		}
+		end := i + len(old)
+		inAction := strings.LastIndex(text[:i], "{{") > strings.LastIndex(text[:i], "}}")
+		boundary := (i == 0 || !isWordRune(rune(text[i-1])) || !isWordRune(rune(old[0]))) &&
+			(end == len(text) || !isWordRune(rune(text[end])) || !isWordRune(rune(old[len(old)-1])))
+		if boundary && !inAction {
+			b.WriteString(text[:i])
+			b.WriteString(replacement)
+		} else {
+			b.WriteString(text[:end])
+		}
+		text = text[end:]
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func commonPrefix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
+
+func commonSuffix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return a[len(a)-n:]
+}
+
+var nonIDChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// legIDFromTitle derives a leg ID from a bead title.
+func legIDFromTitle(title string) string {
+	id := strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if id == "" {
+		return "leg"
+	}
+	return id
+}
+
+// targetDescriptionFor mirrors the target description gt formula run
+// renders for a run.
+func targetDescriptionFor(pr int) string {
+	if pr > 0 {
+		return fmt.Sprintf("PR #%d", pr)
+	}
+	return "local files"
+}
+
+// guessSynthesisFile returns the file in the run's output directory that no
+// leg wrote, if there is exactly one, else "synthesis.md".
+func guessSynthesisFile(legs []capturedLeg) string {
+	legOutputs := make(map[string]bool)
+	dir := ""
+	for _, l := range legs {
+		if l.Payload != nil && l.Payload.OutputPath != "" {
+			legOutputs[filepath.Base(l.Payload.OutputPath)] = true
+			dir = filepath.Dir(l.Payload.OutputPath)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	var others []string
+	for _, e := range entries {
+		if !e.IsDir() && !legOutputs[e.Name()] {
+			others = append(others, e.Name())
+		}
+	}
+	if dir != "" && len(others) == 1 {
+		return others[0]
+	}
+	return "synthesis.md"
+}
+
+// newFormulaDir returns the directory new formulas are written to: the
+// project's .beads/formulas if there is a .beads here, else the user's.
+func newFormulaDir() (string, error) {
+	formulasDir := ".beads/formulas"
+	if _, err := os.Stat(".beads"); os.IsNotExist(err) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot find home directory: %w", err)
+		}
+		formulasDir = filepath.Join(home, ".beads", "formulas")
+	}
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		return "", fmt.Errorf("creating formulas directory: %w", err)
+	}
+	return formulasDir, nil
+}
+
+// capturedFormulaTOML renders a captured formula as formula TOML.
+func capturedFormulaTOML(cf *capturedFormula) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Formula: %s\n# Captured from convoy %s by gt formula capture\n\n", cf.Name, cf.Source)
+	fmt.Fprintf(&b, "description = %s\n", tomlString(cf.Description))
+	fmt.Fprintf(&b, "formula = %s\ntype = \"convoy\"\nversion = 1\n", tomlString(cf.Name))
+	if cf.Agent != "" {
+		fmt.Fprintf(&b, "agent = %s\n", tomlString(cf.Agent))
+	}
+
+	if cf.FromPR {
+		b.WriteString("\n[inputs]\n[inputs.pr]\ndescription = \"Pull request number\"\ntype = \"number\"\nrequired = true\n")
+	}
+	if base, ok := cf.Prompts["base"]; ok {
+		fmt.Fprintf(&b, "\n[prompts]\nbase = %s\n", tomlString(base))
+	}
+	if cf.Output != nil {
+		fmt.Fprintf(&b, "\n[output]\ndirectory = %s\nleg_pattern = %s\n", tomlString(cf.Output.Directory), tomlString(cf.Output.LegPattern))
+		if cf.Output.Synthesis != "" {
+			fmt.Fprintf(&b, "synthesis = %s\n", tomlString(cf.Output.Synthesis))
+		}
+	}
+
+	for _, leg := range cf.Legs {
+		fmt.Fprintf(&b, "\n[[legs]]\nid = %s\ntitle = %s\n", tomlString(leg.ID), tomlString(leg.Title))
+		if leg.Focus != "" {
+			fmt.Fprintf(&b, "focus = %s\n", tomlString(leg.Focus))
+		}
+		if len(leg.Needs) > 0 {
+			fmt.Fprintf(&b, "needs = %s\n", tomlStringArray(leg.Needs))
+		}
+		if leg.Workdir != "" {
+			fmt.Fprintf(&b, "workdir = %s\n", tomlString(leg.Workdir))
+		}
+		if leg.Branch != "" {
+			fmt.Fprintf(&b, "branch = %s\n", tomlString(leg.Branch))
+		}
+		if leg.Runner != "" {
+			fmt.Fprintf(&b, "runner = %s\nimage = %s\n", tomlString(leg.Runner), tomlString(leg.Image))
+			if len(leg.Mounts) > 0 {
+				fmt.Fprintf(&b, "mounts = %s\n", tomlStringArray(leg.Mounts))
+			}
+		}
+		if e := leg.Expect; e != nil {
+			if len(e.Files) > 0 {
+				fmt.Fprintf(&b, "expect.files = %s\n", tomlStringArray(e.Files))
+			}
+			if e.MinBytes > 0 {
+				fmt.Fprintf(&b, "expect.min_bytes = %d\n", e.MinBytes)
+			}
+			if e.JSONSchema != "" {
+				fmt.Fprintf(&b, "expect.json_schema = %s\n", tomlString(e.JSONSchema))
+			}
+		}
+		keys := make([]string, 0, len(leg.Env))
+		for k := range leg.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "env.%s = %s\n", k, tomlString(leg.Env[k]))
+		}
+		fmt.Fprintf(&b, "description = %s\n", tomlString(leg.Description))
+	}
+
+	if s := cf.Synthesis; s != nil {
+		fmt.Fprintf(&b, "\n[synthesis]\ntitle = %s\ndescription = %s\ndepends_on = %s\n",
+			tomlString(s.Title), tomlString(s.Description), tomlStringArray(s.DependsOn))
+	}
+	return b.String()
+}
+
+// tomlString quotes s as a TOML basic string, multi-line if it spans lines.
+func tomlString(s string) string {
+	var b strings.Builder
+	multiline := strings.Contains(s, "\n")
+	if multiline {
+		b.WriteString("\"\"\"\n")
+	} else {
+		b.WriteString(`"`)
+	}
+	quotes := 0 // Consecutive quotes written raw in a multi-line string
+	for _, r := range s {
+		if r != '"' {
+			quotes = 0
+		}
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '"' && multiline && quotes < 2:
+			b.WriteRune(r)
+			quotes++
+		case r == '"':
+			b.WriteString(`\"`)
+			quotes = 0
+		case r == '\n' && multiline:
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if multiline {
+		// Values from multi-line strings end with the newline before the
+		// closing delimiter; keep the delimiter on its own line only then.
+		b.WriteString(`"""`)
+	} else {
+		b.WriteString(`"`)
+	}
+	return b.String()
+}
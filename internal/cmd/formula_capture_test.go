@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// recordRun renders legs the way gt formula run does and returns what the
+// run leaves behind: leg beads and their payloads.
+func recordRun(t *testing.T, f *formulaData, reviewID string, pr int) []capturedLeg {
+	t.Helper()
+	outputDir := ".reviews/" + reviewID
+	var legs []capturedLeg
+	beadIDs := make(map[string]string)
+	for i, leg := range f.Legs {
+		beadID := "hq-leg-" + string(rune('a'+i))
+		beadIDs[leg.ID] = beadID
+		ctx := legPromptContext("code-review", leg, reviewID, targetDescriptionFor(pr), pr, "", nil)
+		addLegOutputContext(f, ctx, leg, outputDir)
+		desc := renderLegDescription(f, leg, ctx)
+		outputPath, _ := ctx["output_path"].(string)
+
+		p := &slingPayload{
+			BeadID:     beadID,
+			ConvoyID:   "hq-cv-run",
+			Subject:    leg.Title,
+			Args:       leg.Description,
+			Prompt:     desc + "\n\n---\nOutput contract:\nWrite it.",
+			OutputPath: outputPath,
+			Env:        map[string]string{"GT_LEG": leg.ID, "GT_CONVOY": "hq-cv-run", "TOKEN": "$secret:gh"},
+		}
+		for _, need := range leg.Needs {
+			p.Needs = append(p.Needs, beadIDs[need])
+		}
+		legs = append(legs, capturedLeg{
+			Bead:    &beads.Issue{ID: beadID, Title: leg.Title, Description: desc},
+			Payload: p,
+		})
+	}
+	return legs
+}
+
+func TestCaptureFormulaRoundTrip(t *testing.T) {
+	base := "Review {{.target_description}} for {{.leg.focus}}.\n\n## {{.leg.title}} ({{.leg.id}})\n{{.leg.description}}\n\nWrite to {{.output_path}}.\n"
+	f := &formulaData{
+		Prompts: map[string]string{"base": base},
+		Output:  &formulaOutput{Directory: ".reviews/{{.review_id}}", LegPattern: "{{.leg.id}}-findings.md"},
+		Legs: []formulaLeg{
+			{ID: "security", Title: "Security Review", Focus: "injection and auth bypasses", Description: "Look for security holes."},
+			{ID: "perf", Title: "Performance Review", Focus: "hot loops", Description: "Look for slow code.", Needs: []string{"security"}},
+		},
+	}
+	legs := recordRun(t, f, "abc12", 42)
+	meta := &ConvoyMeta{ID: "hq-cv-run", Title: "Review", Formula: "code-review", ReviewID: "abc12", PR: 42}
+	synthesis := &beads.Issue{ID: "hq-syn-x", Title: "Synthesis", Description: "Combine {{.output.directory}}."}
+
+	cf := captureFormula("my-review", meta, legs, synthesis)
+	if got := cf.Prompts["base"]; got != base {
+		t.Errorf("base prompt =\n%q\nwant\n%q", got, base)
+	}
+	if cf.Output == nil || cf.Output.Directory != ".reviews/{{.review_id}}" || cf.Output.LegPattern != "{{.leg.id}}-findings.md" {
+		t.Errorf("output = %+v", cf.Output)
+	}
+	if cf.Legs[0].Focus != "injection and auth bypasses" || cf.Legs[1].Focus != "hot loops" {
+		t.Errorf("focus = %q, %q", cf.Legs[0].Focus, cf.Legs[1].Focus)
+	}
+	if len(cf.Legs[1].Needs) != 1 || cf.Legs[1].Needs[0] != "security" {
+		t.Errorf("needs = %v", cf.Legs[1].Needs)
+	}
+	if len(cf.Legs[0].Env) != 1 || cf.Legs[0].Env["TOKEN"] != "$secret:gh" {
+		t.Errorf("env = %v, want run env dropped", cf.Legs[0].Env)
+	}
+
+	content := capturedFormulaTOML(cf)
+	parsed, err := formula.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("captured formula does not parse: %v\n%s", err, content)
+	}
+	if parsed.Prompts["base"] != base || len(parsed.Legs) != 2 || !parsed.Inputs["pr"].Required {
+		t.Errorf("parsed = %+v", parsed)
+	}
+	if parsed.Synthesis == nil || strings.Join(parsed.Synthesis.DependsOn, ",") != "security,perf" {
+		t.Errorf("synthesis = %+v", parsed.Synthesis)
+	}
+}
+
+func TestCaptureFormulaDivergentPrompts(t *testing.T) {
+	legs := []capturedLeg{
+		{Bead: &beads.Issue{ID: "hq-leg-a", Title: "First Pass", Description: "Do A." + basePromptMarker + "Alpha prompt.\nline two"}},
+		{Bead: &beads.Issue{ID: "hq-leg-b", Title: "Second Pass", Description: "Do B." + basePromptMarker + "Completely different.\nmore"}},
+	}
+	cf := captureFormula("adhoc", &ConvoyMeta{ID: "hq-cv-x", Title: "Ad hoc"}, legs, nil)
+	if _, ok := cf.Prompts["base"]; ok {
+		t.Errorf("base prompt = %q, want none", cf.Prompts["base"])
+	}
+	if cf.Legs[0].ID != "first-pass" || !strings.Contains(cf.Legs[0].Description, "Alpha prompt.") {
+		t.Errorf("leg = %+v, want its full prompt kept", cf.Legs[0])
+	}
+	if len(cf.Notes) == 0 {
+		t.Error("expected a note about divergent prompts")
+	}
+	if _, err := formula.Parse([]byte(capturedFormulaTOML(cf))); err != nil {
+		t.Errorf("captured formula does not parse: %v", err)
+	}
+}
+
+func TestTemplatize(t *testing.T) {
+	values := map[string]string{"{{.leg.id}}": "style", "{{.review_id}}": "abc12"}
+	got := templatize("style review in .reviews/abc12 on stylesheet", values)
+	want := "{{.leg.id}} review in .reviews/{{.review_id}} on stylesheet"
+	if got != want {
+		t.Errorf("templatize() = %q, want %q", got, want)
+	}
+}
+
+func TestTOMLString(t *testing.T) {
+	for _, s := range []string{`say "hi" \ bye`, "a\n\"\"\"quoted\"\"\"\n\tb\n", "ends in quote\n\""} {
+		var v struct{ S string }
+		if _, err := toml.Decode("s = "+tomlString(s)+"\n", &v); err != nil {
+			t.Fatalf("tomlString(%q) = %s: %v", s, tomlString(s), err)
+		}
+		if v.S != s {
+			t.Errorf("tomlString(%q) decodes to %q", s, v.S)
+		}
+	}
+}
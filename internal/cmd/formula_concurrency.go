@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+)
+
+// loadConcurrencyConfig returns the town's concurrency classes, or nil if
+// none are set.
+func loadConcurrencyConfig(townRoot string) *config.ConcurrencyConfig {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings.Concurrency
+}
+
+// checkConcurrencyClass refuses a run of a formula in class while the
+// class already has as many open convoys, in any rig, as the town allows.
+// Formulas without a class, and classes without a limit, are never refused.
+// A run is also refused when the open convoys can't be counted.
+//
+// When the class has a limit, the check holds the town's concurrency lock
+// until the returned release is called. Callers release it once the run's
+// convoy bead exists (or the run fails), so two runs can't both take the
+// class's last slot. Release may be called more than once.
+func checkConcurrencyClass(townRoot, class string) (release func(), err error) {
+	release = func() {}
+	if townRoot == "" || class == "" {
+		return release, nil
+	}
+	limits := loadConcurrencyConfig(townRoot)
+	if limits.Limit(class) <= 0 {
+		return release, nil
+	}
+	unlock, err := fsx.Lock(concurrencyLockPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("concurrency class %s: %w", class, err)
+	}
+	release = sync.OnceFunc(unlock)
+	counts, err := countOpenClassConvoys(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("concurrency class %s: counting open convoys: %w", class, err)
+	}
+	if err := limits.Check(class, counts[class]); err != nil {
+		release()
+		return nil, fmt.Errorf("%w\n\nSee open convoys with: gt convoy list\nAdjust limits under \"concurrency\" in %s",
+			err, config.TownSettingsPath(townRoot))
+	}
+	return release, nil
+}
+
+// concurrencyLockPath returns the file whose lock serializes concurrency
+// class checks with the convoy beads they count.
+func concurrencyLockPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "concurrency")
+}
+
+// countOpenClassConvoys counts open formula convoys per concurrency class.
+// Formula convoys record their class as a "concurrency_class: <name>"
+// description line.
+func countOpenClassConvoys(townBeads string) (map[string]int, error) {
+	listCmd := exec.Command("bd", "list", "--type=convoy", "--status=open", "--limit=0", "--json")
+	listCmd.Dir = townBeads
+	var stdout, stderr bytes.Buffer
+	listCmd.Stdout = &stdout
+	listCmd.Stderr = &stderr
+	if err := cmdtrace.Run(listCmd); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("bd list: %s", msg)
+		}
+		return nil, fmt.Errorf("bd list: %w", err)
+	}
+
+	var convoys []struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
+		return nil, fmt.Errorf("parsing bd list output: %w", err)
+	}
+	counts := make(map[string]int)
+	for _, c := range convoys {
+		if class := convoyConcurrencyClass(c.Description); class != "" {
+			counts[class]++
+		}
+	}
+	return counts, nil
+}
+
+// convoyConcurrencyClass returns the concurrency class a formula convoy
+// description records, or "".
+func convoyConcurrencyClass(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		if class, ok := strings.CutPrefix(strings.TrimSpace(line), "concurrency_class:"); ok {
+			return strings.TrimSpace(class)
+		}
+	}
+	return ""
+}
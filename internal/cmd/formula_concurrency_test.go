@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestConvoyConcurrencyClass(t *testing.T) {
+	desc := "Formula convoy: build\n\nformula: build\nreview_id: abc\nLegs: 2\nRig: gastown\nconcurrency_class: heavy-build"
+	if got := convoyConcurrencyClass(desc); got != "heavy-build" {
+		t.Errorf("convoyConcurrencyClass = %q, want heavy-build", got)
+	}
+	if got := convoyConcurrencyClass("Formula convoy: lint\n\nRig: gastown"); got != "" {
+		t.Errorf("convoy without a class: got %q", got)
+	}
+}
+
+func TestCheckConcurrencyClassUnlimited(t *testing.T) {
+	// No town settings: nothing is limited, and bd is never consulted
+	if release, err := checkConcurrencyClass(t.TempDir(), "heavy-build"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	} else {
+		release()
+	}
+	if release, err := checkConcurrencyClass(t.TempDir(), ""); err != nil {
+		t.Errorf("formula without a class: unexpected error %v", err)
+	} else {
+		release()
+	}
+}
+
+func TestCheckConcurrencyClassLimited(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script bd stub")
+	}
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.Concurrency = &config.ConcurrencyConfig{Classes: map[string]int{"heavy-build": 1}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	// bd stub: logs its args and prints whatever "list.json" holds, or
+	// fails when it's missing
+	binDir := t.TempDir()
+	listFile := filepath.Join(binDir, "list.json")
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$@\" >> " + argsFile + "\ncat " + listFile + " || exit 1\n"
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if _, err := checkConcurrencyClass(townRoot, "heavy-build"); err == nil {
+		t.Error("run allowed while bd can't count open convoys")
+	}
+
+	if err := os.WriteFile(listFile, []byte(`[{"description":"Rig: gastown\nconcurrency_class: lint"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	release, err := checkConcurrencyClass(townRoot, "heavy-build")
+	if err != nil {
+		t.Fatalf("class with a free slot refused: %v", err)
+	}
+	release()
+	release() // Release may be called twice
+
+	if err := os.WriteFile(listFile, []byte(`[{"description":"Rig: gastown\nconcurrency_class: heavy-build"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := checkConcurrencyClass(townRoot, "heavy-build"); err == nil {
+		t.Error("full class allowed another run")
+	}
+
+	args, _ := os.ReadFile(argsFile)
+	if !strings.Contains(string(args), "--limit=0") {
+		t.Errorf("bd list args = %q, want --limit=0", args)
+	}
+}
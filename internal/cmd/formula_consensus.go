@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/review"
+)
+
+// expandConsensusLegs returns the formula as dispatched to polecats, with
+// each consensus leg split into one leg per agent. It mirrors
+// formula.Formula.ExpandConsensus, so 'gt review render' finds the agents'
+// outputs under the same leg IDs.
+func expandConsensusLegs(f *formulaData) *formulaData {
+	subLegs := make(map[string][]string)
+	var legs []formulaLeg
+	for _, leg := range f.Legs {
+		if leg.Consensus == nil {
+			legs = append(legs, leg)
+			continue
+		}
+		for _, agent := range leg.Consensus.Agents {
+			sub := leg
+			sub.ID = formula.ConsensusLegID(leg.ID, agent)
+			sub.Title = formula.ConsensusLegTitle(leg.Title, leg.ID, agent)
+			sub.Agent = agent
+			sub.Consensus = nil
+			legs = append(legs, sub)
+			subLegs[leg.ID] = append(subLegs[leg.ID], sub.ID)
+		}
+	}
+	if len(subLegs) == 0 {
+		return f
+	}
+	for i := range legs {
+		legs[i].Needs = formula.ExpandConsensusNeeds(legs[i].Needs, subLegs)
+	}
+	expanded := *f
+	expanded.Legs = legs
+	if f.Synthesis != nil {
+		syn := *f.Synthesis
+		syn.DependsOn = formula.ExpandConsensusNeeds(syn.DependsOn, subLegs)
+		expanded.Synthesis = &syn
+	}
+	return &expanded
+}
+
+// consensusAgentOutputPath is where a local run saves one agent's reply to
+// a consensus leg: the output path the agent's leg would have on a polecat
+// run.
+func consensusAgentOutputPath(f *formulaData, leg formulaLeg, agent, outputDir string, legCtx map[string]interface{}) string {
+	sub := leg
+	sub.ID = formula.ConsensusLegID(leg.ID, agent)
+	sub.Title = formula.ConsensusLegTitle(leg.Title, leg.ID, agent)
+	ctx := make(map[string]interface{}, len(legCtx))
+	for k, v := range legCtx {
+		ctx[k] = v
+	}
+	ctx["leg"] = map[string]interface{}{
+		"id":          sub.ID,
+		"title":       sub.Title,
+		"focus":       sub.Focus,
+		"description": sub.Description,
+	}
+	delete(ctx, "output_path")
+	addLegOutputContext(f, ctx, sub, outputDir)
+	if path, _ := ctx["output_path"].(string); path != "" {
+		return path
+	}
+	return filepath.Join(outputDir, sub.ID+"-findings.md")
+}
+
+// runConsensusLeg sends a consensus leg's prompt to each of its agents at
+// once, saves every reply to the agent's output path, and returns the
+// merged findings as the leg's reply. The leg fails only if no agent
+// replies; the quorum counts the agents that did. The reply is cached if
+// every agent's was.
+func runConsensusLeg(cache *localAgentCache, townRoot, rigPath string, leg formulaLeg, prompt string, paths map[string]string) (string, bool, error) {
+	agents := leg.Consensus.Agents
+	replies := make([]string, len(agents))
+	cached := make([]bool, len(agents))
+	errs := make([]error, len(agents))
+	var wg sync.WaitGroup
+	for i, agent := range agents {
+		wg.Add(1)
+		go func(i int, agent string) {
+			defer wg.Done()
+			replies[i], cached[i], errs[i] = cache.run(townRoot, rigPath, agent, prompt)
+			if errs[i] == nil {
+				errs[i] = writeLocalOutput(paths[agent], replies[i])
+			}
+		}(i, agent)
+	}
+	wg.Wait()
+
+	var reports []review.AgentFindings
+	var failures []string
+	allCached := true
+	for i, agent := range agents {
+		if errs[i] != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", agent, errs[i]))
+			continue
+		}
+		reports = append(reports, review.AgentFindings{Agent: agent, Findings: review.ParseFindings(replies[i], leg.ID)})
+		allCached = allCached && cached[i]
+	}
+	if len(reports) == 0 {
+		return "", false, fmt.Errorf("no consensus agent replied (%s)", strings.Join(failures, "; "))
+	}
+
+	merged := review.MergeConsensus(reports, leg.Consensus.Quorum(len(reports)))
+	reply := merged.Markdown(legTitle(leg))
+	if len(failures) > 0 {
+		reply += "\n## Failed agents\n\n- " + strings.Join(failures, "\n- ") + "\n"
+	}
+	return reply, allCached, nil
+}
+
+// legTitle returns the leg's title, or its ID if it has none.
+func legTitle(leg formulaLeg) string {
+	if leg.Title != "" {
+		return leg.Title
+	}
+	return leg.ID
+}
+
+// mergeConsensusOutputs merges the agents' outputs of a consensus leg from
+// a polecat run, read through read. It reports false until every agent's
+// output exists, since a quorum of the agents that finished first would
+// pass findings the others might dispute.
+func mergeConsensusOutputs(leg formula.Leg, names map[string]string, read func(string) (string, bool)) (string, bool) {
+	var reports []review.AgentFindings
+	for _, agent := range leg.Consensus.Agents {
+		content, ok := read(names[agent])
+		if !ok {
+			return "", false
+		}
+		reports = append(reports, review.AgentFindings{Agent: agent, Findings: review.ParseFindings(content, leg.ID)})
+	}
+	title := leg.Title
+	if title == "" {
+		title = leg.ID
+	}
+	merged := review.MergeConsensus(reports, leg.Consensus.Quorum(len(reports)))
+	return strings.TrimSpace(merged.Markdown(title)), true
+}
+
+// legAgent returns the agent a leg's polecat runs: the leg's own agent if
+// it has one, otherwise the formula's.
+func legAgent(f *formulaData, leg formulaLeg) string {
+	if leg.Agent != "" {
+		return leg.Agent
+	}
+	return f.Agent
+}
+
+// describeLegConsensus summarizes a consensus leg for dry runs.
+func describeLegConsensus(c *formula.Consensus) string {
+	return fmt.Sprintf("consensus: %s (%s)", strings.Join(c.Agents, ", "), c.StrategyOrDefault())
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/review"
+)
+
+func TestParseLegConsensus(t *testing.T) {
+	f, err := parseFormulaContent(t, `formula = "review"
+
+[[legs]]
+id = "security"
+consensus = { agents = ["claude", "gemini"], strategy = "majority" }
+
+[[legs]]
+id = "style"
+`)
+	if err != nil || len(f.Legs) != 2 {
+		t.Fatalf("parseFormulaFile() = %+v, %v", f, err)
+	}
+	c := f.Legs[0].Consensus
+	if c == nil || strings.Join(c.Agents, ",") != "claude,gemini" || c.Strategy != formula.ConsensusMajority {
+		t.Errorf("security consensus = %+v", c)
+	}
+	if f.Legs[1].Consensus != nil {
+		t.Errorf("style consensus = %+v, want nil", f.Legs[1].Consensus)
+	}
+
+	if _, err := parseFormulaContent(t, "formula = \"review\"\n[[legs]]\nid = \"broken\"\nconsensus = { agents = [\"claude\"] }\n"); err == nil {
+		t.Error("parseFormulaFile() accepted a one-agent consensus")
+	}
+}
+
+func TestExpandConsensusLegs(t *testing.T) {
+	f := &formulaData{
+		Agent: "claude",
+		Legs: []formulaLeg{
+			{ID: "security", Title: "Security", Consensus: &formula.Consensus{Agents: []string{"claude", "gemini"}}},
+			{ID: "summary", Title: "Summary", Needs: []string{"security"}},
+		},
+		Synthesis: &formulaSynthesis{DependsOn: []string{"security"}},
+	}
+
+	e := expandConsensusLegs(f)
+	if len(e.Legs) != 3 || e.Legs[1].ID != "security@gemini" || e.Legs[1].Title != "Security (gemini)" {
+		t.Fatalf("expanded legs = %+v", e.Legs)
+	}
+	if got := legAgent(e, e.Legs[1]); got != "gemini" {
+		t.Errorf("legAgent(security@gemini) = %q, want gemini", got)
+	}
+	if got := legAgent(e, e.Legs[2]); got != "claude" {
+		t.Errorf("legAgent(summary) = %q, want the formula agent", got)
+	}
+	if got := strings.Join(e.Legs[2].Needs, " "); got != "security@claude security@gemini" {
+		t.Errorf("summary needs = %s", got)
+	}
+	if got := strings.Join(e.Synthesis.DependsOn, " "); got != "security@claude security@gemini" {
+		t.Errorf("synthesis depends_on = %s", got)
+	}
+}
+
+func TestExecuteConvoyFormulaLocalConsensus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell script agent stubs")
+	}
+
+	binDir := t.TempDir()
+	stubs := map[string]string{
+		"claude": "## High\n- SQL injection in user lookup at db/users.go:42\n- Session tokens are written to the debug log\n",
+		"gemini": "## Critical\n- db/users.go:42 builds the query by string concatenation\n",
+	}
+	for name, reply := range stubs {
+		script := "#!/bin/sh\nprintf '" + reply + "'\n"
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &formulaData{
+		Type: "convoy",
+		Legs: []formulaLeg{{
+			ID:        "security",
+			Title:     "Security",
+			Consensus: &formula.Consensus{Agents: []string{"claude", "gemini"}},
+		}},
+		Output: &formulaOutput{Directory: "out", LegPattern: "{{.leg.id}}.md"},
+	}
+	if err := executeConvoyFormulaLocal(f, "review", "gastown", runHold{}); err != nil {
+		t.Fatalf("executeConvoyFormulaLocal() = %v", err)
+	}
+
+	for _, name := range []string{"security@claude.md", "security@gemini.md"} {
+		if _, err := os.Stat(filepath.Join(workDir, "out", name)); err != nil {
+			t.Errorf("agent output %s: %v", name, err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, "out", "security.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := review.ParseFindings(string(data), "security")
+	if len(findings) != 1 || findings[0].Severity != review.SeverityCritical {
+		t.Errorf("merged findings = %+v, want the agreed injection as critical\n%s", findings, data)
+	}
+	if !strings.Contains(string(data), "## Disputed") || !strings.Contains(string(data), "debug log") {
+		t.Errorf("merged output doesn't flag the disputed finding:\n%s", data)
+	}
+}
+
+func TestBuildReviewReportConsensus(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"security@claude.md": "## High\n- Token leak in auth/session.go:10\n- Weak hash\n",
+		"security@gemini.md": "## Medium\n- auth/session.go:10 logs the token\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f := &formula.Formula{
+		Legs:   []formula.Leg{{ID: "security", Title: "Security", Consensus: &formula.Consensus{Agents: []string{"claude", "gemini"}}}},
+		Output: &formula.Output{LegPattern: "{{.leg.id}}.md"},
+	}
+
+	r, err := buildReviewReport("abc", dir, &ConvoyMeta{}, f)
+	if err != nil {
+		t.Fatalf("buildReviewReport: %v", err)
+	}
+	if len(r.Legs) != 1 || r.Legs[0].Status != review.StatusComplete {
+		t.Fatalf("legs = %+v, want the merged security leg only", r.Legs)
+	}
+	findings := review.ParseFindings(r.Legs[0].Content, "security")
+	if len(findings) != 1 || findings[0].Severity != review.SeverityHigh {
+		t.Errorf("merged findings = %+v, want the agreed token leak\n%s", findings, r.Legs[0].Content)
+	}
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tokens"
+)
+
+// legContextPlan describes whether a convoy run's PR diff fits alongside
+// the leg prompts, and how the formula trims it when it doesn't.
+type legContextPlan struct {
+	Strategy     formula.ContextStrategy
+	Budget       int // Token budget for prompt plus context
+	PromptTokens int // Largest leg prompt
+	DiffTokens   int
+}
+
+// Oversized reports whether prompt plus diff exceeds the budget.
+func (p legContextPlan) Oversized() bool {
+	return p.PromptTokens+p.DiffTokens > p.Budget
+}
+
+// Trims reports whether the formula's strategy will rewrite the context.
+func (p legContextPlan) Trims() bool {
+	return p.Oversized() && p.Strategy != formula.ContextNone
+}
+
+// DiffBudget is the room left for context after the largest prompt.
+func (p legContextPlan) DiffBudget() int {
+	if n := p.Budget - p.PromptTokens; n > 0 {
+		return n
+	}
+	return 1
+}
+
+// planLegContext sizes the largest leg prompt and the diff against the
+// formula's context_limit (or the model's default budget).
+func planLegContext(f *formulaData, family tokens.Family, diff string) legContextPlan {
+	plan := legContextPlan{
+		Strategy:   f.ContextStrategy,
+		Budget:     f.ContextLimit,
+		DiffTokens: tokens.Estimate(diff, family),
+	}
+	if plan.Budget == 0 {
+		plan.Budget = tokens.DefaultBudget(family)
+	}
+	base := tokens.Estimate(f.Prompts["base"], family)
+	for _, leg := range f.Legs {
+		if n := base + tokens.Estimate(leg.Description, family); n > plan.PromptTokens {
+			plan.PromptTokens = n
+		}
+	}
+	return plan
+}
+
+// describeLegContextPlan explains what a run would do with oversized context.
+func describeLegContextPlan(plan legContextPlan) string {
+	if plan.Trims() {
+		return fmt.Sprintf("diff ≈%s exceeds the %s budget; will apply %s",
+			tokens.Format(plan.DiffTokens), tokens.Format(plan.Budget), plan.Strategy)
+	}
+	return fmt.Sprintf("diff ≈%s exceeds the %s budget; set context_strategy in the formula to trim it",
+		tokens.Format(plan.DiffTokens), tokens.Format(plan.Budget))
+}
+
+// buildLegContext applies the formula's context strategy to an oversized
+// diff and returns the section appended to every leg description. Chunks
+// are written under contextDir. summarize_diff falls back to
+// drop_file_bodies if the summarizing agent fails.
+func buildLegContext(plan legContextPlan, f *formulaData, diff, townRoot, rigPath, contextDir string, family tokens.Family) (string, error) {
+	files := formula.SplitDiff(diff)
+	estimate := func(s string) int { return tokens.Estimate(s, family) }
+
+	switch plan.Strategy {
+	case formula.ContextChunk:
+		chunks := formula.ChunkDiff(files, plan.DiffBudget(), estimate)
+		if err := os.MkdirAll(contextDir, 0755); err != nil {
+			return "", fmt.Errorf("creating context dir: %w", err)
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "The PR diff (≈%s tokens) is too large to read at once. It is split into %d chunks that each fit your context; review them one at a time:\n",
+			tokens.Format(plan.DiffTokens), len(chunks))
+		for i, chunk := range chunks {
+			path := filepath.Join(contextDir, fmt.Sprintf("diff-%02d.patch", i+1))
+			if err := os.WriteFile(path, []byte(chunk), 0644); err != nil { //nolint:gosec // G306: review artifacts are not secret
+				return "", fmt.Errorf("writing context chunk: %w", err)
+			}
+			fmt.Fprintf(&b, "  %s\n", path)
+		}
+		return b.String(), nil
+
+	case formula.ContextSummarizeDiff:
+		summary, err := summarizeDiff(files, f.ContextAgent, townRoot, rigPath, family)
+		if err == nil {
+			return fmt.Sprintf("The PR diff (≈%s tokens) is too large to include. Summary of the changes:\n\n%s\n\nChanged files:\n%s",
+				tokens.Format(plan.DiffTokens), strings.TrimSpace(summary), formula.FileStats(files)), nil
+		}
+		fmt.Fprintf(formulaRunLog(), "%s summarize_diff failed, dropping file bodies instead: %v\n", style.Dim.Render("Warning:"), err)
+		fallthrough
+
+	case formula.ContextDropFileBodies:
+		return fmt.Sprintf("The PR diff (≈%s tokens) is too large to include. Only per-file change counts are listed; read the files you need instead of the whole diff:\n\n%s",
+			tokens.Format(plan.DiffTokens), formula.FileStats(files)), nil
+	}
+	return "", nil
+}
+
+// summarizeDiff runs a pre-summarization pass over the diff with the
+// formula's context_agent (or the rig's agent), one chunk at a time.
+func summarizeDiff(files []formula.FileDiff, agent, townRoot, rigPath string, family tokens.Family) (string, error) {
+	if agent != "" {
+		family = tokens.FamilyForAgent(agent)
+	}
+	// Leave half the summarizer's window for instructions and its reply.
+	chunkBudget := tokens.ContextWindow(family) / 2
+	chunks := formula.ChunkDiff(files, chunkBudget, func(s string) int { return tokens.Estimate(s, family) })
+
+	var summaries []string
+	for i, chunk := range chunks {
+		fmt.Fprintf(formulaRunLog(), "  %s Summarizing diff chunk %d/%d...\n", style.Dim.Render("○"), i+1, len(chunks))
+		prompt := "Summarize this diff for code reviewers. For each file, say what changed and why it matters; " +
+			"call out risky changes. Be concise and do not include code.\n\n" + chunk
+		out, _, err := runAgentWithFallback(townRoot, rigPath, agent, prompt, formulaRunLog())
+		if err != nil {
+			return "", err
+		}
+		summaries = append(summaries, strings.TrimSpace(out))
+	}
+	return strings.Join(summaries, "\n\n"), nil
+}
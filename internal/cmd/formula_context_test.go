@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/tokens"
+)
+
+func TestBuildLegContext(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n" +
+		"diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +1,2 @@\n x\n+y\n"
+	f := &formulaData{Legs: []formulaLeg{{ID: "a"}}}
+
+	t.Run("drop_file_bodies", func(t *testing.T) {
+		f.ContextStrategy = formula.ContextDropFileBodies
+		plan := legContextPlan{Strategy: f.ContextStrategy, Budget: 1, DiffTokens: 50}
+		got, err := buildLegContext(plan, f, diff, "", "", t.TempDir(), tokens.FamilyClaude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(got, "a.go (+1 -1)") || strings.Contains(got, "+new") {
+			t.Errorf("drop_file_bodies context = %q, want stats without bodies", got)
+		}
+	})
+
+	t.Run("chunk", func(t *testing.T) {
+		f.ContextStrategy = formula.ContextChunk
+		dir := filepath.Join(t.TempDir(), "context")
+		plan := legContextPlan{Strategy: f.ContextStrategy, Budget: 12, DiffTokens: 50}
+		got, err := buildLegContext(plan, f, diff, "", "", dir, tokens.FamilyClaude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("chunk wrote %d files, want 2", len(entries))
+		}
+		if !strings.Contains(got, filepath.Join(dir, "diff-01.patch")) {
+			t.Errorf("chunk context = %q, want chunk paths listed", got)
+		}
+	})
+}
+
+func TestPlanLegContext(t *testing.T) {
+	f := &formulaData{
+		Legs:         []formulaLeg{{ID: "a", Description: "short"}},
+		ContextLimit: 100,
+	}
+	if plan := planLegContext(f, tokens.FamilyClaude, ""); plan.Oversized() {
+		t.Errorf("empty diff should fit, got %+v", plan)
+	}
+	plan := planLegContext(f, tokens.FamilyClaude, strings.Repeat("word ", 500))
+	if !plan.Oversized() || plan.Trims() {
+		t.Errorf("large diff without strategy: Oversized=%v Trims=%v, want true/false", plan.Oversized(), plan.Trims())
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var formulaRunForce bool
+
+func init() {
+	formulaRunCmd.Flags().BoolVar(&formulaRunForce, "force", false, "With --pr, run even if this formula already ran on the PR's current head")
+}
+
+// checkDuplicatePRRun refuses to start a run of formulaName on a PR whose
+// current head already has one, unless --force is given. It records the
+// head commit for the new run so later checks can find it.
+func checkDuplicatePRRun(townRoot, formulaName, rigName string) error {
+	if formulaRunPR <= 0 {
+		return nil
+	}
+	if formulaRunHeadSHA == "" {
+		provider, err := formulaPRProvider(townRoot, filepath.Join(townRoot, rigName))
+		if err != nil {
+			return err
+		}
+		_, sha, err := fetchPRHead(provider, formulaRunPR)
+		if err != nil {
+			// Without the head commit there is nothing to compare against.
+			fmt.Printf("%s Could not check PR #%d for earlier runs: %v\n", style.Dim.Render("Warning:"), formulaRunPR, err)
+			return nil
+		}
+		formulaRunHeadSHA = sha
+	}
+	if formulaRunForce {
+		return nil
+	}
+	existing, local := findPRRun(townRoot, formulaName, rigName, formulaRunPR, formulaRunHeadSHA)
+	if existing == "" {
+		return nil
+	}
+	fmt.Printf("%s %s already ran on PR #%d at %s (%s)\n",
+		style.Dim.Render("Warning:"), formulaName, formulaRunPR, shortSHA(formulaRunHeadSHA), describePRRun(existing, local))
+	return fmt.Errorf("duplicate run of %s on PR #%d; use --force to run again", formulaName, formulaRunPR)
+}
+
+// findPRRun returns the convoy of an earlier run of formulaName on a PR at
+// headSHA, or "" if there is none. Open convoys catch runs still being
+// dispatched; the rig's audit log catches finished ones, including
+// --local-agent runs, for which it returns the review ID and local.
+func findPRRun(townRoot, formulaName, rigName string, pr int, headSHA string) (id string, local bool) {
+	if id := findOpenPRConvoy(filepath.Join(townRoot, ".beads"), formulaName, rigName, pr, headSHA); id != "" {
+		return id, false
+	}
+	entries, err := witness.ReadAuditLog(filepath.Join(townRoot, rigName))
+	if err != nil {
+		return "", false
+	}
+	prStr := strconv.Itoa(pr)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Action == witness.ActionFormulaRun && e.Details["formula"] == formulaName &&
+			e.Details["pr"] == prStr && e.Details["head_sha"] == headSHA {
+			return e.Subject, e.Details["local"] == "true"
+		}
+	}
+	return "", false
+}
+
+// describePRRun names a run found by findPRRun.
+func describePRRun(id string, local bool) string {
+	if local {
+		return "local run " + id
+	}
+	return "convoy " + id
+}
+
+// findOpenPRConvoy searches open formula convoys for a run of formulaName
+// on rigName's PR at headSHA.
+func findOpenPRConvoy(townBeads, formulaName, rigName string, pr int, headSHA string) string {
+	listCmd := exec.Command("bd", "list", "--type=convoy", "--status=open", "--json")
+	listCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	listCmd.Stdout = &stdout
+	if err := cmdtrace.Run(listCmd); err != nil {
+		return ""
+	}
+
+	var convoys []struct {
+		ID          string `json:"id"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
+		return ""
+	}
+	for _, c := range convoys {
+		if isPRRunConvoy(c.Description, formulaName, rigName, pr, headSHA) {
+			return c.ID
+		}
+	}
+	return ""
+}
+
+// isPRRunConvoy reports whether a formula convoy description records a run
+// of formulaName on rigName's PR at headSHA.
+func isPRRunConvoy(description, formulaName, rigName string, pr int, headSHA string) bool {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(description, "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), ":"); ok {
+			fields[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	return fields["formula"] == formulaName &&
+		fields["rig"] == rigName &&
+		fields["pr"] == fmt.Sprintf("#%d", pr) &&
+		fields["head_sha"] == headSHA
+}
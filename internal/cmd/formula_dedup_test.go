@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+func TestIsPRRunConvoy(t *testing.T) {
+	desc := "Formula convoy: code-review\n\nformula: code-review\nreview_id: abc\nLegs: 3\nRig: gastown\nPR: #12\nhead_sha: 3f2c9a1b"
+	if !isPRRunConvoy(desc, "code-review", "gastown", 12, "3f2c9a1b") {
+		t.Error("expected match")
+	}
+	for name, args := range map[string]struct {
+		formula, rig string
+		pr           int
+		sha          string
+	}{
+		"formula": {"shiny", "gastown", 12, "3f2c9a1b"},
+		"rig":     {"code-review", "beads", 12, "3f2c9a1b"},
+		"pr":      {"code-review", "gastown", 1, "3f2c9a1b"},
+		"sha":     {"code-review", "gastown", 12, "0000"},
+	} {
+		if isPRRunConvoy(desc, args.formula, args.rig, args.pr, args.sha) {
+			t.Errorf("different %s should not match", name)
+		}
+	}
+}
+
+func TestFindPRRunAuditLog(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	for _, e := range []witness.AuditEntry{
+		{Action: witness.ActionFormulaRun, Subject: "hq-cv-old", Details: map[string]string{"formula": "code-review", "pr": "12", "head_sha": "aaa"}},
+		{Action: witness.ActionFormulaRun, Subject: "hq-cv-new", Details: map[string]string{"formula": "code-review", "pr": "12", "head_sha": "bbb"}},
+		{Action: witness.ActionFormulaRun, Subject: "abc23", Details: map[string]string{"formula": "code-review", "pr": "12", "head_sha": "ccc", "local": "true"}},
+	} {
+		if err := witness.Record(rigPath, e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if got, local := findPRRun(townRoot, "code-review", "gastown", 12, "bbb"); got != "hq-cv-new" || local {
+		t.Errorf("findPRRun = %q (local %v), want convoy hq-cv-new", got, local)
+	}
+	if got, local := findPRRun(townRoot, "code-review", "gastown", 12, "ccc"); got != "abc23" || !local {
+		t.Errorf("findPRRun for locally reviewed head = %q (local %v), want local run abc23", got, local)
+	}
+	if got, _ := findPRRun(townRoot, "code-review", "gastown", 12, "ddd"); got != "" {
+		t.Errorf("findPRRun for new head = %q, want none", got)
+	}
+	if got, _ := findPRRun(townRoot, "shiny", "gastown", 12, "aaa"); got != "" {
+		t.Errorf("findPRRun for other formula = %q, want none", got)
+	}
+}
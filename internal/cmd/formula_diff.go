@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// Formula diff flags
+var (
+	formulaDiffFormat  string
+	formulaDiffNoPager bool
+)
+
+var formulaDiffCmd = &cobra.Command{
+	Use:   "diff <name> [other]",
+	Short: "Show differences between formulas",
+	Long: `Show differences between two formulas, or between an installed formula
+and the version shipped with gt.
+
+With one argument, compares the embedded formula (old) against the one
+found in the search paths (new), showing local edits. With two arguments,
+compares them in order. Arguments are formula names or file paths.
+
+Formats:
+  text     Colored unified diff (default)
+  unified  Plain unified diff, pipeable to delta, bat, or patch
+  json     Hunk structure for tooling
+
+Text output goes through a pager ($GT_PAGER, $PAGER, or less) when it is
+longer than the terminal. Use --no-pager or GT_NO_PAGER=1 to disable.
+
+Examples:
+  gt formula diff code-review                      # Local edits vs shipped
+  gt formula diff code-review my-review            # Compare two formulas
+  gt formula diff code-review --format unified | delta
+  gt formula diff code-review --format json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runFormulaDiff,
+}
+
+func init() {
+	formulaDiffCmd.Flags().StringVar(&formulaDiffFormat, "format", "text", "Output format: text, unified, or json")
+	formulaDiffCmd.Flags().BoolVar(&formulaDiffNoPager, "no-pager", false, "Don't pipe text output through a pager")
+	formulaCmd.AddCommand(formulaDiffCmd)
+}
+
+// formulaDiffResult is the JSON form of a formula diff.
+type formulaDiffResult struct {
+	Old       string     `json:"old"`
+	New       string     `json:"new"`
+	Identical bool       `json:"identical"`
+	Hunks     []diffHunk `json:"hunks"`
+	// Override is the owner/reason annotation of the new side, if any.
+	Override *formula.Override `json:"override,omitempty"`
+}
+
+func runFormulaDiff(cmd *cobra.Command, args []string) error {
+	switch formulaDiffFormat {
+	case "text", "unified", "json":
+	default:
+		return fmt.Errorf("invalid --format %q (must be text, unified, or json)", formulaDiffFormat)
+	}
+
+	var oldName, newName, oldContent, newContent string
+	if len(args) == 1 {
+		embedded, err := formula.EmbeddedFormula(args[0])
+		if err != nil {
+			return fmt.Errorf("%w; give a second formula to compare against", err)
+		}
+		path, content, err := loadFormulaSource(args[0])
+		if err != nil {
+			return err
+		}
+		oldName, oldContent = "embedded/"+args[0]+".formula.toml", string(embedded)
+		newName, newContent = path, content
+	} else {
+		var err error
+		if oldName, oldContent, err = loadFormulaSource(args[0]); err != nil {
+			return err
+		}
+		if newName, newContent, err = loadFormulaSource(args[1]); err != nil {
+			return err
+		}
+	}
+
+	if formulaDiffFormat == "json" {
+		res := formulaDiffResult{
+			Old:       oldName,
+			New:       newName,
+			Identical: oldContent == newContent,
+			Hunks:     lineDiffHunks(oldContent, newContent),
+		}
+		if res.Hunks == nil {
+			res.Hunks = []diffHunk{}
+		}
+		if o := formula.ParseOverride([]byte(newContent)); !o.IsZero() {
+			res.Override = &o
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	diff := unifiedLineDiff(oldName, newName, oldContent, newContent)
+	if diff == "" {
+		if formulaDiffFormat == "text" {
+			fmt.Printf("%s %s\n", style.Success.Render("✓"), i18n.Sprintf("No differences"))
+		}
+		return nil
+	}
+	if formulaDiffFormat == "unified" {
+		fmt.Print(diff)
+		return nil
+	}
+	out := colorDiff(diff)
+	if o := formula.ParseOverride([]byte(newContent)); !o.IsZero() {
+		out = style.Dim.Render("Override "+formatOverride(o)) + "\n" + out
+	}
+	return ui.ToPager(out, ui.PagerOptions{NoPager: formulaDiffNoPager})
+}
+
+// loadFormulaSource reads a formula given as a file path or a name in the
+// formula search paths.
+func loadFormulaSource(arg string) (string, string, error) {
+	path := arg
+	if info, err := os.Stat(arg); err != nil || info.IsDir() || !strings.Contains(filepath.Base(arg), ".") {
+		path, err = findFormulaFile(arg)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is user-provided by design
+	if err != nil {
+		return "", "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return path, string(data), nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLineDiffHunks(t *testing.T) {
+	if hunks := lineDiffHunks("a\nb\n", "a\nb\n"); len(hunks) != 0 {
+		t.Errorf("identical input: got %d hunks", len(hunks))
+	}
+
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	updated := "1\nTWO\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	hunks := lineDiffHunks(old, updated)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2: %+v", len(hunks), hunks)
+	}
+	want := diffHunk{OldStart: 1, OldLines: 5, NewStart: 1, NewLines: 5,
+		Lines: []string{" 1", "-2", "+TWO", " 3", " 4", " 5"}}
+	if !reflect.DeepEqual(hunks[0], want) {
+		t.Errorf("hunk 0 = %+v, want %+v", hunks[0], want)
+	}
+	if hunks[1].OldStart != 10 || hunks[1].NewLines != 4 {
+		t.Errorf("hunk 1 = %+v", hunks[1])
+	}
+}
+
+func TestLoadFormulaSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mine.formula.toml")
+	if err := os.WriteFile(path, []byte("formula = \"mine\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gotPath, content, err := loadFormulaSource(path)
+	if err != nil {
+		t.Fatalf("loadFormulaSource(path): %v", err)
+	}
+	if gotPath != path || content != "formula = \"mine\"\n" {
+		t.Errorf("got %q, %q", gotPath, content)
+	}
+
+	if _, _, err := loadFormulaSource("definitely-not-a-formula"); err == nil {
+		t.Error("expected error for unknown formula name")
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Formula disable/enable flags
+var (
+	formulaDisableRig    string
+	formulaDisableReason string
+)
+
+var formulaDisableCmd = &cobra.Command{
+	Use:   "disable <name> [--rig <rig>] [--reason <why>]",
+	Short: "Stop a formula from running without deleting it",
+	Long: `Mark a formula as disabled in the town's settings, or with --rig in that
+rig's settings. 'gt formula run' refuses a disabled formula and prints the
+reason, and 'gt formula list' shows it greyed out. The formula file, shipped
+or customized, is left alone; 'gt formula enable' reverses it.
+
+This is the safe way to retire a workflow for a while: resetting or
+deleting an override loses it.
+
+Examples:
+  gt formula disable code-review --reason "flaky since the agent upgrade"
+  gt formula disable design --rig gastown
+  gt formula enable code-review`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaDisable,
+}
+
+var formulaEnableCmd = &cobra.Command{
+	Use:   "enable <name> [--rig <rig>]",
+	Short: "Re-enable a formula disabled with gt formula disable",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFormulaEnable,
+}
+
+func init() {
+	formulaDisableCmd.Flags().StringVar(&formulaDisableRig, "rig", "", "Disable the formula only on this rig")
+	formulaDisableCmd.Flags().StringVar(&formulaDisableReason, "reason", "", "Why the formula is disabled (shown when a run is refused)")
+	formulaEnableCmd.Flags().StringVar(&formulaDisableRig, "rig", "", "Re-enable the formula on this rig")
+
+	formulaCmd.AddCommand(formulaDisableCmd)
+	formulaCmd.AddCommand(formulaEnableCmd)
+}
+
+func runFormulaDisable(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, err := findFormulaFile(name); err != nil {
+		if _, embErr := formula.EmbeddedFormula(name); embErr != nil {
+			return err
+		}
+	}
+
+	d := config.DisabledFormula{Reason: formulaDisableReason, By: detectSender(), At: time.Now().UTC()}
+	scope, err := updateDisabledFormulas(townRoot, formulaDisableRig, func(m map[string]config.DisabledFormula) map[string]config.DisabledFormula {
+		if m == nil {
+			m = make(map[string]config.DisabledFormula)
+		}
+		m[name] = d
+		return m
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", style.Success.Render("✓"), i18n.Sprintf("Disabled %s %s", name, scope))
+	fmt.Printf("  %s\n", style.Dim.Render(i18n.Sprintf("Re-enable with: gt formula enable %s%s", name, rigFlagSuffix(formulaDisableRig))))
+	return nil
+}
+
+func runFormulaEnable(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	found := false
+	scope, err := updateDisabledFormulas(townRoot, formulaDisableRig, func(m map[string]config.DisabledFormula) map[string]config.DisabledFormula {
+		_, found = m[name]
+		delete(m, name)
+		if len(m) == 0 {
+			return nil
+		}
+		return m
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		rigName := formulaDisableRig
+		if rigName == "" {
+			rigName, _ = inferRigFromCwd(townRoot)
+		}
+		if d, where := formulaDisabled(townRoot, rigName, name); d != nil {
+			return fmt.Errorf("%s is not disabled %s, but is disabled %s; enable it there instead", name, scope, where)
+		}
+		return fmt.Errorf("%s is not disabled %s", name, scope)
+	}
+	fmt.Printf("%s %s\n", style.Success.Render("✓"), i18n.Sprintf("Enabled %s %s", name, scope))
+	return nil
+}
+
+// updateDisabledFormulas applies update to the disabled formulas of the
+// town, or of rigName if set, and saves the settings. It returns the scope
+// changed, e.g. "in the town".
+func updateDisabledFormulas(townRoot, rigName string, update func(map[string]config.DisabledFormula) map[string]config.DisabledFormula) (string, error) {
+	if rigName == "" {
+		path := config.TownSettingsPath(townRoot)
+		settings, err := config.LoadOrCreateTownSettings(path)
+		if err != nil {
+			return "", fmt.Errorf("loading town settings: %w", err)
+		}
+		settings.DisabledFormulas = update(settings.DisabledFormulas)
+		if err := config.SaveTownSettings(path, settings); err != nil {
+			return "", fmt.Errorf("saving town settings: %w", err)
+		}
+		return "in the town", nil
+	}
+
+	rigPath := filepath.Join(townRoot, rigName)
+	if _, err := os.Stat(filepath.Join(rigPath, "config.json")); err != nil {
+		return "", fmt.Errorf("rig %q not found in %s", rigName, townRoot)
+	}
+	path := config.RigSettingsPath(rigPath)
+	settings, err := config.LoadRigSettings(path)
+	if errors.Is(err, config.ErrNotFound) {
+		settings, err = config.NewRigSettings(), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("loading rig settings: %w", err)
+	}
+	if settings.Workflow == nil {
+		settings.Workflow = &config.WorkflowConfig{}
+	}
+	settings.Workflow.DisabledFormulas = update(settings.Workflow.DisabledFormulas)
+	if err := config.SaveRigSettings(path, settings); err != nil {
+		return "", fmt.Errorf("saving rig settings: %w", err)
+	}
+	return "on rig " + rigName, nil
+}
+
+// formulaDisabled returns how name was disabled on rigName, or else in the
+// town, and where ("on rig gastown", "in the town"); nil if it is enabled.
+func formulaDisabled(townRoot, rigName, name string) (*config.DisabledFormula, string) {
+	if rigName != "" {
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName))); err == nil && settings.Workflow != nil {
+			if d, ok := settings.Workflow.DisabledFormulas[name]; ok {
+				return &d, "on rig " + rigName
+			}
+		}
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		if d, ok := settings.DisabledFormulas[name]; ok {
+			return &d, "in the town"
+		}
+	}
+	return nil, ""
+}
+
+// checkFormulaEnabled refuses a formula disabled on rigName or in the town.
+func checkFormulaEnabled(townRoot, rigName, name string) error {
+	d, where := formulaDisabled(townRoot, rigName, name)
+	if d == nil {
+		return nil
+	}
+	msg := fmt.Sprintf("formula %s is disabled %s", name, where)
+	if d.By != "" {
+		msg += " by " + d.By
+	}
+	if !d.At.IsZero() {
+		msg += " on " + d.At.Local().Format("2006-01-02")
+	}
+	if d.Reason != "" {
+		msg += ": " + d.Reason
+	}
+	scopeFlag := ""
+	if where != "in the town" {
+		scopeFlag = rigFlagSuffix(rigName)
+	}
+	return fmt.Errorf("%s\n\nRe-enable it with: gt formula enable %s%s", msg, name, scopeFlag)
+}
+
+// rigFlagSuffix returns " --rig <rig>" for a rig name, or "".
+func rigFlagSuffix(rigName string) string {
+	if rigName == "" {
+		return ""
+	}
+	return " --rig " + rigName
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+func TestFormulaDisableEnable(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := workspace.WriteMarker(townRoot, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "gastown", "config.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	defer func() { formulaDisableRig, formulaDisableReason = "", "" }()
+
+	// Disabled on a rig: refused there, allowed elsewhere
+	formulaDisableRig, formulaDisableReason = "gastown", "retired for Q3"
+	if err := runFormulaDisable(nil, []string{"code-review"}); err != nil {
+		t.Fatalf("disable --rig: %v", err)
+	}
+	err := checkFormulaEnabled(townRoot, "gastown", "code-review")
+	if err == nil || !strings.Contains(err.Error(), "retired for Q3") || !strings.Contains(err.Error(), "--rig gastown") {
+		t.Errorf("checkFormulaEnabled(gastown) = %v, want the reason and how to re-enable", err)
+	}
+	if err := checkFormulaEnabled(townRoot, "beads", "code-review"); err != nil {
+		t.Errorf("checkFormulaEnabled(beads) = %v, want allowed", err)
+	}
+
+	// Enabling in the town doesn't touch the rig's entry
+	formulaDisableRig = ""
+	t.Chdir(filepath.Join(townRoot, "gastown"))
+	if err := runFormulaEnable(nil, []string{"code-review"}); err == nil || !strings.Contains(err.Error(), "on rig gastown") {
+		t.Errorf("enable in town = %v, want a pointer to the rig", err)
+	}
+	formulaDisableRig = "gastown"
+	if err := runFormulaEnable(nil, []string{"code-review"}); err != nil {
+		t.Fatalf("enable --rig: %v", err)
+	}
+	if err := checkFormulaEnabled(townRoot, "gastown", "code-review"); err != nil {
+		t.Errorf("checkFormulaEnabled() after enable = %v", err)
+	}
+
+	// Disabled in the town: refused on every rig
+	formulaDisableRig, formulaDisableReason = "", ""
+	if err := runFormulaDisable(nil, []string{"code-review"}); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if err := checkFormulaEnabled(townRoot, "beads", "code-review"); err == nil {
+		t.Error("checkFormulaEnabled() allowed a formula disabled in the town")
+	}
+
+	if err := runFormulaDisable(nil, []string{"no-such-formula"}); err == nil {
+		t.Error("disable accepted an unknown formula")
+	}
+}
@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Formula edit flags
+var (
+	formulaEditAI    string
+	formulaEditAgent string
+	formulaEditYes   bool
+)
+
+var formulaEditCmd = &cobra.Command{
+	Use:   "edit <name> --ai <instruction>",
+	Short: "Edit a formula with an agent",
+	Long: `Edit a formula by describing the change in plain language.
+
+Sends the current formula and your instruction to the configured agent
+(or --agent) in non-interactive mode, validates that the reply is a
+well-formed formula, shows a diff, and writes it back on confirmation.
+
+Only TOML formulas can be edited this way. If the file is changed by hand
+while the agent is working, nothing is written: your edits are kept and the
+agent's version is saved to a temp file for comparison.
+
+Examples:
+  gt formula edit code-review --ai "add a leg that checks for race conditions"
+  gt formula edit shiny --ai "make the design step depend on research" --agent gemini
+  gt formula edit shiny --ai "rename the review leg to audit" --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaEdit,
+}
+
+func init() {
+	formulaEditCmd.Flags().StringVar(&formulaEditAI, "ai", "", "Instruction describing the change (required)")
+	formulaEditCmd.Flags().StringVar(&formulaEditAgent, "agent", "", "Agent to use (default: town/rig default agent)")
+	formulaEditCmd.Flags().BoolVarP(&formulaEditYes, "yes", "y", false, "Write the change without confirmation")
+	_ = formulaEditCmd.MarkFlagRequired("ai")
+
+	formulaCmd.AddCommand(formulaEditCmd)
+}
+
+func runFormulaEdit(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	path, err := findFormulaFile(name)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(path, ".toml") {
+		return fmt.Errorf("%s is not a TOML formula; --ai only edits .formula.toml files", path)
+	}
+
+	current, err := os.ReadFile(path) //nolint:gosec // G304: path is from formula search paths
+	if err != nil {
+		return fmt.Errorf("reading formula: %w", err)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("→"), i18n.Sprintf("Asking agent to edit %s...", name))
+	chain := agentChain(townRoot, "", formulaEditAgent)
+	reply, agent, err := runAgentChain(chain, os.Stdout, func(a string) (string, error) {
+		return runAgentOneShot(townRoot, "", a, buildFormulaEditPrompt(name, string(current), formulaEditAI))
+	})
+	if err != nil {
+		return err
+	}
+	if agent != chain[0] {
+		fmt.Printf("%s %s\n", style.Dim.Render("○"), i18n.Sprintf("Edit proposed by fallback agent %s", agent))
+	}
+
+	revised := extractTOMLReply(reply)
+	if strings.TrimSpace(revised) == "" {
+		return fmt.Errorf("agent returned an empty formula")
+	}
+	expanded, err := formula.ExpandIncludes([]byte(revised), append([]string{filepath.Dir(path)}, formulaIncludeDirs()...))
+	if err != nil {
+		return fmt.Errorf("agent returned an invalid formula: %w", err)
+	}
+	if _, err := formula.Parse(expanded); err != nil {
+		return fmt.Errorf("agent returned an invalid formula: %w", err)
+	}
+
+	diff := unifiedLineDiff(filepath.Base(path), filepath.Base(path), string(current), revised)
+	if diff == "" {
+		fmt.Printf("%s %s\n", style.Dim.Render("○"), i18n.Sprintf("Agent made no changes"))
+		return nil
+	}
+	printColoredDiff(diff)
+
+	if !formulaEditYes && !promptYesNo(i18n.Sprintf("Write changes to %s?", path)) {
+		fmt.Println(i18n.Sprintf("Aborted; formula unchanged"))
+		return nil
+	}
+	if err := writeFormulaEdit(path, current, name, revised); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("✓"), i18n.Sprintf("Updated %s", path))
+	recordFormulaOverrideAudit(name, "edit", path)
+	fireFormulaOverrideHook(name, "edit", path)
+	return nil
+}
+
+// writeFormulaEdit replaces the formula at path with the agent's revision,
+// holding the formula's lock so a concurrent gt write can't land between
+// the check and the write. The file may have been edited by hand while the
+// agent was working or while we waited for confirmation; never clobber
+// those edits.
+func writeFormulaEdit(path string, original []byte, name, revised string) error {
+	unlock, err := fsx.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := checkFormulaUnchanged(path, original, name, revised); err != nil {
+		return err
+	}
+	if err := fsx.WriteFile(path, []byte(revised), 0644); err != nil {
+		return fmt.Errorf("writing formula: %w", err)
+	}
+	return nil
+}
+
+// checkFormulaUnchanged returns an error if the formula at path no longer
+// matches the content the agent edited. The agent's revision is saved to a
+// temp file so it isn't lost.
+func checkFormulaUnchanged(path string, original []byte, name, revised string) error {
+	now, err := os.ReadFile(path) //nolint:gosec // G304: path is from formula search paths
+	if err != nil {
+		return fmt.Errorf("re-reading formula: %w", err)
+	}
+	if bytes.Equal(now, original) {
+		return nil
+	}
+	msg := fmt.Sprintf("%s was modified while the agent was editing it; your changes were kept and the agent's were not applied.\nRe-run 'gt formula edit %s --ai ...' to apply the instruction to the current file", path, name)
+	if f, err := os.CreateTemp("", name+"-*.formula.toml"); err == nil {
+		_, werr := f.WriteString(revised)
+		if cerr := f.Close(); werr == nil && cerr == nil {
+			msg += fmt.Sprintf(", or compare with the agent's version saved at %s", f.Name())
+		}
+	}
+	return errors.New(msg)
+}
+
+// buildFormulaEditPrompt asks the agent for the complete revised formula.
+func buildFormulaEditPrompt(name, current, instruction string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are editing the Gas Town formula %q.\n\n", name)
+	b.WriteString("Apply this change:\n")
+	b.WriteString(instruction)
+	b.WriteString("\n\nCurrent formula (TOML):\n```toml\n")
+	b.WriteString(current)
+	if !strings.HasSuffix(current, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("```\n\n")
+	b.WriteString("Reply with the complete revised formula as TOML and nothing else. ")
+	b.WriteString("Keep the formula name, type, and any parts unrelated to the change as they are.\n")
+	return b.String()
+}
+
+// extractTOMLReply strips commentary and markdown code fences from an agent
+// reply, returning the contents of the first fenced block if there is one.
+func extractTOMLReply(reply string) string {
+	lines := strings.Split(reply, "\n")
+	start := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start < 0 {
+			if strings.HasPrefix(trimmed, "```") {
+				start = i + 1
+			}
+			continue
+		}
+		if trimmed == "```" {
+			return strings.Join(lines[start:i], "\n") + "\n"
+		}
+	}
+	if start >= 0 {
+		return strings.Join(lines[start:], "\n")
+	}
+	return strings.TrimSpace(reply) + "\n"
+}
+
+// printColoredDiff prints a unified diff with added/removed lines highlighted.
+func printColoredDiff(diff string) {
+	fmt.Print(colorDiff(diff))
+}
+
+// colorDiff highlights the added/removed lines of a unified diff.
+func colorDiff(diff string) string {
+	var out strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			line = style.Bold.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			line = style.Dim.Render(line)
+		case strings.HasPrefix(line, "+"):
+			line = style.Success.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = style.Error.Render(line)
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+type diffOp struct {
+	kind byte // ' ', '-', or '+'
+	text string
+}
+
+// diffHunk is one "@@" section of a unified diff. Lines keep their
+// ' ', '-', or '+' prefix.
+type diffHunk struct {
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Lines    []string `json:"lines"`
+}
+
+// unifiedLineDiff returns a unified diff between a and b, or "" if they are
+// identical.
+func unifiedLineDiff(oldName, newName, a, b string) string {
+	if a == b {
+		return ""
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range lineDiffHunks(a, b) {
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		for _, line := range h.Lines {
+			out.WriteString(line)
+			out.WriteByte('\n')
+		}
+	}
+	return out.String()
+}
+
+// lineDiffHunks groups the line changes from a to b into hunks with
+// diffContext lines of context. It uses a plain LCS, which is fine for
+// formula-sized files.
+func lineDiffHunks(a, b string) []diffHunk {
+	ops := diffLines(diffSplitLines(a), diffSplitLines(b))
+
+	var hunks []diffHunk
+	i := 0
+	for i < len(ops) {
+		// Find the next change.
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		// Extend the hunk while changes are within 2*context of each other.
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				end += min(diffContext, run-end)
+				break
+			}
+			end = run
+		}
+
+		h := diffHunk{OldStart: 1, NewStart: 1}
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				h.OldStart++
+			}
+			if op.kind != '-' {
+				h.NewStart++
+			}
+		}
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				h.OldLines++
+			}
+			if op.kind != '-' {
+				h.NewLines++
+			}
+			h.Lines = append(h.Lines, string(op.kind)+op.text)
+		}
+		hunks = append(hunks, h)
+		i = end
+	}
+	return hunks
+}
+
+// diffSplitLines splits text into lines, ignoring a trailing newline.
+func diffSplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a line edit script from a to b via longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
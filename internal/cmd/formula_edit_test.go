@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestExtractTOMLReply(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  string
+	}{
+		{"bare", "formula = \"x\"\n", "formula = \"x\"\n"},
+		{"fenced", "Here you go:\n```toml\nformula = \"x\"\n```\nDone.", "formula = \"x\"\n"},
+		{"unterminated", "```\nformula = \"x\"", "formula = \"x\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractTOMLReply(tt.reply); got != tt.want {
+				t.Errorf("extractTOMLReply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedLineDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	b := "a\nb\nc\nd\nE\nf\ng\nh\ni\n"
+
+	if got := unifiedLineDiff("x", "x", a, a); got != "" {
+		t.Fatalf("identical input should produce no diff, got %q", got)
+	}
+
+	got := unifiedLineDiff("old", "new", a, b)
+	want := strings.Join([]string{
+		"--- old",
+		"+++ new",
+		"@@ -2,7 +2,8 @@",
+		" b",
+		" c",
+		" d",
+		"-e",
+		"+E",
+		" f",
+		" g",
+		" h",
+		"+i",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("unifiedLineDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAgentOneShotArgs(t *testing.T) {
+	tests := []struct {
+		agent string
+		rc    *config.RuntimeConfig
+		want  []string
+	}{
+		{"claude", &config.RuntimeConfig{Command: "claude", Args: []string{"--dangerously-skip-permissions"}},
+			[]string{"claude", "--dangerously-skip-permissions", "--print", "hi"}},
+		{"gemini", &config.RuntimeConfig{Command: "gemini", Args: []string{"--approval-mode", "yolo"}},
+			[]string{"gemini", "--approval-mode", "yolo", "-p", "hi"}},
+		{"codex", &config.RuntimeConfig{Command: "codex", Args: []string{"--yolo"}},
+			[]string{"codex", "exec", "--yolo", "hi"}},
+		{"", &config.RuntimeConfig{Command: "/usr/local/bin/my-agent"},
+			[]string{"/usr/local/bin/my-agent", "hi"}},
+	}
+	for _, tt := range tests {
+		if got := agentOneShotArgs(tt.agent, tt.rc, "hi"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("agentOneShotArgs(%q) = %v, want %v", tt.agent, got, tt.want)
+		}
+	}
+}
+
+func TestCheckFormulaUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	path := filepath.Join(dir, "x.formula.toml")
+	original := []byte("formula = \"x\"\n")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkFormulaUnchanged(path, original, "x", "revised"); err != nil {
+		t.Fatalf("unchanged file: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("formula = \"x\"\n# hand edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := checkFormulaUnchanged(path, original, "x", "revised")
+	if err == nil || !strings.Contains(err.Error(), "Re-run 'gt formula edit x") {
+		t.Fatalf("expected re-run error, got %v", err)
+	}
+	saved, _ := filepath.Glob(filepath.Join(dir, "x-*.formula.toml"))
+	if len(saved) != 1 {
+		t.Fatalf("agent revision not saved: %v", saved)
+	}
+	if got, _ := os.ReadFile(saved[0]); string(got) != "revised" {
+		t.Errorf("saved revision = %q", got)
+	}
+}
+
+func TestWriteFormulaEdit(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	path := filepath.Join(dir, "x.formula.toml")
+	original := []byte("formula = \"x\"\n")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFormulaEdit(path, original, "x", "revised"); err != nil {
+		t.Fatalf("writeFormulaEdit: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "revised" {
+		t.Errorf("formula = %q, want the revision", got)
+	}
+
+	// The file no longer matches what the agent saw: nothing is written.
+	if err := writeFormulaEdit(path, original, "x", "second"); err == nil {
+		t.Fatal("expected an error for a formula changed underneath")
+	}
+	if got, _ := os.ReadFile(path); string(got) != "revised" {
+		t.Errorf("formula = %q, want the hand edit kept", got)
+	}
+}
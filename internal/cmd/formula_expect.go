@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// LegFailedLabel is set on a convoy leg bead whose outputs violated the
+// leg's expect contract. The bead is still closed; synthesis treats it as
+// failed rather than complete.
+const LegFailedLabel = "status:failed"
+
+// resolveLegContract renders a leg's contract for dispatch: file templates
+// are expanded with the leg's prompt context and made absolute, and a
+// json_schema path is replaced by the schema it points to (relative to the
+// formula file), so gt done can check the contract from any directory.
+func resolveLegContract(leg formulaLeg, legCtx map[string]interface{}, formulaPath string) (*formula.LegExpect, error) {
+	if leg.Expect == nil {
+		return nil, nil
+	}
+	resolved := &formula.LegExpect{MinBytes: leg.Expect.MinBytes, JSONSchema: leg.Expect.JSONSchema}
+
+	for _, tmpl := range leg.Expect.Files {
+		file, err := renderTemplate(tmpl, legCtx)
+		if err != nil {
+			return nil, fmt.Errorf("rendering expect.files %q: %w", tmpl, err)
+		}
+		if abs, err := filepath.Abs(file); err == nil {
+			file = abs
+		}
+		resolved.Files = append(resolved.Files, file)
+	}
+
+	if resolved.JSONSchema != "" && !resolved.IsInlineSchema() {
+		schemaPath := resolved.JSONSchema
+		if !filepath.IsAbs(schemaPath) {
+			schemaPath = filepath.Join(filepath.Dir(formulaPath), schemaPath)
+		}
+		data, err := os.ReadFile(schemaPath) //nolint:gosec // G304: schema path comes from the formula
+		if err != nil {
+			return nil, fmt.Errorf("reading expect.json_schema: %w", err)
+		}
+		resolved.JSONSchema = string(data)
+	}
+	return resolved, nil
+}
+
+// legContractNote tells the leg's agent what its outputs must satisfy.
+func legContractNote(c *formula.LegExpect) string {
+	var b strings.Builder
+	b.WriteString("gt done checks these outputs; the leg is marked failed if they are missing or invalid.\n")
+	for _, file := range c.Files {
+		fmt.Fprintf(&b, "- write %s\n", file)
+	}
+	if c.MinBytes > 0 {
+		fmt.Fprintf(&b, "- each file at least %d bytes\n", c.MinBytes)
+	}
+	if c.JSONSchema != "" {
+		fmt.Fprintf(&b, "- each file valid JSON matching:\n%s\n", c.JSONSchema)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// checkLegContract validates the contract recorded in a leg's sling payload.
+// Returns nil if the bead has no payload or its payload declares no contract.
+func checkLegContract(townRoot, issueID string) []string {
+	p, err := loadSlingPayload(slingPayloadPath(townRoot, issueID))
+	if err != nil || p.Expect == nil {
+		return nil
+	}
+	return p.Expect.Check(townRoot)
+}
+
+// hasLabel reports whether labels contains label.
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// markLegFailed labels a leg bead failed, and with the kind of failure for
+// its retry policy, and records why in a comment.
+func markLegFailed(cwd, issueID string, violations []string) error {
+	bd := beads.New(beads.ResolveBeadsDir(cwd))
+	labels := []string{LegFailedLabel, LegFailureLabelPrefix + formula.ViolationsKind(violations)}
+	if err := bd.Update(issueID, beads.UpdateOptions{AddLabels: labels}); err != nil {
+		return err
+	}
+	reason := "Leg contract violated:\n- " + strings.Join(violations, "\n- ")
+	commentCmd := exec.Command("bd", "comment", issueID, reason)
+	commentCmd.Dir = resolveBeadDir(issueID)
+	return cmdtrace.Run(commentCmd)
+}
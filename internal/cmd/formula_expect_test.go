@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLegExpect(t *testing.T) {
+	f, err := parseFormulaContent(t, `formula = "review"
+
+[[legs]]
+id = "security"
+title = "Security"
+expect.files = ["{{ .output_path }}", "extra.json"]
+expect.min_bytes = 200
+expect.json_schema = "schemas/findings.json"
+
+[[legs]]
+id = "style"
+title = "Style"
+`)
+	if err != nil || len(f.Legs) != 2 {
+		t.Fatalf("parseFormulaFile() = %+v, %v", f, err)
+	}
+	legs := f.Legs
+	e := legs[0].Expect
+	if e == nil || len(e.Files) != 2 || e.Files[0] != "{{ .output_path }}" || e.MinBytes != 200 || e.JSONSchema != "schemas/findings.json" {
+		t.Errorf("security Expect = %+v", e)
+	}
+	if legs[1].Expect != nil {
+		t.Errorf("style Expect = %+v, want nil", legs[1].Expect)
+	}
+}
+
+func TestResolveLegContract(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	schema := `{"type": "object"}`
+	if err := os.WriteFile(filepath.Join(dir, "schemas", "findings.json"), []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	formulaPath := filepath.Join(dir, "review.formula.toml")
+	ctx := map[string]interface{}{"output_path": filepath.Join(dir, "out", "security.md")}
+
+	if c, err := resolveLegContract(formulaLeg{ID: "style"}, ctx, formulaPath); err != nil || c != nil {
+		t.Fatalf("leg without contract = %+v, %v; want nil", c, err)
+	}
+
+	f, err := parseFormulaContent(t, "formula = \"review\"\n[[legs]]\nid = \"security\"\nexpect.files = [\"{{ .output_path }}\"]\nexpect.json_schema = \"schemas/findings.json\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leg := f.Legs[0]
+	c, err := resolveLegContract(leg, ctx, formulaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Files) != 1 || c.Files[0] != filepath.Join(dir, "out", "security.md") {
+		t.Errorf("Files = %v", c.Files)
+	}
+	if c.JSONSchema != schema {
+		t.Errorf("JSONSchema = %q, want schema file contents", c.JSONSchema)
+	}
+
+	leg.Expect.JSONSchema = "schemas/missing.json"
+	if _, err := resolveLegContract(leg, ctx, formulaPath); err == nil {
+		t.Error("expected error for missing schema file")
+	}
+}
+
+func TestLegOutputComplete(t *testing.T) {
+	if !(LegOutput{Status: "closed"}).Complete() {
+		t.Error("closed leg should be complete")
+	}
+	if (LegOutput{Status: "closed", Failed: true}).Complete() {
+		t.Error("failed leg should not be complete")
+	}
+	if (LegOutput{Status: "open"}).Complete() {
+		t.Error("open leg should not be complete")
+	}
+}
+
+func TestParseFormulaEnv(t *testing.T) {
+	content := `
+formula = "review"
+type = "convoy"
+
+[env]
+GOFLAGS = "-mod=mod"
+API_TOKEN = "secret:env:REVIEW_TOKEN"
+
+[[legs]]
+id = "security"
+env.GOFLAGS = "-race"
+runner = "docker"
+image = "golang:1.22"
+mounts = ["/cache:/cache:ro"]
+
+[[legs]]
+id = "style"
+`
+	f, err := parseFormulaContent(t, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := f.Env
+	if env["GOFLAGS"] != "-mod=mod" || env["API_TOKEN"] != "secret:env:REVIEW_TOKEN" || len(env) != 2 {
+		t.Errorf("formula env = %v", env)
+	}
+	legs := f.Legs
+	if len(legs) != 2 || legs[0].Env["GOFLAGS"] != "-race" || legs[1].Env != nil {
+		t.Errorf("leg env = %+v", legs)
+	}
+	if legs[0].Runner != "docker" || legs[0].Image != "golang:1.22" || len(legs[0].Mounts) != 1 || legs[1].Runner != "" {
+		t.Errorf("leg runner = %+v", legs)
+	}
+	if f, err := parseFormulaContent(t, "formula = \"x\"\n[[legs]]\nid = \"a\"\nenv.X = \"1\"\n"); err != nil || f.Env != nil {
+		t.Errorf("leg env leaked into formula env: %+v, %v", f, err)
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/optree"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// explainFormulaRun describes what gt formula run is about to do for
+// --explain. Bead IDs are generated when the run starts, so the plan
+// names them by leg.
+func explainFormulaRun(f *formulaData, formulaName, targetRig string) *optree.Node {
+	if formulaRunLocalAgent || formulaRunOutput != "" {
+		return explainFormulaRunLocal(f, formulaName, targetRig)
+	}
+	f = expandConsensusLegs(f)
+
+	townRoot, _ := workspace.FindFromCwd()
+	townBeads := filepath.Join(townRoot, ".beads")
+	plan := optree.New("gt formula run %s (rig %s, %d leg(s))", formulaName, targetRig, len(f.Legs))
+	if formulaRunWatchPR {
+		plan.Step("Watch PR #%d and repeat this run on each new head commit", formulaRunPR)
+	}
+	if formulaRunPR > 0 && formulaRunReplay == nil {
+		plan.Step("Fetch PR #%d title, changed files and diff", formulaRunPR)
+	}
+
+	if !formulaRunNoMolecule {
+		plan.Step("Cook formula %s to a proto", formulaName).
+			Run(optree.In(townBeads, "bd", "cook", formulaName))
+	}
+	convoyID := "hq-cv-<id>"
+	createArgs := []string{"bd", "create", "--type=convoy", "--id=" + convoyID, "--title=" + formulaName + ": " + f.Description}
+	labels, _ := formulaRunBeadLabels()
+	if len(labels) > 0 {
+		createArgs = append(createArgs, "--labels="+strings.Join(labels, ","))
+	}
+	plan.Step("Create convoy bead %s", convoyID).
+		Run(optree.In(townBeads, createArgs...))
+
+	batch := plan.Step("Create leg and synthesis beads in one batch")
+	if !formulaRunNoMolecule {
+		batch.Step("molecule: hq-mol-<id> epic poured from the proto, parent of each leg and synthesis bead")
+	}
+	for _, leg := range orderedLegs(f) {
+		node := batch.Step("leg %s: hq-leg-<%s> %q", leg.ID, leg.ID, leg.Title)
+		if leg.Workdir != "" || leg.Branch != "" {
+			node.Step("prepare an isolated worktree (git fetch, worktree add)")
+		}
+	}
+	if f.Synthesis != nil && !f.Synthesis.Require.IsPartial() {
+		batch.Step("synthesis: hq-syn-<id> %q, blocked until legs complete", f.Synthesis.Title)
+	}
+	batch.Run(optree.In(townBeads, "bd", "import", "-i", "<batch>.jsonl"))
+	batch.Step("convoy tracks each bead").
+		Run(optree.In(townBeads, "bd", "dep", "add", convoyID, "<bead-id>", "--type=tracks"))
+
+	if formulaRunNeedsApproval(f, townRoot, targetRig) {
+		plan.Step("Hold the legs for approval and mail the approvers; gt convoy approve releases them")
+	}
+	sling := plan.Step("Sling legs to polecats on %s", targetRig)
+	for _, leg := range f.Legs {
+		if len(leg.Needs) > 0 {
+			sling.Step("leg %s waits (%s); gt convoy check slings it", leg.ID, describeLegNeeds(leg))
+			continue
+		}
+		payload := slingPayloadPath(townRoot, "hq-leg-<"+leg.ID+">")
+		sling.Run(optree.Cmd("gt", "sling", "hq-leg-<"+leg.ID+">", targetRig, "--context-file", payload))
+	}
+	if f.Synthesis != nil && f.Synthesis.Require.IsPartial() {
+		plan.Step("Synthesis starts once %s legs complete (gt synthesis start)", f.Synthesis.Require)
+	}
+	plan.Step("Record the run in %s's audit log", targetRig)
+	return plan
+}
+
+// explainFormulaRunLocal describes a --local-agent run: each leg is a
+// one-shot agent call whose reply is written to the leg's output file.
+func explainFormulaRunLocal(f *formulaData, formulaName, targetRig string) *optree.Node {
+	townRoot, rigPath := ".", "."
+	if root, err := workspace.FindFromCwd(); err == nil && root != "" {
+		townRoot, rigPath = root, filepath.Join(root, targetRig)
+	}
+	agent := formulaRunAgent
+	if agent == "" {
+		agent = f.Agent
+	}
+	var argv func(prompt string) []string
+	if rc, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, agent); err == nil {
+		argv = func(prompt string) []string { return agentOneShotArgs(agentName, rc, prompt) }
+	}
+
+	parallel := formulaRunParallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	plan := optree.New("gt formula run %s --local-agent (%d leg(s), %d at a time)", formulaName, len(f.Legs), parallel)
+	outputDir := filepath.Join(".reviews", "<review-id>")
+	if formulaRunOutput != "" {
+		outputDir = filepath.Join("<tmp>", "gt-review-<review-id>")
+	}
+	plan.Step("Create output directory %s", outputDir)
+	if formulaRunPR > 0 {
+		plan.Step("Fetch PR #%d title, changed files and diff", formulaRunPR)
+	}
+
+	agentCall := func(node *optree.Node, prompt string) {
+		if argv == nil {
+			node.Step("run the configured agent (could not resolve it)")
+			return
+		}
+		node.Run(optree.In(townRoot, argv(prompt)...))
+	}
+	legs := plan.Step("Run legs with the one-shot agent")
+	for _, leg := range orderedLegs(f) {
+		label := fmt.Sprintf("leg %s → %s", leg.ID, filepath.Join(outputDir, leg.ID+"-findings.md"))
+		if len(leg.Needs) > 0 {
+			label += " (" + describeLegNeeds(leg) + ")"
+		}
+		if leg.Consensus != nil {
+			node := legs.Step("%s (%s)", label, describeLegConsensus(leg.Consensus))
+			for _, a := range leg.Consensus.Agents {
+				step := node.Step("ask %s → %s", a, filepath.Join(outputDir, formula.ConsensusLegID(leg.ID, a)+"-findings.md"))
+				if rc, name, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, a); err == nil {
+					step.Run(optree.In(townRoot, agentOneShotArgs(name, rc, "<prompt for "+leg.ID+">")...))
+				}
+			}
+			node.Step("Merge the agents' findings, keeping those the quorum reported")
+			continue
+		}
+		agentCall(legs.Step("%s", label), "<prompt for "+leg.ID+">")
+	}
+	if f.Synthesis != nil {
+		agentCall(plan.Step("Synthesize %s from the leg outputs", f.Synthesis.Title), "<synthesis prompt>")
+	}
+	switch formulaRunOutput {
+	case "":
+	case "-":
+		plan.Step("Write the report to stdout")
+	default:
+		plan.Step("Write the report to %s", formulaRunOutput)
+	}
+	return plan
+}
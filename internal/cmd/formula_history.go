@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/history"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/version"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Formula history flags
+var (
+	formulaHistoryVerbose bool
+	formulaHistoryRig     string
+	formulaHistoryLimit   int
+	formulaHistoryJSON    bool
+	formulaHistoryLabels  []string
+)
+
+// historyFormula and historyRunEnv are recorded with the command in gt
+// history once a formula run finishes.
+var (
+	historyFormula string
+	historyRunEnv  *history.Fingerprint
+)
+
+// fingerprintProbeTimeout bounds each version probe of a run fingerprint.
+const fingerprintProbeTimeout = 3 * time.Second
+
+var formulaHistoryCmd = &cobra.Command{
+	Use:   "history [name]",
+	Short: "Show recorded formula runs and the environment they ran in",
+	Long: `Show the formula runs recorded in gt history, newest last.
+
+Every formula run records a fingerprint of its environment: the gt and bd
+versions, the version each agent it used reports, the OS, and the git SHA
+of the rig's HEAD. With --verbose the fingerprint is shown for each run,
+along with what changed since the previous run of the same formula on the
+same rig, to trace "this worked last week" regressions to environment
+drift.
+
+Examples:
+  gt formula history
+  gt formula history code-review --verbose
+  gt formula history --label team=infra
+  gt formula history --rig gastown --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFormulaHistory,
+}
+
+func init() {
+	formulaHistoryCmd.Flags().BoolVarP(&formulaHistoryVerbose, "verbose", "v", false, "Show each run's environment fingerprint and what changed since the previous run")
+	formulaHistoryCmd.Flags().StringVar(&formulaHistoryRig, "rig", "", "Only show runs against this rig")
+	formulaHistoryCmd.Flags().IntVarP(&formulaHistoryLimit, "limit", "n", 20, "Maximum number of runs to show (0 for all)")
+	formulaHistoryCmd.Flags().BoolVar(&formulaHistoryJSON, "json", false, "Output as JSON")
+	formulaHistoryCmd.Flags().StringArrayVar(&formulaHistoryLabels, "label", nil, "Only show runs with this --label, as key=value (repeatable)")
+	formulaCmd.AddCommand(formulaHistoryCmd)
+}
+
+func runFormulaHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	all, err := history.Read(townRoot)
+	if err != nil {
+		return err
+	}
+	labels, err := parseRunLabels(formulaHistoryLabels)
+	if err != nil {
+		return err
+	}
+
+	var runs, matched []history.Entry
+	for _, e := range all {
+		if e.Formula == "" || (len(args) > 0 && e.Formula != args[0]) {
+			continue
+		}
+		if formulaHistoryRig != "" && e.Rig != formulaHistoryRig {
+			continue
+		}
+		if !hasRunLabels(e.Labels, labels) {
+			continue
+		}
+		matched = append(matched, e)
+		if !e.IsLegRetry() {
+			runs = append(runs, e)
+		}
+	}
+	changes := formulaRunChanges(runs)
+	if formulaHistoryLimit > 0 && len(runs) > formulaHistoryLimit {
+		changes = changes[len(runs)-formulaHistoryLimit:]
+		runs = runs[len(runs)-formulaHistoryLimit:]
+	}
+
+	if formulaHistoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if runs == nil {
+			runs = []history.Entry{}
+		}
+		return enc.Encode(runs)
+	}
+
+	retried := legRetryStats(matched)
+	if len(runs) == 0 && len(retried) == 0 {
+		fmt.Printf("%s No formula runs recorded\n", style.Dim.Render("○"))
+		return nil
+	}
+	for i, e := range runs {
+		mark := style.Success.Render("✓")
+		if e.Failed() {
+			mark = style.Error.Render("✗")
+		}
+		where := ""
+		if e.Rig != "" {
+			where = " [" + e.Rig + "]"
+		}
+		fmt.Printf("%s %s  %-8s %s%s  %s\n", mark,
+			e.Time.Local().Format("2006-01-02 15:04:05"), formatHistoryDuration(e.DurationMs),
+			e.Formula, style.Dim.Render(where), style.Dim.Render(strings.Join(append([]string{"gt"}, e.Args...), " ")))
+		if e.Error != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(e.Error))
+		}
+		if len(e.Labels) > 0 {
+			fmt.Printf("    %s\n", style.Dim.Render("labels: "+strings.Join(e.Labels, ", ")))
+		}
+		if len(e.LegAttempts) > 0 {
+			fmt.Printf("    %s %s\n", style.Warning.Render("retried"), describeLegAttempts(e.LegAttempts))
+		}
+		if !formulaHistoryVerbose {
+			continue
+		}
+		if e.Env == nil {
+			fmt.Printf("    %s\n", style.Dim.Render("(no environment recorded)"))
+			continue
+		}
+		for _, line := range e.Env.Lines() {
+			fmt.Printf("    %s\n", style.Dim.Render(line))
+		}
+		for _, change := range changes[i] {
+			fmt.Printf("    %s %s\n", style.Warning.Render("changed"), change)
+		}
+	}
+
+	// Legs that keep needing retries are flaky, whatever the runs' outcome
+	if len(retried) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Retried legs:"))
+		for _, r := range retried {
+			fmt.Printf("  %s/%s  retried %d time(s) in %d run(s)\n", r.Formula, r.Leg, r.Retries, r.Runs)
+		}
+	}
+	return nil
+}
+
+// describeLegAttempts lists the attempts of each retried leg of a run.
+func describeLegAttempts(attempts map[string]int) string {
+	legs := make([]string, 0, len(attempts))
+	for leg := range attempts {
+		legs = append(legs, leg)
+	}
+	sort.Strings(legs)
+	parts := make([]string, len(legs))
+	for i, leg := range legs {
+		parts[i] = fmt.Sprintf("%s (%d attempts)", leg, attempts[leg])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// legRetryStat totals a formula leg's retries across recorded runs.
+type legRetryStat struct {
+	Formula string
+	Leg     string
+	Retries int // Retries across all runs
+	Runs    int // Runs in which the leg was retried
+}
+
+// legRetryStats totals the retries of each formula leg, most retried
+// first. Local runs record a leg's attempts with the run; convoy runs
+// record one entry per retry, counted as one run per convoy.
+func legRetryStats(entries []history.Entry) []legRetryStat {
+	stats := make(map[string]*legRetryStat)
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		for leg, attempts := range e.LegAttempts {
+			key := e.Formula + "\x00" + leg
+			s := stats[key]
+			if s == nil {
+				s = &legRetryStat{Formula: e.Formula, Leg: leg}
+				stats[key] = s
+			}
+			if !e.IsLegRetry() {
+				s.Retries += attempts - 1
+				s.Runs++
+				continue
+			}
+			s.Retries++
+			if run := key + "\x00" + e.Convoy; !seen[run] {
+				seen[run] = true
+				s.Runs++
+			}
+		}
+	}
+	out := make([]legRetryStat, 0, len(stats))
+	for _, s := range stats {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Retries != out[j].Retries {
+			return out[i].Retries > out[j].Retries
+		}
+		if out[i].Formula != out[j].Formula {
+			return out[i].Formula < out[j].Formula
+		}
+		return out[i].Leg < out[j].Leg
+	})
+	return out
+}
+
+// formulaRunChanges returns, for each run, how its environment differs
+// from the previous recorded run of the same formula on the same rig.
+func formulaRunChanges(runs []history.Entry) [][]string {
+	changes := make([][]string, len(runs))
+	prev := make(map[string]*history.Fingerprint)
+	for i, e := range runs {
+		if e.Env == nil {
+			continue
+		}
+		key := e.Formula + "\x00" + e.Rig
+		if p := prev[key]; p != nil {
+			changes[i] = e.Env.Changes(p)
+		}
+		prev[key] = e.Env
+	}
+	return changes
+}
+
+// recordFormulaRunEnv fingerprints the environment of a formula run for
+// gt history.
+func recordFormulaRunEnv(f *formulaData, formulaName, townRoot, rigPath string) {
+	historyFormula = formulaName
+	historyRunEnv = collectRunFingerprint(townRoot, rigPath, formulaRunAgents(f, townRoot, rigPath))
+}
+
+// recordReplayRunEnv fingerprints the environment of an exact rerun, which
+// uses the agents its recorded payloads name.
+func recordReplayRunEnv(snap *runSnapshot, townRoot string) {
+	rigPath := filepath.Join(townRoot, snap.Rig)
+	seen := make(map[string]bool)
+	var agents []string
+	for _, leg := range snap.Legs {
+		agent := ""
+		if leg.Payload != nil {
+			agent = leg.Payload.Agent
+		}
+		if agent == "" {
+			if _, name, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, ""); err == nil {
+				agent = name
+			}
+		}
+		if agent != "" && !seen[agent] {
+			seen[agent] = true
+			agents = append(agents, agent)
+		}
+	}
+	sort.Strings(agents)
+	historyFormula = snap.Formula
+	historyRunEnv = collectRunFingerprint(townRoot, rigPath, agents)
+}
+
+// formulaRunAgents returns the agents a run of f uses, by name: each leg's
+// agent (--agent for local runs), every consensus agent, and the rig's
+// default for legs that name none.
+func formulaRunAgents(f *formulaData, townRoot, rigPath string) []string {
+	def := formulaRunAgent
+	if def == "" {
+		def = f.Agent
+	}
+	if def == "" && rigPath != "" {
+		if _, name, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, ""); err == nil {
+			def = name
+		}
+	}
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" {
+			seen[name] = true
+		}
+	}
+	add(def)
+	for _, leg := range f.Legs {
+		if leg.Consensus != nil {
+			for _, agent := range leg.Consensus.Agents {
+				add(agent)
+			}
+		}
+		add(leg.Agent)
+	}
+	agents := make([]string, 0, len(seen))
+	for name := range seen {
+		agents = append(agents, name)
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+// collectRunFingerprint probes the versions a run depends on, in parallel.
+// Probes that fail are left empty.
+func collectRunFingerprint(townRoot, rigPath string, agents []string) *history.Fingerprint {
+	fp := &history.Fingerprint{
+		GT:     Version,
+		OS:     runtime.GOOS + "/" + runtime.GOARCH,
+		Agents: make(map[string]string, len(agents)),
+	}
+	if commit := resolveCommitHash(); commit != "" {
+		fp.GT += " (" + version.ShortCommit(commit) + ")"
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	probe := func(set func(string), fn func() string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := fn()
+			mu.Lock()
+			defer mu.Unlock()
+			set(v)
+		}()
+	}
+	probe(func(v string) { fp.BD = v }, func() string {
+		v, _ := getBeadsVersion()
+		return v
+	})
+	if rigPath != "" {
+		probe(func(v string) { fp.RigHead = v }, func() string {
+			repo, err := legRepoBase(rigPath)
+			if err != nil {
+				return ""
+			}
+			sha, _ := repo.Rev("HEAD")
+			return sha
+		})
+	}
+	for _, agent := range agents {
+		agent := agent
+		probe(func(v string) { fp.Agents[agent] = v }, func() string {
+			return agentVersion(townRoot, rigPath, agent)
+		})
+	}
+	wg.Wait()
+	return fp
+}
+
+// agentVersion returns the first line an agent's command prints for
+// --version, or "" if it can't be asked.
+func agentVersion(townRoot, rigPath, agent string) string {
+	if rigPath == "" {
+		rigPath = townRoot
+	}
+	rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, agent)
+	if err != nil || rc.Command == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), fingerprintProbeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, rc.Command, "--version").Output() //nolint:gosec // G204: agent command comes from town/rig config
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(line)
+}
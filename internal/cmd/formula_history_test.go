@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/history"
+)
+
+func TestFormulaRunChanges(t *testing.T) {
+	runs := []history.Entry{
+		{Formula: "review", Rig: "gastown", Env: &history.Fingerprint{BD: "0.44.0"}},
+		{Formula: "review", Rig: "beads", Env: &history.Fingerprint{BD: "0.45.0"}},
+		{Formula: "review", Rig: "gastown"},
+		{Formula: "review", Rig: "gastown", Env: &history.Fingerprint{BD: "0.45.0"}},
+	}
+	changes := formulaRunChanges(runs)
+	if len(changes[0]) != 0 || len(changes[1]) != 0 || len(changes[2]) != 0 {
+		t.Errorf("changes = %q, want none before the second gastown run", changes)
+	}
+	if got := strings.Join(changes[3], "; "); got != "bd: 0.44.0 → 0.45.0" {
+		t.Errorf("changes[3] = %q", got)
+	}
+}
+
+func TestFormulaRunAgents(t *testing.T) {
+	f := &formulaData{
+		Agent: "claude",
+		Legs: []formulaLeg{
+			{ID: "a"},
+			{ID: "b", Agent: "codex"},
+			{ID: "c", Consensus: &formula.Consensus{Agents: []string{"gemini", "claude"}}},
+		},
+	}
+	if got := strings.Join(formulaRunAgents(f, "", ""), ","); got != "claude,codex,gemini" {
+		t.Errorf("formulaRunAgents() = %s", got)
+	}
+}
+
+func TestCollectRunFingerprint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script agent stub")
+	}
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte("#!/bin/sh\necho '2.0.3 (Claude Code)'\necho extra\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	town := t.TempDir()
+	fp := collectRunFingerprint(town, filepath.Join(town, "gastown"), []string{"claude"})
+	if fp.Agents["claude"] != "2.0.3 (Claude Code)" {
+		t.Errorf("claude version = %q", fp.Agents["claude"])
+	}
+	if fp.OS != runtime.GOOS+"/"+runtime.GOARCH || !strings.HasPrefix(fp.GT, Version) {
+		t.Errorf("fingerprint = %+v", fp)
+	}
+	if fp.BD != "" || fp.RigHead != "" {
+		t.Errorf("fingerprint = %+v, want bd and rig HEAD unknown without bd or a repo", fp)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"io"
+	mrand "math/rand/v2"
+	"os"
+	"strings"
+	"sync"
+)
+
+// deterministicIDsEnv, when set, seeds formula run IDs (convoys, legs,
+// synthesis beads, and review IDs) from its value, so the same commands
+// produce the same IDs for snapshot tests. Each gt process restarts the
+// sequence: give every invocation its own seed when IDs must stay unique
+// across runs in one town.
+const deterministicIDsEnv = "GT_DETERMINISTIC_IDS"
+
+var (
+	formulaIDMu     sync.Mutex
+	formulaIDSource io.Reader // Set on first use; tests replace it with setFormulaIDSource
+)
+
+// generateFormulaShortID generates a short random ID (5 lowercase chars)
+func generateFormulaShortID() string {
+	formulaIDMu.Lock()
+	defer formulaIDMu.Unlock()
+	if formulaIDSource == nil {
+		formulaIDSource = defaultFormulaIDSource()
+	}
+	b := make([]byte, 3)
+	_, _ = io.ReadFull(formulaIDSource, b) // crypto/rand only fails on a broken system
+	return strings.ToLower(base32.StdEncoding.EncodeToString(b)[:5])
+}
+
+// defaultFormulaIDSource is crypto/rand, or a ChaCha8 stream seeded from
+// GT_DETERMINISTIC_IDS when that is set.
+func defaultFormulaIDSource() io.Reader {
+	seed := os.Getenv(deterministicIDsEnv)
+	if seed == "" {
+		return rand.Reader
+	}
+	return seededIDSource(seed)
+}
+
+// seededIDSource returns a reproducible byte stream for seed.
+func seededIDSource(seed string) io.Reader {
+	return mrand.NewChaCha8(sha256.Sum256([]byte(seed)))
+}
+
+// setFormulaIDSource makes generateFormulaShortID read from r until the
+// returned restore function is called.
+func setFormulaIDSource(r io.Reader) (restore func()) {
+	formulaIDMu.Lock()
+	defer formulaIDMu.Unlock()
+	prev := formulaIDSource
+	formulaIDSource = r
+	return func() {
+		formulaIDMu.Lock()
+		defer formulaIDMu.Unlock()
+		formulaIDSource = prev
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGenerateFormulaShortIDSeeded(t *testing.T) {
+	ids := func(seed string) []string {
+		restore := setFormulaIDSource(seededIDSource(seed))
+		defer restore()
+		var out []string
+		for i := 0; i < 5; i++ {
+			out = append(out, generateFormulaShortID())
+		}
+		return out
+	}
+
+	first, second := ids("golden"), ids("golden")
+	valid := regexp.MustCompile(`^[a-z2-7]{5}$`)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("id %d = %q then %q; want the same for one seed", i, first[i], second[i])
+		}
+		if !valid.MatchString(first[i]) {
+			t.Errorf("id %q is not 5 base32 characters", first[i])
+		}
+	}
+	if other := ids("other"); other[0] == first[0] && other[1] == first[1] {
+		t.Errorf("seeds %q and %q gave the same ids %v", "golden", "other", first)
+	}
+}
+
+func TestDefaultFormulaIDSourceEnv(t *testing.T) {
+	t.Setenv(deterministicIDsEnv, "snapshot")
+	restore := setFormulaIDSource(defaultFormulaIDSource())
+	got := generateFormulaShortID()
+	restore()
+
+	restore = setFormulaIDSource(seededIDSource("snapshot"))
+	defer restore()
+	if want := generateFormulaShortID(); got != want {
+		t.Errorf("with %s=snapshot got id %q, want %q", deterministicIDsEnv, got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Run label flags
+var formulaRunLabelArgs []string
+
+// historyRunLabels is recorded with the command in gt history once a
+// formula run finishes.
+var historyRunLabels []string
+
+func init() {
+	formulaRunCmd.Flags().StringArrayVar(&formulaRunLabelArgs, "label", nil, "Label every bead the run creates, as key=value (repeatable; e.g. team=infra)")
+}
+
+// formulaRunBeadLabels validates --label and returns the bead labels it
+// asks for, as key:value like gt's own labels.
+func formulaRunBeadLabels() ([]string, error) {
+	return parseRunLabels(formulaRunLabelArgs)
+}
+
+// parseRunLabels turns key=value arguments into key:value bead labels,
+// dropping repeats.
+func parseRunLabels(args []string) ([]string, error) {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("--label %q: want key=value (e.g. team=infra)", arg)
+		}
+		if strings.ContainsAny(arg, ", \t\n") || strings.Contains(key, ":") {
+			return nil, fmt.Errorf("--label %q: keys and values can't contain commas or spaces, and keys can't contain ':'", arg)
+		}
+		label := key + ":" + value
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels, nil
+}
+
+// hasRunLabels reports whether labels include every one of want.
+func hasRunLabels(labels, want []string) bool {
+	for _, w := range want {
+		if !hasLabel(labels, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRunLabels(t *testing.T) {
+	labels, err := parseRunLabels([]string{"team=infra", "sprint=42", "incident=INC-7", "team=infra"})
+	if err != nil {
+		t.Fatalf("parseRunLabels() = %v", err)
+	}
+	if got := strings.Join(labels, ","); got != "team:infra,sprint:42,incident:INC-7" {
+		t.Errorf("parseRunLabels() = %q", got)
+	}
+
+	for _, bad := range []string{"team", "=infra", "team=", "team=a,b", "team=a b", "a:b=c"} {
+		if _, err := parseRunLabels([]string{bad}); err == nil {
+			t.Errorf("parseRunLabels(%q) = nil error", bad)
+		}
+	}
+}
+
+func TestHasRunLabels(t *testing.T) {
+	labels := []string{"team:infra", "sprint:42"}
+	if !hasRunLabels(labels, nil) || !hasRunLabels(labels, []string{"sprint:42"}) {
+		t.Error("hasRunLabels() = false for labels the run has")
+	}
+	if hasRunLabels(labels, []string{"team:infra", "sprint:43"}) || hasRunLabels(nil, []string{"team:infra"}) {
+		t.Error("hasRunLabels() = true for a label the run lacks")
+	}
+}
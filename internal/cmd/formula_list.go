@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// formulaCatalog lists every formula by name: search-path files first (in
+// search order, so the file that would run wins), then embedded formulas
+// not shadowed by a file. typeFilter, if set, keeps only that type.
+func formulaCatalog(typeFilter string) []formula.Metadata {
+	cache := openFormulaCache()
+	defer func() { _ = cache.Save() }()
+
+	seen := make(map[string]bool)
+	var catalog []formula.Metadata
+
+	for _, sp := range formulaSearchPaths() {
+		entries, err := os.ReadDir(sp.Dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := formulaFileName(entry.Name())
+			if entry.IsDir() || !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			path := filepath.Join(sp.Dir, entry.Name())
+			catalog = append(catalog, formulaFileMetadata(cache, name, path, sp.Label))
+		}
+	}
+
+	for _, m := range formula.EmbeddedCatalog() {
+		if !seen[m.Name] {
+			seen[m.Name] = true
+			catalog = append(catalog, m)
+		}
+	}
+
+	if typeFilter != "" {
+		filtered := catalog[:0]
+		for _, m := range catalog {
+			if string(m.Type) == typeFilter {
+				filtered = append(filtered, m)
+			}
+		}
+		catalog = filtered
+	}
+
+	sort.SliceStable(catalog, func(i, j int) bool {
+		if catalog[i].Category != catalog[j].Category {
+			return catalog[i].Category < catalog[j].Category
+		}
+		return catalog[i].Name < catalog[j].Name
+	})
+	return catalog
+}
+
+// formulaFileName returns the formula name for a file in a search path.
+func formulaFileName(file string) (string, bool) {
+	for _, ext := range formulaExtensions {
+		if name := strings.TrimSuffix(file, ext); name != file {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// openFormulaCache opens the town's formula metadata cache, or an
+// in-memory one outside a town.
+func openFormulaCache() *formula.MetadataCache {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return formula.LoadMetadataCache("")
+	}
+	return formula.LoadMetadataCache(formula.CachePath(townRoot))
+}
+
+// formulaFileMetadata reads catalog metadata for a formula file. JSON
+// formulas and files that fail to parse are listed by name only.
+func formulaFileMetadata(cache *formula.MetadataCache, name, path, source string) formula.Metadata {
+	m := formula.Metadata{Name: name, Category: "other"}
+	if _, parsed, err := cache.Lookup(path); err == nil && parsed != nil {
+		m = *parsed
+		m.Name = name
+	}
+	m.Source = source
+	m.Path = path
+	return m
+}
+
+// formulaCounts summarizes a formula's size, e.g. "5 legs" or "3 steps".
+func formulaCounts(m formula.Metadata) string {
+	var parts []string
+	add := func(n int, unit string) {
+		switch {
+		case n == 1:
+			parts = append(parts, "1 "+unit)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, unit))
+		}
+	}
+	add(m.Legs, "leg")
+	add(m.Steps, "step")
+	add(m.Templates, "template")
+	add(m.Aspects, "aspect")
+	return strings.Join(parts, ", ")
+}
+
+// runFormulaList prints the formula catalog grouped by category.
+func runFormulaList(cmd *cobra.Command, args []string) error {
+	catalog := formulaCatalog(formulaListType)
+	markDisabledFormulas(catalog)
+
+	if formulaListJSON {
+		if catalog == nil {
+			catalog = []formula.Metadata{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(catalog)
+	}
+
+	if len(catalog) == 0 {
+		if formulaListType != "" {
+			fmt.Printf("No %s formulas found.\n", formulaListType)
+		} else {
+			fmt.Println("No formulas found.")
+		}
+		return nil
+	}
+
+	var table *style.Table
+	flush := func() {
+		if table != nil {
+			fmt.Print(table.Render())
+			fmt.Println()
+		}
+	}
+	category := ""
+	for _, m := range catalog {
+		if table == nil || m.Category != category {
+			flush()
+			category = m.Category
+			fmt.Printf("%s\n", style.Bold.Render(strings.ToUpper(category)))
+			table = style.NewTable(
+				style.Column{Name: i18n.Sprintf("NAME"), Width: 28},
+				style.Column{Name: i18n.Sprintf("TYPE"), Width: 9},
+				style.Column{Name: i18n.Sprintf("SIZE"), Width: 12},
+				style.Column{Name: i18n.Sprintf("SOURCE"), Width: 8, Style: style.Dim},
+				style.Column{Name: i18n.Sprintf("DESCRIPTION"), Width: 56},
+			)
+		}
+		desc := m.Summary
+		if len(m.Tags) > 0 {
+			desc += " " + style.Dim.Render("["+strings.Join(m.Tags, ", ")+"]")
+		}
+		if m.Schema > formula.SchemaVersion {
+			desc += " " + style.Warning.Render("(needs newer gt)")
+		} else if m.Schema != 0 && m.Schema < formula.SchemaVersion {
+			desc += " " + style.Dim.Render(fmt.Sprintf("(schema %d)", m.Schema))
+		}
+		if m.Owner != "" {
+			desc += " " + style.Dim.Render("(owner: "+m.Owner+")")
+		}
+		if m.Disabled {
+			note := "(disabled)"
+			if m.DisabledReason != "" {
+				note = "(disabled: " + m.DisabledReason + ")"
+			}
+			table.AddRow(style.Dim.Render(m.Name), style.Dim.Render(string(m.Type)), style.Dim.Render(formulaCounts(m)), m.Source,
+				style.Dim.Render(m.Summary)+" "+style.Warning.Render(note))
+			continue
+		}
+		table.AddRow(m.Name, string(m.Type), formulaCounts(m), m.Source, desc)
+	}
+	flush()
+
+	fmt.Println(i18n.Sprintf("%d formulas", len(catalog)))
+	return nil
+}
+
+// markDisabledFormulas flags the catalog entries disabled in the town or
+// in the rig the current directory is in.
+func markDisabledFormulas(catalog []formula.Metadata) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	rigName, _ := inferRigFromCwd(townRoot)
+	for i := range catalog {
+		if d, _ := formulaDisabled(townRoot, rigName, catalog[i].Name); d != nil {
+			catalog[i].Disabled = true
+			catalog[i].DisabledReason = d.Reason
+		}
+	}
+}
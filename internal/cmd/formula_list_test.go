@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestFormulaCatalog(t *testing.T) {
+	workDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := filepath.Join(workDir, ".beads", "formulas")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	local := "formula = \"code-review\"\ntype = \"convoy\"\ncategory = \"review\"\n\n[[legs]]\nid = \"only\"\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "code-review.formula.toml"), []byte(local), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := formulaCatalog("convoy")
+	var sawDesign bool
+	for _, m := range catalog {
+		if m.Type != "convoy" {
+			t.Errorf("%s: type %q passed the convoy filter", m.Name, m.Type)
+		}
+		switch m.Name {
+		case "code-review":
+			if m.Source != "project" || m.Legs != 1 || m.Category != "review" {
+				t.Errorf("code-review = %+v, want the project file to shadow the embedded one", m)
+			}
+		case "design":
+			sawDesign = m.Source == "embedded"
+		}
+	}
+	if !sawDesign {
+		t.Error("embedded design formula missing from catalog")
+	}
+	if len(catalog) == 0 || catalog[0].Category != "convoy" {
+		t.Errorf("catalog not grouped by category: %+v", catalog)
+	}
+}
+
+func TestFormulaCounts(t *testing.T) {
+	tests := []struct {
+		m    formula.Metadata
+		want string
+	}{
+		{formula.Metadata{Legs: 1}, "1 leg"},
+		{formula.Metadata{Steps: 3}, "3 steps"},
+		{formula.Metadata{Templates: 2, Aspects: 1}, "2 templates, 1 aspect"},
+		{formula.Metadata{}, ""},
+	}
+	for _, tt := range tests {
+		if got := formulaCounts(tt.m); got != tt.want {
+			t.Errorf("formulaCounts(%+v) = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
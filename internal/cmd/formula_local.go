@@ -0,0 +1,473 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/review"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Local agent flags
+var (
+	formulaRunLocalAgent bool
+	formulaRunParallel   int
+	formulaRunAgent      string
+	formulaRunFailOn     string
+)
+
+func init() {
+	formulaRunCmd.Flags().BoolVar(&formulaRunLocalAgent, "local-agent", false, "Run legs inline with the one-shot agent instead of slinging to polecats")
+	formulaRunCmd.Flags().IntVar(&formulaRunParallel, "parallel", 1, "Legs to run at once with --local-agent")
+	formulaRunCmd.Flags().StringVar(&formulaRunAgent, "agent", "", "Agent for --local-agent (default: the formula's agent, else rig/town default)")
+	formulaRunCmd.Flags().StringVar(&formulaRunFailOn, "fail-on", "", "With --local-agent, fail if any finding is at or above this severity (critical, high, medium, low, info)")
+}
+
+// localLegResult is the outcome of running one leg inline.
+type localLegResult struct {
+	LegID    string
+	Path     string
+	Duration time.Duration
+	Cached   bool   // Reply came from the agent cache
+	Fallback string // Agent that replied after the primary failed, if any
+	Attempts int    // Runs of the leg, counting retries
+	Err      error
+}
+
+// executeConvoyFormulaLocal runs a convoy formula in the current process:
+// each leg's prompt goes through the one-shot agent and the reply is written
+// to the leg's output path, then synthesis runs over the leg outputs.
+// No beads, convoys, or polecats are created. In a town the run is recorded
+// in the rig's audit log, where it counts toward the rig's quota; the quota
+// hold is released once it is.
+func executeConvoyFormulaLocal(f *formulaData, formulaName, targetRig string, hold runHold) error {
+	hold.releaseClass() // A local run has no convoy
+	defer hold.release()
+
+	out := formulaRunLog()
+	fmt.Fprintf(out, "%s Running convoy formula locally: %s\n\n", style.Bold.Render("🚚"), formulaName)
+
+	// A town is optional: without one, agent config falls back to defaults.
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	townRoot, rigPath := cwd, cwd
+	inTown := false
+	if root, err := workspace.FindFromCwd(); err == nil && root != "" {
+		townRoot = root
+		rigPath = filepath.Join(root, targetRig)
+		inTown = true
+		if err := checkDuplicatePRRun(townRoot, formulaName, targetRig); err != nil {
+			return err
+		}
+	}
+
+	agent := formulaRunAgent
+	if agent == "" {
+		agent = f.Agent
+	}
+	chain := agentChain(townRoot, rigPath, agent)
+
+	reviewID := generateFormulaShortID()
+	targetDescription := "local files"
+	var prTitle string
+	var changedFiles []map[string]interface{}
+	if formulaRunPR > 0 {
+		targetDescription = fmt.Sprintf("PR #%d", formulaRunPR)
+		provider, err := formulaPRProvider(townRoot, rigPath)
+		if err != nil {
+			return err
+		}
+		if prTitle, changedFiles, err = fetchPRInfo(provider, formulaRunPR); err != nil {
+			return err
+		}
+	}
+
+	outputDir := ".reviews/" + reviewID
+	if formulaRunOutput != "" {
+		// Single-document mode: leg outputs are scratch files.
+		tmp, err := os.MkdirTemp("", "gt-review-"+reviewID+"-")
+		if err != nil {
+			return fmt.Errorf("creating scratch directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		outputDir = tmp
+	} else {
+		if f.Output != nil && f.Output.Directory != "" {
+			root := rigPath
+			if inTown {
+				root = formulaOutputRoot(townRoot, rigPath)
+			}
+			if outputDir, err = renderOutputDir(f, map[string]interface{}{
+				"review_id":    reviewID,
+				"formula_name": formulaName,
+			}, outputDir, root); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+		fmt.Fprintf(out, "  %s Output directory: %s\n", style.Dim.Render("📁"), outputDir)
+	}
+
+	if inTown {
+		auditDetails := map[string]string{
+			"formula":   formulaName,
+			"review_id": reviewID,
+			"agent":     agent,
+			"local":     "true",
+		}
+		if formulaRunPR > 0 {
+			auditDetails["pr"] = strconv.Itoa(formulaRunPR)
+		}
+		if formulaRunHeadSHA != "" {
+			auditDetails["head_sha"] = formulaRunHeadSHA
+		}
+		recordAudit(townRoot, targetRig, witness.AuditEntry{
+			Action:  witness.ActionFormulaRun,
+			Subject: reviewID,
+			Details: auditDetails,
+		})
+	}
+	hold.releaseQuota()
+
+	var legContext string
+	if formulaRunPR > 0 {
+		family, _ := resolveTokenFamily(townRoot, rigPath)
+		provider, err := formulaPRProvider(townRoot, rigPath)
+		if err != nil {
+			return err
+		}
+		diff, err := fetchPRDiff(provider, formulaRunPR)
+		if err != nil {
+			return err
+		}
+		if plan := planLegContext(f, family, diff); plan.Trims() {
+			fmt.Fprintf(out, "  %s Context: %s\n", style.Dim.Render("⚠"), describeLegContextPlan(plan))
+			legContext, err = buildLegContext(plan, f, diff, townRoot, rigPath, filepath.Join(outputDir, "context"), family)
+			if err != nil {
+				fmt.Fprintf(out, "%s Failed to apply %s: %v\n", style.Dim.Render("Warning:"), plan.Strategy, err)
+			}
+		}
+	}
+
+	cache := newLocalAgentCache(f, townRoot, inTown, outputDir, reviewID)
+	if agent == simulateAgent {
+		cache = nil // Simulated replies cost nothing to redo
+	}
+
+	parallel := formulaRunParallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	fmt.Fprintf(out, "\n%s Running %d leg(s), %d at a time...\n\n", style.Bold.Render("→"), len(f.Legs), parallel)
+
+	// Legs run once the legs they need (and, with --canary, the canary
+	// legs) have finished; a leg whose needed leg or canary failed fails
+	// without running.
+	canaries := pickCanaryLegs(f, formulaRunCanary)
+	if len(canaries) > 0 {
+		fmt.Fprintf(out, "  %s Canary: %s\n", style.Dim.Render("◆"), describeCanary(f, canaries))
+	}
+	results := make([]localLegResult, len(f.Legs))
+	index := make(map[string]int, len(f.Legs))
+	done := make(map[string]chan struct{}, len(f.Legs))
+	for i, leg := range f.Legs {
+		index[leg.ID] = i
+		done[leg.ID] = make(chan struct{})
+	}
+	built := make(map[string]map[string]interface{})
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, leg := range orderedLegs(f) {
+		i := index[leg.ID]
+		legCtx := legPromptContext(formulaName, leg, reviewID, targetDescription, formulaRunPR, prTitle, changedFiles)
+		addLegOutputContext(f, legCtx, leg, outputDir)
+		outputPath, _ := legCtx["output_path"].(string)
+		if outputPath == "" {
+			outputPath = filepath.Join(outputDir, leg.ID+"-findings.md")
+			legCtx["output_path"] = outputPath
+		}
+		addLegUpstreamContext(legCtx, leg, built)
+		built[leg.ID] = legCtx
+		prompt := renderLegDescription(f, leg, legCtx)
+		if legContext != "" {
+			prompt += "\n\n---\nContext:\n" + legContext
+		}
+		prompt += "\n\n---\nReply with your findings as markdown. Your reply is saved to " + outputPath + "."
+		var agentPaths map[string]string
+		if leg.Consensus != nil {
+			agentPaths = make(map[string]string, len(leg.Consensus.Agents))
+			for _, a := range leg.Consensus.Agents {
+				agentPaths[a] = consensusAgentOutputPath(f, leg, a, outputDir, legCtx)
+			}
+		}
+		contract, err := resolveLegContract(leg, legCtx, f.Path)
+		if err != nil {
+			results[i] = localLegResult{LegID: leg.ID, Path: outputPath, Err: err}
+			fmt.Fprintf(out, "  %s %s: %v\n", style.Error.Render("✗"), leg.ID, err)
+			close(done[leg.ID])
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, leg formulaLeg, prompt, outputPath string, contract *formula.LegExpect, agentPaths map[string]string) {
+			defer wg.Done()
+			defer close(done[leg.ID])
+
+			res := localLegResult{LegID: leg.ID, Path: outputPath}
+			if !slices.Contains(canaries, leg.ID) {
+				for _, canary := range canaries {
+					<-done[canary]
+					if res.Err == nil && results[index[canary]].Err != nil {
+						res.Err = fmt.Errorf("canary leg %s failed", canary)
+					}
+				}
+			}
+			for _, need := range leg.Needs {
+				<-done[need]
+				if res.Err == nil && results[index[need]].Err != nil {
+					res.Err = fmt.Errorf("needed leg %s failed", need)
+				}
+			}
+			// One run of the leg; retries skip the agent cache, which
+			// would hand back the reply that failed
+			attempt := func(cache *localAgentCache) error {
+				var reply string
+				var cached bool
+				var err error
+				if leg.Consensus != nil {
+					reply, cached, err = runConsensusLeg(cache, townRoot, rigPath, leg, prompt, agentPaths)
+				} else {
+					var used string
+					reply, used, err = runAgentChain(chain, out, func(a string) (string, error) {
+						r, c, err := cache.run(townRoot, rigPath, a, prompt)
+						cached = c
+						return r, err
+					})
+					if err == nil && used != chain[0] {
+						res.Fallback = used
+					}
+				}
+				res.Cached = cached
+				if err != nil {
+					return &legFailure{Kind: agentFailureKind(err), Err: err}
+				}
+				if strings.TrimSpace(reply) == "" {
+					return &legFailure{Kind: formula.FailureEmptyOutput, Err: errors.New("agent replied with no output")}
+				}
+				if err := writeLocalOutput(outputPath, reply); err != nil {
+					return err
+				}
+				if contract != nil {
+					if violations := contract.Check(""); len(violations) > 0 {
+						return &legFailure{
+							Kind: formula.ViolationsKind(violations),
+							Err:  fmt.Errorf("output contract violated: %s", strings.Join(violations, "; ")),
+						}
+					}
+				}
+				return nil
+			}
+			if res.Err == nil {
+				sem <- struct{}{}
+				start := time.Now()
+				legCache := cache
+				for {
+					res.Attempts++
+					res.Err = attempt(legCache)
+					kind := legFailureKind(res.Err)
+					if res.Err == nil || !leg.Retry.Allows(kind, res.Attempts-1) {
+						break
+					}
+					backoff := leg.Retry.BackoffDuration()
+					mu.Lock()
+					fmt.Fprintf(out, "  %s %s: %v; retry %d/%d in %s\n", style.Warning.Render("↻"), leg.ID,
+						res.Err, res.Attempts, leg.Retry.Max, backoff)
+					mu.Unlock()
+					<-sem // Don't hold a slot while backing off
+					time.Sleep(backoff)
+					sem <- struct{}{}
+					legCache = nil
+				}
+				res.Duration = time.Since(start)
+				<-sem
+			}
+			results[i] = res
+
+			mu.Lock()
+			defer mu.Unlock()
+			if res.Err != nil {
+				fmt.Fprintf(out, "  %s %s: %v\n", style.Error.Render("✗"), leg.ID, res.Err)
+			} else {
+				took := res.Duration.Round(time.Second).String()
+				if res.Cached {
+					took = "cached"
+				}
+				if res.Fallback != "" {
+					took += ", by " + res.Fallback
+				}
+				fmt.Fprintf(out, "  %s %s %s\n", style.Success.Render("✓"), leg.ID,
+					style.Dim.Render(fmt.Sprintf("→ %s (%s)", outputPath, took)))
+			}
+		}(i, leg, prompt, outputPath, contract, agentPaths)
+	}
+	wg.Wait()
+	historyLegAttempts = retriedLegAttempts(results)
+
+	legFailures := countLegFailures(results)
+	failed := legFailures
+
+	var synthesisPath string
+	succeeded := len(f.Legs) - legFailures
+	if f.Synthesis != nil && succeeded > 0 && succeeded < localSynthesisThreshold(f) {
+		fmt.Fprintf(out, "\n%s Skipping synthesis: %d/%d legs succeeded, synthesis.require = %s\n",
+			style.Warning.Render("⚠"), succeeded, len(f.Legs), f.Synthesis.Require)
+	} else if f.Synthesis != nil && succeeded > 0 {
+		synthesisPath = filepath.Join(outputDir, "synthesis.md")
+		if f.Output != nil && f.Output.Synthesis != "" {
+			synthesisPath = filepath.Join(outputDir, f.Output.Synthesis)
+		}
+		fmt.Fprintf(out, "\n%s Synthesizing %s...\n", style.Bold.Render("→"), f.Synthesis.Title)
+		prompt := buildLocalSynthesisPrompt(f, results)
+		reply, used, err := runAgentChain(chain, out, func(a string) (string, error) {
+			r, _, err := cache.run(townRoot, rigPath, a, prompt)
+			return r, err
+		})
+		if err == nil {
+			err = writeLocalOutput(synthesisPath, reply)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "  %s synthesis: %v\n", style.Error.Render("✗"), err)
+			synthesisPath = ""
+			failed++
+		} else {
+			by := ""
+			if used != chain[0] {
+				by = " (by " + used + ")"
+			}
+			fmt.Fprintf(out, "  %s synthesis %s\n", style.Success.Render("✓"), style.Dim.Render("→ "+synthesisPath+by))
+		}
+	}
+
+	if formulaRunOutput != "" {
+		if err := writeFormulaReport(buildFormulaReport(f, formulaName, results, synthesisPath)); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		synthesisPath = ""
+		if formulaRunOutput != "-" {
+			synthesisPath = formulaRunOutput
+		}
+	}
+
+	fmt.Fprintf(out, "\n%s Local run complete\n", style.Bold.Render("✓"))
+	fmt.Fprintf(out, "  Review:  %s\n", reviewID)
+	fmt.Fprintf(out, "  Legs:    %d/%d succeeded\n", len(f.Legs)-legFailures, len(f.Legs))
+	if synthesisPath != "" {
+		fmt.Fprintf(out, "  Report:  %s\n", synthesisPath)
+	}
+	if cache != nil {
+		fmt.Fprintf(out, "  Cache:   %s\n", cache.summary())
+		historyAgentCache = cache.cache.Stats()
+	}
+
+	legs := collectLegFindings(results)
+	printLegFindings(out, legs)
+
+	var failing []review.Finding
+	if formulaRunFailOn != "" {
+		threshold, err := review.ParseSeverity(formulaRunFailOn)
+		if err != nil {
+			return fmt.Errorf("--fail-on: %w", err)
+		}
+		var findings []review.Finding
+		for _, l := range legs {
+			findings = append(findings, l.Findings...)
+		}
+		failing = review.AtOrAbove(findings, threshold)
+		fmt.Fprintf(out, "\nAll legs: %s\n", formatSeverityCounts(findings))
+		printSeverityGate(out, failing, threshold)
+	}
+
+	printNextSteps(out, localRunNextSteps(formulaName, reviewID, targetRig, inTown, failed))
+
+	if failed > 0 {
+		return fmt.Errorf("%d step(s) failed", failed)
+	}
+	if len(failing) > 0 {
+		return fmt.Errorf("%d finding(s) at or above %s", len(failing), formulaRunFailOn)
+	}
+	return nil
+}
+
+// localSynthesisThreshold is how many legs must succeed for a local run to
+// synthesize. Local runs synthesize whatever succeeded unless the formula
+// sets synthesis.require explicitly.
+func localSynthesisThreshold(f *formulaData) int {
+	if f.Synthesis.Require == "" {
+		return 1
+	}
+	return f.Synthesis.Require.Threshold(len(f.Legs))
+}
+
+// countLegFailures counts legs that did not produce output.
+func countLegFailures(results []localLegResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// buildLocalSynthesisPrompt combines the synthesis instructions with the
+// findings of every leg that succeeded.
+func buildLocalSynthesisPrompt(f *formulaData, results []localLegResult) string {
+	var b strings.Builder
+	b.WriteString(f.Synthesis.Title)
+	b.WriteString("\n\n")
+	if f.Synthesis.Description != "" {
+		b.WriteString(f.Synthesis.Description)
+	} else {
+		b.WriteString("Synthesize findings from all legs into unified output")
+	}
+	b.WriteString("\n\nLeg findings follow.\n")
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(&b, "\n## %s\n\n(leg failed: %v)\n", r.LegID, r.Err)
+			continue
+		}
+		data, err := os.ReadFile(r.Path) //nolint:gosec // G304: path is this run's own output
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", r.LegID, strings.TrimSpace(string(data)))
+	}
+	b.WriteString("\nReply with the synthesized report as markdown.\n")
+	return b.String()
+}
+
+// writeLocalOutput writes an agent reply to an output path.
+func writeLocalOutput(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return os.WriteFile(path, []byte(content), 0644) //nolint:gosec // G306: review outputs are not secret
+}
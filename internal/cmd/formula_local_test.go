@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentcache"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/readonly"
+)
+
+func TestExecuteConvoyFormulaLocal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script agent stub")
+	}
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\nfor last; do :; done\ncase \"$last\" in\n*Synth*) echo \"# Report\" ;;\n*) echo \"finding\" ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &formulaData{
+		Type:      "convoy",
+		Legs:      []formulaLeg{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}},
+		Synthesis: &formulaSynthesis{Title: "Synthesize"},
+		Output:    &formulaOutput{Directory: "out", LegPattern: "{{.leg.id}}.md", Synthesis: "report.md"},
+	}
+	formulaRunParallel = 2
+	defer func() { formulaRunParallel = 1 }()
+
+	if err := executeConvoyFormulaLocal(f, "review", "gastown", runHold{}); err != nil {
+		t.Fatalf("executeConvoyFormulaLocal() = %v", err)
+	}
+
+	for _, name := range []string{"a.md", "b.md"} {
+		data, err := os.ReadFile(filepath.Join(workDir, "out", name))
+		if err != nil || strings.TrimSpace(string(data)) != "finding" {
+			t.Errorf("%s = %q, %v; want leg finding", name, data, err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, "out", "report.md"))
+	if err != nil || !strings.Contains(string(data), "# Report") {
+		t.Errorf("report.md = %q, %v; want synthesis", data, err)
+	}
+}
+
+func TestExecuteConvoyFormulaLocalSingleFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script agent stub")
+	}
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\nfor last; do :; done\ncase \"$last\" in\n*Synth*) echo \"combined\" ;;\n*) echo \"finding\" ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &formulaData{
+		Type:      "convoy",
+		Legs:      []formulaLeg{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}},
+		Synthesis: &formulaSynthesis{Title: "Synthesize"},
+		Output:    &formulaOutput{Directory: "out"},
+	}
+	formulaRunOutput = "report.md"
+	defer func() { formulaRunOutput = "" }()
+
+	if err := executeConvoyFormulaLocal(f, "review", "gastown", runHold{}); err != nil {
+		t.Fatalf("executeConvoyFormulaLocal() = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(workDir, "out")); !os.IsNotExist(err) {
+		t.Errorf("output directory created in single-file mode: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, "report.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# review\n\n## Synthesize\n\ncombined\n\n## a\n\nfinding\n\n## b\n\nfinding\n"
+	if string(data) != want {
+		t.Errorf("report.md = %q, want %q", data, want)
+	}
+}
+
+func TestExecuteConvoyFormulaLocalFailOn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script agent stub")
+	}
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\nprintf '## Minor Issues\\n- naming\\n'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &formulaData{Type: "convoy", Legs: []formulaLeg{{ID: "a", Title: "A"}}}
+	defer func() { formulaRunFailOn = "" }()
+
+	formulaRunFailOn = "high"
+	if err := executeConvoyFormulaLocal(f, "review", "gastown", runHold{}); err != nil {
+		t.Errorf("--fail-on high with a medium finding = %v, want success", err)
+	}
+	formulaRunFailOn = "medium"
+	if err := executeConvoyFormulaLocal(f, "review", "gastown", runHold{}); err == nil {
+		t.Error("--fail-on medium with a medium finding should fail")
+	}
+}
+
+func TestExecuteConvoyFormulaLocalCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script agent stub")
+	}
+
+	binDir := t.TempDir()
+	calls := filepath.Join(binDir, "calls")
+	script := "#!/bin/sh\necho call >> " + calls + "\necho finding\n"
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"type":"town","name":"t"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(town)
+	defer func() { historyAgentCache = agentcache.Stats{} }()
+
+	f := &formulaData{
+		Type:      "convoy",
+		Legs:      []formulaLeg{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}},
+		Synthesis: &formulaSynthesis{Title: "Synthesize"},
+		CacheTTL:  time.Hour,
+	}
+	agentCalls := func() int {
+		data, _ := os.ReadFile(calls)
+		return strings.Count(string(data), "call")
+	}
+
+	if err := executeConvoyFormulaLocal(f, "patrol", "gastown", runHold{}); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if n := agentCalls(); n != 3 {
+		t.Fatalf("first run made %d agent calls, want 3", n)
+	}
+
+	// Same input in a new run (new review ID): every reply is cached
+	if err := executeConvoyFormulaLocal(f, "patrol", "gastown", runHold{}); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if n := agentCalls(); n != 3 {
+		t.Errorf("second run made %d more agent calls, want 0", n-3)
+	}
+	if historyAgentCache != (agentcache.Stats{Hits: 3}) {
+		t.Errorf("history cache stats = %+v, want 3 hits", historyAgentCache)
+	}
+
+	formulaRunNoCache = true
+	defer func() { formulaRunNoCache = false }()
+	if err := executeConvoyFormulaLocal(f, "patrol", "gastown", runHold{}); err != nil {
+		t.Fatalf("--no-cache run: %v", err)
+	}
+	if n := agentCalls(); n != 6 {
+		t.Errorf("--no-cache run made %d agent calls, want 3", n-3)
+	}
+}
+
+func TestExecuteConvoyFormulaLocalFallback(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell script agent stubs")
+	}
+
+	binDir := t.TempDir()
+	stubs := map[string]string{
+		"claude": "#!/bin/sh\necho 'rate limit exceeded' >&2\nexit 1\n",
+		"gemini": "#!/bin/sh\necho finding\n",
+	}
+	for name, script := range stubs {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	if err := config.SaveAgentRegistry(config.DefaultAgentRegistryPath(workDir), &config.AgentRegistry{
+		Version: config.CurrentAgentRegistryVersion,
+		Order:   []string{"claude", "gemini"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &formulaData{
+		Type:      "convoy",
+		Agent:     "claude",
+		Legs:      []formulaLeg{{ID: "a", Title: "A"}},
+		Synthesis: &formulaSynthesis{Title: "Synthesize"},
+	}
+	formulaRunOutput = filepath.Join(workDir, "report.md")
+	defer func() { formulaRunOutput = "" }()
+
+	if err := executeConvoyFormulaLocal(f, "review", "gastown", runHold{}); err != nil {
+		t.Fatalf("executeConvoyFormulaLocal() = %v", err)
+	}
+	data, err := os.ReadFile(formulaRunOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "_Answered by fallback agent gemini._\n\nfinding") {
+		t.Errorf("report doesn't record the fallback agent:\n%s", data)
+	}
+}
+
+func TestRunAgentChain(t *testing.T) {
+	var log strings.Builder
+	var tried []string
+	reply, used, err := runAgentChain([]string{"claude", "opencode", "gemini"}, &log, func(a string) (string, error) {
+		tried = append(tried, a)
+		if a == "claude" {
+			return "", errors.New("agent claude timed out after 10m0s")
+		}
+		return "ok from " + a, nil
+	})
+	if err != nil || used != "opencode" || reply != "ok from opencode" {
+		t.Errorf("runAgentChain() = %q, %q, %v", reply, used, err)
+	}
+	if strings.Join(tried, ",") != "claude,opencode" || !strings.Contains(log.String(), "falling back to opencode") {
+		t.Errorf("tried %v, log %q", tried, log.String())
+	}
+
+	t.Setenv(readonly.EnvVar, "1")
+	_, _, err = runAgentChain([]string{"claude", "gemini"}, &log, func(string) (string, error) {
+		return "", readonly.Check("call agent")
+	})
+	if !errors.Is(err, readonly.ErrReadOnly) {
+		t.Errorf("runAgentChain() = %v, want the read-only refusal without fallback", err)
+	}
+}
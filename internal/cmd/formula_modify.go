@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Formula modify flags
+var (
+	formulaModifyOwner  string
+	formulaModifyReason string
+)
+
+var formulaModifyCmd = &cobra.Command{
+	Use:   "modify <name> [--owner <who>] [--reason <why>]",
+	Short: "Record who owns a formula override and why",
+	Long: `Annotate the formula file a name resolves to with its owner and the
+reason it was customized. The values are stored as comments at the top of
+the file:
+
+  # owner: alice
+  # reason: tuned for monorepo
+
+and shown by 'gt formula list', 'gt formula diff', and before
+'gt formula reset', so others know who to ask before discarding the
+customization. Pass an empty value to clear a field.
+
+Annotating a shipped formula changes the file, so 'gt doctor --fix'
+treats it as locally modified and leaves it alone.
+
+Examples:
+  gt formula modify code-review --owner alice --reason "tuned for monorepo"
+  gt formula modify code-review --reason ""     # Clear the reason`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaModify,
+}
+
+func init() {
+	formulaModifyCmd.Flags().StringVar(&formulaModifyOwner, "owner", "", "Who owns this override")
+	formulaModifyCmd.Flags().StringVar(&formulaModifyReason, "reason", "", "Why this override differs")
+
+	formulaCmd.AddCommand(formulaModifyCmd)
+}
+
+func runFormulaModify(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	ownerSet, reasonSet := cmd.Flags().Changed("owner"), cmd.Flags().Changed("reason")
+	if !ownerSet && !reasonSet {
+		return fmt.Errorf("nothing to modify; give --owner and/or --reason")
+	}
+
+	path, err := findFormulaFile(name)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(path, ".toml") {
+		return fmt.Errorf("%s is not a TOML formula; only .formula.toml files can be annotated", path)
+	}
+	content, err := os.ReadFile(path) //nolint:gosec // G304: path is from formula search paths
+	if err != nil {
+		return fmt.Errorf("reading formula: %w", err)
+	}
+
+	o := formula.ParseOverride(content)
+	if ownerSet {
+		o.Owner = formulaModifyOwner
+	}
+	if reasonSet {
+		o.Reason = formulaModifyReason
+	}
+	if err := fsx.WriteFile(path, formula.SetOverride(content, o), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	fmt.Printf("%s %s\n", style.Success.Render("✓"), i18n.Sprintf("Updated %s", path))
+	if o.IsZero() {
+		fmt.Printf("  %s\n", style.Dim.Render(i18n.Sprintf("no owner or reason recorded")))
+	} else {
+		fmt.Printf("  %s\n", formatOverride(o))
+	}
+	recordFormulaOverrideAudit(name, "modify", path)
+	fireFormulaOverrideHook(name, "modify", path)
+	return nil
+}
+
+// formatOverride describes override metadata on one line, e.g.
+// "owner: alice — tuned for monorepo".
+func formatOverride(o formula.Override) string {
+	var parts []string
+	if o.Owner != "" {
+		parts = append(parts, "owner: "+o.Owner)
+	}
+	if o.Reason != "" {
+		parts = append(parts, o.Reason)
+	}
+	return strings.Join(parts, " — ")
+}
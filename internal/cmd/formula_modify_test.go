@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+func TestRunFormulaModify(t *testing.T) {
+	workDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(workDir, ".beads", "formulas")
+	path := filepath.Join(dir, "my-review.formula.toml")
+	content := "formula = \"my-review\"\ntype = \"workflow\"\n"
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := formulaModifyCmd
+	defer func() {
+		formulaModifyOwner, formulaModifyReason = "", ""
+		cmd.Flags().Lookup("owner").Changed = false
+		cmd.Flags().Lookup("reason").Changed = false
+	}()
+	if err := runFormulaModify(cmd, []string{"my-review"}); err == nil {
+		t.Error("expected error with no flags")
+	}
+
+	_ = cmd.Flags().Set("owner", "alice")
+	_ = cmd.Flags().Set("reason", "tuned for monorepo")
+	if err := runFormulaModify(cmd, []string{"my-review"}); err != nil {
+		t.Fatalf("runFormulaModify: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if want := "# owner: alice\n# reason: tuned for monorepo\n" + content; string(got) != want {
+		t.Errorf("file =\n%s\nwant\n%s", got, want)
+	}
+
+	// Only the flags given change; an empty value clears.
+	cmd.Flags().Lookup("owner").Changed = false
+	_ = cmd.Flags().Set("reason", "")
+	if err := runFormulaModify(cmd, []string{"my-review"}); err != nil {
+		t.Fatalf("runFormulaModify: %v", err)
+	}
+	got, _ = os.ReadFile(path)
+	if !strings.HasPrefix(string(got), "# owner: alice\nformula") {
+		t.Errorf("file =\n%s", got)
+	}
+}
+
+func TestRecordFormulaOverrideAudit(t *testing.T) {
+	townRoot, rigName := setupTestRigForSettings(t)
+	rigPath := filepath.Join(townRoot, rigName)
+
+	recordFormulaOverrideAudit("my-review", "modify", filepath.Join(rigPath, ".beads", "formulas", "my-review.formula.toml"))
+	recordFormulaOverrideAudit("my-review", "reset", filepath.Join(townRoot, ".beads", "formulas", "my-review.formula.toml"))
+
+	entries, err := witness.ReadAuditLog(rigPath)
+	if err != nil {
+		t.Fatalf("ReadAuditLog: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d audit entries, want 2: %+v", len(entries), entries)
+	}
+	for i, op := range []string{"modify", "reset"} {
+		e := entries[i]
+		if e.Action != witness.ActionFormulaOverride || e.Subject != "my-review" || e.Details["op"] != op {
+			t.Errorf("entry %d = %+v, want formula.override %s of my-review", i, e, op)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Molecule flags
+var formulaRunNoMolecule bool
+
+func init() {
+	formulaRunCmd.Flags().BoolVar(&formulaRunNoMolecule, "no-molecule", false, "Create loose convoy, leg and synthesis beads instead of pouring the run as a molecule")
+}
+
+// Seams for tests: cookFormulaFn cooks a formula's proto,
+// pourMoleculeFn pours a molecule from it, returning the root ID, and
+// labelMoleculeFn adds the run's labels to the root.
+var (
+	cookFormulaFn   = CookFormula
+	pourMoleculeFn  = pourMolecule
+	labelMoleculeFn = func(townBeads, rootID string, labels []string) error {
+		return beads.OpenStore(townBeads).Update(rootID, beads.UpdateOptions{AddLabels: labels})
+	}
+)
+
+// formulaMolecule is a convoy run poured as a beads molecule of the
+// formula's cooked proto. The run's leg and synthesis beads become
+// children of the molecule root, with their needs as blocking edges, so
+// bd's molecule tooling shows the run's DAG. The convoy still tracks the
+// leg beads for gt's monitor and leg queue.
+type formulaMolecule struct {
+	RootID string
+	Proto  string
+}
+
+// newFormulaMolecule cooks the formula's proto and pours a molecule of it
+// for run reviewID, labelled with the run's labels. It returns nil with
+// --no-molecule, which keeps the run's beads loose.
+func newFormulaMolecule(townBeads, formulaName, reviewID string, labels []string) (*formulaMolecule, error) {
+	if formulaRunNoMolecule {
+		return nil, nil
+	}
+	if err := cookFormulaFn(formulaName, townBeads); err != nil {
+		return nil, fmt.Errorf("cooking formula %s (use --no-molecule to run without one): %w", formulaName, err)
+	}
+	rootID, err := pourMoleculeFn(formulaName, townBeads, []string{"review_id=" + reviewID})
+	if err != nil {
+		return nil, fmt.Errorf("pouring molecule of %s (use --no-molecule to run without one): %w", formulaName, err)
+	}
+	m := &formulaMolecule{RootID: rootID, Proto: formulaName}
+	if len(labels) > 0 {
+		if err := labelMoleculeFn(townBeads, rootID, labels); err != nil {
+			m.discard(townBeads)
+			return nil, fmt.Errorf("labelling molecule %s: %w", rootID, err)
+		}
+	}
+	return m, nil
+}
+
+// pourMolecule pours a persistent molecule of a cooked proto with bd mol
+// pour and returns its root ID.
+func pourMolecule(proto, workDir string, vars []string) (string, error) {
+	args := []string{"--no-daemon", "mol", "pour", proto}
+	for _, v := range vars {
+		args = append(args, "--var", v)
+	}
+	args = append(args, "--json")
+
+	cmd := exec.Command("bd", args...)
+	cmd.Dir = workDir
+	cmd.Stderr = os.Stderr
+	out, err := cmdtrace.Output(cmd)
+	if err != nil {
+		return "", err
+	}
+	return parseWispIDFromJSON(out)
+}
+
+// convoyField returns the convoy description line naming the molecule.
+func (m *formulaMolecule) convoyField() string {
+	if m == nil {
+		return ""
+	}
+	return "\nmolecule: " + m.RootID
+}
+
+// link queues the molecule root's relation to the convoy on batch.
+func (m *formulaMolecule) link(batch *beads.Batch, convoyID string) {
+	if m == nil {
+		return
+	}
+	batch.AddDependency("molecule", m.RootID, convoyID, "related")
+}
+
+// adopt queues a leg or synthesis bead as a step of the molecule, on
+// behalf of key.
+func (m *formulaMolecule) adopt(batch *beads.Batch, key, beadID string) {
+	if m == nil {
+		return
+	}
+	batch.AddDependency(key, beadID, m.RootID, "parent-child")
+}
+
+// discard closes a molecule whose run failed before its convoy was
+// created, so it isn't left in progress.
+func (m *formulaMolecule) discard(townBeads string) {
+	if m == nil {
+		return
+	}
+	if err := beads.New(townBeads).CloseWithReason("formula run failed", m.RootID); err != nil {
+		fmt.Printf("%s Failed to close molecule %s: %v\n", style.Dim.Render("Warning:"), m.RootID, err)
+	}
+}
+
+// report prints the poured molecule, and whether linking it failed.
+func (m *formulaMolecule) report(batchErr *beads.BatchError) {
+	if m == nil {
+		return
+	}
+	if err := batchErr.Failed("molecule"); err != nil {
+		fmt.Printf("%s Failed to link molecule %s to the convoy: %v\n", style.Dim.Render("Warning:"), m.RootID, err)
+	}
+	fmt.Printf("  %s Poured molecule: %s (from proto %s)\n", style.Dim.Render("⬡"), m.RootID, m.Proto)
+}
+
+// closeFormulaMolecule closes the molecule of a formula convoy that has
+// closed, so bd doesn't list the molecule as in progress.
+func closeFormulaMolecule(townBeads, convoyID, description string) {
+	rootID := convoyDescriptionField(description, "molecule")
+	if rootID == "" {
+		return
+	}
+	if err := beads.New(townBeads).CloseWithReason("convoy "+convoyID+" closed", rootID); err != nil {
+		fmt.Printf("%s Failed to close molecule %s: %v\n", style.Dim.Render("Warning:"), rootID, err)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestNewFormulaMolecule(t *testing.T) {
+	defer func(cook func(string, string) error, pour func(string, string, []string) (string, error), label func(string, string, []string) error) {
+		cookFormulaFn, pourMoleculeFn, labelMoleculeFn = cook, pour, label
+	}(cookFormulaFn, pourMoleculeFn, labelMoleculeFn)
+
+	var calls []string
+	cookFormulaFn = func(name, dir string) error {
+		calls = append(calls, "cook "+name+"@"+dir)
+		return nil
+	}
+	pourMoleculeFn = func(proto, dir string, vars []string) (string, error) {
+		calls = append(calls, "pour "+proto+"@"+dir+" "+strings.Join(vars, " "))
+		return "hq-mol-abc", nil
+	}
+	labelMoleculeFn = func(dir, id string, labels []string) error {
+		calls = append(calls, "label "+id+"@"+dir+" "+strings.Join(labels, " "))
+		return nil
+	}
+	m, err := newFormulaMolecule("/town/.beads", "code-review", "r1", []string{"team:infra", "incident:INC-7"})
+	if err != nil || m == nil || m.Proto != "code-review" || m.RootID != "hq-mol-abc" {
+		t.Errorf("newFormulaMolecule() = %+v, %v", m, err)
+	}
+	if got := strings.Join(calls, ","); got != "cook code-review@/town/.beads,pour code-review@/town/.beads review_id=r1,label hq-mol-abc@/town/.beads team:infra incident:INC-7" {
+		t.Errorf("calls = %s", got)
+	}
+	if got := m.convoyField(); got != "\nmolecule: hq-mol-abc" {
+		t.Errorf("convoyField() = %q", got)
+	}
+
+	// A formula bd can't cook or pour fails the run
+	pourMoleculeFn = func(string, string, []string) (string, error) { return "", errors.New("no proto") }
+	if m, err := newFormulaMolecule("/town/.beads", "code-review", "r1", nil); err == nil || m != nil {
+		t.Errorf("newFormulaMolecule() with a failed pour = %+v, %v", m, err)
+	}
+	calls = nil
+	cookFormulaFn = func(string, string) error { return errors.New("unknown formula type") }
+	if m, err := newFormulaMolecule("/town/.beads", "code-review", "r1", nil); err == nil || m != nil || len(calls) != 0 {
+		t.Errorf("newFormulaMolecule() with a failed cook = %+v, %v (calls %v)", m, err, calls)
+	}
+
+	formulaRunNoMolecule = true
+	defer func() { formulaRunNoMolecule = false }()
+	m, err = newFormulaMolecule("/town/.beads", "code-review", "r1", nil)
+	if err != nil || m != nil || m.convoyField() != "" {
+		t.Errorf("newFormulaMolecule() with --no-molecule = %+v, %v", m, err)
+	}
+}
+
+func TestPourMolecule(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script bd stub")
+	}
+
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$@\" > \"" + argsFile + "\"\necho '{\"new_epic_id\":\"hq-mol-xyz\"}'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rootID, err := pourMolecule("code-review", t.TempDir(), []string{"review_id=r1"})
+	if err != nil || rootID != "hq-mol-xyz" {
+		t.Fatalf("pourMolecule() = %q, %v", rootID, err)
+	}
+	args, _ := os.ReadFile(argsFile)
+	if got := strings.TrimSpace(string(args)); got != "--no-daemon mol pour code-review --var review_id=r1 --json" {
+		t.Errorf("bd args = %q", got)
+	}
+}
+
+func TestFormulaMoleculeLink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script bd stub")
+	}
+
+	// bd stub that keeps the batch's import file and logs dep adds
+	binDir := t.TempDir()
+	imported := filepath.Join(binDir, "import.jsonl")
+	depLog := filepath.Join(binDir, "deps")
+	script := `#!/bin/sh
+while [ "${1#--}" != "$1" ]; do
+  if [ "$1" = "--db" ]; then shift; fi
+  shift
+done
+if [ "$1" = import ]; then cp "$3" "` + imported + `"; fi
+if [ "$1" = dep ]; then echo "$@" >> "` + depLog + `"; fi
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	m := &formulaMolecule{RootID: "hq-mol-abc", Proto: "code-review"}
+	batch := beads.NewIsolated(t.TempDir()).NewBatch()
+	m.link(batch, "hq-cv-abc")
+	batch.Create("security", beads.BatchIssue{ID: "hq-leg-sec", Title: "Security", Priority: -1})
+	m.adopt(batch, "security", "hq-leg-sec")
+	if err := batch.Apply(); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+
+	data, err := os.ReadFile(imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"issue_id":"hq-leg-sec","depends_on_id":"hq-mol-abc","type":"parent-child"`) {
+		t.Errorf("leg isn't a child of the molecule: %s", data)
+	}
+	deps, _ := os.ReadFile(depLog)
+	if got := strings.TrimSpace(string(deps)); got != "dep add hq-mol-abc hq-cv-abc --type=related" {
+		t.Errorf("molecule not linked to the convoy: %q", got)
+	}
+}
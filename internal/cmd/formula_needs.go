@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// orderedLegs returns a formula's legs in dispatch order: each leg after
+// the legs it needs. parseFormulaFile has already rejected bad needs.
+func orderedLegs(f *formulaData) []formulaLeg {
+	ids := make([]string, 0, len(f.Legs))
+	needs := make(map[string][]string)
+	byID := make(map[string]formulaLeg, len(f.Legs))
+	for _, leg := range f.Legs {
+		ids = append(ids, leg.ID)
+		needs[leg.ID] = leg.Needs
+		byID[leg.ID] = leg
+	}
+	order, err := formula.LegOrder(ids, needs)
+	if err != nil {
+		return f.Legs
+	}
+	legs := make([]formulaLeg, 0, len(order))
+	for _, id := range order {
+		legs = append(legs, byID[id])
+	}
+	return legs
+}
+
+// addLegUpstreamContext sets .upstream in a leg's template context: for
+// each leg it needs, that leg's id, title, output_path, and bead_id (when
+// its bead exists), taken from the contexts built so far.
+func addLegUpstreamContext(legCtx map[string]interface{}, leg formulaLeg, built map[string]map[string]interface{}) {
+	upstream := make(map[string]interface{}, len(leg.Needs))
+	for _, need := range leg.Needs {
+		ctx, ok := built[need]
+		if !ok {
+			continue
+		}
+		up := map[string]interface{}{
+			"id":          need,
+			"output_path": ctx["output_path"],
+			"bead_id":     ctx["bead_id"],
+		}
+		if info, ok := ctx["leg"].(map[string]interface{}); ok {
+			up["title"] = info["title"]
+		}
+		upstream[need] = up
+	}
+	legCtx["upstream"] = upstream
+}
+
+// upstreamRefPattern matches .upstream.<leg-id> references whose leg ID
+// need not be a valid template identifier (e.g. "api-design").
+var upstreamRefPattern = regexp.MustCompile(`(^|[^\w$)\]])\.upstream\.([A-Za-z0-9_-]+)`)
+
+// rewriteUpstreamRefs turns {{ .upstream.api-design.output_path }} into
+// {{ (index .upstream "api-design").output_path }}, since text/template
+// field names cannot contain hyphens.
+func rewriteUpstreamRefs(tmplText string) string {
+	return upstreamRefPattern.ReplaceAllString(tmplText, `$1(index .upstream "$2")`)
+}
+
+// missingNeeds returns the first leg that leg needs but has no bead in
+// legBeads, or "" if every needed leg has one.
+func missingNeeds(leg formulaLeg, legBeads map[string]string) string {
+	for _, need := range leg.Needs {
+		if _, ok := legBeads[need]; !ok {
+			return need
+		}
+	}
+	return ""
+}
+
+// describeLegNeeds describes what a waiting leg waits for.
+func describeLegNeeds(leg formulaLeg) string {
+	return "after " + strings.Join(leg.Needs, ", ")
+}
+
+// dispatchReadyLegs queues a formula convoy's waiting legs once every leg
+// they need has closed; the leg queue slings them as polecats free up. Waiting legs are the open, unassigned tracked
+// issues whose saved sling payload lists needs or canaries. Legs held
+// behind a canary that failed its output contract are closed instead. The
+// convoy watcher runs gt convoy check whenever a tracked issue closes,
+// which lands here.
+func dispatchReadyLegs(townBeads, description string, tracked []trackedIssueInfo, dryRun bool) {
+	rigName := convoyDescriptionField(description, "rig")
+	if rigName == "" {
+		return
+	}
+	townRoot := filepath.Dir(townBeads)
+
+	status := make(map[string]string, len(tracked))
+	for _, t := range tracked {
+		status[t.ID] = t.Status
+	}
+	var ready []convoy.QueuedLeg
+	for _, t := range tracked {
+		if t.Status != "open" || t.Assignee != "" {
+			continue
+		}
+		payloadPath := slingPayloadPath(townRoot, t.ID)
+		if _, err := os.Stat(payloadPath); err != nil {
+			continue
+		}
+		p, err := loadSlingPayload(payloadPath)
+		if err != nil || (len(p.Needs) == 0 && len(p.Canary) == 0) || !legNeedsMet(p.Canary, status) {
+			continue
+		}
+		if failed := failedCanary(townBeads, p.Canary); failed != "" {
+			// The run is misconfigured; don't spend a polecat on this leg
+			if dryRun {
+				fmt.Printf("%s Would close %s: canary %s failed\n", style.Warning.Render("⚠"), t.ID, failed)
+				continue
+			}
+			reason := "canary leg " + failed + " failed its output contract"
+			if err := beads.New(townBeads).CloseWithReason(reason, t.ID); err != nil {
+				fmt.Printf("%s Failed to close %s: %v\n", style.Dim.Render("Warning:"), t.ID, err)
+			} else {
+				fmt.Printf("%s Closed %s: %s\n", style.Warning.Render("⚠"), t.ID, reason)
+			}
+			continue
+		}
+		if !legNeedsMet(p.Needs, status) {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("%s Would queue %s: %s\n", style.Warning.Render("⚠"), t.ID, t.Title)
+			continue
+		}
+		ready = append(ready, convoy.QueuedLeg{
+			BeadID:   t.ID,
+			Rig:      rigName,
+			ConvoyID: p.ConvoyID,
+			Payload:  payloadPath,
+			Priority: p.Priority,
+		})
+	}
+	if err := enqueueLegs(townRoot, ready); err != nil {
+		fmt.Printf("%s Failed to queue ready legs: %v\n", style.Dim.Render("Warning:"), err)
+	}
+}
+
+// legNeedsMet reports whether every needed leg bead has closed.
+func legNeedsMet(needs []string, status map[string]string) bool {
+	for _, id := range needs {
+		if status[id] != "closed" {
+			return false
+		}
+	}
+	return true
+}
+
+// convoyDescriptionField returns a "key: value" field from a convoy
+// description; keys match case-insensitively.
+func convoyDescriptionField(description, key string) string {
+	for _, line := range strings.Split(description, "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && strings.EqualFold(strings.TrimSpace(k), key) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrderedLegs(t *testing.T) {
+	f := &formulaData{Legs: []formulaLeg{
+		{ID: "impl", Needs: []string{"api-design"}},
+		{ID: "docs", Needs: []string{"impl"}},
+		{ID: "api-design"},
+	}}
+	var got []string
+	for _, leg := range orderedLegs(f) {
+		got = append(got, leg.ID)
+	}
+	if strings.Join(got, ",") != "api-design,impl,docs" {
+		t.Errorf("orderedLegs() = %v, want [api-design impl docs]", got)
+	}
+}
+
+func TestRenderTemplateUpstream(t *testing.T) {
+	built := map[string]map[string]interface{}{
+		"api-design": {
+			"output_path": ".reviews/x/api-design.md",
+			"bead_id":     "hq-leg-abc",
+			"leg":         map[string]interface{}{"title": "API design"},
+		},
+	}
+	ctx := map[string]interface{}{}
+	addLegUpstreamContext(ctx, formulaLeg{ID: "impl", Needs: []string{"api-design"}}, built)
+
+	got, err := renderTemplate(`Read {{ .upstream.api-design.output_path }} ({{.upstream.api-design.bead_id}}, {{ .upstream.api-design.title }})`, ctx)
+	if err != nil {
+		t.Fatalf("renderTemplate() error = %v", err)
+	}
+	want := "Read .reviews/x/api-design.md (hq-leg-abc, API design)"
+	if got != want {
+		t.Errorf("renderTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestLegNeedsMet(t *testing.T) {
+	status := map[string]string{"hq-leg-a": "closed", "hq-leg-b": "open"}
+	if !legNeedsMet([]string{"hq-leg-a"}, status) {
+		t.Error("legNeedsMet(closed) = false")
+	}
+	if legNeedsMet([]string{"hq-leg-a", "hq-leg-b"}, status) {
+		t.Error("legNeedsMet(open) = true")
+	}
+	if legNeedsMet([]string{"hq-leg-untracked"}, status) {
+		t.Error("legNeedsMet(untracked) = true")
+	}
+}
+
+func TestConvoyDescriptionField(t *testing.T) {
+	desc := "Review convoy\n\nformula: code-review\nRig: gastown\n"
+	if got := convoyDescriptionField(desc, "rig"); got != "gastown" {
+		t.Errorf("rig = %q, want gastown", got)
+	}
+	if got := convoyDescriptionField(desc, "pr"); got != "" {
+		t.Errorf("pr = %q, want empty", got)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/steveyegge/gastown/internal/review"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// runNextStep is a command suggested at the end of a formula run, with why
+// you would run it.
+type runNextStep struct {
+	Command string
+	Why     string
+}
+
+// dispatchState is what a polecat dispatch left behind, from which its
+// next steps follow.
+type dispatchState struct {
+	ConvoyID  string
+	ReviewID  string
+	Rig       string
+	OutputDir string // Empty when the formula writes no output directory
+	FirstLeg  string // Bead of the first dispatched leg; empty if none was
+	Held      bool   // Legs are held for approval
+	Queued    int    // Legs waiting for a free polecat
+	Partial   bool   // Synthesis must be started by hand
+}
+
+// dispatchNextSteps returns the commands that apply to a dispatched convoy,
+// most immediate first. Commands that can't do anything yet are left out:
+// attach and watch only when a leg has a polecat, approve only when legs
+// are held.
+func dispatchNextSteps(s dispatchState) []runNextStep {
+	var steps []runNextStep
+	if s.Held {
+		steps = append(steps, runNextStep{"gt convoy approve " + s.ConvoyID, "Release the held legs"})
+	}
+	if s.FirstLeg != "" {
+		steps = append(steps, runNextStep{"gt attach " + s.FirstLeg, "Watch the first leg's polecat work"})
+		steps = append(steps, runNextStep{"gt convoy watch " + s.ConvoyID + " --follow-leg " + s.FirstLeg, "Stream the first leg's output"})
+	}
+	if s.Queued > 0 {
+		steps = append(steps, runNextStep{"gt queue list", "See legs waiting for a polecat"})
+	}
+	steps = append(steps, runNextStep{"gt convoy status " + s.ConvoyID, "Track the legs' progress"})
+	if s.Partial {
+		steps = append(steps, runNextStep{"gt synthesis start " + s.ConvoyID, "Synthesize once enough legs complete"})
+	}
+	if s.OutputDir != "" {
+		steps = append(steps, runNextStep{withRigFlag("gt review render "+s.ReviewID, s.Rig), "Assemble the findings into a report"})
+	}
+	return steps
+}
+
+// localRunNextSteps returns the commands that apply after a foreground
+// run that had failed failures. Outside a town there are none.
+func localRunNextSteps(formulaName, reviewID, rig string, inTown bool, failed int) []runNextStep {
+	var steps []runNextStep
+	if inTown && formulaRunOutput == "" {
+		steps = append(steps, runNextStep{withRigFlag("gt review render "+reviewID, rig), "Assemble the findings into a shareable report"})
+	}
+	if failed > 0 && inTown {
+		steps = append(steps, runNextStep{withRigFlag("gt formula history "+formulaName, rig), "Compare with earlier runs of this formula"})
+	}
+	return steps
+}
+
+// withRigFlag appends --rig to command when rig is set.
+func withRigFlag(command, rig string) string {
+	if rig == "" {
+		return command
+	}
+	return command + " --rig " + rig
+}
+
+// printNextSteps prints steps as an aligned command list.
+func printNextSteps(out io.Writer, steps []runNextStep) {
+	if len(steps) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\nNext steps:\n")
+	tw := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	for _, s := range steps {
+		fmt.Fprintf(tw, "  %s\t%s\n", s.Command, style.Dim.Render("# "+s.Why))
+	}
+	_ = tw.Flush()
+}
+
+// legFindings are the findings of one leg of a foreground run; Err is set
+// when the leg produced no output.
+type legFindings struct {
+	LegID    string
+	Findings []review.Finding
+	Err      error
+}
+
+// collectLegFindings parses each leg's output for findings.
+func collectLegFindings(results []localLegResult) []legFindings {
+	legs := make([]legFindings, 0, len(results))
+	for _, r := range results {
+		lf := legFindings{LegID: r.LegID, Err: r.Err}
+		if r.Err == nil {
+			if data, err := os.ReadFile(r.Path); err == nil { //nolint:gosec // G304: path is this run's own output
+				lf.Findings = review.ParseFindings(string(data), r.LegID)
+			}
+		}
+		legs = append(legs, lf)
+	}
+	return legs
+}
+
+// printLegFindings prints a line per leg with its finding count and the
+// severities present, e.g. "security   3 (1 high, 2 low)".
+func printLegFindings(out io.Writer, legs []legFindings) {
+	fmt.Fprintf(out, "\nFindings by leg:\n")
+	tw := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	for _, l := range legs {
+		if l.Err != nil {
+			fmt.Fprintf(tw, "  %s\t%s\n", l.LegID, style.Error.Render("failed"))
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", l.LegID, describeFindingCount(l.Findings))
+	}
+	_ = tw.Flush()
+}
+
+// describeFindingCount is the number of findings followed by the nonzero
+// severity counts, most severe first.
+func describeFindingCount(findings []review.Finding) string {
+	if len(findings) == 0 {
+		return "0"
+	}
+	counts := review.CountBySeverity(findings)
+	var parts []string
+	for sev := review.SeverityCritical; sev >= review.SeverityInfo; sev-- {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+		}
+	}
+	return fmt.Sprintf("%d (%s)", len(findings), strings.Join(parts, ", "))
+}
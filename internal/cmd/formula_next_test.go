@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/review"
+)
+
+func nextStepCommands(steps []runNextStep) string {
+	var cmds []string
+	for _, s := range steps {
+		cmds = append(cmds, s.Command)
+	}
+	return strings.Join(cmds, "; ")
+}
+
+func TestDispatchNextSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		state dispatchState
+		want  string
+	}{
+		{
+			name:  "dispatched",
+			state: dispatchState{ConvoyID: "hq-cv-a", ReviewID: "r1", Rig: "gastown", OutputDir: ".reviews/r1", FirstLeg: "hq-leg-b"},
+			want:  "gt attach hq-leg-b; gt convoy watch hq-cv-a --follow-leg hq-leg-b; gt convoy status hq-cv-a; gt review render r1 --rig gastown",
+		},
+		{
+			name:  "held for approval",
+			state: dispatchState{ConvoyID: "hq-cv-a", Held: true},
+			want:  "gt convoy approve hq-cv-a; gt convoy status hq-cv-a",
+		},
+		{
+			name:  "all queued, partial synthesis",
+			state: dispatchState{ConvoyID: "hq-cv-a", Queued: 3, Partial: true},
+			want:  "gt queue list; gt convoy status hq-cv-a; gt synthesis start hq-cv-a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextStepCommands(dispatchNextSteps(tt.state)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalRunNextSteps(t *testing.T) {
+	if got := localRunNextSteps("code-review", "r1", "", false, 1); len(got) != 0 {
+		t.Errorf("outside a town got %q, want none", nextStepCommands(got))
+	}
+	got := nextStepCommands(localRunNextSteps("code-review", "r1", "gastown", true, 1))
+	want := "gt review render r1 --rig gastown; gt formula history code-review --rig gastown"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrintLegFindings(t *testing.T) {
+	var b strings.Builder
+	printLegFindings(&b, []legFindings{
+		{LegID: "security", Findings: []review.Finding{{Severity: review.SeverityHigh}, {Severity: review.SeverityLow}, {Severity: review.SeverityLow}}},
+		{LegID: "style"},
+		{LegID: "perf", Err: errors.New("agent failed")},
+	})
+	out := b.String()
+	for _, want := range []string{"security   3 (1 high, 2 low)", "style      0", "perf       failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
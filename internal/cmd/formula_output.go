@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// formulaRunOutput is "-" for stdout or a file path; empty writes the usual
+// output directory tree.
+var formulaRunOutput string
+
+func init() {
+	formulaRunCmd.Flags().StringVar(&formulaRunOutput, "output", "", "Collect findings and synthesis into one document: a file, or - for stdout (implies --local-agent)")
+}
+
+// formulaRunLog is where formula run progress goes. With --output - it is
+// stderr, so stdout carries only the report.
+func formulaRunLog() io.Writer {
+	if formulaRunOutput == "-" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// buildFormulaReport concatenates a local run's synthesis and leg findings
+// into a single markdown document, synthesis first.
+func buildFormulaReport(f *formulaData, formulaName string, results []localLegResult, synthesisPath string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", formulaName)
+	if f.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(f.Description))
+	}
+	if synthesisPath != "" {
+		if data, err := os.ReadFile(synthesisPath); err == nil { //nolint:gosec // G304: path is this run's own output
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", f.Synthesis.Title, strings.TrimSpace(string(data)))
+		}
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(&b, "\n## %s\n\n(leg failed: %v)\n", r.LegID, r.Err)
+			continue
+		}
+		data, err := os.ReadFile(r.Path) //nolint:gosec // G304: path is this run's own output
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", r.LegID)
+		if r.Fallback != "" {
+			fmt.Fprintf(&b, "_Answered by fallback agent %s._\n\n", r.Fallback)
+		}
+		fmt.Fprintf(&b, "%s\n", strings.TrimSpace(string(data)))
+	}
+	return b.String()
+}
+
+// writeFormulaReport writes the report to the --output destination.
+func writeFormulaReport(report string) error {
+	if formulaRunOutput == "-" {
+		_, err := io.WriteString(os.Stdout, report)
+		return err
+	}
+	return writeLocalOutput(formulaRunOutput, report)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// formulaOutputRoot returns the directory a run's absolute output paths
+// must lie within: the town's output_root setting, or rigPath (the current
+// directory outside a rig).
+func formulaOutputRoot(townRoot, rigPath string) string {
+	if townRoot != "" {
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.OutputRoot != "" {
+			if filepath.IsAbs(settings.OutputRoot) {
+				return filepath.Clean(settings.OutputRoot)
+			}
+			return filepath.Join(townRoot, settings.OutputRoot)
+		}
+	}
+	if rigPath == "" {
+		rigPath, _ = os.Getwd()
+	}
+	return rigPath
+}
+
+// renderOutputDir renders a formula's output directory template, or returns
+// def if it has none or it can't be rendered, and refuses directories
+// outside root (see formula.CheckOutputPath).
+func renderOutputDir(f *formulaData, ctx map[string]interface{}, def, root string) (string, error) {
+	dir := def
+	if f.Output != nil {
+		dir = renderTemplateOrDefault(f.Output.Directory, ctx, def)
+	}
+	if err := formula.CheckOutputPath(root, dir); err != nil {
+		return "", fmt.Errorf("output directory: %w", err)
+	}
+	return dir, nil
+}
+
+// renderLegPattern renders a formula's leg output file name, falling back
+// to the default when the template leaves the output directory.
+func renderLegPattern(f *formulaData, legCtx map[string]interface{}, leg formulaLeg) string {
+	def := leg.ID + "-findings.md"
+	pattern := renderTemplateOrDefault(f.Output.LegPattern, legCtx, def)
+	if err := formula.CheckOutputPath("", pattern); err != nil || filepath.IsAbs(pattern) {
+		fmt.Fprintf(os.Stderr, "%s leg %s: output file %q leaves the output directory; using %s\n",
+			style.Warning.Render("⚠"), leg.ID, pattern, def)
+		return def
+	}
+	return pattern
+}
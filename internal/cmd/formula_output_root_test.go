@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestFormulaOutputRoot(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+	if got := formulaOutputRoot(town, rig); got != rig {
+		t.Errorf("default root = %q, want the rig %q", got, rig)
+	}
+
+	if err := os.MkdirAll(filepath.Join(town, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "settings", "config.json"), []byte(`{"type":"town-settings","version":1,"output_root":"reviews"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := formulaOutputRoot(town, rig), filepath.Join(town, "reviews"); got != want {
+		t.Errorf("configured root = %q, want %q", got, want)
+	}
+}
+
+func TestRenderOutputDir(t *testing.T) {
+	ctx := map[string]interface{}{"review_id": "abc12"}
+	f := &formulaData{Output: &formulaOutput{Directory: ".reviews/{{.review_id}}"}}
+	if dir, err := renderOutputDir(f, ctx, ".reviews/abc12", "/town/gastown"); err != nil || dir != ".reviews/abc12" {
+		t.Errorf("renderOutputDir() = %q, %v", dir, err)
+	}
+
+	f.Output.Directory = "../../etc/{{.review_id}}"
+	if _, err := renderOutputDir(f, ctx, ".reviews/abc12", "/town/gastown"); !errors.Is(err, formula.ErrOutputEscapes) {
+		t.Errorf("traversal: err = %v, want ErrOutputEscapes", err)
+	}
+	f.Output.Directory = "/var/reviews/{{.review_id}}"
+	if _, err := renderOutputDir(f, ctx, ".reviews/abc12", "/town/gastown"); !errors.Is(err, formula.ErrOutputEscapes) {
+		t.Errorf("absolute outside root: err = %v, want ErrOutputEscapes", err)
+	}
+	if dir, err := renderOutputDir(f, ctx, ".reviews/abc12", "/var/reviews"); err != nil || dir != "/var/reviews/abc12" {
+		t.Errorf("absolute inside root: %q, %v", dir, err)
+	}
+}
+
+func TestRenderLegPatternTraversal(t *testing.T) {
+	leg := formulaLeg{ID: "security"}
+	f := &formulaData{Output: &formulaOutput{LegPattern: "../../{{.leg.id}}.md"}}
+	ctx := legPromptContext("code-review", leg, "abc12", "PR #7", 7, "Fix", nil)
+	if got := renderLegPattern(f, ctx, leg); got != "security-findings.md" {
+		t.Errorf("renderLegPattern() = %q, want the default", got)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// parseFormulaContent writes content to a formula file and parses it.
+func parseFormulaContent(t *testing.T, content string) (*formulaData, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.formula.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return parseFormulaFile(path)
+}
+
+// TestParseFormulaFileREADMESnippets parses the formula README's examples
+// verbatim, trailing comments included.
+func TestParseFormulaFileREADMESnippets(t *testing.T) {
+	f, err := parseFormulaContent(t, `formula = "review"
+context_strategy = "summarize_diff"
+context_limit = 60000        # tokens; default is 75% of the model's window
+context_agent = "gemini"     # summarizer; default is the rig's agent
+
+[[legs]]
+id = "build-check"
+runner = "docker"            # "host" (default) | "docker"
+image = "ghcr.io/example/agent-go:1.22"
+mounts = ["/var/cache/go-mod:/go/pkg/mod"]
+
+[[legs]]
+id = "sast"
+expect.files = ["{{ .output_path }}"]
+expect.min_bytes = 200
+expect.json_schema = "schemas/findings.json"
+
+[synthesis]
+title = "Security Report"
+require = "majority"         # "all" (default) | "majority" | N
+`)
+	if err != nil {
+		t.Fatalf("parseFormulaFile() = %v", err)
+	}
+	if f.ContextStrategy != formula.ContextSummarizeDiff || f.ContextLimit != 60000 || f.ContextAgent != "gemini" {
+		t.Errorf("context = %s, %d, %q", f.ContextStrategy, f.ContextLimit, f.ContextAgent)
+	}
+	if leg := f.Legs[0]; leg.Runner != "docker" || leg.Image != "ghcr.io/example/agent-go:1.22" {
+		t.Errorf("build-check leg = %+v", leg)
+	}
+	if _, err := formula.NewLegContainer(f.Legs[0].Runner, f.Legs[0].Image, f.Legs[0].Mounts); err != nil {
+		t.Errorf("NewLegContainer() = %v", err)
+	}
+	if e := f.Legs[1].Expect; e == nil || e.MinBytes != 200 || e.JSONSchema != "schemas/findings.json" {
+		t.Errorf("sast expect = %+v", e)
+	}
+	if f.Synthesis == nil || f.Synthesis.Require != formula.RequireMajority {
+		t.Errorf("synthesis = %+v", f.Synthesis)
+	}
+}
+
+func TestParseFormulaFileRejectsInvalidTOML(t *testing.T) {
+	if _, err := parseFormulaContent(t, "formula = \"review\"\n[[legs]]\nid = \"a\"\nretry = { max = 2, on = [\"timeout\" }\n"); err == nil {
+		t.Error("parseFormulaFile() accepted malformed TOML")
+	}
+}
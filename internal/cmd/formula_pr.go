@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/offline"
+	"github.com/steveyegge/gastown/internal/scm"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// formulaRunPRArg is the raw --pr value: a PR number or a PR URL.
+var formulaRunPRArg string
+
+// formulaRunPRRef is the PR --pr named by URL; nil when it was a number.
+var formulaRunPRRef *scm.PRRef
+
+// parsePRArg parses a --pr value: a number ("123" or "#123") or a PR URL
+// (see scm.ParsePRURL). The ref is nil for numbers.
+func parsePRArg(arg string) (int, *scm.PRRef, error) {
+	arg = strings.TrimSpace(arg)
+	if n, err := strconv.Atoi(strings.TrimPrefix(arg, "#")); err == nil {
+		if n <= 0 {
+			return 0, nil, fmt.Errorf("--pr: bad PR number %q", arg)
+		}
+		return n, nil, nil
+	}
+	ref, err := scm.ParsePRURL(arg)
+	if err != nil {
+		return 0, nil, fmt.Errorf("--pr: %w", err)
+	}
+	return ref.Number, &ref, nil
+}
+
+// resolveFormulaRunPR parses --pr into formulaRunPR and formulaRunPRRef.
+// For a PR URL it returns the rig tracking the PR's repository, or "" if
+// no rig does (or --rig already names one).
+func resolveFormulaRunPR() (string, error) {
+	formulaRunPRRef = nil
+	if formulaRunPRArg == "" {
+		return "", nil
+	}
+	n, ref, err := parsePRArg(formulaRunPRArg)
+	if err != nil {
+		return "", err
+	}
+	formulaRunPR, formulaRunPRRef = n, ref
+	if ref == nil {
+		return "", nil
+	}
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return "", nil
+	}
+	refHost := scm.Config{Provider: ref.Provider, Host: ref.Host}.Hostname()
+	if formulaRunRig != "" {
+		rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+		if err != nil {
+			return "", nil
+		}
+		host, repo, ok := rigRepo(townRoot, formulaRunRig, rigsConfig.Rigs[formulaRunRig])
+		if ok && (host != refHost || !strings.EqualFold(repo, ref.Repo)) {
+			fmt.Printf("%s Rig %s tracks %s/%s, not %s/%s\n",
+				style.Dim.Render("Warning:"), formulaRunRig, host, repo, refHost, ref.Repo)
+		}
+		return "", nil
+	}
+	return rigForRepo(townRoot, refHost, ref.Repo)
+}
+
+// rigForRepo returns the rig tracking repo (owner/name) on host, or "" if
+// none does. Repository mappings in rigs.json take precedence over rigs'
+// scm settings and git URLs. More than one match is an error: the caller
+// must pick with --rig.
+func rigForRepo(townRoot, host, repo string) (string, error) {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		return "", nil
+	}
+	matches := rigsConfig.RigsForRepo(host, repo)
+	if len(matches) == 0 {
+		for name, entry := range rigsConfig.Rigs {
+			h, r, ok := rigRepo(townRoot, name, entry)
+			if ok && h == host && strings.EqualFold(r, repo) {
+				matches = append(matches, name)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	}
+	sort.Strings(matches)
+	return "", fmt.Errorf("rigs %s all track %s/%s; pick one with --rig or map the repository to one rig (gt rig repo)",
+		strings.Join(matches, ", "), host, repo)
+}
+
+// rigRepo returns the hostname and owner/name of the repository a rig
+// tracks: its repository mapping in rigs.json, else its scm settings when
+// they name a repo, else its git_url.
+func rigRepo(townRoot, rigName string, entry config.RigEntry) (host, repo string, ok bool) {
+	if entry.Repository != nil {
+		return entry.Repository.Hostname(), entry.Repository.Name, true
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil &&
+		settings.SCM != nil && settings.SCM.Repo != "" {
+		cfg := scm.Config{Provider: settings.SCM.Provider, Host: settings.SCM.Host}
+		if cfg.Host == "" && settings.GitHub != nil && (cfg.Provider == "" || cfg.Provider == scm.ProviderGitHub) {
+			cfg.Host = settings.GitHub.Host
+		}
+		return cfg.Hostname(), settings.SCM.Repo, true
+	}
+	return scm.ParseRepoURL(entry.GitURL)
+}
+
+// formulaPRProvider returns the provider to read formulaRunPR from in a
+// rig: the rig's, pointed at the repository of a --pr URL when one was
+// given. The rig's token goes only to its own host. Offline, PRs are read
+// from refs in the local clone instead.
+func formulaPRProvider(townRoot, rigPath string) (scm.Provider, error) {
+	if offline.Enabled() {
+		return localPRProvider(rigPath), nil
+	}
+	cfg, err := rigSCMConfig(townRoot, rigPath)
+	if err != nil {
+		return nil, err
+	}
+	if ref := formulaRunPRRef; ref != nil {
+		prCfg := scm.Config{Provider: ref.Provider, Host: ref.Host, Repo: ref.Repo}
+		if prCfg.Hostname() == "github.com" {
+			prCfg.Host = ""
+		}
+		if cfg.Provider == prCfg.Provider && cfg.Hostname() == prCfg.Hostname() {
+			prCfg.Token = cfg.Token
+		}
+		cfg = prCfg
+	}
+	return scm.New(cfg)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/scm"
+)
+
+func TestParsePRArg(t *testing.T) {
+	if n, ref, err := parsePRArg("#42"); err != nil || n != 42 || ref != nil {
+		t.Errorf("parsePRArg(#42) = %d, %+v, %v", n, ref, err)
+	}
+	n, ref, err := parsePRArg("https://github.com/org/app/pull/7")
+	if err != nil || n != 7 || ref == nil || ref.Repo != "org/app" {
+		t.Errorf("parsePRArg(URL) = %d, %+v, %v", n, ref, err)
+	}
+	for _, bad := range []string{"0", "-3", "org/app#7"} {
+		if _, _, err := parsePRArg(bad); err == nil {
+			t.Errorf("parsePRArg(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestRigForRepo(t *testing.T) {
+	townRoot := t.TempDir()
+	rigs := `{"version": 1, "rigs": {
+		"gastown": {"git_url": "git@github.com:steveyegge/gastown.git"},
+		"beads": {"git_url": "https://github.com/steveyegge/beads.git"},
+		"infra": {"git_url": "/srv/git/infra"},
+		"beads-mirror": {"git_url": "git@github-work:acme/beads-fork.git",
+			"repository": {"name": "steveyegge/beads", "default_branch": "main"}}}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// infra's clone comes from a local mirror; its scm settings name the host.
+	if err := os.MkdirAll(filepath.Join(townRoot, "infra", "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type": "rig-settings", "version": 1, "scm": {"provider": "gitea", "host": "gitea.example.com", "repo": "ops/infra"}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "infra", "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct{ host, repo, want string }{
+		{"github.com", "SteveYegge/Gastown", "gastown"},
+		{"github.com", "steveyegge/beads", "beads-mirror"}, // The mapping wins over beads' git_url
+		{"gitea.example.com", "ops/infra", "infra"},
+		{"github.com", "someone/else", ""},
+	} {
+		if got, err := rigForRepo(townRoot, tc.host, tc.repo); err != nil || got != tc.want {
+			t.Errorf("rigForRepo(%s, %s) = %q, %v; want %q", tc.host, tc.repo, got, err, tc.want)
+		}
+	}
+}
+
+func TestFormulaPRProvider(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GHE_TOKEN", "s3cret")
+	settings := `{"type": "rig-settings", "version": 1, "github": {"host": "ghe.example.com", "token": "secret:env:GHE_TOKEN"}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { formulaRunPRRef = nil }()
+
+	// The rig's token goes with a PR on its own host...
+	formulaRunPRRef = &scm.PRRef{Provider: scm.ProviderGitHub, Host: "ghe.example.com", Repo: "org/app", Number: 1}
+	p, err := formulaPRProvider(townRoot, rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (scm.GitHub{Host: "ghe.example.com", Repo: "org/app", Token: "s3cret"}); *p.(*scm.GitHub) != want {
+		t.Errorf("provider = %+v, want %+v", p, want)
+	}
+
+	// ...but not to another host.
+	formulaRunPRRef = &scm.PRRef{Provider: scm.ProviderGitHub, Host: "github.com", Repo: "org/app", Number: 1}
+	if p, err = formulaPRProvider(townRoot, rigPath); err != nil {
+		t.Fatal(err)
+	}
+	if want := (scm.GitHub{Repo: "org/app"}); *p.(*scm.GitHub) != want {
+		t.Errorf("provider = %+v, want %+v", p, want)
+	}
+}
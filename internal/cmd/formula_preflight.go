@@ -0,0 +1,68 @@
+package cmd
+
+// formulaPreflight holds the gates every formula dispatch passes through,
+// whether it comes from gt formula run, a --watch-pr poll, gt convoy clone,
+// or gt formula rerun.
+type formulaPreflight struct {
+	TownRoot string // Empty outside a town: only the formula's own checks run
+	Rig      string
+	Formula  string
+	F        *formulaData // Nil for an exact replay, which skips requires
+	Class    string       // Concurrency class; defaults to F's
+
+	OverridePause bool
+	// Poll refuses during a maintenance window instead of queueing the
+	// command to run after it, for callers that try again later.
+	Poll bool
+	// Confirm, if set, is asked after the other gates pass and before the
+	// quota and class locks are taken; declining stops the run.
+	Confirm func() bool
+}
+
+// check refuses the run while the town is paused, the formula is disabled
+// on the rig, or its requirements are missing, and defers it past an open
+// maintenance window. It then checks the rig's quota and the concurrency
+// class, whose locks come back held.
+//
+// ok is false with a nil error when the run should stop quietly: it was
+// queued for a maintenance window or Confirm declined it.
+func (p formulaPreflight) check() (hold runHold, ok bool, err error) {
+	if p.TownRoot != "" {
+		if err := checkTownPause(p.TownRoot, p.OverridePause); err != nil {
+			return runHold{}, false, err
+		}
+		if p.Poll {
+			if err := maintenanceWindowError(p.TownRoot); err != nil && !p.OverridePause {
+				return runHold{}, false, err
+			}
+		} else if queued, err := queueForMaintenance(p.TownRoot, p.OverridePause); queued || err != nil {
+			return runHold{}, false, err
+		}
+		if err := checkFormulaEnabled(p.TownRoot, p.Rig, p.Formula); err != nil {
+			return runHold{}, false, err
+		}
+	}
+
+	// Fail fast on missing tools, before any beads are created
+	if p.F != nil {
+		if err := checkFormulaRequires(p.F, p.Formula, p.Rig); err != nil {
+			return runHold{}, false, err
+		}
+	}
+
+	if p.Confirm != nil && !p.Confirm() {
+		return runHold{}, false, nil
+	}
+
+	if p.TownRoot == "" {
+		return runHold{}, true, nil
+	}
+	class := p.Class
+	if class == "" && p.F != nil {
+		class = p.F.ConcurrencyClass
+	}
+	if hold, err = takeRunHold(p.TownRoot, p.Rig, class); err != nil {
+		return runHold{}, false, err
+	}
+	return hold, true, nil
+}
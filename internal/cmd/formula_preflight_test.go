@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/mayor"
+)
+
+func TestFormulaPreflight(t *testing.T) {
+	t.Setenv(overridePauseEnv, "")
+	town := t.TempDir()
+	asked := 0
+	p := formulaPreflight{
+		TownRoot: town,
+		Rig:      "gastown",
+		Formula:  "code-review",
+		F:        &formulaData{Name: "code-review", Type: "convoy"},
+		Confirm:  func() bool { asked++; return asked > 1 },
+	}
+
+	// Declined: stops quietly, holding nothing
+	if _, ok, err := p.check(); ok || err != nil {
+		t.Errorf("declined check = %v, %v; want a quiet stop", ok, err)
+	}
+	hold, ok, err := p.check()
+	if !ok || err != nil {
+		t.Fatalf("confirmed check = %v, %v", ok, err)
+	}
+	hold.release()
+
+	// A paused town is refused before anyone is asked
+	if err := mayor.PauseTown(town, "INC-42", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := p.check(); ok || err == nil {
+		t.Errorf("paused town check = %v, %v; want refused", ok, err)
+	}
+	if asked != 2 {
+		t.Errorf("Confirm asked %d times, want 2", asked)
+	}
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Formula promote/demote flags
+var (
+	formulaMoveFrom  string
+	formulaMoveTo    string
+	formulaMoveForce bool
+)
+
+var formulaPromoteCmd = &cobra.Command{
+	Use:   "promote <name>",
+	Short: "Move a formula override to a broader level",
+	Long: `Move a formula file from one resolution level to another, e.g. from a
+rig to the whole town once it has proven itself.
+
+Levels:
+  project     .beads/formulas/ in the current directory
+  rig:<name>  <town>/<rig>/.beads/formulas/, searched from inside the rig
+  town        <town>/.beads/formulas/
+  user        ~/.beads/formulas/
+
+The file is validated, moved, and its .installed.json record re-stamped at
+the new level, so 'gt doctor --fix' keeps treating local edits to a
+shipped formula as edits. Afterwards, copies that shadow the moved formula
+(or that it now shadows) are listed.
+
+An existing different file at the destination is only replaced with --force;
+compare first with 'gt formula diff'.
+
+Examples:
+  gt formula promote code-review --from rig:myproj --to town
+  gt formula promote code-review               # project → town
+  gt formula demote code-review --to rig:myproj`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaPromote,
+}
+
+var formulaDemoteCmd = &cobra.Command{
+	Use:   "demote <name>",
+	Short: "Move a formula override to a narrower level",
+	Long: `Move a formula file to a narrower resolution level, e.g. from the town
+back to the one rig that needs it. Same as 'gt formula promote' with the
+default direction reversed: --from defaults to town and --to to project.
+
+Examples:
+  gt formula demote code-review --to rig:myproj
+  gt formula demote code-review --from user --to town`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaDemote,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{formulaPromoteCmd, formulaDemoteCmd} {
+		c.Flags().StringVar(&formulaMoveFrom, "from", "", "Level to move the formula from (project, rig:<name>, town, user)")
+		c.Flags().StringVar(&formulaMoveTo, "to", "", "Level to move the formula to (project, rig:<name>, town, user)")
+		c.Flags().BoolVarP(&formulaMoveForce, "force", "f", false, "Replace a different file at the destination")
+		formulaCmd.AddCommand(c)
+	}
+}
+
+func runFormulaPromote(cmd *cobra.Command, args []string) error {
+	return moveFormula(args[0], orDefault(formulaMoveFrom, "project"), orDefault(formulaMoveTo, "town"))
+}
+
+func runFormulaDemote(cmd *cobra.Command, args []string) error {
+	return moveFormula(args[0], orDefault(formulaMoveFrom, "town"), orDefault(formulaMoveTo, "project"))
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// formulaLevelDir returns the formulas directory for a level name.
+func formulaLevelDir(level string) (string, error) {
+	switch {
+	case level == "project":
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(cwd, ".beads", "formulas"), nil
+	case level == "user":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".beads", "formulas"), nil
+	case level == "town" || strings.HasPrefix(level, "rig:"):
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return "", fmt.Errorf("level %s: not in a Gas Town workspace: %w", level, err)
+		}
+		if level == "town" {
+			return filepath.Join(townRoot, ".beads", "formulas"), nil
+		}
+		rigName := strings.TrimPrefix(level, "rig:")
+		if rigName == "" || strings.ContainsAny(rigName, `/\`) {
+			return "", fmt.Errorf("invalid level %q", level)
+		}
+		rigPath := filepath.Join(townRoot, rigName)
+		if info, err := os.Stat(rigPath); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("rig %q not found in %s", rigName, townRoot)
+		}
+		return filepath.Join(rigPath, ".beads", "formulas"), nil
+	}
+	return "", fmt.Errorf("unknown level %q (want project, rig:<name>, town, or user)", level)
+}
+
+// moveFormula moves name's formula file between levels.
+func moveFormula(name, from, to string) error {
+	srcDir, err := formulaLevelDir(from)
+	if err != nil {
+		return err
+	}
+	dstDir, err := formulaLevelDir(to)
+	if err != nil {
+		return err
+	}
+	if samePath(srcDir, dstDir) {
+		return fmt.Errorf("%s and %s are the same directory (%s)", from, to, srcDir)
+	}
+
+	var filename string
+	for _, ext := range formulaExtensions {
+		if _, err := os.Stat(filepath.Join(srcDir, name+ext)); err == nil {
+			filename = name + ext
+			break
+		}
+	}
+	if filename == "" {
+		return fmt.Errorf("formula %s not found at %s (%s)", name, from, srcDir)
+	}
+	srcPath, dstPath := filepath.Join(srcDir, filename), filepath.Join(dstDir, filename)
+
+	content, err := os.ReadFile(srcPath) //nolint:gosec // G304: formula search path
+	if err != nil {
+		return err
+	}
+	if _, err := parseFormulaFile(srcPath); err != nil {
+		return fmt.Errorf("%s is not a valid formula: %w", srcPath, err)
+	}
+	if existing, err := os.ReadFile(dstPath); err == nil && !bytes.Equal(existing, content) && !formulaMoveForce { //nolint:gosec // G304: formula search path
+		return fmt.Errorf("%s already has a different %s; compare with 'gt formula diff %s', then use --force to replace it", to, filename, name)
+	}
+
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return fmt.Errorf("creating formulas directory: %w", err)
+	}
+	if err := fsx.WriteFile(dstPath, content, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", dstPath, err)
+	}
+	if err := formula.TransferInstalled(srcDir, dstDir, filename); err != nil {
+		return fmt.Errorf("updating install records: %w", err)
+	}
+	if err := os.Remove(srcPath); err != nil {
+		return fmt.Errorf("removing %s: %w", srcPath, err)
+	}
+
+	fmt.Printf("%s %s\n", style.Success.Render("✓"), i18n.Sprintf("Moved %s from %s to %s", name, from, to))
+	fmt.Printf("  %s\n", style.Dim.Render(dstPath))
+	for _, w := range formulaShadowWarnings(name, dstPath) {
+		fmt.Printf("%s %s\n", style.Warning.Render("⚠"), w)
+	}
+	recordFormulaOverrideAudit(name, "move", dstPath)
+	fireFormulaOverrideHook(name, "move", dstPath)
+	return nil
+}
+
+// formulaShadowWarnings describes, from the current directory's point of
+// view, copies of name that shadow the file at path or that it shadows, and
+// rigs that keep their own copy.
+func formulaShadowWarnings(name, path string) []string {
+	var warnings []string
+	var selected *formulaCandidate
+	inSearch := false
+	for _, c := range resolveFormulaCandidates(name) {
+		if c.Status == candidateSelected {
+			selected = &c
+		}
+		if samePath(c.Path, path) {
+			inSearch = true
+			continue
+		}
+		if c.Status == candidateMissing || c.Status == candidateEmbedded {
+			continue
+		}
+		if selected != nil && samePath(selected.Path, path) && c.Status == candidateShadowed {
+			warnings = append(warnings, i18n.Sprintf("now shadows the %s copy at %s", c.Source, c.Path))
+		}
+	}
+	if inSearch && selected != nil && !samePath(selected.Path, path) {
+		warnings = append(warnings, i18n.Sprintf("the %s copy at %s shadows it here", selected.Source, selected.Path))
+	}
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return warnings
+	}
+	entries, _ := os.ReadDir(townRoot)
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		for _, ext := range formulaExtensions {
+			p := filepath.Join(townRoot, e.Name(), ".beads", "formulas", name+ext)
+			if _, err := os.Stat(p); err == nil && !samePath(p, path) {
+				warnings = append(warnings, i18n.Sprintf("rig %s keeps its own copy at %s", e.Name(), p))
+			}
+		}
+	}
+	return warnings
+}
+
+// samePath reports whether two paths name the same file or directory,
+// following symlinks such as <rig>/.beads → mayor/rig/.beads.
+func samePath(a, b string) bool {
+	if ra, err := filepath.EvalSymlinks(a); err == nil {
+		a = ra
+	}
+	if rb, err := filepath.EvalSymlinks(b); err == nil {
+		b = rb
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMoveFormula(t *testing.T) {
+	workDir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := filepath.Join(workDir, ".beads", "formulas")
+	userDir := filepath.Join(home, ".beads", "formulas")
+	content := "formula = \"my-review\"\ntype = \"workflow\"\n\n[[steps]]\nid = \"a\"\ntitle = \"A\"\n"
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "my-review.formula.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveFormula("my-review", "project", "user"); err != nil {
+		t.Fatalf("moveFormula: %v", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(userDir, "my-review.formula.toml")); err != nil || string(got) != content {
+		t.Errorf("user copy = %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, "my-review.formula.toml")); !os.IsNotExist(err) {
+		t.Errorf("project copy still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(userDir, ".installed.json")); !os.IsNotExist(err) {
+		t.Error("formulas gt does not ship should not be recorded as installed")
+	}
+
+	// A different file at the destination needs --force.
+	if err := os.WriteFile(filepath.Join(projectDir, "my-review.formula.toml"), []byte(content+"# edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := moveFormula("my-review", "project", "user"); err == nil {
+		t.Error("expected error when destination differs")
+	}
+	formulaMoveForce = true
+	defer func() { formulaMoveForce = false }()
+	if err := moveFormula("my-review", "project", "user"); err != nil {
+		t.Errorf("moveFormula --force: %v", err)
+	}
+
+	if err := moveFormula("my-review", "user", "user"); err == nil {
+		t.Error("expected error moving to the same level")
+	}
+	if _, err := formulaLevelDir("rig:"); err == nil {
+		t.Error("expected error for empty rig level")
+	}
+}
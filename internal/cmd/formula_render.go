@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/scm"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tokens"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Formula render flags
+var (
+	formulaRenderLeg  string
+	formulaRenderPR   int
+	formulaRenderRig  string
+	formulaRenderJSON bool
+)
+
+var formulaRenderCmd = &cobra.Command{
+	Use:   "render <name>",
+	Short: "Render leg prompts with token estimates",
+	Long: `Render each leg's prompt exactly as 'gt formula run' would send it,
+with an estimate of its size in tokens for the rig's agent.
+
+Estimates use the tokenizer profile of the agent's model family. With --pr,
+the PR diff the agent will read is estimated too, and a warning is shown
+when prompt plus diff is likely to exceed the model's context window.
+
+Examples:
+  gt formula render code-review
+  gt formula render code-review --leg security
+  gt formula render code-review --pr 123 --rig gastown
+  gt formula render code-review --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaRender,
+}
+
+func init() {
+	formulaRenderCmd.Flags().StringVar(&formulaRenderLeg, "leg", "", "Render only this leg")
+	formulaRenderCmd.Flags().IntVar(&formulaRenderPR, "pr", 0, "GitHub PR number to render against")
+	formulaRenderCmd.Flags().StringVar(&formulaRenderRig, "rig", "", "Rig whose agent to estimate for (default: current rig)")
+	formulaRenderCmd.Flags().BoolVar(&formulaRenderJSON, "json", false, "Output as JSON")
+
+	formulaCmd.AddCommand(formulaRenderCmd)
+}
+
+// legPromptContext builds the template context used to render a leg's
+// prompt and output path.
+func legPromptContext(formulaName string, leg formulaLeg, reviewID, targetDescription string, prNumber int, prTitle string, changedFiles []map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"formula_name":       formulaName,
+		"target_description": targetDescription,
+		"review_id":          reviewID,
+		"pr_number":          prNumber,
+		"pr_title":           prTitle,
+		"leg": map[string]interface{}{
+			"id":          leg.ID,
+			"title":       leg.Title,
+			"focus":       leg.Focus,
+			"description": leg.Description,
+		},
+		"changed_files": changedFiles,
+		"files":         []string{}, // TODO: support --files flag
+	}
+}
+
+// addLegOutputContext adds output_path and output to a leg context when the
+// formula declares an [output] section.
+func addLegOutputContext(f *formulaData, legCtx map[string]interface{}, leg formulaLeg, outputDir string) {
+	if f.Output == nil {
+		return
+	}
+	legCtx["output_path"] = filepath.Join(outputDir, renderLegPattern(f, legCtx, leg))
+	legCtx["output"] = map[string]interface{}{
+		"directory": outputDir,
+		"synthesis": f.Output.Synthesis,
+	}
+}
+
+// renderLegDescription returns the bead description for a leg: its
+// description followed by the rendered base prompt, if the formula has one.
+// Descriptions of legs with needs are rendered too, so they can refer to
+// .upstream outputs.
+func renderLegDescription(f *formulaData, leg formulaLeg, legCtx map[string]interface{}) string {
+	legCtx, delimited := untrustedPromptContext(f.Untrusted, legCtx)
+	description := leg.Description
+	if len(leg.Needs) > 0 {
+		if rendered, err := renderTemplate(description, legCtx); err == nil {
+			description = rendered
+		} else {
+			fmt.Printf("%s Failed to render description for %s: %v\n",
+				style.Dim.Render("Warning:"), leg.ID, err)
+		}
+	}
+	basePrompt, ok := f.Prompts["base"]
+	if !ok {
+		return description
+	}
+	renderedPrompt, err := renderTemplate(basePrompt, legCtx)
+	if err != nil {
+		fmt.Printf("%s Failed to render template for %s: %v\n",
+			style.Dim.Render("Warning:"), leg.ID, err)
+		renderedPrompt = basePrompt // Fall back to raw template
+	}
+	if delimited {
+		renderedPrompt = formula.UntrustedNotice + "\n\n" + renderedPrompt
+	}
+	return fmt.Sprintf("%s\n\n---\nBase Prompt:\n%s", description, renderedPrompt)
+}
+
+// untrustedPromptContext returns a copy of a leg context with the PR title
+// and changed file names placed per the formula's untrusted text settings,
+// and whether any were wrapped in delimiters.
+func untrustedPromptContext(u formula.UntrustedText, legCtx map[string]interface{}) (map[string]interface{}, bool) {
+	out := make(map[string]interface{}, len(legCtx))
+	for k, v := range legCtx {
+		out[k] = v
+	}
+	delimited := false
+	if title, _ := legCtx["pr_title"].(string); title != "" {
+		out["pr_title"] = u.Line("PR title", title, formula.DefaultTitleLimit)
+		delimited = u.Delimit
+	}
+	if files, ok := legCtx["changed_files"].([]map[string]interface{}); ok && len(files) > 0 {
+		placed := make([]map[string]interface{}, len(files))
+		for i, file := range files {
+			placed[i] = make(map[string]interface{}, len(file))
+			for k, v := range file {
+				placed[i][k] = v
+			}
+			if path, ok := file["path"].(string); ok {
+				placed[i]["path"] = u.Line("file name", path, formula.DefaultPathLimit)
+			}
+		}
+		out["changed_files"] = placed
+		delimited = delimited || u.Delimit
+	}
+	return out, delimited
+}
+
+// fetchPRDiff returns the diff of a PR.
+func fetchPRDiff(p scm.Provider, prNumber int) (string, error) {
+	diff, err := p.Diff(context.Background(), prNumber)
+	if err != nil {
+		return "", fmt.Errorf("fetching PR #%d diff: %w", prNumber, err)
+	}
+	return diff, nil
+}
+
+// resolveTokenFamily returns the model family of the agent that will run
+// legs in a rig (the town default agent when rigPath is empty).
+func resolveTokenFamily(townRoot, rigPath string) (tokens.Family, string) {
+	if townRoot == "" {
+		return tokens.FamilyClaude, "claude"
+	}
+	if rigPath == "" {
+		rigPath = townRoot
+	}
+	rc, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, "")
+	if err != nil {
+		return tokens.FamilyClaude, "claude"
+	}
+	if agentName == "" {
+		agentName = filepath.Base(rc.Command)
+	}
+	return tokens.FamilyForAgent(agentName), agentName
+}
+
+// legPromptEstimate is the estimated size of one leg's prompt.
+type legPromptEstimate struct {
+	Leg          string `json:"leg"`
+	Title        string `json:"title"`
+	Prompt       string `json:"prompt,omitempty"`
+	PromptTokens int    `json:"prompt_tokens"`
+	DiffTokens   int    `json:"diff_tokens,omitempty"`
+	TotalTokens  int    `json:"total_tokens"`
+	Window       int    `json:"context_window"`
+	Level        string `json:"level"`
+}
+
+// estimateLegPrompt estimates a rendered leg prompt plus any injected diff.
+func estimateLegPrompt(leg formulaLeg, prompt string, diffTokens int, family tokens.Family) legPromptEstimate {
+	e := legPromptEstimate{
+		Leg:          leg.ID,
+		Title:        leg.Title,
+		Prompt:       prompt,
+		PromptTokens: tokens.Estimate(prompt, family),
+		DiffTokens:   diffTokens,
+		Window:       tokens.ContextWindow(family),
+	}
+	e.TotalTokens = e.PromptTokens + e.DiffTokens
+	switch tokens.Check(e.TotalTokens, family) {
+	case tokens.LevelOver:
+		e.Level = "over"
+	case tokens.LevelNear:
+		e.Level = "near"
+	default:
+		e.Level = "ok"
+	}
+	return e
+}
+
+// formatLegEstimate renders an estimate as a one-line summary.
+func formatLegEstimate(e legPromptEstimate) string {
+	s := fmt.Sprintf("≈%s tokens", tokens.Format(e.PromptTokens))
+	if e.DiffTokens > 0 {
+		s += fmt.Sprintf(" + diff ≈%s", tokens.Format(e.DiffTokens))
+	}
+	return s + fmt.Sprintf(" of %s", tokens.Format(e.Window))
+}
+
+// hasLegEstimateWarnings reports whether any leg is near or over its window.
+func hasLegEstimateWarnings(estimates []legPromptEstimate) bool {
+	for _, e := range estimates {
+		if e.Level != "ok" {
+			return true
+		}
+	}
+	return false
+}
+
+// printLegEstimateWarnings prints a warning for each leg whose prompt is
+// likely to exceed, or come close to, the model's context window.
+func printLegEstimateWarnings(estimates []legPromptEstimate, agentName string) {
+	for _, e := range estimates {
+		switch e.Level {
+		case "over":
+			fmt.Printf("  %s leg %s: ≈%s tokens likely exceeds %s's %s context window\n",
+				style.Error.Render("✗"), e.Leg, tokens.Format(e.TotalTokens), agentName, tokens.Format(e.Window))
+		case "near":
+			fmt.Printf("  %s leg %s: ≈%s tokens is close to %s's %s context window\n",
+				style.Warning.Render("⚠"), e.Leg, tokens.Format(e.TotalTokens), agentName, tokens.Format(e.Window))
+		}
+	}
+}
+
+func runFormulaRender(cmd *cobra.Command, args []string) error {
+	formulaName := args[0]
+
+	formulaPath, err := findFormulaFile(formulaName)
+	if err != nil {
+		return fmt.Errorf("finding formula: %w", err)
+	}
+	f, err := parseFormulaFile(formulaPath)
+	if err != nil {
+		return fmt.Errorf("parsing formula: %w", err)
+	}
+	warnFormulaSchema(f)
+	if len(f.Legs) == 0 {
+		return fmt.Errorf("formula %s has no legs to render", formulaName)
+	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	var rigPath string
+	if townRoot != "" {
+		rigName := formulaRenderRig
+		if rigName == "" {
+			rigName, _ = inferRigFromCwd(townRoot)
+		}
+		if rigName != "" {
+			rigPath = filepath.Join(townRoot, rigName)
+		}
+	}
+	family, agentName := resolveTokenFamily(townRoot, rigPath)
+
+	reviewID := "render"
+	targetDescription := "local files"
+	var prTitle string
+	var changedFiles []map[string]interface{}
+	var diff string
+	if formulaRenderPR > 0 {
+		targetDescription = fmt.Sprintf("PR #%d", formulaRenderPR)
+		provider, err := rigSCMProvider(townRoot, rigPath)
+		if err != nil {
+			return err
+		}
+		if prTitle, changedFiles, err = fetchPRInfo(provider, formulaRenderPR); err != nil {
+			return err
+		}
+		if diff, err = fetchPRDiff(provider, formulaRenderPR); err != nil {
+			return err
+		}
+	}
+	diffTokens := tokens.Estimate(diff, family)
+
+	var outputDir string
+	if f.Output != nil {
+		outputDir, err = renderOutputDir(f, map[string]interface{}{
+			"review_id":    reviewID,
+			"formula_name": formulaName,
+		}, ".reviews/"+reviewID, formulaOutputRoot(townRoot, rigPath))
+		if err != nil {
+			return err
+		}
+	}
+
+	var estimates []legPromptEstimate
+	built := make(map[string]map[string]interface{})
+	for _, leg := range orderedLegs(f) {
+		legCtx := legPromptContext(formulaName, leg, reviewID, targetDescription, formulaRenderPR, prTitle, changedFiles)
+		addLegOutputContext(f, legCtx, leg, outputDir)
+		addLegUpstreamContext(legCtx, leg, built)
+		built[leg.ID] = legCtx
+		if formulaRenderLeg != "" && leg.ID != formulaRenderLeg {
+			continue
+		}
+		prompt := renderLegDescription(f, leg, legCtx)
+		estimates = append(estimates, estimateLegPrompt(leg, prompt, diffTokens, family))
+	}
+	if len(estimates) == 0 {
+		return fmt.Errorf("formula %s has no leg %q", formulaName, formulaRenderLeg)
+	}
+
+	if formulaRenderJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(estimates)
+	}
+
+	for i, e := range estimates {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %s: %s %s\n", style.Bold.Render("━━"), e.Leg, e.Title, style.Dim.Render("("+formatLegEstimate(e)+")"))
+		fmt.Println(strings.TrimRight(e.Prompt, "\n"))
+	}
+	fmt.Printf("\n%s Estimated for %s (%s tokenizer)\n", style.Dim.Render("Note:"), agentName, family)
+	if hasLegEstimateWarnings(estimates) {
+		printLegEstimateWarnings(estimates, agentName)
+	}
+	if plan := planLegContext(f, family, diff); diff != "" && plan.Oversized() {
+		fmt.Printf("%s Context: %s\n", style.Dim.Render("Note:"), describeLegContextPlan(plan))
+	}
+	return nil
+}
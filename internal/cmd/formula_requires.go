@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// formulaRunTarget returns the machine a formula run's legs execute on:
+// the local host for local-agent runs and rigs without a remote, else the
+// rig's remote host.
+func formulaRunTarget(targetRig string) (connection.Connection, string, error) {
+	if formulaRunLocalAgent || formulaRunOutput != "" {
+		return connection.NewLocalConnection(), "local host", nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" || targetRig == "" {
+		return connection.NewLocalConnection(), "local host", nil
+	}
+	return rig.DispatchTarget(filepath.Join(townRoot, targetRig))
+}
+
+// checkFormulaRequires verifies the tools a formula requires on the
+// machine its legs run on, so a missing tool fails the run before any
+// beads are created instead of failing every leg.
+func checkFormulaRequires(f *formulaData, formulaName, targetRig string) error {
+	if len(f.Requires) == 0 {
+		return nil
+	}
+	conn, target, err := formulaRunTarget(targetRig)
+	if err != nil {
+		return fmt.Errorf("resolving dispatch target of %s: %w", targetRig, err)
+	}
+	problems := formula.CheckRequirements(conn, f.Requires)
+	if len(problems) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "formula %s needs tools missing on %s (rig %s):", formulaName, target, targetRig)
+	for _, p := range problems {
+		fmt.Fprintf(&b, "\n  - %v", p)
+	}
+	fmt.Fprintf(&b, "\nInstall them on %s, or run the formula on a rig that has them (--rig)", target)
+	return fmt.Errorf("%s", b.String())
+}
+
+// printFormulaRequires lists a formula's requirements and whether the
+// dispatch target meets them, for --dry-run.
+func printFormulaRequires(f *formulaData, targetRig string) {
+	if len(f.Requires) == 0 {
+		return
+	}
+	conn, target, err := formulaRunTarget(targetRig)
+	if err != nil {
+		fmt.Printf("  Requires: %s\n", style.Warning.Render("cannot check: "+err.Error()))
+		return
+	}
+	fmt.Printf("  Requires (on %s):\n", target)
+	for _, r := range f.Requires {
+		if problems := formula.CheckRequirements(conn, []formula.Requirement{r}); len(problems) > 0 {
+			fmt.Printf("    %s %v\n", style.Error.Render("✗"), problems[0])
+		} else {
+			fmt.Printf("    %s %s\n", style.Success.Render("✓"), r)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestCheckFormulaRequires(t *testing.T) {
+	t.Chdir(t.TempDir()) // outside a town: legs run on the local host
+
+	f := &formulaData{}
+	if err := checkFormulaRequires(f, "lint", "gastown"); err != nil {
+		t.Fatalf("no requirements: %v", err)
+	}
+
+	reqs, err := formula.ParseRequirements([]string{"sh", "gt-test-missing-tool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Requires = reqs
+	err = checkFormulaRequires(f, "lint", "gastown")
+	if err == nil {
+		t.Fatal("missing tool: want error")
+	}
+	for _, want := range []string{"lint", "local host", "gt-test-missing-tool: not found", "Install"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "- sh") {
+		t.Errorf("error lists an installed tool: %q", err)
+	}
+}
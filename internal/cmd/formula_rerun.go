@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Formula rerun flags
+var (
+	formulaRerunExact  bool
+	formulaRerunDryRun bool
+)
+
+// Set while gt formula rerun dispatches. formulaRunReplay replaces the PR
+// fetch with the inputs the original run saw; formulaRunRerunOf is
+// recorded in the new convoy's description, snapshot, and audit entry.
+var (
+	formulaRunReplay  *runInputs
+	formulaRunRerunOf string
+)
+
+var formulaRerunCmd = &cobra.Command{
+	Use:   "rerun <run-id|convoy-id>",
+	Short: "Run a convoy formula again from a recorded run",
+	Long: `Run a convoy formula again on exactly the input an earlier run saw.
+
+Every convoy formula run records a snapshot of its resolved context in
+.runtime/runs/<run-id>.json: the PR title, changed files, and diff
+context it fetched, its template variables, the formula's content hash,
+and every leg's rendered prompt. The run ID is the convoy's review_id;
+a convoy ID is accepted too.
+
+By default the current formula is rendered against the recorded input,
+so a prompt edit can be compared with the original run even after the PR
+has moved on. With --exact the recorded prompts are replayed unchanged,
+whatever has happened to the formula or the PR since; only the output
+directory, worktrees, and branches move to the new run ID.
+
+The new convoy records "rerun_of: <run-id>".
+
+Examples:
+  gt formula rerun k3x9a             # Current formula, recorded input
+  gt formula rerun k3x9a --exact     # Recorded prompts, verbatim
+  gt formula rerun hq-cv-abc --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaRerun,
+}
+
+func init() {
+	formulaRerunCmd.Flags().BoolVar(&formulaRerunExact, "exact", false, "Replay the recorded prompts instead of re-rendering the formula")
+	formulaRerunCmd.Flags().BoolVar(&formulaRerunDryRun, "dry-run", false, "Preview the rerun without dispatching")
+	formulaRerunCmd.Flags().BoolVar(&formulaRunOverridePause, "override-pause", false, "Run even though the town is paused (gt pause) or in a maintenance window")
+
+	formulaCmd.AddCommand(formulaRerunCmd)
+}
+
+func runFormulaRerun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	snap, err := findRunSnapshot(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	if formulaRerunExact {
+		if formulaRerunDryRun {
+			printRunSnapshot(snap)
+			return nil
+		}
+		hold, ok, err := formulaPreflight{
+			TownRoot:      townRoot,
+			Rig:           snap.Rig,
+			Formula:       snap.Formula,
+			Class:         snap.ConcurrencyClass,
+			OverridePause: formulaRunOverridePause,
+		}.check()
+		if !ok {
+			return err
+		}
+		recordReplayRunEnv(snap, townRoot)
+		_, err = replayRunSnapshot(townRoot, snap, hold)
+		return err
+	}
+
+	formulaPath, err := findFormulaFile(snap.Formula)
+	if err != nil {
+		return fmt.Errorf("finding formula: %w", err)
+	}
+	f, err := parseFormulaFile(formulaPath)
+	if err != nil {
+		return fmt.Errorf("parsing formula: %w", err)
+	}
+	warnFormulaSchema(f)
+	if f.Type != "convoy" {
+		return fmt.Errorf("formula %s is now type %q; only convoy formulas can be rerun", snap.Formula, f.Type)
+	}
+	if data, err := os.ReadFile(formulaPath); err == nil && snap.FormulaHash != "" { //nolint:gosec // G304: formula path from the search path
+		if hash := shortContentHash(data); hash != snap.FormulaHash {
+			fmt.Printf("%s Formula %s changed since run %s (%s → %s); use --exact to replay the recorded prompts\n",
+				style.Dim.Render("Note:"), snap.Formula, snap.RunID, snap.FormulaHash, hash)
+		}
+	}
+
+	formulaRunPR = snap.Inputs.PR
+	formulaRunHeadSHA = snap.Inputs.HeadSHA
+	formulaRunReplay = &snap.Inputs
+	formulaRunRerunOf = snap.RunID
+	if formulaRerunDryRun {
+		fmt.Printf("%s Rerunning %s\n", style.Dim.Render("[dry-run]"), snap.RunID)
+		return dryRunFormula(f, snap.Formula, snap.Rig)
+	}
+	hold, ok, err := formulaPreflight{
+		TownRoot:      townRoot,
+		Rig:           snap.Rig,
+		Formula:       snap.Formula,
+		F:             f,
+		OverridePause: formulaRunOverridePause,
+	}.check()
+	if !ok {
+		return err
+	}
+
+	fmt.Printf("%s Rerunning %s on its recorded input\n", style.Bold.Render("↻"), snap.RunID)
+	recordFormulaRunEnv(f, snap.Formula, townRoot, filepath.Join(townRoot, snap.Rig))
+	_, err = executeConvoyFormula(f, snap.Formula, snap.Rig, hold)
+	return err
+}
+
+// findRunSnapshot loads the snapshot for a run ID, or for the run behind a
+// convoy ID.
+func findRunSnapshot(townRoot, id string) (*runSnapshot, error) {
+	if _, err := os.Stat(runSnapshotPath(townRoot, id)); err == nil {
+		return loadRunSnapshot(townRoot, id)
+	}
+	meta, err := getConvoyMeta(id)
+	if err != nil {
+		return nil, fmt.Errorf("no run %s: not a recorded run ID or convoy", id)
+	}
+	if meta.ReviewID == "" {
+		return nil, fmt.Errorf("convoy %s was not created by a formula run; nothing to rerun", id)
+	}
+	return loadRunSnapshot(townRoot, meta.ReviewID)
+}
+
+// printRunSnapshot shows what an exact replay would dispatch.
+func printRunSnapshot(snap *runSnapshot) {
+	fmt.Printf("%s Would replay run %s exactly:\n", style.Dim.Render("[dry-run]"), snap.RunID)
+	fmt.Printf("  Formula: %s (%s)\n", style.Bold.Render(snap.Formula), snap.FormulaHash)
+	fmt.Printf("  Rig:     %s\n", snap.Rig)
+	fmt.Printf("  Target:  %s\n", snap.Inputs.TargetDescription)
+	if snap.Inputs.HeadSHA != "" {
+		fmt.Printf("  Head:    %s\n", snap.Inputs.HeadSHA)
+	}
+	fmt.Printf("  Recorded: %s\n", snap.CreatedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("\n  Legs (%d):\n", len(snap.Legs))
+	for _, leg := range snap.Legs {
+		fmt.Printf("    • %s: %s (%d-byte prompt)\n", leg.ID, leg.Title, len(leg.Payload.Prompt))
+	}
+	if snap.Synthesis != nil {
+		fmt.Printf("\n  Synthesis: %s\n", snap.Synthesis.Title)
+	}
+}
+
+// replayRunSnapshot dispatches a new convoy whose legs get the recorded
+// run's payloads, rewritten for the new run ID and convoy.
+func replayRunSnapshot(townRoot string, snap *runSnapshot, hold runHold) (string, error) {
+	defer hold.release()
+	fmt.Printf("%s Replaying run %s exactly\n\n", style.Bold.Render("↻"), snap.RunID)
+	townBeads := filepath.Join(townRoot, ".beads")
+	rigPath := filepath.Join(townRoot, snap.Rig)
+
+	convoyID := fmt.Sprintf("hq-cv-%s", generateFormulaShortID())
+	runID := generateFormulaShortID()
+	rewrite := runSnapshotRewriter(snap, runID, convoyID)
+
+	// Prepare the legs' worktrees on their rewritten branches before
+	// creating anything, as a fresh run does
+	workspaces := make(map[string]*legWorkspace)
+	var legIDs []string
+	for _, leg := range snap.Legs {
+		if leg.Payload.Workdir != "" {
+			workspaces[leg.ID] = &legWorkspace{Path: rewrite.Replace(leg.Payload.Workdir), Branch: rewrite.Replace(leg.Payload.Branch)}
+			legIDs = append(legIDs, leg.ID)
+		}
+	}
+	if err := prepareLegWorkspaces(rigPath, legIDs, workspaces); err != nil {
+		return "", err
+	}
+
+	convoyTitle := fmt.Sprintf("%s: rerun of %s", snap.Formula, snap.RunID)
+	mol, err := newFormulaMolecule(townBeads, snap.Formula, runID, nil)
+	if err != nil {
+		return "", err
+	}
+	description := fmt.Sprintf("Formula convoy: %s\n\nformula: %s\nreview_id: %s\nLegs: %d\nRig: %s",
+		snap.Formula, snap.Formula, runID, len(snap.Legs), snap.Rig)
+	if snap.Inputs.PR > 0 {
+		description += fmt.Sprintf("\nPR: #%d", snap.Inputs.PR)
+	}
+	description += prRunFields(snap.Inputs.HeadSHA, "")
+	description += "\nrerun_of: " + snap.RunID
+	if snap.ConcurrencyClass != "" {
+		description += "\nconcurrency_class: " + snap.ConcurrencyClass
+	}
+	description += mol.convoyField()
+	if err := createFormulaConvoyBead(townBeads, convoyID, convoyTitle, description, nil); err != nil {
+		mol.discard(townBeads)
+		return "", err
+	}
+	hold.releaseClass()
+	fmt.Printf("%s Created convoy: %s\n", style.Bold.Render("✓"), convoyID)
+
+	outputDir := rewrite.Replace(snap.OutputDir)
+	if outputDir != "" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			fmt.Printf("%s Failed to create output directory %s: %v\n",
+				style.Dim.Render("Warning:"), outputDir, err)
+		}
+	}
+
+	// Legs are recorded in dispatch order, so the legs one needs come first
+	next := &runSnapshot{
+		RunID: runID, ConvoyID: convoyID, Formula: snap.Formula, FormulaPath: snap.FormulaPath,
+		FormulaHash: snap.FormulaHash, Rig: snap.Rig, RerunOf: snap.RunID,
+		Inputs: snap.Inputs, OutputDir: outputDir, Synthesis: snap.Synthesis,
+		Env: historyRunEnv,
+	}
+	next.Vars = make(map[string]string, len(snap.Vars))
+	for k, v := range snap.Vars {
+		next.Vars[k] = v
+	}
+	next.Vars["review_id"] = runID
+	legBeads := make(map[string]string)
+	batch := beads.OpenStore(townBeads).NewBatch()
+	mol.link(batch, convoyID)
+	for _, leg := range snap.Legs {
+		var needs []string
+		for _, need := range leg.Needs {
+			if id, ok := legBeads[need]; ok {
+				needs = append(needs, id)
+			}
+		}
+		if len(needs) < len(leg.Needs) {
+			fmt.Printf("%s %s needs a skipped leg, skipping leg\n", style.Dim.Render("Warning:"), leg.ID)
+			continue
+		}
+		payload := replayPayload(leg.Payload, rewrite, runID, convoyID, needs)
+
+		legBeadID := fmt.Sprintf("hq-leg-%s", generateFormulaShortID())
+		batch.Create(leg.ID, beads.BatchIssue{ID: legBeadID, Title: leg.Title, Description: payload.Prompt, Priority: -1})
+		batch.AddDependency(leg.ID, convoyID, legBeadID, "tracks")
+		mol.adopt(batch, leg.ID, legBeadID)
+		for _, upstream := range needs {
+			batch.AddDependency(leg.ID, legBeadID, upstream, "")
+		}
+		legBeads[leg.ID] = legBeadID
+		next.Legs = append(next.Legs, runSnapshotLeg{ID: leg.ID, Title: leg.Title, Needs: leg.Needs, Payload: payload})
+	}
+
+	var synthesisBeadID string
+	if snap.Synthesis != nil {
+		synthesisBeadID = fmt.Sprintf("hq-syn-%s", generateFormulaShortID())
+		batch.Create("synthesis", beads.BatchIssue{
+			ID:          synthesisBeadID,
+			Title:       snap.Synthesis.Title,
+			Description: snap.Synthesis.Description,
+			Priority:    -1,
+		})
+		batch.AddDependency("synthesis", convoyID, synthesisBeadID, "tracks")
+		mol.adopt(batch, "synthesis", synthesisBeadID)
+		for _, leg := range next.Legs {
+			batch.AddDependency("synthesis", synthesisBeadID, legBeads[leg.ID], "")
+		}
+	}
+
+	var batchErr *beads.BatchError
+	if err := batch.Apply(); err != nil {
+		var ok bool
+		if batchErr, ok = err.(*beads.BatchError); !ok {
+			return "", fmt.Errorf("creating leg beads: %w", err)
+		}
+	}
+	mol.report(batchErr)
+	if synthesisBeadID != "" {
+		if err := batchErr.CreateFailed(synthesisBeadID); err != nil {
+			fmt.Printf("%s Failed to create synthesis bead: %v\n", style.Dim.Render("Warning:"), err)
+			synthesisBeadID = ""
+		}
+	}
+
+	fmt.Printf("\n%s Dispatching legs to polecats...\n\n", style.Bold.Render("→"))
+	slingCount, waitCount := 0, 0
+	firstLeg := ""
+	createdBeads := []string{convoyID}
+	for _, leg := range next.Legs {
+		legBeadID := legBeads[leg.ID]
+		if err := batchErr.CreateFailed(legBeadID); err != nil {
+			fmt.Printf("%s Failed to create leg bead for %s: %v\n", style.Dim.Render("Warning:"), leg.ID, err)
+			continue
+		}
+		createdBeads = append(createdBeads, legBeadID)
+		payloadPath, err := saveSlingPayload(townRoot, legBeadID, leg.Payload)
+		if err != nil {
+			fmt.Printf("%s Failed to write context for leg %s: %v\n", style.Dim.Render("Warning:"), leg.ID, err)
+			continue
+		}
+		if len(leg.Needs) > 0 {
+			fmt.Printf("  %s %s waits (after %d legs)\n", style.Dim.Render("◌"), leg.ID, len(leg.Needs))
+			waitCount++
+			continue
+		}
+		slingCmd := exec.Command("gt", "sling", legBeadID, snap.Rig, "--context-file", payloadPath)
+		slingCmd.Stdout = os.Stdout
+		slingCmd.Stderr = os.Stderr
+		if err := cmdtrace.Run(slingCmd); err != nil {
+			fmt.Printf("%s Failed to sling leg %s: %v\n", style.Dim.Render("Warning:"), leg.ID, err)
+			continue
+		}
+		slingCount++
+		if firstLeg == "" {
+			firstLeg = legBeadID
+		}
+	}
+	if synthesisBeadID != "" {
+		createdBeads = append(createdBeads, synthesisBeadID)
+	}
+
+	next.CreatedAt = time.Now().UTC()
+	if err := saveRunSnapshot(townRoot, next); err != nil {
+		fmt.Printf("%s %v\n", style.Dim.Render("Warning:"), err)
+	}
+	auditDetails := map[string]string{
+		"formula":   snap.Formula,
+		"review_id": runID,
+		"legs":      fmt.Sprintf("%d/%d dispatched", slingCount, len(snap.Legs)),
+		"beads":     auditJoin(createdBeads),
+		"rerun_of":  snap.RunID,
+		"exact":     "true",
+	}
+	if snap.Inputs.PR > 0 {
+		auditDetails["pr"] = strconv.Itoa(snap.Inputs.PR)
+	}
+	recordAudit(townRoot, snap.Rig, witness.AuditEntry{
+		Action:  witness.ActionFormulaRun,
+		Subject: convoyID,
+		Details: auditDetails,
+	})
+	hold.releaseQuota()
+
+	fmt.Printf("\n%s Convoy dispatched!\n", style.Bold.Render("✓"))
+	fmt.Printf("  Convoy:  %s (rerun of %s)\n", convoyID, snap.RunID)
+	fmt.Printf("  Legs:    %d dispatched\n", slingCount)
+	if waitCount > 0 {
+		fmt.Printf("  Waiting: %d (dispatched as the legs they need complete)\n", waitCount)
+	}
+	if synthesisBeadID != "" {
+		fmt.Printf("  Synthesis: %s (blocked until legs complete)\n", synthesisBeadID)
+	}
+	if next.OutputDir != "" {
+		fmt.Printf("  Outputs: %s\n", next.OutputDir)
+	}
+	printNextSteps(os.Stdout, dispatchNextSteps(dispatchState{
+		ConvoyID:  convoyID,
+		ReviewID:  runID,
+		Rig:       snap.Rig,
+		OutputDir: next.OutputDir,
+		FirstLeg:  firstLeg,
+	}))
+	return convoyID, nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var formulaResetForce bool
+
+var formulaResetCmd = &cobra.Command{
+	Use:   "reset <name>",
+	Short: "Restore a formula to the version shipped with gt",
+	Long: `Overwrite the town's copy of a formula (.beads/formulas/) with the
+version embedded in gt, discarding local edits.
+
+formula.reset is a protected operation by default: you are asked to type
+the formula name unless --force is given. Narrow protection to specific
+formulas in settings/config.json, e.g.:
+
+  "protected": {"operations": ["down.nuke", "formula.reset:mol-*"]}
+
+Examples:
+  gt formula diff mol-polecat-work          # Review local edits first
+  gt formula reset mol-polecat-work
+  gt formula reset mol-polecat-work --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaReset,
+}
+
+func init() {
+	formulaResetCmd.Flags().BoolVarP(&formulaResetForce, "force", "f", false, "Skip the protected-operation confirmation")
+
+	formulaCmd.AddCommand(formulaResetCmd)
+}
+
+func runFormulaReset(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, err := formula.EmbeddedFormula(name); err != nil {
+		return fmt.Errorf("%w; only formulas shipped with gt can be reset", err)
+	}
+
+	if content, err := os.ReadFile(filepath.Join(townRoot, ".beads", "formulas", name+".formula.toml")); err == nil { //nolint:gosec // G304: town formulas dir
+		if o := formula.ParseOverride(content); !o.IsZero() {
+			fmt.Printf("%s %s\n", style.Warning.Render("⚠"), i18n.Sprintf("%s is a customized override (%s)", name, formatOverride(o)))
+		}
+	}
+
+	if err := confirmProtected(townRoot, "formula.reset", name, name, formulaResetForce); err != nil {
+		return err
+	}
+	if err := formula.ResetFormula(townRoot, name); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", style.Success.Render("✓"), i18n.Sprintf("Reset %s to the embedded version", name))
+	path := filepath.Join(townRoot, ".beads", "formulas", name+".formula.toml")
+	recordFormulaOverrideAudit(name, "reset", path)
+	fireFormulaOverrideHook(name, "reset", path)
+	return nil
+}
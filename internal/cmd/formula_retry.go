@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/history"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// LegFailureLabelPrefix labels a failed leg bead with how it failed, e.g.
+// failure:empty_output, alongside LegFailedLabel.
+const LegFailureLabelPrefix = "failure:"
+
+// historyLegAttempts is recorded with the command in gt history once a
+// local formula run finishes: the legs that needed more than one attempt.
+var historyLegAttempts map[string]int
+
+// polecatLegFailureKinds are the ways a polecat leg can be seen to fail:
+// gt done labels a leg that breaks its output contract. Timeouts and
+// agent errors are only detected for --local-agent runs.
+var polecatLegFailureKinds = []string{formula.FailureEmptyOutput, formula.FailureContract}
+
+// checkPolecatLegRetry refuses a polecat run of a formula whose retry
+// policies list failures polecat legs never report, which would never
+// retry.
+func checkPolecatLegRetry(f *formulaData) error {
+	for _, leg := range f.Legs {
+		if leg.Retry == nil {
+			continue
+		}
+		for _, kind := range leg.Retry.On {
+			if !slices.Contains(polecatLegFailureKinds, kind) {
+				return fmt.Errorf("leg %s: retry.on %q only applies to --local-agent runs; polecat legs are retried on %s",
+					leg.ID, kind, strings.Join(polecatLegFailureKinds, " or "))
+			}
+		}
+	}
+	return nil
+}
+
+// legFailure is a failed leg attempt of a known kind.
+type legFailure struct {
+	Kind string
+	Err  error
+}
+
+func (e *legFailure) Error() string {
+	return e.Err.Error()
+}
+
+func (e *legFailure) Unwrap() error {
+	return e.Err
+}
+
+// legFailureKind returns the failure kind of a leg attempt's error; errors
+// of no known kind count as error.
+func legFailureKind(err error) string {
+	var lf *legFailure
+	if errors.As(err, &lf) {
+		return lf.Kind
+	}
+	return formula.FailureError
+}
+
+// agentFailureKind tells an agent that timed out from one that failed.
+func agentFailureKind(err error) string {
+	var te *agentTimeoutError
+	if errors.As(err, &te) {
+		return formula.FailureTimeout
+	}
+	return formula.FailureError
+}
+
+// retriedLegAttempts returns the attempts of each local leg that ran more
+// than once.
+func retriedLegAttempts(results []localLegResult) map[string]int {
+	var attempts map[string]int
+	for _, r := range results {
+		if r.Attempts > 1 {
+			if attempts == nil {
+				attempts = make(map[string]int)
+			}
+			attempts[r.LegID] = r.Attempts
+		}
+	}
+	return attempts
+}
+
+// failedLegKind returns how a failed leg bead failed, from its labels.
+// Legs failed before kinds were recorded broke their output contract.
+func failedLegKind(labels []string) string {
+	for _, l := range labels {
+		if kind, ok := strings.CutPrefix(l, LegFailureLabelPrefix); ok {
+			return kind
+		}
+	}
+	return formula.FailureContract
+}
+
+// retryFailedLegs reopens and re-queues a formula convoy's failed legs
+// whose retry policy covers how they failed. The leg queue dispatches each
+// once its backoff has passed. Each retry is noted in a comment on the leg
+// bead and recorded in gt history. It returns the bead IDs being retried,
+// which the caller treats as open.
+func retryFailedLegs(townBeads, description string, tracked []trackedIssueInfo, dryRun bool) map[string]bool {
+	rigName := convoyDescriptionField(description, "rig")
+	if rigName == "" {
+		return nil
+	}
+	townRoot := filepath.Dir(townBeads)
+	b := beads.New(townBeads)
+
+	retrying := make(map[string]bool)
+	var queued []convoy.QueuedLeg
+	for _, t := range tracked {
+		if t.Status != "closed" {
+			continue
+		}
+		payloadPath := slingPayloadPath(townRoot, t.ID)
+		if _, err := os.Stat(payloadPath); err != nil {
+			continue
+		}
+		p, err := loadSlingPayload(payloadPath)
+		if err != nil || p.Retry == nil || p.Retries >= p.Retry.Max {
+			continue
+		}
+		issue, err := b.Show(t.ID)
+		if err != nil || !hasLabel(issue.Labels, LegFailedLabel) {
+			continue
+		}
+		kind := failedLegKind(issue.Labels)
+		if !p.Retry.Allows(kind, p.Retries) {
+			continue
+		}
+
+		retrying[t.ID] = true
+		attempt := p.Retries + 1
+		if dryRun {
+			fmt.Printf("%s Would retry %s (%s, retry %d/%d)\n", style.Warning.Render("⚠"), t.ID, kind, attempt, p.Retry.Max)
+			continue
+		}
+
+		// The backoff runs from when the leg failed
+		failedAt := time.Now()
+		if closed, err := time.Parse(time.RFC3339, issue.ClosedAt); err == nil {
+			failedAt = closed
+		}
+		notBefore := failedAt.Add(p.Retry.BackoffDuration())
+
+		p.Retries = attempt
+		if _, err := saveSlingPayload(townRoot, t.ID, p); err != nil {
+			fmt.Printf("%s Failed to save %s retry count: %v\n", style.Dim.Render("Warning:"), t.ID, err)
+			delete(retrying, t.ID)
+			continue
+		}
+		open, unassigned := "open", ""
+		if err := b.Update(t.ID, beads.UpdateOptions{
+			Status:       &open,
+			Assignee:     &unassigned,
+			RemoveLabels: []string{LegFailedLabel, LegFailureLabelPrefix + kind},
+		}); err != nil {
+			fmt.Printf("%s Failed to reopen %s for retry: %v\n", style.Dim.Render("Warning:"), t.ID, err)
+			delete(retrying, t.ID)
+			continue
+		}
+		note := fmt.Sprintf("Retry %d/%d: leg failed (%s); re-queued to dispatch after %s",
+			attempt, p.Retry.Max, kind, notBefore.Local().Format("15:04:05"))
+		commentCmd := exec.Command("bd", "comment", t.ID, note)
+		commentCmd.Dir = townBeads
+		if err := cmdtrace.Run(commentCmd); err != nil {
+			fmt.Printf("%s Failed to comment on %s: %v\n", style.Dim.Render("Warning:"), t.ID, err)
+		}
+
+		queued = append(queued, convoy.QueuedLeg{
+			BeadID:    t.ID,
+			Rig:       rigName,
+			ConvoyID:  p.ConvoyID,
+			Payload:   payloadPath,
+			Priority:  p.Priority,
+			NotBefore: notBefore,
+		})
+		fmt.Printf("%s Retrying %s: %s (%s, retry %d/%d)\n", style.Warning.Render("↻"), t.ID, t.Title, kind, attempt, p.Retry.Max)
+
+		legID := p.Env["GT_LEG"]
+		if legID == "" {
+			legID = t.ID
+		}
+		// The run's --label labels are on its convoy
+		var labels []string
+		if cv, err := b.Show(p.ConvoyID); err == nil {
+			labels = cv.Labels
+		}
+		_ = history.Append(townRoot, history.Entry{
+			Time:        time.Now().UTC(),
+			Command:     "gt convoy check",
+			Town:        townRoot,
+			Rig:         rigName,
+			Formula:     convoyDescriptionField(description, "formula"),
+			Labels:      labels,
+			Convoy:      p.ConvoyID,
+			LegAttempts: map[string]int{legID: attempt + 1},
+		})
+	}
+	if err := enqueueLegs(townRoot, queued); err != nil {
+		fmt.Printf("%s Failed to queue retried legs: %v\n", style.Dim.Render("Warning:"), err)
+	}
+	return retrying
+}
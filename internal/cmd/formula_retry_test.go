@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/history"
+)
+
+func TestParseLegRetry(t *testing.T) {
+	f, err := parseFormulaContent(t, `formula = "review"
+
+[[legs]]
+id = "security"
+retry = { max = 2, backoff = "30s", on = ["timeout", "empty_output"] }
+
+[[legs]]
+id = "style"
+`)
+	if err != nil || len(f.Legs) != 2 {
+		t.Fatalf("parseFormulaFile() = %+v, %v", f, err)
+	}
+	r := f.Legs[0].Retry
+	if r == nil || r.Max != 2 || r.Backoff != "30s" || strings.Join(r.On, ",") != "timeout,empty_output" {
+		t.Errorf("security retry = %+v", r)
+	}
+	if f.Legs[1].Retry != nil {
+		t.Errorf("style retry = %+v, want nil", f.Legs[1].Retry)
+	}
+
+	if _, err := parseFormulaContent(t, "formula = \"review\"\n[[legs]]\nid = \"broken\"\nretry = { max = 2, on = [\"flaky\"] }\n"); err == nil {
+		t.Error("parseFormulaFile() accepted an unknown retry.on kind")
+	}
+}
+
+func TestCheckPolecatLegRetry(t *testing.T) {
+	f := &formulaData{Legs: []formulaLeg{
+		{ID: "style"},
+		{ID: "security", Retry: &formula.LegRetry{Max: 2, On: []string{"empty_output", "contract"}}},
+		{ID: "any", Retry: &formula.LegRetry{Max: 1}},
+	}}
+	if err := checkPolecatLegRetry(f); err != nil {
+		t.Errorf("checkPolecatLegRetry() = %v", err)
+	}
+
+	for _, kind := range []string{"timeout", "error"} {
+		f.Legs[1].Retry.On = []string{"empty_output", kind}
+		if err := checkPolecatLegRetry(f); err == nil || !strings.Contains(err.Error(), "security") {
+			t.Errorf("retry.on %s: checkPolecatLegRetry() = %v, want an error naming the leg", kind, err)
+		}
+	}
+}
+
+func TestLegFailureKinds(t *testing.T) {
+	timeout := &agentTimeoutError{"agent claude timed out after 10m0s"}
+	if got := agentFailureKind(timeout); got != formula.FailureTimeout {
+		t.Errorf("agentFailureKind(timeout) = %q", got)
+	}
+	if got := agentFailureKind(errors.New("running agent claude: exit status 1")); got != formula.FailureError {
+		t.Errorf("agentFailureKind(exit) = %q", got)
+	}
+	if got := legFailureKind(&legFailure{Kind: formula.FailureEmptyOutput, Err: errors.New("empty")}); got != formula.FailureEmptyOutput {
+		t.Errorf("legFailureKind(empty) = %q", got)
+	}
+	if got := failedLegKind([]string{LegFailedLabel, "failure:empty_output"}); got != formula.FailureEmptyOutput {
+		t.Errorf("failedLegKind() = %q", got)
+	}
+	if got := failedLegKind([]string{LegFailedLabel}); got != formula.FailureContract {
+		t.Errorf("failedLegKind() without a kind = %q, want contract", got)
+	}
+
+	// A chain that only timed out is a timeout, not a failure
+	_, _, err := runAgentChain([]string{"claude", "gemini"}, &strings.Builder{}, func(a string) (string, error) {
+		return "", &agentTimeoutError{"agent " + a + " timed out after 10m0s"}
+	})
+	if got := agentFailureKind(err); got != formula.FailureTimeout {
+		t.Errorf("agentFailureKind(all timed out) = %q (%v)", got, err)
+	}
+}
+
+func TestExecuteConvoyFormulaLocalRetry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell script agent stubs")
+	}
+
+	// The agent replies with nothing the first time it is asked
+	binDir := t.TempDir()
+	marker := filepath.Join(binDir, "asked")
+	script := "#!/bin/sh\nif [ -f " + marker + " ]; then echo finding; else touch " + marker + "; fi\n"
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	if err := config.SaveAgentRegistry(config.DefaultAgentRegistryPath(workDir), &config.AgentRegistry{
+		Version: config.CurrentAgentRegistryVersion,
+		Order:   []string{"claude"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &formulaData{
+		Type:  "convoy",
+		Agent: "claude",
+		Legs: []formulaLeg{{ID: "a", Title: "A", Retry: &formula.LegRetry{
+			Max: 2,
+			On:  []string{formula.FailureEmptyOutput},
+		}}},
+	}
+	formulaRunOutput = filepath.Join(workDir, "report.md")
+	defer func() {
+		formulaRunOutput = ""
+		historyLegAttempts = nil
+	}()
+
+	if err := executeConvoyFormulaLocal(f, "review", "gastown", runHold{}); err != nil {
+		t.Fatalf("executeConvoyFormulaLocal() = %v", err)
+	}
+	data, err := os.ReadFile(formulaRunOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "finding") {
+		t.Errorf("report doesn't have the retried leg's reply:\n%s", data)
+	}
+	if historyLegAttempts["a"] != 2 {
+		t.Errorf("historyLegAttempts = %v, want a: 2", historyLegAttempts)
+	}
+}
+
+func TestLegRetryStats(t *testing.T) {
+	entries := []history.Entry{
+		{Formula: "review", LegAttempts: map[string]int{"security": 3}},
+		{Formula: "review"},
+		{Formula: "review", Convoy: "hq-cv-1", LegAttempts: map[string]int{"perf": 2}},
+		{Formula: "review", Convoy: "hq-cv-1", LegAttempts: map[string]int{"perf": 3}},
+		{Formula: "review", Convoy: "hq-cv-2", LegAttempts: map[string]int{"perf": 2}},
+		{Formula: "review", Convoy: "hq-cv-2", LegAttempts: map[string]int{"security": 2}},
+	}
+	got := legRetryStats(entries)
+	want := []legRetryStat{
+		{Formula: "review", Leg: "perf", Retries: 3, Runs: 2},
+		{Formula: "review", Leg: "security", Retries: 3, Runs: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("legRetryStats() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("legRetryStats()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
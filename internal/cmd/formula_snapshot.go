@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/history"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// runSnapshot is the fully resolved context of a convoy formula run: the
+// inputs it was rendered from, the formula it used, and every leg's
+// rendered sling payload. gt formula rerun replays runs from it.
+type runSnapshot struct {
+	RunID       string    `json:"run_id"` // The run's review_id
+	ConvoyID    string    `json:"convoy_id"`
+	Formula     string    `json:"formula"`
+	FormulaPath string    `json:"formula_path,omitempty"`
+	FormulaHash string    `json:"formula_hash,omitempty"` // SHA-256 of the formula file
+	Rig         string    `json:"rig"`
+	CreatedAt   time.Time `json:"created_at"`
+	RerunOf     string    `json:"rerun_of,omitempty"`
+
+	// ConcurrencyClass is the formula's concurrency class, if it had one.
+	ConcurrencyClass string `json:"concurrency_class,omitempty"`
+
+	// Env is the environment the run was dispatched from.
+	Env *history.Fingerprint `json:"env,omitempty"`
+
+	// Vars are the template variables the leg prompts were rendered with.
+	Vars   map[string]string `json:"vars"`
+	Inputs runInputs         `json:"inputs"`
+
+	OutputDir string                `json:"output_dir,omitempty"`
+	Legs      []runSnapshotLeg      `json:"legs"`
+	Synthesis *runSnapshotSynthesis `json:"synthesis,omitempty"`
+}
+
+// runInputs is what a run fetched about its target. A rerun reuses them
+// instead of asking GitHub again, so it sees the PR as it was.
+type runInputs struct {
+	PR                int                      `json:"pr,omitempty"`
+	HeadSHA           string                   `json:"head_sha,omitempty"`
+	PRTitle           string                   `json:"pr_title,omitempty"`
+	TargetDescription string                   `json:"target_description"`
+	ChangedFiles      []map[string]interface{} `json:"changed_files,omitempty"`
+	LegContext        string                   `json:"leg_context,omitempty"` // Diff context appended to each leg
+}
+
+// runSnapshotLeg is one dispatched leg, in dispatch order.
+type runSnapshotLeg struct {
+	ID      string        `json:"id"`
+	Title   string        `json:"title"`
+	Needs   []string      `json:"needs,omitempty"` // Leg IDs
+	Payload *slingPayload `json:"payload"`
+}
+
+type runSnapshotSynthesis struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// runSnapshotDir is where run snapshots are kept.
+func runSnapshotDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "runs")
+}
+
+func runSnapshotPath(townRoot, runID string) string {
+	return filepath.Join(runSnapshotDir(townRoot), runID+".json")
+}
+
+// newRunSnapshot starts a snapshot of a run, hashing the formula file.
+func newRunSnapshot(f *formulaData, formulaName, rig, runID, convoyID string, inputs runInputs) *runSnapshot {
+	snap := &runSnapshot{
+		RunID:       runID,
+		ConvoyID:    convoyID,
+		Formula:     formulaName,
+		FormulaPath: f.Path,
+		Rig:         rig,
+		CreatedAt:   time.Now().UTC(),
+		Env:         historyRunEnv,
+		Inputs:      inputs,
+		Vars: map[string]string{
+			"formula_name":       formulaName,
+			"review_id":          runID,
+			"target_description": inputs.TargetDescription,
+			"pr_number":          strconv.Itoa(inputs.PR),
+			"pr_title":           inputs.PRTitle,
+		},
+	}
+	if f.Path != "" {
+		if data, err := os.ReadFile(f.Path); err == nil { //nolint:gosec // G304: formula path from the search path
+			snap.FormulaHash = shortContentHash(data)
+		}
+	}
+	return snap
+}
+
+func saveRunSnapshot(townRoot string, snap *runSnapshot) error {
+	if err := os.MkdirAll(runSnapshotDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating snapshot dir: %w", err)
+	}
+	if err := util.AtomicWriteJSON(runSnapshotPath(townRoot, snap.RunID), snap); err != nil {
+		return fmt.Errorf("writing run snapshot: %w", err)
+	}
+	return nil
+}
+
+func loadRunSnapshot(townRoot, runID string) (*runSnapshot, error) {
+	data, err := os.ReadFile(runSnapshotPath(townRoot, runID)) //nolint:gosec // G304: path built from the run ID
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no snapshot for run %s (runs started before snapshots were recorded can't be rerun)", runID)
+		}
+		return nil, fmt.Errorf("reading run snapshot: %w", err)
+	}
+	var snap runSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parsing run snapshot %s: %w", runID, err)
+	}
+	return &snap, nil
+}
+
+// runSnapshotRewriter moves a replayed run's locations to the new run:
+// the output directory, leg worktrees and branches, and the convoy ID.
+// The prompts are otherwise left byte-for-byte as recorded.
+func runSnapshotRewriter(snap *runSnapshot, newRunID, newConvoyID string) *strings.Replacer {
+	moved := make(map[string]string)
+	move := func(s string) {
+		if s != "" && strings.Contains(s, snap.RunID) {
+			moved[s] = strings.ReplaceAll(s, snap.RunID, newRunID)
+		}
+	}
+	move(snap.OutputDir)
+	if abs, err := filepath.Abs(snap.OutputDir); err == nil && snap.OutputDir != "" {
+		move(abs)
+	}
+	for _, leg := range snap.Legs {
+		if leg.Payload != nil {
+			move(leg.Payload.Workdir)
+			move(leg.Payload.Branch)
+		}
+	}
+	moved[snap.ConvoyID] = newConvoyID
+
+	// Longest first, so an absolute path wins over its relative suffix
+	olds := make([]string, 0, len(moved))
+	for old := range moved {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool { return len(olds[i]) > len(olds[j]) })
+	pairs := make([]string, 0, 2*len(olds))
+	for _, old := range olds {
+		pairs = append(pairs, old, moved[old])
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// replayPayload copies a recorded leg payload for a new run, with its
+// locations rewritten and needs mapped to the new run's leg beads. A
+// rerun dispatches every leg at once, so canaries are dropped.
+func replayPayload(p *slingPayload, r *strings.Replacer, newRunID, newConvoyID string, needs []string) *slingPayload {
+	out := *p
+	out.BeadID = ""
+	out.Canary = nil
+	out.Retries = 0
+	out.ConvoyID = newConvoyID
+	out.Prompt = r.Replace(p.Prompt)
+	out.OutputPath = r.Replace(p.OutputPath)
+	out.Workdir = r.Replace(p.Workdir)
+	out.Branch = r.Replace(p.Branch)
+	out.Needs = needs
+	if p.Env != nil {
+		out.Env = make(map[string]string, len(p.Env))
+		for k, v := range p.Env {
+			out.Env[k] = r.Replace(v)
+		}
+		out.Env["GT_CONVOY"] = newConvoyID
+		out.Env["GT_REVIEW_ID"] = newRunID
+	}
+	if p.Expect != nil {
+		expect := *p.Expect
+		expect.Files = make([]string, len(p.Expect.Files))
+		for i, file := range p.Expect.Files {
+			expect.Files[i] = r.Replace(file)
+		}
+		out.Expect = &expect
+	}
+	return &out
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestRunSnapshotRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	formulaPath := filepath.Join(townRoot, "review.formula.toml")
+	if err := os.WriteFile(formulaPath, []byte("formula = \"review\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := &formulaData{Path: formulaPath}
+
+	snap := newRunSnapshot(f, "review", "gastown", "k3x9a", "hq-cv-abcde", runInputs{
+		PR:                42,
+		PRTitle:           "Fix the thing",
+		TargetDescription: "PR #42",
+		ChangedFiles:      []map[string]interface{}{{"path": "main.go"}},
+	})
+	snap.Legs = []runSnapshotLeg{{ID: "security", Title: "Security", Payload: &slingPayload{Prompt: "look closely"}}}
+	if snap.FormulaHash == "" {
+		t.Error("FormulaHash should be set from the formula file")
+	}
+	if snap.Vars["pr_number"] != "42" || snap.Vars["review_id"] != "k3x9a" {
+		t.Errorf("Vars = %v", snap.Vars)
+	}
+
+	if err := saveRunSnapshot(townRoot, snap); err != nil {
+		t.Fatalf("saveRunSnapshot: %v", err)
+	}
+	got, err := loadRunSnapshot(townRoot, "k3x9a")
+	if err != nil {
+		t.Fatalf("loadRunSnapshot: %v", err)
+	}
+	if got.Inputs.PRTitle != "Fix the thing" || got.Inputs.ChangedFiles[0]["path"] != "main.go" {
+		t.Errorf("Inputs = %+v", got.Inputs)
+	}
+	if len(got.Legs) != 1 || got.Legs[0].Payload.Prompt != "look closely" {
+		t.Errorf("Legs = %+v", got.Legs)
+	}
+
+	if _, err := loadRunSnapshot(townRoot, "nope1"); err == nil || !strings.Contains(err.Error(), "no snapshot") {
+		t.Errorf("missing snapshot error = %v", err)
+	}
+}
+
+func TestReplayPayloadRewritesLocations(t *testing.T) {
+	outputDir := filepath.Join(".reviews", "k3x9a")
+	absOutput, _ := filepath.Abs(outputDir)
+	snap := &runSnapshot{
+		RunID:     "k3x9a",
+		ConvoyID:  "hq-cv-old01",
+		OutputDir: outputDir,
+		Legs: []runSnapshotLeg{{
+			ID: "perf",
+			Payload: &slingPayload{
+				BeadID:     "hq-leg-old02",
+				ConvoyID:   "hq-cv-old01",
+				Prompt:     "Review k3x9a's diff. Write " + filepath.Join(outputDir, "perf.md") + " for convoy hq-cv-old01.",
+				OutputPath: filepath.Join(outputDir, "perf.md"),
+				Workdir:    "/town/rig/legs/k3x9a/perf",
+				Branch:     "review/k3x9a/perf",
+				Env:        map[string]string{"GT_CONVOY": "hq-cv-old01", "GT_REVIEW_ID": "k3x9a", "GT_LEG": "perf"},
+				Expect:     &formula.LegExpect{Files: []string{filepath.Join(absOutput, "perf.md")}, MinBytes: 10},
+				Needs:      []string{"hq-leg-old03"},
+			},
+		}},
+	}
+
+	r := runSnapshotRewriter(snap, "n3w00", "hq-cv-new01")
+	got := replayPayload(snap.Legs[0].Payload, r, "n3w00", "hq-cv-new01", []string{"hq-leg-new03"})
+
+	newOutput := filepath.Join(".reviews", "n3w00")
+	wantPrompt := "Review k3x9a's diff. Write " + filepath.Join(newOutput, "perf.md") + " for convoy hq-cv-new01."
+	if got.Prompt != wantPrompt {
+		t.Errorf("Prompt = %q, want %q (bare run IDs left alone)", got.Prompt, wantPrompt)
+	}
+	if got.OutputPath != filepath.Join(newOutput, "perf.md") {
+		t.Errorf("OutputPath = %q", got.OutputPath)
+	}
+	if got.Workdir != "/town/rig/legs/n3w00/perf" || got.Branch != "review/n3w00/perf" {
+		t.Errorf("Workdir, Branch = %q, %q", got.Workdir, got.Branch)
+	}
+	wantEnv := map[string]string{"GT_CONVOY": "hq-cv-new01", "GT_REVIEW_ID": "n3w00", "GT_LEG": "perf"}
+	if !reflect.DeepEqual(got.Env, wantEnv) {
+		t.Errorf("Env = %v, want %v", got.Env, wantEnv)
+	}
+	if want := filepath.Join(filepath.Dir(absOutput), "n3w00", "perf.md"); got.Expect.Files[0] != want || got.Expect.MinBytes != 10 {
+		t.Errorf("Expect = %+v, want file %s", got.Expect, want)
+	}
+	if got.BeadID != "" || got.ConvoyID != "hq-cv-new01" || !reflect.DeepEqual(got.Needs, []string{"hq-leg-new03"}) {
+		t.Errorf("BeadID, ConvoyID, Needs = %q, %q, %v", got.BeadID, got.ConvoyID, got.Needs)
+	}
+
+	// The recorded payload is left untouched
+	if snap.Legs[0].Payload.Env["GT_REVIEW_ID"] != "k3x9a" || snap.Legs[0].Payload.Expect.Files[0] != filepath.Join(absOutput, "perf.md") {
+		t.Error("replayPayload modified the recorded payload")
+	}
+}
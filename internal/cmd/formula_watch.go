@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/offline"
+	"github.com/steveyegge/gastown/internal/scm"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Watch flags
+var (
+	formulaRunWatchPR       bool
+	formulaRunWatchInterval time.Duration
+)
+
+// Set by the watch loop for the run being dispatched; recorded in the
+// convoy description and the rig's audit log to link runs of one PR.
+var (
+	formulaRunHeadSHA    string
+	formulaRunSupersedes string
+)
+
+func init() {
+	formulaRunCmd.Flags().BoolVar(&formulaRunWatchPR, "watch-pr", false, "With --pr, re-run whenever the PR gets new commits, superseding the previous convoy")
+	formulaRunCmd.Flags().DurationVar(&formulaRunWatchInterval, "watch-interval", 2*time.Minute, "How often --watch-pr polls the PR")
+}
+
+// watchPRFormula runs a convoy formula on a PR, then polls the PR and
+// re-runs it whenever its head moves. Each new convoy supersedes the
+// previous one: the old convoy and its unfinished legs are closed and their
+// polecats stopped. Stops when the PR is merged or closed.
+func watchPRFormula(f *formulaData, formulaName, targetRig string) error {
+	if formulaRunPR <= 0 {
+		return fmt.Errorf("--watch-pr requires --pr")
+	}
+	if formulaRunLocalAgent {
+		return fmt.Errorf("--watch-pr cannot be used with --local-agent")
+	}
+	if err := offline.Check("poll the PR for new commits"); err != nil {
+		return fmt.Errorf("--watch-pr: %w", err)
+	}
+	if formulaRunWatchInterval < 10*time.Second {
+		return fmt.Errorf("--watch-interval must be at least 10s")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	townBeads := filepath.Join(townRoot, ".beads")
+	provider, err := formulaPRProvider(townRoot, filepath.Join(townRoot, targetRig))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Watching PR #%d (every %s, Ctrl-C to stop)\n\n",
+		style.Bold.Render("👁"), formulaRunPR, formulaRunWatchInterval)
+
+	var convoyID, headSHA string
+	for {
+		state, sha, err := fetchPRHead(provider, formulaRunPR)
+		switch {
+		case err != nil:
+			fmt.Printf("%s Could not check PR #%d: %v\n", style.Dim.Render("Warning:"), formulaRunPR, err)
+		case state != "OPEN":
+			fmt.Printf("%s PR #%d is %s; stopping watch\n", style.Bold.Render("✓"), formulaRunPR, strings.ToLower(state))
+			return nil
+		case sha != headSHA:
+			if headSHA != "" {
+				fmt.Printf("\n%s PR #%d has new commits: %s → %s\n\n",
+					style.Bold.Render("↻"), formulaRunPR, shortSHA(headSHA), shortSHA(sha))
+			}
+			hold, _, err := formulaPreflight{
+				TownRoot:      townRoot,
+				Rig:           targetRig,
+				Formula:       formulaName,
+				F:             f,
+				OverridePause: formulaRunOverridePause,
+				Poll:          true,
+			}.check()
+			if err != nil {
+				// Try again next poll; headSHA stays unchanged.
+				fmt.Printf("%s Not re-running: %v\n", style.Dim.Render("Warning:"), err)
+				break
+			}
+			// Another run (a webhook, a manual or local run) may already
+			// cover this head. A local run has no convoy to adopt.
+			newID, local := "", false
+			if !formulaRunForce {
+				newID, local = findPRRun(townRoot, formulaName, targetRig, formulaRunPR, sha)
+			}
+			if local {
+				hold.release()
+				fmt.Printf("%s %s already covered by %s\n", style.Dim.Render("○"), shortSHA(sha), describePRRun(newID, local))
+				headSHA = sha
+				break
+			}
+			if newID != "" {
+				fmt.Printf("%s %s already covered by %s\n", style.Dim.Render("○"), shortSHA(sha), describePRRun(newID, local))
+			} else {
+				formulaRunHeadSHA, formulaRunSupersedes = sha, convoyID
+				newID, err = executeConvoyFormula(f, formulaName, targetRig, hold)
+			}
+			hold.release()
+			if err != nil {
+				fmt.Printf("%s Run for %s failed: %v\n", style.Dim.Render("Warning:"), shortSHA(sha), err)
+				break
+			}
+			if convoyID != "" && convoyID != newID {
+				supersedeConvoy(townBeads, convoyID, newID)
+			}
+			convoyID, headSHA = newID, sha
+		}
+		time.Sleep(formulaRunWatchInterval)
+	}
+}
+
+// fetchPRHead returns a PR's state (OPEN, CLOSED, MERGED) and head commit.
+func fetchPRHead(p scm.Provider, prNumber int) (state, sha string, err error) {
+	pr, err := p.PR(context.Background(), prNumber)
+	if err != nil {
+		return "", "", err
+	}
+	return pr.State, pr.HeadSHA, nil
+}
+
+// prRunFields returns the convoy description lines linking a PR run to its
+// head commit and to the run it supersedes.
+func prRunFields(headSHA, supersedes string) string {
+	var b strings.Builder
+	if headSHA != "" {
+		fmt.Fprintf(&b, "\nhead_sha: %s", headSHA)
+	}
+	if supersedes != "" {
+		fmt.Fprintf(&b, "\nsupersedes: %s", supersedes)
+	}
+	return b.String()
+}
+
+// supersedeConvoy closes an outdated convoy and its unfinished legs, and
+// stops the polecats still working on them.
+func supersedeConvoy(townBeads, oldID, newID string) {
+	reason := "superseded by " + newID
+	sessions := session.OpenBackend(filepath.Dir(townBeads), nil)
+	for _, issue := range getTrackedIssues(townBeads, oldID) {
+		if issue.Status == "closed" {
+			continue
+		}
+		closeCmd := exec.Command("bd", "close", issue.ID, "-r", reason)
+		closeCmd.Dir = townBeads
+		if err := cmdtrace.Run(closeCmd); err != nil {
+			fmt.Printf("%s Failed to close %s: %v\n", style.Dim.Render("Warning:"), issue.ID, err)
+		}
+		if rig, name, ok := polecatFromAssignee(issue.Assignee); ok {
+			_ = sessions.KillSession(session.PolecatSessionName(rig, name))
+		}
+	}
+	closeCmd := exec.Command("bd", "close", oldID, "-r", reason)
+	closeCmd.Dir = townBeads
+	if err := cmdtrace.Run(closeCmd); err != nil {
+		fmt.Printf("%s Failed to close convoy %s: %v\n", style.Dim.Render("Warning:"), oldID, err)
+		return
+	}
+	fmt.Printf("%s Superseded convoy %s\n", style.Dim.Render("○"), oldID)
+}
+
+// polecatFromAssignee parses a "<rig>/polecats/<name>" assignee.
+func polecatFromAssignee(assignee string) (rig, name string, ok bool) {
+	parts := strings.Split(strings.TrimSuffix(assignee, "/"), "/")
+	if len(parts) != 3 || parts[1] != "polecats" || parts[0] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[2], true
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import "testing"
+
+func TestPRRunFields(t *testing.T) {
+	if got := prRunFields("", ""); got != "" {
+		t.Errorf("no watch: %q", got)
+	}
+	want := "\nhead_sha: abc123\nsupersedes: hq-cv-old"
+	if got := prRunFields("abc123", "hq-cv-old"); got != want {
+		t.Errorf("prRunFields = %q, want %q", got, want)
+	}
+}
+
+func TestPolecatFromAssignee(t *testing.T) {
+	if rig, name, ok := polecatFromAssignee("gastown/polecats/Toast"); !ok || rig != "gastown" || name != "Toast" {
+		t.Errorf("polecat assignee = %q, %q, %v", rig, name, ok)
+	}
+	for _, a := range []string{"", "mayor/", "gastown/crew/max", "gastown/polecats/"} {
+		if _, _, ok := polecatFromAssignee(a); ok {
+			t.Errorf("polecatFromAssignee(%q) matched", a)
+		}
+	}
+}
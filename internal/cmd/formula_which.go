@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var formulaWhichJSON bool
+
+var formulaWhichCmd = &cobra.Command{
+	Use:   "which <name>",
+	Short: "Explain which file a formula name resolves to",
+	Long: `Show every location examined when resolving a formula name, which one
+won, and why the others were skipped.
+
+Candidates are checked in search order (project, rig, town, user), each with
+.formula.toml before .formula.json. The first file found wins; later
+candidates are shadowed. The version embedded in gt is listed last: it is
+what gt install provisions, and is never run directly.
+
+Each existing candidate shows a short content hash, so you can tell at a
+glance whether two copies differ.
+
+Examples:
+  gt formula which code-review
+  gt formula which code-review --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaWhich,
+}
+
+func init() {
+	formulaWhichCmd.Flags().BoolVar(&formulaWhichJSON, "json", false, "Output as JSON")
+	formulaCmd.AddCommand(formulaWhichCmd)
+}
+
+// Formula candidate statuses.
+const (
+	candidateSelected = "selected"
+	candidateShadowed = "shadowed"
+	candidateMissing  = "missing"
+	candidateEmbedded = "embedded"
+)
+
+// formulaCandidate is one location examined while resolving a formula.
+type formulaCandidate struct {
+	Source string `json:"source"` // project, rig, town, user, embedded
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Hash   string `json:"hash,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// resolveFormulaCandidates lists every candidate for name in search order,
+// marking the first existing file as selected.
+func resolveFormulaCandidates(name string) []formulaCandidate {
+	cache := openFormulaCache()
+	defer func() { _ = cache.Save() }()
+
+	var candidates []formulaCandidate
+	var selected *formulaCandidate
+	seenDirs := make(map[string]string) // dir -> label that already covered it
+
+	for _, sp := range formulaSearchPaths() {
+		dir := filepath.Clean(sp.Dir)
+		for _, ext := range formulaExtensions {
+			c := formulaCandidate{Source: sp.Label, Path: filepath.Join(dir, name+ext)}
+			if prev, ok := seenDirs[dir]; ok && prev != sp.Label {
+				c.Status = candidateShadowed
+				c.Reason = "same directory as " + prev
+				candidates = append(candidates, c)
+				continue
+			}
+			hash, _, err := cache.Lookup(c.Path)
+			if err != nil {
+				c.Status = candidateMissing
+				candidates = append(candidates, c)
+				continue
+			}
+			c.Hash = hash[:12]
+			if selected == nil {
+				c.Status = candidateSelected
+				c.Reason = "first match in search order"
+			} else {
+				c.Status = candidateShadowed
+				c.Reason = fmt.Sprintf("%s %s wins (higher priority)", selected.Source, filepath.Base(selected.Path))
+				if c.Hash == selected.Hash {
+					c.Reason += "; identical content"
+				}
+			}
+			candidates = append(candidates, c)
+			if selected == nil {
+				selected = &c
+			}
+		}
+		if _, ok := seenDirs[dir]; !ok {
+			seenDirs[dir] = sp.Label
+		}
+	}
+
+	if data, err := formula.EmbeddedFormula(name); err == nil {
+		c := formulaCandidate{
+			Source: "embedded",
+			Path:   "embedded/" + name + ".formula.toml",
+			Status: candidateEmbedded,
+			Hash:   shortContentHash(data),
+		}
+		switch {
+		case selected == nil:
+			c.Reason = "not installed; run gt install or copy it into .beads/formulas/"
+		case selected.Hash == c.Hash:
+			c.Reason = "matches selected"
+		default:
+			c.Reason = "differs from selected (see gt formula diff " + name + ")"
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+// shortContentHash returns the first 12 hex digits of the SHA-256 of data.
+func shortContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func runFormulaWhich(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	candidates := resolveFormulaCandidates(name)
+
+	if formulaWhichJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(candidates)
+	}
+
+	var winner string
+	for _, c := range candidates {
+		if c.Status == candidateSelected {
+			winner = c.Path
+		}
+	}
+	if winner != "" {
+		fmt.Printf("%s %s → %s\n\n", style.Success.Render("✓"), style.Bold.Render(name), winner)
+	} else {
+		fmt.Printf("%s %s not found in search paths\n\n", style.Error.Render("✗"), style.Bold.Render(name))
+	}
+
+	for _, c := range candidates {
+		var mark string
+		switch c.Status {
+		case candidateSelected:
+			mark = style.Success.Render("→")
+		case candidateShadowed:
+			mark = style.Warning.Render("⚠")
+		case candidateEmbedded:
+			mark = style.Dim.Render("○")
+		default:
+			mark = style.Dim.Render("·")
+		}
+		fmt.Printf("  %s %-8s %s\n", mark, c.Source, c.Path)
+		switch {
+		case c.Hash != "" && c.Reason != "":
+			fmt.Printf("             %s\n", style.Dim.Render(fmt.Sprintf("%s  %s", c.Hash, c.Reason)))
+		case c.Hash != "":
+			fmt.Printf("             %s\n", style.Dim.Render(c.Hash))
+		case c.Reason != "":
+			fmt.Printf("             %s\n", style.Dim.Render(c.Reason))
+		}
+	}
+
+	if winner == "" {
+		return NewSilentExit(1)
+	}
+	return nil
+}
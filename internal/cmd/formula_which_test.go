@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+func TestResolveFormulaCandidates(t *testing.T) {
+	workDir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := filepath.Join(workDir, ".beads", "formulas")
+	userDir := filepath.Join(home, ".beads", "formulas")
+	for _, dir := range []string{projectDir, userDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "code-review.formula.toml"), []byte("formula = \"code-review\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	byKey := make(map[string]formulaCandidate)
+	for _, c := range resolveFormulaCandidates("code-review") {
+		byKey[c.Source+" "+filepath.Base(c.Path)] = c
+	}
+
+	project := byKey["project code-review.formula.toml"]
+	if project.Status != candidateSelected || project.Hash == "" {
+		t.Errorf("project candidate = %+v, want selected with hash", project)
+	}
+	if c := byKey["project code-review.formula.json"]; c.Status != candidateMissing {
+		t.Errorf("project json candidate = %+v, want missing", c)
+	}
+	user := byKey["user code-review.formula.toml"]
+	if user.Status != candidateShadowed || user.Hash != project.Hash {
+		t.Errorf("user candidate = %+v, want shadowed with same hash", user)
+	}
+	embedded := byKey["embedded code-review.formula.toml"]
+	if embedded.Status != candidateEmbedded || embedded.Hash == project.Hash {
+		t.Errorf("embedded candidate = %+v, want embedded with different hash", embedded)
+	}
+
+	for _, c := range resolveFormulaCandidates("no-such-formula") {
+		if c.Status == candidateSelected {
+			t.Errorf("unexpected selected candidate %+v", c)
+		}
+	}
+}
+
+func TestFindFormulaFileSuggestsNearest(t *testing.T) {
+	workDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := filepath.Join(workDir, ".beads", "formulas")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "my-review.formula.toml"), []byte("formula = \"my-review\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = findFormulaFile("my-reveiw")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"Formula 'my-reveiw' not found", "Did you mean?", "• my-review", "Searched:", projectDir + " (project)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%s", want, err)
+		}
+	}
+}
+
+func TestRigNotFoundError(t *testing.T) {
+	err := rigNotFoundError("gastwon", []string{"gastown", "beads"})
+	if !strings.Contains(err.Error(), "• gastown") || strings.Contains(err.Error(), "• beads") {
+		t.Errorf("unexpected suggestions:\n%s", err)
+	}
+	if err := rigNotFoundError("zzz", []string{"gastown"}); strings.Contains(err.Error(), "Did you mean") {
+		t.Errorf("unrelated name should have no suggestions:\n%s", err)
+	}
+}
+
+func TestParseFormulaFileIncludesFromUserDir(t *testing.T) {
+	workDir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := filepath.Join(workDir, ".beads", "formulas")
+	fragmentDir := filepath.Join(home, ".beads", "formulas", "fragments")
+	for _, dir := range []string{projectDir, fragmentDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	formulaPath := filepath.Join(projectDir, "review.formula.toml")
+	if err := os.WriteFile(formulaPath, []byte("formula = \"review\"\ntype = \"convoy\"\ninclude = \"fragments/mine.toml\"\n\n[[legs]]\nid = \"a\"\ntitle = \"A\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(fragmentDir, "mine.toml"), []byte("[[legs]]\nid = \"b\"\ntitle = \"B\"\nneeds = [\"a\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parseFormulaFile(formulaPath)
+	if err != nil {
+		t.Fatalf("parseFormulaFile() error = %v", err)
+	}
+	if len(f.Legs) != 2 || f.Legs[1].ID != "b" || f.Legs[1].Title != "B" || len(f.Legs[1].Needs) != 1 {
+		t.Errorf("legs = %+v, want a and included b", f.Legs)
+	}
+}
+
+func TestFindFormulaFileRigLevel(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	if err := workspace.WriteMarker(townRoot, "test"); err != nil {
+		t.Fatal(err)
+	}
+	rigDir := filepath.Join(townRoot, "gastown")
+	workDir := filepath.Join(rigDir, "polecats", "toast")
+	rigFormulas := filepath.Join(rigDir, ".beads", "formulas")
+	for _, dir := range []string{workDir, rigFormulas} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(rigDir, "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigFormulas, "my-review.formula.toml"), []byte("formula = \"my-review\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(workDir)
+
+	// A formula promoted to rig:gastown is found from inside the rig
+	path, err := findFormulaFile("my-review")
+	if err != nil {
+		t.Fatalf("findFormulaFile: %v", err)
+	}
+	if want := filepath.Join(rigFormulas, "my-review.formula.toml"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	dir, err := formulaLevelDir("rig:gastown")
+	if err != nil || dir != rigFormulas {
+		t.Errorf("formulaLevelDir(rig:gastown) = %q, %v; want %q", dir, err, rigFormulas)
+	}
+	for _, c := range resolveFormulaCandidates("my-review") {
+		if c.Status == candidateSelected && c.Source != "rig" {
+			t.Errorf("selected candidate from %q, want rig", c.Source)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// formulaCreateInteractive is the --interactive flag for gt formula create.
+var formulaCreateInteractive bool
+
+func init() {
+	formulaCreateCmd.Flags().BoolVarP(&formulaCreateInteractive, "interactive", "i", false, "Build the formula by answering questions")
+}
+
+// formulaSpec is what the create wizard collects.
+type formulaSpec struct {
+	Name        string
+	Type        string // "convoy", "workflow", or "patrol"
+	Description string
+	Legs        []formulaLeg // convoy
+	Steps       []formula.Step
+	Vars        []wizardVar
+	Output      formulaOutput // convoy
+	Synthesis   bool          // convoy
+	Agent       string        // convoy; empty for the rig default
+}
+
+type wizardVar struct {
+	Name        string
+	Description string
+	Default     string // Empty means required
+}
+
+var wizardIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// formulaWizard asks the create questions on in and prompts on out.
+type formulaWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prompts for a line, returning def if the answer is empty.
+func (w *formulaWizard) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	line, _ := w.in.ReadString('\n')
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+	return def
+}
+
+// askChoice prompts until the answer is one of choices.
+func (w *formulaWizard) askChoice(question string, choices []string, def string) (string, error) {
+	for {
+		answer := w.ask(fmt.Sprintf("%s (%s)", question, strings.Join(choices, ", ")), def)
+		for _, c := range choices {
+			if answer == c {
+				return answer, nil
+			}
+		}
+		if _, err := w.in.Peek(1); err != nil {
+			return "", fmt.Errorf("%s: no valid answer given", question)
+		}
+		fmt.Fprintf(w.out, "  Please answer one of: %s\n", strings.Join(choices, ", "))
+	}
+}
+
+func (w *formulaWizard) askYesNo(question string, def bool) bool {
+	d := "y/N"
+	if def {
+		d = "Y/n"
+	}
+	answer := strings.ToLower(w.ask(question, d))
+	if answer == strings.ToLower(d) {
+		return def
+	}
+	return answer == "y" || answer == "yes"
+}
+
+// askID prompts for a leg, step, or variable name, which must be usable as
+// a bare TOML key. An empty answer is returned as is.
+func (w *formulaWizard) askID(question string) string {
+	for {
+		id := w.ask(question, "")
+		if id == "" || wizardIDPattern.MatchString(id) {
+			return id
+		}
+		fmt.Fprintln(w.out, "  Use letters, digits, '-' and '_' only.")
+	}
+}
+
+// askList prompts for a comma-separated list.
+func (w *formulaWizard) askList(question, def string) []string {
+	var items []string
+	for _, item := range strings.Split(w.ask(question, def), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// run asks every question and returns the resulting spec.
+func (w *formulaWizard) run(name string) (*formulaSpec, error) {
+	spec := &formulaSpec{Name: name}
+	title := cases.Title(language.English).String(strings.ReplaceAll(name, "-", " "))
+
+	var err error
+	if spec.Type, err = w.askChoice("Type", []string{"convoy", "workflow", "patrol"}, "convoy"); err != nil {
+		return nil, err
+	}
+	spec.Description = w.ask("Description", title+" "+spec.Type)
+
+	if spec.Type == "convoy" {
+		fmt.Fprintln(w.out, "\nLegs run in parallel, each as its own polecat. Empty id to finish.")
+		for {
+			id := w.askID(fmt.Sprintf("Leg %d id", len(spec.Legs)+1))
+			if id == "" {
+				if len(spec.Legs) == 0 {
+					if _, err := w.in.Peek(1); err != nil {
+						return nil, fmt.Errorf("a convoy formula needs at least one leg")
+					}
+					fmt.Fprintln(w.out, "  A convoy needs at least one leg.")
+					continue
+				}
+				break
+			}
+			leg := formulaLeg{ID: id}
+			leg.Title = w.ask("  Title", cases.Title(language.English).String(strings.ReplaceAll(id, "-", " ")))
+			leg.Focus = w.ask("  Focus", leg.Title)
+			leg.Needs = w.askList("  Needs (legs to finish first, comma-separated)", "")
+			spec.Legs = append(spec.Legs, leg)
+		}
+	} else {
+		fmt.Fprintln(w.out, "\nSteps run in order of their needs. Empty id to finish.")
+		for {
+			id := w.askID(fmt.Sprintf("Step %d id", len(spec.Steps)+1))
+			if id == "" {
+				if len(spec.Steps) == 0 {
+					if _, err := w.in.Peek(1); err != nil {
+						return nil, fmt.Errorf("a %s formula needs at least one step", spec.Type)
+					}
+					fmt.Fprintf(w.out, "  A %s needs at least one step.\n", spec.Type)
+					continue
+				}
+				break
+			}
+			step := formula.Step{ID: id}
+			step.Title = w.ask("  Title", cases.Title(language.English).String(strings.ReplaceAll(id, "-", " ")))
+			prev := ""
+			if len(spec.Steps) > 0 {
+				prev = spec.Steps[len(spec.Steps)-1].ID
+			}
+			step.Needs = w.askList("  Needs (comma-separated)", prev)
+			spec.Steps = append(spec.Steps, step)
+		}
+	}
+
+	fmt.Fprintln(w.out, "\nVariables are passed when the formula runs. Empty name to finish.")
+	for {
+		name := w.askID("Variable name")
+		if name == "" {
+			break
+		}
+		v := wizardVar{Name: name}
+		v.Description = w.ask("  Description", "")
+		v.Default = w.ask("  Default (empty = required)", "")
+		spec.Vars = append(spec.Vars, v)
+	}
+
+	if spec.Type == "convoy" {
+		fmt.Fprintln(w.out, "\nOutput layout (templates; .review_id and .leg.id are available).")
+		spec.Output.Directory = w.ask("Output directory", ".reviews/{{.review_id}}")
+		spec.Output.LegPattern = w.ask("Leg output file", "{{.leg.id}}.md")
+		if spec.Synthesis = w.askYesNo("Combine leg outputs in a synthesis step?", true); spec.Synthesis {
+			spec.Output.Synthesis = w.ask("Synthesis output file", "synthesis.md")
+		}
+		spec.Agent = w.ask("\nAgent to run legs with (e.g. claude, codex; empty for the rig default)", "")
+	}
+
+	return spec, nil
+}
+
+// generateFormulaTOML renders a wizard spec as formula TOML.
+func generateFormulaTOML(spec *formulaSpec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Formula: %s\n# Type: %s\n# Created by: gt formula create --interactive\n\n", spec.Name, spec.Type)
+	fmt.Fprintf(&b, "description = %q\n", spec.Description)
+	fmt.Fprintf(&b, "formula = %q\n", spec.Name)
+	if spec.Type == "convoy" {
+		b.WriteString("type = \"convoy\"\n")
+	}
+	b.WriteString("version = 1\n")
+	if spec.Agent != "" {
+		fmt.Fprintf(&b, "agent = %q\n", spec.Agent)
+	}
+
+	if spec.Type != "convoy" {
+		for _, step := range spec.Steps {
+			fmt.Fprintf(&b, "\n[[steps]]\nid = %q\ntitle = %q\n", step.ID, step.Title)
+			if len(step.Needs) > 0 {
+				fmt.Fprintf(&b, "needs = %s\n", tomlStringArray(step.Needs))
+			}
+			fmt.Fprintf(&b, "description = \"\"\"\n%s.\n\"\"\"\n", step.Title)
+		}
+		if len(spec.Vars) > 0 {
+			b.WriteString("\n[vars]")
+			for _, v := range spec.Vars {
+				fmt.Fprintf(&b, "\n[vars.%s]\ndescription = %q\n", v.Name, v.Description)
+				if v.Default != "" {
+					fmt.Fprintf(&b, "default = %q\n", v.Default)
+				} else {
+					b.WriteString("required = true\n")
+				}
+			}
+		}
+		return b.String()
+	}
+
+	if len(spec.Vars) > 0 {
+		b.WriteString("\n[inputs]")
+		for _, v := range spec.Vars {
+			fmt.Fprintf(&b, "\n[inputs.%s]\ndescription = %q\ntype = \"string\"\n", v.Name, v.Description)
+			if v.Default != "" {
+				fmt.Fprintf(&b, "default = %q\n", v.Default)
+			} else {
+				b.WriteString("required = true\n")
+			}
+		}
+	}
+
+	b.WriteString(`
+[prompts]
+base = """
+You are one leg of the {{.formula_name}} convoy.
+
+## Your focus
+{{.leg.focus}}
+
+## Your task
+{{.leg.description}}
+
+Write your findings to: **{{.output_path}}**
+"""
+`)
+
+	fmt.Fprintf(&b, "\n[output]\ndirectory = %q\nleg_pattern = %q\n", spec.Output.Directory, spec.Output.LegPattern)
+	if spec.Synthesis {
+		fmt.Fprintf(&b, "synthesis = %q\n", spec.Output.Synthesis)
+	}
+
+	for _, leg := range spec.Legs {
+		fmt.Fprintf(&b, "\n[[legs]]\nid = %q\ntitle = %q\nfocus = %q\n", leg.ID, leg.Title, leg.Focus)
+		if len(leg.Needs) > 0 {
+			fmt.Fprintf(&b, "needs = %s\n", tomlStringArray(leg.Needs))
+		}
+		fmt.Fprintf(&b, "description = \"\"\"\nReview the target for: %s.\n\"\"\"\n", leg.Focus)
+	}
+
+	if spec.Synthesis {
+		ids := make([]string, 0, len(spec.Legs))
+		for _, leg := range spec.Legs {
+			ids = append(ids, leg.ID)
+		}
+		fmt.Fprintf(&b, `
+[synthesis]
+title = "Synthesis"
+description = """
+Combine the leg outputs in {{.output.directory}}/ into one report at
+{{.output.directory}}/{{.output.synthesis}}. Deduplicate findings and
+order them by impact.
+"""
+depends_on = %s
+`, tomlStringArray(ids))
+	}
+	return b.String()
+}
+
+func tomlStringArray(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, fmt.Sprintf("%q", item))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// runFormulaCreateWizard builds a formula from the wizard's answers,
+// validates it, writes it to filename, and offers to open it in $EDITOR.
+func runFormulaCreateWizard(name, filename string) error {
+	w := &formulaWizard{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	spec, err := w.run(name)
+	if err != nil {
+		return err
+	}
+
+	content := generateFormulaTOML(spec)
+	if _, err := formula.Parse([]byte(content)); err != nil {
+		return fmt.Errorf("generated formula is invalid: %w", err)
+	}
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		return fmt.Errorf("writing formula file: %w", err)
+	}
+	fmt.Printf("\n%s Created formula: %s\n", style.Bold.Render("✓"), filename)
+
+	if editor := os.Getenv("EDITOR"); editor != "" && w.askYesNo(fmt.Sprintf("Open it in %s?", editor), false) {
+		editCmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", filename)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+		if err := editCmd.Run(); err != nil {
+			return fmt.Errorf("running editor: %w", err)
+		}
+		if _, err := formula.ParseFile(filename, formulaIncludeDirs()...); err != nil {
+			fmt.Printf("%s Formula no longer validates after editing: %v\n", style.Warning.Render("⚠"), err)
+		}
+	}
+
+	fmt.Printf("\nNext steps:\n")
+	fmt.Printf("  View it: gt formula show %s\n", name)
+	fmt.Printf("  Run it:  gt formula run %s --dry-run\n", name)
+	return nil
+}
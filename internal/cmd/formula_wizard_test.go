@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func runWizard(t *testing.T, name, answers string) (*formulaSpec, error) {
+	t.Helper()
+	w := &formulaWizard{in: bufio.NewReader(strings.NewReader(answers)), out: io.Discard}
+	return w.run(name)
+}
+
+func TestFormulaWizardConvoy(t *testing.T) {
+	answers := strings.Join([]string{
+		"",                    // type: convoy
+		"",                    // description
+		"api-design",          // leg 1
+		"",                    // title
+		"API shape",           // focus
+		"",                    // needs
+		"bad id!",             // rejected leg id
+		"impl",                // leg 2
+		"Implementation",      // title
+		"",                    // focus
+		"api-design",          // needs
+		"",                    // done with legs
+		"pr",                  // variable
+		"PR to review",        // description
+		"",                    // required
+		"",                    // done with variables
+		"",                    // output directory
+		".out/{{.leg.id}}.md", // leg pattern
+		"",                    // synthesis: yes
+		"",                    // synthesis file
+		"codex",               // agent
+	}, "\n") + "\n"
+
+	spec, err := runWizard(t, "my-review", answers)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if spec.Type != "convoy" || len(spec.Legs) != 2 || spec.Legs[1].Needs[0] != "api-design" || spec.Agent != "codex" {
+		t.Errorf("spec = %+v", spec)
+	}
+
+	content := generateFormulaTOML(spec)
+	f, err := formula.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("generated formula does not parse: %v\n%s", err, content)
+	}
+	if f.Type != formula.TypeConvoy || f.Agent != "codex" || f.Synthesis == nil || !f.Inputs["pr"].Required {
+		t.Errorf("parsed formula = %+v", f)
+	}
+	if f.Output.LegPattern != ".out/{{.leg.id}}.md" || f.Output.Synthesis != "synthesis.md" {
+		t.Errorf("output = %+v", f.Output)
+	}
+
+	path := filepath.Join(t.TempDir(), "my-review.formula.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parseFormulaFile(path)
+	if err != nil {
+		t.Fatalf("parseFormulaFile() error = %v", err)
+	}
+	if data.Agent != "codex" || len(data.Legs) != 2 || data.Legs[0].Focus != "API shape" || data.Prompts["base"] == "" {
+		t.Errorf("formulaData = %+v", data)
+	}
+}
+
+func TestFormulaWizardWorkflow(t *testing.T) {
+	answers := strings.Join([]string{
+		"workflow",
+		"Nightly cleanup",
+		"",       // no steps yet: asked again
+		"scan",   // step 1
+		"",       // title
+		"",       // needs
+		"fix",    // step 2
+		"",       // title
+		"",       // needs: defaults to scan
+		"",       // done with steps
+		"target", // variable
+		"",       // description
+		"main",   // default
+		"",       // done with variables
+	}, "\n") + "\n"
+
+	spec, err := runWizard(t, "nightly", answers)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	content := generateFormulaTOML(spec)
+	f, err := formula.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("generated formula does not parse: %v\n%s", err, content)
+	}
+	if f.Type != formula.TypeWorkflow || len(f.Steps) != 2 || f.Steps[1].Needs[0] != "scan" {
+		t.Errorf("parsed formula = %+v", f)
+	}
+	if f.Vars["target"].Default != "main" {
+		t.Errorf("vars = %+v", f.Vars)
+	}
+}
+
+func TestFormulaWizardEOF(t *testing.T) {
+	if _, err := runWizard(t, "x", "convoy\n\n"); err == nil || !strings.Contains(err.Error(), "at least one leg") {
+		t.Errorf("run() error = %v, want at least one leg", err)
+	}
+	if _, err := runWizard(t, "x", "sequence\n"); err == nil {
+		t.Error("run() accepted an unknown type at EOF")
+	}
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// legWorkspace is the isolated worktree and branch a convoy leg works in.
+type legWorkspace struct {
+	Path   string
+	Branch string
+}
+
+// resolveLegWorkspace renders a leg's workdir and branch templates.
+// Returns nil if the leg doesn't declare either. A branch without a workdir
+// gets a worktree under legs/<review-id>/<leg-id>; a workdir without a
+// branch gets review/<review-id>/<leg-id>. The workdir must be inside the
+// rig.
+func resolveLegWorkspace(leg formulaLeg, legCtx map[string]interface{}, rigPath string) (*legWorkspace, error) {
+	if leg.Workdir == "" && leg.Branch == "" {
+		return nil, nil
+	}
+	reviewID, _ := legCtx["review_id"].(string)
+
+	workdir := filepath.Join("legs", reviewID, leg.ID)
+	if leg.Workdir != "" {
+		rendered, err := renderTemplate(leg.Workdir, legCtx)
+		if err != nil {
+			return nil, fmt.Errorf("rendering workdir: %w", err)
+		}
+		workdir = rendered
+	}
+	if !filepath.IsAbs(workdir) {
+		workdir = filepath.Join(rigPath, workdir)
+	}
+
+	branch := fmt.Sprintf("review/%s/%s", reviewID, leg.ID)
+	if leg.Branch != "" {
+		rendered, err := renderTemplate(leg.Branch, legCtx)
+		if err != nil {
+			return nil, fmt.Errorf("rendering branch: %w", err)
+		}
+		branch = rendered
+	}
+
+	ws := &legWorkspace{Path: filepath.Clean(workdir), Branch: branch}
+	if err := checkLegWorkdir(rigPath, ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+// checkLegWorkdir rejects a leg workdir that isn't strictly inside the rig.
+func checkLegWorkdir(rigPath string, ws *legWorkspace) error {
+	rel, err := filepath.Rel(filepath.Clean(rigPath), ws.Path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("workdir %s is outside rig %s", ws.Path, rigPath)
+	}
+	return nil
+}
+
+// checkLegWorkspaces rejects a run whose legs share a branch or a workdir.
+// A git branch can only be checked out in one worktree, so a shared one
+// would leave all but one leg without a workspace.
+func checkLegWorkspaces(rigPath string, legIDs []string, workspaces map[string]*legWorkspace) error {
+	branches := make(map[string]string) // branch -> leg ID
+	paths := make(map[string]string)    // workdir -> leg ID
+	for _, id := range legIDs {
+		ws := workspaces[id]
+		if ws == nil {
+			continue
+		}
+		if err := checkLegWorkdir(rigPath, ws); err != nil {
+			return fmt.Errorf("leg %s: %w", id, err)
+		}
+		if other, ok := branches[ws.Branch]; ok {
+			return fmt.Errorf("legs %s and %s both use branch %s; give each leg its own branch (e.g. with {{ .leg.id }})", other, id, ws.Branch)
+		}
+		if other, ok := paths[ws.Path]; ok {
+			return fmt.Errorf("legs %s and %s both use workdir %s; give each leg its own workdir", other, id, ws.Path)
+		}
+		branches[ws.Branch], paths[ws.Path] = id, id
+	}
+	return nil
+}
+
+// prepareLegWorkspaces checks and prepares the worktrees of a run's legs,
+// in legIDs order. Any failure fails the whole run.
+func prepareLegWorkspaces(rigPath string, legIDs []string, workspaces map[string]*legWorkspace) error {
+	if err := checkLegWorkspaces(rigPath, legIDs, workspaces); err != nil {
+		return err
+	}
+	for _, id := range legIDs {
+		ws := workspaces[id]
+		if ws == nil {
+			continue
+		}
+		if err := prepareLegWorktree(rigPath, ws); err != nil {
+			return fmt.Errorf("preparing workspace for leg %s: %w", id, err)
+		}
+		fmt.Printf("  %s Prepared worktree: %s (%s)\n", style.Dim.Render("⎇"), ws.Path, ws.Branch)
+	}
+	return nil
+}
+
+// prepareLegWorktree makes ws.Path a clean worktree on ws.Branch. A new
+// branch starts from the rig's default branch; an existing branch is
+// checked out as it is, never reset, so committed leg work survives a
+// rerun. An existing checkout is reused only if it has no uncommitted
+// changes, so concurrent legs never share dirty state.
+func prepareLegWorktree(rigPath string, ws *legWorkspace) error {
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	startPoint := "origin/" + defaultBranch
+
+	if _, err := os.Stat(filepath.Join(ws.Path, ".git")); err == nil {
+		g := git.NewGit(ws.Path)
+		dirty, err := g.HasUncommittedChanges()
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", ws.Path, err)
+		}
+		if dirty {
+			return fmt.Errorf("%s has uncommitted changes; commit or clean it before reusing it for a leg", ws.Path)
+		}
+		if current, err := g.CurrentBranch(); err == nil && current == ws.Branch {
+			return nil
+		}
+		exists, err := g.BranchExists(ws.Branch)
+		if err != nil {
+			return fmt.Errorf("checking branch %s: %w", ws.Branch, err)
+		}
+		if !exists {
+			if err := g.Fetch("origin"); err != nil {
+				fmt.Printf("Warning: could not fetch origin in %s: %v\n", ws.Path, err)
+			}
+			if err := g.CreateBranchFrom(ws.Branch, startPoint); err != nil {
+				return fmt.Errorf("creating branch %s: %w", ws.Branch, err)
+			}
+		}
+		return g.Checkout(ws.Branch)
+	} else if _, err := os.Stat(ws.Path); err == nil {
+		return fmt.Errorf("%s exists and is not a git checkout", ws.Path)
+	}
+
+	repoGit, err := legRepoBase(rigPath)
+	if err != nil {
+		return err
+	}
+	if err := repoGit.Fetch("origin"); err != nil {
+		// Non-fatal - proceed with potentially stale code
+		fmt.Printf("Warning: could not fetch origin: %v\n", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(ws.Path), 0755); err != nil {
+		return fmt.Errorf("creating worktree parent: %w", err)
+	}
+
+	exists, err := repoGit.BranchExists(ws.Branch)
+	if err != nil {
+		return fmt.Errorf("checking branch %s: %w", ws.Branch, err)
+	}
+	if exists {
+		return repoGit.WorktreeAddExisting(ws.Path, ws.Branch)
+	}
+	return repoGit.WorktreeAddFromRef(ws.Path, ws.Branch, startPoint)
+}
+
+// legRepoBase returns the repository leg worktrees are created from: the
+// shared bare repo (.repo.git) if present, otherwise mayor/rig.
+func legRepoBase(rigPath string) (*git.Git, error) {
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if info, err := os.Stat(bareRepoPath); err == nil && info.IsDir() {
+		return git.NewGitWithDir(bareRepoPath, ""), nil
+	}
+	mayorPath := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(mayorPath); err != nil {
+		return nil, fmt.Errorf("no repo base found in %s (neither .repo.git nor mayor/rig exists)", rigPath)
+	}
+	return git.NewGit(mayorPath), nil
+}
+
+// legWorkspaceNote tells the leg's agent where to work.
+func legWorkspaceNote(ws *legWorkspace) string {
+	return fmt.Sprintf("Work in %s on branch %s. It is a dedicated worktree for this leg; make and commit changes there, not in your own clone.", ws.Path, ws.Branch)
+}
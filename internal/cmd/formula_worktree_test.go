@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveLegWorkspace(t *testing.T) {
+	ctx := map[string]interface{}{"review_id": "r1", "leg": map[string]interface{}{"id": "sec"}}
+
+	if ws, err := resolveLegWorkspace(formulaLeg{ID: "sec"}, ctx, "/rig"); err != nil || ws != nil {
+		t.Fatalf("leg without workdir/branch = %+v, %v; want nil", ws, err)
+	}
+
+	ws, err := resolveLegWorkspace(formulaLeg{ID: "sec", Workdir: "crew/{{ .leg.id }}", Branch: "review/{{ .review_id }}"}, ctx, "/rig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Path != filepath.Join("/rig", "crew", "sec") || ws.Branch != "review/r1" {
+		t.Errorf("workspace = %+v, want /rig/crew/sec on review/r1", ws)
+	}
+
+	ws, err = resolveLegWorkspace(formulaLeg{ID: "sec", Branch: "x"}, ctx, "/rig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Path != filepath.Join("/rig", "legs", "r1", "sec") {
+		t.Errorf("default workdir = %s, want /rig/legs/r1/sec", ws.Path)
+	}
+
+	for _, workdir := range []string{"../other", "/elsewhere/sec", "."} {
+		if _, err := resolveLegWorkspace(formulaLeg{ID: "sec", Workdir: workdir}, ctx, "/rig"); err == nil || !strings.Contains(err.Error(), "outside rig") {
+			t.Errorf("workdir %q: err = %v, want outside rig error", workdir, err)
+		}
+	}
+}
+
+func TestCheckLegWorkspaces(t *testing.T) {
+	workspaces := map[string]*legWorkspace{
+		"a": {Path: "/rig/crew/a", Branch: "review/r1"},
+		"b": {Path: "/rig/crew/b", Branch: "review/r1"},
+	}
+	if err := checkLegWorkspaces("/rig", []string{"a", "b"}, workspaces); err == nil || !strings.Contains(err.Error(), "both use branch review/r1") {
+		t.Errorf("shared branch: err = %v, want collision error", err)
+	}
+
+	workspaces["b"] = &legWorkspace{Path: "/rig/crew/a", Branch: "review/r1/b"}
+	if err := checkLegWorkspaces("/rig", []string{"a", "b"}, workspaces); err == nil || !strings.Contains(err.Error(), "both use workdir") {
+		t.Errorf("shared workdir: err = %v, want collision error", err)
+	}
+
+	workspaces["b"] = &legWorkspace{Path: "/rig/crew/b", Branch: "review/r1/b"}
+	if err := checkLegWorkspaces("/rig", []string{"a", "b"}, workspaces); err != nil {
+		t.Errorf("distinct workspaces: %v", err)
+	}
+}
+
+func TestPrepareLegWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmp := t.TempDir()
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	// origin with one commit on main, cloned into <rig>/mayor/rig
+	origin := filepath.Join(tmp, "origin")
+	if err := os.MkdirAll(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(origin, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(origin, "README"), []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(origin, "add", ".")
+	runGit(origin, "commit", "-q", "-m", "init")
+
+	rigPath := filepath.Join(tmp, "rig")
+	if err := os.MkdirAll(filepath.Join(rigPath, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(filepath.Join(rigPath, "mayor"), "clone", "-q", origin, "rig")
+
+	ws := &legWorkspace{Path: filepath.Join(rigPath, "legs", "r1", "a"), Branch: "review/r1/a"}
+	if err := prepareLegWorktree(rigPath, ws); err != nil {
+		t.Fatalf("prepareLegWorktree() = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ws.Path, "README")); err != nil {
+		t.Fatalf("worktree not checked out: %v", err)
+	}
+
+	// A clean existing worktree is reused without resetting committed work.
+	if err := os.WriteFile(filepath.Join(ws.Path, "work"), []byte("done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(ws.Path, "add", "work")
+	runGit(ws.Path, "commit", "-q", "-m", "leg work")
+	if err := prepareLegWorktree(rigPath, ws); err != nil {
+		t.Fatalf("reusing clean worktree: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ws.Path, "work")); err != nil {
+		t.Errorf("reuse discarded committed work: %v", err)
+	}
+
+	// A worktree reused for another existing branch checks it out as is.
+	runGit(ws.Path, "branch", "review/r2/a")
+	runGit(ws.Path, "checkout", "-q", "-b", "scratch", "origin/main")
+	ws2 := &legWorkspace{Path: ws.Path, Branch: "review/r2/a"}
+	if err := prepareLegWorktree(rigPath, ws2); err != nil {
+		t.Fatalf("reusing worktree for existing branch: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ws.Path, "work")); err != nil {
+		t.Errorf("existing branch was reset: %v", err)
+	}
+
+	// A dirty worktree is refused.
+	if err := os.WriteFile(filepath.Join(ws.Path, "README"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := prepareLegWorktree(rigPath, ws); err == nil || !strings.Contains(err.Error(), "uncommitted") {
+		t.Errorf("dirty worktree: err = %v, want uncommitted changes error", err)
+	}
+}
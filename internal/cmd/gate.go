@@ -7,6 +7,7 @@ import (
 	"os/exec"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -86,7 +87,7 @@ func runGateWake(cmd *cobra.Command, args []string) error {
 
 	// Get gate info
 	gateCheck := exec.Command("bd", "gate", "show", gateID, "--json")
-	gateOutput, err := gateCheck.Output()
+	gateOutput, err := cmdtrace.Output(gateCheck)
 	if err != nil {
 		return fmt.Errorf("gate '%s' not found or not accessible", gateID)
 	}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/gc"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// GC flags
+var (
+	gcDryRun bool
+	gcJSON   bool
+)
+
+var gcCmd = &cobra.Command{
+	Use:     "gc",
+	GroupID: GroupDiag,
+	Short:   "Remove runtime debris and report reclaimed space",
+	Long: `Remove runtime debris that long-lived towns accumulate:
+
+  cache      Files under .runtime/cache/ not written in 7 days (rebuilt on demand)
+  worktree   Worktree metadata whose checkout is gone, and polecat or pooled
+             checkouts the rig's repository no longer knows
+  session    State of exited process-backend sessions; their logs after 14 days
+  review     Review output directories (.reviews/<id>) unchanged for 30 days
+  wisp       Wisps closed more than 24 hours ago
+
+Retention periods are set in the town's settings/config.json:
+
+  "gc": {"cache_days": 7, "session_log_days": 14, "review_days": 30, "wisp_hours": 24}
+
+Examples:
+  gt gc --dry-run    # List what would be removed
+  gt gc              # Remove it and report reclaimed space`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+func init() {
+	gcCmd.Flags().BoolVarP(&gcDryRun, "dry-run", "n", false, "List debris without removing it")
+	gcCmd.Flags().BoolVar(&gcJSON, "json", false, "Output in JSON format")
+	rootCmd.AddCommand(gcCmd)
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var rigs []string
+	if rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON)); err == nil {
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+	}
+	var policy *config.GCConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		policy = settings.GC
+	}
+
+	collector := gc.New(townRoot, rigs, policy)
+	items := collector.Scan()
+	for _, w := range collector.Warnings {
+		fmt.Fprintf(os.Stderr, "%s %s\n", style.Warning.Render("!"), w)
+	}
+
+	var reclaimed int64
+	var removeErr error
+	if !gcDryRun {
+		reclaimed, removeErr = collector.Remove(items)
+	}
+
+	if gcJSON {
+		out := struct {
+			DryRun    bool      `json:"dry_run"`
+			Items     []gc.Item `json:"items"`
+			Bytes     int64     `json:"bytes"`
+			Reclaimed int64     `json:"reclaimed"`
+		}{gcDryRun, items, gc.Total(items), reclaimed}
+		if out.Items == nil {
+			out.Items = []gc.Item{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+		return removeErr
+	}
+
+	if len(items) == 0 {
+		fmt.Printf("%s Nothing to collect\n", style.Success.Render("✓"))
+		return nil
+	}
+	printGCSummary(townRoot, items)
+	if gcDryRun {
+		fmt.Printf("\n%s Dry run: %s reclaimable; run without --dry-run to remove\n",
+			style.Dim.Render("ℹ"), formatBytes(gc.Total(items)))
+		return nil
+	}
+	if removeErr != nil {
+		fmt.Printf("\n%s Reclaimed %s; some debris could not be removed\n", style.Warning.Render("⚠"), formatBytes(reclaimed))
+		return removeErr
+	}
+	fmt.Printf("\n%s Reclaimed %s\n", style.Success.Render("✓"), formatBytes(reclaimed))
+	return nil
+}
+
+// printGCSummary prints debris by kind, listing each item on a dry run.
+func printGCSummary(townRoot string, items []gc.Item) {
+	byKind := make(map[string][]gc.Item)
+	for _, it := range items {
+		byKind[it.Kind] = append(byKind[it.Kind], it)
+	}
+	kinds := make([]string, 0, len(byKind))
+	for kind := range byKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		group := byKind[kind]
+		fmt.Printf("%s %d item(s), %s\n", style.Bold.Render(kind), len(group), formatBytes(gc.Total(group)))
+		if !gcDryRun {
+			continue
+		}
+		for _, it := range group {
+			target := it.ID
+			if target == "" {
+				target, _ = filepath.Rel(townRoot, it.Path)
+			}
+			fmt.Printf("  %s %s\n", target, style.Dim.Render("("+it.Reason+")"))
+		}
+	}
+}
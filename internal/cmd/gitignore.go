@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/gitignore"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	gitignoreSyncDryRun bool
+	gitignoreSyncRig    string
+)
+
+var gitignoreCmd = &cobra.Command{
+	Use:     "gitignore",
+	GroupID: GroupWorkspace,
+	Short:   "Manage Gas Town patterns in .gitignore files",
+	RunE:    requireSubcommand,
+	Long: `Manage the gt-owned block of patterns in .gitignore files.
+
+Gas Town keeps its required patterns (.runtime/, .reviews/, .beads-wisp/)
+between "# gastown:begin" and "# gastown:end" markers. Lines outside the
+markers are never touched.
+
+Managed locations:
+  - Town root .gitignore
+  - Each rig's canonical clone (<rig>/mayor/rig)
+  - Each crew member's clone (<rig>/crew/<name>)
+
+Commands:
+  sync    Insert or update the managed block everywhere`,
+}
+
+var gitignoreSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Write the managed block into town, rig, and crew .gitignore files",
+	Long: `Insert or update the gt-managed block in every managed .gitignore.
+
+This is the same fix applied by 'gt doctor --fix' for the runtime-gitignore
+check, but runs unconditionally so pattern changes from gt upgrades are
+propagated even when existing files already ignore .runtime/.
+
+Examples:
+  gt gitignore sync              # Sync all locations
+  gt gitignore sync --rig=beads  # Only the town root and one rig
+  gt gitignore sync --dry-run    # Show what would change`,
+	RunE: runGitignoreSync,
+}
+
+func init() {
+	gitignoreSyncCmd.Flags().BoolVar(&gitignoreSyncDryRun, "dry-run", false, "Show what would change without writing")
+	gitignoreSyncCmd.Flags().StringVar(&gitignoreSyncRig, "rig", "", "Limit to a single rig")
+
+	gitignoreCmd.AddCommand(gitignoreSyncCmd)
+	rootCmd.AddCommand(gitignoreCmd)
+}
+
+func runGitignoreSync(cmd *cobra.Command, args []string) error {
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+
+	var rigPaths []string
+	for _, r := range rigs {
+		if gitignoreSyncRig != "" && r.Name != gitignoreSyncRig {
+			continue
+		}
+		rigPaths = append(rigPaths, r.Path)
+	}
+	if gitignoreSyncRig != "" && len(rigPaths) == 0 {
+		return rigNotFoundError(gitignoreSyncRig, rigNames(rigs))
+	}
+
+	changed := 0
+	for _, target := range gitignore.Targets(townRoot, rigPaths) {
+		label := "town"
+		if rel, err := filepath.Rel(townRoot, target.Dir); err == nil && rel != "." {
+			label = rel
+		}
+
+		if gitignoreSyncDryRun {
+			if missing := gitignore.Missing(target.Path(), gitignore.RequiredPatterns); len(missing) > 0 || !gitignore.HasBlock(target.Path()) {
+				fmt.Printf("  %s %s/.gitignore", style.Dim.Render("~"), label)
+				if len(missing) > 0 {
+					fmt.Printf(" (missing %s)", strings.Join(missing, ", "))
+				}
+				fmt.Println()
+				changed++
+			}
+			continue
+		}
+
+		updated, err := gitignore.Sync(target.Path(), gitignore.RequiredPatterns)
+		if err != nil {
+			fmt.Printf("  %s %s/.gitignore: %v\n", style.Error.Render("✗"), label, err)
+			continue
+		}
+		if updated {
+			fmt.Printf("  %s %s/.gitignore\n", style.Success.Render("✓"), label)
+			changed++
+		}
+	}
+
+	switch {
+	case changed == 0:
+		fmt.Printf("%s All .gitignore files up to date\n", style.Success.Render("✓"))
+	case gitignoreSyncDryRun:
+		fmt.Printf("\n%s %d file(s) would be updated\n", style.Dim.Render("[dry-run]"), changed)
+	default:
+		fmt.Printf("\n%s Updated %d file(s)\n", style.Bold.Render("✓"), changed)
+	}
+
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/offline"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -234,6 +236,10 @@ func initGitRepo(path string) error {
 }
 
 func createGitHubRepo(hqRoot, repo string, private bool) error {
+	if err := offline.Check("create a GitHub repository"); err != nil {
+		return err
+	}
+
 	// Check if gh CLI is available
 	if _, err := exec.LookPath("gh"); err != nil {
 		return fmt.Errorf("GitHub CLI (gh) not found. Install it with: brew install gh")
@@ -271,7 +277,7 @@ func createGitHubRepo(hqRoot, repo string, private bool) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return fmt.Errorf("gh repo create failed: %w", err)
 	}
 	fmt.Printf("   ✓ Created and pushed to GitHub: %s (%s)\n", repo, visibility)
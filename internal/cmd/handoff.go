@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
@@ -79,7 +80,7 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		doneCmd := exec.Command("gt", "done", "--exit", "DEFERRED")
 		doneCmd.Stdout = os.Stdout
 		doneCmd.Stderr = os.Stderr
-		return doneCmd.Run()
+		return cmdtrace.Run(doneCmd)
 	}
 
 	// If --collect flag is set, auto-collect state into the message
@@ -304,6 +305,7 @@ func resolveRoleToSession(role string) (string, error) {
 // resolvePathToSession converts a path like "<rig>/crew/<name>" to a session name.
 // Supported formats:
 //   - <rig>/crew/<name> -> gt-<rig>-crew-<name>
+//   - <rig>/crew -> first idle crew worker (running session, clean tree)
 //   - <rig>/witness -> gt-<rig>-witness
 //   - <rig>/refinery -> gt-<rig>-refinery
 //   - <rig>/polecats/<name> -> gt-<rig>-<name> (explicit polecat)
@@ -338,8 +340,16 @@ func resolvePathToSession(path string) (string, error) {
 		case "refinery":
 			return fmt.Sprintf("gt-%s-refinery", rig), nil
 		case "crew":
-			// Just "<rig>/crew" without a name - need more info
-			return "", fmt.Errorf("crew path requires name: %s/crew/<name>", rig)
+			// Just "<rig>/crew" without a name - dispatch to an idle crew worker
+			crewMgr, _, err := getCrewManager(rig)
+			if err != nil {
+				return "", err
+			}
+			worker, err := crewMgr.PickIdle()
+			if err != nil {
+				return "", fmt.Errorf("no idle crew in %s (specify %s/crew/<name>): %w", rig, rig, err)
+			}
+			return crewMgr.SessionName(worker.Name), nil
 		case "polecats":
 			// Just "<rig>/polecats" without a name - need more info
 			return "", fmt.Errorf("polecats path requires name: %s/polecats/<name>", rig)
@@ -708,7 +718,7 @@ func sendHandoffMail(subject, message string) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return "", fmt.Errorf("creating handoff mail: %s", errMsg)
@@ -727,7 +737,7 @@ func sendHandoffMail(subject, message string) (string, error) {
 	hookCmd.Env = append(os.Environ(), "BEADS_DIR="+filepath.Join(townRoot, ".beads"))
 	hookCmd.Stderr = os.Stderr
 
-	if err := hookCmd.Run(); err != nil {
+	if err := cmdtrace.Run(hookCmd); err != nil {
 		// Non-fatal: mail was created, just couldn't hook
 		style.PrintWarning("created mail %s but failed to auto-hook: %v", beadID, err)
 		return beadID, nil
@@ -774,7 +784,7 @@ func looksLikeBeadID(s string) bool {
 func hookBeadForHandoff(beadID string) error {
 	// Verify the bead exists first
 	verifyCmd := exec.Command("bd", "show", beadID, "--json")
-	if err := verifyCmd.Run(); err != nil {
+	if err := cmdtrace.Run(verifyCmd); err != nil {
 		return fmt.Errorf("bead '%s' not found", beadID)
 	}
 
@@ -794,7 +804,7 @@ func hookBeadForHandoff(beadID string) error {
 	// Pin the bead using bd update (discovery-based approach)
 	pinCmd := exec.Command("bd", "update", beadID, "--status=pinned", "--assignee="+agentID)
 	pinCmd.Stderr = os.Stderr
-	if err := pinCmd.Run(); err != nil {
+	if err := cmdtrace.Run(pinCmd); err != nil {
 		return fmt.Errorf("pinning bead: %w", err)
 	}
 
@@ -808,7 +818,7 @@ func collectHandoffState() string {
 	var parts []string
 
 	// Get hooked work
-	hookOutput, err := exec.Command("gt", "hook").Output()
+	hookOutput, err := cmdtrace.Output(exec.Command("gt", "hook"))
 	if err == nil {
 		hookStr := strings.TrimSpace(string(hookOutput))
 		if hookStr != "" && !strings.Contains(hookStr, "Nothing on hook") {
@@ -817,7 +827,7 @@ func collectHandoffState() string {
 	}
 
 	// Get inbox summary (first few messages)
-	inboxOutput, err := exec.Command("gt", "mail", "inbox").Output()
+	inboxOutput, err := cmdtrace.Output(exec.Command("gt", "mail", "inbox"))
 	if err == nil {
 		inboxStr := strings.TrimSpace(string(inboxOutput))
 		if inboxStr != "" && !strings.Contains(inboxStr, "Inbox empty") {
@@ -831,7 +841,7 @@ func collectHandoffState() string {
 	}
 
 	// Get ready beads
-	readyOutput, err := exec.Command("bd", "ready").Output()
+	readyOutput, err := cmdtrace.Output(exec.Command("bd", "ready"))
 	if err == nil {
 		readyStr := strings.TrimSpace(string(readyOutput))
 		if readyStr != "" && !strings.Contains(readyStr, "No issues ready") {
@@ -845,7 +855,7 @@ func collectHandoffState() string {
 	}
 
 	// Get in-progress beads
-	inProgressOutput, err := exec.Command("bd", "list", "--status=in_progress").Output()
+	inProgressOutput, err := cmdtrace.Output(exec.Command("bd", "list", "--status=in_progress"))
 	if err == nil {
 		ipStr := strings.TrimSpace(string(inProgressOutput))
 		if ipStr != "" && !strings.Contains(ipStr, "No issues") {
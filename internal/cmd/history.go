@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/history"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// History command flags
+var (
+	historyFailed bool
+	historySince  string
+	historyRig    string
+	historyLimit  int
+	historyJSON   bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:     "history",
+	GroupID: GroupDiag,
+	Short:   "Show recorded gt command invocations",
+	Long: `Show the gt commands run in this town.
+
+Every gt invocation inside a town is recorded in .runtime/history.jsonl
+with its arguments, working directory, rig, user, agent role, duration,
+and exit status. The log rotates at 5 MB, keeping three old files.
+
+Set GT_NO_HISTORY=1 to skip recording.
+
+Examples:
+  gt history                       # Most recent 50 commands
+  gt history --failed              # Only commands that exited non-zero
+  gt history --since 24h           # Commands from the last day
+  gt history --rig gastown --since 7d
+  gt history --json`,
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().BoolVar(&historyFailed, "failed", false, "Only show commands that exited non-zero")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Show commands since duration (e.g., 1h, 24h, 7d)")
+	historyCmd.Flags().StringVar(&historyRig, "rig", "", "Only show commands run against this rig")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 50, "Maximum number of entries to show (0 for all)")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(historyCmd)
+}
+
+// historySkipCommands are not recorded: shell completion runs on every
+// keypress, and help/version change nothing.
+var historySkipCommands = map[string]bool{
+	"__complete":       true,
+	"__completeNoDesc": true,
+	"completion":       true,
+	"help":             true,
+	"history":          true,
+	"version":          true,
+}
+
+// recordHistory appends the finished invocation to the town's history log.
+// Failures are ignored: history must never break a command.
+func recordHistory(start time.Time, exitCode int, runErr error) {
+	if os.Getenv("GT_NO_HISTORY") != "" {
+		return
+	}
+	args := os.Args[1:]
+	if len(args) > 0 && historySkipCommands[args[0]] {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+
+	cwd, _ := os.Getwd()
+	entry := history.Entry{
+		Time:       start.UTC(),
+		Command:    rootCmd.Name(),
+		Args:       args,
+		Cwd:        cwd,
+		Town:       townRoot,
+		Rig:        historyRigFor(townRoot, cwd),
+		Role:       os.Getenv("GT_ROLE"),
+		DurationMs: time.Since(start).Milliseconds(),
+		ExitCode:   exitCode,
+
+		CacheHits:   historyAgentCache.Hits,
+		CacheMisses: historyAgentCache.Misses,
+
+		Formula: historyFormula,
+		Env:     historyRunEnv,
+		Labels:  historyRunLabels,
+
+		LegAttempts: historyLegAttempts,
+	}
+	if c, _, err := rootCmd.Find(args); err == nil && c != nil {
+		entry.Command = c.CommandPath()
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	if runErr != nil && exitCode != 0 {
+		if _, silent := IsSilentExit(runErr); !silent {
+			entry.Error = runErr.Error()
+		}
+	}
+	_ = history.Append(townRoot, entry)
+}
+
+// historyRigFor returns the rig a command was run against: GT_RIG for
+// agents, otherwise the rig directory containing cwd.
+func historyRigFor(townRoot, cwd string) string {
+	if rig := os.Getenv("GT_RIG"); rig != "" {
+		return rig
+	}
+	rel, err := filepath.Rel(townRoot, cwd)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	first := strings.Split(filepath.ToSlash(rel), "/")[0]
+	if _, err := os.Stat(filepath.Join(townRoot, first, "config.json")); err == nil {
+		return first
+	}
+	return ""
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var since time.Time
+	if historySince != "" {
+		d, err := parseDuration(historySince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	all, err := history.Read(townRoot)
+	if err != nil {
+		return err
+	}
+	var entries []history.Entry
+	for _, e := range all {
+		if historyFailed && !e.Failed() {
+			continue
+		}
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		if historyRig != "" && e.Rig != historyRig {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if historyLimit > 0 && len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+
+	if historyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("%s No matching commands recorded\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, e := range entries {
+		mark := style.Success.Render("✓")
+		if e.Failed() {
+			mark = style.Error.Render("✗")
+		}
+		who := e.User
+		if e.Role != "" {
+			who = e.Role
+		}
+		where := ""
+		if e.Rig != "" {
+			where = " [" + e.Rig + "]"
+		}
+		if lookups := e.CacheHits + e.CacheMisses; lookups > 0 {
+			where += fmt.Sprintf(" (cache %d/%d hits)", e.CacheHits, lookups)
+		}
+		fmt.Printf("%s %s  %-8s %s%s  %s\n", mark,
+			e.Time.Local().Format("2006-01-02 15:04:05"), formatHistoryDuration(e.DurationMs),
+			strings.Join(append([]string{"gt"}, e.Args...), " "), style.Dim.Render(where), style.Dim.Render(who))
+		if e.Error != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(e.Error))
+		}
+	}
+	return nil
+}
+
+// formatHistoryDuration renders a millisecond count compactly.
+func formatHistoryDuration(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Second {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/history"
+)
+
+func TestRecordHistory(t *testing.T) {
+	townRoot := t.TempDir()
+	rigDir := filepath.Join(townRoot, "gastown", "mayor", "rig")
+	for _, dir := range []string{filepath.Join(townRoot, "mayor"), rigDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{filepath.Join(townRoot, "mayor", "town.json"), filepath.Join(townRoot, "gastown", "config.json")} {
+		if err := os.WriteFile(f, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(rigDir)
+	t.Setenv("GT_NO_HISTORY", "")
+	t.Setenv("GT_RIG", "")
+	t.Setenv("GT_ROLE", "gastown/crew/max")
+
+	origArgs := os.Args
+	t.Cleanup(func() { os.Args = origArgs })
+
+	os.Args = []string{"gt", "rig", "list"}
+	recordHistory(time.Now(), 0, nil)
+	os.Args = []string{"gt", "history"}
+	recordHistory(time.Now(), 0, nil)
+	os.Args = []string{"gt", "rig", "add", "bad"}
+	recordHistory(time.Now(), 1, errors.New("boom"))
+
+	entries, err := history.Read(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2 (history itself is not recorded): %+v", len(entries), entries)
+	}
+	first, second := entries[0], entries[1]
+	if first.Command != "gt rig list" || first.Rig != "gastown" || first.Role != "gastown/crew/max" {
+		t.Errorf("first entry = %+v", first)
+	}
+	if !second.Failed() || second.Error != "boom" {
+		t.Errorf("second entry = %+v, want failed with error", second)
+	}
+}
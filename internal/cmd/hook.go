@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
@@ -229,7 +230,7 @@ func runHook(_ *cobra.Command, args []string) error {
 					}
 					closeCmd := exec.Command("bd", closeArgs...)
 					closeCmd.Stderr = os.Stderr
-					if err := closeCmd.Run(); err != nil {
+					if err := cmdtrace.Run(closeCmd); err != nil {
 						return fmt.Errorf("closing completed bead %s: %w", existing.ID, err)
 					}
 				} else {
@@ -283,7 +284,7 @@ func runHook(_ *cobra.Command, args []string) error {
 	hookCmd := exec.Command("bd", "update", beadID, "--status=hooked", "--assignee="+agentID)
 	hookCmd.Dir = townRoot
 	hookCmd.Stderr = os.Stderr
-	if err := hookCmd.Run(); err != nil {
+	if err := cmdtrace.Run(hookCmd); err != nil {
 		return fmt.Errorf("hooking bead: %w", err)
 	}
 
@@ -424,7 +425,7 @@ func runHookShow(cmd *cobra.Command, args []string) error {
 // findTownRoot finds the Gas Town root directory.
 func findTownRoot() (string, error) {
 	cmd := exec.Command("gt", "root")
-	out, err := cmd.Output()
+	out, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return "", err
 	}
@@ -8,13 +8,18 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-var initForce bool
+var (
+	initForce        bool
+	initFromManifest string
+)
 
 var initCmd = &cobra.Command{
 	Use:     "init",
@@ -26,16 +31,29 @@ This creates the standard agent directories (polecats/, witness/, refinery/,
 mayor/) and updates .git/info/exclude to ignore them.
 
 The current directory must be a git repository. Use --force to reinitialize
-an existing rig structure.`,
+an existing rig structure.
+
+With --from-manifest, instead bring the current town in line with a
+manifest from 'gt export manifest': apply agent settings and profiles, add
+missing rigs, and check formula overrides against their recorded hashes.
+
+Examples:
+  gt init                                  # Initialize this repo as a rig
+  gt init --from-manifest town.json        # Recreate a town from a manifest`,
 	RunE: runInit,
 }
 
 func init() {
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "Reinitialize existing structure")
+	initCmd.Flags().StringVar(&initFromManifest, "from-manifest", "", "Recreate the current town from a manifest (see 'gt export manifest')")
 	rootCmd.AddCommand(initCmd)
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
+	if initFromManifest != "" {
+		return runInitFromManifest()
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("getting current directory: %w", err)
@@ -158,7 +176,7 @@ func registerCustomTypes(workDir string) error {
 	// Try to set custom types
 	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(cmd)
 	if err != nil {
 		// Check for common expected errors
 		outStr := string(output)
@@ -170,3 +188,22 @@ func registerCustomTypes(workDir string) error {
 	}
 	return nil
 }
+
+func runInitFromManifest() error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace (run 'gt install' first): %w", err)
+	}
+	m, err := loadTownManifest(initFromManifest)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Applying manifest %s to %s\n\n",
+		style.Bold.Render("⚙️"), initFromManifest, style.Dim.Render(townRoot))
+	if err := applyTownManifest(townRoot, m); err != nil {
+		return err
+	}
+	fmt.Printf("\n%s Town matches manifest (%d rig(s))\n", style.Bold.Render("✓"), len(m.Rigs))
+	return nil
+}
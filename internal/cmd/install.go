@@ -12,11 +12,13 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deps"
 	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/migrate"
 	"github.com/steveyegge/gastown/internal/shell"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
@@ -182,6 +184,12 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("   ✓ Created mayor/town.json\n")
 
+	// Mark the town root so workspace detection doesn't rely on heuristics
+	if err := workspace.WriteMarker(absPath, townName); err != nil {
+		return err
+	}
+	fmt.Printf("   ✓ Created %s\n", workspace.TownMarker)
+
 	// Create rigs.json in mayor/
 	rigsConfig := &config.RigsConfig{
 		Version: config.CurrentRigsVersion,
@@ -326,6 +334,12 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   ✓ Created settings/escalation.json\n")
 	}
 
+	// A fresh town already has the current layout; record it so
+	// 'gt migrate' only runs steps added by later upgrades.
+	if err := migrate.MarkCurrent(absPath); err != nil {
+		fmt.Printf("   %s Could not record layout version: %v\n", style.Dim.Render("⚠"), err)
+	}
+
 	// Provision town-level slash commands (.claude/commands/)
 	// All agents inherit these via Claude's directory traversal - no per-workspace copies needed.
 	if err := templates.ProvisionCommands(absPath); err != nil {
@@ -450,7 +464,7 @@ func initTownBeads(townPath string) error {
 	cmd := exec.Command("bd", "init", "--prefix", "hq")
 	cmd.Dir = townPath
 
-	output, err := cmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(cmd)
 	if err != nil {
 		// Check if beads is already initialized
 		if strings.Contains(string(output), "already initialized") {
@@ -469,7 +483,7 @@ func initTownBeads(townPath string) error {
 	// Explicitly set issue_prefix config (bd init --prefix may not persist it in newer versions).
 	prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", "hq")
 	prefixSetCmd.Dir = townPath
-	if prefixOutput, prefixErr := prefixSetCmd.CombinedOutput(); prefixErr != nil {
+	if prefixOutput, prefixErr := cmdtrace.CombinedOutput(prefixSetCmd); prefixErr != nil {
 		return fmt.Errorf("bd config set issue_prefix failed: %s", strings.TrimSpace(string(prefixOutput)))
 	}
 
@@ -483,7 +497,7 @@ func initTownBeads(townPath string) error {
 	// This allows bd create --id=hq-cv-xxx to pass prefix validation.
 	prefixCmd := exec.Command("bd", "config", "set", "allowed_prefixes", "hq,hq-cv")
 	prefixCmd.Dir = townPath
-	if prefixOutput, prefixErr := prefixCmd.CombinedOutput(); prefixErr != nil {
+	if prefixOutput, prefixErr := cmdtrace.CombinedOutput(prefixCmd); prefixErr != nil {
 		fmt.Printf("   %s Could not set allowed_prefixes: %s\n", style.Dim.Render("⚠"), strings.TrimSpace(string(prefixOutput)))
 	}
 
@@ -528,7 +542,7 @@ func initTownBeads(townPath string) error {
 func ensureRepoFingerprint(beadsPath string) error {
 	cmd := exec.Command("bd", "migrate", "--update-repo-id")
 	cmd.Dir = beadsPath
-	output, err := cmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd migrate --update-repo-id: %s", strings.TrimSpace(string(output)))
 	}
@@ -542,7 +556,7 @@ func ensureRepoFingerprint(beadsPath string) error {
 func ensureCustomTypes(beadsPath string) error {
 	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = beadsPath
-	output, err := cmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom: %s", strings.TrimSpace(string(output)))
 	}
@@ -633,7 +647,7 @@ func ensureBeadsCustomTypes(workDir string, types []string) error {
 
 	cmd := exec.Command("bd", "config", "set", "types.custom", strings.Join(types, ","))
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom failed: %s", strings.TrimSpace(string(output)))
 	}
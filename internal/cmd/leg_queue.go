@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Leg priority flags
+var (
+	formulaRunPriority string
+	formulaRunPreempt  bool
+)
+
+func init() {
+	formulaRunCmd.Flags().StringVar(&formulaRunPriority, "priority", "normal", "Dispatch priority of the run's legs: high, normal, or low")
+	formulaRunCmd.Flags().BoolVar(&formulaRunPreempt, "preempt", false, "With --priority high, pause running low priority legs when the rig has no free polecat")
+}
+
+// formulaRunLegPriority validates --priority and --preempt.
+func formulaRunLegPriority(townRoot string) (convoy.Priority, error) {
+	p, err := convoy.ParsePriority(formulaRunPriority)
+	if err != nil {
+		return "", fmt.Errorf("--priority: %w", err)
+	}
+	if formulaRunPreempt && p != convoy.PriorityHigh {
+		return "", fmt.Errorf("--preempt needs --priority high")
+	}
+	if formulaRunPreempt {
+		if err := checkPreemptBackend(townRoot); err != nil {
+			return "", err
+		}
+	}
+	return p, nil
+}
+
+// checkPreemptBackend refuses --preempt when the town's session backend
+// can't pause a running polecat.
+func checkPreemptBackend(townRoot string) error {
+	if backend := session.BackendTypeFor(townRoot); !session.CanSuspend(backend) {
+		return fmt.Errorf("--preempt: the %s session backend can't pause running polecats", backend)
+	}
+	return nil
+}
+
+// legDispatchOptions control a pass over the leg queue.
+type legDispatchOptions struct {
+	DryRun  bool
+	Preempt bool // Pause running low priority legs for waiting high priority ones
+}
+
+// Seams for tests of the dispatcher.
+var (
+	rigMaxPolecatsFn     = rigMaxPolecats
+	rigPolecatSessionsFn = rigPolecatSessions
+	runningLowLegsFn     = runningLowPriorityLegs
+	slingQueuedLegFn     = slingQueuedLeg
+	suspendSessionFn     = func(townRoot, sess string) error { return session.OpenBackend(townRoot, nil).SuspendSession(sess) }
+	resumeSessionFn      = func(townRoot, sess string) error { return session.OpenBackend(townRoot, nil).ResumeSession(sess) }
+)
+
+// enqueueLegs adds legs to the town's leg queue; legs already queued keep
+// their place.
+func enqueueLegs(townRoot string, legs []convoy.QueuedLeg) error {
+	return convoy.UpdateQueue(townRoot, func(q *convoy.Queue) error {
+		for _, leg := range legs {
+			q.Add(leg)
+		}
+		return nil
+	})
+}
+
+// dispatchLegQueue services the town's leg queue. Each rig runs at most
+// max_polecats polecats; free slots go to queued legs in priority order,
+// resuming paused legs before slinging waiting legs of the same priority.
+// Held legs keep their place but are skipped.
+// Nothing is dispatched while the town is paused or in a maintenance
+// window. It returns the bead IDs slung or resumed.
+func dispatchLegQueue(townRoot string, opts legDispatchOptions) []string {
+	if paused, _, _ := mayor.IsTownPaused(townRoot); paused {
+		return nil
+	}
+	if w, _ := maintenance.Active(townRoot, time.Now()); w != nil {
+		return nil
+	}
+
+	var dispatched []string
+	pass := func(q *convoy.Queue) error {
+		for _, rigName := range q.Rigs() {
+			dispatched = append(dispatched, dispatchRigLegs(townRoot, rigName, q, opts)...)
+		}
+		return nil
+	}
+	var err error
+	if opts.DryRun {
+		var q *convoy.Queue
+		if q, err = convoy.LoadQueue(townRoot); err == nil {
+			err = pass(q)
+		}
+	} else {
+		err = convoy.UpdateQueue(townRoot, pass)
+	}
+	if err != nil {
+		fmt.Printf("%s Leg queue: %v\n", style.Dim.Render("Warning:"), err)
+	}
+	return dispatched
+}
+
+// dispatchRigLegs hands a rig's free polecat slots to its queued legs.
+func dispatchRigLegs(townRoot, rigName string, q *convoy.Queue, opts legDispatchOptions) []string {
+	entries := q.Order(rigName)
+	sessions, _ := rigPolecatSessionsFn(townRoot, rigName)
+	slots := rigMaxPolecatsFn(townRoot, rigName) - len(sessions)
+	for _, e := range entries {
+		if e.Paused != nil {
+			slots++ // A suspended polecat doesn't hold its slot
+		}
+	}
+
+	if opts.Preempt {
+		urgent := 0
+		for _, e := range entries {
+			if e.Waiting != nil && !e.Held() && !e.BackingOff(time.Now()) && e.Priority() == convoy.PriorityHigh {
+				urgent++
+			}
+		}
+		slots += preemptLowLegs(townRoot, rigName, q, urgent-slots, opts.DryRun)
+	}
+
+	var dispatched []string
+	for _, e := range entries {
+		if slots <= 0 {
+			break
+		}
+		if e.Held() {
+			continue // Held with gt queue hold
+		}
+		if e.BackingOff(time.Now()) {
+			continue // A retry waiting out its backoff
+		}
+		slots--
+		switch {
+		case opts.DryRun && e.Paused != nil:
+			fmt.Printf("%s Would resume %s (%s)\n", style.Warning.Render("⚠"), e.BeadID(), e.Priority())
+		case opts.DryRun:
+			fmt.Printf("%s Would dispatch %s to %s (%s)\n", style.Warning.Render("⚠"), e.BeadID(), rigName, e.Priority())
+		case e.Paused != nil:
+			q.Remove(e.BeadID())
+			if err := resumeSessionFn(townRoot, e.Paused.Session); err != nil {
+				fmt.Printf("%s Failed to resume %s: %v\n", style.Dim.Render("Warning:"), e.BeadID(), err)
+				continue
+			}
+			fmt.Printf("%s Resumed %s\n", style.Bold.Render("▶"), e.BeadID())
+		default:
+			q.Remove(e.BeadID())
+			if err := slingQueuedLegFn(townRoot, *e.Waiting); err != nil {
+				fmt.Printf("%s Failed to dispatch %s: %v\n", style.Dim.Render("Warning:"), e.BeadID(), err)
+				continue
+			}
+			fmt.Printf("%s Dispatched %s to %s (%s)\n", style.Bold.Render("→"), e.BeadID(), rigName, e.Priority())
+		}
+		dispatched = append(dispatched, e.BeadID())
+	}
+	return dispatched
+}
+
+// preemptLowLegs suspends up to n running low priority legs on a rig so
+// waiting high priority legs can take their slots. Paused legs go on the
+// queue and resume once slots free up. It returns how many it paused.
+func preemptLowLegs(townRoot, rigName string, q *convoy.Queue, n int, dryRun bool) int {
+	if n <= 0 {
+		return 0
+	}
+	paused := 0
+	for _, leg := range runningLowLegsFn(townRoot, rigName) {
+		if paused == n {
+			break
+		}
+		if q.Has(leg.BeadID) {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("%s Would pause low priority leg %s (%s)\n", style.Warning.Render("⚠"), leg.BeadID, leg.Session)
+			paused++
+			continue
+		}
+		if err := suspendSessionFn(townRoot, leg.Session); err != nil {
+			fmt.Printf("%s Failed to pause %s: %v\n", style.Dim.Render("Warning:"), leg.BeadID, err)
+			continue
+		}
+		leg.PausedAt = time.Now().UTC()
+		q.Paused = append(q.Paused, leg)
+		fmt.Printf("%s Paused low priority leg %s for urgent work\n", style.Warning.Render("⏸"), leg.BeadID)
+		paused++
+	}
+	return paused
+}
+
+// rigPolecatSessions returns the rig's polecat sessions in the town's
+// session backend.
+func rigPolecatSessions(townRoot, rigName string) ([]string, error) {
+	all, err := session.OpenBackend(townRoot, nil).ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("gt-%s-", rigName)
+	var sessions []string
+	for _, name := range all {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if _, _, ok := parsePolecatSessionName(name); ok {
+			sessions = append(sessions, name)
+		}
+	}
+	return sessions, nil
+}
+
+// runningLowPriorityLegs returns the rig's polecats working on low
+// priority formula legs, as candidates for preemption.
+func runningLowPriorityLegs(townRoot, rigName string) []convoy.PausedLeg {
+	r, err := loadTownRig(townRoot, rigName)
+	if err != nil {
+		return nil
+	}
+	polecats, err := polecat.NewManager(r, git.NewGit(r.Path), nil).List()
+	if err != nil {
+		return nil
+	}
+	var legs []convoy.PausedLeg
+	for _, p := range polecats {
+		if p.Issue == "" {
+			continue
+		}
+		payload, err := loadSlingPayload(slingPayloadPath(townRoot, p.Issue))
+		if err != nil || payload.Priority != convoy.PriorityLow {
+			continue
+		}
+		legs = append(legs, convoy.PausedLeg{
+			BeadID:   p.Issue,
+			Rig:      rigName,
+			ConvoyID: payload.ConvoyID,
+			Session:  session.PolecatSessionName(rigName, p.Name),
+			Priority: convoy.PriorityLow,
+		})
+	}
+	return legs
+}
+
+// slingQueuedLeg slings a queued leg to a polecat on its rig.
+func slingQueuedLeg(townRoot string, leg convoy.QueuedLeg) error {
+	slingCmd := exec.Command("gt", "sling", leg.BeadID, leg.Rig, "--context-file", leg.Payload)
+	slingCmd.Dir = townRoot
+	slingCmd.Stdout = os.Stdout
+	slingCmd.Stderr = os.Stderr
+	if err := cmdtrace.Run(slingCmd); err != nil {
+		// Record the failure on the leg bead
+		commentCmd := exec.Command("bd", "comment", leg.BeadID, fmt.Sprintf("Failed to sling: %v", err))
+		commentCmd.Dir = filepath.Join(townRoot, ".beads")
+		_ = cmdtrace.Run(commentCmd)
+		return err
+	}
+	return nil
+}
+
+// rigMaxPolecats returns how many polecats a rig may run at once: its
+// max_polecats setting, or the system default.
+func rigMaxPolecats(townRoot, rigName string) int {
+	if r, err := loadTownRig(townRoot, rigName); err == nil {
+		if n := r.GetIntConfig("max_polecats"); n > 0 {
+			return n
+		}
+	}
+	n, _ := rig.SystemDefaults["max_polecats"].(int)
+	return n
+}
+
+// loadTownRig loads a rig of the town at townRoot.
+func loadTownRig(townRoot, rigName string) (*rig.Rig, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	return rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).GetRig(rigName)
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/mayor"
+)
+
+// stubLegDispatch replaces the dispatcher's seams with a rig that has
+// running polecats and the given room, and records what it slings,
+// suspends and resumes.
+type stubLegDispatch struct {
+	slung, suspended, resumed []string
+}
+
+func newStubLegDispatch(t *testing.T, maxPolecats, running int, lowLegs []convoy.PausedLeg) *stubLegDispatch {
+	t.Helper()
+	s := &stubLegDispatch{}
+	oldMax, oldSessions, oldLow := rigMaxPolecatsFn, rigPolecatSessionsFn, runningLowLegsFn
+	oldSling, oldSuspend, oldResume := slingQueuedLegFn, suspendSessionFn, resumeSessionFn
+	t.Cleanup(func() {
+		rigMaxPolecatsFn, rigPolecatSessionsFn, runningLowLegsFn = oldMax, oldSessions, oldLow
+		slingQueuedLegFn, suspendSessionFn, resumeSessionFn = oldSling, oldSuspend, oldResume
+	})
+
+	rigMaxPolecatsFn = func(string, string) int { return maxPolecats }
+	rigPolecatSessionsFn = func(string, string) ([]string, error) { return make([]string, running), nil }
+	runningLowLegsFn = func(string, string) []convoy.PausedLeg { return lowLegs }
+	slingQueuedLegFn = func(_ string, leg convoy.QueuedLeg) error {
+		s.slung = append(s.slung, leg.BeadID)
+		return nil
+	}
+	suspendSessionFn = func(_, sess string) error {
+		s.suspended = append(s.suspended, sess)
+		return nil
+	}
+	resumeSessionFn = func(_, sess string) error {
+		s.resumed = append(s.resumed, sess)
+		return nil
+	}
+	return s
+}
+
+func queueLegs(t *testing.T, townRoot string, legs ...convoy.QueuedLeg) {
+	t.Helper()
+	if err := enqueueLegs(townRoot, legs); err != nil {
+		t.Fatalf("enqueueLegs() error: %v", err)
+	}
+}
+
+func TestDispatchLegQueuePriorityOrder(t *testing.T) {
+	town := t.TempDir()
+	stub := newStubLegDispatch(t, 3, 1, nil)
+	queueLegs(t, town,
+		convoy.QueuedLeg{BeadID: "low", Rig: "gastown", Priority: convoy.PriorityLow},
+		convoy.QueuedLeg{BeadID: "normal", Rig: "gastown"},
+		convoy.QueuedLeg{BeadID: "high", Rig: "gastown", Priority: convoy.PriorityHigh},
+	)
+
+	dispatched := dispatchLegQueue(town, legDispatchOptions{})
+	if len(dispatched) != 2 || dispatched[0] != "high" || dispatched[1] != "normal" {
+		t.Errorf("dispatched %v, want [high normal]", dispatched)
+	}
+	q, _ := convoy.LoadQueue(town)
+	if len(q.Waiting) != 1 || q.Waiting[0].BeadID != "low" {
+		t.Errorf("queue left %+v, want only the low leg", q.Waiting)
+	}
+	if len(stub.slung) != 2 {
+		t.Errorf("slung %v, want two legs", stub.slung)
+	}
+}
+
+func TestDispatchLegQueueDryRun(t *testing.T) {
+	town := t.TempDir()
+	stub := newStubLegDispatch(t, 2, 0, nil)
+	queueLegs(t, town, convoy.QueuedLeg{BeadID: "leg", Rig: "gastown"})
+
+	if got := dispatchLegQueue(town, legDispatchOptions{DryRun: true}); len(got) != 1 {
+		t.Errorf("dry run dispatched %v, want [leg]", got)
+	}
+	if len(stub.slung) != 0 {
+		t.Errorf("dry run slung %v", stub.slung)
+	}
+	if q, _ := convoy.LoadQueue(town); !q.Has("leg") {
+		t.Error("dry run removed the leg from the queue")
+	}
+}
+
+func TestDispatchLegQueuePreempt(t *testing.T) {
+	town := t.TempDir()
+	low := []convoy.PausedLeg{{BeadID: "patrol", Rig: "gastown", Session: "gt-gastown-rictus", Priority: convoy.PriorityLow}}
+	stub := newStubLegDispatch(t, 1, 1, low)
+	queueLegs(t, town, convoy.QueuedLeg{BeadID: "review", Rig: "gastown", Priority: convoy.PriorityHigh})
+
+	// Without --preempt the urgent leg waits for a free polecat
+	if got := dispatchLegQueue(town, legDispatchOptions{}); len(got) != 0 {
+		t.Fatalf("dispatched %v with a full rig", got)
+	}
+
+	got := dispatchLegQueue(town, legDispatchOptions{Preempt: true})
+	if len(got) != 1 || got[0] != "review" {
+		t.Fatalf("dispatched %v, want [review]", got)
+	}
+	if len(stub.suspended) != 1 || stub.suspended[0] != "gt-gastown-rictus" {
+		t.Errorf("suspended %v, want the patrol leg's session", stub.suspended)
+	}
+	q, _ := convoy.LoadQueue(town)
+	if len(q.Paused) != 1 || q.Paused[0].BeadID != "patrol" {
+		t.Fatalf("paused %+v, want the patrol leg", q.Paused)
+	}
+
+	// Once the review's polecat is gone, the paused leg resumes
+	newStubLegDispatch(t, 1, 1, nil)
+	resumed := dispatchLegQueue(town, legDispatchOptions{})
+	if len(resumed) != 1 || resumed[0] != "patrol" {
+		t.Errorf("dispatched %v, want the paused patrol leg resumed", resumed)
+	}
+}
+
+func TestDispatchLegQueueFailedSling(t *testing.T) {
+	town := t.TempDir()
+	newStubLegDispatch(t, 2, 0, nil)
+	slingQueuedLegFn = func(string, convoy.QueuedLeg) error { return errors.New("no polecat") }
+	queueLegs(t, town, convoy.QueuedLeg{BeadID: "leg", Rig: "gastown"})
+
+	if got := dispatchLegQueue(town, legDispatchOptions{}); len(got) != 0 {
+		t.Errorf("dispatched %v after a failed sling", got)
+	}
+}
+
+func TestDispatchLegQueueTownPaused(t *testing.T) {
+	town := t.TempDir()
+	stub := newStubLegDispatch(t, 2, 0, nil)
+	queueLegs(t, town, convoy.QueuedLeg{BeadID: "leg", Rig: "gastown"})
+	if err := mayor.PauseTown(town, "incident", "human"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := dispatchLegQueue(town, legDispatchOptions{}); len(got) != 0 || len(stub.slung) != 0 {
+		t.Errorf("dispatched %v while the town was paused", got)
+	}
+}
+
+func TestFormulaRunLegPriority(t *testing.T) {
+	defer func(p string, preempt bool) { formulaRunPriority, formulaRunPreempt = p, preempt }(formulaRunPriority, formulaRunPreempt)
+	t.Setenv("GT_SESSION_BACKEND", "tmux")
+	town := t.TempDir()
+
+	formulaRunPriority, formulaRunPreempt = "high", true
+	if p, err := formulaRunLegPriority(town); err != nil || p != convoy.PriorityHigh {
+		t.Errorf("--priority high --preempt = %q, %v", p, err)
+	}
+	t.Setenv("GT_SESSION_BACKEND", "screen")
+	if _, err := formulaRunLegPriority(town); err == nil {
+		t.Error("--preempt on the screen backend succeeded, want error")
+	}
+	t.Setenv("GT_SESSION_BACKEND", "tmux")
+	formulaRunPriority = "low"
+	if _, err := formulaRunLegPriority(town); err == nil {
+		t.Error("--priority low --preempt succeeded, want error")
+	}
+	formulaRunPriority, formulaRunPreempt = "asap", false
+	if _, err := formulaRunLegPriority(town); err == nil {
+		t.Error("--priority asap succeeded, want error")
+	}
+}
+
+func TestDispatchLegQueueSkipsHeld(t *testing.T) {
+	town := t.TempDir()
+	stub := newStubLegDispatch(t, 1, 0, nil)
+	queueLegs(t, town,
+		convoy.QueuedLeg{BeadID: "held", Rig: "gastown", Priority: convoy.PriorityHigh},
+		convoy.QueuedLeg{BeadID: "next", Rig: "gastown"},
+	)
+	if err := convoy.UpdateQueue(town, func(q *convoy.Queue) error {
+		q.SetHeld("held", true)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := dispatchLegQueue(town, legDispatchOptions{})
+	if len(got) != 1 || got[0] != "next" || len(stub.slung) != 1 {
+		t.Errorf("dispatched %v, want the held leg skipped", got)
+	}
+	if q, _ := convoy.LoadQueue(town); !q.Has("held") {
+		t.Error("held leg left the queue")
+	}
+}
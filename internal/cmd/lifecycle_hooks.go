@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/userhooks"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// fireLifecycleHook runs the town's hooks for event and warns about the
+// ones that failed; a broken hook never fails the command that fired it.
+func fireLifecycleHook(townRoot, event string, data interface{}) {
+	for _, err := range userhooks.Fire(townRoot, event, data) {
+		style.PrintWarning("%v", err)
+	}
+}
+
+// fireFormulaOverrideHook runs the town's on_formula_override_changed
+// hooks after gt changed the formula file at path; action is what changed
+// it: "modify", "reset", "edit", or "move" (gt formula promote and demote).
+func fireFormulaOverrideHook(name, action, path string) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	data := map[string]string{"formula": name, "action": action, "path": path}
+	if content, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is a formula gt just wrote
+		o := formula.ParseOverride(content)
+		data["owner"], data["reason"] = o.Owner, o.Reason
+	}
+	fireLifecycleHook(townRoot, config.HookFormulaOverrideChanged, data)
+}
@@ -344,6 +344,7 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 
 	// Log the event
 	logger := townlog.NewLogger(townRoot)
+	defer logger.Close()
 	if err := logger.Log(eventType, crashAgent, context); err != nil {
 		return fmt.Errorf("logging event: %w", err)
 	}
@@ -363,12 +364,14 @@ func LogEvent(eventType townlog.EventType, agent, context string) error {
 	}
 
 	logger := townlog.NewLogger(townRoot)
+	defer logger.Close()
 	return logger.Log(eventType, agent, context)
 }
 
 // LogEventWithRoot logs an event when the town root is already known.
 func LogEventWithRoot(townRoot string, eventType townlog.EventType, agent, context string) error {
 	logger := townlog.NewLogger(townRoot)
+	defer logger.Close()
 	return logger.Log(eventType, agent, context)
 }
 
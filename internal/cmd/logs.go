@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/logs"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Logs command flags
+var (
+	logsRig    string
+	logsConvoy string
+	logsFollow bool
+	logsSince  string
+	logsGrep   string
+	logsTail   int
+)
+
+var logsCmd = &cobra.Command{
+	Use:     "logs",
+	GroupID: GroupDiag,
+	Short:   "Show daemon and agent session logs",
+	Long: `Show the town's log files merged into one stream, each line prefixed
+with its source.
+
+Sources:
+  <rig>/polecats/<name>   polecat session output (.runtime/logs/)
+  daemon                  daemon/daemon.log
+  dolt                    daemon/dolt-server.log
+  town                    logs/town.log (agent lifecycle events, see gt log)
+
+Lines are ordered by their timestamps; lines without one (most session
+output) follow the line before them.
+
+With --convoy, only the sessions of the polecats assigned to the convoy's
+legs are shown, plus lines of other logs that mention the convoy or a leg.
+
+Examples:
+  gt logs                          # Last 100 lines across the town
+  gt logs --rig gastown -f         # Follow one rig's sessions
+  gt logs --convoy hq-cv-abc12     # One convoy run
+  gt logs --since 30m --grep 'error|panic'`,
+	Args: cobra.NoArgs,
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().StringVar(&logsRig, "rig", "", "Only show logs of this rig")
+	logsCmd.Flags().StringVar(&logsConvoy, "convoy", "", "Only show logs related to this convoy")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new lines as they are written")
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "Only show lines from this long ago (e.g., 30m, 2h)")
+	logsCmd.Flags().StringVarP(&logsGrep, "grep", "g", "", "Only show lines matching this regular expression")
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "n", 100, "Number of lines to show before following (0 for all)")
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter := logs.Filter{Rig: logsRig}
+	if logsSince != "" {
+		d, err := time.ParseDuration(logsSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		filter.Since = time.Now().Add(-d)
+	}
+	if logsGrep != "" {
+		if filter.Match, err = regexp.Compile(logsGrep); err != nil {
+			return fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+	}
+	if logsConvoy != "" {
+		tracked := getTrackedIssues(filepath.Join(townRoot, ".beads"), logsConvoy)
+		if len(tracked) == 0 {
+			return fmt.Errorf("convoy %s not found or tracks no issues", logsConvoy)
+		}
+		filter.Agents, filter.MentionAny = convoyLogFilter(logsConvoy, tracked)
+	}
+
+	sources, err := logs.Sources(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing logs: %w", err)
+	}
+	lines, err := logs.Read(sources, filter)
+	if err != nil {
+		return fmt.Errorf("reading logs: %w", err)
+	}
+	if logsTail > 0 && len(lines) > logsTail {
+		lines = lines[len(lines)-logsTail:]
+	}
+
+	width := logSourceWidth(sources)
+	for _, line := range lines {
+		printLogLine(line, width)
+	}
+	if !logsFollow {
+		if len(lines) == 0 {
+			fmt.Printf("%s No log lines match\n", style.Dim.Render("○"))
+		}
+		return nil
+	}
+
+	tailer, err := logs.NewTailer(townRoot, filter)
+	if err != nil {
+		return err
+	}
+	for {
+		time.Sleep(500 * time.Millisecond)
+		lines, err := tailer.Poll()
+		if err != nil {
+			return fmt.Errorf("following logs: %w", err)
+		}
+		for _, line := range lines {
+			printLogLine(line, width)
+		}
+	}
+}
+
+// convoyLogFilter returns the agents whose sessions belong to a convoy and
+// the IDs whose mention ties a line in another log to it.
+func convoyLogFilter(convoyID string, tracked []trackedIssueInfo) (agents, mentions []string) {
+	mentions = []string{convoyID}
+	for _, t := range tracked {
+		mentions = append(mentions, t.ID)
+		if t.Assignee != "" {
+			agents = append(agents, strings.TrimSuffix(t.Assignee, "/"))
+		}
+	}
+	return agents, mentions
+}
+
+// logSourceWidth is the prefix width that aligns the longest source name.
+func logSourceWidth(sources []logs.Source) int {
+	width := 0
+	for _, src := range sources {
+		if len(src.Name) > width {
+			width = len(src.Name)
+		}
+	}
+	return width
+}
+
+// logSourceColors are cycled through to tell sources apart.
+var logSourceColors = []lipgloss.TerminalColor{
+	ui.ColorAccent,
+	ui.ColorPass,
+	ui.ColorWarn,
+	ui.ColorStatusHooked,
+	ui.ColorStatusPinned,
+	ui.ColorTypeFeature,
+}
+
+// logSourceStyle gives each source a stable color.
+func logSourceStyle(name string) lipgloss.Style {
+	switch name {
+	case "daemon", "dolt", "town":
+		return style.Dim
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return lipgloss.NewStyle().Foreground(logSourceColors[h.Sum32()%uint32(len(logSourceColors))])
+}
+
+func printLogLine(line logs.Line, width int) {
+	prefix := fmt.Sprintf("%-*s", width, line.Source)
+	fmt.Printf("%s %s %s\n", logSourceStyle(line.Source).Render(prefix), style.Dim.Render("|"), line.Text)
+}
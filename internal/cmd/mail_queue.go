@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -152,7 +153,7 @@ func listUnclaimedQueueMessages(beadsDir, queueName string) ([]queueMessage, err
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return nil, fmt.Errorf("%s", errMsg)
@@ -235,7 +236,7 @@ func claimQueueMessage(beadsDir, messageID, claimant string) error {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return fmt.Errorf("%s", errMsg)
@@ -313,7 +314,7 @@ func getQueueMessageInfo(beadsDir, messageID string) (*queueMessageInfo, error)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if strings.Contains(errMsg, "not found") {
 			return nil, fmt.Errorf("message not found: %s", messageID)
@@ -384,7 +385,7 @@ func releaseQueueMessage(beadsDir, messageID, actor string) error {
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
 
-		if err := cmd.Run(); err != nil {
+		if err := cmdtrace.Run(cmd); err != nil {
 			errMsg := strings.TrimSpace(stderr.String())
 			if errMsg != "" && !strings.Contains(errMsg, "does not have label") {
 				return fmt.Errorf("%s", errMsg)
@@ -405,7 +406,7 @@ func releaseQueueMessage(beadsDir, messageID, actor string) error {
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
 
-		if err := cmd.Run(); err != nil {
+		if err := cmdtrace.Run(cmd); err != nil {
 			errMsg := strings.TrimSpace(stderr.String())
 			if errMsg != "" && !strings.Contains(errMsg, "does not have label") {
 				return fmt.Errorf("%s", errMsg)
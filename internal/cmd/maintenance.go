@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// queueForMaintenance queues the current command to run after the town's
+// maintenance window closes, if one is open and override is false. It
+// reports whether the command was queued; the caller then stops.
+func queueForMaintenance(townRoot string, override bool) (bool, error) {
+	if override || os.Getenv(overridePauseEnv) != "" {
+		return false, nil
+	}
+	w, end := maintenance.Active(townRoot, time.Now())
+	if w == nil {
+		return false, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return false, fmt.Errorf("getting working directory: %w", err)
+	}
+	if err := maintenance.Enqueue(townRoot, maintenance.Run{Args: os.Args[1:], Dir: dir, Window: w.Label()}); err != nil {
+		return false, fmt.Errorf("queueing run: %w", err)
+	}
+	fmt.Printf("%s Maintenance window %s is open%s; queued to run after it closes\n",
+		style.Warning.Render("🔧"), w.Label(), maintenanceUntil(end))
+	fmt.Printf("  %s\n", style.Dim.Render("Use --override-pause to run now"))
+	return true, nil
+}
+
+// maintenanceUntil describes when a maintenance window closes.
+func maintenanceUntil(end time.Time) string {
+	if end.IsZero() {
+		return ""
+	}
+	return " until " + end.Local().Format("Mon 15:04")
+}
+
+// maintenanceWindowError returns an error naming the open maintenance
+// window, or nil outside windows.
+func maintenanceWindowError(townRoot string) error {
+	w, end := maintenance.Active(townRoot, time.Now())
+	if w == nil {
+		return nil
+	}
+	return fmt.Errorf("maintenance window %s is open%s", w.Label(), maintenanceUntil(end))
+}
+
+// MaintenanceStatus describes an open maintenance window in gt status.
+type MaintenanceStatus struct {
+	Window string    `json:"window"`
+	Until  time.Time `json:"until,omitempty"`
+	Queued int       `json:"queued"` // Runs waiting for the window to close
+}
+
+// townMaintenanceStatus returns the open maintenance window of the town,
+// or nil outside windows.
+func townMaintenanceStatus(townRoot string) *MaintenanceStatus {
+	w, end := maintenance.Active(townRoot, time.Now())
+	if w == nil {
+		return nil
+	}
+	runs, _ := maintenance.Pending(townRoot)
+	return &MaintenanceStatus{Window: w.Label(), Until: end, Queued: len(runs)}
+}
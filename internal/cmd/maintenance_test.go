@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/maintenance"
+)
+
+func TestQueueForMaintenance(t *testing.T) {
+	t.Setenv(overridePauseEnv, "")
+	town := t.TempDir()
+
+	if queued, err := queueForMaintenance(town, false); queued || err != nil {
+		t.Fatalf("no window: queued=%v err=%v", queued, err)
+	}
+
+	settings := config.NewTownSettings()
+	settings.Maintenance = &config.MaintenanceConfig{Windows: []config.MaintenanceWindow{{Name: "always", Cron: "* * * * *"}}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	if queued, err := queueForMaintenance(town, true); queued || err != nil {
+		t.Fatalf("override: queued=%v err=%v", queued, err)
+	}
+	if queued, err := queueForMaintenance(town, false); !queued || err != nil {
+		t.Fatalf("open window: queued=%v err=%v", queued, err)
+	}
+	runs, err := maintenance.Pending(town)
+	if err != nil || len(runs) != 1 || runs[0].Window != "always" {
+		t.Fatalf("queue = %+v, %v", runs, err)
+	}
+
+	if status := townMaintenanceStatus(town); status == nil || status.Queued != 1 {
+		t.Errorf("townMaintenanceStatus = %+v, want 1 queued", status)
+	}
+	if err := maintenanceWindowError(town); err == nil {
+		t.Error("maintenanceWindowError: want error during window")
+	}
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
@@ -66,9 +68,21 @@ Detach with Ctrl-B D.`,
 
 var mayorStatusCmd = &cobra.Command{
 	Use:   "status",
-	Short: "Check Mayor session status",
-	Long:  `Check if the Mayor tmux session is currently running.`,
-	RunE:  runMayorStatus,
+	Short: "Show Mayor session and town orchestration status",
+	Long: `Show the Mayor session and an overview of the whole town.
+
+Answers "is the town configured correctly and fully up" by combining:
+  - Mayor, Deacon, and daemon state
+  - Each rig registered in mayor/rigs.json (directory present, witness
+    and refinery running)
+  - Directories that look like rigs but aren't registered
+
+Problems are listed with the command that fixes them.
+
+Examples:
+  gt mayor status
+  gt mayor status --json`,
+	RunE: runMayorStatus,
 }
 
 var mayorRestartCmd = &cobra.Command{
@@ -231,30 +245,45 @@ func runMayorStatus(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("finding workspace: %w", err)
+	}
 
 	info, err := mgr.Status()
-	if err != nil {
-		if err == mayor.ErrNotRunning {
-			fmt.Printf("%s Mayor session is %s\n",
-				style.Dim.Render("○"),
-				"not running")
-			fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt mayor start"))
-			return nil
-		}
+	if err != nil && err != mayor.ErrNotRunning {
 		return fmt.Errorf("checking status: %w", err)
 	}
 
-	status := "detached"
-	if info.Attached {
-		status = "attached"
+	overview := buildTownOverview(townRoot)
+	if mayorStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*townOverview
+			Healthy bool `json:"healthy"`
+		}{overview, overview.Healthy()})
+	}
+
+	if err == mayor.ErrNotRunning {
+		fmt.Printf("%s Mayor session is %s\n",
+			style.Dim.Render("○"),
+			"not running")
+		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt mayor start"))
+	} else {
+		status := "detached"
+		if info.Attached {
+			status = "attached"
+		}
+		fmt.Printf("%s Mayor session is %s\n",
+			style.Bold.Render("●"),
+			style.Bold.Render("running"))
+		fmt.Printf("  Status: %s\n", status)
+		fmt.Printf("  Created: %s\n", info.Created)
+		fmt.Printf("\nAttach with: %s\n", style.Dim.Render("gt mayor attach"))
 	}
-	fmt.Printf("%s Mayor session is %s\n",
-		style.Bold.Render("●"),
-		style.Bold.Render("running"))
-	fmt.Printf("  Status: %s\n", status)
-	fmt.Printf("  Created: %s\n", info.Created)
-	fmt.Printf("\nAttach with: %s\n", style.Dim.Render("gt mayor attach"))
 
+	printTownOverview(overview)
 	return nil
 }
 
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	mayorStatusJSON bool
+	mayorAuditJSON  bool
+)
+
+var mayorAdoptCmd = &cobra.Command{
+	Use:   "adopt <path>",
+	Short: "Register an existing directory as a rig",
+	Long: `Register an existing directory in the town root as a rig.
+
+The directory must be a direct child of the town root. The rig name is the
+directory name; the git URL is detected from its origin remote (or from
+mayor/rig) unless --url is given. This is the same as
+'gt rig add <name> --adopt', addressed by path.
+
+Use 'gt mayor audit' to find directories that need adopting.
+
+Examples:
+  gt mayor adopt ./myproject
+  gt mayor adopt ~/gt/legacy_api --url git@github.com:org/legacy-api.git
+  gt mayor adopt ./scratch --force      # No git remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMayorAdopt,
+}
+
+var mayorAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Find rig-like directories that aren't registered",
+	Long: `Scan the town root for directories that look like rigs but are not
+registered in mayor/rigs.json, and for registered rigs whose directory is
+missing.
+
+A directory looks like a rig if it has a rig config.json, any of crew/,
+polecats/, witness/, refinery/, a mayor/rig clone, or is itself a git
+repository.
+
+Exits non-zero when anything is found, so it can gate scripts.
+
+Examples:
+  gt mayor audit
+  gt mayor audit --json`,
+	RunE: runMayorAudit,
+}
+
+func init() {
+	mayorStatusCmd.Flags().BoolVar(&mayorStatusJSON, "json", false, "Output as JSON")
+	mayorAuditCmd.Flags().BoolVar(&mayorAuditJSON, "json", false, "Output as JSON")
+
+	mayorAdoptCmd.Flags().StringVar(&rigAddAdoptURL, "url", "", "Git remote URL (default: auto-detected from origin)")
+	mayorAdoptCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
+	mayorAdoptCmd.Flags().BoolVar(&rigAddAdoptForce, "force", false, "Register even if git remote cannot be detected")
+
+	mayorCmd.AddCommand(mayorAdoptCmd)
+	mayorCmd.AddCommand(mayorAuditCmd)
+}
+
+// townRigStatus is the per-rig portion of the town overview.
+type townRigStatus struct {
+	Name     string `json:"name"`
+	OnDisk   bool   `json:"on_disk"`
+	Witness  bool   `json:"witness"`
+	Refinery bool   `json:"refinery"`
+}
+
+// townOverview answers "is the town configured correctly and fully up".
+type townOverview struct {
+	TownRoot     string               `json:"town_root"`
+	Mayor        bool                 `json:"mayor"`
+	Deacon       bool                 `json:"deacon"`
+	Daemon       bool                 `json:"daemon"`
+	DaemonPID    int                  `json:"daemon_pid,omitempty"`
+	Rigs         []townRigStatus      `json:"rigs"`
+	Unregistered []mayor.RigCandidate `json:"unregistered,omitempty"`
+	Problems     []string             `json:"problems,omitempty"`
+}
+
+// Healthy reports whether everything is configured and running.
+func (o *townOverview) Healthy() bool {
+	return len(o.Problems) == 0
+}
+
+// buildTownOverview collects registry, daemon, and session state for a town.
+func buildTownOverview(townRoot string) *townOverview {
+	o := &townOverview{TownRoot: townRoot}
+	t := tmux.NewTmux()
+
+	o.Mayor, _ = t.HasSession(session.MayorSessionName())
+	o.Deacon, _ = t.HasSession(session.DeaconSessionName())
+	o.Daemon, o.DaemonPID, _ = daemon.IsRunning(townRoot)
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		o.Problems = append(o.Problems, fmt.Sprintf("cannot load mayor/rigs.json: %v", err))
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+
+	registered := make(map[string]bool, len(rigsConfig.Rigs))
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		registered[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rs := townRigStatus{Name: name}
+		if info, err := os.Stat(filepath.Join(townRoot, name)); err == nil && info.IsDir() {
+			rs.OnDisk = true
+		}
+		rs.Witness, _ = t.HasSession(session.WitnessSessionName(name))
+		rs.Refinery, _ = t.HasSession(session.RefinerySessionName(name))
+		o.Rigs = append(o.Rigs, rs)
+
+		switch {
+		case !rs.OnDisk:
+			o.Problems = append(o.Problems, fmt.Sprintf("rig %s is registered but its directory is missing", name))
+		case !rs.Witness || !rs.Refinery:
+			o.Problems = append(o.Problems, fmt.Sprintf("rig %s is not fully up (gt rig start %s)", name, name))
+		}
+	}
+
+	o.Unregistered, _ = mayor.FindUnregisteredRigs(townRoot, registered)
+	for _, c := range o.Unregistered {
+		o.Problems = append(o.Problems, fmt.Sprintf("%s looks like a rig but isn't registered (gt mayor adopt %s)", c.Name, c.Name))
+	}
+
+	if !o.Mayor {
+		o.Problems = append(o.Problems, "mayor is not running (gt mayor start)")
+	}
+	if !o.Deacon {
+		o.Problems = append(o.Problems, "deacon is not running (gt deacon start)")
+	}
+	if !o.Daemon {
+		o.Problems = append(o.Problems, "daemon is not running (gt daemon start)")
+	}
+
+	return o
+}
+
+// printTownOverview renders the town overview for gt mayor status.
+func printTownOverview(o *townOverview) {
+	mark := func(ok bool) string {
+		if ok {
+			return style.Success.Render("●")
+		}
+		return style.Dim.Render("○")
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Town"))
+	fmt.Printf("  %s mayor   %s deacon   %s daemon", mark(o.Mayor), mark(o.Deacon), mark(o.Daemon))
+	if o.DaemonPID > 0 {
+		fmt.Printf(" %s", style.Dim.Render(fmt.Sprintf("(pid %d)", o.DaemonPID)))
+	}
+	fmt.Println()
+
+	fmt.Printf("\n%s (%d registered)\n", style.Bold.Render("Rigs"), len(o.Rigs))
+	for _, r := range o.Rigs {
+		if !r.OnDisk {
+			fmt.Printf("  %s %-20s %s\n", style.Error.Render("✗"), r.Name, "directory missing")
+			continue
+		}
+		fmt.Printf("  %s %-20s %s witness  %s refinery\n",
+			mark(r.Witness && r.Refinery), r.Name, mark(r.Witness), mark(r.Refinery))
+	}
+
+	if o.Healthy() {
+		fmt.Printf("\n%s Town is configured and fully up\n", style.Success.Render("✓"))
+		return
+	}
+	fmt.Printf("\n%s %d problem(s):\n", style.Warning.Render("⚠"), len(o.Problems))
+	for _, p := range o.Problems {
+		fmt.Printf("  - %s\n", p)
+	}
+}
+
+func runMayorAdopt(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	absPath, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	}
+	resolvedTown := townRoot
+	if resolved, err := filepath.EvalSymlinks(townRoot); err == nil {
+		resolvedTown = resolved
+	}
+
+	if filepath.Dir(absPath) != resolvedTown {
+		return fmt.Errorf("%s is not a direct child of the town root (%s)\n\nMove or clone it into the town root first", absPath, townRoot)
+	}
+
+	return runRigAdopt(cmd, []string{filepath.Base(absPath)})
+}
+
+func runMayorAudit(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	registered := make(map[string]bool, len(rigsConfig.Rigs))
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		registered[name] = true
+		names = append(names, name)
+	}
+
+	candidates, err := mayor.FindUnregisteredRigs(townRoot, registered)
+	if err != nil {
+		return fmt.Errorf("scanning town root: %w", err)
+	}
+	missing := mayor.MissingRigs(townRoot, names)
+
+	if mayorAuditJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Unregistered []mayor.RigCandidate `json:"unregistered"`
+			Missing      []string             `json:"missing"`
+		}{candidates, missing}); err != nil {
+			return err
+		}
+	} else {
+		if len(candidates) == 0 && len(missing) == 0 {
+			fmt.Printf("%s All rig directories are registered (%d rigs)\n", style.Success.Render("✓"), len(names))
+			return nil
+		}
+		if len(candidates) > 0 {
+			fmt.Printf("%s\n", style.Bold.Render("Unregistered rig-like directories:"))
+			for _, c := range candidates {
+				fmt.Printf("  %s %s %s\n", style.Warning.Render("?"), c.Name, style.Dim.Render("("+strings.Join(c.Reasons, ", ")+")"))
+				fmt.Printf("    Adopt with: gt mayor adopt %s\n", c.Name)
+			}
+		}
+		if len(missing) > 0 {
+			fmt.Printf("%s\n", style.Bold.Render("Registered rigs with no directory:"))
+			for _, name := range missing {
+				fmt.Printf("  %s %s\n", style.Error.Render("✗"), name)
+				fmt.Printf("    Remove with: gt rig remove %s\n", name)
+			}
+		}
+	}
+
+	if len(candidates) > 0 || len(missing) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
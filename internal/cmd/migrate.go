@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/migrate"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	migrateStatusJSON  bool
+	migrateApplyDryRun bool
+	migrateApplyTo     int
+)
+
+var migrateCmd = &cobra.Command{
+	Use:     "migrate",
+	GroupID: GroupConfig,
+	Short:   "Apply town layout migrations",
+	RunE:    requireSubcommand,
+	Long: `Apply versioned town layout migrations.
+
+When a gt upgrade changes the town layout (renamed directories, new
+registries), it ships a migration step. The town records the layout
+version it has reached in settings/config.json (layout_version), and
+'gt migrate apply' runs the pending steps in order.
+
+Commands:
+  status   Show the current layout version and pending steps
+  apply    Run pending steps (--dry-run to preview)`,
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show layout version and pending migrations",
+	Long: `Show the town's layout version and every migration step, marking
+which have been applied.
+
+Examples:
+  gt migrate status
+  gt migrate status --json`,
+	RunE: runMigrateStatus,
+}
+
+var migrateApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Run pending layout migrations",
+	Long: `Run pending layout migrations in order.
+
+The recorded layout version is bumped after each step, so an interrupted
+run resumes where it stopped. Steps move rather than delete data.
+
+Examples:
+  gt migrate apply --dry-run    # Show what would change
+  gt migrate apply              # Apply all pending steps
+  gt migrate apply --to 1       # Apply steps up to version 1`,
+	RunE: runMigrateApply,
+}
+
+func init() {
+	migrateStatusCmd.Flags().BoolVar(&migrateStatusJSON, "json", false, "Output as JSON")
+	migrateApplyCmd.Flags().BoolVarP(&migrateApplyDryRun, "dry-run", "n", false, "Show what would change without applying")
+	migrateApplyCmd.Flags().IntVar(&migrateApplyTo, "to", 0, "Stop after this version (default: latest)")
+
+	migrateCmd.AddCommand(migrateStatusCmd)
+	migrateCmd.AddCommand(migrateApplyCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// migrateStepStatus is the JSON form of one step in 'gt migrate status'.
+type migrateStepStatus struct {
+	Version     int    `json:"version"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Applied     bool   `json:"applied"`
+}
+
+func runMigrateStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	current, err := migrate.CurrentVersion(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var stepStatus []migrateStepStatus
+	pending := 0
+	for _, s := range migrate.Steps() {
+		applied := s.Version <= current
+		if !applied {
+			pending++
+		}
+		stepStatus = append(stepStatus, migrateStepStatus{
+			Version:     s.Version,
+			Name:        s.Name,
+			Description: s.Description,
+			Applied:     applied,
+		})
+	}
+
+	if migrateStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"current": current,
+			"latest":  migrate.Latest(),
+			"steps":   stepStatus,
+		})
+	}
+
+	fmt.Printf("%s Layout version %d (latest %d)\n\n", style.Bold.Render("Town:"), current, migrate.Latest())
+	for _, s := range stepStatus {
+		mark := style.Dim.Render("○")
+		if s.Applied {
+			mark = style.Success.Render("✓")
+		}
+		fmt.Printf("  %s %3d  %-24s %s\n", mark, s.Version, s.Name, style.Dim.Render(s.Description))
+	}
+	fmt.Println()
+	if pending == 0 {
+		fmt.Printf("%s Up to date\n", style.Success.Render("✓"))
+	} else {
+		fmt.Printf("%d pending step(s). Run 'gt migrate apply --dry-run' to preview.\n", pending)
+	}
+	return nil
+}
+
+func runMigrateApply(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	results, err := migrate.Apply(townRoot, migrateApplyTo, migrateApplyDryRun)
+	for _, r := range results {
+		label := fmt.Sprintf("%d %s", r.Step.Version, r.Step.Name)
+		if migrateApplyDryRun {
+			fmt.Printf("%s Would apply %s\n", style.Dim.Render("○"), label)
+		} else {
+			fmt.Printf("%s Applied %s\n", style.Success.Render("✓"), label)
+		}
+		if len(r.Actions) == 0 {
+			fmt.Printf("    %s\n", style.Dim.Render("(nothing to change)"))
+		}
+		for _, a := range r.Actions {
+			fmt.Printf("    %s\n", a)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Printf("%s No pending migrations\n", style.Success.Render("✓"))
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -331,7 +332,7 @@ func parseIntSimple(s string) (int, error) {
 func updateAgentHeartbeat(agentBead, beadsDir string) error {
 	cmd := exec.Command("bd", "agent", "heartbeat", agentBead)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	return cmd.Run()
+	return cmdtrace.Run(cmd)
 }
 
 // setAgentIdleCycles sets the idle:N label on an agent bead.
@@ -365,7 +366,7 @@ func setAgentIdleCycles(agentBead, beadsDir string, cycles int) error {
 	cmd := exec.Command("bd", args...)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return fmt.Errorf("setting idle label: %w", err)
 	}
 
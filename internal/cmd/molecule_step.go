@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -406,7 +407,7 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 	pinCmd := exec.Command("bd", "update", nextStep.ID, "--status=pinned", "--assignee="+agentID)
 	pinCmd.Dir = gitRoot
 	pinCmd.Stderr = os.Stderr
-	if err := pinCmd.Run(); err != nil {
+	if err := cmdtrace.Run(pinCmd); err != nil {
 		return fmt.Errorf("pinning next step: %w", err)
 	}
 
@@ -485,7 +486,7 @@ func handleParallelSteps(cwd, townRoot, workDir string, steps []*beads.Issue, dr
 		markCmd := exec.Command("bd", "update", step.ID, "--status=in_progress")
 		markCmd.Dir = gitRoot
 		markCmd.Stderr = os.Stderr
-		if err := markCmd.Run(); err != nil {
+		if err := cmdtrace.Run(markCmd); err != nil {
 			style.PrintWarning("could not mark step %s as in_progress: %v", step.ID, err)
 		}
 	}
@@ -579,7 +580,7 @@ func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error
 			unpinCmd := exec.Command("bd", "update", pinnedBeads[0].ID, "--status=open")
 			unpinCmd.Dir = gitRoot
 			unpinCmd.Stderr = os.Stderr
-			if err := unpinCmd.Run(); err != nil {
+			if err := cmdtrace.Run(unpinCmd); err != nil {
 				style.PrintWarning("could not unpin bead: %v", err)
 			} else {
 				fmt.Printf("%s Work unpinned\n", style.Bold.Render("✓"))
@@ -594,7 +595,7 @@ func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error
 		doneCmd := exec.Command("gt", "done", "--exit", "DEFERRED")
 		doneCmd.Stdout = os.Stdout
 		doneCmd.Stderr = os.Stderr
-		return doneCmd.Run()
+		return cmdtrace.Run(doneCmd)
 	}
 
 	// For other roles, just print completion message
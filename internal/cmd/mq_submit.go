@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -296,7 +297,7 @@ Please verify state and execute lifecycle action.
 	)
 	cmd.Dir = townRoot
 
-	if out, err := cmd.CombinedOutput(); err != nil {
+	if out, err := cmdtrace.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("sending lifecycle request: %w: %s", err, string(out))
 	}
 	fmt.Printf("%s Sent shutdown request to %s\n", style.Bold.Render("✓"), manager)
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/offline"
+	"github.com/steveyegge/gastown/internal/scm"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Offline flags
+var (
+	offlineFlag bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&offlineFlag, "offline", false,
+		"Make no GitHub or other network calls; PR context comes from the local clone; also set by "+offline.EnvVar+"=1")
+}
+
+// applyOffline turns on offline mode for --offline. Child gt processes
+// inherit it through the environment.
+func applyOffline() {
+	if offlineFlag {
+		offline.Enable()
+	}
+}
+
+// offlineNotes are the notes already printed, so each is said once.
+var offlineNotes sync.Map
+
+// noteOffline tells the user, once, what context an offline command ran
+// without.
+func noteOffline(note string) {
+	if _, seen := offlineNotes.LoadOrStore(note, true); seen {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s Offline: %s\n", style.Dim.Render("○"), note)
+}
+
+// localPRProvider returns the provider offline runs read PRs with: the
+// rig's canonical clone, else the rig directory, else the working
+// directory outside a town.
+func localPRProvider(rigPath string) scm.Provider {
+	dir := "."
+	if rigPath != "" {
+		dir = rigPath
+		clone := filepath.Join(rigPath, "mayor", constants.DirRig)
+		if info, err := os.Stat(clone); err == nil && info.IsDir() {
+			dir = clone
+		}
+	}
+	return &scm.Local{Dir: dir}
+}
+
+// noteLocalPR says where an offline run read a PR from and what it lacks.
+func noteLocalPR(p scm.Provider, prNumber int) {
+	l, ok := p.(*scm.Local)
+	if !ok {
+		return
+	}
+	ref, err := l.HeadRef(context.Background(), prNumber)
+	if err != nil {
+		return
+	}
+	noteOffline(fmt.Sprintf("PR #%d read from %s; its title is the head commit's subject, and its URL, state, and description are unavailable", prNumber, ref))
+}
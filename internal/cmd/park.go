@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -87,7 +88,7 @@ func runPark(cmd *cobra.Command, args []string) error {
 
 	// Verify gate exists and is open
 	gateCheck := exec.Command("bd", "gate", "show", gateID, "--json")
-	gateOutput, err := gateCheck.Output()
+	gateOutput, err := cmdtrace.Output(gateCheck)
 	if err != nil {
 		return fmt.Errorf("gate '%s' not found or not accessible", gateID)
 	}
@@ -169,7 +170,7 @@ func runPark(cmd *cobra.Command, args []string) error {
 
 	// Add agent as waiter on the gate
 	waitCmd := exec.Command("bd", "gate", "wait", gateID, "--notify", agentID)
-	if err := waitCmd.Run(); err != nil {
+	if err := cmdtrace.Run(waitCmd); err != nil {
 		// Not fatal - might already be a waiter
 		fmt.Printf("%s Note: could not add as waiter (may already be registered)\n", style.Dim.Render("⚠"))
 	}
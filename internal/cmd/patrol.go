@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -188,7 +189,7 @@ func queryPatrolDigests(targetDate time.Time) ([]PatrolCycleEntry, error) {
 		"--json",
 		"--limit=0", // Get all
 	)
-	listOutput, err := listCmd.Output()
+	listOutput, err := cmdtrace.Output(listCmd)
 	if err != nil {
 		if patrolDigestVerbose {
 			fmt.Fprintf(os.Stderr, "[patrol] bd list failed: %v\n", err)
@@ -304,7 +305,7 @@ func createPatrolDigestBead(digest PatrolDigest) (string, error) {
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating digest bead: %w\nOutput: %s", err, string(output))
 	}
@@ -313,7 +314,7 @@ func createPatrolDigestBead(digest PatrolDigest) (string, error) {
 
 	// Auto-close the digest (it's an audit record, not work)
 	closeCmd := exec.Command("bd", "close", digestID, "--reason=daily patrol digest")
-	_ = closeCmd.Run() // Best effort
+	_ = cmdtrace.Run(closeCmd) // Best effort
 
 	return digestID, nil
 }
@@ -329,7 +330,7 @@ func findExistingPatrolDigest(dateStr string) (string, error) {
 		"--json",
 		"--limit=50", // Recent events only
 	)
-	listOutput, err := listCmd.Output()
+	listOutput, err := cmdtrace.Output(listCmd)
 	if err != nil {
 		return "", err
 	}
@@ -373,7 +374,7 @@ func deletePatrolDigests(targetDate time.Time) (int, error) {
 	// Delete in batch
 	deleteArgs := append([]string{"delete", "--force"}, idsToDelete...)
 	deleteCmd := exec.Command("bd", deleteArgs...)
-	if err := deleteCmd.Run(); err != nil {
+	if err := cmdtrace.Run(deleteCmd); err != nil {
 		return 0, fmt.Errorf("deleting patrol digests: %w", err)
 	}
 
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -36,7 +37,7 @@ func findActivePatrol(cfg PatrolConfig) (patrolID, patrolLine string, found bool
 		cmdList.Stdout = &stdoutList
 		cmdList.Stderr = &stderrList
 
-		if err := cmdList.Run(); err != nil {
+		if err := cmdtrace.Run(cmdList); err != nil {
 			if errMsg := strings.TrimSpace(stderrList.String()); errMsg != "" {
 				fmt.Fprintf(os.Stderr, "bd list: %s\n", errMsg)
 			}
@@ -60,7 +61,7 @@ func findActivePatrol(cfg PatrolConfig) (patrolID, patrolLine string, found bool
 	cmdOpen.Stdout = &stdoutOpen
 	cmdOpen.Stderr = &stderrOpen
 
-	if err := cmdOpen.Run(); err != nil {
+	if err := cmdtrace.Run(cmdOpen); err != nil {
 		if errMsg := strings.TrimSpace(stderrOpen.String()); errMsg != "" {
 			fmt.Fprintf(os.Stderr, "bd list: %s\n", errMsg)
 		}
@@ -77,7 +78,7 @@ func findActivePatrol(cfg PatrolConfig) (patrolID, patrolLine string, found bool
 					var stdoutShow, stderrShow bytes.Buffer
 					cmdShow.Stdout = &stdoutShow
 					cmdShow.Stderr = &stderrShow
-					if err := cmdShow.Run(); err != nil {
+					if err := cmdtrace.Run(cmdShow); err != nil {
 						if errMsg := strings.TrimSpace(stderrShow.String()); errMsg != "" {
 							fmt.Fprintf(os.Stderr, "bd show: %s\n", errMsg)
 						}
@@ -110,7 +111,7 @@ func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
 	cmdCatalog.Stdout = &stdoutCatalog
 	cmdCatalog.Stderr = &stderrCatalog
 
-	if err := cmdCatalog.Run(); err != nil {
+	if err := cmdtrace.Run(cmdCatalog); err != nil {
 		errMsg := strings.TrimSpace(stderrCatalog.String())
 		if errMsg != "" {
 			return "", fmt.Errorf("failed to list formulas: %s", errMsg)
@@ -143,7 +144,7 @@ func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
 	cmdSpawn.Stdout = &stdoutSpawn
 	cmdSpawn.Stderr = &stderrSpawn
 
-	if err := cmdSpawn.Run(); err != nil {
+	if err := cmdtrace.Run(cmdSpawn); err != nil {
 		return "", fmt.Errorf("failed to create patrol wisp: %s", stderrSpawn.String())
 	}
 
@@ -169,7 +170,7 @@ func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
 	// Hook the wisp to the agent so gt mol status sees it
 	cmdPin := exec.Command("bd", "--no-daemon", "update", patrolID, "--status=hooked", "--assignee="+cfg.Assignee)
 	cmdPin.Dir = cfg.BeadsDir
-	if err := cmdPin.Run(); err != nil {
+	if err := cmdtrace.Run(cmdPin); err != nil {
 		return patrolID, fmt.Errorf("created wisp %s but failed to hook", patrolID)
 	}
 
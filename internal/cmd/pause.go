@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// overridePauseEnv is set by commands run with --override-pause so the
+// gt sling processes they spawn dispatch despite the town pause.
+const overridePauseEnv = "GT_OVERRIDE_PAUSE"
+
+// Town pause flags
+var (
+	townPauseReason string
+)
+
+var pauseCmd = &cobra.Command{
+	Use:     "pause",
+	GroupID: GroupServices,
+	Short:   "Halt new work dispatch town-wide",
+	Long: `Halt new work dispatch across the town, e.g. during an incident.
+
+While paused, gt sling and gt formula run refuse to dispatch unless given
+--override-pause, PR watches hold off re-running, and scheduled plugin
+runs are skipped. Nothing is torn down: running agents keep working and
+the daemon, witnesses, and refineries stay up. gt status shows a banner
+until the town is resumed with gt resume --town.
+
+Examples:
+  gt pause --reason "INC-42: upstream outage"
+  gt resume --town`,
+	Args: cobra.NoArgs,
+	RunE: runPause,
+}
+
+func init() {
+	pauseCmd.Flags().StringVar(&townPauseReason, "reason", "", "Why dispatch is paused (shown in status and refusals)")
+	rootCmd.AddCommand(pauseCmd)
+}
+
+func runPause(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	paused, state, err := mayor.IsTownPaused(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking pause state: %w", err)
+	}
+	if paused {
+		fmt.Printf("%s Town is already paused\n", style.Dim.Render("○"))
+		printTownPause(state)
+		return nil
+	}
+
+	pausedBy := "human"
+	if role := os.Getenv("GT_ROLE"); role != "" {
+		pausedBy = role
+	}
+	if err := mayor.PauseTown(townRoot, townPauseReason, pausedBy); err != nil {
+		return fmt.Errorf("pausing town: %w", err)
+	}
+
+	fmt.Printf("%s Town paused: no new work will be dispatched\n", style.Bold.Render("⏸️"))
+	if townPauseReason != "" {
+		fmt.Printf("  Reason: %s\n", townPauseReason)
+	}
+	fmt.Printf("Resume with: %s\n", style.Dim.Render("gt resume --town"))
+	return nil
+}
+
+// runResumeTown lifts a town pause (gt resume --town).
+func runResumeTown() error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	paused, _, err := mayor.IsTownPaused(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking pause state: %w", err)
+	}
+	if !paused {
+		fmt.Printf("%s Town is not paused\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	if err := mayor.ResumeTown(townRoot); err != nil {
+		return fmt.Errorf("resuming town: %w", err)
+	}
+	fmt.Printf("%s Town resumed: work dispatch is allowed again\n", style.Bold.Render("▶️"))
+	return nil
+}
+
+// printTownPause prints the details of a town pause.
+func printTownPause(state *mayor.TownPause) {
+	if state.Reason != "" {
+		fmt.Printf("  Reason: %s\n", state.Reason)
+	}
+	fmt.Printf("  Paused at: %s\n", state.PausedAt.Format(time.RFC3339))
+	if state.PausedBy != "" {
+		fmt.Printf("  Paused by: %s\n", state.PausedBy)
+	}
+}
+
+// checkTownPause returns an error if dispatch is paused in the town and
+// override is false. With override, it marks the environment so spawned
+// gt sling processes dispatch too. A pause file that can't be read is
+// treated as paused: an incident stop must not fail open.
+func checkTownPause(townRoot string, override bool) error {
+	if override || os.Getenv(overridePauseEnv) != "" {
+		_ = os.Setenv(overridePauseEnv, "1")
+		return nil
+	}
+	paused, state, err := mayor.IsTownPaused(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking town pause: %w", err)
+	}
+	if !paused {
+		return nil
+	}
+	msg := "town is paused"
+	if state.Reason != "" {
+		msg += " (" + state.Reason + ")"
+	}
+	return fmt.Errorf("%s; run 'gt resume --town' or pass --override-pause", msg)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/mayor"
+)
+
+func TestCheckTownPause(t *testing.T) {
+	t.Setenv(overridePauseEnv, "")
+	town := t.TempDir()
+
+	if err := checkTownPause(town, false); err != nil {
+		t.Fatalf("unpaused town: %v", err)
+	}
+
+	if err := mayor.PauseTown(town, "INC-42", "human"); err != nil {
+		t.Fatal(err)
+	}
+	err := checkTownPause(town, false)
+	if err == nil {
+		t.Fatal("paused town: want error")
+	}
+	for _, want := range []string{"INC-42", "--override-pause", "gt resume --town"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	// Overriding marks the environment so spawned slings dispatch too.
+	if err := checkTownPause(town, true); err != nil {
+		t.Fatalf("override: %v", err)
+	}
+	if os.Getenv(overridePauseEnv) == "" {
+		t.Errorf("%s not set after override", overridePauseEnv)
+	}
+	if err := checkTownPause(town, false); err != nil {
+		t.Errorf("inherited override: %v", err)
+	}
+}
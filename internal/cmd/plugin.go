@@ -17,12 +17,13 @@ import (
 
 // Plugin command flags
 var (
-	pluginListJSON    bool
-	pluginShowJSON    bool
-	pluginRunForce    bool
-	pluginRunDryRun   bool
-	pluginHistoryJSON bool
-	pluginHistoryLimit int
+	pluginListJSON         bool
+	pluginShowJSON         bool
+	pluginRunForce         bool
+	pluginRunOverridePause bool
+	pluginRunDryRun        bool
+	pluginHistoryJSON      bool
+	pluginHistoryLimit     int
 )
 
 var pluginCmd = &cobra.Command{
@@ -88,7 +89,9 @@ var pluginRunCmd = &cobra.Command{
 	Long: `Manually trigger a plugin to run.
 
 By default, checks if the gate would allow execution and informs you
-if it wouldn't. Use --force to bypass gate checks.
+if it wouldn't. Use --force to bypass gate checks. While the town is
+paused (gt pause), plugins don't run unless --override-pause is given;
+during a maintenance window they are queued to run after it closes.
 
 Examples:
   gt plugin run rebuild-gt              # Run if gate allows
@@ -123,6 +126,7 @@ func init() {
 	// Run subcommand flags
 	pluginRunCmd.Flags().BoolVar(&pluginRunForce, "force", false, "Bypass gate check")
 	pluginRunCmd.Flags().BoolVar(&pluginRunDryRun, "dry-run", false, "Show what would happen without executing")
+	pluginRunCmd.Flags().BoolVar(&pluginRunOverridePause, "override-pause", false, "Run even though the town is paused (gt pause) or in a maintenance window")
 
 	// History subcommand flags
 	pluginHistoryCmd.Flags().BoolVar(&pluginHistoryJSON, "json", false, "Output as JSON")
@@ -416,6 +420,15 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Scheduled runs hold off while the town is paused (gt pause)
+	if err := checkTownPause(townRoot, pluginRunOverridePause); err != nil {
+		fmt.Printf("%s Not running plugin: %v\n", style.Warning.Render("⚠"), err)
+		return nil
+	}
+	if queued, err := queueForMaintenance(townRoot, pluginRunOverridePause); queued || err != nil {
+		return err
+	}
+
 	if !gateOpen && !pluginRunForce {
 		fmt.Printf("%s Gate closed: %s\n", style.Warning.Render("⚠"), gateReason)
 		fmt.Printf("  Use --force to bypass gate check\n")
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -1165,7 +1166,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		}
 		closeCmd := exec.Command("bd", closeArgs...)
 		closeCmd.Dir = filepath.Join(p.r.Path, "mayor", "rig")
-		if err := closeCmd.Run(); err != nil {
+		if err := cmdtrace.Run(closeCmd); err != nil {
 			// Non-fatal - agent bead might not exist
 			fmt.Printf("  %s agent bead not found or already closed\n", style.Dim.Render("○"))
 		} else {
@@ -14,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/style"
@@ -810,7 +811,7 @@ func queryAssignedIssues(rigPath, assignee, status string) ([]IssueInfo, error)
 
 	cmd := exec.Command("bd", args...)
 	cmd.Dir = rigPath
-	out, err := cmd.Output()
+	out, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Polecat pool flags
+var (
+	polecatPoolFill  bool
+	polecatPoolDrain bool
+)
+
+var polecatPoolCmd = &cobra.Command{
+	Use:   "pool <rig>",
+	Short: "Show or refill a rig's pool of ready worktrees",
+	Long: `Show, refill, or empty a rig's pool of ready polecat worktrees.
+
+With worktree_pool.size set in the rig's settings/config.json, the rig
+keeps that many clean worktrees checked out at the default branch under
+.runtime/worktree-pool/. New polecats take one instead of checking out
+the repository, and nuked polecats' worktrees are cleaned and returned
+while the pool has room. The daemon refills pools on each heartbeat.
+
+Examples:
+  gt polecat pool gastown           # Ready worktrees vs. configured size
+  gt polecat pool gastown --fill    # Fetch, refresh, and top up now
+  gt polecat pool gastown --drain   # Remove all pooled worktrees`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPool,
+}
+
+func init() {
+	polecatPoolCmd.Flags().BoolVar(&polecatPoolFill, "fill", false, "Refresh pooled worktrees and top the pool up to its size")
+	polecatPoolCmd.Flags().BoolVar(&polecatPoolDrain, "drain", false, "Remove all pooled worktrees")
+	polecatCmd.AddCommand(polecatPoolCmd)
+}
+
+func runPolecatPool(cmd *cobra.Command, args []string) error {
+	if polecatPoolFill && polecatPoolDrain {
+		return fmt.Errorf("--fill and --drain are mutually exclusive")
+	}
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	pool := polecat.NewWorktreePool(r.Path)
+
+	switch {
+	case polecatPoolDrain:
+		n := len(pool.Ready())
+		if err := pool.Drain(); err != nil {
+			return fmt.Errorf("draining pool: %w", err)
+		}
+		fmt.Printf("%s Removed %d pooled worktree(s) from %s\n", style.Success.Render("✓"), n, r.Name)
+		return nil
+	case polecatPoolFill:
+		if pool.Size() == 0 {
+			return fmt.Errorf("rig %s has no worktree pool; set worktree_pool.size in its settings", r.Name)
+		}
+		added, err := pool.Fill()
+		if err != nil {
+			return fmt.Errorf("filling pool: %w", err)
+		}
+		fmt.Printf("%s Added %d worktree(s); %d/%d ready\n", style.Success.Render("✓"), added, len(pool.Ready()), pool.Size())
+		return nil
+	}
+
+	ready := pool.Ready()
+	fmt.Printf("%s worktree pool: %d/%d ready\n", style.Bold.Render(r.Name), len(ready), pool.Size())
+	for _, path := range ready {
+		fmt.Printf("  %s\n", style.Dim.Render(filepath.Base(path)))
+	}
+	return nil
+}
@@ -12,9 +12,11 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -29,8 +31,10 @@ type SpawnedPolecatInfo struct {
 	Pane        string // Tmux pane ID (empty until StartSession is called)
 
 	// Internal fields for deferred session start
-	account string
-	agent   string
+	account   string
+	agent     string
+	env       map[string]string
+	container *formula.LegContainer
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -45,11 +49,13 @@ func (s *SpawnedPolecatInfo) SessionStarted() bool {
 
 // SlingSpawnOptions contains options for spawning a polecat via sling.
 type SlingSpawnOptions struct {
-	Force    bool   // Force spawn even if polecat has uncommitted work
-	Account  string // Claude Code account handle to use
-	Create   bool   // Create polecat if it doesn't exist (currently always true for sling)
-	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
-	Agent    string // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
+	Force     bool                  // Force spawn even if polecat has uncommitted work
+	Account   string                // Claude Code account handle to use
+	Create    bool                  // Create polecat if it doesn't exist (currently always true for sling)
+	HookBead  string                // Bead ID to set as hook_bead at spawn time (atomic assignment)
+	Agent     string                // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
+	Env       map[string]string     // Env injected into the agent process (per-formula/leg overrides)
+	Container *formula.LegContainer // Run the agent in this container instead of on the host
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -166,6 +172,8 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		Pane:        "", // Empty until StartSession is called
 		account:     opts.Account,
 		agent:       opts.Agent,
+		env:         opts.Env,
+		container:   opts.Container,
 	}, nil
 }
 
@@ -211,9 +219,11 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	fmt.Printf("Starting session for %s/%s...\n", s.RigName, s.PolecatName)
 	startOpts := polecat.SessionStartOptions{
 		RuntimeConfigDir: claudeConfigDir,
+		Env:              s.env,
+		Container:        s.container,
 	}
 	if s.agent != "" {
-		cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(s.RigName, s.PolecatName, r.Path, "", s.agent)
+		cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(s.RigName, s.PolecatName, r.Path, "", s.agent, s.env)
 		if err != nil {
 			return "", err
 		}
@@ -224,9 +234,13 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	}
 
 	// Wait for runtime to be fully ready before returning.
-	runtimeConfig := config.LoadRuntimeConfig(r.Path)
-	if err := t.WaitForRuntimeReady(s.SessionName, runtimeConfig, 30*time.Second); err != nil {
-		fmt.Printf("Warning: runtime may not be fully ready: %v\n", err)
+	// Only tmux sessions have a pane to watch.
+	usesTmux := polecatSessMgr.Backend().Type() == session.BackendTmux
+	if usesTmux {
+		runtimeConfig := config.LoadRuntimeConfig(r.Path)
+		if err := t.WaitForRuntimeReady(s.SessionName, runtimeConfig, 30*time.Second); err != nil {
+			fmt.Printf("Warning: runtime may not be fully ready: %v\n", err)
+		}
 	}
 
 	// Update agent state
@@ -241,7 +255,11 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 		fmt.Printf("Warning: could not update issue status to in_progress: %v\n", err)
 	}
 
-	// Get pane
+	// Get pane. Other backends have none, so the agent finds its work
+	// through gt prime instead of a nudge.
+	if !usesTmux {
+		return "", nil
+	}
 	pane, err := getSessionPane(s.SessionName)
 	if err != nil {
 		return "", fmt.Errorf("getting pane for %s: %w", s.SessionName, err)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
@@ -350,7 +351,7 @@ func runBdPrime(workDir string) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		// Skip if bd prime fails (beads might not be available)
 		// But log stderr if present for debugging
 		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
@@ -376,7 +377,7 @@ func runMailCheckInject(workDir string) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		// Skip if mail check fails, but log stderr for debugging
 		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
 			fmt.Fprintf(os.Stderr, "gt mail check: %s\n", errMsg)
@@ -490,6 +491,9 @@ func checkSlungWork(ctx RoleContext) bool {
 			fmt.Printf("    %s\n", line)
 		}
 	}
+	if attachment != nil && attachment.AttachedContext != "" {
+		printSlingPayload(attachment.AttachedContext)
+	}
 	fmt.Println()
 
 	// If molecule attached, show molecule context prominently INSTEAD of bd show
@@ -515,7 +519,7 @@ func checkSlungWork(ctx RoleContext) bool {
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
-		if err := cmd.Run(); err != nil {
+		if err := cmdtrace.Run(cmd); err != nil {
 			if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
 				fmt.Fprintf(os.Stderr, "  bd show %s: %s\n", hookedBead.ID, errMsg)
 			} else {
@@ -715,7 +719,7 @@ func checkPendingEscalations(ctx RoleContext) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		// Silently skip - escalation check is best-effort
 		return
 	}
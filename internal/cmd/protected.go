@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"golang.org/x/term"
+)
+
+// confirmProtected guards a destructive operation. If the town declares op
+// (on target) protected, a human must type phrase or pass --force; an agent
+// (GT_ROLE set) must pass --force and have its role listed in allow_roles.
+// Returns nil when the operation may proceed.
+func confirmProtected(townRoot, op, target, phrase string, force bool) error {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		settings = nil // fall back to the default protection list
+	}
+	if !settings.IsProtected(op, target) {
+		return nil
+	}
+
+	label := op
+	if target != "" {
+		label = fmt.Sprintf("%s (%s)", op, target)
+	}
+
+	if envRole := os.Getenv("GT_ROLE"); envRole != "" {
+		role, _, _ := parseRoleString(envRole)
+		if !settings.RoleAllowedProtected(string(role)) && !settings.RoleAllowedProtected(envRole) {
+			return fmt.Errorf("%s is a protected operation and role %q may not run it (see protected.allow_roles in settings/config.json)", label, envRole)
+		}
+		if !force {
+			return fmt.Errorf("%s is a protected operation; rerun with --force", label)
+		}
+		return nil
+	}
+
+	if force {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("%s is a protected operation; rerun with --force to run non-interactively", label)
+	}
+
+	fmt.Println()
+	fmt.Printf("%s %s is a protected operation.\n", style.Warning.Render("⚠"), style.Bold.Render(label))
+	fmt.Printf("Type %s to confirm: ", style.Bold.Render(phrase))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if strings.TrimSpace(answer) != phrase {
+		return fmt.Errorf("confirmation did not match; %s aborted", op)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestConfirmProtected(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Protected = &config.ProtectedConfig{
+		Operations: []string{"down.nuke", "formula.reset:mol-*"},
+		AllowRoles: []string{"mayor"},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		role    string
+		op      string
+		target  string
+		force   bool
+		wantErr string
+	}{
+		{name: "unprotected target", op: "formula.reset", target: "shiny"},
+		{name: "human with force", op: "down.nuke", force: true},
+		{name: "human without terminal", op: "down.nuke", wantErr: "rerun with --force"},
+		{name: "allowed role with force", role: "mayor", op: "down.nuke", force: true},
+		{name: "allowed role without force", role: "mayor", op: "down.nuke", wantErr: "rerun with --force"},
+		{name: "disallowed role", role: "gastown/polecats/toast", op: "formula.reset", target: "mol-polecat-work", force: true, wantErr: "may not run it"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GT_ROLE", tt.role)
+			err := confirmProtected(townRoot, tt.op, tt.target, "confirm", tt.force)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("confirmProtected() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("confirmProtected() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
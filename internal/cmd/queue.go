@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Queue flags
+var (
+	queueListJSON  bool
+	queueListRig   string
+	queueDropClose bool
+)
+
+var queueCmd = &cobra.Command{
+	Use:     "queue",
+	GroupID: GroupWork,
+	Short:   "Inspect and manage formula legs waiting for polecats",
+	Long: `Inspect and manage the town's dispatch queue: formula legs waiting for a
+free polecat on their rig.
+
+A rig runs at most max_polecats polecats at once. Legs beyond that wait
+here and are dispatched high priority first, then normal, then low, oldest
+first within a priority. Low priority legs paused for urgent work
+(gt formula run --priority high --preempt) wait here too, and resume ahead
+of waiting legs of the same priority.
+
+Hold legs to keep them out of the way during busy periods, release them
+when there is room, or drop the ones that are no longer needed.`,
+	RunE: requireSubcommand,
+}
+
+var queueListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List queued legs in dispatch order",
+	Long: `List queued legs per rig in the order they will get a polecat, with
+their priority, state (waiting, paused, or held), time in the queue, and
+originating convoy.
+
+Examples:
+  gt queue list
+  gt queue list --rig gastown
+  gt queue list --json`,
+	Args: cobra.NoArgs,
+	RunE: runQueueList,
+}
+
+var queueHoldCmd = &cobra.Command{
+	Use:   "hold <leg-bead>...",
+	Short: "Keep queued legs from being dispatched",
+	Long: `Hold queued legs. Held legs keep their place in the queue but are not
+dispatched, and held paused legs stay paused, until released.
+
+Examples:
+  gt queue hold hq-leg-abc
+  gt queue hold hq-leg-abc hq-leg-def`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQueueHold,
+}
+
+var queueReleaseCmd = &cobra.Command{
+	Use:   "release <leg-bead>...",
+	Short: "Let held legs be dispatched again",
+	Long: `Release held legs, then dispatch the queue so released legs get any free
+polecats right away.
+
+Examples:
+  gt queue release hq-leg-abc`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQueueRelease,
+}
+
+var queueDropCmd = &cobra.Command{
+	Use:   "drop <leg-bead>...",
+	Short: "Remove legs from the queue",
+	Long: `Remove legs from the queue. A dropped waiting leg is never dispatched;
+its bead stays open unless --close is given, so the convoy can still land
+once it is closed or slung by hand. A dropped paused leg resumes at once,
+even if its rig is at max_polecats.
+
+Examples:
+  gt queue drop hq-leg-abc
+  gt queue drop hq-leg-abc --close`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQueueDrop,
+}
+
+func init() {
+	queueListCmd.Flags().BoolVar(&queueListJSON, "json", false, "Output as JSON")
+	queueListCmd.Flags().StringVar(&queueListRig, "rig", "", "Only list legs for this rig")
+	queueDropCmd.Flags().BoolVar(&queueDropClose, "close", false, "Close dropped waiting legs' beads so their convoy can land")
+
+	queueCmd.AddCommand(queueListCmd)
+	queueCmd.AddCommand(queueHoldCmd)
+	queueCmd.AddCommand(queueReleaseCmd)
+	queueCmd.AddCommand(queueDropCmd)
+	rootCmd.AddCommand(queueCmd)
+}
+
+// QueueItem is a queued leg in gt queue list --json.
+type QueueItem struct {
+	BeadID   string          `json:"bead_id"`
+	Rig      string          `json:"rig"`
+	Priority convoy.Priority `json:"priority"`
+	State    string          `json:"state"` // waiting, paused, or held
+	ConvoyID string          `json:"convoy_id,omitempty"`
+	Since    time.Time       `json:"since"`
+	Session  string          `json:"session,omitempty"` // Paused legs' polecat session
+}
+
+// queueItems lists a queue's legs per rig in dispatch order.
+func queueItems(q *convoy.Queue, rigFilter string) []QueueItem {
+	var items []QueueItem
+	for _, rigName := range q.Rigs() {
+		if rigFilter != "" && rigName != rigFilter {
+			continue
+		}
+		for _, e := range q.Order(rigName) {
+			item := QueueItem{
+				BeadID:   e.BeadID(),
+				Rig:      rigName,
+				Priority: e.Priority(),
+				State:    e.State(),
+				ConvoyID: e.ConvoyID(),
+				Since:    e.Since(),
+			}
+			if e.Paused != nil {
+				item.Session = e.Paused.Session
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func runQueueList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	q, err := convoy.LoadQueue(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading dispatch queue: %w", err)
+	}
+	items := queueItems(q, queueListRig)
+
+	if queueListJSON {
+		if items == nil {
+			items = []QueueItem{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No legs queued.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LEG\tRIG\tPRIORITY\tSTATE\tAGE\tCONVOY")
+	for _, item := range items {
+		convoyID := item.ConvoyID
+		if convoyID == "" {
+			convoyID = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", item.BeadID, item.Rig, item.Priority, item.State,
+			formatWorkerAge(time.Since(item.Since)), convoyID)
+	}
+	return w.Flush()
+}
+
+func runQueueHold(cmd *cobra.Command, args []string) error {
+	return setQueueHeld(args, true)
+}
+
+func runQueueRelease(cmd *cobra.Command, args []string) error {
+	if err := setQueueHeld(args, false); err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	dispatchLegQueue(townRoot, legDispatchOptions{})
+	return nil
+}
+
+// setQueueHeld holds or releases queued legs. Nothing changes unless every
+// leg is queued.
+func setQueueHeld(beadIDs []string, held bool) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return convoy.UpdateQueue(townRoot, func(q *convoy.Queue) error {
+		if err := requireQueued(q, beadIDs); err != nil {
+			return err
+		}
+		for _, id := range beadIDs {
+			q.SetHeld(id, held)
+			if held {
+				fmt.Printf("%s Held %s\n", style.Warning.Render("⏸"), id)
+			} else {
+				fmt.Printf("%s Released %s\n", style.Bold.Render("▶"), id)
+			}
+		}
+		return nil
+	})
+}
+
+func runQueueDrop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var dropped []convoy.Entry
+	err = convoy.UpdateQueue(townRoot, func(q *convoy.Queue) error {
+		if err := requireQueued(q, args); err != nil {
+			return err
+		}
+		for _, id := range args {
+			e, _ := q.Lookup(id)
+			q.Remove(id)
+			dropped = append(dropped, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var toClose []string
+	for _, e := range dropped {
+		if e.Paused != nil {
+			if err := resumeSessionFn(townRoot, e.Paused.Session); err != nil {
+				fmt.Printf("%s Dropped %s but failed to resume %s: %v\n",
+					style.Dim.Render("Warning:"), e.BeadID(), e.Paused.Session, err)
+				continue
+			}
+			fmt.Printf("%s Dropped %s; its polecat resumed\n", style.Bold.Render("✓"), e.BeadID())
+			continue
+		}
+		fmt.Printf("%s Dropped %s\n", style.Bold.Render("✓"), e.BeadID())
+		if queueDropClose {
+			toClose = append(toClose, e.BeadID())
+		}
+	}
+	if len(toClose) > 0 {
+		if err := beads.New(filepath.Join(townRoot, ".beads")).CloseWithReason("dropped from the dispatch queue", toClose...); err != nil {
+			return fmt.Errorf("closing dropped legs: %w", err)
+		}
+		fmt.Printf("  Closed %d leg bead(s)\n", len(toClose))
+	}
+	return nil
+}
+
+// requireQueued returns an error naming the first bead that isn't queued.
+func requireQueued(q *convoy.Queue, beadIDs []string) error {
+	for _, id := range beadIDs {
+		if !q.Has(id) {
+			return fmt.Errorf("%s is not in the dispatch queue (see gt queue list)", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/convoy"
+)
+
+func TestQueueItems(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := &convoy.Queue{}
+	q.Add(convoy.QueuedLeg{BeadID: "hq-leg-a", Rig: "gastown", ConvoyID: "hq-cv-1", QueuedAt: base})
+	q.Add(convoy.QueuedLeg{BeadID: "hq-leg-b", Rig: "beads", Priority: convoy.PriorityHigh, QueuedAt: base})
+	q.Paused = append(q.Paused, convoy.PausedLeg{
+		BeadID: "hq-leg-c", Rig: "gastown", Session: "gt-gastown-nux", Priority: convoy.PriorityLow, PausedAt: base,
+	})
+	q.SetHeld("hq-leg-a", true)
+
+	items := queueItems(q, "")
+	if len(items) != 3 {
+		t.Fatalf("queueItems() = %+v, want 3 legs", items)
+	}
+	if items[0].BeadID != "hq-leg-b" || items[0].Priority != convoy.PriorityHigh {
+		t.Errorf("first item = %+v, want the beads rig's high priority leg", items[0])
+	}
+	if items[1].BeadID != "hq-leg-a" || items[1].State != "held" || items[1].ConvoyID != "hq-cv-1" {
+		t.Errorf("second item = %+v, want held hq-leg-a of hq-cv-1", items[1])
+	}
+	if items[2].State != "paused" || items[2].Session != "gt-gastown-nux" {
+		t.Errorf("third item = %+v, want the paused leg with its session", items[2])
+	}
+
+	if got := queueItems(q, "beads"); len(got) != 1 || got[0].BeadID != "hq-leg-b" {
+		t.Errorf("queueItems(--rig beads) = %+v", got)
+	}
+}
+
+func TestRequireQueued(t *testing.T) {
+	q := &convoy.Queue{}
+	q.Add(convoy.QueuedLeg{BeadID: "hq-leg-a", Rig: "gastown"})
+	if err := requireQueued(q, []string{"hq-leg-a"}); err != nil {
+		t.Errorf("requireQueued(queued) = %v", err)
+	}
+	if err := requireQueued(q, []string{"hq-leg-a", "hq-leg-x"}); err == nil {
+		t.Error("requireQueued() accepted a leg that isn't queued")
+	}
+}
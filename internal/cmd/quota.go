@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// Quota command flags
+var (
+	quotaStatusRig  string
+	quotaStatusJSON bool
+)
+
+var quotaCmd = &cobra.Command{
+	Use:     "quota",
+	GroupID: GroupConfig,
+	Short:   "Inspect per-rig formula run quotas",
+	RunE:    requireSubcommand,
+	Long: `Inspect per-rig formula run quotas.
+
+Quotas are set in a rig's settings/config.json and are checked by
+gt formula run before any convoy is created:
+
+  "quota": {
+    "runs_per_hour": 10,          # formula runs started in a rolling hour
+    "max_concurrent_convoys": 3,  # formula convoys open at once
+    "daily_cost_usd": 50          # recorded session cost per calendar day
+  }
+
+Omitted or zero limits are not enforced.
+
+Formulas that set concurrency_class are also capped town-wide by class,
+in the town's settings/config.json:
+
+  "concurrency": {"classes": {"heavy-build": 1}}
+
+Commands:
+  status  Show each rig's limits and current usage`,
+}
+
+var quotaStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show quota limits and current usage per rig",
+	Long: `Show quota limits and current usage for every rig (or one rig).
+
+Usage is measured from the rig's witness audit log (formula runs), open
+convoy beads (concurrent convoys), and ~/.gt/costs.jsonl (daily cost).
+
+Examples:
+  gt quota status
+  gt quota status --rig gastown
+  gt quota status --json`,
+	RunE: runQuotaStatus,
+}
+
+func init() {
+	quotaStatusCmd.Flags().StringVar(&quotaStatusRig, "rig", "", "Only show this rig")
+	quotaStatusCmd.Flags().BoolVar(&quotaStatusJSON, "json", false, "Output as JSON")
+
+	quotaCmd.AddCommand(quotaStatusCmd)
+	rootCmd.AddCommand(quotaCmd)
+}
+
+// rigQuotaStatus is one rig's row in gt quota status.
+type rigQuotaStatus struct {
+	Rig        string              `json:"rig"`
+	Quota      *config.QuotaConfig `json:"quota,omitempty"`
+	Usage      config.QuotaUsage   `json:"usage"`
+	Violations []string            `json:"violations,omitempty"`
+}
+
+func runQuotaStatus(cmd *cobra.Command, args []string) error {
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+
+	var statuses []rigQuotaStatus
+	for _, r := range rigs {
+		if quotaStatusRig != "" && r.Name != quotaStatusRig {
+			continue
+		}
+		quota := loadRigQuota(r.Path)
+		usage := rigQuotaUsage(townRoot, r.Name)
+		statuses = append(statuses, rigQuotaStatus{
+			Rig:        r.Name,
+			Quota:      quota,
+			Usage:      usage,
+			Violations: quota.Violations(usage),
+		})
+	}
+	if quotaStatusRig != "" && len(statuses) == 0 {
+		return rigNotFoundError(quotaStatusRig, rigNames(rigs))
+	}
+
+	if quotaStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	for _, s := range statuses {
+		marker := style.Success.Render("✓")
+		if len(s.Violations) > 0 {
+			marker = style.Error.Render("✗")
+		}
+		fmt.Printf("%s %s\n", marker, style.Bold.Render(s.Rig))
+		q := s.Quota
+		if q == nil {
+			q = &config.QuotaConfig{}
+		}
+		fmt.Printf("    runs/hour:  %s\n", quotaCell(fmt.Sprintf("%d", s.Usage.RunsLastHour), q.RunsPerHour > 0, fmt.Sprintf("%d", q.RunsPerHour)))
+		fmt.Printf("    convoys:    %s\n", quotaCell(fmt.Sprintf("%d", s.Usage.OpenConvoys), q.MaxConcurrentConvoys > 0, fmt.Sprintf("%d", q.MaxConcurrentConvoys)))
+		cost := fmt.Sprintf("$%.2f", s.Usage.CostTodayUSD)
+		if s.Usage.CostUnavailable {
+			cost = "?"
+		}
+		fmt.Printf("    cost today: %s\n", quotaCell(cost, q.DailyCostUSD > 0, fmt.Sprintf("$%.2f", q.DailyCostUSD)))
+	}
+
+	// Concurrency classes span rigs, so they follow the per-rig rows
+	limits := loadConcurrencyConfig(townRoot)
+	if classes := limits.ClassNames(); len(classes) > 0 && quotaStatusRig == "" {
+		fmt.Printf("\n%s\n", style.Bold.Render("Concurrency classes (all rigs)"))
+		open, err := countOpenClassConvoys(filepath.Join(townRoot, ".beads"))
+		if err != nil {
+			fmt.Printf("  %s Could not count open convoys: %v\n", style.Dim.Render("Warning:"), err)
+			return nil
+		}
+		for _, class := range classes {
+			marker := style.Success.Render("✓")
+			if limits.Check(class, open[class]) != nil {
+				marker = style.Error.Render("✗")
+			}
+			fmt.Printf("  %s %s: %d / %d convoys open\n", marker, class, open[class], limits.Limit(class))
+		}
+	}
+	return nil
+}
+
+// quotaCell renders "used / limit", or "used (no limit)" when unset.
+func quotaCell(used string, limited bool, limit string) string {
+	if !limited {
+		return used + " " + style.Dim.Render("(no limit)")
+	}
+	return used + " / " + limit
+}
+
+// loadRigQuota returns the rig's quota config, or nil if none is set.
+func loadRigQuota(rigPath string) *config.QuotaConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Quota
+}
+
+// checkRigQuota refuses new formula work for a rig whose quota is exhausted.
+// Rigs without a quota, or outside a town, are never refused.
+//
+// When the rig has a quota, the check holds the rig's quota lock until the
+// returned release is called. Callers release it once the run's formula.run
+// audit entry is recorded (or the run fails), so two runs can't both take
+// the last free slot. Release may be called more than once.
+func checkRigQuota(townRoot, rigName string) (release func(), err error) {
+	release = func() {}
+	if townRoot == "" || rigName == "" {
+		return release, nil
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+	quota := loadRigQuota(rigPath)
+	if quota == nil {
+		return release, nil
+	}
+	unlock, err := fsx.Lock(quotaLockPath(rigPath))
+	if err != nil {
+		return nil, fmt.Errorf("rig %s: %w", rigName, err)
+	}
+	release = sync.OnceFunc(unlock)
+	if err := quota.Check(rigQuotaUsage(townRoot, rigName)); err != nil {
+		release()
+		return nil, fmt.Errorf("rig %s: %w\n\nSee usage with: gt quota status --rig %s\nAdjust limits under \"quota\" in %s",
+			rigName, err, rigName, config.RigSettingsPath(rigPath))
+	}
+	return release, nil
+}
+
+// runHold is what a run's quota and concurrency class checks keep held:
+// the class lock until the run's convoy bead exists, and the rig's quota
+// lock until the run is recorded in the rig's audit log. Each release may
+// be called more than once, and the zero runHold holds nothing.
+type runHold struct {
+	quota, class func()
+}
+
+// takeRunHold checks the rig's quota and then the formula's concurrency
+// class, holding both on success.
+func takeRunHold(townRoot, rigName, class string) (runHold, error) {
+	releaseQuota, err := checkRigQuota(townRoot, rigName)
+	if err != nil {
+		return runHold{}, err
+	}
+	releaseClass, err := checkConcurrencyClass(townRoot, class)
+	if err != nil {
+		releaseQuota()
+		return runHold{}, err
+	}
+	return runHold{quota: releaseQuota, class: releaseClass}, nil
+}
+
+func (h runHold) releaseQuota() {
+	if h.quota != nil {
+		h.quota()
+	}
+}
+
+func (h runHold) releaseClass() {
+	if h.class != nil {
+		h.class()
+	}
+}
+
+// release drops whatever the hold still has.
+func (h runHold) release() {
+	h.releaseClass()
+	h.releaseQuota()
+}
+
+// quotaLockPath returns the file whose lock serializes a rig's quota
+// checks with the audit entries they count. It is not the audit log's own
+// lock, which witness.Record takes while the quota lock is held.
+func quotaLockPath(rigPath string) string {
+	return filepath.Join(rigPath, "witness", "quota")
+}
+
+// rigQuotaUsage measures a rig's current consumption. Each source is
+// best-effort: an unreadable source counts as zero usage.
+func rigQuotaUsage(townRoot, rigName string) config.QuotaUsage {
+	now := time.Now()
+	usage := config.QuotaUsage{
+		RunsLastHour: countRecentFormulaRuns(filepath.Join(townRoot, rigName), now.Add(-time.Hour)),
+		OpenConvoys:  countOpenRigConvoys(filepath.Join(townRoot, ".beads"), rigName),
+	}
+	entries, err := querySessionCostEntries(now)
+	if err != nil {
+		usage.CostUnavailable = true
+	}
+	for _, e := range entries {
+		if e.Rig == rigName {
+			usage.CostTodayUSD += e.CostUSD
+		}
+	}
+	return usage
+}
+
+// countRecentFormulaRuns counts formula.run audit entries at or after since.
+func countRecentFormulaRuns(rigPath string, since time.Time) int {
+	entries, err := witness.ReadAuditLog(rigPath)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, e := range entries {
+		if e.Action == witness.ActionFormulaRun && !e.Time.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// countOpenRigConvoys counts open formula convoys targeting rigName.
+// Formula convoys record their rig as a "Rig: <name>" description line.
+func countOpenRigConvoys(townBeads, rigName string) int {
+	listCmd := exec.Command("bd", "list", "--type=convoy", "--status=open", "--json")
+	listCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	listCmd.Stdout = &stdout
+	if err := cmdtrace.Run(listCmd); err != nil {
+		return 0
+	}
+
+	var convoys []struct {
+		ID          string `json:"id"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
+		return 0
+	}
+	count := 0
+	for _, c := range convoys {
+		if convoyTargetsRig(c.Description, rigName) {
+			count++
+		}
+	}
+	return count
+}
+
+// convoyTargetsRig reports whether a formula convoy description names rigName.
+func convoyTargetsRig(description, rigName string) bool {
+	for _, line := range strings.Split(description, "\n") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "Rig:"); ok && strings.TrimSpace(name) == rigName {
+			return true
+		}
+	}
+	return false
+}
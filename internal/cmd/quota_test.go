@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+func TestConvoyTargetsRig(t *testing.T) {
+	desc := "Formula convoy: shiny\n\nformula: shiny\nreview_id: abc\nLegs: 3\nRig: gastown"
+	if !convoyTargetsRig(desc, "gastown") {
+		t.Error("expected convoy to target gastown")
+	}
+	if convoyTargetsRig(desc, "beads") {
+		t.Error("convoy should not target beads")
+	}
+	if convoyTargetsRig("Tracking: hq-abc", "gastown") {
+		t.Error("convoy without Rig line should not match")
+	}
+}
+
+func TestCountRecentFormulaRuns(t *testing.T) {
+	rigPath := t.TempDir()
+	for _, action := range []string{witness.ActionFormulaRun, witness.ActionDoctorFix, witness.ActionFormulaRun} {
+		if err := witness.Record(rigPath, witness.AuditEntry{Action: action}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if got := countRecentFormulaRuns(rigPath, time.Now().Add(-time.Hour)); got != 2 {
+		t.Errorf("countRecentFormulaRuns = %d, want 2", got)
+	}
+	if got := countRecentFormulaRuns(rigPath, time.Now().Add(time.Minute)); got != 0 {
+		t.Errorf("countRecentFormulaRuns (future) = %d, want 0", got)
+	}
+}
+
+func TestCheckRigQuotaHoldsUntilRecorded(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	settings := config.NewRigSettings()
+	settings.Quota = &config.QuotaConfig{RunsPerHour: 1}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := checkRigQuota(townRoot, "gastown")
+	if err != nil {
+		t.Fatalf("first check: %v", err)
+	}
+	second := make(chan error, 1)
+	go func() {
+		release, err := checkRigQuota(townRoot, "gastown")
+		if err == nil {
+			release()
+		}
+		second <- err
+	}()
+
+	// The second check waits for the first run to be recorded
+	select {
+	case err := <-second:
+		t.Fatalf("second check returned (%v) while the quota was held", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := witness.Record(rigPath, witness.AuditEntry{Action: witness.ActionFormulaRun}); err != nil {
+		t.Fatal(err)
+	}
+	release()
+	release()
+	if err := <-second; err == nil {
+		t.Error("second check passed; want the used-up quota refused")
+	}
+}
+
+func TestRunHoldRelease(t *testing.T) {
+	runHold{}.release() // The zero hold holds nothing
+
+	var released []string
+	hold := runHold{
+		quota: sync.OnceFunc(func() { released = append(released, "quota") }),
+		class: sync.OnceFunc(func() { released = append(released, "class") }),
+	}
+	hold.releaseClass() // Once the convoy bead exists
+	hold.release()
+	if got := strings.Join(released, ","); got != "class,quota" {
+		t.Errorf("released %q, want the class and then the quota", got)
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/readonly"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Read-only flags
+var (
+	readOnlyFlag bool
+)
+
+// readOnlyCommands are the commands that may run in read-only mode, by
+// command path. A non-nil check refuses the flags that would make the
+// command write, or switches it to a preview.
+var readOnlyCommands = map[string]func() error{
+	"gt":                                    nil,
+	"gt help":                               nil,
+	"gt version":                            nil,
+	"gt completion bash":                    nil,
+	"gt completion zsh":                     nil,
+	"gt completion fish":                    nil,
+	"gt completion powershell":              nil,
+	"gt " + cobra.ShellCompRequestCmd:       nil,
+	"gt " + cobra.ShellCompNoDescRequestCmd: nil,
+	"gt status":                             nil,
+	"gt whoami":                             nil,
+	"gt info":                               nil,
+	"gt du":                                 nil,
+	"gt history":                            nil,
+	"gt audit":                              nil,
+	"gt show":                               nil,
+	"gt ready":                              nil,
+	"gt trail":                              nil,
+	"gt peek":                               nil,
+	"gt feed":                               nil,
+	"gt log":                                nil,
+	"gt logs":                               nil,
+	"gt stale":                              nil,
+	"gt convoy list":                        nil,
+	"gt convoy status":                      nil,
+	"gt convoy report":                      refuseFlag(&convoyReportOutput, "--output"),
+	"gt queue list":                         nil,
+	"gt formula list":                       nil,
+	"gt formula show":                       nil,
+	"gt formula which":                      nil,
+	"gt formula diff":                       nil,
+	"gt formula render":                     nil,
+	"gt formula run":                        previewFormulaRun,
+	"gt review render":                      checkReviewRenderReadOnly,
+	"gt review gate":                        nil,
+	"gt quota status":                       nil,
+	"gt rig list":                           nil,
+	"gt rig status":                         nil,
+	"gt polecat list":                       nil,
+	"gt polecat status":                     nil,
+	"gt crew list":                          nil,
+	"gt crew status":                        nil,
+	"gt hooks list":                         nil,
+	"gt plugin list":                        nil,
+	"gt plugin show":                        nil,
+	"gt doctor":                             checkDoctorReadOnly,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&readOnlyFlag, "read-only", false,
+		"Refuse anything with side effects (bead changes, file writes, agent calls); also set by "+readonly.EnvVar+"=1")
+}
+
+// checkReadOnly turns on read-only mode for --read-only and refuses
+// commands that can't run without side effects. Child gt and bd processes
+// inherit the mode through the environment.
+func checkReadOnly(cmd *cobra.Command) error {
+	if readOnlyFlag {
+		readonly.Enable()
+	}
+	if !readonly.Enabled() {
+		return nil
+	}
+	path := buildCommandPath(cmd)
+	check, ok := readOnlyCommands[path]
+	if !ok {
+		return fmt.Errorf("%w: %s may change the town; run it without --read-only or %s",
+			readonly.ErrReadOnly, path, readonly.EnvVar)
+	}
+	if check != nil {
+		if err := check(); err != nil {
+			return fmt.Errorf("%w: %s: %v", readonly.ErrReadOnly, path, err)
+		}
+	}
+	return nil
+}
+
+// refuseFlag returns a check refusing a string flag that makes a command
+// write.
+func refuseFlag(value *string, name string) func() error {
+	return func() error {
+		if *value != "" {
+			return fmt.Errorf("%s writes a file", name)
+		}
+		return nil
+	}
+}
+
+// previewFormulaRun turns a formula run into its --dry-run preview.
+func previewFormulaRun() error {
+	if !formulaRunDryRun {
+		fmt.Fprintf(os.Stderr, "%s Read-only mode: previewing the run (--dry-run)\n", style.Dim.Render("○"))
+		formulaRunDryRun = true
+	}
+	return nil
+}
+
+func checkReviewRenderReadOnly() error {
+	if reviewRenderComment {
+		return fmt.Errorf("--comment posts to the PR")
+	}
+	return refuseFlag(&reviewRenderOutput, "--output")()
+}
+
+func checkDoctorReadOnly() error {
+	if doctorFix {
+		return fmt.Errorf("--fix changes the town; run gt doctor without it")
+	}
+	return nil
+}
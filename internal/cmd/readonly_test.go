@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/readonly"
+)
+
+func TestCheckReadOnly(t *testing.T) {
+	t.Setenv(readonly.EnvVar, "1")
+	defer func() { formulaRunDryRun = false; doctorFix = false }()
+
+	if err := checkReadOnly(statusCmd); err != nil {
+		t.Errorf("checkReadOnly(status) = %v, want allowed", err)
+	}
+	if err := checkReadOnly(convoyCloseCmd); !errors.Is(err, readonly.ErrReadOnly) {
+		t.Errorf("checkReadOnly(convoy close) = %v, want ErrReadOnly", err)
+	}
+
+	doctorFix = true
+	if err := checkReadOnly(doctorCmd); !errors.Is(err, readonly.ErrReadOnly) {
+		t.Errorf("checkReadOnly(doctor --fix) = %v, want ErrReadOnly", err)
+	}
+
+	formulaRunDryRun = false
+	if err := checkReadOnly(formulaRunCmd); err != nil {
+		t.Errorf("checkReadOnly(formula run) = %v, want a preview", err)
+	}
+	if !formulaRunDryRun {
+		t.Error("formula run in read-only mode should be a dry run")
+	}
+}
+
+func TestCheckReadOnlyOff(t *testing.T) {
+	t.Setenv(readonly.EnvVar, "")
+	if err := checkReadOnly(convoyCloseCmd); err != nil {
+		t.Errorf("checkReadOnly() with read-only off = %v", err)
+	}
+}
@@ -106,7 +106,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 			}
 		}
 		if len(filtered) == 0 {
-			return fmt.Errorf("rig not found: %s", readyRig)
+			return rigNotFoundError(readyRig, rigNames(rigs))
 		}
 		rigs = filtered
 	}
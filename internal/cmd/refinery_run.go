@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Refinery run flags
+var (
+	refineryRunRig    string
+	refineryRunDir    string
+	refineryRunDryRun bool
+)
+
+var refineryRunCmd = &cobra.Command{
+	Use:   "run <review-id>",
+	Short: "Run the post-synthesis pipeline for a review",
+	Long: `Run the rig's post-synthesis refinery steps for a convoy review.
+
+Steps are defined in <rig>/settings/config.json under "refinery":
+
+  "refinery": {
+    "steps": [
+      {"name": "pdf", "run": "pandoc synthesis.md -o $GT_REFINERY_OUTPUT/review.pdf"},
+      {"name": "publish", "run": "./scripts/publish-review.sh", "timeout": "2m"}
+    ]
+  }
+
+Each step runs with sh -c from the review directory, with GT_REVIEW_ID,
+GT_REVIEW_DIR, GT_REFINERY_OUTPUT and GT_RIG set. Artifacts belong in
+GT_REFINERY_OUTPUT (<rig>/refinery/outputs/<review-id>/), where the run
+summary is also written to pipeline.json.
+
+The pipeline runs automatically on 'gt synthesis close' unless the rig sets
+"manual": true. Use this command to run it by hand or retry a failed run.
+
+Examples:
+  gt refinery run abc123
+  gt refinery run abc123 --rig gastown
+  gt refinery run abc123 --dir ./out/.reviews/abc123
+  gt refinery run abc123 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRefineryRun,
+}
+
+func init() {
+	refineryRunCmd.Flags().StringVar(&refineryRunRig, "rig", "", "Rig whose pipeline to run (default: current rig)")
+	refineryRunCmd.Flags().StringVar(&refineryRunDir, "dir", "", "Review output directory (default: .reviews/<review-id>)")
+	refineryRunCmd.Flags().BoolVar(&refineryRunDryRun, "dry-run", false, "Show the steps without running them")
+
+	refineryCmd.AddCommand(refineryRunCmd)
+}
+
+func runRefineryRun(cmd *cobra.Command, args []string) error {
+	reviewID := args[0]
+	if err := refinery.ValidateReviewID(reviewID); err != nil {
+		return err
+	}
+
+	rigName := refineryRunRig
+	if rigName == "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return fmt.Errorf("could not determine rig (use --rig flag): %w", err)
+		}
+	}
+
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	reviewDir := refineryRunDir
+	if reviewDir == "" {
+		reviewDir = findReviewDir(townRoot, r, reviewID)
+	}
+
+	steps := loadRefinerySteps(r)
+	if len(steps) == 0 {
+		fmt.Printf("%s No refinery steps configured for %s\n", style.Dim.Render("○"), r.Name)
+		fmt.Printf("  Add a \"refinery\" section to %s\n", config.RigSettingsPath(r.Path))
+		return nil
+	}
+
+	if refineryRunDryRun {
+		fmt.Printf("%s Would run %d refinery step(s) for %s\n", style.Bold.Render("→"), len(steps), reviewID)
+		fmt.Printf("  Review dir: %s\n", reviewDir)
+		fmt.Printf("  Output dir: %s\n", filepath.Join(r.Path, "refinery", "outputs", reviewID))
+		for i, step := range steps {
+			fmt.Printf("  %d. %s: %s\n", i+1, step.Name, style.Dim.Render(step.Run))
+		}
+		return nil
+	}
+
+	return runRefineryPipeline(r, steps, reviewID, reviewDir)
+}
+
+// loadRefinerySteps returns the rig's configured post-synthesis steps.
+func loadRefinerySteps(r *rig.Rig) []config.RefineryStep {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.Refinery == nil {
+		return nil
+	}
+	return settings.Refinery.Steps
+}
+
+// findReviewDir locates the output directory for a review. Convoy outputs
+// are written relative to the agent's working directory, so check the
+// current directory, the rig's canonical clone, and the town root.
+func findReviewDir(townRoot string, r *rig.Rig, reviewID string) string {
+	rel := filepath.Join(".reviews", reviewID)
+	candidates := []string{rel, filepath.Join(r.Path, "mayor", "rig", rel), filepath.Join(townRoot, rel)}
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return rel
+}
+
+// runRefineryPipeline runs steps for a review and prints per-step results.
+func runRefineryPipeline(r *rig.Rig, steps []config.RefineryStep, reviewID, reviewDir string) error {
+	absDir, err := filepath.Abs(reviewDir)
+	if err != nil {
+		return fmt.Errorf("resolving review dir: %w", err)
+	}
+
+	fmt.Printf("%s Running refinery pipeline for %s (%d step(s))\n",
+		style.Bold.Render("→"), reviewID, len(steps))
+
+	run := &refinery.PipelineRun{
+		ReviewID:  reviewID,
+		ReviewDir: absDir,
+		RigName:   r.Name,
+		RigPath:   r.Path,
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+	}
+	result, err := refinery.RunPipeline(context.Background(), steps, run)
+	if result != nil {
+		for _, step := range result.Steps {
+			if step.Success {
+				fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), step.Name, style.Dim.Render(step.Duration.Round(time.Millisecond).String()))
+			} else {
+				fmt.Printf("  %s %s: %s\n", style.Error.Render("✗"), step.Name, step.Error)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Artifacts: %s\n", style.Bold.Render("✓"), run.OutputDir())
+	return nil
+}
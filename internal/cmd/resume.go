@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -22,6 +23,8 @@ var resumeCmd = &cobra.Command{
 By default, this command checks for parked work (from 'gt park') and whether
 its gate has cleared. If the gate is closed, it restores your work context.
 
+With --town, it lifts a town-wide dispatch pause set by 'gt pause'.
+
 With --handoff, it checks the inbox for handoff messages (messages with
 "HANDOFF" in the subject) and displays them formatted for easy continuation.
 
@@ -34,7 +37,8 @@ The resume command:
 Examples:
   gt resume              # Check for and resume parked work
   gt resume --status     # Just show parked work status without resuming
-  gt resume --handoff    # Check inbox for handoff messages`,
+  gt resume --handoff    # Check inbox for handoff messages
+  gt resume --town       # Allow work dispatch again after gt pause`,
 	RunE: runResume,
 }
 
@@ -42,12 +46,14 @@ var (
 	resumeStatusOnly bool
 	resumeJSON       bool
 	resumeHandoff    bool
+	resumeTown       bool
 )
 
 func init() {
 	resumeCmd.Flags().BoolVar(&resumeStatusOnly, "status", false, "Just show parked work status")
 	resumeCmd.Flags().BoolVar(&resumeJSON, "json", false, "Output as JSON")
 	resumeCmd.Flags().BoolVar(&resumeHandoff, "handoff", false, "Check for handoff messages instead of parked work")
+	resumeCmd.Flags().BoolVar(&resumeTown, "town", false, "Lift a town-wide dispatch pause (see gt pause)")
 	rootCmd.AddCommand(resumeCmd)
 }
 
@@ -61,6 +67,10 @@ type ResumeStatus struct {
 }
 
 func runResume(cmd *cobra.Command, args []string) error {
+	if resumeTown {
+		return runResumeTown()
+	}
+
 	// If --handoff flag, check for handoff messages instead
 	if resumeHandoff {
 		return checkHandoffMessages()
@@ -94,7 +104,7 @@ func runResume(cmd *cobra.Command, args []string) error {
 
 	// Check gate status
 	gateCheck := exec.Command("bd", "gate", "show", parked.GateID, "--json")
-	gateOutput, err := gateCheck.Output()
+	gateOutput, err := cmdtrace.Output(gateCheck)
 	gateNotFound := false
 	if err != nil {
 		// Gate might have been deleted (wisp cleanup) or is inaccessible
@@ -158,7 +168,7 @@ func runResume(cmd *cobra.Command, args []string) error {
 		pinCmd := exec.Command("bd", "update", parked.BeadID, "--status=pinned", "--assignee="+agentID)
 		pinCmd.Dir = cloneRoot
 		pinCmd.Stderr = os.Stderr
-		if err := pinCmd.Run(); err != nil {
+		if err := cmdtrace.Run(pinCmd); err != nil {
 			return fmt.Errorf("pinning bead: %w", err)
 		}
 
@@ -225,11 +235,11 @@ func displayResumeStatus(status ResumeStatus, parked *ParkedWork) error {
 func checkHandoffMessages() error {
 	// Get inbox in JSON format
 	inboxCmd := exec.Command("gt", "mail", "inbox", "--json")
-	output, err := inboxCmd.Output()
+	output, err := cmdtrace.Output(inboxCmd)
 	if err != nil {
 		// Fallback to non-JSON if --json not supported
 		inboxCmd = exec.Command("gt", "mail", "inbox")
-		output, err = inboxCmd.Output()
+		output, err = cmdtrace.Output(inboxCmd)
 		if err != nil {
 			return fmt.Errorf("checking inbox: %w", err)
 		}
@@ -257,7 +267,7 @@ func checkHandoffMessages() error {
 	if err := json.Unmarshal(output, &messages); err != nil {
 		// JSON parse failed, use plain text output
 		inboxCmd = exec.Command("gt", "mail", "inbox")
-		output, err = inboxCmd.Output()
+		output, err = cmdtrace.Output(inboxCmd)
 		if err != nil {
 			return fmt.Errorf("fallback inbox check failed: %w", err)
 		}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/review"
+	"github.com/steveyegge/gastown/internal/scm"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Review render flags
+var (
+	reviewRenderFormat   string
+	reviewRenderTemplate string
+	reviewRenderRig      string
+	reviewRenderDir      string
+	reviewRenderOutput   string
+	reviewRenderComment  bool
+)
+
+var reviewCmd = &cobra.Command{
+	Use:     "review",
+	GroupID: GroupWork,
+	Short:   "Work with convoy review outputs",
+	RunE:    requireSubcommand,
+	Long: `Work with the outputs of convoy formula runs (reviews).
+
+A review is the set of leg findings and the synthesis report a convoy
+formula writes under its output directory (default .reviews/<review-id>/).`,
+}
+
+var reviewRenderCmd = &cobra.Command{
+	Use:   "render <review-id>",
+	Short: "Assemble a review into a shareable report",
+	Long: `Assemble a review's synthesis and leg findings into one report.
+
+The report starts with the review's metadata (formula, rig, PR link and
+head commit, convoy) followed by the synthesis and a section per leg, in
+formula order. Legs without output are listed as missing.
+
+Metadata comes from the review's convoy, found by its review_id. The output
+directory is located like 'gt refinery run' does; use --dir to point at it.
+
+Reports are rendered with Go templates. --template replaces the embedded
+default; templates receive the review.Report fields (.Title, .ReviewID,
+.Formula, .Rig, .PR, .PRURL, .HeadSHA, .Convoy, .Synthesis, .Legs, ...)
+and the functions markdown (markdown to HTML in html reports) and shortsha.
+
+--comment posts the rendered markdown report on the review's PR, through
+the rig's source host (GitHub, Gitea, or Bitbucket; see the scm settings).
+
+Examples:
+  gt review render abc123                        # Markdown on stdout
+  gt review render abc123 --format html -o review.html
+  gt review render abc123 --template team.md.tmpl
+  gt review render abc123 --dir ./out/.reviews/abc123
+  gt review render abc123 --comment              # Post on the PR`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReviewRender,
+}
+
+func init() {
+	reviewRenderCmd.Flags().StringVar(&reviewRenderFormat, "format", review.FormatMarkdown, "Report format: md or html")
+	reviewRenderCmd.Flags().StringVar(&reviewRenderTemplate, "template", "", "Go template to render instead of the default")
+	reviewRenderCmd.Flags().StringVar(&reviewRenderRig, "rig", "", "Rig the review ran in (default: from the convoy or current rig)")
+	reviewRenderCmd.Flags().StringVar(&reviewRenderDir, "dir", "", "Review output directory (default: .reviews/<review-id>)")
+	reviewRenderCmd.Flags().StringVarP(&reviewRenderOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	reviewRenderCmd.Flags().BoolVar(&reviewRenderComment, "comment", false, "Post the markdown report as a comment on the review's PR")
+
+	reviewCmd.AddCommand(reviewRenderCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
+
+func runReviewRender(cmd *cobra.Command, args []string) error {
+	reviewID := args[0]
+
+	meta, reviewDir, f, err := locateReview(reviewID, reviewRenderRig, reviewRenderDir)
+	if err != nil {
+		return err
+	}
+
+	report, err := buildReviewReport(reviewID, reviewDir, meta, f)
+	if err != nil {
+		return err
+	}
+	if reviewRenderComment && report.PR == 0 {
+		return fmt.Errorf("review %s has no PR to comment on", reviewID)
+	}
+	if reviewRenderComment && reviewRenderFormat != review.FormatMarkdown {
+		return fmt.Errorf("--comment posts markdown; drop --format %s", reviewRenderFormat)
+	}
+	var provider scm.Provider
+	if report.PR > 0 {
+		rigName := reviewRenderRig
+		if rigName == "" {
+			rigName = meta.Rig
+		}
+		if provider, err = formulaRunSCM(rigName); err == nil {
+			report.PRURL = fetchPRURL(provider, report.PR)
+		} else if reviewRenderComment {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := review.Render(&buf, report, reviewRenderFormat, reviewRenderTemplate); err != nil {
+		return err
+	}
+	if reviewRenderComment {
+		if err := provider.Comment(context.Background(), report.PR, buf.String()); err != nil {
+			return fmt.Errorf("commenting on PR #%d: %w", report.PR, err)
+		}
+		fmt.Fprintf(os.Stderr, "Posted review %s on PR #%d\n", reviewID, report.PR)
+		if reviewRenderOutput == "" {
+			return nil
+		}
+	}
+	if reviewRenderOutput == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(reviewRenderOutput, buf.Bytes(), 0644); err != nil { //nolint:gosec // G306: reports are meant to be shared
+		return fmt.Errorf("writing report: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", reviewRenderOutput)
+	return nil
+}
+
+// locateReview finds a review's convoy metadata, output directory, and
+// formula. Metadata and formula are best-effort: local runs have no convoy.
+func locateReview(reviewID, rigName, reviewDir string) (*ConvoyMeta, string, *formula.Formula, error) {
+	meta := &ConvoyMeta{ReviewID: reviewID}
+	townRoot, _ := workspace.FindFromCwd()
+	if townRoot != "" {
+		if convoyID := findReviewConvoy(filepath.Join(townRoot, ".beads"), reviewID); convoyID != "" {
+			if m, err := getConvoyMeta(convoyID); err == nil {
+				meta = m
+			}
+		}
+	}
+
+	if reviewDir == "" {
+		reviewDir = filepath.Join(".reviews", reviewID)
+		if rigName == "" {
+			rigName = meta.Rig
+		}
+		if rigName == "" && townRoot != "" {
+			rigName, _ = inferRigFromCwd(townRoot)
+		}
+		if rigName != "" {
+			if root, r, err := getRig(rigName); err == nil {
+				reviewDir = findReviewDir(root, r, reviewID)
+			}
+		}
+	}
+	if info, err := os.Stat(reviewDir); err != nil || !info.IsDir() {
+		return nil, "", nil, fmt.Errorf("review output directory %s not found (use --dir)", reviewDir)
+	}
+
+	var f *formula.Formula
+	if meta.Formula != "" {
+		if path, err := findFormula(meta.Formula); err == nil {
+			f, _ = formula.ParseFile(path, formulaIncludeDirs()...)
+		}
+	}
+	return meta, reviewDir, f, nil
+}
+
+// buildReviewReport reads a review's output directory. With a formula, legs
+// follow its order and titles and expected-but-absent outputs are reported
+// missing; any other markdown files in the directory are appended as legs.
+func buildReviewReport(reviewID, reviewDir string, meta *ConvoyMeta, f *formula.Formula) (*review.Report, error) {
+	entries, err := os.ReadDir(reviewDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading review directory: %w", err)
+	}
+
+	report := &review.Report{
+		ReviewID:     reviewID,
+		Title:        "Review " + reviewID,
+		Formula:      meta.Formula,
+		Rig:          meta.Rig,
+		PR:           meta.PR,
+		HeadSHA:      meta.HeadSHA,
+		Generated:    time.Now(),
+		Convoy:       meta.ID,
+		ConvoyStatus: meta.Status,
+	}
+	if meta.Title != "" {
+		report.Title = meta.Title
+	}
+
+	synthesisFile := "synthesis.md"
+	synthesisTitle := "Synthesis"
+	legPattern := "{{.leg.id}}-findings.md"
+	if f != nil {
+		report.Description = f.Description
+		if f.Output != nil && f.Output.Synthesis != "" {
+			synthesisFile = f.Output.Synthesis
+		}
+		if f.Output != nil && f.Output.LegPattern != "" {
+			legPattern = f.Output.LegPattern
+		}
+		if f.Synthesis != nil && f.Synthesis.Title != "" {
+			synthesisTitle = f.Synthesis.Title
+		}
+	}
+
+	read := func(name string) (string, bool) {
+		data, err := os.ReadFile(filepath.Join(reviewDir, name)) //nolint:gosec // G304: files in the review directory
+		if err != nil {
+			return "", false
+		}
+		return strings.TrimSpace(string(data)), true
+	}
+
+	used := map[string]bool{synthesisFile: true}
+	if content, ok := read(synthesisFile); ok {
+		report.Synthesis = &review.Section{ID: "synthesis", Title: synthesisTitle, Status: review.StatusComplete, Content: content}
+	}
+	if f != nil {
+		for _, leg := range f.Legs {
+			name := expandOutputPath("", legPattern, reviewID, leg.ID)
+			used[name] = true
+			section := review.Section{ID: leg.ID, Title: leg.Title, Status: review.StatusMissing}
+			if section.Title == "" {
+				section.Title = leg.ID
+			}
+			// A consensus leg's agents each leave an output; local runs
+			// also write the merged one, polecat runs leave merging to us
+			var agentNames map[string]string
+			if leg.Consensus != nil {
+				agentNames = make(map[string]string, len(leg.Consensus.Agents))
+				for _, agent := range leg.Consensus.Agents {
+					agentNames[agent] = expandOutputPath("", legPattern, reviewID, formula.ConsensusLegID(leg.ID, agent))
+					used[agentNames[agent]] = true
+				}
+			}
+			if content, ok := read(name); ok {
+				section.Status, section.Content = review.StatusComplete, content
+			} else if agentNames != nil {
+				if content, ok := mergeConsensusOutputs(leg, agentNames, read); ok {
+					section.Status, section.Content = review.StatusComplete, content
+				}
+			}
+			report.Legs = append(report.Legs, section)
+		}
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || used[name] || filepath.Ext(name) != ".md" {
+			continue
+		}
+		content, ok := read(name)
+		if !ok {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimSuffix(name, ".md"), "-findings")
+		report.Legs = append(report.Legs, review.Section{ID: id, Title: id, Status: review.StatusComplete, Content: content})
+	}
+	return report, nil
+}
+
+// findReviewConvoy returns the formula convoy whose description records
+// review_id, or "" if none does.
+func findReviewConvoy(townBeads, reviewID string) string {
+	listCmd := exec.Command("bd", "list", "--type=convoy", "--all", "--json")
+	listCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	listCmd.Stdout = &stdout
+	if err := cmdtrace.Run(listCmd); err != nil {
+		return ""
+	}
+
+	var convoys []struct {
+		ID          string `json:"id"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
+		return ""
+	}
+	for _, c := range convoys {
+		for _, line := range strings.Split(c.Description, "\n") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(line), "review_id:"); ok && strings.TrimSpace(value) == reviewID {
+				return c.ID
+			}
+		}
+	}
+	return ""
+}
+
+// fetchPRURL returns a PR's web URL, or "" if unavailable.
+func fetchPRURL(p scm.Provider, prNumber int) string {
+	pr, err := p.PR(context.Background(), prNumber)
+	if err != nil {
+		return ""
+	}
+	return pr.URL
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/review"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// reviewGateIncompleteExit is gt review gate's exit code for a review that
+// can't pass because legs have no output yet.
+const reviewGateIncompleteExit = 2
+
+// Review gate flags
+var (
+	reviewGateThreshold string
+	reviewGateRig       string
+	reviewGateDir       string
+	reviewGateJSON      bool
+)
+
+var reviewGateCmd = &cobra.Command{
+	Use:   "gate <review-id>",
+	Short: "Fail when a review has findings at or above a severity",
+	Long: `Check a review's findings against a severity threshold.
+
+Findings are read from the leg outputs (the synthesis only if no leg wrote
+any). A finding is a top-level list item under a heading whose first word
+is a severity, or a list item tagged with one:
+
+  ## Critical Issues          ## High
+  - SQL injection in ...      - [medium] Missing timeout in ...
+
+Severities, most to least severe: critical (P0), high (major, P1),
+medium (minor, P2), low (P3), info (observations).
+
+Exits 1 if any finding is at or above --threshold, so CI can fail a build.
+Exits 2 if the review is incomplete: a leg that failed, is still running,
+or left no output, or no output at all. Only a review whose every leg
+finished can pass.
+
+Examples:
+  gt review gate abc123                         # Fail on high or critical
+  gt review gate abc123 --threshold critical
+  gt review gate abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReviewGate,
+}
+
+func init() {
+	reviewGateCmd.Flags().StringVar(&reviewGateThreshold, "threshold", "high", "Fail on findings at or above this severity")
+	reviewGateCmd.Flags().StringVar(&reviewGateRig, "rig", "", "Rig the review ran in (default: from the convoy or current rig)")
+	reviewGateCmd.Flags().StringVar(&reviewGateDir, "dir", "", "Review output directory (default: .reviews/<review-id>)")
+	reviewGateCmd.Flags().BoolVar(&reviewGateJSON, "json", false, "Output as JSON")
+
+	reviewCmd.AddCommand(reviewGateCmd)
+}
+
+func runReviewGate(cmd *cobra.Command, args []string) error {
+	reviewID := args[0]
+	threshold, err := review.ParseSeverity(reviewGateThreshold)
+	if err != nil {
+		return err
+	}
+
+	meta, reviewDir, f, err := locateReview(reviewID, reviewGateRig, reviewGateDir)
+	if err != nil {
+		return err
+	}
+	report, err := buildReviewReport(reviewID, reviewDir, meta, f)
+	if err != nil {
+		return err
+	}
+	findings := reportFindings(report)
+	failing := review.AtOrAbove(findings, threshold)
+	incomplete, empty := incompleteReviewLegs(report)
+	passed := len(failing) == 0 && len(incomplete) == 0 && !empty
+
+	if reviewGateJSON {
+		out := struct {
+			ReviewID   string           `json:"review_id"`
+			Threshold  review.Severity  `json:"threshold"`
+			Passed     bool             `json:"passed"`
+			Complete   bool             `json:"complete"`
+			Incomplete []string         `json:"incomplete,omitempty"`
+			Counts     map[string]int   `json:"counts"`
+			Failing    []review.Finding `json:"failing"`
+		}{reviewID, threshold, passed, len(incomplete) == 0 && !empty, incomplete, severityCountMap(findings), failing}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("Review %s: %s\n", reviewID, formatSeverityCounts(findings))
+		printSeverityGate(os.Stdout, failing, threshold)
+		switch {
+		case empty:
+			fmt.Printf("%s No leg or synthesis output in %s\n", style.Error.Render("✗"), reviewDir)
+		case len(incomplete) > 0:
+			fmt.Printf("%s %d leg(s) without output (failed or still running): %s\n",
+				style.Error.Render("✗"), len(incomplete), strings.Join(incomplete, ", "))
+		}
+	}
+	switch {
+	case len(failing) > 0:
+		return NewSilentExit(1)
+	case len(incomplete) > 0 || empty:
+		return NewSilentExit(reviewGateIncompleteExit)
+	}
+	return nil
+}
+
+// incompleteReviewLegs returns the legs of a report that have no output,
+// and whether the report has no output at all.
+func incompleteReviewLegs(r *review.Report) (incomplete []string, empty bool) {
+	complete := 0
+	for _, leg := range r.Legs {
+		if leg.Status == review.StatusComplete {
+			complete++
+		} else {
+			incomplete = append(incomplete, leg.ID)
+		}
+	}
+	return incomplete, complete == 0 && r.Synthesis == nil
+}
+
+// reportFindings collects findings from a report's legs, falling back to
+// the synthesis when no leg produced output.
+func reportFindings(r *review.Report) []review.Finding {
+	var findings []review.Finding
+	legOutput := false
+	for _, leg := range r.Legs {
+		if leg.Status == review.StatusComplete {
+			legOutput = true
+			findings = append(findings, review.ParseFindings(leg.Content, leg.ID)...)
+		}
+	}
+	if !legOutput && r.Synthesis != nil {
+		findings = review.ParseFindings(r.Synthesis.Content, r.Synthesis.ID)
+	}
+	return findings
+}
+
+// printSeverityGate reports the findings that failed a severity gate.
+func printSeverityGate(w io.Writer, failing []review.Finding, threshold review.Severity) {
+	if len(failing) == 0 {
+		fmt.Fprintf(w, "%s No findings at or above %s\n", style.Success.Render("✓"), threshold)
+		return
+	}
+	fmt.Fprintf(w, "%s %d finding(s) at or above %s:\n", style.Error.Render("✗"), len(failing), threshold)
+	for _, f := range failing {
+		fmt.Fprintf(w, "  [%s] %s: %s\n", f.Severity, f.Source, f.Text)
+	}
+}
+
+// formatSeverityCounts summarizes findings as "1 critical, 2 high, ...".
+func formatSeverityCounts(findings []review.Finding) string {
+	counts := review.CountBySeverity(findings)
+	parts := make([]string, 0, len(counts))
+	for sev := review.SeverityCritical; sev >= review.SeverityInfo; sev-- {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func severityCountMap(findings []review.Finding) map[string]int {
+	counts := review.CountBySeverity(findings)
+	m := make(map[string]int, len(counts))
+	for sev, n := range counts {
+		m[review.Severity(sev).String()] = n
+	}
+	return m
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/review"
+)
+
+func TestBuildReviewReport(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"report.md": "summary\n",
+		"sec.md":    "finding\n",
+		"extra.md":  "note\n",
+		"data.json": "{}",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f := &formula.Formula{
+		Description: "Review code",
+		Legs:        []formula.Leg{{ID: "sec", Title: "Security"}, {ID: "perf", Title: "Performance"}},
+		Synthesis:   &formula.Synthesis{Title: "Summary"},
+		Output:      &formula.Output{LegPattern: "{{.leg.id}}.md", Synthesis: "report.md"},
+	}
+	meta := &ConvoyMeta{ID: "hq-cv-1", Title: "code-review: x", Formula: "code-review", PR: 12}
+
+	r, err := buildReviewReport("abc", dir, meta, f)
+	if err != nil {
+		t.Fatalf("buildReviewReport: %v", err)
+	}
+	if r.Title != "code-review: x" || r.PR != 12 || r.Convoy != "hq-cv-1" || r.Description != "Review code" {
+		t.Errorf("metadata = %+v", r)
+	}
+	if r.Synthesis == nil || r.Synthesis.Title != "Summary" || r.Synthesis.Content != "summary" {
+		t.Errorf("synthesis = %+v", r.Synthesis)
+	}
+	want := []review.Section{
+		{ID: "sec", Title: "Security", Status: review.StatusComplete, Content: "finding"},
+		{ID: "perf", Title: "Performance", Status: review.StatusMissing},
+		{ID: "extra", Title: "extra", Status: review.StatusComplete, Content: "note"},
+	}
+	if len(r.Legs) != len(want) {
+		t.Fatalf("legs = %+v, want %+v", r.Legs, want)
+	}
+	for i := range want {
+		if r.Legs[i] != want[i] {
+			t.Errorf("leg %d = %+v, want %+v", i, r.Legs[i], want[i])
+		}
+	}
+}
+
+func TestReportFindings(t *testing.T) {
+	r := &review.Report{
+		Synthesis: &review.Section{ID: "synthesis", Status: review.StatusComplete, Content: "## Critical\n- dup of sec"},
+		Legs: []review.Section{
+			{ID: "sec", Status: review.StatusComplete, Content: "## Critical\n- injection"},
+			{ID: "perf", Status: review.StatusMissing},
+		},
+	}
+	got := reportFindings(r)
+	if len(got) != 1 || got[0].Source != "sec" {
+		t.Errorf("reportFindings = %+v, want only the leg finding", got)
+	}
+
+	r.Legs = nil
+	if got := reportFindings(r); len(got) != 1 || got[0].Source != "synthesis" {
+		t.Errorf("reportFindings without legs = %+v, want synthesis finding", got)
+	}
+}
+
+func TestIncompleteReviewLegs(t *testing.T) {
+	r := &review.Report{
+		Legs: []review.Section{
+			{ID: "sec", Status: review.StatusComplete},
+			{ID: "perf", Status: review.StatusMissing},
+		},
+	}
+	if incomplete, empty := incompleteReviewLegs(r); len(incomplete) != 1 || incomplete[0] != "perf" || empty {
+		t.Errorf("incompleteReviewLegs = %v, %v; want [perf], false", incomplete, empty)
+	}
+
+	r.Legs = []review.Section{{ID: "perf", Status: review.StatusMissing}}
+	if _, empty := incompleteReviewLegs(r); !empty {
+		t.Error("a review without any output should be empty")
+	}
+
+	r.Legs = nil
+	if incomplete, empty := incompleteReviewLegs(r); len(incomplete) != 0 || !empty {
+		t.Errorf("incompleteReviewLegs(no legs, no synthesis) = %v, %v; want none, true", incomplete, empty)
+	}
+	r.Synthesis = &review.Section{ID: "synthesis", Status: review.StatusComplete}
+	if incomplete, empty := incompleteReviewLegs(r); len(incomplete) != 0 || empty {
+		t.Errorf("incompleteReviewLegs(synthesis only) = %v, %v; want complete", incomplete, empty)
+	}
+}
@@ -41,8 +41,9 @@ A rig is a container for managing a project and its agents:
 }
 
 var rigAddCmd = &cobra.Command{
-	Use:   "add <name> <git-url>",
-	Short: "Add a new rig to the workspace",
+	Use:     "add <name> <git-url>",
+	Aliases: []string{"create"},
+	Short:   "Add a new rig to the workspace",
 	Long: `Add a new rig by cloning a repository.
 
 This creates a rig container with:
@@ -60,6 +61,24 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+Use --template to scaffold the rig from a template (see 'gt rig templates'):
+  - Pre-populates settings/config.json (test gate, default formula)
+  - Creates the template's crew workspaces
+  - Adds build outputs and Gas Town directories to .gitignore
+
+Use --remote to run the rig's polecats on another machine over SSH (e.g. a
+build server holding a large checkout). The remote working copy must be a
+clone of the same repository with an origin remote. Polecats get a
+worktree next to it, their agents run there over ssh, and the worktree is
+copied back to the local polecat directory (without .git) when the agent
+exits. Beads, mail, and tmux sessions stay on this machine.
+
+Use --depth, --filter, and --sparse for large repositories. They are saved
+as the rig's clone settings (settings/config.json) and apply to the shared
+repo, crew clones, and polecat and refinery worktrees; the mayor clone
+gets the whole tree. --sparse takes directories to check out; root files
+and dot-directories are always checked out.
+
 Use --adopt to register an existing directory instead of creating new:
   - Reads existing config.json if present
   - Auto-detects git URL from origin remote (git-url argument not required)
@@ -68,6 +87,9 @@ Use --adopt to register an existing directory instead of creating new:
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
   gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig create api https://github.com/org/api --template go-service
+  gt rig add monorepo git@github.com:org/mono.git --remote ssh://me@build01/srv/mono
+  gt rig add mono git@github.com:org/mono.git --filter blob:none --sparse services/api,libs
   gt rig add existing-rig --adopt`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runRigAdd,
@@ -265,6 +287,11 @@ var (
 	rigAddAdopt        bool
 	rigAddAdoptURL     string
 	rigAddAdoptForce   bool
+	rigAddTemplate     string
+	rigAddRemote       string
+	rigAddDepth        int
+	rigAddFilter       string
+	rigAddSparse       []string
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -298,6 +325,11 @@ func init() {
 	rigAddCmd.Flags().BoolVar(&rigAddAdopt, "adopt", false, "Adopt an existing directory instead of creating new")
 	rigAddCmd.Flags().StringVar(&rigAddAdoptURL, "url", "", "Git remote URL for --adopt (default: auto-detected from origin)")
 	rigAddCmd.Flags().BoolVar(&rigAddAdoptForce, "force", false, "With --adopt, register even if git remote cannot be detected")
+	rigAddCmd.Flags().StringVar(&rigAddTemplate, "template", "", "Scaffold the rig from a template (see 'gt rig templates')")
+	rigAddCmd.Flags().StringVar(&rigAddRemote, "remote", "", "Run polecats in this remote working copy (ssh://user@host/path)")
+	rigAddCmd.Flags().IntVar(&rigAddDepth, "depth", 0, "Clone only this many commits of history")
+	rigAddCmd.Flags().StringVar(&rigAddFilter, "filter", "", "Partial clone filter (blob:none, blob:limit=<n>, tree:<depth>)")
+	rigAddCmd.Flags().StringSliceVar(&rigAddSparse, "sparse", nil, "Directories to check out in crew clones and worktrees (comma-separated)")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -322,6 +354,15 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 
 	// Handle --adopt mode: register existing directory
 	if rigAddAdopt {
+		if rigAddTemplate != "" {
+			return fmt.Errorf("--template cannot be used with --adopt")
+		}
+		if rigAddRemote != "" {
+			return fmt.Errorf("--remote cannot be used with --adopt (set \"remote\" in the rig's config.json)")
+		}
+		if rigAddDepth != 0 || rigAddFilter != "" || len(rigAddSparse) > 0 {
+			return fmt.Errorf("--depth, --filter, and --sparse cannot be used with --adopt (set \"clone\" in the rig's settings/config.json)")
+		}
 		return runRigAdopt(cmd, args)
 	}
 
@@ -346,6 +387,14 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// Resolve the template before cloning so a typo fails fast
+	var tmpl *rig.Template
+	if rigAddTemplate != "" {
+		if tmpl, err = rig.LoadTemplate(townRoot, rigAddTemplate); err != nil {
+			return err
+		}
+	}
+
 	// Load rigs config
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	rigsConfig, err := config.LoadRigsConfig(rigsPath)
@@ -366,6 +415,16 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	if rigAddLocalRepo != "" {
 		fmt.Printf("  Local repo: %s\n", rigAddLocalRepo)
 	}
+	if rigAddRemote != "" {
+		fmt.Printf("  Remote: %s\n", rigAddRemote)
+	}
+	var clone *config.CloneConfig
+	if rigAddDepth != 0 || rigAddFilter != "" || len(rigAddSparse) > 0 {
+		clone = &config.CloneConfig{Depth: rigAddDepth, Filter: rigAddFilter, Sparse: rigAddSparse}
+		if err := config.ValidateRigSettings(&config.RigSettings{Clone: clone}); err != nil {
+			return err
+		}
+	}
 
 	startTime := time.Now()
 
@@ -376,6 +435,8 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		BeadsPrefix:   rigAddPrefix,
 		LocalRepo:     rigAddLocalRepo,
 		DefaultBranch: rigAddBranch,
+		Remote:        rigAddRemote,
+		Clone:         clone,
 	})
 	if err != nil {
 		return fmt.Errorf("adding rig: %w", err)
@@ -430,6 +491,14 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Scaffold from template
+	var templateCrew []string
+	if tmpl != nil {
+		if templateCrew, err = applyRigTemplate(townRoot, newRig, tmpl); err != nil {
+			return err
+		}
+	}
+
 	elapsed := time.Since(startTime)
 
 	// Read default branch from rig config
@@ -447,7 +516,11 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  ├── plugins/          (rig-level plugins)\n")
 	fmt.Printf("  ├── mayor/rig/        (clone: %s)\n", defaultBranch)
 	fmt.Printf("  ├── refinery/rig/     (worktree: %s, sees polecat branches)\n", defaultBranch)
-	fmt.Printf("  ├── crew/             (empty - add crew with 'gt crew add')\n")
+	if len(templateCrew) > 0 {
+		fmt.Printf("  ├── crew/             (%s)\n", strings.Join(templateCrew, ", "))
+	} else {
+		fmt.Printf("  ├── crew/             (empty - add crew with 'gt crew add')\n")
+	}
 	fmt.Printf("  ├── witness/\n")
 	fmt.Printf("  └── polecats/\n")
 
@@ -1217,6 +1290,7 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 	// Polecats
 	polecatGit := git.NewGit(r.Path)
 	polecatMgr := polecat.NewManager(r, polecatGit, t)
+	polecatSessions := polecat.NewSessionManager(t, r).Backend()
 	polecats, err := polecatMgr.List()
 	fmt.Printf("%s", style.Bold.Render("Polecats"))
 	if err != nil || len(polecats) == 0 {
@@ -1225,7 +1299,7 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf(" (%d)\n", len(polecats))
 		for _, p := range polecats {
 			sessionName := fmt.Sprintf("gt-%s-%s", rigName, p.Name)
-			hasSession, _ := t.HasSession(sessionName)
+			hasSession, _ := polecatSessions.HasSession(sessionName)
 
 			sessionIcon := style.Dim.Render("○")
 			if hasSession {
@@ -1421,6 +1495,11 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
 	t := tmux.NewTmux()
 
+	// With --explain, show what the restart will do and ask before doing it
+	if explainFlag && !confirmExplained(explainRigRestart(rigMgr, t, args)) {
+		return nil
+	}
+
 	// Track results
 	var succeeded []string
 	var failed []string
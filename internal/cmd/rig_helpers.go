@@ -7,6 +7,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/suggest"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -29,8 +30,28 @@ func getRig(rigName string) (string, *rig.Rig, error) {
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
 	r, err := rigMgr.GetRig(rigName)
 	if err != nil {
-		return "", nil, fmt.Errorf("rig '%s' not found", rigName)
+		known := make([]string, 0, len(rigsConfig.Rigs))
+		for name := range rigsConfig.Rigs {
+			known = append(known, name)
+		}
+		return "", nil, rigNotFoundError(rigName, known)
 	}
 
 	return townRoot, r, nil
 }
+
+// rigNotFoundError reports an unknown rig name, suggesting the closest of
+// the known rig names.
+func rigNotFoundError(rigName string, known []string) error {
+	suggestions := suggest.Nearest(rigName, known, 3)
+	return fmt.Errorf("%s", suggest.FormatSuggestion("Rig", rigName, suggestions, "Run 'gt rig list' to see available rigs"))
+}
+
+// rigNames returns the names of rigs.
+func rigNames(rigs []*rig.Rig) []string {
+	names := make([]string, len(rigs))
+	for i, r := range rigs {
+		names[i] = r.Name
+	}
+	return names
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -98,7 +99,7 @@ func runRigQuickAdd(cmd *cobra.Command, args []string) error {
 	addCmd.Dir = townRoot
 	addCmd.Stdout = os.Stdout
 	addCmd.Stderr = os.Stderr
-	if err := addCmd.Run(); err != nil {
+	if err := cmdtrace.Run(addCmd); err != nil {
 		fmt.Printf("\n%s Failed to add rig. You can try manually:\n", style.Warning.Render("⚠"))
 		fmt.Printf("  cd %s && gt rig add %s %s\n", townRoot, rigName, gitURL)
 		return fmt.Errorf("gt rig add failed: %w", err)
@@ -121,7 +122,7 @@ func runRigQuickAdd(cmd *cobra.Command, args []string) error {
 	crewCmd.Dir = filepath.Join(townRoot, rigName)
 	crewCmd.Stdout = os.Stdout
 	crewCmd.Stderr = os.Stderr
-	if err := crewCmd.Run(); err != nil {
+	if err := cmdtrace.Run(crewCmd); err != nil {
 		fmt.Printf("  %s Could not create crew workspace: %v\n", style.Dim.Render("⚠"), err)
 		fmt.Printf("  Run manually: cd %s && gt crew add %s --rig %s\n", filepath.Join(townRoot, rigName), user, rigName)
 	}
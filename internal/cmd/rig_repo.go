@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Rig repo flags
+var (
+	rigRepoHost          string
+	rigRepoDefaultBranch string
+	rigRepoClear         bool
+)
+
+var rigRepoCmd = &cobra.Command{
+	Use:   "repo <rig> [owner/name]",
+	Short: "Show or set the repository a rig tracks",
+	Long: `Show or set the repository a rig tracks on its source host.
+
+The mapping is stored in mayor/rigs.json and resolves PR URLs (gt formula
+run --pr <url>) and source host events to the rig, so they need no --rig.
+Rigs without a mapping are matched by their scm settings or git_url, which
+fails for local mirrors and ssh host aliases.
+
+With only a rig, shows its mapping. With owner/name, sets it; --host
+defaults to github.com and --default-branch to the rig's default branch.
+
+Examples:
+  gt rig repo gastown
+  gt rig repo gastown steveyegge/gastown
+  gt rig repo infra ops/infra --host gitea.example.com --default-branch trunk
+  gt rig repo infra --clear`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRigRepo,
+}
+
+func init() {
+	rigRepoCmd.Flags().StringVar(&rigRepoHost, "host", "", "Source host (default: github.com)")
+	rigRepoCmd.Flags().StringVar(&rigRepoDefaultBranch, "default-branch", "", "Branch PRs target (default: the rig's default branch)")
+	rigRepoCmd.Flags().BoolVar(&rigRepoClear, "clear", false, "Remove the rig's repository mapping")
+	rigCmd.AddCommand(rigRepoCmd)
+}
+
+func runRigRepo(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsPath := filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON)
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	entry, ok := rigsConfig.Rigs[rigName]
+	if !ok {
+		return fmt.Errorf("rig %q not found", rigName)
+	}
+
+	switch {
+	case rigRepoClear:
+		if len(args) > 1 {
+			return fmt.Errorf("--clear takes no repository")
+		}
+		entry.Repository = nil
+	case len(args) > 1:
+		mapping := &config.RepoMapping{Host: rigRepoHost, Name: args[1], DefaultBranch: rigRepoDefaultBranch}
+		if mapping.DefaultBranch == "" {
+			if _, r, err := getRig(rigName); err == nil {
+				mapping.DefaultBranch = r.DefaultBranch()
+			}
+		}
+		entry.Repository = mapping
+	default:
+		return showRigRepo(townRoot, rigName, entry)
+	}
+
+	rigsConfig.Rigs[rigName] = entry
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+	if entry.Repository == nil {
+		fmt.Printf("%s Cleared repository mapping of %s\n", style.Success.Render("✓"), rigName)
+		return nil
+	}
+	fmt.Printf("%s %s tracks %s/%s (default branch %s)\n", style.Success.Render("✓"), rigName,
+		entry.Repository.Hostname(), entry.Repository.Name, entry.Repository.DefaultBranch)
+	return nil
+}
+
+// showRigRepo prints a rig's repository mapping, or the repository it is
+// matched by without one.
+func showRigRepo(townRoot, rigName string, entry config.RigEntry) error {
+	if m := entry.Repository; m != nil {
+		fmt.Printf("%s/%s\n", m.Hostname(), m.Name)
+		if m.DefaultBranch != "" {
+			fmt.Printf("  default branch: %s\n", m.DefaultBranch)
+		}
+		return nil
+	}
+	host, repo, ok := rigRepo(townRoot, rigName, entry)
+	if !ok {
+		fmt.Printf("%s No repository mapping; set one with: gt rig repo %s <owner/name>\n",
+			style.Dim.Render("○"), rigName)
+		return nil
+	}
+	fmt.Printf("%s/%s %s\n", host, repo, style.Dim.Render("(inferred; no mapping in rigs.json)"))
+	return nil
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/optree"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// explainRigRestart describes what gt rig restart is about to do for
+// --explain, based on the sessions running now.
+func explainRigRestart(rigMgr *rig.Manager, t *tmux.Tmux, rigNames []string) *optree.Node {
+	plan := optree.New("gt rig restart %s", strings.Join(rigNames, " "))
+	for _, rigName := range rigNames {
+		r, err := rigMgr.GetRig(rigName)
+		if err != nil {
+			plan.Step("Rig %s: not found, skipped", rigName)
+			continue
+		}
+		node := plan.Step("Rig %s", rigName)
+
+		if !rigRestartNuclear {
+			check := node.Step("Check polecats for uncommitted work; stop here if any has some")
+			polecats, _ := polecat.NewManager(r, git.NewGit(r.Path), nil).List()
+			for _, p := range polecats {
+				clone := check.Step("polecat %s", p.Name)
+				clone.Run(optree.In(p.ClonePath, "git", "status", "--porcelain"))
+				clone.Run(optree.In(p.ClonePath, "git", "stash", "list"))
+				clone.Run(optree.In(p.ClonePath, "git", "rev-list", "--count", "@{u}..HEAD"))
+			}
+		}
+
+		stop := node.Step("Stop")
+		infos, _ := polecat.NewSessionManager(t, r).List()
+		for _, info := range infos {
+			sess := stop.Step("polecat %s", info.Polecat)
+			if !rigRestartForce {
+				sess.Run(optree.Cmd("tmux", "send-keys", "-t", info.SessionID, "C-c"))
+			}
+			sess.Run(optree.Cmd("tmux", "kill-session", "-t", info.SessionID))
+		}
+		refMgr := refinery.NewManager(r)
+		refineryRunning, _ := refMgr.IsRunning()
+		if refineryRunning {
+			stop.Step("refinery").Run(optree.Cmd("tmux", "kill-session", "-t", refMgr.SessionName()))
+		}
+		witMgr := witness.NewManager(r)
+		witnessRunning, _ := witMgr.IsRunning()
+		if witnessRunning {
+			stop.Step("witness").Run(optree.Cmd("tmux", "kill-session", "-t", witMgr.SessionName()))
+		}
+		if len(stop.Children) == 0 {
+			stop.Step("nothing running")
+		}
+
+		start := node.Step("Start")
+		start.Step("witness").Run(optree.Cmd("tmux", "new-session", "-d", "-s", witMgr.SessionName(), "<witness agent command>"))
+		start.Step("refinery").Run(optree.Cmd("tmux", "new-session", "-d", "-s", refMgr.SessionName(), "<refinery agent command>"))
+	}
+	return plan
+}
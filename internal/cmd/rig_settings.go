@@ -13,7 +13,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/fsx"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var rigSettingsCmd = &cobra.Command{
@@ -132,6 +135,12 @@ func runRigSettingsSet(cmd *cobra.Command, args []string) error {
 
 	settingsPath := filepath.Join(r.Path, "settings", "config.json")
 
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Load existing settings or create new
 	settings, err := config.LoadRigSettings(settingsPath)
 	if err != nil {
@@ -156,6 +165,13 @@ func runRigSettingsSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("saving settings: %w", err)
 	}
 
+	townRoot, _ := workspace.FindFromCwd()
+	recordAudit(townRoot, r.Name, witness.AuditEntry{
+		Action:  witness.ActionConfigChange,
+		Subject: "settings/config.json",
+		Details: map[string]string{"op": "set", "key": keyPath}, // values may be secrets
+	})
+
 	fmt.Printf("%s Set %s=%v in settings for rig %s\n",
 		style.Success.Render("✓"), keyPath, formatValueForDisplay(value), rigName)
 	return nil
@@ -172,6 +188,12 @@ func runRigSettingsUnset(cmd *cobra.Command, args []string) error {
 
 	settingsPath := filepath.Join(r.Path, "settings", "config.json")
 
+	unlock, err := fsx.Lock(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Load existing settings
 	settings, err := config.LoadRigSettings(settingsPath)
 	if err != nil {
@@ -191,6 +213,13 @@ func runRigSettingsUnset(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("saving settings: %w", err)
 	}
 
+	townRoot, _ := workspace.FindFromCwd()
+	recordAudit(townRoot, r.Name, witness.AuditEntry{
+		Action:  witness.ActionConfigChange,
+		Subject: "settings/config.json",
+		Details: map[string]string{"op": "unset", "key": keyPath},
+	})
+
 	fmt.Printf("%s Unset %s from settings for rig %s\n",
 		style.Success.Render("✓"), keyPath, rigName)
 	return nil
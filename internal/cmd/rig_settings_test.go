@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/witness"
 )
 
 // setupTestRigForSettings creates a test rig for settings testing.
@@ -191,6 +192,27 @@ func TestRigSettingsSet(t *testing.T) {
 		}
 	})
 
+	t.Run("audits the key but not the value", func(t *testing.T) {
+		townRoot, rigName := setupTestRigForSettings(t)
+		rigPath := filepath.Join(townRoot, rigName)
+
+		if err := runRigSettingsSet(rigSettingsSetCmd, []string{rigName, "env.API_TOKEN", "s3cret"}); err != nil {
+			t.Fatalf("runRigSettingsSet error: %v", err)
+		}
+
+		entries, err := witness.ReadAuditLog(rigPath)
+		if err != nil {
+			t.Fatalf("ReadAuditLog: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Details["key"] != "env.API_TOKEN" {
+			t.Fatalf("audit entries = %+v, want one for env.API_TOKEN", entries)
+		}
+		data, _ := os.ReadFile(witness.AuditLogPath(rigPath))
+		if strings.Contains(string(data), "s3cret") {
+			t.Errorf("audit log records the value:\n%s", data)
+		}
+	})
+
 	t.Run("sets nested keys with dot notation", func(t *testing.T) {
 		townRoot, rigName := setupTestRigForSettings(t)
 		rigPath := filepath.Join(townRoot, rigName)
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var rigTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List rig bootstrap templates",
+	Long: `List the templates available to gt rig add --template.
+
+A template pre-populates a new rig's settings/config.json (merge queue test
+gate, default formula, ...), creates crew workspaces, and adds the
+project's build outputs plus Gas Town's runtime directories to .gitignore.
+
+Built-in templates: go-service, node-app, docs. Towns can add their own,
+or override a built-in, as JSON files in settings/rig-templates/<name>.json:
+
+  {
+    "description": "Payments service",
+    "settings": {"merge_queue": {"test_command": "make test"}},
+    "crew": ["dev"],
+    "gitignore": ["bin/"]
+  }
+
+The "settings" object has the same shape as the rig's settings/config.json.`,
+	Args: cobra.NoArgs,
+	RunE: runRigTemplates,
+}
+
+func init() {
+	rigCmd.AddCommand(rigTemplatesCmd)
+}
+
+func runRigTemplates(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	templates, err := rig.ListTemplates(townRoot)
+	if err != nil {
+		return err
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "NAME", Width: 16},
+		style.Column{Name: "CREW", Width: 16},
+		style.Column{Name: "SOURCE", Width: 10, Style: style.Dim},
+		style.Column{Name: "DESCRIPTION", Width: 60},
+	)
+	for _, t := range templates {
+		source := t.Source
+		if source != "built-in" {
+			source = "town"
+		}
+		table.AddRow(t.Name, strings.Join(t.Crew, ", "), source, t.Description)
+	}
+	fmt.Print(table.Render())
+	fmt.Printf("\n%s\n", style.Dim.Render("Custom templates: "+rig.TemplatesDir(townRoot)))
+	return nil
+}
+
+// applyRigTemplate scaffolds a freshly added rig from a template: settings,
+// crew workspaces, and .gitignore entries for the rig clone and each crew
+// clone. Returns the crew workspaces created.
+func applyRigTemplate(townRoot string, r *rig.Rig, tmpl *rig.Template) ([]string, error) {
+	fmt.Printf("  Applying template %s...\n", style.Bold.Render(tmpl.Name))
+	if err := tmpl.ApplySettings(r.Path); err != nil {
+		return nil, fmt.Errorf("applying template settings: %w", err)
+	}
+	fmt.Printf("   ✓ Wrote settings/config.json\n")
+
+	clones := []string{filepath.Join(r.Path, "mayor", "rig")}
+	var created []string
+	if len(tmpl.Crew) > 0 {
+		crewMgr := crew.NewManager(r, git.NewGit(r.Path))
+		bd := beads.New(beads.ResolveBeadsDir(r.Path))
+		for _, name := range tmpl.Crew {
+			worker, err := crewMgr.Add(name, false)
+			if err != nil {
+				style.PrintWarning("creating crew workspace '%s': %v", name, err)
+				continue
+			}
+			ensureCrewAgentBead(townRoot, r.Name, name, bd)
+			clones = append(clones, worker.ClonePath)
+			created = append(created, name)
+			fmt.Printf("   ✓ Created crew workspace %s\n", name)
+		}
+	}
+
+	for _, dir := range clones {
+		if err := tmpl.ApplyGitignore(dir); err != nil {
+			style.PrintWarning("updating .gitignore in %s: %v", dir, err)
+		}
+	}
+	fmt.Printf("   ✓ Updated .gitignore\n")
+	return created, nil
+}
@@ -6,10 +6,12 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/i18n"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/ui"
 	"github.com/steveyegge/gastown/internal/version"
@@ -62,6 +64,11 @@ var beadsExemptCommands = map[string]bool{
 	"tap":        true,
 	"dnd":        true,
 	"krc":        true, // KRC doesn't require beads
+	"du":         true, // Only measures directories
+	"pause":      true, // Incident stop must work without beads
+	"history":    true,
+	"docs":       true,
+	"env":        true, // Reports a missing bd itself
 }
 
 // Commands exempt from the town root branch warning.
@@ -73,10 +80,28 @@ var branchCheckExemptCommands = map[string]bool{
 	"doctor":     true, // Used to fix the problem
 	"install":    true, // Initial setup
 	"git-init":   true, // Git setup
+	"docs":       true, // Works anywhere
+	"env":        true, // Diagnostic
 }
 
 // persistentPreRun runs before every command.
 func persistentPreRun(cmd *cobra.Command, args []string) error {
+	// Echo bd/gh/gt subprocesses for --show-subcommands
+	applyShowSubcommands()
+
+	// Make no network calls for --offline
+	applyOffline()
+
+	// Refuse commands with side effects in read-only mode
+	if err := checkReadOnly(cmd); err != nil {
+		return err
+	}
+
+	// Reject --explain on commands that can't explain themselves
+	if err := checkExplainSupported(cmd); err != nil {
+		return err
+	}
+
 	// Check if binary was built properly (via make build, not raw go build).
 	// Raw go build produces unsigned binaries that macOS may kill.
 	// Warning only - doesn't block execution.
@@ -88,8 +113,8 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Initialize CLI theme (dark/light mode support)
-	initCLITheme()
+	// Initialize CLI theme (dark/light mode support) and output language
+	initCLIPreferences()
 
 	// Get the root command name being run
 	cmdName := cmd.Name()
@@ -117,20 +142,25 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// initCLITheme initializes the CLI color theme based on settings and environment.
-func initCLITheme() {
-	// Try to load town settings for CLITheme config
-	var configTheme string
+// initCLIPreferences initializes the CLI color theme and output language
+// based on settings and environment.
+func initCLIPreferences() {
+	// Try to load town settings for CLITheme and Locale config
+	var configTheme, configLocale string
 	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
 		settingsPath := config.TownSettingsPath(townRoot)
 		if settings, err := config.LoadOrCreateTownSettings(settingsPath); err == nil {
 			configTheme = settings.CLITheme
+			configLocale = settings.Locale
 		}
 	}
 
 	// Initialize theme with config value (env var takes precedence inside InitTheme)
 	ui.InitTheme(configTheme)
 	ui.ApplyThemeMode()
+
+	// Likewise GT_LOCALE takes precedence over the configured locale
+	i18n.Init(configLocale)
 }
 
 // warnIfTownRootOffMain prints a warning if the town root is not on main branch.
@@ -229,15 +259,20 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
+	start := time.Now()
+	err := rootCmd.Execute()
+	code := 0
+	if err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
-		if code, ok := IsSilentExit(err); ok {
-			return code
+		if silentCode, ok := IsSilentExit(err); ok {
+			code = silentCode
+		} else {
+			// Other errors already printed by cobra
+			code = 1
 		}
-		// Other errors already printed by cobra
-		return 1
 	}
-	return 0
+	recordHistory(start, code, err)
+	return code
 }
 
 // Command group IDs - used by subcommands to organize help output
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/gh"
+	"github.com/steveyegge/gastown/internal/scm"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// rigGitHubHost returns the GitHub host a rig's PRs live on, per its
+// github settings, with the token reference resolved. Rigs without github
+// settings (or outside a town) use github.com and gh's own login.
+func rigGitHubHost(townRoot, rigPath string) (gh.Host, error) {
+	if rigPath == "" {
+		return gh.Host{}, nil
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.GitHub == nil {
+		return gh.Host{}, nil
+	}
+	host := gh.Host{Name: settings.GitHub.Host}
+	if settings.GitHub.Token != "" {
+		token, err := config.ResolveSecretRef(townRoot, settings.GitHub.Token)
+		if err != nil {
+			return gh.Host{}, fmt.Errorf("resolving github.token for %s: %w", host.Name, err)
+		}
+		host.Token = token
+	}
+	return host, nil
+}
+
+// rigSCMProvider returns the provider serving a rig's pull requests: the
+// rig's scm settings if they name one, else GitHub (see rigGitHubHost).
+func rigSCMProvider(townRoot, rigPath string) (scm.Provider, error) {
+	cfg, err := rigSCMConfig(townRoot, rigPath)
+	if err != nil {
+		return nil, err
+	}
+	return scm.New(cfg)
+}
+
+// rigSCMConfig returns the provider configuration of rigSCMProvider, with
+// the token resolved. The rig's repository mapping in rigs.json supplies
+// the repository when the scm settings don't.
+func rigSCMConfig(townRoot, rigPath string) (scm.Config, error) {
+	var cfg config.SCMConfig
+	if rigPath != "" {
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil && settings.SCM != nil {
+			cfg = *settings.SCM
+		}
+	}
+	mapping := rigRepoMapping(townRoot, rigPath)
+	if cfg.Repo == "" && mapping != nil {
+		cfg.Repo = mapping.Name
+	}
+	if cfg.Provider == "" || cfg.Provider == scm.ProviderGitHub {
+		host, err := rigGitHubHost(townRoot, rigPath)
+		if err != nil {
+			return scm.Config{}, err
+		}
+		if host.Name == "" && mapping != nil && mapping.Hostname() != config.DefaultRepoHost {
+			host.Name = mapping.Hostname()
+		}
+		return scm.Config{Provider: scm.ProviderGitHub, Host: host.Name, Repo: cfg.Repo, Token: host.Token}, nil
+	}
+
+	token, err := config.ResolveSecretRef(townRoot, cfg.Token)
+	if err != nil {
+		return scm.Config{}, fmt.Errorf("resolving scm.token: %w", err)
+	}
+	return scm.Config{Provider: cfg.Provider, Host: cfg.Host, Repo: cfg.Repo, Token: token}, nil
+}
+
+// rigRepoMapping returns the repository mapping of the rig at rigPath in
+// rigs.json, or nil.
+func rigRepoMapping(townRoot, rigPath string) *config.RepoMapping {
+	if townRoot == "" || rigPath == "" {
+		return nil
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil {
+		return nil
+	}
+	return rigsConfig.Rigs[filepath.Base(rigPath)].Repository
+}
+
+// formulaRunSCM returns the PR provider of targetRig in the town
+// containing the working directory (see formulaPRProvider).
+func formulaRunSCM(targetRig string) (scm.Provider, error) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" || targetRig == "" {
+		return formulaPRProvider("", "")
+	}
+	return formulaPRProvider(townRoot, filepath.Join(townRoot, targetRig))
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/gh"
+	"github.com/steveyegge/gastown/internal/scm"
+)
+
+func TestRigGitHubHost(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if host, err := rigGitHubHost(townRoot, rigPath); err != nil || host != (gh.Host{}) {
+		t.Errorf("without settings = %+v, %v; want github.com", host, err)
+	}
+
+	t.Setenv("GHE_TOKEN", "s3cret")
+	settings := `{"type": "rig-settings", "version": 1, "github": {"host": "ghe.example.com", "token": "secret:env:GHE_TOKEN"}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	host, err := rigGitHubHost(townRoot, rigPath)
+	if err != nil {
+		t.Fatalf("rigGitHubHost: %v", err)
+	}
+	if want := (gh.Host{Name: "ghe.example.com", Token: "s3cret"}); host != want {
+		t.Errorf("host = %+v, want %+v", host, want)
+	}
+}
+
+func TestRigSCMProvider(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := rigSCMProvider(townRoot, rigPath)
+	if err != nil || p.Name() != scm.ProviderGitHub {
+		t.Fatalf("without settings = %v, %v; want github", p, err)
+	}
+
+	t.Setenv("GITEA_TOKEN", "s3cret")
+	settings := `{"type": "rig-settings", "version": 1,
+		"scm": {"provider": "gitea", "host": "gitea.example.com", "repo": "org/gastown", "token": "secret:env:GITEA_TOKEN"}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err = rigSCMProvider(townRoot, rigPath)
+	if err != nil {
+		t.Fatalf("rigSCMProvider: %v", err)
+	}
+	want := &scm.Gitea{BaseURL: "https://gitea.example.com", Owner: "org", Repo: "gastown", Token: "s3cret"}
+	if g, ok := p.(*scm.Gitea); !ok || *g != *want {
+		t.Errorf("provider = %+v, want %+v", p, want)
+	}
+}
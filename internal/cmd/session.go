@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -279,6 +280,7 @@ func runSessionStart(cmd *cobra.Command, args []string) error {
 		agent := fmt.Sprintf("%s/%s", rigName, polecatName)
 		logger := townlog.NewLogger(townRoot)
 		_ = logger.Log(townlog.EventWake, agent, sessionIssue)
+		_ = logger.Close()
 	}
 
 	return nil
@@ -315,6 +317,7 @@ func runSessionStop(cmd *cobra.Command, args []string) error {
 		}
 		logger := townlog.NewLogger(townRoot)
 		_ = logger.Log(townlog.EventKill, agent, reason)
+		_ = logger.Close()
 	}
 
 	return nil
@@ -626,7 +629,7 @@ func runSessionCheck(cmd *cobra.Command, args []string) error {
 			}
 		}
 		if len(filtered) == 0 {
-			return fmt.Errorf("rig not found: %s", rigFilter)
+			return rigNotFoundError(rigFilter, rigNames(rigs))
 		}
 		rigs = filtered
 	}
@@ -634,6 +637,7 @@ func runSessionCheck(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s Session Health Check\n\n", style.Bold.Render("🔍"))
 
 	t := tmux.NewTmux()
+	sessions := session.OpenBackend(townRoot, t)
 	totalChecked := 0
 	totalHealthy := 0
 	totalCrashed := 0
@@ -657,7 +661,7 @@ func runSessionCheck(cmd *cobra.Command, args []string) error {
 			totalChecked++
 
 			// Check if session exists
-			running, err := t.HasSession(sessionName)
+			running, err := sessions.HasSession(sessionName)
 			if err != nil {
 				fmt.Printf("  %s %s/%s: %s\n", style.Bold.Render("⚠"), r.Name, polecatName, style.Dim.Render("error checking session"))
 				continue
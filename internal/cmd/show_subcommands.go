@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+)
+
+// Subcommand tracing flags
+var (
+	showSubcommandsFlag bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&showSubcommandsFlag, "show-subcommands", false,
+		"Echo every bd, gh, and gt subprocess as invoked (secrets redacted) with its exit status; also set by "+cmdtrace.EnvVar+"=1")
+}
+
+// applyShowSubcommands turns on subprocess tracing for --show-subcommands.
+// Child gt processes inherit it through the environment.
+func applyShowSubcommands() {
+	if showSubcommandsFlag {
+		cmdtrace.Enable()
+	}
+}
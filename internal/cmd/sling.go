@@ -10,7 +10,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -43,6 +45,7 @@ Target Resolution:
   gt sling gp-abc greenplace               # Auto-spawn polecat in rig
   gt sling gt-abc greenplace/Toast         # Specific polecat
   gt sling gt-abc mayor                 # Mayor
+  gt sling gt-abc greenplace/crew       # Dispatch to an idle crew worker
   gt sling gt-abc deacon/dogs           # Auto-dispatch to idle dog
   gt sling gt-abc deacon/dogs/alpha     # Specific dog
 
@@ -51,6 +54,9 @@ Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --force                # Ignore unread mail
   gt sling gp-abc greenplace --account work         # Use specific Claude account
 
+While the town is paused (gt pause), sling refuses to dispatch unless
+--override-pause is given.
+
 Natural Language Args:
   gt sling gt-abc --args "patch release"
   gt sling code-review --args "focus on security"
@@ -58,6 +64,16 @@ Natural Language Args:
 The --args string is stored in the bead and shown via gt prime. Since the
 executor is an LLM, it interprets these instructions naturally.
 
+Structured Context (--context-file):
+  gt sling gt-abc gastown --context-file leg.json
+  gt sling gastown --context-file leg.toml   # Bead taken from bead_id
+
+  Instead of long -a/-s strings, pass a JSON or TOML payload with any of:
+  bead_id, convoy_id, subject, args, prompt, output_path, workdir, branch,
+  and an env table. The payload is saved under .runtime/sling-context/,
+  linked from the bead, and shown via gt prime; env is set in the target's
+  tmux session. Explicit -s/-a flags win over payload values.
+
 Formula Slinging:
   gt sling mol-release mayor/           # Cook + wisp + attach + nudge
   gt sling towers-of-hanoi --var disks=3
@@ -99,6 +115,8 @@ var (
 	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
 	slingNoMerge  bool   // --no-merge: skip merge queue on completion (for upstream PRs/human review)
 	slingNoBoot   bool   // --no-boot: skip waking witness+refinery after dispatch (G11)
+
+	slingOverridePause bool // --override-pause: dispatch even though the town is paused
 )
 
 func init() {
@@ -118,6 +136,7 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingHookRawBead, "hook-raw-bead", false, "Hook raw bead without default formula (expert mode)")
 	slingCmd.Flags().BoolVar(&slingNoMerge, "no-merge", false, "Skip merge queue on completion (keep work on feature branch for review)")
 	slingCmd.Flags().BoolVar(&slingNoBoot, "no-boot", false, "Skip waking witness+refinery after polecat dispatch (avoids dolt lock contention)")
+	slingCmd.Flags().BoolVar(&slingOverridePause, "override-pause", false, "Dispatch even though the town is paused (gt pause)")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -136,6 +155,33 @@ func runSling(cmd *cobra.Command, args []string) error {
 	}
 	townBeadsDir := filepath.Join(townRoot, ".beads")
 
+	// No new dispatches while the town is paused (gt pause)
+	if !slingDryRun {
+		if err := checkTownPause(townRoot, slingOverridePause); err != nil {
+			return err
+		}
+	}
+
+	// Load the structured context payload (--context-file). It may name the
+	// bead, so it is applied before args are interpreted.
+	var payload *slingPayload
+	var payloadEnv map[string]string
+	var payloadContainer *formula.LegContainer
+	if slingContextFile != "" {
+		payload, err = loadSlingPayload(slingContextFile)
+		if err != nil {
+			return err
+		}
+		args, err = applySlingPayload(args, payload)
+		if err != nil {
+			return err
+		}
+		if payloadEnv, err = payload.resolvedEnv(townRoot); err != nil {
+			return err
+		}
+		payloadContainer = payload.container()
+	}
+
 	// Normalize target arguments: trim trailing slashes from target to handle tab-completion
 	// artifacts like "gt sling sl-123 slingshot/" → "gt sling sl-123 slingshot"
 	// This makes sling more forgiving without breaking existing functionality.
@@ -199,11 +245,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
-	var hookWorkDir string                  // Working directory for running bd hook commands
-	var hookSetAtomically bool              // True if hook was set during polecat spawn (skip redundant update)
-	var delayedDogInfo *DogDispatchInfo     // For delayed dog session start after hook is set
-	var newPolecatInfo *SpawnedPolecatInfo  // Spawned polecat info (session started after bead setup)
-	var isSelfSling bool                    // True if slinging to self (skip nudge - agent already knows)
+	var hookWorkDir string                 // Working directory for running bd hook commands
+	var hookSetAtomically bool             // True if hook was set during polecat spawn (skip redundant update)
+	var delayedDogInfo *DogDispatchInfo    // For delayed dog session start after hook is set
+	var newPolecatInfo *SpawnedPolecatInfo // Spawned polecat info (session started after bead setup)
+	var isSelfSling bool                   // True if slinging to self (skip nudge - agent already knows)
 
 	if len(args) > 1 {
 		target := args[1]
@@ -254,18 +300,20 @@ func runSling(cmd *cobra.Command, args []string) error {
 				// Spawn a fresh polecat in the rig
 				fmt.Printf("Target is rig '%s', spawning fresh polecat...\n", rigName)
 				spawnOpts := SlingSpawnOptions{
-					Force:    slingForce,
-					Account:  slingAccount,
-					Create:   slingCreate,
-					HookBead: beadID, // Set atomically at spawn time
-					Agent:    slingAgent,
+					Force:     slingForce,
+					Account:   slingAccount,
+					Create:    slingCreate,
+					HookBead:  beadID, // Set atomically at spawn time
+					Agent:     slingAgent,
+					Env:       payloadEnv,
+					Container: payloadContainer,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
 					return fmt.Errorf("spawning polecat: %w", spawnErr)
 				}
 				targetAgent = spawnInfo.AgentID()
-				newPolecatInfo = spawnInfo      // Store for later session start
+				newPolecatInfo = spawnInfo        // Store for later session start
 				hookWorkDir = spawnInfo.ClonePath // Run bd commands from polecat's worktree
 				hookSetAtomically = true          // Hook was set during spawn (GH #gt-mzyk5)
 
@@ -288,11 +336,13 @@ func runSling(cmd *cobra.Command, args []string) error {
 						rigName := parts[0]
 						fmt.Printf("Target polecat has no active session, spawning fresh polecat in rig '%s'...\n", rigName)
 						spawnOpts := SlingSpawnOptions{
-							Force:    slingForce,
-							Account:  slingAccount,
-							Create:   slingCreate,
-							HookBead: beadID,
-							Agent:    slingAgent,
+							Force:     slingForce,
+							Account:   slingAccount,
+							Create:    slingCreate,
+							HookBead:  beadID,
+							Agent:     slingAgent,
+							Env:       payloadEnv,
+							Container: payloadContainer,
 						}
 						spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 						if spawnErr != nil {
@@ -394,7 +444,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 		// Unhook the bead from old owner (set status back to open)
 		unhookCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--status=open", "--assignee=")
 		unhookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, "")
-		if err := unhookCmd.Run(); err != nil {
+		if err := cmdtrace.Run(unhookCmd); err != nil {
 			fmt.Printf("%s Could not unhook bead from old owner: %v\n", style.Dim.Render("Warning:"), err)
 		}
 	}
@@ -449,6 +499,18 @@ func runSling(cmd *cobra.Command, args []string) error {
 		if slingArgs != "" {
 			fmt.Printf("  args (in nudge): %s\n", slingArgs)
 		}
+		if payload != nil {
+			fmt.Printf("  context file: %s → %s\n", slingContextFile, slingPayloadPath(townRoot, beadID))
+			if payload.OutputPath != "" {
+				fmt.Printf("    output: %s\n", payload.OutputPath)
+			}
+			for k, v := range payload.Env {
+				fmt.Printf("    env: %s=%s\n", k, v)
+			}
+			if payloadContainer != nil {
+				fmt.Printf("    container: %s\n", payloadContainer.Image)
+			}
+		}
 		fmt.Printf("Would inject start prompt to pane: %s\n", targetPane)
 		return nil
 	}
@@ -495,7 +557,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 		hookCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--status=hooked", "--assignee="+targetAgent)
 		hookCmd.Dir = hookDir
 		hookCmd.Stderr = os.Stderr
-		if err := hookCmd.Run(); err != nil {
+		if err := cmdtrace.Run(hookCmd); err != nil {
 			lastErr = err
 			if attempt < maxRetries {
 				backoff := time.Duration(attempt*500) * time.Millisecond
@@ -570,6 +632,19 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Store the structured context payload and link it from the bead
+	if payload != nil {
+		payloadPath, err := saveSlingPayload(townRoot, beadID, payload)
+		if err == nil {
+			err = storeContextInBead(beadID, payloadPath)
+		}
+		if err != nil {
+			fmt.Printf("%s Could not store context payload: %v\n", style.Dim.Render("Warning:"), err)
+		} else {
+			fmt.Printf("%s Context payload stored: %s\n", style.Bold.Render("✓"), payloadPath)
+		}
+	}
+
 	// Store no_merge flag in bead (skips merge queue on completion)
 	if slingNoMerge {
 		if err := storeNoMergeInBead(beadID, true); err != nil {
@@ -612,6 +687,14 @@ func runSling(cmd *cobra.Command, args []string) error {
 		targetPane = pane
 	}
 
+	if payload != nil && !isSelfSling {
+		applySlingPayloadEnv(targetPane, payloadEnv)
+	}
+	if payloadContainer != nil && !freshlySpawned {
+		fmt.Printf("%s Context file asks for a container, but %s is already running on the host\n",
+			style.Dim.Render("Warning:"), targetAgent)
+	}
+
 	// Try to inject the "start now" prompt (graceful if no tmux)
 	// Skip for freshly spawned polecats - SessionManager.Start() already sent StartupNudge.
 	// Skip for self-sling - agent is currently processing the sling command and will see
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// slingContextFile is the --context-file flag: a structured payload that
+// replaces long -a/-s strings.
+var slingContextFile string
+
+func init() {
+	slingCmd.Flags().StringVar(&slingContextFile, "context-file", "", "JSON or TOML payload with prompt, output path, bead/convoy IDs, and env")
+}
+
+// slingPayload is the structured context handed to an agent by
+// 'gt sling --context-file'. It is stored under the town's .runtime/ and
+// linked from the bead so gt prime can show it.
+type slingPayload struct {
+	BeadID     string            `json:"bead_id,omitempty" toml:"bead_id"`
+	ConvoyID   string            `json:"convoy_id,omitempty" toml:"convoy_id"`
+	Subject    string            `json:"subject,omitempty" toml:"subject"`
+	Args       string            `json:"args,omitempty" toml:"args"`
+	Prompt     string            `json:"prompt,omitempty" toml:"prompt"`
+	OutputPath string            `json:"output_path,omitempty" toml:"output_path"`
+	Workdir    string            `json:"workdir,omitempty" toml:"workdir"`
+	Branch     string            `json:"branch,omitempty" toml:"branch"`
+	Env        map[string]string `json:"env,omitempty" toml:"env"`
+	Agent      string            `json:"agent,omitempty" toml:"agent"` // Default for --agent
+
+	// Expect is the leg's resolved output contract, checked by gt done.
+	Expect *formula.LegExpect `json:"expect,omitempty" toml:"expect"`
+
+	// Container runs the agent in a container instead of on the host.
+	Container *formula.LegContainer `json:"container,omitempty" toml:"container"`
+
+	// Needs lists the leg beads that must close before this leg is
+	// dispatched; gt convoy check slings it once they have.
+	Needs []string `json:"needs,omitempty" toml:"needs"`
+
+	// Canary lists the canary leg beads of a gt formula run --canary run.
+	// The leg is dispatched once they close with their output contracts
+	// met, and closed undispatched if one fails.
+	Canary []string `json:"canary,omitempty" toml:"canary"`
+
+	// Retry is the leg's retry policy; gt convoy check re-queues the leg
+	// when it fails in a way the policy covers. Retries counts the
+	// retries made so far.
+	Retry   *formula.LegRetry `json:"retry,omitempty" toml:"retry"`
+	Retries int               `json:"retries,omitempty" toml:"retries"`
+
+	// Priority is the leg's dispatch priority; low priority legs may be
+	// paused for urgent ones.
+	Priority convoy.Priority `json:"priority,omitempty" toml:"priority"`
+}
+
+// loadSlingPayload reads a payload file. The format is chosen by extension
+// (.json or .toml); other extensions are tried as JSON, then TOML.
+func loadSlingPayload(path string) (*slingPayload, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is user-provided by design
+	if err != nil {
+		return nil, fmt.Errorf("reading context file: %w", err)
+	}
+
+	var p slingPayload
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &p)
+	case ".toml":
+		_, err = toml.Decode(string(data), &p)
+	default:
+		if err = json.Unmarshal(data, &p); err != nil {
+			p = slingPayload{}
+			_, err = toml.Decode(string(data), &p)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing context file %s: %w", path, err)
+	}
+	if p.Container != nil {
+		if _, err := formula.NewLegContainer(formula.RunnerDocker, p.Container.Image, p.Container.Mounts); err != nil {
+			return nil, fmt.Errorf("context file %s: %w", path, err)
+		}
+	}
+	return &p, nil
+}
+
+// applySlingPayload merges a payload into the sling arguments and flags.
+// Explicit flags win over payload values. If the payload names a bead and
+// the arguments don't start with it, the bead is prepended so that
+// 'gt sling <target> --context-file leg.json' works.
+func applySlingPayload(args []string, p *slingPayload) ([]string, error) {
+	if p.BeadID != "" {
+		switch {
+		case len(args) > 0 && args[0] == p.BeadID:
+		case len(args) > 1 && looksLikeBeadID(args[0]):
+			return nil, fmt.Errorf("bead %s does not match context file bead_id %s", args[0], p.BeadID)
+		default:
+			args = append([]string{p.BeadID}, args...)
+		}
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no bead given and context file has no bead_id")
+	}
+
+	if slingSubject == "" {
+		slingSubject = p.Subject
+	}
+	if slingAgent == "" {
+		slingAgent = p.Agent
+	}
+	if slingArgs == "" {
+		// Args travel in a single-line bead field; the full prompt stays in the payload.
+		slingArgs = strings.Join(strings.Fields(p.Args), " ")
+	}
+	return args, nil
+}
+
+// slingPayloadPath returns where a bead's payload is stored in the town.
+func slingPayloadPath(townRoot, beadID string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "sling-context", beadID+".json")
+}
+
+// saveSlingPayload stores a payload for beadID under the town's .runtime/.
+func saveSlingPayload(townRoot, beadID string, p *slingPayload) (string, error) {
+	p.BeadID = beadID
+	path := slingPayloadPath(townRoot, beadID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("creating sling-context dir: %w", err)
+	}
+	if err := util.AtomicWriteJSON(path, p); err != nil {
+		return "", fmt.Errorf("writing context payload: %w", err)
+	}
+	return path, nil
+}
+
+// storeContextInBead records the payload path in the bead's description.
+func storeContextInBead(beadID, payloadPath string) error {
+	// Get the bead to preserve existing description content
+	showCmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
+	showCmd.Dir = resolveBeadDir(beadID)
+	out, err := cmdtrace.Output(showCmd)
+	if err != nil {
+		return fmt.Errorf("fetching bead: %w", err)
+	}
+
+	var issues []beads.Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return fmt.Errorf("parsing bead: %w", err)
+	}
+	if len(issues) == 0 {
+		return fmt.Errorf("bead not found")
+	}
+	issue := &issues[0]
+
+	fields := beads.ParseAttachmentFields(issue)
+	if fields == nil {
+		fields = &beads.AttachmentFields{}
+	}
+	fields.AttachedContext = payloadPath
+
+	updateCmd := exec.Command("bd", "update", beadID, "--description="+beads.SetAttachmentFields(issue, fields))
+	updateCmd.Dir = resolveBeadDir(beadID)
+	updateCmd.Stderr = os.Stderr
+	if err := cmdtrace.Run(updateCmd); err != nil {
+		return fmt.Errorf("updating bead description: %w", err)
+	}
+	return nil
+}
+
+// resolvedEnv returns the payload's env with secret references resolved.
+// The stored payload keeps the references, so secrets never land in
+// .runtime/sling-context/.
+func (p *slingPayload) resolvedEnv(townRoot string) (map[string]string, error) {
+	if len(p.Env) == 0 {
+		return nil, nil
+	}
+	env := make(map[string]string, len(p.Env))
+	for k, v := range p.Env {
+		value, err := config.ResolveSecretRef(townRoot, v)
+		if err != nil {
+			return nil, fmt.Errorf("context env %s: %w", k, err)
+		}
+		env[k] = value
+	}
+	return env, nil
+}
+
+// container returns the payload's container with the leg's worktree, if
+// any, bound at its host path so the agent can reach it.
+func (p *slingPayload) container() *formula.LegContainer {
+	if p.Container == nil {
+		return nil
+	}
+	c := *p.Container
+	if p.Workdir != "" {
+		c.Mounts = append(append([]string(nil), c.Mounts...), p.Workdir+":"+p.Workdir)
+	}
+	return &c
+}
+
+// applySlingPayloadEnv sets the payload's resolved env in the target's tmux
+// session so commands the agent runs from then on see it.
+func applySlingPayloadEnv(targetPane string, env map[string]string) {
+	if len(env) == 0 || targetPane == "" {
+		return
+	}
+	sessionName := getSessionFromPane(targetPane)
+	if sessionName == "" {
+		return
+	}
+	t := tmux.NewTmux()
+	for k, v := range env {
+		if err := t.SetEnvironment(sessionName, k, v); err != nil {
+			fmt.Printf("%s Could not set %s in %s: %v\n", style.Dim.Render("Warning:"), k, sessionName, err)
+		}
+	}
+}
+
+// printSlingPayload shows a stored payload in gt prime output.
+func printSlingPayload(path string) {
+	p, err := loadSlingPayload(path)
+	if err != nil {
+		fmt.Printf("  Context file: %s (unreadable: %v)\n", path, err)
+		return
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("📦 CONTEXT (from gt sling --context-file):"))
+	if p.ConvoyID != "" {
+		fmt.Printf("  Convoy: %s\n", p.ConvoyID)
+	}
+	if p.OutputPath != "" {
+		fmt.Printf("  Write output to: %s\n", p.OutputPath)
+	}
+	if p.Workdir != "" {
+		fmt.Printf("  Work in: %s", p.Workdir)
+		if p.Branch != "" {
+			fmt.Printf(" (branch %s)", p.Branch)
+		}
+		fmt.Println()
+	}
+	if len(p.Env) > 0 {
+		keys := make([]string, 0, len(p.Env))
+		for k := range p.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Println("  Env:")
+		for _, k := range keys {
+			fmt.Printf("    %s=%s\n", k, p.Env[k])
+		}
+	}
+	if p.Prompt != "" {
+		fmt.Println("  Prompt:")
+		for _, line := range strings.Split(strings.TrimRight(p.Prompt, "\n"), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
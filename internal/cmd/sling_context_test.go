@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSlingPayload(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "leg.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"bead_id":"hq-leg-abc","convoy_id":"hq-cv-xyz","prompt":"Review it","output_path":".reviews/r1/security.md","env":{"GT_LEG":"security"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tomlPath := filepath.Join(dir, "leg.toml")
+	if err := os.WriteFile(tomlPath, []byte(`bead_id = "hq-leg-abc"
+convoy_id = "hq-cv-xyz"
+prompt = """
+Review it"""
+output_path = ".reviews/r1/security.md"
+
+[env]
+GT_LEG = "security"
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{jsonPath, tomlPath} {
+		p, err := loadSlingPayload(path)
+		if err != nil {
+			t.Fatalf("loadSlingPayload(%s): %v", path, err)
+		}
+		if p.BeadID != "hq-leg-abc" || p.ConvoyID != "hq-cv-xyz" || p.OutputPath != ".reviews/r1/security.md" {
+			t.Errorf("%s: got %+v", path, p)
+		}
+		if strings.TrimSpace(p.Prompt) != "Review it" {
+			t.Errorf("%s: prompt = %q", path, p.Prompt)
+		}
+		if p.Env["GT_LEG"] != "security" {
+			t.Errorf("%s: env = %v", path, p.Env)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSlingPayload(bad); err == nil {
+		t.Error("expected parse error for malformed JSON")
+	}
+}
+
+func TestApplySlingPayload(t *testing.T) {
+	oldSubject, oldArgs, oldAgent := slingSubject, slingArgs, slingAgent
+	defer func() { slingSubject, slingArgs, slingAgent = oldSubject, oldArgs, oldAgent }()
+
+	tests := []struct {
+		name    string
+		args    []string
+		payload slingPayload
+		want    []string
+		wantErr bool
+	}{
+		{"bead from payload", []string{"gastown"}, slingPayload{BeadID: "gt-abc"}, []string{"gt-abc", "gastown"}, false},
+		{"bead matches", []string{"gt-abc", "gastown"}, slingPayload{BeadID: "gt-abc"}, []string{"gt-abc", "gastown"}, false},
+		{"bead mismatch", []string{"gt-def", "gastown"}, slingPayload{BeadID: "gt-abc"}, nil, true},
+		{"no bead anywhere", nil, slingPayload{}, nil, true},
+		{"bead from args only", []string{"gt-abc"}, slingPayload{}, []string{"gt-abc"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slingSubject, slingArgs = "", ""
+			got, err := applySlingPayload(tt.args, &tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("args = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	slingSubject, slingArgs = "explicit", ""
+	if _, err := applySlingPayload([]string{"gt-abc"}, &slingPayload{Subject: "from payload", Args: "line one\nline two"}); err != nil {
+		t.Fatal(err)
+	}
+	if slingSubject != "explicit" {
+		t.Errorf("subject = %q, explicit flag should win", slingSubject)
+	}
+	if slingArgs != "line one line two" {
+		t.Errorf("args = %q, want flattened payload args", slingArgs)
+	}
+
+	slingAgent = ""
+	if _, err := applySlingPayload([]string{"gt-abc"}, &slingPayload{Agent: "codex"}); err != nil {
+		t.Fatal(err)
+	}
+	if slingAgent != "codex" {
+		t.Errorf("agent = %q, want payload agent when --agent is unset", slingAgent)
+	}
+}
+
+func TestSlingPayloadContainer(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "leg.json")
+	if err := os.WriteFile(path, []byte(`{"workdir":"/town/rig/.worktrees/build","container":{"image":"golang:1.22","mounts":["/cache:/cache"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := loadSlingPayload(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := p.container()
+	if c == nil || c.Image != "golang:1.22" || len(c.Mounts) != 2 || c.Mounts[1] != "/town/rig/.worktrees/build:/town/rig/.worktrees/build" {
+		t.Errorf("container() = %+v", c)
+	}
+	if len(p.Container.Mounts) != 1 {
+		t.Errorf("container() modified the payload: %+v", p.Container)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"container":{"image":""}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSlingPayload(path); err == nil {
+		t.Error("expected error for container without image")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -35,7 +36,7 @@ func isTrackedByConvoy(beadID string) string {
 	depCmd := exec.Command("bd", "--no-daemon", "dep", "list", beadID, "--direction=up", "--type=tracks", "--json")
 	depCmd.Dir = townRoot
 
-	out, err := depCmd.Output()
+	out, err := cmdtrace.Output(depCmd)
 	if err == nil {
 		var trackers []struct {
 			ID        string `json:"id"`
@@ -64,7 +65,7 @@ func findConvoyByDescription(townRoot, beadID string) string {
 	listCmd := exec.Command("bd", "--no-daemon", "list", "--type=convoy", "--status=open", "--json")
 	listCmd.Dir = filepath.Join(townRoot, ".beads")
 
-	out, err := listCmd.Output()
+	out, err := cmdtrace.Output(listCmd)
 	if err != nil {
 		return ""
 	}
@@ -121,7 +122,7 @@ func createAutoConvoy(beadID, beadTitle string) (string, error) {
 	createCmd.Dir = townBeads
 	createCmd.Stderr = os.Stderr
 
-	if err := createCmd.Run(); err != nil {
+	if err := cmdtrace.Run(createCmd); err != nil {
 		return "", fmt.Errorf("creating convoy: %w", err)
 	}
 
@@ -132,7 +133,7 @@ func createAutoConvoy(beadID, beadTitle string) (string, error) {
 	depCmd.Dir = townBeads
 	depCmd.Stderr = os.Stderr
 
-	if err := depCmd.Run(); err != nil {
+	if err := cmdtrace.Run(depCmd); err != nil {
 		// Convoy was created but tracking failed - log warning but continue
 		fmt.Printf("%s Could not add tracking relation: %v\n", style.Dim.Render("Warning:"), err)
 	}
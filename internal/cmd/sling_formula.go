@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -57,13 +58,13 @@ func verifyFormulaExists(formulaName string) error {
 	// Use Output() instead of Run() to detect bd --no-daemon exit 0 bug:
 	// when formula not found, --no-daemon may exit 0 but produce empty stdout.
 	cmd := exec.Command("bd", "--no-daemon", "formula", "show", formulaName, "--allow-stale")
-	if out, err := cmd.Output(); err == nil && len(out) > 0 {
+	if out, err := cmdtrace.Output(cmd); err == nil && len(out) > 0 {
 		return nil
 	}
 
 	// Try with mol- prefix
 	cmd = exec.Command("bd", "--no-daemon", "formula", "show", "mol-"+formulaName, "--allow-stale")
-	if out, err := cmd.Output(); err == nil && len(out) > 0 {
+	if out, err := cmdtrace.Output(cmd); err == nil && len(out) > 0 {
 		return nil
 	}
 
@@ -200,7 +201,7 @@ func runSlingFormula(args []string) error {
 	cookCmd := exec.Command("bd", cookArgs...)
 	cookCmd.Dir = formulaWorkDir
 	cookCmd.Stderr = os.Stderr
-	if err := cookCmd.Run(); err != nil {
+	if err := cmdtrace.Run(cookCmd); err != nil {
 		return fmt.Errorf("cooking formula: %w", err)
 	}
 
@@ -215,7 +216,7 @@ func runSlingFormula(args []string) error {
 	wispCmd := exec.Command("bd", wispArgs...)
 	wispCmd.Dir = formulaWorkDir
 	wispCmd.Stderr = os.Stderr // Show wisp errors to user
-	wispOut, err := wispCmd.Output()
+	wispOut, err := cmdtrace.Output(wispCmd)
 	if err != nil {
 		return fmt.Errorf("creating wisp: %w", err)
 	}
@@ -234,7 +235,7 @@ func runSlingFormula(args []string) error {
 	hookCmd := exec.Command("bd", "--no-daemon", "update", wispRootID, "--status=hooked", "--assignee="+targetAgent)
 	hookCmd.Dir = beads.ResolveHookDir(townRoot, wispRootID, "")
 	hookCmd.Stderr = os.Stderr
-	if err := hookCmd.Run(); err != nil {
+	if err := cmdtrace.Run(hookCmd); err != nil {
 		return fmt.Errorf("hooking wisp bead: %w", err)
 	}
 	fmt.Printf("%s Attached to hook (status=hooked)\n", style.Bold.Render("✓"))
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -78,7 +79,7 @@ type beadInfo struct {
 func verifyBeadExists(beadID string) error {
 	cmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
 	cmd.Dir = resolveBeadDir(beadID)
-	out, err := cmd.Output()
+	out, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return fmt.Errorf("bead '%s' not found (bd show failed)", beadID)
 	}
@@ -93,7 +94,7 @@ func verifyBeadExists(beadID string) error {
 func getBeadInfo(beadID string) (*beadInfo, error) {
 	cmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
 	cmd.Dir = resolveBeadDir(beadID)
-	out, err := cmd.Output()
+	out, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("bead '%s' not found", beadID)
 	}
@@ -117,7 +118,7 @@ func storeArgsInBead(beadID, args string) error {
 	// Get the bead to preserve existing description content
 	showCmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
 	showCmd.Dir = resolveBeadDir(beadID)
-	out, err := showCmd.Output()
+	out, err := cmdtrace.Output(showCmd)
 	if err != nil {
 		return fmt.Errorf("fetching bead: %w", err)
 	}
@@ -157,7 +158,7 @@ func storeArgsInBead(beadID, args string) error {
 	// Update the bead
 	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
 	updateCmd.Stderr = os.Stderr
-	if err := updateCmd.Run(); err != nil {
+	if err := cmdtrace.Run(updateCmd); err != nil {
 		return fmt.Errorf("updating bead description: %w", err)
 	}
 
@@ -173,7 +174,7 @@ func storeDispatcherInBead(beadID, dispatcher string) error {
 
 	// Get the bead to preserve existing description content
 	showCmd := exec.Command("bd", "show", beadID, "--json")
-	out, err := showCmd.Output()
+	out, err := cmdtrace.Output(showCmd)
 	if err != nil {
 		return fmt.Errorf("fetching bead: %w", err)
 	}
@@ -203,7 +204,7 @@ func storeDispatcherInBead(beadID, dispatcher string) error {
 	// Update the bead
 	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
 	updateCmd.Stderr = os.Stderr
-	if err := updateCmd.Run(); err != nil {
+	if err := cmdtrace.Run(updateCmd); err != nil {
 		return fmt.Errorf("updating bead description: %w", err)
 	}
 
@@ -226,7 +227,7 @@ func storeAttachedMoleculeInBead(beadID, moleculeID string) error {
 	if logPath == "" {
 		// Get the bead to preserve existing description content
 		showCmd := exec.Command("bd", "show", beadID, "--json")
-		out, err := showCmd.Output()
+		out, err := cmdtrace.Output(showCmd)
 		if err != nil {
 			return fmt.Errorf("fetching bead: %w", err)
 		}
@@ -263,7 +264,7 @@ func storeAttachedMoleculeInBead(beadID, moleculeID string) error {
 	// Update the bead
 	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
 	updateCmd.Stderr = os.Stderr
-	if err := updateCmd.Run(); err != nil {
+	if err := cmdtrace.Run(updateCmd); err != nil {
 		return fmt.Errorf("updating bead description: %w", err)
 	}
 
@@ -280,7 +281,7 @@ func storeNoMergeInBead(beadID string, noMerge bool) error {
 
 	// Get the bead to preserve existing description content
 	showCmd := exec.Command("bd", "show", beadID, "--json")
-	out, err := showCmd.Output()
+	out, err := cmdtrace.Output(showCmd)
 	if err != nil {
 		return fmt.Errorf("fetching bead: %w", err)
 	}
@@ -310,7 +311,7 @@ func storeNoMergeInBead(beadID string, noMerge bool) error {
 	// Update the bead
 	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
 	updateCmd.Stderr = os.Stderr
-	if err := updateCmd.Run(); err != nil {
+	if err := cmdtrace.Run(updateCmd); err != nil {
 		return fmt.Errorf("updating bead description: %w", err)
 	}
 
@@ -529,7 +530,7 @@ func updateAgentHookBead(agentID, beadID, workDir, townBeadsDir string) {
 func wakeRigAgents(rigName string) {
 	// Boot the rig (idempotent - no-op if already running)
 	bootCmd := exec.Command("gt", "rig", "boot", rigName)
-	_ = bootCmd.Run() // Ignore errors - rig might already be running
+	_ = cmdtrace.Run(bootCmd) // Ignore errors - rig might already be running
 
 	// Nudge witness to clear any backoff
 	t := tmux.NewTmux()
@@ -592,12 +593,16 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	// Route bd mutations (wisp/bond) to the correct beads context for the target bead.
 	formulaWorkDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 
+	if err := checkSlingFeatureVar(formulaName, title); err != nil {
+		return nil, err
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	if !skipCook {
 		cookCmd := exec.Command("bd", "cook", formulaName)
 		cookCmd.Dir = formulaWorkDir
 		cookCmd.Stderr = os.Stderr
-		if err := cookCmd.Run(); err != nil {
+		if err := cmdtrace.Run(cookCmd); err != nil {
 			return nil, fmt.Errorf("cooking formula %s: %w", formulaName, err)
 		}
 	}
@@ -614,7 +619,7 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	wispCmd.Dir = formulaWorkDir
 	wispCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 	wispCmd.Stderr = os.Stderr
-	wispOut, err := wispCmd.Output()
+	wispOut, err := cmdtrace.Output(wispCmd)
 	if err != nil {
 		return nil, fmt.Errorf("creating wisp for formula %s: %w", formulaName, err)
 	}
@@ -630,7 +635,7 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	bondCmd := exec.Command("bd", bondArgs...)
 	bondCmd.Dir = formulaWorkDir
 	bondCmd.Stderr = os.Stderr
-	bondOut, err := bondCmd.Output()
+	bondOut, err := cmdtrace.Output(bondCmd)
 	if err != nil {
 		return nil, fmt.Errorf("bonding formula to bead: %w", err)
 	}
@@ -655,5 +660,5 @@ func CookFormula(formulaName, workDir string) error {
 	cookCmd := exec.Command("bd", "cook", formulaName)
 	cookCmd.Dir = workDir
 	cookCmd.Stderr = os.Stderr
-	return cookCmd.Run()
+	return cmdtrace.Run(cookCmd)
 }
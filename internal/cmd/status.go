@@ -18,7 +18,9 @@ import (
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mayor"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -56,12 +58,14 @@ func init() {
 
 // TownStatus represents the overall status of the workspace.
 type TownStatus struct {
-	Name     string         `json:"name"`
-	Location string         `json:"location"`
-	Overseer *OverseerInfo  `json:"overseer,omitempty"` // Human operator
-	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
-	Rigs     []RigStatus    `json:"rigs"`
-	Summary  StatusSum      `json:"summary"`
+	Name        string             `json:"name"`
+	Location    string             `json:"location"`
+	Overseer    *OverseerInfo      `json:"overseer,omitempty"`    // Human operator
+	Paused      *mayor.TownPause   `json:"paused,omitempty"`      // Set while dispatch is paused (gt pause)
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty"` // Set during a maintenance window
+	Agents      []AgentRuntime     `json:"agents"`                // Global agents (Mayor, Deacon)
+	Rigs        []RigStatus        `json:"rigs"`
+	Summary     StatusSum          `json:"summary"`
 }
 
 // OverseerInfo represents the human operator's identity and status.
@@ -212,13 +216,21 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	// Create tmux instance for runtime checks
 	t := tmux.NewTmux()
 
-	// Pre-fetch all tmux sessions for O(1) lookup
+	// Pre-fetch all tmux sessions for O(1) lookup, plus polecat sessions
+	// when they run in another backend
 	allSessions := make(map[string]bool)
 	if sessions, err := t.ListSessions(); err == nil {
 		for _, s := range sessions {
 			allSessions[s] = true
 		}
 	}
+	if backend := session.OpenBackend(townRoot, t); backend.Type() != session.BackendTmux {
+		if sessions, err := backend.ListSessions(); err == nil {
+			for _, s := range sessions {
+				allSessions[s] = true
+			}
+		}
+	}
 
 	// Discover rigs
 	rigs, err := mgr.DiscoverRigs()
@@ -345,6 +357,10 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		Overseer: overseerInfo,
 		Rigs:     make([]RigStatus, len(rigs)),
 	}
+	if paused, pause, _ := mayor.IsTownPaused(townRoot); paused {
+		status.Paused = pause
+	}
+	status.Maintenance = townMaintenanceStatus(townRoot)
 
 	var wg sync.WaitGroup
 
@@ -441,6 +457,30 @@ func outputStatusText(status TownStatus) error {
 	fmt.Printf("%s %s\n", style.Bold.Render("Town:"), status.Name)
 	fmt.Printf("%s\n\n", style.Dim.Render(status.Location))
 
+	// Dispatch pause banner
+	if p := status.Paused; p != nil {
+		banner := "⏸️  DISPATCH PAUSED since " + p.PausedAt.Local().Format("2006-01-02 15:04")
+		if p.PausedBy != "" {
+			banner += " by " + p.PausedBy
+		}
+		fmt.Println(style.Warning.Render(banner))
+		if p.Reason != "" {
+			fmt.Printf("   Reason: %s\n", p.Reason)
+		}
+		fmt.Printf("   %s\n\n", style.Dim.Render("No new work is dispatched. Resume with: gt resume --town"))
+	}
+
+	// Maintenance window banner
+	if m := status.Maintenance; m != nil {
+		banner := "🔧 MAINTENANCE WINDOW " + m.Window + maintenanceUntil(m.Until)
+		fmt.Println(style.Warning.Render(banner))
+		detail := "Patrols are suspended; formula and plugin runs are queued"
+		if m.Queued > 0 {
+			detail += fmt.Sprintf(" (%d waiting)", m.Queued)
+		}
+		fmt.Printf("   %s\n\n", style.Dim.Render(detail+"."))
+	}
+
 	// Overseer info
 	if status.Overseer != nil {
 		overseerDisplay := status.Overseer.Name
@@ -629,8 +669,8 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 	case "muted", "paused", "degraded":
 		// Other intentional non-observable states
 		stateInfo = style.Dim.Render(fmt.Sprintf(" [%s]", beadState))
-	// Ignore observable states: "running", "idle", "dead", "done", "stopped", ""
-	// These should be derived from tmux, not bead.
+		// Ignore observable states: "running", "idle", "dead", "done", "stopped", ""
+		// These should be derived from tmux, not bead.
 	}
 
 	// Build agent bead ID using canonical naming: prefix-rig-role-name
@@ -853,7 +893,7 @@ func buildStatusIndicator(agent AgentRuntime) string {
 		indicator += style.Dim.Render(" gate")
 	case "muted", "paused", "degraded":
 		indicator += style.Dim.Render(" " + beadState)
-	// Ignore observable states: running, idle, dead, done, stopped, ""
+		// Ignore observable states: running, idle, dead, done, stopped, ""
 	}
 
 	return indicator
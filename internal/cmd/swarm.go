@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
@@ -237,7 +238,7 @@ func runSwarmCreate(cmd *cobra.Command, args []string) error {
 	beadsPath := r.BeadsPath()
 	checkCmd := exec.Command("bd", "show", swarmEpic, "--json")
 	checkCmd.Dir = beadsPath
-	if err := checkCmd.Run(); err != nil {
+	if err := cmdtrace.Run(checkCmd); err != nil {
 		// Epic doesn't exist, create it as a swarm molecule
 		createArgs := []string{
 			"create",
@@ -250,7 +251,7 @@ func runSwarmCreate(cmd *cobra.Command, args []string) error {
 		createCmd.Dir = beadsPath
 		var stdout bytes.Buffer
 		createCmd.Stdout = &stdout
-		if err := createCmd.Run(); err != nil {
+		if err := cmdtrace.Run(createCmd); err != nil {
 			return fmt.Errorf("creating swarm epic: %w", err)
 		}
 	}
@@ -286,7 +287,7 @@ func runSwarmCreate(cmd *cobra.Command, args []string) error {
 		statusCmd.Dir = beadsPath
 		var statusOut bytes.Buffer
 		statusCmd.Stdout = &statusOut
-		if err := statusCmd.Run(); err != nil {
+		if err := cmdtrace.Run(statusCmd); err != nil {
 			return fmt.Errorf("getting swarm status: %w", err)
 		}
 
@@ -327,7 +328,7 @@ func runSwarmStart(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() to ensure we read from git-synced location
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := cmdtrace.Run(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -343,7 +344,7 @@ func runSwarmStart(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	statusCmd.Stdout = &stdout
 
-	if err := statusCmd.Run(); err != nil {
+	if err := cmdtrace.Run(statusCmd); err != nil {
 		return fmt.Errorf("getting swarm status: %w", err)
 	}
 
@@ -406,7 +407,7 @@ func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() to ensure we read from git-synced location
 		checkCmd := exec.Command("bd", "show", epicID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := cmdtrace.Run(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -425,7 +426,7 @@ func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	statusCmd.Stdout = &stdout
 
-	if err := statusCmd.Run(); err != nil {
+	if err := cmdtrace.Run(statusCmd); err != nil {
 		return fmt.Errorf("getting epic status: %w", err)
 	}
 
@@ -472,7 +473,7 @@ func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 	slingCmd.Stdout = os.Stdout
 	slingCmd.Stderr = os.Stderr
 
-	if err := slingCmd.Run(); err != nil {
+	if err := cmdtrace.Run(slingCmd); err != nil {
 		return fmt.Errorf("slinging task: %w", err)
 	}
 
@@ -509,7 +510,7 @@ func spawnSwarmWorkersFromBeads(r *rig.Rig, townRoot string, swarmID string, wor
 		// Use gt sling to assign task to worker (this updates beads)
 		slingCmd := exec.Command("gt", "sling", task.ID, fmt.Sprintf("%s/%s", r.Name, worker))
 		slingCmd.Dir = townRoot
-		if err := slingCmd.Run(); err != nil {
+		if err := cmdtrace.Run(slingCmd); err != nil {
 			style.PrintWarning("  couldn't sling %s to %s: %v", task.ID, worker, err)
 
 			// Fallback: update polecat state directly
@@ -564,7 +565,7 @@ func runSwarmStatus(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() to ensure we read from git-synced location
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := cmdtrace.Run(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -585,7 +586,7 @@ func runSwarmStatus(cmd *cobra.Command, args []string) error {
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
 
-	return bdCmd.Run()
+	return cmdtrace.Run(bdCmd)
 }
 
 func runSwarmList(cmd *cobra.Command, args []string) error {
@@ -635,7 +636,7 @@ func runSwarmList(cmd *cobra.Command, args []string) error {
 		var stdout bytes.Buffer
 		bdCmd.Stdout = &stdout
 
-		if err := bdCmd.Run(); err != nil {
+		if err := cmdtrace.Run(bdCmd); err != nil {
 			continue
 		}
 
@@ -712,7 +713,7 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() for git-synced beads
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := cmdtrace.Run(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -728,7 +729,7 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	statusCmd.Stdout = &stdout
 
-	if err := statusCmd.Run(); err != nil {
+	if err := cmdtrace.Run(statusCmd); err != nil {
 		return fmt.Errorf("getting swarm status: %w", err)
 	}
 
@@ -778,7 +779,7 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 	}
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = foundRig.BeadsPath()
-	if err := closeCmd.Run(); err != nil {
+	if err := cmdtrace.Run(closeCmd); err != nil {
 		style.PrintWarning("couldn't close swarm epic in beads: %v", err)
 	}
 
@@ -802,7 +803,7 @@ func runSwarmCancel(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() for git-synced beads
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := cmdtrace.Run(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -817,7 +818,7 @@ func runSwarmCancel(cmd *cobra.Command, args []string) error {
 	checkCmd.Dir = foundRig.BeadsPath()
 	var stdout bytes.Buffer
 	checkCmd.Stdout = &stdout
-	if err := checkCmd.Run(); err != nil {
+	if err := cmdtrace.Run(checkCmd); err != nil {
 		return fmt.Errorf("checking swarm status: %w", err)
 	}
 
@@ -837,7 +838,7 @@ func runSwarmCancel(cmd *cobra.Command, args []string) error {
 	}
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = foundRig.BeadsPath()
-	if err := closeCmd.Run(); err != nil {
+	if err := cmdtrace.Run(closeCmd); err != nil {
 		return fmt.Errorf("closing swarm: %w", err)
 	}
 
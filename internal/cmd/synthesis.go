@@ -7,21 +7,26 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // Synthesis command flags
 var (
-	synthesisRig     string
-	synthesisDryRun  bool
-	synthesisForce   bool
-	synthesisReviewID string
+	synthesisRig        string
+	synthesisDryRun     bool
+	synthesisForce      bool
+	synthesisReviewID   string
+	synthesisNoRefinery bool
 )
 
 var synthesisCmd = &cobra.Command{
@@ -85,7 +90,15 @@ var synthesisCloseCmd = &cobra.Command{
 	Short: "Close convoy after synthesis",
 	Long: `Close a convoy after synthesis is complete.
 
-This marks the convoy as complete and triggers any configured notifications.`,
+This marks the convoy as complete and triggers any configured notifications.
+
+If the convoy's rig defines refinery steps in its settings, the
+post-synthesis pipeline runs next (see 'gt refinery run'). A pipeline
+failure is reported but does not reopen the convoy.
+
+Examples:
+  gt synthesis close hq-cv-abc
+  gt synthesis close hq-cv-abc --no-refinery`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSynthesisClose,
 }
@@ -97,6 +110,9 @@ func init() {
 	synthesisStartCmd.Flags().BoolVar(&synthesisForce, "force", false, "Start even if legs incomplete")
 	synthesisStartCmd.Flags().StringVar(&synthesisReviewID, "review-id", "", "Override review ID")
 
+	// Close flags
+	synthesisCloseCmd.Flags().BoolVar(&synthesisNoRefinery, "no-refinery", false, "Skip the post-synthesis refinery pipeline")
+
 	// Add subcommands
 	synthesisCmd.AddCommand(synthesisStartCmd)
 	synthesisCmd.AddCommand(synthesisStatusCmd)
@@ -113,6 +129,12 @@ type LegOutput struct {
 	FilePath string `json:"file_path,omitempty"`
 	Content  string `json:"content,omitempty"`
 	HasFile  bool   `json:"has_file"`
+	Failed   bool   `json:"failed,omitempty"` // Output contract violated (see gt done)
+}
+
+// Complete reports whether the leg closed with usable output.
+func (l LegOutput) Complete() bool {
+	return l.Status == "closed" && !l.Failed
 }
 
 // ConvoyMeta holds metadata about a convoy including its formula.
@@ -120,9 +142,13 @@ type ConvoyMeta struct {
 	ID          string   `json:"id"`
 	Title       string   `json:"title"`
 	Status      string   `json:"status"`
-	Formula     string   `json:"formula,omitempty"`     // Formula name
+	Formula     string   `json:"formula,omitempty"`      // Formula name
 	FormulaPath string   `json:"formula_path,omitempty"` // Path to formula file
 	ReviewID    string   `json:"review_id,omitempty"`    // Review ID for output paths
+	Rig         string   `json:"rig,omitempty"`          // Rig the legs were dispatched to
+	PR          int      `json:"pr,omitempty"`           // PR the formula ran on
+	HeadSHA     string   `json:"head_sha,omitempty"`     // PR head commit the run reviewed
+	ClonedFrom  string   `json:"cloned_from,omitempty"`  // Convoy this run was cloned from
 	LegIssues   []string `json:"leg_issues,omitempty"`   // Tracked leg issue IDs
 }
 
@@ -141,7 +167,7 @@ func runSynthesisStart(cmd *cobra.Command, args []string) error {
 	// Load formula if specified
 	var f *formula.Formula
 	if meta.FormulaPath != "" {
-		f, err = formula.ParseFile(meta.FormulaPath)
+		f, err = formula.ParseFile(meta.FormulaPath, formulaIncludeDirs()...)
 		if err != nil {
 			return fmt.Errorf("loading formula: %w", err)
 		}
@@ -149,7 +175,7 @@ func runSynthesisStart(cmd *cobra.Command, args []string) error {
 		// Try to find formula by name
 		formulaPath, findErr := findFormula(meta.Formula)
 		if findErr == nil {
-			f, err = formula.ParseFile(formulaPath)
+			f, err = formula.ParseFile(formulaPath, formulaIncludeDirs()...)
 			if err != nil {
 				return fmt.Errorf("loading formula: %w", err)
 			}
@@ -157,31 +183,40 @@ func runSynthesisStart(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check leg completion status
-	legOutputs, allComplete, err := collectLegOutputs(meta, f)
+	legOutputs, _, err := collectLegOutputs(meta, f)
 	if err != nil {
 		return fmt.Errorf("collecting leg outputs: %w", err)
 	}
 
 	// Report status
-	completedCount := 0
-	for _, leg := range legOutputs {
-		if leg.Status == "closed" {
-			completedCount++
-		}
-	}
+	completedCount, required, running := synthesisReadiness(f, legOutputs)
 	fmt.Printf("  Legs: %d/%d complete\n", completedCount, len(legOutputs))
 
-	if !allComplete && !synthesisForce {
-		fmt.Printf("\n%s Not all legs complete. Use --force to proceed anyway.\n",
-			style.Warning.Render("⚠"))
+	if completedCount < required && !synthesisForce {
+		if required == len(legOutputs) {
+			fmt.Printf("\n%s Not all legs complete. Use --force to proceed anyway.\n",
+				style.Warning.Render("⚠"))
+		} else {
+			fmt.Printf("\n%s Synthesis requires %d complete legs (synthesis.require = %s). Use --force to proceed anyway.\n",
+				style.Warning.Render("⚠"), required, synthesisRequire(f))
+		}
 		fmt.Printf("\nIncomplete legs:\n")
 		for _, leg := range legOutputs {
-			if leg.Status != "closed" {
+			if leg.Failed {
+				fmt.Printf("  ✗ %s: %s [failed: output contract violated]\n", leg.LegID, leg.Title)
+			} else if !leg.Complete() {
 				fmt.Printf("  ○ %s: %s [%s]\n", leg.LegID, leg.Title, leg.Status)
 			}
 		}
 		return nil
 	}
+	if completedCount < len(legOutputs) {
+		fmt.Printf("  %s Proceeding with partial findings; %d leg(s) missing\n",
+			style.Warning.Render("⚠"), len(legOutputs)-completedCount)
+		if running > 0 {
+			fmt.Printf("  %s %d leg(s) still running are left out\n", style.Dim.Render("○"), running)
+		}
+	}
 
 	// Determine review ID
 	reviewID := synthesisReviewID
@@ -249,20 +284,14 @@ func runSynthesisStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load formula if available
-	var f *formula.Formula
-	if meta.FormulaPath != "" {
-		f, _ = formula.ParseFile(meta.FormulaPath)
-	} else if meta.Formula != "" {
-		if path, err := findFormula(meta.Formula); err == nil {
-			f, _ = formula.ParseFile(path)
-		}
-	}
+	f := loadConvoyFormula(meta)
 
 	// Collect leg outputs
 	legOutputs, allComplete, err := collectLegOutputs(meta, f)
 	if err != nil {
 		return fmt.Errorf("collecting leg outputs: %w", err)
 	}
+	completedCount, required, running := synthesisReadiness(f, legOutputs)
 
 	// Display status
 	fmt.Printf("🚚 %s %s\n\n", style.Bold.Render(convoyID+":"), meta.Title)
@@ -275,30 +304,36 @@ func runSynthesisStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\n  %s\n", style.Bold.Render("Legs:"))
 	for _, leg := range legOutputs {
 		status := "○"
-		if leg.Status == "closed" {
+		legStatus := leg.Status
+		if leg.Failed {
+			status = "✗"
+			legStatus = "failed"
+		} else if leg.Status == "closed" {
 			status = "✓"
 		}
 		fileStatus := ""
 		if leg.HasFile {
 			fileStatus = style.Dim.Render(" (output: ✓)")
 		}
-		fmt.Printf("    %s %s: %s [%s]%s\n", status, leg.LegID, leg.Title, leg.Status, fileStatus)
+		fmt.Printf("    %s %s: %s [%s]%s\n", status, leg.LegID, leg.Title, legStatus, fileStatus)
 	}
 
 	// Synthesis readiness
 	fmt.Printf("\n  %s\n", style.Bold.Render("Synthesis:"))
-	if allComplete {
+	switch {
+	case allComplete:
 		fmt.Printf("    %s Ready - all legs complete\n", style.Success.Render("✓"))
 		fmt.Printf("    Run: gt synthesis start %s\n", convoyID)
-	} else {
-		completedCount := 0
-		for _, leg := range legOutputs {
-			if leg.Status == "closed" {
-				completedCount++
-			}
+	case completedCount >= required:
+		fmt.Printf("    %s Ready - %d/%d legs complete (synthesis.require = %s)\n",
+			style.Success.Render("✓"), completedCount, len(legOutputs), synthesisRequire(f))
+		if running > 0 {
+			fmt.Printf("    %d leg(s) still running would be left out\n", running)
 		}
-		fmt.Printf("    %s Waiting - %d/%d legs complete\n",
-			style.Warning.Render("○"), completedCount, len(legOutputs))
+		fmt.Printf("    Run: gt synthesis start %s\n", convoyID)
+	default:
+		fmt.Printf("    %s Waiting - %d/%d legs complete, %d required\n",
+			style.Warning.Render("○"), completedCount, len(legOutputs), required)
 	}
 
 	if f != nil && f.Synthesis != nil {
@@ -330,18 +365,60 @@ func runSynthesisClose(cmd *cobra.Command, args []string) error {
 	closeCmd.Dir = townBeads
 	closeCmd.Stderr = os.Stderr
 
-	if err := closeCmd.Run(); err != nil {
+	if err := cmdtrace.Run(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
 	fmt.Printf("%s Convoy closed: %s\n", style.Bold.Render("✓"), convoyID)
 
+	if meta, err := getConvoyMeta(convoyID); err == nil {
+		townRoot, _ := workspace.FindFromCwd()
+		recordAudit(townRoot, meta.Rig, witness.AuditEntry{
+			Action:  witness.ActionSynthesisClose,
+			Subject: convoyID,
+			Details: map[string]string{"formula": meta.Formula, "review_id": meta.ReviewID},
+		})
+	}
+
 	// TODO: Trigger notification if configured
 	// Parse description for "Notify: <address>" and send mail
 
+	if !synthesisNoRefinery {
+		runPostSynthesisRefinery(convoyID)
+	}
+
 	return nil
 }
 
+// runPostSynthesisRefinery runs the convoy rig's refinery pipeline, if any.
+// Failures are warnings: the convoy is already closed and the pipeline can
+// be retried with gt refinery run.
+func runPostSynthesisRefinery(convoyID string) {
+	meta, err := getConvoyMeta(convoyID)
+	if err != nil || meta.ReviewID == "" || meta.Rig == "" {
+		return
+	}
+
+	townRoot, r, err := getRig(meta.Rig)
+	if err != nil {
+		return
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.Refinery == nil || len(settings.Refinery.Steps) == 0 {
+		return
+	}
+	if settings.Refinery.Manual {
+		fmt.Printf("  Refinery pipeline is manual: gt refinery run %s --rig %s\n", meta.ReviewID, r.Name)
+		return
+	}
+
+	reviewDir := findReviewDir(townRoot, r, meta.ReviewID)
+	if err := runRefineryPipeline(r, settings.Refinery.Steps, meta.ReviewID, reviewDir); err != nil {
+		fmt.Printf("%s refinery pipeline: %v\n", style.Dim.Render("Warning:"), err)
+		fmt.Printf("  Retry with: gt refinery run %s --rig %s\n", meta.ReviewID, r.Name)
+	}
+}
+
 // getConvoyMeta retrieves convoy metadata from beads.
 func getConvoyMeta(convoyID string) (*ConvoyMeta, error) {
 	townBeads, err := getTownBeadsDir()
@@ -354,7 +431,7 @@ func getConvoyMeta(convoyID string) (*ConvoyMeta, error) {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -395,6 +472,14 @@ func getConvoyMeta(convoyID string) (*ConvoyMeta, error) {
 				meta.FormulaPath = value
 			case "review_id", "review-id":
 				meta.ReviewID = value
+			case "rig":
+				meta.Rig = value
+			case "pr":
+				meta.PR, _ = strconv.Atoi(strings.TrimPrefix(value, "#"))
+			case "head_sha":
+				meta.HeadSHA = value
+			case "cloned_from":
+				meta.ClonedFrom = value
 			}
 		}
 	}
@@ -424,8 +509,9 @@ func collectLegOutputs(meta *ConvoyMeta, f *formula.Formula) ([]LegOutput, bool,
 			if details != nil {
 				output.Title = details.Title
 				output.Status = details.Status
+				output.Failed = hasLabel(details.Labels, LegFailedLabel)
 			}
-			if output.Status != "closed" {
+			if !output.Complete() {
 				allComplete = false
 			}
 			outputs = append(outputs, output)
@@ -434,7 +520,8 @@ func collectLegOutputs(meta *ConvoyMeta, f *formula.Formula) ([]LegOutput, bool,
 
 	// If we have a formula, also try to find output files
 	if f != nil && f.Output != nil && meta.ReviewID != "" {
-		for _, leg := range f.Legs {
+		// Consensus legs ran as one leg per agent
+		for _, leg := range f.ExpandConsensus().Legs {
 			// Expand output path template
 			outputPath := expandOutputPath(f.Output.Directory, f.Output.LegPattern,
 				meta.ReviewID, leg.ID)
@@ -444,7 +531,8 @@ func collectLegOutputs(meta *ConvoyMeta, f *formula.Formula) ([]LegOutput, bool,
 				// Find or create leg output entry
 				found := false
 				for i := range outputs {
-					if outputs[i].LegID == leg.ID {
+					// Leg beads created by gt formula run carry the leg title
+					if outputs[i].LegID == leg.ID || outputs[i].Title == leg.Title {
 						outputs[i].FilePath = outputPath
 						outputs[i].Content = string(content)
 						outputs[i].HasFile = true
@@ -470,13 +558,16 @@ func collectLegOutputs(meta *ConvoyMeta, f *formula.Formula) ([]LegOutput, bool,
 }
 
 // expandOutputPath expands template variables in output paths.
-// Supports: {{review_id}}, {{leg.id}}
+// Supports: {{review_id}}, {{leg.id}} and the Go template forms
+// {{.review_id}}, {{.leg.id}} used by formula files.
 func expandOutputPath(directory, pattern, reviewID, legID string) string {
 	// Expand directory
 	dir := strings.ReplaceAll(directory, "{{review_id}}", reviewID)
+	dir = strings.ReplaceAll(dir, "{{.review_id}}", reviewID)
 
 	// Expand pattern
 	file := strings.ReplaceAll(pattern, "{{leg.id}}", legID)
+	file = strings.ReplaceAll(file, "{{.leg.id}}", legID)
 
 	return filepath.Join(dir, file)
 }
@@ -504,11 +595,27 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 		desc.WriteString("\n\n")
 	}
 
+	// Note legs that didn't complete so synthesis accounts for the gaps
+	if missing := missingLegs(legOutputs); len(missing) > 0 {
+		desc.WriteString("## Missing Legs\n\n")
+		desc.WriteString("Synthesis is running with partial findings. These legs did not complete; call out the gaps in coverage:\n\n")
+		for _, leg := range missing {
+			reason := leg.Status
+			if leg.Failed {
+				reason = "failed"
+			}
+			desc.WriteString(fmt.Sprintf("- %s: %s (%s)\n", leg.LegID, leg.Title, reason))
+		}
+		desc.WriteString("\n")
+	}
+
 	// Add collected leg outputs
 	desc.WriteString("## Leg Outputs\n\n")
 	for _, leg := range legOutputs {
 		desc.WriteString(fmt.Sprintf("### %s: %s\n\n", leg.LegID, leg.Title))
-		if leg.Content != "" {
+		if leg.Failed {
+			desc.WriteString("(leg failed: output contract violated; do not rely on its output)\n\n")
+		} else if leg.Content != "" {
 			desc.WriteString(leg.Content)
 			desc.WriteString("\n\n")
 		} else if leg.FilePath != "" {
@@ -545,7 +652,7 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 	createCmd.Stdout = &stdout
 	createCmd.Stderr = os.Stderr
 
-	if err := createCmd.Run(); err != nil {
+	if err := cmdtrace.Run(createCmd); err != nil {
 		return "", fmt.Errorf("creating synthesis bead: %w", err)
 	}
 
@@ -566,7 +673,13 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 	depArgs := []string{"dep", "add", convoyID, result.ID, "--type=tracks"}
 	depCmd := exec.Command("bd", depArgs...)
 	depCmd.Dir = townBeads
-	_ = depCmd.Run() // Non-fatal if this fails
+	_ = cmdtrace.Run(depCmd) // Non-fatal if this fails
+
+	recordAudit(filepath.Dir(townBeads), meta.Rig, witness.AuditEntry{
+		Action:  witness.ActionBeadCreate,
+		Subject: result.ID,
+		Details: map[string]string{"type": "synthesis", "convoy": convoyID},
+	})
 
 	return result.ID, nil
 }
@@ -578,7 +691,7 @@ func slingSynthesis(beadID, targetRig string) error {
 	slingCmd.Stdout = os.Stdout
 	slingCmd.Stderr = os.Stderr
 
-	return slingCmd.Run()
+	return cmdtrace.Run(slingCmd)
 }
 
 // findFormula searches for a formula file by name.
@@ -617,15 +730,22 @@ func findFormula(name string) (string, error) {
 }
 
 // CheckSynthesisReady checks if a convoy is ready for synthesis.
-// Returns true if all tracked legs are complete.
+// Returns true once enough tracked legs are complete to satisfy the
+// formula's synthesis.require policy (all legs by default). Legs still
+// running then don't hold synthesis back, so a hung leg can't stall a
+// partial synthesis.
 func CheckSynthesisReady(convoyID string) (bool, error) {
 	meta, err := getConvoyMeta(convoyID)
 	if err != nil {
 		return false, err
 	}
 
-	_, allComplete, err := collectLegOutputs(meta, nil)
-	return allComplete, err
+	f := loadConvoyFormula(meta)
+	legOutputs, _, err := collectLegOutputs(meta, f)
+	if err != nil {
+		return false, err
+	}
+	return synthesisReady(f, legOutputs), nil
 }
 
 // TriggerSynthesisIfReady checks convoy status and starts synthesis if ready.
@@ -641,23 +761,14 @@ func TriggerSynthesisIfReady(convoyID, targetRig string) error {
 	}
 
 	// Synthesis is ready - start it
-	fmt.Printf("%s All legs complete, starting synthesis...\n", style.Bold.Render("🔬"))
+	fmt.Printf("%s Enough legs complete, starting synthesis...\n", style.Bold.Render("🔬"))
 
 	meta, err := getConvoyMeta(convoyID)
 	if err != nil {
 		return err
 	}
 
-	// Load formula if available
-	var f *formula.Formula
-	if meta.FormulaPath != "" {
-		f, _ = formula.ParseFile(meta.FormulaPath)
-	} else if meta.Formula != "" {
-		if path, err := findFormula(meta.Formula); err == nil {
-			f, _ = formula.ParseFile(path)
-		}
-	}
-
+	f := loadConvoyFormula(meta)
 	legOutputs, _, _ := collectLegOutputs(meta, f)
 	reviewID := meta.ReviewID
 	if reviewID == "" {
@@ -675,3 +786,58 @@ func TriggerSynthesisIfReady(convoyID, targetRig string) error {
 
 	return nil
 }
+
+// loadConvoyFormula loads the formula a convoy was run from, or nil if it
+// can't be found or parsed.
+func loadConvoyFormula(meta *ConvoyMeta) *formula.Formula {
+	var f *formula.Formula
+	if meta.FormulaPath != "" {
+		f, _ = formula.ParseFile(meta.FormulaPath, formulaIncludeDirs()...)
+	} else if meta.Formula != "" {
+		if path, err := findFormula(meta.Formula); err == nil {
+			f, _ = formula.ParseFile(path, formulaIncludeDirs()...)
+		}
+	}
+	return f
+}
+
+// synthesisRequire returns the formula's synthesis.require policy.
+func synthesisRequire(f *formula.Formula) formula.SynthesisRequire {
+	if f == nil || f.Synthesis == nil || f.Synthesis.Require == "" {
+		return formula.RequireAll
+	}
+	return f.Synthesis.Require
+}
+
+// synthesisReadiness counts complete legs, how many the formula's
+// synthesis.require policy needs, and the legs still running. Synthesis may
+// run once enough legs are complete; legs still running are reported as
+// missing, since a hung polecat leg may never finish.
+func synthesisReadiness(f *formula.Formula, legs []LegOutput) (completed, required, running int) {
+	for _, leg := range legs {
+		switch {
+		case leg.Complete():
+			completed++
+		case leg.Status != "closed":
+			running++
+		}
+	}
+	return completed, synthesisRequire(f).Threshold(len(legs)), running
+}
+
+// synthesisReady reports whether enough legs are complete for synthesis.
+func synthesisReady(f *formula.Formula, legs []LegOutput) bool {
+	completed, required, _ := synthesisReadiness(f, legs)
+	return completed >= required
+}
+
+// missingLegs returns the legs that did not complete.
+func missingLegs(legs []LegOutput) []LegOutput {
+	var missing []LegOutput
+	for _, leg := range legs {
+		if !leg.Complete() {
+			missing = append(missing, leg)
+		}
+	}
+	return missing
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"path/filepath"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
 )
 
 func TestExpandOutputPath(t *testing.T) {
@@ -38,6 +40,14 @@ func TestExpandOutputPath(t *testing.T) {
 			legID:     "performance",
 			want:      "reviews/pr-123/findings/leg-performance-analysis.md",
 		},
+		{
+			name:      "go template syntax",
+			directory: ".reviews/{{.review_id}}",
+			pattern:   "{{.leg.id}}-findings.md",
+			reviewID:  "abc123",
+			legID:     "security",
+			want:      ".reviews/abc123/security-findings.md",
+		},
 	}
 
 	for _, tt := range tests {
@@ -93,3 +103,57 @@ func TestConvoyMeta(t *testing.T) {
 		t.Errorf("len(LegIssues) = %d, want 3", len(meta.LegIssues))
 	}
 }
+
+func TestSynthesisReadiness(t *testing.T) {
+	legs := []LegOutput{
+		{LegID: "a", Status: "closed"},
+		{LegID: "b", Status: "closed"},
+		{LegID: "c", Status: "closed", Failed: true},
+		{LegID: "d", Status: "in_progress"},
+	}
+
+	tests := []struct {
+		require      formula.SynthesisRequire
+		wantRequired int
+	}{
+		{"", 4},
+		{formula.RequireMajority, 3},
+		{"2", 2},
+	}
+	for _, tt := range tests {
+		f := &formula.Formula{Synthesis: &formula.Synthesis{Require: tt.require}}
+		completed, required, running := synthesisReadiness(f, legs)
+		if completed != 2 || required != tt.wantRequired || running != 1 {
+			t.Errorf("require %q: readiness = %d/%d (%d running), want 2/%d (1 running)", tt.require, completed, required, running, tt.wantRequired)
+		}
+	}
+
+	if _, required, _ := synthesisReadiness(nil, legs); required != 4 {
+		t.Errorf("no formula: required = %d, want all 4", required)
+	}
+
+	// A leg that never finishes doesn't hold back a partial synthesis
+	if !synthesisReady(&formula.Formula{Synthesis: &formula.Synthesis{Require: "2"}}, legs) {
+		t.Error("require 2 with 2 complete and 1 running: not ready")
+	}
+	if synthesisReady(nil, legs) {
+		t.Error("require all with 1 running: ready")
+	}
+
+	missing := missingLegs(legs)
+	if len(missing) != 2 || missing[0].LegID != "c" || missing[1].LegID != "d" {
+		t.Errorf("missingLegs = %+v, want c and d", missing)
+	}
+}
+
+func TestParseSynthesisRequire(t *testing.T) {
+	legs := "formula = \"review\"\n[[legs]]\nid = \"a\"\n[[legs]]\nid = \"b\"\n"
+	f, err := parseFormulaContent(t, legs+"[synthesis]\ntitle = \"Report\"\nrequire = \"majority\"\n")
+	if err != nil || f.Synthesis == nil || f.Synthesis.Require != formula.RequireMajority {
+		t.Fatalf("parseFormulaFile() = %+v, %v; want require majority", f, err)
+	}
+	f, err = parseFormulaContent(t, legs+"[synthesis]\ntitle = \"Report\"\nrequire = 2\n")
+	if err != nil || f.Synthesis == nil || f.Synthesis.Require != "2" {
+		t.Fatalf("parseFormulaFile() = %+v, %v; want require 2", f, err)
+	}
+}
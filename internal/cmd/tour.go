@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Tour flags
+var (
+	tourYes  bool
+	tourKeep bool
+)
+
+// tourRig is the sample rig the tour creates.
+const tourRig = "tour_demo"
+
+// tourRigMarker is the first line of the sample rig's README; the tour only
+// removes a rig that starts with it.
+const tourRigMarker = "# tour_demo: a sample rig created by gt tour"
+
+var tourCmd = &cobra.Command{
+	Use:     "tour",
+	GroupID: GroupWorkspace,
+	Short:   "Walk through Gas Town on a sample rig",
+	Long: `A guided first run: create a sample rig, run a simulated convoy on it,
+and inspect the results, one step at a time.
+
+Every step prints and runs real gt commands, so what you see is what you
+would type. The convoy uses the built-in simulate agent, which answers with
+canned findings: nothing is sent to a model and the run takes seconds.
+
+At each step press Enter to run it, s to skip it, or q to stop. The sample
+rig (tour_demo) is removed at the end unless you pass --keep.
+
+Examples:
+  gt tour
+  gt tour --yes --keep   # Run every step without pausing, keep the rig`,
+	Args: cobra.NoArgs,
+	RunE: runTour,
+}
+
+func init() {
+	tourCmd.Flags().BoolVarP(&tourYes, "yes", "y", false, "Run every step without pausing")
+	tourCmd.Flags().BoolVar(&tourKeep, "keep", false, "Keep the sample rig after the tour")
+	rootCmd.AddCommand(tourCmd)
+}
+
+// tour is the state of a tour in progress.
+type tour struct {
+	townRoot string
+	gt       string // gt executable the steps run
+	out      io.Writer
+	in       *bufio.Reader // nil when not pausing between steps
+	reviewID string        // Set once the convoy has run
+}
+
+// tourStep is one stop of the tour. Setup, if set, runs before the
+// commands; commands are gt arguments, run in order from the sample rig.
+type tourStep struct {
+	Title    string
+	Text     string
+	Setup    func(t *tour) error
+	Commands func(t *tour) [][]string
+}
+
+// tourSteps are the stops of the tour, in order.
+var tourSteps = []tourStep{
+	{
+		Title: "Your town",
+		Text: `A town is the directory gt works in: settings, beads (issues), and one
+directory per rig. gt env shows what gt resolved from where you are.`,
+		Commands: func(t *tour) [][]string {
+			return [][]string{{"env"}}
+		},
+	},
+	{
+		Title: "A sample rig",
+		Text: `A rig is a project the town works on, usually a git repository added
+with 'gt rig add <name> <git-url>'. The tour writes a small sample project
+and adopts it as the rig ` + tourRig + `.`,
+		Setup: writeTourRig,
+		Commands: func(t *tour) [][]string {
+			if t.rigRegistered() {
+				return [][]string{{"rig", "list"}}
+			}
+			return [][]string{{"rig", "add", tourRig, "--adopt", "--force"}, {"rig", "list"}}
+		},
+	},
+	{
+		Title: "A simulated convoy",
+		Text: `A convoy formula fans work out to parallel legs and synthesizes their
+results. code-review runs ten review legs (correctness, security, ...).
+--local-agent runs the legs here instead of on polecats, and the simulate
+agent answers them without calling a model.`,
+		Commands: func(t *tour) [][]string {
+			return [][]string{
+				{"formula", "show", "code-review"},
+				{"formula", "run", "code-review", "--rig", tourRig, "--local-agent", "--agent", simulateAgent, "--parallel", "10"},
+			}
+		},
+	},
+	{
+		Title: "Inspecting the results",
+		Text: `Every run is recorded with the environment it ran in, and its outputs
+form a review: one findings file per leg plus the synthesis.`,
+		Setup: findTourReview,
+		Commands: func(t *tour) [][]string {
+			return [][]string{
+				{"formula", "history", "code-review", "--rig", tourRig},
+				{"review", "render", t.reviewID, "--rig", tourRig},
+			}
+		},
+	},
+}
+
+// tourCleanup removes the sample rig; it is skipped with --keep.
+var tourCleanup = tourStep{
+	Title: "Cleaning up",
+	Text:  "The sample rig is unregistered and its directory removed.",
+	Commands: func(t *tour) [][]string {
+		if !t.rigRegistered() {
+			return nil
+		}
+		return [][]string{{"rig", "remove", tourRig}}
+	},
+}
+
+func runTour(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("gt tour runs in a town; create one first:\n\n  gt install ~/gt\n  cd ~/gt && gt tour")
+	}
+	gt, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gt: %w", err)
+	}
+	t := &tour{townRoot: townRoot, gt: gt, out: os.Stdout}
+	if !tourYes && term.IsTerminal(int(os.Stdin.Fd())) {
+		t.in = bufio.NewReader(os.Stdin)
+	}
+
+	fmt.Fprintf(t.out, "%s Welcome to Gas Town\n", style.Bold.Render("🏭"))
+	fmt.Fprintf(t.out, "  %s\n", style.Dim.Render("Town: "+townRoot))
+
+	steps := tourSteps
+	if !tourKeep {
+		steps = append(steps[:len(steps):len(steps)], tourCleanup)
+	}
+	for i, step := range steps {
+		fmt.Fprintf(t.out, "\n%s %s\n\n", style.Bold.Render(fmt.Sprintf("── %d/%d", i+1, len(steps))), style.Bold.Render(step.Title))
+		fmt.Fprintln(t.out, step.Text)
+		switch t.pause() {
+		case "s":
+			fmt.Fprintf(t.out, "  %s\n", style.Dim.Render("Skipped"))
+			continue
+		case "q":
+			fmt.Fprintf(t.out, "\nTour stopped. Run 'gt tour' to start again.\n")
+			return nil
+		}
+		if err := t.run(step); err != nil {
+			fmt.Fprintf(t.out, "\n%s %v\n", style.Warning.Render("⚠"), err)
+		}
+		if i == len(steps)-1 && step.Title == tourCleanup.Title {
+			t.removeRigDir()
+		}
+	}
+
+	fmt.Fprintf(t.out, "\n%s Tour complete\n", style.Success.Render("✓"))
+	fmt.Fprintln(t.out, "  Next: add a real project with 'gt rig add <name> <git-url>',")
+	fmt.Fprintln(t.out, "  then 'gt formula list' to see what you can run on it.")
+	return nil
+}
+
+// pause waits for the user to continue, returning "s" to skip the step,
+// "q" to stop, or "" to run it. It returns "" at once when not pausing.
+func (t *tour) pause() string {
+	if t.in == nil {
+		return ""
+	}
+	fmt.Fprintf(t.out, "\n%s ", style.Dim.Render("[Enter] run · s skip · q quit"))
+	answer, err := t.in.ReadString('\n')
+	if err != nil && answer == "" {
+		return "q"
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "s" || answer == "q" {
+		return answer
+	}
+	return ""
+}
+
+// run runs a step's setup and commands, stopping at the first failure.
+func (t *tour) run(step tourStep) error {
+	if step.Setup != nil {
+		if err := step.Setup(t); err != nil {
+			return err
+		}
+	}
+	if step.Commands == nil {
+		return nil
+	}
+	for _, args := range step.Commands(t) {
+		fmt.Fprintf(t.out, "\n%s\n", style.Bold.Render("$ gt "+strings.Join(args, " ")))
+		c := exec.Command(t.gt, args...) //nolint:gosec // G204: runs this gt with the tour's own arguments
+		c.Dir = t.rigDir()
+		if _, err := os.Stat(c.Dir); err != nil {
+			c.Dir = t.townRoot
+		}
+		c.Stdout, c.Stderr = t.out, t.out
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("gt %s: %w", args[0], err)
+		}
+	}
+	return nil
+}
+
+func (t *tour) rigDir() string {
+	return filepath.Join(t.townRoot, tourRig)
+}
+
+// rigRegistered reports whether the sample rig is in the town's rigs.
+func (t *tour) rigRegistered() bool {
+	data, err := os.ReadFile(filepath.Join(t.townRoot, "mayor", "rigs.json"))
+	return err == nil && strings.Contains(string(data), `"`+tourRig+`"`)
+}
+
+// isTourRig reports whether dir is a sample rig written by the tour.
+func isTourRig(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "README.md")) //nolint:gosec // G304: the tour's own rig
+	return err == nil && strings.HasPrefix(string(data), tourRigMarker)
+}
+
+// removeRigDir deletes the sample rig's directory, if the tour wrote it.
+func (t *tour) removeRigDir() {
+	if t.rigRegistered() || !isTourRig(t.rigDir()) {
+		return
+	}
+	if err := os.RemoveAll(t.rigDir()); err != nil {
+		fmt.Fprintf(t.out, "%s removing %s: %v\n", style.Warning.Render("⚠"), t.rigDir(), err)
+	}
+}
+
+// tourRigFiles are the sample rig's files. The code has the kind of flaws
+// the simulated review reports.
+var tourRigFiles = map[string]string{
+	"README.md": tourRigMarker + `
+
+A tiny calculator used to demonstrate Gas Town. Safe to delete.
+`,
+	"calc.go": `package calc
+
+// Divide returns a / b.
+func Divide(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errDivideByZero
+	}
+	return a / b, nil
+}
+
+func Average(xs []int) int {
+	tmp2, _ := Divide(sum(xs), len(xs))
+	return tmp2
+}
+
+func sum(xs []int) int {
+	total := 0
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+`,
+}
+
+// writeTourRig writes the sample rig, unless a previous tour left one.
+func writeTourRig(t *tour) error {
+	dir := t.rigDir()
+	if _, err := os.Stat(dir); err == nil {
+		if !isTourRig(dir) {
+			return fmt.Errorf("%s exists and wasn't created by gt tour; remove or rename it to take the tour", dir)
+		}
+		fmt.Fprintf(t.out, "\n  %s\n", style.Dim.Render("Reusing the sample rig from an earlier tour"))
+		return nil
+	}
+	for name, content := range tourRigFiles {
+		if err := writeLocalOutput(filepath.Join(dir, name), content); err != nil {
+			return fmt.Errorf("writing sample rig: %w", err)
+		}
+	}
+	config := fmt.Sprintf(`{"type": "rig", "version": 1, "name": %q, "git_url": "", "created_at": %q}`+"\n",
+		tourRig, time.Now().UTC().Format(time.RFC3339))
+	if err := writeLocalOutput(filepath.Join(dir, "config.json"), config); err != nil {
+		return fmt.Errorf("writing sample rig: %w", err)
+	}
+	fmt.Fprintf(t.out, "\n  %s Wrote %s\n", style.Success.Render("✓"), dir)
+	return nil
+}
+
+// findTourReview sets the tour's review to the newest review the convoy
+// step wrote.
+func findTourReview(t *tour) error {
+	dir := filepath.Join(formulaOutputRoot(t.townRoot, t.rigDir()), ".reviews")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.New("no review found; run the convoy step first")
+	}
+	var newest time.Time
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		if info.ModTime().After(newest) {
+			newest, t.reviewID = info.ModTime(), e.Name()
+		}
+	}
+	if t.reviewID == "" {
+		return errors.New("no review found; run the convoy step first")
+	}
+	return nil
+}
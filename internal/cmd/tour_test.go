@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSimulatedReply(t *testing.T) {
+	reply := simulatedReply("# Correctness review\n\nReview calc.go.")
+	if reply != simulatedReply("# Correctness review\n\nReview calc.go.") {
+		t.Error("same prompt gave different replies")
+	}
+	if !strings.HasPrefix(reply, "# Correctness review\n") {
+		t.Errorf("reply doesn't start with the prompt's title: %q", reply)
+	}
+	if n := strings.Count(reply, "\n- ["); n != 2 {
+		t.Errorf("reply has %d findings, want 2: %q", n, reply)
+	}
+}
+
+func TestIsTourRig(t *testing.T) {
+	dir := t.TempDir()
+	if isTourRig(dir) {
+		t.Error("empty directory reported as the tour rig")
+	}
+	if err := writeLocalOutput(dir+"/README.md", tourRigFiles["README.md"]); err != nil {
+		t.Fatal(err)
+	}
+	if !isTourRig(dir) {
+		t.Error("directory with the tour README not reported as the tour rig")
+	}
+}
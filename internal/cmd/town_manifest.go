@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// townManifestVersion is the schema version of exported town manifests.
+const townManifestVersion = 1
+
+// townManifest describes a town well enough to recreate an equivalent one
+// elsewhere. It holds no secrets or machine-specific paths so it can be
+// committed alongside the projects it describes: custom agents are exported
+// without their env, which commonly carries API keys.
+type townManifest struct {
+	Version    int       `json:"version" toml:"version"`
+	GTVersion  string    `json:"gt_version" toml:"gt_version"`
+	ExportedAt time.Time `json:"exported_at" toml:"exported_at"`
+	Name       string    `json:"name,omitempty" toml:"name,omitempty"`
+	Owner      string    `json:"owner,omitempty" toml:"owner,omitempty"`
+
+	Rigs     []manifestRig     `json:"rigs" toml:"rigs"`
+	Formulas []manifestFormula `json:"formulas,omitempty" toml:"formulas,omitempty"`
+	Agents   manifestAgents    `json:"agents" toml:"agents"`
+	Profiles []manifestProfile `json:"profiles,omitempty" toml:"profiles,omitempty"`
+
+	// strippedEnv lists the custom agents whose env was left out.
+	strippedEnv []string
+}
+
+// manifestRig is one registered rig and the ref its canonical clone was at.
+// Applying the manifest checks the canonical clone out at Ref.
+type manifestRig struct {
+	Name          string `json:"name" toml:"name"`
+	GitURL        string `json:"git_url" toml:"git_url"`
+	DefaultBranch string `json:"default_branch,omitempty" toml:"default_branch,omitempty"`
+	Ref           string `json:"ref,omitempty" toml:"ref,omitempty"`
+	Prefix        string `json:"prefix,omitempty" toml:"prefix,omitempty"`
+}
+
+// manifestFormula is a formula override file and its content hash.
+type manifestFormula struct {
+	Name   string `json:"name" toml:"name"`
+	Scope  string `json:"scope" toml:"scope"` // "town" or a rig name
+	Path   string `json:"path" toml:"path"`   // Relative to the town root
+	SHA256 string `json:"sha256" toml:"sha256"`
+}
+
+// manifestAgents is the town's agent selection.
+type manifestAgents struct {
+	Default string                           `json:"default,omitempty" toml:"default,omitempty"`
+	Roles   map[string]string                `json:"roles,omitempty" toml:"roles,omitempty"`
+	Custom  map[string]*config.RuntimeConfig `json:"custom,omitempty" toml:"custom,omitempty"`
+}
+
+// manifestProfile is an account profile. Config directories are machine
+// specific and are recreated under the default accounts directory.
+type manifestProfile struct {
+	Handle      string `json:"handle" toml:"handle"`
+	Email       string `json:"email" toml:"email"`
+	Description string `json:"description,omitempty" toml:"description,omitempty"`
+	Default     bool   `json:"default,omitempty" toml:"default,omitempty"`
+}
+
+// buildTownManifest collects the manifest for the town at townRoot.
+func buildTownManifest(townRoot string) (*townManifest, error) {
+	m := &townManifest{
+		Version:    townManifestVersion,
+		GTVersion:  Version,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	if townCfg, err := config.LoadTownConfig(constants.MayorTownPath(townRoot)); err == nil {
+		m.Name = townCfg.Name
+		m.Owner = townCfg.Owner
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := rigsConfig.Rigs[name]
+		mr := manifestRig{Name: name, GitURL: entry.GitURL}
+		if entry.BeadsConfig != nil {
+			mr.Prefix = entry.BeadsConfig.Prefix
+		}
+		rigPath := filepath.Join(townRoot, name)
+		if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil {
+			mr.DefaultBranch = rigCfg.DefaultBranch
+			if mr.Prefix == "" && rigCfg.Beads != nil {
+				mr.Prefix = rigCfg.Beads.Prefix
+			}
+		}
+		if ref, err := git.NewGit(filepath.Join(rigPath, "mayor", "rig")).Rev("HEAD"); err == nil {
+			mr.Ref = ref
+		}
+		m.Rigs = append(m.Rigs, mr)
+	}
+
+	m.Formulas = append(m.Formulas, manifestFormulaOverrides(townRoot, "town", townRoot)...)
+	for _, name := range names {
+		m.Formulas = append(m.Formulas, manifestFormulaOverrides(townRoot, name, filepath.Join(townRoot, name))...)
+	}
+
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		m.Agents = manifestAgents{
+			Default: settings.DefaultAgent,
+			Roles:   settings.RoleAgents,
+		}
+		for name, rc := range settings.Agents {
+			if rc == nil {
+				continue
+			}
+			if m.Agents.Custom == nil {
+				m.Agents.Custom = make(map[string]*config.RuntimeConfig)
+			}
+			exported := *rc
+			if len(exported.Env) > 0 {
+				exported.Env = nil
+				m.strippedEnv = append(m.strippedEnv, name)
+			}
+			m.Agents.Custom[name] = &exported
+		}
+		sort.Strings(m.strippedEnv)
+	}
+
+	if accounts, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot)); err == nil {
+		for handle, acct := range accounts.Accounts {
+			m.Profiles = append(m.Profiles, manifestProfile{
+				Handle:      handle,
+				Email:       acct.Email,
+				Description: acct.Description,
+				Default:     handle == accounts.Default,
+			})
+		}
+		sort.Slice(m.Profiles, func(i, j int) bool { return m.Profiles[i].Handle < m.Profiles[j].Handle })
+	}
+
+	return m, nil
+}
+
+// manifestFormulaOverrides lists the formula files in dir/.beads/formulas.
+func manifestFormulaOverrides(townRoot, scope, dir string) []manifestFormula {
+	formulasDir := filepath.Join(dir, ".beads", "formulas")
+	entries, err := os.ReadDir(formulasDir)
+	if err != nil {
+		return nil
+	}
+	var out []manifestFormula
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		var name string
+		for _, ext := range formulaExtensions {
+			if strings.HasSuffix(e.Name(), ext) {
+				name = strings.TrimSuffix(e.Name(), ext)
+				break
+			}
+		}
+		if name == "" {
+			continue
+		}
+		path := filepath.Join(formulasDir, e.Name())
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town
+		if err != nil {
+			continue
+		}
+		rel, _ := filepath.Rel(townRoot, path)
+		out = append(out, manifestFormula{
+			Name:   name,
+			Scope:  scope,
+			Path:   filepath.ToSlash(rel),
+			SHA256: sha256Hex(data),
+		})
+	}
+	return out
+}
+
+// encodeTownManifest renders a manifest as "json" or "toml".
+func encodeTownManifest(m *townManifest, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case "toml":
+		var sb strings.Builder
+		if err := toml.NewEncoder(&sb).Encode(m); err != nil {
+			return nil, err
+		}
+		return []byte(sb.String()), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want json or toml)", format)
+	}
+}
+
+// loadTownManifest reads a manifest, choosing the decoder by extension.
+func loadTownManifest(path string) (*townManifest, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: manifest path is user-provided
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	var m townManifest
+	if strings.HasSuffix(path, ".toml") {
+		if _, err := toml.Decode(string(data), &m); err != nil {
+			return nil, fmt.Errorf("parsing manifest: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if m.Version > townManifestVersion {
+		return nil, fmt.Errorf("manifest version %d is newer than this gt supports (%d)", m.Version, townManifestVersion)
+	}
+	return &m, nil
+}
+
+// applyTownManifest brings the town at townRoot in line with m: agent
+// settings and profiles are merged, missing rigs are added at their
+// recorded refs, and formula overrides are checked against their recorded
+// hashes.
+func applyTownManifest(townRoot string, m *townManifest) error {
+	if m.GTVersion != "" && m.GTVersion != Version {
+		fmt.Printf("%s manifest was exported by gt %s; this is gt %s\n",
+			style.Dim.Render("Warning:"), m.GTVersion, Version)
+	}
+
+	// Agents
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if m.Agents.Default != "" {
+		settings.DefaultAgent = m.Agents.Default
+	}
+	for role, agent := range m.Agents.Roles {
+		if settings.RoleAgents == nil {
+			settings.RoleAgents = make(map[string]string)
+		}
+		settings.RoleAgents[role] = agent
+	}
+	for name, rc := range m.Agents.Custom {
+		if settings.Agents == nil {
+			settings.Agents = make(map[string]*config.RuntimeConfig)
+		}
+		settings.Agents[name] = rc
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("   ✓ Applied agent settings\n")
+
+	// Profiles
+	if len(m.Profiles) > 0 {
+		accountsPath := constants.MayorAccountsPath(townRoot)
+		accounts, err := config.LoadAccountsConfig(accountsPath)
+		if err != nil {
+			accounts = config.NewAccountsConfig()
+		}
+		for _, p := range m.Profiles {
+			if _, exists := accounts.Accounts[p.Handle]; !exists {
+				accounts.Accounts[p.Handle] = config.Account{
+					Email:       p.Email,
+					Description: p.Description,
+					ConfigDir:   config.DefaultAccountsConfigDir() + "/" + p.Handle,
+				}
+			}
+			if p.Default {
+				accounts.Default = p.Handle
+			}
+		}
+		if err := config.SaveAccountsConfig(accountsPath, accounts); err != nil {
+			return fmt.Errorf("saving accounts config: %w", err)
+		}
+		fmt.Printf("   ✓ Applied %d profile(s) (log in with 'gt account switch <handle>')\n", len(m.Profiles))
+	}
+
+	// Rigs
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	var failed []string
+	for _, r := range m.Rigs {
+		if _, exists := rigsConfig.Rigs[r.Name]; exists {
+			fmt.Printf("   %s Rig %s already registered\n", style.Dim.Render("○"), r.Name)
+			continue
+		}
+		args := []string{"rig", "add", r.Name, r.GitURL}
+		if r.Prefix != "" {
+			args = append(args, "--prefix", r.Prefix)
+		}
+		if r.DefaultBranch != "" {
+			args = append(args, "--branch", r.DefaultBranch)
+		}
+		fmt.Printf("   → gt %s\n", strings.Join(args, " "))
+		c := exec.Command("gt", args...) //nolint:gosec // G204: args come from the manifest the user supplied
+		c.Dir = townRoot
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := cmdtrace.Run(c); err != nil {
+			fmt.Printf("   %s Adding rig %s: %v\n", style.Error.Render("✗"), r.Name, err)
+			failed = append(failed, r.Name)
+			continue
+		}
+		if r.Ref != "" {
+			if err := checkoutManifestRef(filepath.Join(townRoot, r.Name, "mayor", "rig"), r.Ref); err != nil {
+				fmt.Printf("   %s Checking out %s at %s: %v\n", style.Error.Render("✗"), r.Name, r.Ref, err)
+				failed = append(failed, r.Name)
+				continue
+			}
+			fmt.Printf("   ✓ Checked out %s at %s\n", r.Name, r.Ref)
+		}
+	}
+
+	// Formula overrides are content the manifest only fingerprints; report
+	// which ones still need to be copied over.
+	for _, f := range m.Formulas {
+		data, err := os.ReadFile(filepath.Join(townRoot, filepath.FromSlash(f.Path))) //nolint:gosec // G304: path is within the town
+		switch {
+		case err != nil:
+			fmt.Printf("   %s Formula override %s missing (%s)\n", style.Warning.Render("⚠"), f.Name, f.Path)
+		case sha256Hex(data) != f.SHA256:
+			fmt.Printf("   %s Formula override %s differs from manifest (%s)\n", style.Warning.Render("⚠"), f.Name, f.Path)
+		default:
+			fmt.Printf("   ✓ Formula override %s matches\n", f.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to add rig(s): %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// checkoutManifestRef checks a rig's canonical clone out at the ref the
+// manifest recorded, fetching first if the clone doesn't have it yet.
+func checkoutManifestRef(clonePath, ref string) error {
+	g := git.NewGit(clonePath)
+	if head, err := g.Rev("HEAD"); err == nil && head == ref {
+		return nil
+	}
+	if _, err := g.Rev(ref); err != nil {
+		if err := g.Fetch("origin"); err != nil {
+			return err
+		}
+	}
+	return g.Checkout(ref)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func setupManifestTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	rigs := &config.RigsConfig{
+		Version: config.CurrentRigsVersion,
+		Rigs: map[string]config.RigEntry{
+			"gastown": {GitURL: "https://example.com/gastown.git", BeadsConfig: &config.BeadsConfig{Prefix: "gt"}},
+		},
+	}
+	if err := config.SaveRigsConfig(constants.MayorRigsPath(townRoot), rigs); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.DefaultAgent = "codex"
+	settings.RoleAgents = map[string]string{"witness": "claude-haiku"}
+	settings.Agents = map[string]*config.RuntimeConfig{
+		"review-bot": {Command: "review-bot", Env: map[string]string{"REVIEW_API_KEY": "secret"}},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	formulas := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(formulas, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(formulas, "shiny.formula.toml"), []byte("formula = \"shiny\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func TestTownManifestRoundTrip(t *testing.T) {
+	townRoot := setupManifestTown(t)
+
+	m, err := buildTownManifest(townRoot)
+	if err != nil {
+		t.Fatalf("buildTownManifest: %v", err)
+	}
+	if len(m.Rigs) != 1 || m.Rigs[0].Name != "gastown" || m.Rigs[0].Prefix != "gt" {
+		t.Errorf("rigs = %+v", m.Rigs)
+	}
+	if len(m.Formulas) != 1 || m.Formulas[0].Name != "shiny" || m.Formulas[0].Path != ".beads/formulas/shiny.formula.toml" {
+		t.Errorf("formulas = %+v", m.Formulas)
+	}
+	if m.Agents.Default != "codex" || m.Agents.Roles["witness"] != "claude-haiku" {
+		t.Errorf("agents = %+v", m.Agents)
+	}
+	if bot := m.Agents.Custom["review-bot"]; bot == nil || bot.Command != "review-bot" || bot.Env != nil {
+		t.Errorf("custom agent = %+v, want review-bot without env", bot)
+	}
+	if len(m.strippedEnv) != 1 || m.strippedEnv[0] != "review-bot" {
+		t.Errorf("strippedEnv = %v, want [review-bot]", m.strippedEnv)
+	}
+
+	for _, format := range []string{"json", "toml"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := encodeTownManifest(m, format)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			path := filepath.Join(t.TempDir(), "town."+format)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := loadTownManifest(path)
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if loaded.Rigs[0].GitURL != m.Rigs[0].GitURL || loaded.Formulas[0].SHA256 != m.Formulas[0].SHA256 ||
+				loaded.Agents.Default != "codex" {
+				t.Errorf("round trip mismatch: %+v", loaded)
+			}
+		})
+	}
+
+	if _, err := encodeTownManifest(m, "yaml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestApplyTownManifestSettings(t *testing.T) {
+	townRoot := setupManifestTown(t)
+	m := &townManifest{
+		Version:  townManifestVersion,
+		Agents:   manifestAgents{Default: "gemini", Roles: map[string]string{"polecat": "claude-sonnet"}},
+		Profiles: []manifestProfile{{Handle: "work", Email: "me@example.com", Default: true}},
+	}
+
+	if err := applyTownManifest(townRoot, m); err != nil {
+		t.Fatalf("applyTownManifest: %v", err)
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.DefaultAgent != "gemini" {
+		t.Errorf("DefaultAgent = %q, want gemini", settings.DefaultAgent)
+	}
+	if settings.RoleAgents["witness"] != "claude-haiku" || settings.RoleAgents["polecat"] != "claude-sonnet" {
+		t.Errorf("RoleAgents = %v, want merged roles", settings.RoleAgents)
+	}
+
+	accounts, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accounts.Default != "work" || accounts.Accounts["work"].Email != "me@example.com" {
+		t.Errorf("accounts = %+v", accounts)
+	}
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Witness audit flags
+var (
+	witnessLogJSON   bool
+	witnessLogLimit  int
+	witnessLogAction string
+	witnessVerifyAll bool
+)
+
+var witnessLogCmd = &cobra.Command{
+	Use:   "log [rig]",
+	Short: "Show the rig's audit log",
+	Long: `Show the append-only audit log of significant actions in a rig.
+
+gt records formula runs, beads it creates, formula override changes
+(modify, promote/demote, reset), rig settings changes (keys only, since
+values may be secrets), doctor fixes, and synthesis closes to
+<rig>/witness/audit.jsonl. Each entry is hash-chained to the previous one;
+use 'gt witness verify' to detect edits.
+
+Examples:
+  gt witness log                        # Current rig
+  gt witness log greenplace -n 20       # Last 20 entries
+  gt witness log --action formula.run   # Only formula runs
+  gt witness log --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWitnessLog,
+}
+
+var witnessVerifyCmd = &cobra.Command{
+	Use:   "verify [rig]",
+	Short: "Verify the audit log hash chain",
+	Long: `Verify that a rig's audit log has not been tampered with.
+
+Recomputes each entry's hash and checks that it chains to the previous
+entry. Editing, deleting, or reordering entries breaks the chain and is
+reported with the first entry that fails.
+
+Examples:
+  gt witness verify              # Current rig
+  gt witness verify greenplace
+  gt witness verify --all        # Every rig in the town`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWitnessVerify,
+}
+
+func init() {
+	witnessLogCmd.Flags().BoolVar(&witnessLogJSON, "json", false, "Output as JSON")
+	witnessLogCmd.Flags().IntVarP(&witnessLogLimit, "limit", "n", 0, "Show only the last N entries")
+	witnessLogCmd.Flags().StringVar(&witnessLogAction, "action", "", "Filter by action (e.g., formula.run, doctor.fix)")
+
+	witnessVerifyCmd.Flags().BoolVar(&witnessVerifyAll, "all", false, "Verify every rig in the town")
+
+	witnessCmd.AddCommand(witnessLogCmd)
+	witnessCmd.AddCommand(witnessVerifyCmd)
+}
+
+// resolveAuditRigPath returns the rig name and path for audit commands,
+// using the argument if given or inferring the rig from the cwd.
+func resolveAuditRigPath(args []string) (string, string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	} else {
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return "", "", fmt.Errorf("could not determine rig (specify a rig name): %w", err)
+		}
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return "", "", err
+	}
+	return r.Name, r.Path, nil
+}
+
+func runWitnessLog(cmd *cobra.Command, args []string) error {
+	rigName, rigPath, err := resolveAuditRigPath(args)
+	if err != nil {
+		return err
+	}
+
+	entries, err := witness.ReadAuditLog(rigPath)
+	if err != nil {
+		return err
+	}
+
+	if witnessLogAction != "" {
+		var filtered []witness.AuditEntry
+		for _, e := range entries {
+			if e.Action == witnessLogAction {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+	if witnessLogLimit > 0 && len(entries) > witnessLogLimit {
+		entries = entries[len(entries)-witnessLogLimit:]
+	}
+
+	if witnessLogJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No audit entries for %s\n", rigName)
+		return nil
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%s %s %-16s %s",
+			style.Dim.Render(fmt.Sprintf("#%-4d", e.Seq)),
+			style.Dim.Render(e.Time.Local().Format("2006-01-02 15:04:05")),
+			e.Action, e.Subject)
+		if e.Actor != "" {
+			fmt.Printf(" %s", style.Dim.Render("by "+e.Actor))
+		}
+		fmt.Println()
+		if len(e.Details) > 0 {
+			keys := make([]string, 0, len(e.Details))
+			for k := range e.Details {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Printf("      %s: %s\n", k, e.Details[k])
+			}
+		}
+	}
+	return nil
+}
+
+func runWitnessVerify(cmd *cobra.Command, args []string) error {
+	type target struct{ name, path string }
+	var targets []target
+
+	if witnessVerifyAll {
+		rigs, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		for _, r := range rigs {
+			targets = append(targets, target{r.Name, r.Path})
+		}
+	} else {
+		name, path, err := resolveAuditRigPath(args)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target{name, path})
+	}
+
+	failed := 0
+	for _, t := range targets {
+		n, err := witness.VerifyAuditLog(t.path)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Printf("%s %s: %v\n", style.Error.Render("✗"), t.name, err)
+			if n > 0 {
+				fmt.Printf("  Entries 1-%d verified before the break\n", n)
+			}
+		case n == 0:
+			fmt.Printf("%s %s: no audit entries\n", style.Dim.Render("○"), t.name)
+		default:
+			fmt.Printf("%s %s: %d entries verified\n", style.Success.Render("✓"), t.name, n)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d audit log(s) failed verification", failed)
+	}
+	return nil
+}
+
+// recordAudit appends an entry to a rig's audit log. Auditing is best-effort:
+// failures are reported as warnings and never abort the audited action.
+func recordAudit(townRoot, rigName string, entry witness.AuditEntry) {
+	if townRoot == "" || rigName == "" {
+		return
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+	if info, err := os.Stat(rigPath); err != nil || !info.IsDir() {
+		return
+	}
+	if err := witness.Record(rigPath, entry); err != nil {
+		fmt.Printf("%s could not record audit entry: %v\n", style.Dim.Render("Warning:"), err)
+	}
+}
+
+// recordAuditAllRigs records a town-wide action in every rig's audit log.
+func recordAuditAllRigs(townRoot string, entry witness.AuditEntry) {
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return
+	}
+	for _, r := range rigs {
+		recordAudit(townRoot, r.Name, entry)
+	}
+}
+
+// recordFormulaOverrideAudit records a change to a formula override file in
+// the audit log of the rig it lives in, or of every rig for town, user, and
+// project overrides outside a rig.
+func recordFormulaOverrideAudit(name, op, path string) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	entry := witness.AuditEntry{
+		Action:  witness.ActionFormulaOverride,
+		Subject: name,
+		Details: map[string]string{"op": op, "path": path},
+	}
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return
+	}
+	if rel, err := filepath.Rel(townRoot, path); err == nil {
+		top, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		for _, r := range rigs {
+			if r.Name == top {
+				recordAudit(townRoot, r.Name, entry)
+				return
+			}
+		}
+	}
+	for _, r := range rigs {
+		recordAudit(townRoot, r.Name, entry)
+	}
+}
+
+// auditJoin formats a list for an audit detail value.
+func auditJoin(items []string) string {
+	return strings.Join(items, ",")
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // AgentPreset identifies a supported LLM agent runtime.
@@ -98,6 +101,11 @@ type AgentRegistry struct {
 
 	// Agents maps agent names to their configurations.
 	Agents map[string]*AgentPresetInfo `json:"agents"`
+
+	// Order is the fallback chain for one-shot agent calls: when the
+	// chosen agent fails, times out, or is rate limited, the others are
+	// tried in this order. A rig's order replaces the town's.
+	Order []string `json:"order,omitempty"`
 }
 
 // CurrentAgentRegistryVersion is the current schema version.
@@ -308,6 +316,42 @@ func LoadRigAgentRegistry(path string) error {
 	return loadAgentRegistryFromPathLocked(path)
 }
 
+// LoadAgentOrder returns the agent fallback order for a rig: the rig
+// registry's order if it sets one, else the town registry's. Unreadable
+// registries are skipped.
+func LoadAgentOrder(townRoot, rigPath string) []string {
+	paths := []string{DefaultAgentRegistryPath(townRoot)}
+	if rigPath != "" && rigPath != townRoot {
+		paths = append([]string{RigAgentRegistryPath(rigPath)}, paths...)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from config
+		if err != nil {
+			continue
+		}
+		var registry AgentRegistry
+		if err := json.Unmarshal(data, &registry); err != nil {
+			continue
+		}
+		if len(registry.Order) > 0 {
+			return registry.Order
+		}
+	}
+	return nil
+}
+
+// AgentFallbackChain returns the agents to try, in turn, for a call to
+// primary: primary itself, then the rest of order.
+func AgentFallbackChain(primary string, order []string) []string {
+	chain := []string{primary}
+	for _, name := range order {
+		if name != "" && !slices.Contains(chain, name) {
+			chain = append(chain, name)
+		}
+	}
+	return chain
+}
+
 // GetAgentPreset returns the preset info for a given agent name.
 // Returns nil if the preset is not found.
 func GetAgentPreset(name AgentPreset) *AgentPresetInfo {
@@ -486,7 +530,7 @@ func SaveAgentRegistry(path string, registry *AgentRegistry) error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: config file
+	return fsx.WriteFile(path, data, 0644) //nolint:gosec // G306: config file
 }
 
 // NewExampleAgentRegistry creates an example registry with comments.
@@ -198,6 +198,40 @@ func TestLoadAgentRegistry(t *testing.T) {
 	ResetRegistryForTesting()
 }
 
+func TestLoadAgentOrder(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+	if err := SaveAgentRegistry(DefaultAgentRegistryPath(town), &AgentRegistry{
+		Version: CurrentAgentRegistryVersion,
+		Order:   []string{"claude", "opencode", "gemini"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(LoadAgentOrder(town, rig), ","); got != "claude,opencode,gemini" {
+		t.Errorf("LoadAgentOrder() = %s, want the town order", got)
+	}
+	if err := SaveAgentRegistry(RigAgentRegistryPath(rig), &AgentRegistry{
+		Version: CurrentAgentRegistryVersion,
+		Order:   []string{"gemini"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(LoadAgentOrder(town, rig), ","); got != "gemini" {
+		t.Errorf("LoadAgentOrder() = %s, want the rig order", got)
+	}
+}
+
+func TestAgentFallbackChain(t *testing.T) {
+	order := []string{"claude", "opencode", "gemini"}
+	if got := strings.Join(AgentFallbackChain("gemini", order), ","); got != "gemini,claude,opencode" {
+		t.Errorf("AgentFallbackChain(gemini) = %s", got)
+	}
+	if got := strings.Join(AgentFallbackChain("codex", nil), ","); got != "codex" {
+		t.Errorf("AgentFallbackChain(codex, nil) = %s", got)
+	}
+}
+
 func TestAgentPresetYOLOFlags(t *testing.T) {
 	t.Parallel()
 	// Verify YOLO flags are set correctly for each E2E tested agent
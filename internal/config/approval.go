@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApprovalConfig gates a rig's formula runs on approval: the convoy and its
+// beads are created, but no leg is dispatched until an approver runs
+// gt convoy approve.
+type ApprovalConfig struct {
+	// Required holds every formula run on the rig for approval, not just
+	// those of formulas that set approval = true.
+	Required bool `json:"required,omitempty"`
+
+	// Approvers are the mail addresses (e.g. "overseer", "mayor/") allowed
+	// to approve, and who are notified of pending runs. Empty allows anyone.
+	Approvers []string `json:"approvers,omitempty"`
+}
+
+// MayApprove reports whether the given address may approve a run.
+func (a *ApprovalConfig) MayApprove(who string) bool {
+	if a == nil || len(a.Approvers) == 0 {
+		return true
+	}
+	who = strings.TrimSuffix(who, "/")
+	for _, approver := range a.Approvers {
+		if strings.TrimSuffix(approver, "/") == who {
+			return true
+		}
+	}
+	return false
+}
+
+// validateApprovalConfig validates an ApprovalConfig.
+func validateApprovalConfig(c *ApprovalConfig) error {
+	for _, approver := range c.Approvers {
+		if strings.TrimSpace(approver) == "" {
+			return fmt.Errorf("%w: approval.approvers must not contain empty addresses", ErrMissingField)
+		}
+	}
+	return nil
+}
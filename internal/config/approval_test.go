@@ -0,0 +1,21 @@
+package config
+
+import "testing"
+
+func TestApprovalMayApprove(t *testing.T) {
+	var unset *ApprovalConfig
+	if !unset.MayApprove("gastown/crew/joe") {
+		t.Error("nil config should let anyone approve")
+	}
+	a := &ApprovalConfig{Approvers: []string{"overseer", "mayor/"}}
+	for who, want := range map[string]bool{
+		"overseer":         true,
+		"mayor":            true,
+		"mayor/":           true,
+		"gastown/crew/joe": false,
+	} {
+		if got := a.MayApprove(who); got != want {
+			t.Errorf("MayApprove(%q) = %v, want %v", who, got, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CloneConfig controls how much of a rig's repository is fetched and
+// checked out. It suits large monorepos where legs touch a small part of
+// the tree.
+type CloneConfig struct {
+	// Depth truncates history to this many commits when the rig's shared
+	// repo and crew clones are created. Zero fetches full history.
+	Depth int `json:"depth,omitempty"`
+
+	// Filter is a partial clone filter: "blob:none", "blob:limit=<n>[kmg]",
+	// or "tree:<depth>". Omitted objects are fetched on demand.
+	Filter string `json:"filter,omitempty"`
+
+	// Sparse lists the directories checked out in polecat worktrees and
+	// crew clones, relative to the repository root. Files at the root are
+	// always checked out. Empty checks out everything.
+	Sparse []string `json:"sparse,omitempty"`
+}
+
+var cloneFilterRe = regexp.MustCompile(`^(blob:none|blob:limit=[0-9]+[kmg]?|tree:[0-9]+)$`)
+
+// validateCloneConfig validates a CloneConfig.
+func validateCloneConfig(c *CloneConfig) error {
+	if c.Depth < 0 {
+		return fmt.Errorf("clone.depth must not be negative, got %d", c.Depth)
+	}
+	if c.Filter != "" && !cloneFilterRe.MatchString(c.Filter) {
+		return fmt.Errorf("clone.filter %q: want blob:none, blob:limit=<n>[kmg], or tree:<depth>", c.Filter)
+	}
+	for _, p := range c.Sparse {
+		clean := strings.Trim(p, "/")
+		if clean == "" || strings.ContainsAny(clean, "*?[!\\") {
+			return fmt.Errorf("clone.sparse entry %q must be a directory path without patterns", p)
+		}
+		for _, part := range strings.Split(clean, "/") {
+			if part == "" || part == "." || part == ".." {
+				return fmt.Errorf("clone.sparse entry %q must be a plain relative directory path", p)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestValidateCloneConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg     CloneConfig
+		wantErr bool
+	}{
+		{CloneConfig{}, false},
+		{CloneConfig{Depth: 1, Filter: "blob:none", Sparse: []string{"services/api", "libs/"}}, false},
+		{CloneConfig{Filter: "blob:limit=1m"}, false},
+		{CloneConfig{Filter: "tree:0"}, false},
+		{CloneConfig{Depth: -1}, true},
+		{CloneConfig{Filter: "sparse:oid=abc"}, true},
+		{CloneConfig{Sparse: []string{"/"}}, true},
+		{CloneConfig{Sparse: []string{"src/*.go"}}, true},
+		{CloneConfig{Sparse: []string{"!vendor"}}, true},
+		{CloneConfig{Sparse: []string{"../other"}}, true},
+		{CloneConfig{Sparse: []string{"a//b"}}, true},
+	} {
+		err := validateCloneConfig(&tc.cfg)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("validateCloneConfig(%+v) = %v, wantErr %v", tc.cfg, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrConcurrencyLimit indicates a concurrency class has no room for
+// another convoy.
+var ErrConcurrencyLimit = errors.New("concurrency class limit reached")
+
+// ConcurrencyConfig caps formula convoys by concurrency class across every
+// rig in the town. Formulas opt into a class with concurrency_class.
+type ConcurrencyConfig struct {
+	// Classes maps a class name to the most convoys of that class open at
+	// the same time. Classes not listed, or set to zero, are not limited.
+	Classes map[string]int `json:"classes"`
+}
+
+// Limit returns the class's limit, or 0 if it is not limited.
+func (c *ConcurrencyConfig) Limit(class string) int {
+	if c == nil || class == "" {
+		return 0
+	}
+	return c.Classes[class]
+}
+
+// Check returns an ErrConcurrencyLimit error if open convoys of class
+// leave no room for one more, or nil if the run may proceed.
+func (c *ConcurrencyConfig) Check(class string, open int) error {
+	limit := c.Limit(class)
+	if limit <= 0 || open < limit {
+		return nil
+	}
+	return fmt.Errorf("%w: %d/%d %s convoys open", ErrConcurrencyLimit, open, limit, class)
+}
+
+// ClassNames returns the limited classes in order.
+func (c *ConcurrencyConfig) ClassNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.Classes))
+	for name, limit := range c.Classes {
+		if limit > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateConcurrencyConfig validates a ConcurrencyConfig.
+func validateConcurrencyConfig(c *ConcurrencyConfig) error {
+	for name, limit := range c.Classes {
+		if name == "" {
+			return fmt.Errorf("%w: concurrency.classes has an empty class name", ErrMissingField)
+		}
+		if limit < 0 {
+			return fmt.Errorf("%w: concurrency.classes.%s must be non-negative", ErrMissingField, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestConcurrencyCheck(t *testing.T) {
+	c := &ConcurrencyConfig{Classes: map[string]int{"heavy-build": 1, "gpu": 2, "off": 0}}
+
+	if err := c.Check("heavy-build", 0); err != nil {
+		t.Errorf("room left: unexpected error %v", err)
+	}
+	if err := c.Check("heavy-build", 1); !errors.Is(err, ErrConcurrencyLimit) {
+		t.Errorf("class full: got %v, want ErrConcurrencyLimit", err)
+	}
+	if err := c.Check("gpu", 1); err != nil {
+		t.Errorf("gpu 1/2: unexpected error %v", err)
+	}
+	for _, class := range []string{"", "unlisted", "off"} {
+		if err := c.Check(class, 100); err != nil {
+			t.Errorf("class %q is unlimited, got %v", class, err)
+		}
+	}
+	var unset *ConcurrencyConfig
+	if err := unset.Check("heavy-build", 100); err != nil {
+		t.Errorf("nil config: unexpected error %v", err)
+	}
+	if got := c.ClassNames(); len(got) != 2 || got[0] != "gpu" || got[1] != "heavy-build" {
+		t.Errorf("ClassNames = %v, want [gpu heavy-build]", got)
+	}
+}
+
+func TestTownSettingsConcurrencyValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings", "config.json")
+	settings := NewTownSettings()
+	settings.Concurrency = &ConcurrencyConfig{Classes: map[string]int{"heavy-build": -1}}
+	if err := SaveTownSettings(path, settings); err == nil {
+		t.Fatal("expected a negative class limit to be rejected")
+	}
+
+	settings.Concurrency = &ConcurrencyConfig{Classes: map[string]int{"heavy-build": 1}}
+	if err := SaveTownSettings(path, settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	loaded, err := LoadOrCreateTownSettings(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateTownSettings: %v", err)
+	}
+	if loaded.Concurrency.Limit("heavy-build") != 1 {
+		t.Errorf("concurrency round-trip = %+v", loaded.Concurrency)
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContainerCommand wraps an agent startup command so it runs inside image
+// via docker run instead of directly on the host. workDir is bind-mounted at
+// the same path and used as the working directory, so paths in the command
+// and the agent's output stay valid on the host. mounts are extra
+// "src:dst[:mode]" binds; a relative src is resolved against workDir.
+//
+// The container runs as the calling user so files it writes in the worktree
+// stay owned by them, and is removed when the agent exits.
+func ContainerCommand(command, workDir, image string, mounts []string) string {
+	args := []string{"docker", "run", "--rm", "-it", "--init", "-e", "TERM"}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", uid, gid))
+	}
+	args = append(args, "-v", workDir+":"+workDir, "-w", workDir)
+	for _, m := range mounts {
+		if src, rest, ok := strings.Cut(m, ":"); ok && !filepath.IsAbs(src) {
+			m = filepath.Join(workDir, src) + ":" + rest
+		}
+		args = append(args, "-v", m)
+	}
+	args = append(args, image, "sh", "-c", command)
+
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = ShellQuote(a)
+	}
+	return "exec " + strings.Join(quoted, " ")
+}
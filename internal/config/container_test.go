@@ -0,0 +1,27 @@
+package config
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestContainerCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix paths")
+	}
+	got := ContainerCommand("export A='x y' && claude", "/town/rig/polecats/Toast/rig", "golang:1.22",
+		[]string{"cache:/cache", "/etc/ssl:/etc/ssl:ro"})
+
+	for _, want := range []string{
+		"exec docker run --rm -it",
+		"-v /town/rig/polecats/Toast/rig:/town/rig/polecats/Toast/rig -w /town/rig/polecats/Toast/rig",
+		"-v /town/rig/polecats/Toast/rig/cache:/cache",
+		"-v /etc/ssl:/etc/ssl:ro",
+		`golang:1.22 sh -c 'export A='\''x y'\'' && claude'`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ContainerCommand() = %q, missing %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Disk usage categories reported by gt du.
+const (
+	DiskBeads    = "beads"    // .beads/ databases
+	DiskFormulas = "formulas" // .beads/formulas/ overrides
+	DiskRepo     = "repo"     // The rig's shared repo (.repo.git)
+	DiskCrew     = "crew"     // Crew clones
+	DiskPolecats = "polecats" // Polecat worktrees and the worktree pool
+	DiskReviews  = "reviews"  // Review outputs (.reviews/)
+	DiskRuntime  = "runtime"  // .runtime/ state, caches, and logs
+	DiskOther    = "other"    // Everything else (mayor and refinery clones, ...)
+)
+
+// DiskUsageCategories lists the disk usage categories in report order.
+var DiskUsageCategories = []string{
+	DiskBeads, DiskFormulas, DiskRepo, DiskCrew, DiskPolecats, DiskReviews, DiskRuntime, DiskOther,
+}
+
+// DiskUsageConfig sets disk usage limits that gt doctor warns about.
+type DiskUsageConfig struct {
+	// LimitsMB caps each category, in megabytes, for the town itself and
+	// for every rig separately.
+	LimitsMB map[string]int `json:"limits_mb,omitempty"`
+}
+
+// Limit returns the limit of category in bytes, or 0 if it has none.
+func (c *DiskUsageConfig) Limit(category string) int64 {
+	if c == nil {
+		return 0
+	}
+	return int64(c.LimitsMB[category]) << 20
+}
+
+// validateDiskUsageConfig validates a DiskUsageConfig.
+func validateDiskUsageConfig(c *DiskUsageConfig) error {
+	for category, mb := range c.LimitsMB {
+		if !slices.Contains(DiskUsageCategories, category) {
+			return fmt.Errorf("disk_usage.limits_mb: unknown category %q (want one of %v)", category, DiskUsageCategories)
+		}
+		if mb < 0 {
+			return fmt.Errorf("disk_usage.limits_mb.%s must not be negative", category)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default retention periods of gt gc.
+const (
+	DefaultGCCacheDays      = 7
+	DefaultGCSessionLogDays = 14
+	DefaultGCReviewDays     = 30
+	DefaultGCWispHours      = 24
+)
+
+// GCConfig is the retention policy of gt gc. Zero fields use the defaults.
+type GCConfig struct {
+	// CacheDays removes caches under .runtime/cache/ not written for this
+	// many days. Caches are rebuilt on demand.
+	CacheDays int `json:"cache_days,omitempty"`
+
+	// SessionLogDays removes logs of ended process-backend sessions after
+	// this many days.
+	SessionLogDays int `json:"session_log_days,omitempty"`
+
+	// ReviewDays removes review output directories (.reviews/<id>) not
+	// modified for this many days.
+	ReviewDays int `json:"review_days,omitempty"`
+
+	// WispHours deletes closed wisps this many hours after they closed.
+	WispHours int `json:"wisp_hours,omitempty"`
+}
+
+// CacheRetention returns how long caches are kept.
+func (c *GCConfig) CacheRetention() time.Duration {
+	if c == nil {
+		return gcDays(0, DefaultGCCacheDays)
+	}
+	return gcDays(c.CacheDays, DefaultGCCacheDays)
+}
+
+// SessionLogRetention returns how long logs of ended sessions are kept.
+func (c *GCConfig) SessionLogRetention() time.Duration {
+	if c == nil {
+		return gcDays(0, DefaultGCSessionLogDays)
+	}
+	return gcDays(c.SessionLogDays, DefaultGCSessionLogDays)
+}
+
+// ReviewRetention returns how long review output directories are kept.
+func (c *GCConfig) ReviewRetention() time.Duration {
+	if c == nil {
+		return gcDays(0, DefaultGCReviewDays)
+	}
+	return gcDays(c.ReviewDays, DefaultGCReviewDays)
+}
+
+// WispRetention returns how long closed wisps are kept.
+func (c *GCConfig) WispRetention() time.Duration {
+	hours := DefaultGCWispHours
+	if c != nil && c.WispHours > 0 {
+		hours = c.WispHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// gcDays returns n days, or def days if n is zero.
+func gcDays(n, def int) time.Duration {
+	if n <= 0 {
+		n = def
+	}
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// validateGCConfig validates a GCConfig.
+func validateGCConfig(c *GCConfig) error {
+	if c.CacheDays < 0 || c.SessionLogDays < 0 || c.ReviewDays < 0 || c.WispHours < 0 {
+		return fmt.Errorf("gc retention periods must not be negative")
+	}
+	return nil
+}
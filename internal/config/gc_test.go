@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGCConfigRetention(t *testing.T) {
+	var unset *GCConfig
+	if got := unset.ReviewRetention(); got != DefaultGCReviewDays*24*time.Hour {
+		t.Errorf("nil ReviewRetention = %v, want %d days", got, DefaultGCReviewDays)
+	}
+	if got := unset.WispRetention(); got != DefaultGCWispHours*time.Hour {
+		t.Errorf("nil WispRetention = %v, want %d hours", got, DefaultGCWispHours)
+	}
+
+	c := &GCConfig{CacheDays: 2, WispHours: 6}
+	if got := c.CacheRetention(); got != 48*time.Hour {
+		t.Errorf("CacheRetention = %v, want 48h", got)
+	}
+	if got := c.SessionLogRetention(); got != DefaultGCSessionLogDays*24*time.Hour {
+		t.Errorf("SessionLogRetention = %v, want default", got)
+	}
+	if got := c.WispRetention(); got != 6*time.Hour {
+		t.Errorf("WispRetention = %v, want 6h", got)
+	}
+
+	if err := validateGCConfig(&GCConfig{ReviewDays: -1}); err == nil {
+		t.Error("validateGCConfig accepted a negative retention")
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GitHubConfig points a rig at a GitHub Enterprise Server host. gh reaches
+// it through HTTPS_PROXY/NO_PROXY like any other host.
+type GitHubConfig struct {
+	// Host is the GitHub hostname, e.g. "github.example.com". Empty means
+	// github.com.
+	Host string `json:"host,omitempty"`
+
+	// Token authenticates to Host. It must be a secret reference
+	// (secret:env:NAME or secret:file:PATH). Empty uses gh's stored login
+	// for the host (gh auth login --hostname <host>).
+	Token string `json:"token,omitempty"`
+}
+
+// validateGitHubConfig validates a GitHubConfig.
+func validateGitHubConfig(c *GitHubConfig) error {
+	if strings.Contains(c.Host, "://") || strings.ContainsAny(c.Host, "/ ") {
+		return fmt.Errorf("github.host must be a bare hostname, got %q", c.Host)
+	}
+	if c.Token != "" {
+		if !strings.HasPrefix(c.Token, secretRefPrefix) {
+			return fmt.Errorf("github.token must be a secret reference (secret:env:NAME or secret:file:PATH), not a literal token")
+		}
+		if _, _, err := parseSecretRef(c.Token); err != nil {
+			return fmt.Errorf("github.token: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package config
+
+import "testing"
+
+func TestValidateGitHubConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg     GitHubConfig
+		wantErr bool
+	}{
+		{GitHubConfig{}, false},
+		{GitHubConfig{Host: "github.example.com"}, false},
+		{GitHubConfig{Host: "github.example.com", Token: "secret:env:GHE_TOKEN"}, false},
+		{GitHubConfig{Host: "https://github.example.com"}, true},
+		{GitHubConfig{Host: "github.example.com/api"}, true},
+		{GitHubConfig{Token: "ghp_literal"}, true},
+		{GitHubConfig{Token: "secret:vault:x"}, true},
+	} {
+		err := validateGitHubConfig(&tc.cfg)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("validateGitHubConfig(%+v) = %v, wantErr %v", tc.cfg, err, tc.wantErr)
+		}
+	}
+}
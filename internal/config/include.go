@@ -0,0 +1,228 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Settings files (settings/config.json) may list other files to layer
+// underneath them:
+//
+//	{"include": ["config.base.json", "config.dev.json"], ...}
+//
+// Merge semantics:
+//   - Include paths are relative to the including file's directory.
+//   - Includes are merged in order, later ones overriding earlier ones, and
+//     the including file overrides all of its includes.
+//   - Objects merge key by key, recursively. Arrays and scalars replace.
+//   - An explicit null removes the key inherited from an include.
+//   - Included files may include others; cycles are an error.
+//
+// Saving a settings file that has includes writes only the values that
+// differ from its includes, so the layering survives gt config edits.
+
+const (
+	includeKey      = "include"
+	maxIncludeDepth = 8
+)
+
+// ResolveLayeredConfig reads a settings file and its includes and returns
+// the flattened result along with the files that contributed to it, lowest
+// priority first.
+func ResolveLayeredConfig(path string) (map[string]interface{}, []string, error) {
+	return readLayered(path, nil)
+}
+
+// readLayeredJSON returns the flattened JSON for path. Files without an
+// include list are returned unchanged. Missing files return the os error
+// so callers can check os.IsNotExist.
+func readLayeredJSON(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(string(data), `"`+includeKey+`"`) {
+		return data, nil
+	}
+
+	var probe struct {
+		Include []string `json:"include"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil || len(probe.Include) == 0 {
+		return data, nil
+	}
+
+	merged, _, err := readLayered(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the include list so a later save can preserve the layering.
+	merged[includeKey] = probe.Include
+	return json.Marshal(merged)
+}
+
+// readLayered reads path and merges its includes underneath it. stack holds
+// the files currently being resolved, for cycle detection.
+func readLayered(path string, stack []string) (map[string]interface{}, []string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range stack {
+		if p == abs {
+			return nil, nil, fmt.Errorf("include cycle: %s", strings.Join(append(stack, abs), " → "))
+		}
+	}
+	if len(stack) >= maxIncludeDepth {
+		return nil, nil, fmt.Errorf("includes nested deeper than %d at %s", maxIncludeDepth, path)
+	}
+	stack = append(stack, abs)
+
+	data, err := os.ReadFile(abs) //nolint:gosec // G304: path is a settings file or one it includes
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	includes, err := includeList(doc, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(doc, includeKey)
+
+	merged := make(map[string]interface{})
+	var sources []string
+	for _, inc := range includes {
+		incPath := inc
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(abs), incPath)
+		}
+		layer, layerSources, err := readLayered(incPath, stack)
+		if err != nil {
+			return nil, nil, fmt.Errorf("include %s from %s: %w", inc, path, err)
+		}
+		mergeLayer(merged, layer)
+		sources = append(sources, layerSources...)
+	}
+	mergeLayer(merged, doc)
+	return merged, append(sources, abs), nil
+}
+
+// includeList extracts the include array from a parsed settings document.
+func includeList(doc map[string]interface{}, path string) ([]string, error) {
+	raw, ok := doc[includeKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: include must be an array of file paths", path)
+	}
+	includes := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("%s: include entries must be non-empty strings", path)
+		}
+		includes = append(includes, s)
+	}
+	return includes, nil
+}
+
+// mergeLayer merges overlay into base in place.
+func mergeLayer(base, overlay map[string]interface{}) {
+	for k, v := range overlay {
+		if v == nil {
+			delete(base, k)
+			continue
+		}
+		if vm, ok := v.(map[string]interface{}); ok {
+			if bm, ok := base[k].(map[string]interface{}); ok {
+				mergeLayer(bm, vm)
+				continue
+			}
+			// Copy so later merges into base don't alias the overlay.
+			copied := make(map[string]interface{})
+			mergeLayer(copied, vm)
+			base[k] = copied
+			continue
+		}
+		base[k] = v
+	}
+}
+
+// diffLayer returns the smallest overlay that turns base into full:
+// changed keys, with nulls for keys full no longer has.
+func diffLayer(base, full map[string]interface{}) map[string]interface{} {
+	overlay := make(map[string]interface{})
+	for k, v := range full {
+		bv, ok := base[k]
+		if !ok {
+			overlay[k] = v
+			continue
+		}
+		vm, vIsMap := v.(map[string]interface{})
+		bm, bIsMap := bv.(map[string]interface{})
+		if vIsMap && bIsMap {
+			if sub := diffLayer(bm, vm); len(sub) > 0 {
+				overlay[k] = sub
+			}
+			continue
+		}
+		if !reflect.DeepEqual(v, bv) {
+			overlay[k] = v
+		}
+	}
+	for k := range base {
+		if _, ok := full[k]; !ok {
+			overlay[k] = nil
+		}
+	}
+	return overlay
+}
+
+// marshalLayered encodes settings for path. If the settings carry an include
+// list, only the values that differ from the included layers are written.
+func marshalLayered(path string, settings interface{}, includes []string) ([]byte, error) {
+	if len(includes) == 0 {
+		return json.MarshalIndent(settings, "", "  ")
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	var full map[string]interface{}
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, err
+	}
+	delete(full, includeKey)
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	base := make(map[string]interface{})
+	for _, inc := range includes {
+		incPath := inc
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(abs), incPath)
+		}
+		layer, _, err := readLayered(incPath, []string{abs})
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", inc, err)
+		}
+		mergeLayer(base, layer)
+	}
+
+	overlay := diffLayer(base, full)
+	overlay[includeKey] = includes
+	return json.MarshalIndent(overlay, "", "  ")
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSettingsFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveLayeredConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeSettingsFile(t, filepath.Join(dir, "base.json"),
+		`{"type": "rig-settings", "version": 1, "agent": "claude", "merge_queue": {"enabled": true, "run_tests": true}, "role_agents": {"witness": "gemini"}}`)
+	writeSettingsFile(t, filepath.Join(dir, "dev.json"),
+		`{"include": ["base.json"], "merge_queue": {"run_tests": false}, "role_agents": null}`)
+	settingsPath := filepath.Join(dir, "config.json")
+	writeSettingsFile(t, settingsPath, `{"include": ["dev.json"], "agent": "codex"}`)
+
+	merged, sources, err := ResolveLayeredConfig(settingsPath)
+	if err != nil {
+		t.Fatalf("ResolveLayeredConfig: %v", err)
+	}
+	if len(sources) != 3 || filepath.Base(sources[0]) != "base.json" || filepath.Base(sources[2]) != "config.json" {
+		t.Errorf("sources = %v", sources)
+	}
+	if merged["agent"] != "codex" {
+		t.Errorf("agent = %v, want codex (including file wins)", merged["agent"])
+	}
+	mq := merged["merge_queue"].(map[string]interface{})
+	if mq["enabled"] != true || mq["run_tests"] != false {
+		t.Errorf("merge_queue = %v, want objects merged key by key", mq)
+	}
+	if _, ok := merged["role_agents"]; ok {
+		t.Error("role_agents should be removed by explicit null")
+	}
+	if _, ok := merged["include"]; ok {
+		t.Error("include should not appear in flattened settings")
+	}
+}
+
+func TestResolveLayeredConfigCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeSettingsFile(t, filepath.Join(dir, "a.json"), `{"include": ["b.json"]}`)
+	writeSettingsFile(t, filepath.Join(dir, "b.json"), `{"include": ["a.json"]}`)
+
+	_, _, err := ResolveLayeredConfig(filepath.Join(dir, "a.json"))
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected include cycle error, got %v", err)
+	}
+}
+
+func TestRigSettingsIncludeRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	writeSettingsFile(t, filepath.Join(dir, "config.base.json"),
+		`{"type": "rig-settings", "version": 1, "agent": "claude", "role_agents": {"witness": "gemini"}}`)
+	path := filepath.Join(dir, "config.json")
+	writeSettingsFile(t, path, `{"include": ["config.base.json"], "type": "rig-settings", "version": 1}`)
+
+	settings, err := LoadRigSettings(path)
+	if err != nil {
+		t.Fatalf("LoadRigSettings: %v", err)
+	}
+	if settings.Agent != "claude" || settings.RoleAgents["witness"] != "gemini" {
+		t.Errorf("settings = %+v, want values from include", settings)
+	}
+
+	settings.Agent = "codex"
+	if err := SaveRigSettings(path, settings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written map[string]interface{}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if written["agent"] != "codex" {
+		t.Errorf("written agent = %v, want codex", written["agent"])
+	}
+	if _, ok := written["role_agents"]; ok {
+		t.Errorf("inherited role_agents should not be copied into the file: %s", data)
+	}
+	if inc, ok := written["include"].([]interface{}); !ok || len(inc) != 1 {
+		t.Errorf("include list not preserved: %s", data)
+	}
+
+	reloaded, err := LoadRigSettings(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if reloaded.Agent != "codex" || reloaded.RoleAgents["witness"] != "gemini" {
+		t.Errorf("reloaded = %+v", reloaded)
+	}
+}
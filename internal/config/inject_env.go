@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrReservedEnv indicates an env entry that would override an agent's
+// identity variables (GT_ROLE, BD_ACTOR, ...).
+var ErrReservedEnv = errors.New("reserved environment variable")
+
+// secretRefPrefix marks an env value as a secret reference.
+const secretRefPrefix = "secret:"
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvKeys are set by AgentEnv and must not be overridden.
+var reservedEnvKeys = map[string]bool{
+	"BD_ACTOR":                true,
+	"GIT_AUTHOR_NAME":         true,
+	"GIT_CEILING_DIRECTORIES": true,
+	"CLAUDE_CONFIG_DIR":       true,
+}
+
+// IsReservedEnv reports whether key is an agent identity variable that
+// injected env may not set.
+func IsReservedEnv(key string) bool {
+	return reservedEnvKeys[key] || strings.HasPrefix(key, "GT_") || strings.HasPrefix(key, "BEADS_")
+}
+
+// ValidateEnv checks injected env names and secret reference syntax.
+// field names the setting in error messages (e.g. "env", "legs.sast.env").
+func ValidateEnv(field string, env map[string]string) error {
+	for k, v := range env {
+		if !envNamePattern.MatchString(k) {
+			return fmt.Errorf("invalid %s name %q", field, k)
+		}
+		if IsReservedEnv(k) {
+			return fmt.Errorf("%w: %s.%s", ErrReservedEnv, field, k)
+		}
+		if strings.HasPrefix(v, secretRefPrefix) {
+			if _, _, err := parseSecretRef(v); err != nil {
+				return fmt.Errorf("%s.%s: %w", field, k, err)
+			}
+		}
+	}
+	return nil
+}
+
+// parseSecretRef splits "secret:<source>:<name>" into source and name.
+func parseSecretRef(ref string) (source, name string, err error) {
+	source, name, ok := strings.Cut(strings.TrimPrefix(ref, secretRefPrefix), ":")
+	if !ok || name == "" || (source != "env" && source != "file") {
+		return "", "", fmt.Errorf("invalid secret reference %q (want secret:env:NAME or secret:file:PATH)", ref)
+	}
+	return source, name, nil
+}
+
+// ResolveSecretRef returns the value an env entry stands for. Plain values
+// are returned unchanged. Secret references keep credentials out of
+// settings files:
+//
+//	secret:env:NAME   the orchestrator's $NAME
+//	secret:file:PATH  contents of PATH (relative to the town root), trimmed
+func ResolveSecretRef(townRoot, value string) (string, error) {
+	if !strings.HasPrefix(value, secretRefPrefix) {
+		return value, nil
+	}
+	source, name, err := parseSecretRef(value)
+	if err != nil {
+		return "", err
+	}
+	if source == "env" {
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret %s: $%s is not set", value, name)
+		}
+		return v, nil
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(townRoot, path)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: secret path is operator-configured
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", value, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ResolveEnv validates env and resolves its secret references.
+func ResolveEnv(townRoot, field string, env map[string]string) (map[string]string, error) {
+	if len(env) == 0 {
+		return nil, nil
+	}
+	if err := ValidateEnv(field, env); err != nil {
+		return nil, err
+	}
+	resolved := make(map[string]string, len(env))
+	for k, v := range env {
+		value, err := ResolveSecretRef(townRoot, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", field, k, err)
+		}
+		resolved[k] = value
+	}
+	return resolved, nil
+}
+
+// SettingsEnv returns the env injected into agents of a rig: the town's
+// env overlaid with the rig's, secret references resolved. rigPath may be
+// empty for town-level agents.
+func SettingsEnv(townRoot, rigPath string) (map[string]string, error) {
+	var town, rig map[string]string
+	if townRoot != "" {
+		if ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot)); err == nil {
+			town = ts.Env
+		}
+	}
+	if rigPath != "" {
+		if rs, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil {
+			rig = rs.Env
+		}
+	}
+	if len(town) == 0 && len(rig) == 0 {
+		return nil, nil
+	}
+	return ResolveEnv(townRoot, "env", MergeEnv(town, rig))
+}
+
+// addSettingsEnv adds the town/rig settings env to env for keys it does not
+// already set. Resolution failures are reported but do not block startup.
+func addSettingsEnv(env map[string]string, townRoot, rigPath string) {
+	settingsEnv, err := SettingsEnv(townRoot, rigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: settings env not injected: %v\n", err)
+		return
+	}
+	for k, v := range settingsEnv {
+		if _, ok := env[k]; !ok {
+			env[k] = v
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateEnv(t *testing.T) {
+	if err := ValidateEnv("env", map[string]string{"GOFLAGS": "-mod=mod", "API_TOKEN": "secret:env:TOKEN"}); err != nil {
+		t.Errorf("valid env: unexpected error %v", err)
+	}
+	for _, key := range []string{"GT_ROLE", "BEADS_DIR", "BD_ACTOR"} {
+		if err := ValidateEnv("env", map[string]string{key: "x"}); !errors.Is(err, ErrReservedEnv) {
+			t.Errorf("%s: got %v, want ErrReservedEnv", key, err)
+		}
+	}
+	if err := ValidateEnv("env", map[string]string{"BAD-NAME": "x"}); err == nil {
+		t.Error("expected invalid name to be rejected")
+	}
+	if err := ValidateEnv("env", map[string]string{"TOKEN": "secret:vault:x"}); err == nil {
+		t.Error("expected unknown secret source to be rejected")
+	}
+}
+
+func TestResolveSecretRef(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "secrets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "secrets", "api"), []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GT_TEST_SECRET", "env-token")
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"secret:env:GT_TEST_SECRET", "env-token"},
+		{"secret:file:secrets/api", "file-token"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveSecretRef(townRoot, tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveSecretRef(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := ResolveSecretRef(townRoot, "secret:env:GT_TEST_UNSET_SECRET"); err == nil {
+		t.Error("expected unset env secret to fail")
+	}
+}
+
+func TestSettingsEnv(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	t.Setenv("GT_TEST_SECRET", "s3cret")
+
+	town := NewTownSettings()
+	town.Env = map[string]string{"API_URL": "https://town.example", "GOFLAGS": "-mod=mod"}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), town); err != nil {
+		t.Fatal(err)
+	}
+	rig := NewRigSettings()
+	rig.Env = map[string]string{"API_URL": "https://rig.example", "API_TOKEN": "secret:env:GT_TEST_SECRET"}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rig); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := SettingsEnv(townRoot, rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["API_URL"] != "https://rig.example" || env["GOFLAGS"] != "-mod=mod" || env["API_TOKEN"] != "s3cret" {
+		t.Errorf("SettingsEnv = %v", env)
+	}
+
+	cmd := BuildPolecatStartupCommandWithEnv("gastown", "toast", rigPath, "", map[string]string{"GOFLAGS": "-race"})
+	for _, want := range []string{"API_URL=https://rig.example", "API_TOKEN=s3cret", "GOFLAGS=-race", "GT_POLECAT=toast"} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("startup command missing %s: %s", want, cmd)
+		}
+	}
+}
+
+func TestRigSettingsEnvValidation(t *testing.T) {
+	settings := NewRigSettings()
+	settings.Env = map[string]string{"GT_RIG": "other"}
+	if err := SaveRigSettings(RigSettingsPath(t.TempDir()), settings); !errors.Is(err, ErrReservedEnv) {
+		t.Errorf("got %v, want ErrReservedEnv", err)
+	}
+}
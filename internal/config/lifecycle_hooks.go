@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultLifecycleHookTimeout bounds a lifecycle hook without a timeout.
+const DefaultLifecycleHookTimeout = 30 * time.Second
+
+// Lifecycle hook events, named by their settings keys.
+const (
+	HookConvoyComplete         = "on_convoy_complete"
+	HookDoctorWarning          = "on_doctor_warning"
+	HookFormulaOverrideChanged = "on_formula_override_changed"
+)
+
+// shellLineChars in a hook's program name mean it was written as a shell
+// command line rather than an argument vector.
+const shellLineChars = " \t\n;|&<>$`"
+
+// LifecycleHookEvents lists every lifecycle hook event.
+var LifecycleHookEvents = []string{HookConvoyComplete, HookDoctorWarning, HookFormulaOverrideChanged}
+
+// LifecycleHooksConfig maps town events to user commands. Each command is
+// run with a JSON description of the event on stdin, so hooks can be
+// written in any language without gt knowing about them.
+type LifecycleHooksConfig struct {
+	// OnConvoyComplete runs when a convoy lands (all tracked issues closed).
+	OnConvoyComplete []LifecycleHook `json:"on_convoy_complete,omitempty"`
+
+	// OnDoctorWarning runs after gt doctor finds warnings or errors.
+	OnDoctorWarning []LifecycleHook `json:"on_doctor_warning,omitempty"`
+
+	// OnFormulaOverrideChanged runs when gt changes a formula file in the
+	// search path: gt formula modify, reset, edit, promote, or demote.
+	OnFormulaOverrideChanged []LifecycleHook `json:"on_formula_override_changed,omitempty"`
+}
+
+// LifecycleHook is a command run on a town event.
+type LifecycleHook struct {
+	// Command is the program and its arguments. It is run directly, not
+	// through a shell; use ["sh", "-c", "..."] for shell syntax.
+	Command []string `json:"command"`
+
+	// Timeout is how long the command may run, e.g. "10s" (default 30s).
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// For returns the hooks configured for event, one of LifecycleHookEvents.
+func (c *LifecycleHooksConfig) For(event string) []LifecycleHook {
+	if c == nil {
+		return nil
+	}
+	switch event {
+	case HookConvoyComplete:
+		return c.OnConvoyComplete
+	case HookDoctorWarning:
+		return c.OnDoctorWarning
+	case HookFormulaOverrideChanged:
+		return c.OnFormulaOverrideChanged
+	}
+	return nil
+}
+
+// TimeoutDuration returns the hook's timeout, or the default when unset or
+// invalid.
+func (h LifecycleHook) TimeoutDuration() time.Duration {
+	if d, err := time.ParseDuration(h.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultLifecycleHookTimeout
+}
+
+func validateLifecycleHooksConfig(c *LifecycleHooksConfig) error {
+	for _, event := range LifecycleHookEvents {
+		for i, h := range c.For(event) {
+			if len(h.Command) == 0 || h.Command[0] == "" {
+				return fmt.Errorf("lifecycle_hooks.%s[%d].command: must name a program", event, i)
+			}
+			// Hooks run as an argument vector; a whole command line in one
+			// element would be handed to exec as a program name.
+			if strings.ContainsAny(h.Command[0], shellLineChars) {
+				return fmt.Errorf("lifecycle_hooks.%s[%d].command: %q is a command line, not a program; list the program and each argument separately, or use [\"sh\", \"-c\", \"...\"]", event, i, h.Command[0])
+			}
+			if h.Timeout != "" {
+				if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+					return fmt.Errorf("lifecycle_hooks.%s[%d].timeout: invalid duration %q", event, i, h.Timeout)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestValidateLifecycleHooksConfigRequiresArgv(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		wantErr bool
+	}{
+		{"argv", []string{"/usr/local/bin/notify", "--channel", "reviews"}, false},
+		{"explicit shell", []string{"sh", "-c", "logger -t gt < /dev/stdin"}, false},
+		{"command line", []string{"notify --channel reviews"}, true},
+		{"pipeline", []string{"jq .data|logger"}, true},
+		{"empty", []string{""}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &LifecycleHooksConfig{OnConvoyComplete: []LifecycleHook{{Command: tt.command}}}
+			err := validateLifecycleHooksConfig(c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateLifecycleHooksConfig(%q) error = %v, wantErr %v", tt.command, err, tt.wantErr)
+			}
+		})
+	}
+}
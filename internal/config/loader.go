@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 var (
@@ -65,7 +67,7 @@ func SaveTownConfig(path string, config *TownConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := fsx.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -109,7 +111,7 @@ func SaveRigsConfig(path string, config *RigsConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := fsx.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -138,6 +140,14 @@ func validateRigsConfig(c *RigsConfig) error {
 	if c.Rigs == nil {
 		c.Rigs = make(map[string]RigEntry)
 	}
+	for name, entry := range c.Rigs {
+		if entry.Repository == nil {
+			continue
+		}
+		if err := validateRepoMapping(entry.Repository); err != nil {
+			return fmt.Errorf("rig %s: %w", name, err)
+		}
+	}
 	return nil
 }
 
@@ -178,7 +188,7 @@ func SaveRigConfig(path string, config *RigConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -196,9 +206,19 @@ func validateRigConfig(c *RigConfig) error {
 	if c.Name == "" {
 		return fmt.Errorf("%w: name", ErrMissingField)
 	}
+	if c.Remote != "" {
+		if u, err := url.Parse(c.Remote); err != nil || u.Scheme != "ssh" || u.Host == "" || len(u.Path) < 2 {
+			return fmt.Errorf("invalid remote %q: want ssh://[user@]host[:port]/path", c.Remote)
+		}
+	}
 	return nil
 }
 
+// ValidateRigSettings checks rig settings without loading or saving them.
+func ValidateRigSettings(c *RigSettings) error {
+	return validateRigSettings(c)
+}
+
 // validateRigSettings validates a RigSettings.
 func validateRigSettings(c *RigSettings) error {
 	if c.Type != "rig-settings" && c.Type != "" {
@@ -212,6 +232,62 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Refinery != nil {
+		if err := validateRefineryPipelineConfig(c.Refinery); err != nil {
+			return err
+		}
+	}
+	if c.Quota != nil {
+		if err := validateQuotaConfig(c.Quota); err != nil {
+			return err
+		}
+	}
+	if c.Approval != nil {
+		if err := validateApprovalConfig(c.Approval); err != nil {
+			return err
+		}
+	}
+	if c.GitHub != nil {
+		if err := validateGitHubConfig(c.GitHub); err != nil {
+			return err
+		}
+	}
+	if c.SCM != nil {
+		if err := validateSCMConfig(c.SCM); err != nil {
+			return err
+		}
+	}
+	if c.WorktreePool != nil {
+		if err := validateWorktreePoolConfig(c.WorktreePool); err != nil {
+			return err
+		}
+	}
+	if c.Clone != nil {
+		if err := validateCloneConfig(c.Clone); err != nil {
+			return err
+		}
+	}
+	if err := ValidateEnv("env", c.Env); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateRefineryPipelineConfig validates a RefineryPipelineConfig.
+func validateRefineryPipelineConfig(c *RefineryPipelineConfig) error {
+	for i, step := range c.Steps {
+		if step.Name == "" {
+			return fmt.Errorf("%w: refinery.steps[%d].name", ErrMissingField, i)
+		}
+		if step.Run == "" {
+			return fmt.Errorf("%w: refinery.steps[%d].run", ErrMissingField, i)
+		}
+		if step.Timeout != "" {
+			if _, err := time.ParseDuration(step.Timeout); err != nil {
+				return fmt.Errorf("invalid refinery step %q timeout: %w", step.Name, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -266,7 +342,7 @@ func NewRigSettings() *RigSettings {
 
 // LoadRigSettings loads and validates a rig settings file.
 func LoadRigSettings(path string) (*RigSettings, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	data, err := readLayeredJSON(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
@@ -296,12 +372,12 @@ func SaveRigSettings(path string, settings *RigSettings) error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(settings, "", "  ")
+	data, err := marshalLayered(path, settings, settings.Include)
 	if err != nil {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
@@ -345,7 +421,7 @@ func SaveMayorConfig(path string, config *MayorConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -413,7 +489,7 @@ func SaveDaemonPatrolConfig(path string, config *DaemonPatrolConfig) error {
 		return fmt.Errorf("encoding daemon patrol config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
 		return fmt.Errorf("writing daemon patrol config: %w", err)
 	}
 
@@ -479,7 +555,7 @@ func SaveAccountsConfig(path string, config *AccountsConfig) error {
 		return fmt.Errorf("encoding accounts config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: accounts config doesn't contain sensitive credentials
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: accounts config doesn't contain sensitive credentials
 		return fmt.Errorf("writing accounts config: %w", err)
 	}
 
@@ -626,7 +702,7 @@ func SaveMessagingConfig(path string, config *MessagingConfig) error {
 		return fmt.Errorf("encoding messaging config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: messaging config doesn't contain secrets
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: messaging config doesn't contain secrets
 		return fmt.Errorf("writing messaging config: %w", err)
 	}
 
@@ -742,7 +818,7 @@ func RigSettingsPath(rigPath string) string {
 
 // LoadOrCreateTownSettings loads town settings or creates defaults if missing.
 func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	data, err := readLayeredJSON(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return NewTownSettings(), nil
@@ -765,17 +841,50 @@ func SaveTownSettings(path string, settings *TownSettings) error {
 	if settings.Version > CurrentTownSettingsVersion {
 		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, settings.Version, CurrentTownSettingsVersion)
 	}
+	if err := ValidateEnv("env", settings.Env); err != nil {
+		return err
+	}
+	if settings.Logging != nil {
+		if err := validateLoggingConfig(settings.Logging); err != nil {
+			return err
+		}
+	}
+	if settings.GC != nil {
+		if err := validateGCConfig(settings.GC); err != nil {
+			return err
+		}
+	}
+	if settings.DiskUsage != nil {
+		if err := validateDiskUsageConfig(settings.DiskUsage); err != nil {
+			return err
+		}
+	}
+	if settings.Maintenance != nil {
+		if err := validateMaintenanceConfig(settings.Maintenance); err != nil {
+			return err
+		}
+	}
+	if settings.Concurrency != nil {
+		if err := validateConcurrencyConfig(settings.Concurrency); err != nil {
+			return err
+		}
+	}
+	if settings.LifecycleHooks != nil {
+		if err := validateLifecycleHooksConfig(settings.LifecycleHooks); err != nil {
+			return err
+		}
+	}
 
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(settings, "", "  ")
+	data, err := marshalLayered(path, settings, settings.Include)
 	if err != nil {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
@@ -1365,6 +1474,8 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	for k, v := range envVars {
 		resolvedEnv[k] = v
 	}
+	// Inject env from town/rig settings; explicit envVars take precedence
+	addSettingsEnv(resolvedEnv, townRoot, rigPath)
 	// Add GT_ROOT so agents can find town-level resources (formulas, etc.)
 	if townRoot != "" {
 		resolvedEnv["GT_ROOT"] = townRoot
@@ -1475,6 +1586,8 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	for k, v := range envVars {
 		resolvedEnv[k] = v
 	}
+	// Inject env from town/rig settings; explicit envVars take precedence
+	addSettingsEnv(resolvedEnv, townRoot, rigPath)
 	// Add GT_ROOT so agents can find town-level resources (formulas, etc.)
 	if townRoot != "" {
 		resolvedEnv["GT_ROOT"] = townRoot
@@ -1542,32 +1655,33 @@ func BuildAgentStartupCommandWithAgentOverride(role, rig, townRoot, rigPath, pro
 // BuildPolecatStartupCommand builds the startup command for a polecat.
 // Sets GT_ROLE, GT_RIG, GT_POLECAT, BD_ACTOR, GIT_AUTHOR_NAME, and GT_ROOT.
 func BuildPolecatStartupCommand(rigName, polecatName, rigPath, prompt string) string {
-	var townRoot string
-	if rigPath != "" {
-		townRoot = filepath.Dir(rigPath)
-	}
-	envVars := AgentEnv(AgentEnvConfig{
-		Role:      "polecat",
-		Rig:       rigName,
-		AgentName: polecatName,
-		TownRoot:  townRoot,
-	})
-	return BuildStartupCommand(envVars, rigPath, prompt)
+	return BuildPolecatStartupCommandWithEnv(rigName, polecatName, rigPath, prompt, nil)
 }
 
-// BuildPolecatStartupCommandWithAgentOverride is like BuildPolecatStartupCommand, but uses agentOverride if non-empty.
-func BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, rigPath, prompt, agentOverride string) (string, error) {
+// BuildPolecatStartupCommandWithEnv is like BuildPolecatStartupCommand, but
+// also injects extraEnv (e.g., per-formula overrides), which takes precedence
+// over the town/rig settings env.
+func BuildPolecatStartupCommandWithEnv(rigName, polecatName, rigPath, prompt string, extraEnv map[string]string) string {
+	return BuildStartupCommand(polecatEnv(rigName, polecatName, rigPath, extraEnv), rigPath, prompt)
+}
+
+// BuildPolecatStartupCommandWithAgentOverride is like BuildPolecatStartupCommandWithEnv, but uses agentOverride if non-empty.
+func BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, rigPath, prompt, agentOverride string, extraEnv map[string]string) (string, error) {
+	return BuildStartupCommandWithAgentOverride(polecatEnv(rigName, polecatName, rigPath, extraEnv), rigPath, prompt, agentOverride)
+}
+
+// polecatEnv returns a polecat's identity env over extraEnv.
+func polecatEnv(rigName, polecatName, rigPath string, extraEnv map[string]string) map[string]string {
 	var townRoot string
 	if rigPath != "" {
 		townRoot = filepath.Dir(rigPath)
 	}
-	envVars := AgentEnv(AgentEnvConfig{
+	return MergeEnv(extraEnv, AgentEnv(AgentEnvConfig{
 		Role:      "polecat",
 		Rig:       rigName,
 		AgentName: polecatName,
 		TownRoot:  townRoot,
-	})
-	return BuildStartupCommandWithAgentOverride(envVars, rigPath, prompt, agentOverride)
+	}))
 }
 
 // BuildCrewStartupCommand builds the startup command for a crew member.
@@ -1707,7 +1821,7 @@ func SaveEscalationConfig(path string, config *EscalationConfig) error {
 		return fmt.Errorf("encoding escalation config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
 		return fmt.Errorf("writing escalation config: %w", err)
 	}
 
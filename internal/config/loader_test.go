@@ -278,6 +278,33 @@ func TestRigConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ssh remote",
+			config: &RigConfig{
+				Type:   "rig",
+				Name:   "test",
+				Remote: "ssh://me@build01:2222/srv/repo",
+			},
+			wantErr: false,
+		},
+		{
+			name: "remote without path",
+			config: &RigConfig{
+				Type:   "rig",
+				Name:   "test",
+				Remote: "ssh://build01",
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-ssh remote",
+			config: &RigConfig{
+				Type:   "rig",
+				Name:   "test",
+				Remote: "https://build01/srv/repo",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1125,7 +1152,7 @@ func TestBuildPolecatStartupCommandWithAgentOverride(t *testing.T) {
 		t.Fatalf("SaveRigSettings: %v", err)
 	}
 
-	cmd, err := BuildPolecatStartupCommandWithAgentOverride("testrig", "toast", rigPath, "", "gemini")
+	cmd, err := BuildPolecatStartupCommandWithAgentOverride("testrig", "toast", rigPath, "", "gemini", nil)
 	if err != nil {
 		t.Fatalf("BuildPolecatStartupCommandWithAgentOverride: %v", err)
 	}
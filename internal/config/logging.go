@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Log sink types.
+const (
+	LogSinkSyslog = "syslog"
+	LogSinkLoki   = "loki"
+	LogSinkFile   = "file"
+)
+
+// LoggingConfig ships town log events to external log infrastructure in
+// addition to logs/town.log.
+type LoggingConfig struct {
+	Sinks []LogSinkConfig `json:"sinks,omitempty"`
+}
+
+// LogSinkConfig configures one log destination.
+type LogSinkConfig struct {
+	// Type is "syslog", "loki", or "file".
+	Type string `json:"type"`
+
+	// Syslog: Network and Address of the syslog server ("udp",
+	// "host:514"); both empty uses the local syslog daemon. Tag defaults
+	// to "gastown".
+	Network string `json:"network,omitempty"`
+	Address string `json:"address,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+
+	// Loki: push API base URL (e.g. "http://loki:3100") and extra stream
+	// labels. Events are labeled with job="gastown" and their event type.
+	URL    string            `json:"url,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// File: JSON lines file, relative to the town root. Rotated when it
+	// exceeds MaxSizeMB (default 10), keeping MaxFiles old files (default 5).
+	Path      string `json:"path,omitempty"`
+	MaxSizeMB int    `json:"max_size_mb,omitempty"`
+	MaxFiles  int    `json:"max_files,omitempty"`
+}
+
+// validateLoggingConfig validates a LoggingConfig.
+func validateLoggingConfig(c *LoggingConfig) error {
+	for i, s := range c.Sinks {
+		field := fmt.Sprintf("logging.sinks[%d]", i)
+		switch s.Type {
+		case LogSinkSyslog:
+			if (s.Network == "") != (s.Address == "") {
+				return fmt.Errorf("%s: syslog network and address must be set together", field)
+			}
+		case LogSinkLoki:
+			if s.URL == "" {
+				return fmt.Errorf("%w: %s.url", ErrMissingField, field)
+			}
+			if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				return fmt.Errorf("%s: invalid loki url %q", field, s.URL)
+			}
+		case LogSinkFile:
+			if s.Path == "" {
+				return fmt.Errorf("%w: %s.path", ErrMissingField, field)
+			}
+			if s.MaxSizeMB < 0 || s.MaxFiles < 0 {
+				return fmt.Errorf("%s: max_size_mb and max_files must be non-negative", field)
+			}
+		default:
+			return fmt.Errorf("%s: invalid type %q (must be syslog, loki, or file)", field, s.Type)
+		}
+	}
+	return nil
+}
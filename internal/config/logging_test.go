@@ -0,0 +1,34 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateLoggingConfig(t *testing.T) {
+	valid := &LoggingConfig{Sinks: []LogSinkConfig{
+		{Type: LogSinkSyslog},
+		{Type: LogSinkSyslog, Network: "udp", Address: "logs:514"},
+		{Type: LogSinkLoki, URL: "http://loki:3100"},
+		{Type: LogSinkFile, Path: "logs/events.jsonl", MaxSizeMB: 20},
+	}}
+	if err := validateLoggingConfig(valid); err != nil {
+		t.Errorf("valid config: unexpected error %v", err)
+	}
+
+	for _, sink := range []LogSinkConfig{
+		{Type: "kafka"},
+		{Type: LogSinkSyslog, Network: "udp"},
+		{Type: LogSinkLoki, URL: "loki:3100"},
+		{Type: LogSinkFile, Path: "x", MaxFiles: -1},
+	} {
+		if err := validateLoggingConfig(&LoggingConfig{Sinks: []LogSinkConfig{sink}}); err == nil {
+			t.Errorf("%+v: expected error", sink)
+		}
+	}
+
+	err := validateLoggingConfig(&LoggingConfig{Sinks: []LogSinkConfig{{Type: LogSinkFile}}})
+	if !errors.Is(err, ErrMissingField) {
+		t.Errorf("file sink without path: got %v, want ErrMissingField", err)
+	}
+}
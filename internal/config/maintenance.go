@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maintenanceLookahead bounds the search for the end of a maintenance
+// window. Windows open longer than this have no known end.
+const maintenanceLookahead = 8 * 24 * time.Hour
+
+// MaintenanceConfig lists the town's maintenance windows. While one is
+// open, the daemon skips its patrols, and formula and plugin runs are
+// queued until the window closes.
+type MaintenanceConfig struct {
+	Windows []MaintenanceWindow `json:"windows"`
+}
+
+// MaintenanceWindow is a recurring maintenance window.
+type MaintenanceWindow struct {
+	// Name identifies the window in status output and logs.
+	Name string `json:"name,omitempty"`
+
+	// Cron is a five-field cron expression (minute hour day-of-month month
+	// day-of-week). The window is open during every minute it matches, so
+	// ranges describe its extent: "* 2-4 * * SAT" is Saturdays from 02:00
+	// until 05:00.
+	Cron string `json:"cron"`
+
+	// Timezone is the IANA zone the expression is evaluated in (default:
+	// the local zone).
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Label returns the window's name, or its cron expression without one.
+func (w *MaintenanceWindow) Label() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return w.Cron
+}
+
+// ActiveWindow returns the first window open at now and when it closes.
+// The end is zero if the window stays open beyond the lookahead. Windows
+// with invalid expressions or zones are ignored; SaveTownSettings rejects
+// them.
+func (c *MaintenanceConfig) ActiveWindow(now time.Time) (*MaintenanceWindow, time.Time) {
+	if c == nil {
+		return nil, time.Time{}
+	}
+	for i := range c.Windows {
+		w := &c.Windows[i]
+		spec, err := parseCron(w.Cron)
+		if err != nil {
+			continue
+		}
+		loc, err := maintenanceLocation(w.Timezone)
+		if err != nil {
+			continue
+		}
+		t := now.In(loc).Truncate(time.Minute)
+		if !spec.matches(t) {
+			continue
+		}
+		for end := t.Add(time.Minute); end.Sub(t) <= maintenanceLookahead; end = end.Add(time.Minute) {
+			if !spec.matches(end) {
+				return w, end
+			}
+		}
+		return w, time.Time{}
+	}
+	return nil, time.Time{}
+}
+
+// maintenanceLocation resolves a window's timezone.
+func maintenanceLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// validateMaintenanceConfig validates a MaintenanceConfig.
+func validateMaintenanceConfig(c *MaintenanceConfig) error {
+	for i, w := range c.Windows {
+		if _, err := parseCron(w.Cron); err != nil {
+			return fmt.Errorf("maintenance.windows[%d].cron: %w", i, err)
+		}
+		if _, err := maintenanceLocation(w.Timezone); err != nil {
+			return fmt.Errorf("maintenance.windows[%d].timezone: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// cronSpec is a parsed five-field cron expression: the set of values each
+// field matches.
+type cronSpec struct {
+	minute, hour, dom, month, dow map[int]bool
+
+	// domAny and dowAny record unrestricted day fields. As in cron, a
+	// minute matches when either day field does if both are restricted.
+	domAny, dowAny bool
+}
+
+// cronField describes the range and names of a cron field.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names[i] stands for min+i
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// parseCron parses a five-field cron expression. Fields accept *, values,
+// ranges (a-b), steps (*/n, a-b/n), comma-separated lists, and month and
+// weekday names. Day of week 7 is Sunday, as is 0.
+func parseCron(expr string) (*cronSpec, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("%q: want 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(parts))
+	}
+	sets := make([]map[int]bool, len(parts))
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &cronSpec{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: parts[2] == "*", dowAny: parts[4] == "*",
+	}, nil
+}
+
+// parseCronField parses one comma-separated cron field.
+func parseCronField(field string, f cronField) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%s: invalid step in %q", f.name, item)
+			}
+			rng, step = item[:i], n
+		}
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			var err error
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			if lo, err = cronValue(loStr, f); err != nil {
+				return nil, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = cronValue(hiStr, f); err != nil {
+					return nil, err
+				}
+				if hi < lo {
+					return nil, fmt.Errorf("%s: range %q runs backwards", f.name, rng)
+				}
+			} else if step > 1 {
+				hi = f.max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// cronValue parses a single value of a cron field, by number or name.
+func cronValue(s string, f cronField) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s: %q is not in %d-%d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// matches reports whether the minute t falls in the expression.
+func (s *cronSpec) matches(t time.Time) bool {
+	if !s.minute[t.Minute()] || !s.hour[t.Hour()] || !s.month[int(t.Month())] {
+		return false
+	}
+	dom, dow := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	if !s.domAny && !s.dowAny {
+		return dom || dow
+	}
+	return dom && dow
+}
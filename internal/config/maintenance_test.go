@@ -0,0 +1,118 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"*/15 2-4 * * SAT",
+		"0 22 * * mon-fri",
+		"30 1 1,15 JAN-MAR 7",
+		"0-30/10 * * * *",
+	}
+	for _, expr := range valid {
+		if _, err := parseCron(expr); err != nil {
+			t.Errorf("parseCron(%q) error: %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"* 5-2 * * *",
+		"*/0 * * * *",
+		"* * * * FUNDAY",
+	}
+	for _, expr := range invalid {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) accepted an invalid expression", expr)
+		}
+	}
+}
+
+func TestCronSpecMatches(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		tm, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	tests := []struct {
+		expr string
+		at   string
+		want bool
+	}{
+		{"* 2-4 * * SAT", "2026-10-17 02:00", true}, // a Saturday
+		{"* 2-4 * * SAT", "2026-10-17 04:59", true},
+		{"* 2-4 * * SAT", "2026-10-17 05:00", false},
+		{"* 2-4 * * SAT", "2026-10-18 03:00", false},
+		{"* * * * 7", "2026-10-18 03:00", true}, // 7 is Sunday
+		{"*/15 * * * *", "2026-10-17 10:45", true},
+		{"*/15 * * * *", "2026-10-17 10:46", false},
+		// Both day fields restricted: either may match.
+		{"* * 1 * MON", "2026-10-19 12:00", true},
+		{"* * 1 * MON", "2026-11-01 12:00", true},
+		{"* * 1 * MON", "2026-10-20 12:00", false},
+	}
+	for _, tt := range tests {
+		spec, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		if got := spec.matches(at(tt.at)); got != tt.want {
+			t.Errorf("%q matches %s = %v, want %v", tt.expr, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestMaintenanceConfigActiveWindow(t *testing.T) {
+	c := &MaintenanceConfig{Windows: []MaintenanceWindow{
+		{Name: "broken", Cron: "not cron"},
+		{Name: "weekly", Cron: "* 2-4 * * SAT", Timezone: "UTC"},
+	}}
+
+	now := time.Date(2026, 10, 17, 3, 17, 42, 0, time.UTC)
+	w, end := c.ActiveWindow(now)
+	if w == nil || w.Name != "weekly" {
+		t.Fatalf("ActiveWindow(%v) = %v, want weekly", now, w)
+	}
+	if want := time.Date(2026, 10, 17, 5, 0, 0, 0, time.UTC); !end.Equal(want) {
+		t.Errorf("end = %v, want %v", end, want)
+	}
+
+	if w, _ := c.ActiveWindow(now.Add(2 * time.Hour)); w != nil {
+		t.Errorf("ActiveWindow after the window = %v, want none", w.Name)
+	}
+
+	var unset *MaintenanceConfig
+	if w, _ := unset.ActiveWindow(now); w != nil {
+		t.Error("nil config reported an active window")
+	}
+
+	always := &MaintenanceConfig{Windows: []MaintenanceWindow{{Cron: "* * * * *"}}}
+	if w, end := always.ActiveWindow(now); w == nil || !end.IsZero() {
+		t.Errorf("always-open window = %v, end %v; want open with no end", w, end)
+	}
+}
+
+func TestValidateMaintenanceConfig(t *testing.T) {
+	if err := validateMaintenanceConfig(&MaintenanceConfig{Windows: []MaintenanceWindow{{Cron: "0 2 * * *", Timezone: "Europe/Berlin"}}}); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+	if err := validateMaintenanceConfig(&MaintenanceConfig{Windows: []MaintenanceWindow{{Cron: "0 2 * *"}}}); err == nil {
+		t.Error("short cron accepted")
+	}
+	if err := validateMaintenanceConfig(&MaintenanceConfig{Windows: []MaintenanceWindow{{Cron: "0 2 * * *", Timezone: "Mars/Olympus"}}}); err == nil {
+		t.Error("unknown timezone accepted")
+	}
+}
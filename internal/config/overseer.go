@@ -7,6 +7,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/gh"
 )
 
 // OverseerConfig represents the human operator's identity (mayor/overseer.json).
@@ -65,7 +68,7 @@ func SaveOverseerConfig(path string, config *OverseerConfig) error {
 		return fmt.Errorf("encoding overseer config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: overseer config doesn't contain secrets
+	if err := fsx.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: overseer config doesn't contain secrets
 		return fmt.Errorf("writing overseer config: %w", err)
 	}
 
@@ -159,8 +162,7 @@ func detectFromGitConfig(dir string) *OverseerConfig {
 
 // detectFromGitHub attempts to get identity from GitHub CLI.
 func detectFromGitHub() *OverseerConfig {
-	cmd := exec.Command("gh", "api", "user", "--jq", ".login + \"|\" + .name + \"|\" + .email")
-	out, err := cmd.Output()
+	out, err := gh.Run("api", "user", "--jq", ".login + \"|\" + .name + \"|\" + .email")
 	if err != nil {
 		return nil
 	}
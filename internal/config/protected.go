@@ -0,0 +1,66 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// ProtectedConfig declares destructive operations that require a typed
+// confirmation phrase (or --force) before they run.
+type ProtectedConfig struct {
+	// Operations lists protected operations. An entry is an operation name
+	// ("down.nuke") matching every target, or "operation:pattern"
+	// ("formula.reset:code-*") matching targets by glob.
+	Operations []string `json:"operations"`
+
+	// AllowRoles lists agent roles (from GT_ROLE, e.g. "mayor") allowed to
+	// run protected operations with --force. Agents not listed are refused.
+	AllowRoles []string `json:"allow_roles,omitempty"`
+}
+
+// DefaultProtectedOperations are protected when a town does not configure
+// its own list.
+var DefaultProtectedOperations = []string{
+	"down.nuke",     // gt down --nuke: kills the whole tmux server
+	"doctor.fix",    // gt doctor --fix: fixes that delete directories
+	"formula.reset", // gt formula reset: discards local formula edits
+}
+
+// ProtectedOperations returns the town's protected operation list.
+func (s *TownSettings) ProtectedOperations() []string {
+	if s == nil || s.Protected == nil {
+		return DefaultProtectedOperations
+	}
+	return s.Protected.Operations
+}
+
+// IsProtected reports whether operation op on target requires confirmation.
+func (s *TownSettings) IsProtected(op, target string) bool {
+	for _, entry := range s.ProtectedOperations() {
+		name, pattern, hasPattern := strings.Cut(entry, ":")
+		if name != op {
+			continue
+		}
+		if !hasPattern {
+			return true
+		}
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAllowedProtected reports whether an agent role may run protected
+// operations (with --force).
+func (s *TownSettings) RoleAllowedProtected(role string) bool {
+	if s == nil || s.Protected == nil {
+		return false
+	}
+	for _, allowed := range s.Protected.AllowRoles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
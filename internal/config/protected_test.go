@@ -0,0 +1,41 @@
+package config
+
+import "testing"
+
+func TestIsProtected(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *TownSettings
+		op       string
+		target   string
+		want     bool
+	}{
+		{"nil settings use defaults", nil, "down.nuke", "", true},
+		{"default protects formula reset", &TownSettings{}, "formula.reset", "shiny", true},
+		{"unlisted op", &TownSettings{}, "rig.remove", "gastown", false},
+		{"empty list protects nothing", &TownSettings{Protected: &ProtectedConfig{}}, "down.nuke", "", false},
+		{"pattern match", &TownSettings{Protected: &ProtectedConfig{Operations: []string{"formula.reset:mol-*"}}}, "formula.reset", "mol-polecat-work", true},
+		{"pattern miss", &TownSettings{Protected: &ProtectedConfig{Operations: []string{"formula.reset:mol-*"}}}, "formula.reset", "shiny", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.IsProtected(tt.op, tt.target); got != tt.want {
+				t.Errorf("IsProtected(%q, %q) = %v, want %v", tt.op, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoleAllowedProtected(t *testing.T) {
+	s := &TownSettings{Protected: &ProtectedConfig{AllowRoles: []string{"mayor"}}}
+	if !s.RoleAllowedProtected("mayor") {
+		t.Error("mayor should be allowed")
+	}
+	if s.RoleAllowedProtected("polecat") {
+		t.Error("polecat should not be allowed")
+	}
+	var none *TownSettings
+	if none.RoleAllowedProtected("mayor") {
+		t.Error("no roles are allowed by default")
+	}
+}
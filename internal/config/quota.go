@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrQuotaExceeded indicates a rig quota would be exceeded by new work.
+var ErrQuotaExceeded = errors.New("rig quota exceeded")
+
+// QuotaConfig limits formula dispatch for a rig. A zero value for any limit
+// means that limit is not enforced.
+type QuotaConfig struct {
+	// RunsPerHour caps formula runs started in any rolling hour.
+	RunsPerHour int `json:"runs_per_hour,omitempty"`
+
+	// MaxConcurrentConvoys caps formula convoys open at the same time.
+	MaxConcurrentConvoys int `json:"max_concurrent_convoys,omitempty"`
+
+	// DailyCostUSD caps recorded session cost for the rig per calendar day.
+	DailyCostUSD float64 `json:"daily_cost_usd,omitempty"`
+}
+
+// QuotaUsage is a rig's current consumption, measured against a QuotaConfig.
+type QuotaUsage struct {
+	RunsLastHour    int     `json:"runs_last_hour"`
+	OpenConvoys     int     `json:"open_convoys"`
+	CostTodayUSD    float64 `json:"cost_today_usd"`
+	CostUnavailable bool    `json:"cost_unavailable,omitempty"`
+}
+
+// Check returns an ErrQuotaExceeded error naming every limit that starting
+// one more run would break, or nil if the run may proceed.
+func (q *QuotaConfig) Check(usage QuotaUsage) error {
+	violations := q.Violations(usage)
+	if len(violations) == 0 {
+		return nil
+	}
+	msg := violations[0]
+	for _, v := range violations[1:] {
+		msg += "; " + v
+	}
+	return fmt.Errorf("%w: %s", ErrQuotaExceeded, msg)
+}
+
+// Violations describes each limit that starting one more run would break.
+func (q *QuotaConfig) Violations(usage QuotaUsage) []string {
+	if q == nil {
+		return nil
+	}
+	var out []string
+	if q.RunsPerHour > 0 && usage.RunsLastHour >= q.RunsPerHour {
+		out = append(out, fmt.Sprintf("%d/%d runs in the last hour", usage.RunsLastHour, q.RunsPerHour))
+	}
+	if q.MaxConcurrentConvoys > 0 && usage.OpenConvoys >= q.MaxConcurrentConvoys {
+		out = append(out, fmt.Sprintf("%d/%d convoys open", usage.OpenConvoys, q.MaxConcurrentConvoys))
+	}
+	if q.DailyCostUSD > 0 && usage.CostTodayUSD >= q.DailyCostUSD {
+		out = append(out, fmt.Sprintf("$%.2f/$%.2f spent today", usage.CostTodayUSD, q.DailyCostUSD))
+	}
+	return out
+}
+
+// validateQuotaConfig validates a QuotaConfig.
+func validateQuotaConfig(c *QuotaConfig) error {
+	if c.RunsPerHour < 0 {
+		return fmt.Errorf("%w: quota.runs_per_hour must be non-negative", ErrMissingField)
+	}
+	if c.MaxConcurrentConvoys < 0 {
+		return fmt.Errorf("%w: quota.max_concurrent_convoys must be non-negative", ErrMissingField)
+	}
+	if c.DailyCostUSD < 0 {
+		return fmt.Errorf("%w: quota.daily_cost_usd must be non-negative", ErrMissingField)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestQuotaCheck(t *testing.T) {
+	q := &QuotaConfig{RunsPerHour: 5, MaxConcurrentConvoys: 2, DailyCostUSD: 10}
+
+	if err := q.Check(QuotaUsage{RunsLastHour: 4, OpenConvoys: 1, CostTodayUSD: 9.5}); err != nil {
+		t.Errorf("under quota: unexpected error %v", err)
+	}
+
+	err := q.Check(QuotaUsage{RunsLastHour: 5, OpenConvoys: 2, CostTodayUSD: 12})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("over quota: got %v, want ErrQuotaExceeded", err)
+	}
+	if got := len(q.Violations(QuotaUsage{RunsLastHour: 5, OpenConvoys: 2, CostTodayUSD: 12})); got != 3 {
+		t.Errorf("Violations = %d, want 3", got)
+	}
+
+	var unlimited *QuotaConfig
+	if err := unlimited.Check(QuotaUsage{RunsLastHour: 1000}); err != nil {
+		t.Errorf("nil quota: unexpected error %v", err)
+	}
+	if err := (&QuotaConfig{}).Check(QuotaUsage{OpenConvoys: 50, CostTodayUSD: 500}); err != nil {
+		t.Errorf("zero limits: unexpected error %v", err)
+	}
+}
+
+func TestRigSettingsQuotaValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings", "config.json")
+	settings := NewRigSettings()
+	settings.Quota = &QuotaConfig{RunsPerHour: -1}
+	if err := SaveRigSettings(path, settings); err == nil {
+		t.Fatal("expected negative runs_per_hour to be rejected")
+	}
+
+	settings.Quota = &QuotaConfig{RunsPerHour: 3, DailyCostUSD: 25}
+	if err := SaveRigSettings(path, settings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+	loaded, err := LoadRigSettings(path)
+	if err != nil {
+		t.Fatalf("LoadRigSettings: %v", err)
+	}
+	if loaded.Quota == nil || loaded.Quota.RunsPerHour != 3 || loaded.Quota.DailyCostUSD != 25 {
+		t.Errorf("quota round-trip = %+v", loaded.Quota)
+	}
+}
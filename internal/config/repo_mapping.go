@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultRepoHost is the host of a RepoMapping without one.
+const DefaultRepoHost = "github.com"
+
+// Hostname returns the mapping's host, lowercased and without a scheme.
+func (m *RepoMapping) Hostname() string {
+	host := m.Host
+	if host == "" {
+		host = DefaultRepoHost
+	}
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	return strings.ToLower(strings.TrimRight(host, "/"))
+}
+
+// Matches reports whether the mapping names repo (owner/name) on host.
+// Both comparisons ignore case, as the hosts do.
+func (m *RepoMapping) Matches(host, repo string) bool {
+	return m.Hostname() == strings.ToLower(host) && strings.EqualFold(m.Name, repo)
+}
+
+// RigsForRepo returns the rigs whose repository mapping names repo
+// (owner/name) on host, sorted.
+func (c *RigsConfig) RigsForRepo(host, repo string) []string {
+	var rigs []string
+	for name, entry := range c.Rigs {
+		if entry.Repository != nil && entry.Repository.Matches(host, repo) {
+			rigs = append(rigs, name)
+		}
+	}
+	sort.Strings(rigs)
+	return rigs
+}
+
+// validateRepoMapping validates a RepoMapping.
+func validateRepoMapping(m *RepoMapping) error {
+	owner, name, ok := strings.Cut(m.Name, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("repository.name must be owner/name, got %q", m.Name)
+	}
+	if strings.ContainsAny(m.Hostname(), " /") {
+		return fmt.Errorf("repository.host must be a hostname, got %q", m.Host)
+	}
+	return nil
+}
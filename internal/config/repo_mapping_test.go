@@ -0,0 +1,42 @@
+package config
+
+import "testing"
+
+func TestRigsForRepo(t *testing.T) {
+	c := &RigsConfig{Rigs: map[string]RigEntry{
+		"gastown": {Repository: &RepoMapping{Name: "steveyegge/gastown"}},
+		"infra":   {Repository: &RepoMapping{Host: "https://Gitea.example.com", Name: "ops/infra"}},
+		"beads":   {GitURL: "https://github.com/steveyegge/beads.git"},
+	}}
+	for _, tc := range []struct {
+		host, repo string
+		want       int
+	}{
+		{"github.com", "SteveYegge/gastown", 1},
+		{"gitea.example.com", "ops/infra", 1},
+		{"github.com", "ops/infra", 0},
+		{"github.com", "steveyegge/beads", 0}, // No mapping
+	} {
+		if got := c.RigsForRepo(tc.host, tc.repo); len(got) != tc.want {
+			t.Errorf("RigsForRepo(%s, %s) = %v, want %d rigs", tc.host, tc.repo, got, tc.want)
+		}
+	}
+}
+
+func TestValidateRigsConfigRepository(t *testing.T) {
+	for _, tc := range []struct {
+		mapping RepoMapping
+		wantErr bool
+	}{
+		{RepoMapping{Name: "org/app", DefaultBranch: "main"}, false},
+		{RepoMapping{Host: "gitea.example.com", Name: "org/app"}, false},
+		{RepoMapping{Name: "app"}, true},
+		{RepoMapping{Name: "org/app/extra"}, true},
+		{RepoMapping{Host: "gitea.example.com/org", Name: "org/app"}, true},
+	} {
+		c := &RigsConfig{Version: 1, Rigs: map[string]RigEntry{"app": {Repository: &tc.mapping}}}
+		if err := validateRigsConfig(c); (err != nil) != tc.wantErr {
+			t.Errorf("validateRigsConfig(%+v) = %v, wantErr %v", tc.mapping, err, tc.wantErr)
+		}
+	}
+}
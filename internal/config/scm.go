@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SCMConfig says where a rig's pull requests live when that isn't (only)
+// GitHub. GitHub hosts are configured with GitHubConfig.
+type SCMConfig struct {
+	// Provider is "github" (default), "gitea", or "bitbucket" (Cloud).
+	Provider string `json:"provider,omitempty"`
+
+	// Host is the server, e.g. "gitea.example.com". Required for gitea;
+	// bitbucket defaults to api.bitbucket.org.
+	Host string `json:"host,omitempty"`
+
+	// Repo is the repository as owner/name (workspace/slug on Bitbucket).
+	// Required for gitea and bitbucket; for github it overrides the
+	// repository of the working directory.
+	Repo string `json:"repo,omitempty"`
+
+	// Token authenticates API calls. It must be a secret reference.
+	Token string `json:"token,omitempty"`
+}
+
+// validateSCMConfig validates an SCMConfig.
+func validateSCMConfig(c *SCMConfig) error {
+	switch c.Provider {
+	case "", "github":
+	case "gitea":
+		if c.Host == "" {
+			return fmt.Errorf("%w: scm.host is required for gitea", ErrMissingField)
+		}
+	case "bitbucket":
+	default:
+		return fmt.Errorf("scm.provider %q: want github, gitea, or bitbucket", c.Provider)
+	}
+	if c.Provider == "gitea" || c.Provider == "bitbucket" {
+		if c.Repo == "" {
+			return fmt.Errorf("%w: scm.repo is required for %s", ErrMissingField, c.Provider)
+		}
+	}
+	if c.Repo != "" && strings.Count(c.Repo, "/") != 1 {
+		return fmt.Errorf("scm.repo must be owner/name, got %q", c.Repo)
+	}
+	if c.Token != "" {
+		if !strings.HasPrefix(c.Token, secretRefPrefix) {
+			return fmt.Errorf("scm.token must be a secret reference (secret:env:NAME or secret:file:PATH), not a literal token")
+		}
+		if _, _, err := parseSecretRef(c.Token); err != nil {
+			return fmt.Errorf("scm.token: %w", err)
+		}
+	}
+	return nil
+}
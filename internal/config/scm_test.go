@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestValidateSCMConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg     SCMConfig
+		wantErr bool
+	}{
+		{SCMConfig{}, false},
+		{SCMConfig{Provider: "gitea", Host: "gitea.example.com", Repo: "org/app", Token: "secret:env:GITEA_TOKEN"}, false},
+		{SCMConfig{Provider: "bitbucket", Repo: "team/app"}, false},
+		{SCMConfig{Provider: "gitea", Repo: "org/app"}, true},
+		{SCMConfig{Provider: "bitbucket"}, true},
+		{SCMConfig{Provider: "bitbucket", Repo: "app"}, true},
+		{SCMConfig{Provider: "gitlab", Repo: "org/app"}, true},
+		{SCMConfig{Provider: "gitea", Host: "gitea.example.com", Repo: "org/app", Token: "plain"}, true},
+	} {
+		err := validateSCMConfig(&tc.cfg)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("validateSCMConfig(%+v) = %v, wantErr %v", tc.cfg, err, tc.wantErr)
+		}
+	}
+}
@@ -39,12 +39,40 @@ type TownSettings struct {
 	Type    string `json:"type"`    // "town-settings"
 	Version int    `json:"version"` // schema version
 
+	// Include lists settings files layered underneath this one, relative
+	// to this file. See ResolveLayeredConfig for merge semantics.
+	Include []string `json:"include,omitempty"`
+
+	// LayoutVersion is the town layout migration this town has been
+	// brought up to (see 'gt migrate status'). 0 predates migrations.
+	LayoutVersion int `json:"layout_version,omitempty"`
+
+	// Protected declares destructive operations that need a typed
+	// confirmation or --force. Nil means DefaultProtectedOperations.
+	Protected *ProtectedConfig `json:"protected,omitempty"`
+
 	// CLITheme controls CLI output color scheme.
 	// Values: "dark", "light", "auto" (default).
 	// "auto" lets the terminal emulator's background color guide the choice.
 	// Can be overridden by GT_THEME environment variable.
 	CLITheme string `json:"cli_theme,omitempty"`
 
+	// Locale selects the language of gt's output, e.g. "de" or "es_MX".
+	// Empty follows LC_ALL/LC_MESSAGES/LANG. Can be overridden by the
+	// GT_LOCALE environment variable.
+	Locale string `json:"locale,omitempty"`
+
+	// BeadsBackend selects how gt runs bd: "cli" (default) starts a
+	// standalone bd process per operation; "daemon" goes through the bd
+	// daemon. Can be overridden by GT_BEADS_BACKEND.
+	BeadsBackend string `json:"beads_backend,omitempty"`
+
+	// SessionBackend selects what polecat sessions run in: "tmux"
+	// (default), "screen", or "process" (a detached background process
+	// with its output in a log file, for hosts and containers where nobody
+	// attaches). Can be overridden by GT_SESSION_BACKEND.
+	SessionBackend string `json:"session_backend,omitempty"`
+
 	// DefaultAgent is the name of the agent preset to use by default.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp")
 	// or a custom agent name defined in settings/agents.json.
@@ -68,6 +96,42 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// Env is injected into every agent process in the town. Rig settings
+	// override it per key. Values may be secret references (see
+	// ResolveSecretRef).
+	Env map[string]string `json:"env,omitempty"`
+
+	// Logging ships town log events to syslog, Loki, or rotating JSON files.
+	Logging *LoggingConfig `json:"logging,omitempty"`
+
+	// GC sets how long gt gc keeps caches, session logs, review outputs,
+	// and closed wisps.
+	GC *GCConfig `json:"gc,omitempty"`
+
+	// DiskUsage sets per-category disk usage limits checked by gt doctor.
+	DiskUsage *DiskUsageConfig `json:"disk_usage,omitempty"`
+
+	// Maintenance defines recurring windows during which patrols are
+	// skipped and formula and plugin runs are queued.
+	Maintenance *MaintenanceConfig `json:"maintenance,omitempty"`
+
+	// Concurrency caps open formula convoys per concurrency class across
+	// all rigs, e.g. one heavy-build convoy at a time.
+	Concurrency *ConcurrencyConfig `json:"concurrency,omitempty"`
+
+	// OutputRoot is the directory formula runs may write absolute output
+	// paths under, absolute or relative to the town root. Empty confines
+	// them to the rig directory the formula runs against.
+	OutputRoot string `json:"output_root,omitempty"`
+
+	// LifecycleHooks are user commands run on town events, such as a
+	// convoy landing.
+	LifecycleHooks *LifecycleHooksConfig `json:"lifecycle_hooks,omitempty"`
+
+	// DisabledFormulas are the formulas gt formula run refuses anywhere in
+	// the town, by name (see gt formula disable).
+	DisabledFormulas map[string]DisabledFormula `json:"disabled_formulas,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -167,6 +231,15 @@ type RigEntry struct {
 	LocalRepo   string       `json:"local_repo,omitempty"`
 	AddedAt     time.Time    `json:"added_at"`
 	BeadsConfig *BeadsConfig `json:"beads,omitempty"`
+	Repository  *RepoMapping `json:"repository,omitempty"`
+}
+
+// RepoMapping names the repository a rig tracks on its source host, so PR
+// URLs and host events resolve to the rig without --rig.
+type RepoMapping struct {
+	Host          string `json:"host,omitempty"`           // Source host (default: github.com)
+	Name          string `json:"name"`                     // owner/name (workspace/slug on Bitbucket)
+	DefaultBranch string `json:"default_branch,omitempty"` // Branch PRs target, e.g. main
 }
 
 // BeadsConfig represents beads configuration for a rig.
@@ -198,6 +271,10 @@ type RigConfig struct {
 	LocalRepo string       `json:"local_repo,omitempty"`
 	CreatedAt time.Time    `json:"created_at"` // when the rig was created
 	Beads     *BeadsConfig `json:"beads,omitempty"`
+
+	// Remote runs the rig's polecats on another machine over SSH:
+	// "ssh://user@host/path/to/working-copy".
+	Remote string `json:"remote,omitempty"`
 }
 
 // WorkflowConfig represents workflow settings for a rig.
@@ -205,12 +282,25 @@ type WorkflowConfig struct {
 	// DefaultFormula is the formula to use when `gt formula run` is called without arguments.
 	// If empty, no default is set and a formula name must be provided.
 	DefaultFormula string `json:"default_formula,omitempty"`
+
+	// DisabledFormulas are the formulas gt formula run refuses on this
+	// rig, by name (see gt formula disable --rig).
+	DisabledFormulas map[string]DisabledFormula `json:"disabled_formulas,omitempty"`
+}
+
+// DisabledFormula records why and by whom a formula was disabled. The
+// formula file is left alone, so gt formula enable restores it as it was.
+type DisabledFormula struct {
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+	At     time.Time `json:"at"`
 }
 
 // RigSettings represents per-rig behavioral configuration (settings/config.json).
 type RigSettings struct {
 	Type       string            `json:"type"`                  // "rig-settings"
 	Version    int               `json:"version"`               // schema version
+	Include    []string          `json:"include,omitempty"`     // settings files layered underneath this one
 	MergeQueue *MergeQueueConfig `json:"merge_queue,omitempty"` // merge queue settings
 	Theme      *ThemeConfig      `json:"theme,omitempty"`       // tmux theme settings
 	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
@@ -218,6 +308,34 @@ type RigSettings struct {
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
+	// Refinery defines post-synthesis pipeline steps for convoy outputs.
+	Refinery *RefineryPipelineConfig `json:"refinery,omitempty"`
+
+	// Quota limits how much formula work may be dispatched to this rig.
+	Quota *QuotaConfig `json:"quota,omitempty"`
+
+	// Approval holds formula runs for approval before their legs dispatch.
+	Approval *ApprovalConfig `json:"approval,omitempty"`
+
+	// GitHub selects the GitHub host (e.g. GitHub Enterprise Server) the
+	// rig's PRs live on.
+	GitHub *GitHubConfig `json:"github,omitempty"`
+
+	// SCM selects a non-GitHub provider (Gitea, Bitbucket) for the rig's
+	// pull requests.
+	SCM *SCMConfig `json:"scm,omitempty"`
+
+	// WorktreePool keeps checked-out worktrees ready for new polecats.
+	WorktreePool *WorktreePoolConfig `json:"worktree_pool,omitempty"`
+
+	// Clone sets clone depth, partial clone filter, and sparse checkout
+	// paths for the rig's repository.
+	Clone *CloneConfig `json:"clone,omitempty"`
+
+	// Env is injected into every agent process in the rig, over the town's
+	// env. Values may be secret references (see ResolveSecretRef).
+	Env map[string]string `json:"env,omitempty"`
+
 	// Agent selects which agent preset to use for this rig.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp")
 	// or a custom agent defined in settings/agents.json.
@@ -677,6 +795,38 @@ func BuiltinRoleThemes() map[string]string {
 	}
 }
 
+// RefineryPipelineConfig defines the post-synthesis refinery pipeline for a rig.
+// Steps run in order after a convoy's synthesis completes (or manually via
+// gt refinery run) and turn the review outputs into published artifacts.
+type RefineryPipelineConfig struct {
+	// Steps are run in order. A failing step stops the pipeline unless
+	// the step sets ContinueOnError.
+	Steps []RefineryStep `json:"steps"`
+
+	// Manual disables running the pipeline automatically on synthesis close.
+	// The pipeline can still be run with gt refinery run <review-id>.
+	Manual bool `json:"manual,omitempty"`
+}
+
+// RefineryStep is a single post-synthesis pipeline step.
+//
+// Run is executed with sh -c from the review directory. The environment
+// includes GT_REVIEW_ID, GT_REVIEW_DIR, GT_REFINERY_OUTPUT (a per-review
+// directory under <rig>/refinery/outputs/), and GT_RIG.
+type RefineryStep struct {
+	// Name identifies the step in output and logs (e.g., "pdf", "publish").
+	Name string `json:"name"`
+
+	// Run is the shell command to execute.
+	Run string `json:"run"`
+
+	// Timeout bounds the step's runtime (Go duration, e.g., "5m"). Default: 10m.
+	Timeout string `json:"timeout,omitempty"`
+
+	// ContinueOnError lets later steps run even if this one fails.
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+}
+
 // MergeQueueConfig represents merge queue settings for a rig.
 type MergeQueueConfig struct {
 	// Enabled controls whether the merge queue is active.
@@ -0,0 +1,23 @@
+package config
+
+import "fmt"
+
+// MaxWorktreePoolSize caps WorktreePoolConfig.Size; each pooled worktree
+// is a full checkout of the rig's repository.
+const MaxWorktreePoolSize = 32
+
+// WorktreePoolConfig sizes a rig's pool of ready worktrees. New polecats
+// take a pooled worktree instead of checking one out, and removed
+// polecats' worktrees are cleaned and returned to the pool.
+type WorktreePoolConfig struct {
+	// Size is how many worktrees to keep ready. Zero disables the pool.
+	Size int `json:"size"`
+}
+
+// validateWorktreePoolConfig validates a WorktreePoolConfig.
+func validateWorktreePoolConfig(c *WorktreePoolConfig) error {
+	if c.Size < 0 || c.Size > MaxWorktreePoolSize {
+		return fmt.Errorf("worktree_pool.size must be between 0 and %d, got %d", MaxWorktreePoolSize, c.Size)
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// sshConnectionFailed is the exit status ssh uses for its own errors, as
+// opposed to the exit status of the remote command.
+const sshConnectionFailed = 255
+
+// Remote is a rig working copy on another machine, configured as
+// ssh://[user@]host[:port]/path.
+type Remote struct {
+	Host string // user@host or host
+	Port string // empty for the ssh default
+	Path string // absolute path of the working copy
+}
+
+// ParseRemote parses an ssh:// remote URL.
+func ParseRemote(s string) (*Remote, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote %q: %w", s, err)
+	}
+	if u.Scheme != "ssh" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid remote %q: want ssh://[user@]host[:port]/path", s)
+	}
+	if u.Path == "" || u.Path == "/" {
+		return nil, fmt.Errorf("invalid remote %q: missing working copy path", s)
+	}
+	r := &Remote{Host: u.Hostname(), Port: u.Port(), Path: path.Clean(u.Path)}
+	if u.User != nil && u.User.Username() != "" {
+		r.Host = u.User.Username() + "@" + r.Host
+	}
+	return r, nil
+}
+
+// String returns the remote in ssh:// form.
+func (r *Remote) String() string {
+	host := r.Host
+	if r.Port != "" {
+		host += ":" + r.Port
+	}
+	return "ssh://" + host + r.Path
+}
+
+// WorktreePath is where a polecat's worktree lives on the remote machine:
+// next to the working copy, so the working copy itself stays clean.
+func (r *Remote) WorktreePath(polecat string) string {
+	return path.Join(path.Dir(r.Path), path.Base(r.Path)+"-polecats", polecat)
+}
+
+// Connection returns an SSH connection to the remote's host.
+func (r *Remote) Connection() *SSHConnection {
+	return &SSHConnection{name: r.Host, host: r.Host, port: r.Port}
+}
+
+// SSHConnection implements Connection by running commands over ssh.
+// Authentication uses the user's ssh configuration and agent; ssh must be
+// able to connect without prompting.
+type SSHConnection struct {
+	name    string
+	host    string // user@host or an ssh config alias
+	port    string
+	keyPath string
+}
+
+// NewSSHConnection creates a connection to host (user@host or an ssh config
+// alias), optionally with an explicit private key.
+func NewSSHConnection(name, host, keyPath string) *SSHConnection {
+	return &SSHConnection{name: name, host: host, keyPath: keyPath}
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// sshArgs returns the ssh options that select the host, without the host.
+// tty allocates a terminal for interactive commands.
+func (c *SSHConnection) sshArgs(tty bool) []string {
+	var args []string
+	if tty {
+		args = append(args, "-t")
+	} else {
+		args = append(args, "-o", "BatchMode=yes")
+	}
+	if c.port != "" {
+		args = append(args, "-p", c.port)
+	}
+	if c.keyPath != "" {
+		args = append(args, "-i", c.keyPath)
+	}
+	return args
+}
+
+// remoteLine builds the shell line run on the remote host.
+func remoteLine(dir string, env map[string]string, cmd string, args ...string) string {
+	words := make([]string, 0, len(args)+len(env)+2)
+	if len(env) > 0 {
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		words = append(words, "env")
+		for _, k := range keys {
+			words = append(words, config.ShellQuote(k+"="+env[k]))
+		}
+	}
+	words = append(words, config.ShellQuote(cmd))
+	for _, a := range args {
+		words = append(words, config.ShellQuote(a))
+	}
+	line := strings.Join(words, " ")
+	if dir != "" {
+		line = "cd " + config.ShellQuote(dir) + " && " + line
+	}
+	return line
+}
+
+// run executes a shell line on the remote host, feeding stdin if non-nil.
+func (c *SSHConnection) run(op, line string, stdin []byte) (stdout, stderr []byte, err error) {
+	args := append(c.sshArgs(false), c.host, line)
+	cmd := exec.Command("ssh", args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() == sshConnectionFailed) {
+		err = &ConnectionError{Op: op, Machine: c.name, Err: fmt.Errorf("%w: %s", err, strings.TrimSpace(errOut.String()))}
+	}
+	return out.Bytes(), errOut.Bytes(), err
+}
+
+// fileError maps a failed remote file command to the connection error types.
+func fileError(err error, stderr []byte, filePath, op string) error {
+	var connErr *ConnectionError
+	if errors.As(err, &connErr) {
+		return err
+	}
+	msg := string(stderr)
+	switch {
+	case strings.Contains(msg, "No such file"):
+		return &NotFoundError{Path: filePath}
+	case strings.Contains(msg, "Permission denied"):
+		return &PermissionError{Path: filePath, Op: op}
+	}
+	return fmt.Errorf("%s %s: %w: %s", op, filePath, err, strings.TrimSpace(msg))
+}
+
+// ReadFile reads the named file.
+func (c *SSHConnection) ReadFile(filePath string) ([]byte, error) {
+	out, stderr, err := c.run("read", remoteLine("", nil, "cat", "--", filePath), nil)
+	if err != nil {
+		return nil, fileError(err, stderr, filePath, "read")
+	}
+	return out, nil
+}
+
+// WriteFile writes data to the named file.
+func (c *SSHConnection) WriteFile(filePath string, data []byte, perm fs.FileMode) error {
+	q := config.ShellQuote(filePath)
+	line := fmt.Sprintf("cat > %s && chmod %o %s", q, perm.Perm(), q)
+	if _, stderr, err := c.run("write", line, data); err != nil {
+		return fileError(err, stderr, filePath, "write")
+	}
+	return nil
+}
+
+// MkdirAll creates a directory and all parent directories.
+func (c *SSHConnection) MkdirAll(dirPath string, perm fs.FileMode) error {
+	line := remoteLine("", nil, "mkdir", "-p", "-m", fmt.Sprintf("%o", perm.Perm()), "--", dirPath)
+	if _, stderr, err := c.run("mkdir", line, nil); err != nil {
+		return fileError(err, stderr, dirPath, "mkdir")
+	}
+	return nil
+}
+
+// Remove removes the named file or empty directory.
+func (c *SSHConnection) Remove(filePath string) error {
+	q := config.ShellQuote(filePath)
+	line := fmt.Sprintf("if [ -d %s ]; then rmdir -- %s; else rm -f -- %s; fi", q, q, q)
+	if _, stderr, err := c.run("remove", line, nil); err != nil {
+		return fileError(err, stderr, filePath, "remove")
+	}
+	return nil
+}
+
+// RemoveAll removes the named file or directory and any children.
+func (c *SSHConnection) RemoveAll(filePath string) error {
+	if _, stderr, err := c.run("remove", remoteLine("", nil, "rm", "-rf", "--", filePath), nil); err != nil {
+		return fileError(err, stderr, filePath, "remove")
+	}
+	return nil
+}
+
+// Stat returns file info for the named file. Requires GNU stat on the
+// remote host.
+func (c *SSHConnection) Stat(filePath string) (FileInfo, error) {
+	out, stderr, err := c.run("stat", remoteLine("", nil, "stat", "-c", "%s %f %Y", "--", filePath), nil)
+	if err != nil {
+		return nil, fileError(err, stderr, filePath, "stat")
+	}
+	return parseStat(path.Base(filePath), string(out))
+}
+
+// parseStat parses `stat -c '%s %f %Y'` output: size, raw mode in hex, and
+// modification time in seconds.
+func parseStat(name, out string) (FileInfo, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected stat output %q", out)
+	}
+	size, err1 := strconv.ParseInt(fields[0], 10, 64)
+	raw, err2 := strconv.ParseUint(fields[1], 16, 32)
+	mtime, err3 := strconv.ParseInt(fields[2], 10, 64)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, fmt.Errorf("unexpected stat output %q: %w", out, err)
+	}
+	mode := fs.FileMode(raw & 0777)
+	isDir := raw&0170000 == 0040000
+	if isDir {
+		mode |= fs.ModeDir
+	}
+	return BasicFileInfo{FileName: name, FileSize: size, FileMode: mode, FileModTime: time.Unix(mtime, 0), FileIsDir: isDir}, nil
+}
+
+// Glob returns the names of all files matching the pattern. The pattern is
+// expanded by the remote shell, so it must not contain spaces.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	line := fmt.Sprintf(`for f in %s; do [ -e "$f" ] && printf '%%s\n' "$f"; done; true`, pattern)
+	out, _, err := c.run("glob", line, nil)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// Exists returns true if the path exists.
+func (c *SSHConnection) Exists(filePath string) (bool, error) {
+	_, _, err := c.run("stat", remoteLine("", nil, "test", "-e", filePath), nil)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Exec runs a command and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.ExecDir("", cmd, args...)
+}
+
+// ExecDir runs a command in the specified directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	out, stderr, err := c.run("exec", remoteLine(dir, nil, cmd, args...), nil)
+	return append(out, stderr...), err
+}
+
+// ExecEnv runs a command with additional environment variables.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	out, stderr, err := c.run("exec", remoteLine("", env, cmd, args...), nil)
+	return append(out, stderr...), err
+}
+
+// TmuxNewSession creates a new tmux session on the remote host.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	_, err := c.Exec("tmux", args...)
+	return err
+}
+
+// TmuxKillSession terminates a tmux session on the remote host.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	_, err := c.Exec("tmux", "kill-session", "-t", name)
+	return err
+}
+
+// TmuxSendKeys sends keys to a tmux session on the remote host, then Enter.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	line := remoteLine("", nil, "tmux", "send-keys", "-t", session, "-l", keys) +
+		" && " + remoteLine("", nil, "tmux", "send-keys", "-t", session, "Enter")
+	_, _, err := c.run("exec", line, nil)
+	return err
+}
+
+// TmuxCapturePane captures the last N lines from a tmux pane on the remote host.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	out, _, err := c.run("exec", remoteLine("", nil, "tmux", "capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines)), nil)
+	return string(out), err
+}
+
+// TmuxHasSession returns true if the session exists on the remote host.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	_, _, err := c.run("exec", remoteLine("", nil, "tmux", "has-session", "-t", "="+name), nil)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() != sshConnectionFailed {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// TmuxListSessions returns all tmux session names on the remote host.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	out, _, err := c.run("exec", remoteLine("", nil, "tmux", "list-sessions", "-F", "#{session_name}"), nil)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() != sshConnectionFailed {
+		return nil, nil // No server running
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// SessionCommand returns a shell command, for a local tmux pane, that runs
+// command interactively in dir on the remote host. If syncTo is set, dir is
+// copied back to that local directory (without .git) when the command exits,
+// so the outputs it wrote are available locally.
+func (c *SSHConnection) SessionCommand(dir, command, syncTo string) string {
+	ssh := shellJoin(append(append([]string{"ssh"}, c.sshArgs(true)...), c.host, "cd "+config.ShellQuote(dir)+" && "+command))
+	if syncTo == "" {
+		return "exec " + ssh
+	}
+	return "sh -c " + config.ShellQuote(ssh+"; status=$?; "+c.pullCommand(dir, syncTo)+"; exit $status")
+}
+
+// Pull copies dir on the remote host to localDir, without .git.
+func (c *SSHConnection) Pull(dir, localDir string) error {
+	out, err := exec.Command("sh", "-c", c.pullCommand(dir, localDir)).CombinedOutput()
+	if err != nil {
+		return &ConnectionError{Op: "pull", Machine: c.name, Err: fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))}
+	}
+	return nil
+}
+
+// pullCommand is the rsync command line that copies dir to localDir.
+func (c *SSHConnection) pullCommand(dir, localDir string) string {
+	ssh := shellJoin(append([]string{"ssh"}, c.sshArgs(false)...))
+	return shellJoin([]string{"rsync", "-a", "--exclude", ".git", "-e", ssh,
+		c.host + ":" + strings.TrimSuffix(dir, "/") + "/", strings.TrimSuffix(localDir, "/") + "/"})
+}
+
+func shellJoin(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = config.ShellQuote(w)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
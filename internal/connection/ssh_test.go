@@ -0,0 +1,71 @@
+package connection
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRemote(t *testing.T) {
+	r, err := ParseRemote("ssh://me@build01:2222/srv/mono/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Host != "me@build01" || r.Port != "2222" || r.Path != "/srv/mono" {
+		t.Errorf("ParseRemote = %+v", r)
+	}
+	if r.String() != "ssh://me@build01:2222/srv/mono" {
+		t.Errorf("String() = %q", r.String())
+	}
+	if got := r.WorktreePath("Toast"); got != "/srv/mono-polecats/Toast" {
+		t.Errorf("WorktreePath = %q", got)
+	}
+
+	for _, bad := range []string{"", "build01:/srv/mono", "https://build01/srv", "ssh://build01", "ssh:///srv/mono"} {
+		if _, err := ParseRemote(bad); err == nil {
+			t.Errorf("ParseRemote(%q): expected error", bad)
+		}
+	}
+}
+
+func TestRemoteLine(t *testing.T) {
+	got := remoteLine("/srv/my repo", map[string]string{"B": "2", "A": "x y"}, "git", "commit", "-m", "it's done")
+	want := `cd '/srv/my repo' && env 'A=x y' B=2 git commit -m 'it'\''s done'`
+	if got != want {
+		t.Errorf("remoteLine =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	fi, err := parseStat("src", "4096 41ed 1700000000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsDir() || fi.Mode().Perm() != 0755 || fi.Size() != 4096 || fi.ModTime().Unix() != 1700000000 {
+		t.Errorf("dir stat = %+v", fi)
+	}
+	fi, err = parseStat("f", "12 81a4 1700000000")
+	if err != nil || fi.IsDir() || fi.Mode().Perm() != 0644 {
+		t.Errorf("file stat = %+v, %v", fi, err)
+	}
+	if _, err := parseStat("f", "garbage"); err == nil {
+		t.Error("expected error for malformed stat output")
+	}
+}
+
+func TestSessionCommand(t *testing.T) {
+	r, _ := ParseRemote("ssh://me@build01:2222/srv/mono")
+	c := r.Connection()
+
+	got := c.SessionCommand("/srv/mono-polecats/Toast", "claude --resume", "")
+	want := `exec ssh -t -p 2222 me@build01 'cd /srv/mono-polecats/Toast && claude --resume'`
+	if got != want {
+		t.Errorf("SessionCommand =\n  %s\nwant\n  %s", got, want)
+	}
+
+	got = c.SessionCommand("/srv/mono-polecats/Toast", "claude", "/town/mono/polecats/Toast/mono")
+	for _, part := range []string{"sh -c ", "status=$?", "rsync -a --exclude .git", "me@build01:/srv/mono-polecats/Toast/", "/town/mono/polecats/Toast/mono/", "exit $status"} {
+		if !strings.Contains(got, part) {
+			t.Errorf("SessionCommand with sync = %q, missing %q", got, part)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package convoy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+)
+
+// PendingApproval is a formula convoy held for approval. Its beads exist,
+// but its ready legs are only queued for dispatch once it is approved.
+type PendingApproval struct {
+	ConvoyID    string      `json:"convoy_id"`
+	Rig         string      `json:"rig"`
+	Formula     string      `json:"formula"`
+	RequestedBy string      `json:"requested_by,omitempty"`
+	RequestedAt time.Time   `json:"requested_at"`
+	Legs        []QueuedLeg `json:"legs"` // Queued on approval
+}
+
+// Approvals are the town's convoys waiting for approval.
+type Approvals struct {
+	Pending []PendingApproval `json:"pending,omitempty"`
+}
+
+// ApprovalsFile returns the path of the town's pending approvals.
+func ApprovalsFile(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "approvals.json")
+}
+
+// LoadApprovals reads the town's pending approvals. A missing file means
+// none are pending.
+func LoadApprovals(townRoot string) (*Approvals, error) {
+	a := &Approvals{}
+	data, err := os.ReadFile(ApprovalsFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return a, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// UpdateApprovals runs fn on the town's pending approvals while holding
+// their lock and saves the result, so a convoy is approved at most once.
+func UpdateApprovals(townRoot string, fn func(a *Approvals) error) error {
+	path := ApprovalsFile(townRoot)
+	return fsx.WithLock(path, func() error {
+		a, err := LoadApprovals(townRoot)
+		if err != nil {
+			return err
+		}
+		if err := fn(a); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(a, "", "  ")
+		if err != nil {
+			return err
+		}
+		return fsx.WriteFile(path, append(data, '\n'), 0644)
+	})
+}
+
+// Add records a convoy as pending, replacing any earlier record for it.
+func (a *Approvals) Add(p PendingApproval) {
+	if p.RequestedAt.IsZero() {
+		p.RequestedAt = time.Now().UTC()
+	}
+	a.Take(p.ConvoyID)
+	a.Pending = append(a.Pending, p)
+}
+
+// Lookup returns the pending record for a convoy.
+func (a *Approvals) Lookup(convoyID string) (PendingApproval, bool) {
+	for _, p := range a.Pending {
+		if p.ConvoyID == convoyID {
+			return p, true
+		}
+	}
+	return PendingApproval{}, false
+}
+
+// Take removes and returns the pending record for a convoy.
+func (a *Approvals) Take(convoyID string) (PendingApproval, bool) {
+	for i, p := range a.Pending {
+		if p.ConvoyID == convoyID {
+			a.Pending = append(a.Pending[:i], a.Pending[i+1:]...)
+			return p, true
+		}
+	}
+	return PendingApproval{}, false
+}
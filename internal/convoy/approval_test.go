@@ -0,0 +1,33 @@
+package convoy
+
+import "testing"
+
+func TestApprovals(t *testing.T) {
+	town := t.TempDir()
+	err := UpdateApprovals(town, func(a *Approvals) error {
+		a.Add(PendingApproval{ConvoyID: "hq-cv-1", Rig: "gastown", Formula: "deploy", Legs: []QueuedLeg{{BeadID: "hq-leg-1"}}})
+		a.Add(PendingApproval{ConvoyID: "hq-cv-2", Rig: "gastown", Formula: "review"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateApprovals() = %v", err)
+	}
+
+	a, err := LoadApprovals(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := a.Lookup("hq-cv-1")
+	if !ok || p.Formula != "deploy" || len(p.Legs) != 1 || p.RequestedAt.IsZero() {
+		t.Fatalf("Lookup(hq-cv-1) = %+v, %v", p, ok)
+	}
+	if _, ok := a.Take("hq-cv-1"); !ok {
+		t.Fatal("Take(hq-cv-1) found nothing")
+	}
+	if _, ok := a.Take("hq-cv-1"); ok {
+		t.Error("Take() returned hq-cv-1 twice")
+	}
+	if len(a.Pending) != 1 || a.Pending[0].ConvoyID != "hq-cv-2" {
+		t.Errorf("Pending = %+v, want hq-cv-2 only", a.Pending)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // CheckConvoysForIssue finds any convoys tracking the given issue and triggers
@@ -61,7 +63,7 @@ func getTrackingConvoys(townRoot, issueID string) []string {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return nil
 	}
 
@@ -86,7 +88,7 @@ func isConvoyClosed(townRoot, convoyID string) bool {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return false
 	}
 
@@ -108,7 +110,7 @@ func runConvoyCheck(townRoot, convoyID string) error {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return fmt.Errorf("%v: %s", err, stderr.String())
 	}
 
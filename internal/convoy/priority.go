@@ -0,0 +1,51 @@
+package convoy
+
+import "fmt"
+
+// Priority orders formula convoy legs waiting for a polecat: higher
+// priority legs are dispatched first, and high priority runs may pause
+// running low priority legs to make room.
+type Priority string
+
+const (
+	PriorityHigh   Priority = "high"   // Urgent work, e.g. a PR review someone is waiting on
+	PriorityNormal Priority = "normal" // Default
+	PriorityLow    Priority = "low"    // Background work such as patrol sweeps; may be preempted
+)
+
+// ParsePriority parses a --priority value. Empty means normal.
+func ParsePriority(s string) (Priority, error) {
+	switch p := Priority(s); p {
+	case "":
+		return PriorityNormal, nil
+	case PriorityHigh, PriorityNormal, PriorityLow:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid priority %q: want high, normal, or low", s)
+}
+
+// Rank orders priorities for dispatch: lower ranks go first. Unknown
+// values rank as normal.
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	}
+	return 1
+}
+
+// BeadPriority returns the bd priority (0-4) for leg beads of this
+// priority, so bd ready lists urgent legs first too.
+func (p Priority) BeadPriority() int {
+	return p.Rank() + 1
+}
+
+// OrDefault returns p, or normal if p is empty.
+func (p Priority) OrDefault() Priority {
+	if p == "" {
+		return PriorityNormal
+	}
+	return p
+}
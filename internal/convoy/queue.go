@@ -0,0 +1,294 @@
+package convoy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+)
+
+// QueuedLeg is a formula convoy leg waiting for a polecat slot on its rig.
+type QueuedLeg struct {
+	BeadID   string    `json:"bead_id"`
+	Rig      string    `json:"rig"`
+	ConvoyID string    `json:"convoy_id,omitempty"`
+	Payload  string    `json:"payload"` // Sling context file passed to gt sling
+	Priority Priority  `json:"priority"`
+	QueuedAt time.Time `json:"queued_at"`
+	Held     bool      `json:"held,omitempty"` // Skipped by the dispatcher until released
+
+	// NotBefore delays a retried leg's dispatch until its backoff passes.
+	NotBefore time.Time `json:"not_before,omitempty"`
+}
+
+// PausedLeg is a running low priority leg whose polecat was suspended to
+// make room for higher priority work. It resumes before waiting legs of
+// the same priority.
+type PausedLeg struct {
+	BeadID   string    `json:"bead_id"`
+	Rig      string    `json:"rig"`
+	ConvoyID string    `json:"convoy_id,omitempty"`
+	Session  string    `json:"session"`
+	Priority Priority  `json:"priority"`
+	PausedAt time.Time `json:"paused_at"`
+	Held     bool      `json:"held,omitempty"` // Stays paused until released
+}
+
+// Queue is the town's dispatch queue for formula legs.
+type Queue struct {
+	Waiting []QueuedLeg `json:"waiting,omitempty"`
+	Paused  []PausedLeg `json:"paused,omitempty"`
+}
+
+// QueueFile returns the path of the town's leg queue.
+func QueueFile(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "leg-queue.json")
+}
+
+// LoadQueue reads the town's leg queue. A missing file is an empty queue.
+func LoadQueue(townRoot string) (*Queue, error) {
+	q := &Queue{}
+	data, err := os.ReadFile(QueueFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return q, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// UpdateQueue runs fn on the town's leg queue while holding its lock and
+// saves the result, so concurrent dispatchers never hand out the same slot.
+func UpdateQueue(townRoot string, fn func(q *Queue) error) error {
+	path := QueueFile(townRoot)
+	return fsx.WithLock(path, func() error {
+		q, err := LoadQueue(townRoot)
+		if err != nil {
+			return err
+		}
+		if err := fn(q); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(q, "", "  ")
+		if err != nil {
+			return err
+		}
+		return fsx.WriteFile(path, append(data, '\n'), 0644)
+	})
+}
+
+// Add queues a leg unless it is already waiting or paused. It reports
+// whether the leg was added.
+func (q *Queue) Add(leg QueuedLeg) bool {
+	if q.Has(leg.BeadID) {
+		return false
+	}
+	if leg.QueuedAt.IsZero() {
+		leg.QueuedAt = time.Now().UTC()
+	}
+	leg.Priority = leg.Priority.OrDefault()
+	q.Waiting = append(q.Waiting, leg)
+	return true
+}
+
+// Has reports whether a leg bead is waiting or paused.
+func (q *Queue) Has(beadID string) bool {
+	for _, l := range q.Waiting {
+		if l.BeadID == beadID {
+			return true
+		}
+	}
+	for _, l := range q.Paused {
+		if l.BeadID == beadID {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove drops a leg bead from the queue, waiting or paused.
+func (q *Queue) Remove(beadID string) {
+	waiting := q.Waiting[:0]
+	for _, l := range q.Waiting {
+		if l.BeadID != beadID {
+			waiting = append(waiting, l)
+		}
+	}
+	q.Waiting = waiting
+	paused := q.Paused[:0]
+	for _, l := range q.Paused {
+		if l.BeadID != beadID {
+			paused = append(paused, l)
+		}
+	}
+	q.Paused = paused
+}
+
+// Lookup returns a copy of the queued leg for a bead.
+func (q *Queue) Lookup(beadID string) (Entry, bool) {
+	for _, l := range q.Waiting {
+		if l.BeadID == beadID {
+			return Entry{Waiting: &l}, true
+		}
+	}
+	for _, l := range q.Paused {
+		if l.BeadID == beadID {
+			return Entry{Paused: &l}, true
+		}
+	}
+	return Entry{}, false
+}
+
+// SetHeld holds or releases a queued leg. Held legs keep their place but
+// are skipped by the dispatcher. It reports whether the leg is queued.
+func (q *Queue) SetHeld(beadID string, held bool) bool {
+	for i := range q.Waiting {
+		if q.Waiting[i].BeadID == beadID {
+			q.Waiting[i].Held = held
+			return true
+		}
+	}
+	for i := range q.Paused {
+		if q.Paused[i].BeadID == beadID {
+			q.Paused[i].Held = held
+			return true
+		}
+	}
+	return false
+}
+
+// Empty reports whether nothing is waiting or paused.
+func (q *Queue) Empty() bool {
+	return len(q.Waiting) == 0 && len(q.Paused) == 0
+}
+
+// Entry is a waiting or paused leg in dispatch order.
+type Entry struct {
+	Waiting *QueuedLeg // Set for legs to sling
+	Paused  *PausedLeg // Set for legs to resume
+}
+
+// BeadID returns the entry's leg bead.
+func (e Entry) BeadID() string {
+	if e.Paused != nil {
+		return e.Paused.BeadID
+	}
+	return e.Waiting.BeadID
+}
+
+// Rig returns the rig the entry's leg runs on.
+func (e Entry) Rig() string {
+	if e.Paused != nil {
+		return e.Paused.Rig
+	}
+	return e.Waiting.Rig
+}
+
+// Priority returns the entry's leg priority.
+func (e Entry) Priority() Priority {
+	if e.Paused != nil {
+		return e.Paused.Priority.OrDefault()
+	}
+	return e.Waiting.Priority.OrDefault()
+}
+
+// Held reports whether the entry's leg is held.
+func (e Entry) Held() bool {
+	if e.Paused != nil {
+		return e.Paused.Held
+	}
+	return e.Waiting.Held
+}
+
+// BackingOff reports whether the entry is a retried leg whose backoff
+// hasn't passed at now.
+func (e Entry) BackingOff(now time.Time) bool {
+	return e.Waiting != nil && now.Before(e.Waiting.NotBefore)
+}
+
+// State describes the entry for listings: waiting, paused, held, or
+// backoff.
+func (e Entry) State() string {
+	switch {
+	case e.Held():
+		return "held"
+	case e.Paused != nil:
+		return "paused"
+	case e.BackingOff(time.Now()):
+		return "backoff"
+	}
+	return "waiting"
+}
+
+// ConvoyID returns the convoy the entry's leg belongs to, if known.
+func (e Entry) ConvoyID() string {
+	if e.Paused != nil {
+		return e.Paused.ConvoyID
+	}
+	return e.Waiting.ConvoyID
+}
+
+// Since returns when the entry's leg joined the queue.
+func (e Entry) Since() time.Time {
+	if e.Paused != nil {
+		return e.Paused.PausedAt
+	}
+	return e.Waiting.QueuedAt
+}
+
+// Order returns the rig's queued legs in the order they get polecat slots:
+// higher priority first; within a priority, paused legs (which have already
+// started) before waiting ones, then oldest first. Entries are copies, so
+// the queue can be changed while walking them.
+func (q *Queue) Order(rig string) []Entry {
+	var entries []Entry
+	for _, l := range q.Paused {
+		if l.Rig == rig {
+			entries = append(entries, Entry{Paused: &l})
+		}
+	}
+	for _, l := range q.Waiting {
+		if l.Rig == rig {
+			entries = append(entries, Entry{Waiting: &l})
+		}
+	}
+	sort.SliceStable(entries, func(a, b int) bool {
+		ea, eb := entries[a], entries[b]
+		if ra, rb := ea.Priority().Rank(), eb.Priority().Rank(); ra != rb {
+			return ra < rb
+		}
+		if pa, pb := ea.Paused != nil, eb.Paused != nil; pa != pb {
+			return pa
+		}
+		return ea.Since().Before(eb.Since())
+	})
+	return entries
+}
+
+// Rigs returns the rigs with queued legs, sorted.
+func (q *Queue) Rigs() []string {
+	seen := make(map[string]bool)
+	var rigs []string
+	for _, l := range q.Waiting {
+		if !seen[l.Rig] {
+			seen[l.Rig] = true
+			rigs = append(rigs, l.Rig)
+		}
+	}
+	for _, l := range q.Paused {
+		if !seen[l.Rig] {
+			seen[l.Rig] = true
+			rigs = append(rigs, l.Rig)
+		}
+	}
+	sort.Strings(rigs)
+	return rigs
+}
@@ -0,0 +1,121 @@
+package convoy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePriority(t *testing.T) {
+	for in, want := range map[string]Priority{"": PriorityNormal, "high": PriorityHigh, "low": PriorityLow} {
+		got, err := ParsePriority(in)
+		if err != nil || got != want {
+			t.Errorf("ParsePriority(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParsePriority("urgent"); err == nil {
+		t.Error("ParsePriority(urgent) succeeded, want error")
+	}
+	if PriorityHigh.BeadPriority() >= PriorityLow.BeadPriority() {
+		t.Error("high priority legs should get a more urgent bead priority than low ones")
+	}
+}
+
+func TestQueueOrder(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := &Queue{}
+	q.Add(QueuedLeg{BeadID: "low-old", Rig: "gastown", Priority: PriorityLow, QueuedAt: base})
+	q.Add(QueuedLeg{BeadID: "normal", Rig: "gastown", QueuedAt: base.Add(time.Minute)})
+	q.Add(QueuedLeg{BeadID: "high-new", Rig: "gastown", Priority: PriorityHigh, QueuedAt: base.Add(3 * time.Minute)})
+	q.Add(QueuedLeg{BeadID: "high-old", Rig: "gastown", Priority: PriorityHigh, QueuedAt: base.Add(2 * time.Minute)})
+	q.Add(QueuedLeg{BeadID: "other-rig", Rig: "beads", Priority: PriorityHigh, QueuedAt: base})
+	q.Paused = append(q.Paused, PausedLeg{BeadID: "low-paused", Rig: "gastown", Priority: PriorityLow, PausedAt: base.Add(time.Hour)})
+
+	if q.Add(QueuedLeg{BeadID: "normal", Rig: "gastown"}) {
+		t.Error("Add() queued a leg twice")
+	}
+
+	var got []string
+	for _, e := range q.Order("gastown") {
+		got = append(got, e.BeadID())
+	}
+	want := []string{"high-old", "high-new", "normal", "low-paused", "low-old"}
+	if len(got) != len(want) {
+		t.Fatalf("Order() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Order() = %v, want %v", got, want)
+		}
+	}
+
+	if rigs := q.Rigs(); len(rigs) != 2 || rigs[0] != "beads" || rigs[1] != "gastown" {
+		t.Errorf("Rigs() = %v, want [beads gastown]", rigs)
+	}
+
+	q.Remove("low-paused")
+	q.Remove("normal")
+	if q.Has("low-paused") || q.Has("normal") || !q.Has("low-old") {
+		t.Errorf("Remove() left queue %+v", q)
+	}
+}
+
+func TestUpdateQueue(t *testing.T) {
+	town := t.TempDir()
+	err := UpdateQueue(town, func(q *Queue) error {
+		q.Add(QueuedLeg{BeadID: "hq-leg-a", Rig: "gastown", Payload: "a.json"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateQueue() error: %v", err)
+	}
+
+	q, err := LoadQueue(town)
+	if err != nil {
+		t.Fatalf("LoadQueue() error: %v", err)
+	}
+	if len(q.Waiting) != 1 || q.Waiting[0].Priority != PriorityNormal || q.Waiting[0].QueuedAt.IsZero() {
+		t.Errorf("LoadQueue() = %+v, want one normal priority leg", q.Waiting)
+	}
+}
+
+func TestQueueHold(t *testing.T) {
+	q := &Queue{}
+	q.Add(QueuedLeg{BeadID: "waiting", Rig: "gastown"})
+	q.Paused = append(q.Paused, PausedLeg{BeadID: "paused", Rig: "gastown", Priority: PriorityLow})
+
+	if !q.SetHeld("waiting", true) || !q.SetHeld("paused", true) {
+		t.Fatal("SetHeld() didn't find a queued leg")
+	}
+	if q.SetHeld("missing", true) {
+		t.Error("SetHeld() found a leg that isn't queued")
+	}
+	for _, id := range []string{"waiting", "paused"} {
+		e, ok := q.Lookup(id)
+		if !ok || !e.Held() || e.State() != "held" {
+			t.Errorf("Lookup(%s) = %+v, %v; want a held leg", id, e, ok)
+		}
+	}
+
+	q.SetHeld("paused", false)
+	if e, _ := q.Lookup("paused"); e.State() != "paused" {
+		t.Errorf("released paused leg state = %q, want paused", e.State())
+	}
+}
+
+func TestQueueBackingOff(t *testing.T) {
+	now := time.Now()
+	q := &Queue{}
+	q.Add(QueuedLeg{BeadID: "retry", Rig: "gastown", NotBefore: now.Add(time.Minute)})
+	q.Add(QueuedLeg{BeadID: "fresh", Rig: "gastown"})
+
+	e, _ := q.Lookup("retry")
+	if !e.BackingOff(now) || e.State() != "backoff" {
+		t.Errorf("retry entry: BackingOff = %v, State = %q; want backing off", e.BackingOff(now), e.State())
+	}
+	if e.BackingOff(now.Add(2 * time.Minute)) {
+		t.Error("BackingOff() after NotBefore = true")
+	}
+	if e, _ := q.Lookup("fresh"); e.BackingOff(now) || e.State() != "waiting" {
+		t.Errorf("fresh entry state = %q, want waiting", e.State())
+	}
+}
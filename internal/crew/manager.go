@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/gitignore"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -27,6 +29,7 @@ var (
 	ErrInvalidCrewName = errors.New("invalid crew name")
 	ErrSessionRunning  = errors.New("session already running")
 	ErrSessionNotFound = errors.New("session not found")
+	ErrNoIdleCrew      = errors.New("no idle crew worker")
 )
 
 // StartOptions configures crew session startup.
@@ -129,7 +132,8 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 		return nil, fmt.Errorf("creating crew dir: %w", err)
 	}
 
-	// Clone the rig repo
+	// Clone the rig repo, as shallow/partial/sparse as its clone settings say
+	m.git.SetCloneOptions(rig.CloneOptions(m.rig.Path))
 	if m.rig.LocalRepo != "" {
 		if err := m.git.CloneWithReference(m.rig.GitURL, crewPath, m.rig.LocalRepo); err != nil {
 			fmt.Printf("Warning: could not clone with local repo reference: %v\n", err)
@@ -166,31 +170,10 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 		return nil, fmt.Errorf("creating mail dir: %w", err)
 	}
 
-	// Set up shared beads: crew uses rig's shared beads via redirect file
-	if err := m.setupSharedBeads(crewPath); err != nil {
-		// Non-fatal - crew can still work, warn but don't fail
-		fmt.Printf("Warning: could not set up shared beads: %v\n", err)
-	}
-
-	// Provision PRIME.md with Gas Town context for this worker.
-	// This is the fallback if SessionStart hook fails - ensures crew workers
-	// always have GUPP and essential Gas Town context.
-	if err := beads.ProvisionPrimeMDForWorktree(crewPath); err != nil {
-		// Non-fatal - crew can still work via hook, warn but don't fail
-		fmt.Printf("Warning: could not provision PRIME.md: %v\n", err)
-	}
-
-	// Copy overlay files from .runtime/overlay/ to crew root.
-	// This allows services to have .env and other config files at their root.
-	if err := rig.CopyOverlay(m.rig.Path, crewPath); err != nil {
-		// Non-fatal - log warning but continue
-		fmt.Printf("Warning: could not copy overlay files: %v\n", err)
-	}
-
-	// Ensure .gitignore has required Gas Town patterns
-	if err := rig.EnsureGitignorePatterns(crewPath); err != nil {
-		// Non-fatal - log warning but continue
-		fmt.Printf("Warning: could not update .gitignore: %v\n", err)
+	// Provision shared beads, PRIME.md, overlay files, and .gitignore.
+	// All non-fatal - crew can still work, warn but don't fail.
+	for _, warning := range m.provisionWorkspace(crewPath) {
+		fmt.Printf("Warning: %s\n", warning)
 	}
 
 	// NOTE: Slash commands (.claude/commands/) are provisioned at town level by gt install.
@@ -425,6 +408,120 @@ func (m *Manager) setupSharedBeads(crewPath string) error {
 	return beads.SetupRedirect(townRoot, crewPath)
 }
 
+// provisionWorkspace writes the standard Gas Town files into a crew clone:
+// the shared beads redirect, PRIME.md, overlay files, and the required
+// .gitignore patterns. Failures are returned as warnings since the crew
+// worker remains usable without them.
+func (m *Manager) provisionWorkspace(crewPath string) []string {
+	var warnings []string
+
+	// Set up shared beads: crew uses rig's shared beads via redirect file
+	if err := m.setupSharedBeads(crewPath); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not set up shared beads: %v", err))
+	}
+
+	// Provision PRIME.md with Gas Town context for this worker.
+	// This is the fallback if SessionStart hook fails - ensures crew workers
+	// always have GUPP and essential Gas Town context.
+	if err := beads.ProvisionPrimeMDForWorktree(crewPath); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not provision PRIME.md: %v", err))
+	}
+
+	// Copy overlay files from .runtime/overlay/ to crew root.
+	// This allows services to have .env and other config files at their root.
+	if err := rig.CopyOverlay(m.rig.Path, crewPath); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not copy overlay files: %v", err))
+	}
+
+	// Ensure .gitignore has required Gas Town patterns, both the worktree
+	// patterns and the town-wide managed block.
+	if err := rig.EnsureGitignorePatterns(crewPath); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not update .gitignore: %v", err))
+	} else if _, err := gitignore.Sync(filepath.Join(crewPath, ".gitignore"), gitignore.RequiredPatterns); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not update .gitignore: %v", err))
+	}
+
+	return warnings
+}
+
+// Sync re-applies the standard workspace files and shared runtime settings
+// to an existing crew worker and registers it if its state file is missing.
+// Use after upgrading gt or when a workspace was created by hand.
+func (m *Manager) Sync(name string) (*SyncResult, error) {
+	if err := validateCrewName(name); err != nil {
+		return nil, err
+	}
+	if !m.exists(name) {
+		return nil, ErrCrewNotFound
+	}
+
+	crewPath := m.crewDir(name)
+	result := &SyncResult{Name: name}
+
+	result.Warnings = m.provisionWorkspace(crewPath)
+
+	// Runtime settings live in crew/ (shared by all crew members), same as Start.
+	crewBaseDir := filepath.Join(m.rig.Path, "crew")
+	townRoot := filepath.Dir(m.rig.Path)
+	runtimeConfig := config.ResolveRoleAgentConfig("crew", townRoot, m.rig.Path)
+	if err := runtime.EnsureSettingsForRole(crewBaseDir, "crew", runtimeConfig); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("could not ensure runtime settings: %v", err))
+	}
+
+	// Register the worker if it has no state file (e.g., cloned by hand)
+	if _, err := os.Stat(m.stateFile(name)); os.IsNotExist(err) {
+		now := time.Now()
+		worker := &CrewWorker{
+			Name:      name,
+			Rig:       m.rig.Name,
+			ClonePath: crewPath,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if branch, err := git.NewGit(crewPath).CurrentBranch(); err == nil {
+			worker.Branch = branch
+		}
+		if err := m.saveState(worker); err != nil {
+			return nil, fmt.Errorf("saving state: %w", err)
+		}
+		result.Registered = true
+	}
+
+	return result, nil
+}
+
+// SyncResult captures the results of a sync operation.
+type SyncResult struct {
+	Name       string   `json:"name"`
+	Registered bool     `json:"registered"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// PickIdle returns a crew worker that can take dispatched work: one with a
+// running session and a clean working tree. Workers are considered in name
+// order so dispatch is deterministic. Returns ErrNoIdleCrew if none qualify.
+func (m *Manager) PickIdle() (*CrewWorker, error) {
+	workers, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(workers, func(i, j int) bool { return workers[i].Name < workers[j].Name })
+
+	for _, w := range workers {
+		if running, err := m.IsRunning(w.Name); err != nil || !running {
+			continue
+		}
+		hasChanges, err := git.NewGit(m.crewDir(w.Name)).HasUncommittedChanges()
+		if err != nil || hasChanges {
+			continue
+		}
+		return w, nil
+	}
+
+	return nil, ErrNoIdleCrew
+}
+
 // SessionName returns the tmux session name for a crew member.
 func (m *Manager) SessionName(name string) string {
 	return fmt.Sprintf("gt-%s-crew-%s", m.rig.Name, name)
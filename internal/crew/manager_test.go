@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/git"
@@ -397,3 +398,48 @@ func runCmd(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	return cmd.Run()
 }
+
+func TestManagerSyncRegistersAndWritesGitignore(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	rigPath := filepath.Join(tmpDir, "test-rig")
+	crewDir := filepath.Join(rigPath, "crew", "emma")
+	if err := os.MkdirAll(crewDir, 0755); err != nil {
+		t.Fatalf("failed to create crew dir: %v", err)
+	}
+
+	r := &rig.Rig{Name: "test-rig", Path: rigPath}
+	mgr := NewManager(r, git.NewGit(rigPath))
+
+	result, err := mgr.Sync("emma")
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !result.Registered {
+		t.Error("expected hand-created workspace to be registered")
+	}
+	if _, err := os.Stat(filepath.Join(crewDir, "state.json")); err != nil {
+		t.Errorf("state.json was not created: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(crewDir, ".gitignore"))
+	if err != nil {
+		t.Fatalf("reading .gitignore: %v", err)
+	}
+	if !strings.Contains(string(data), "# gastown:begin") {
+		t.Errorf(".gitignore missing managed block:\n%s", data)
+	}
+
+	// Second sync is a no-op for registration
+	result, err = mgr.Sync("emma")
+	if err != nil {
+		t.Fatalf("second Sync failed: %v", err)
+	}
+	if result.Registered {
+		t.Error("expected existing workspace not to be re-registered")
+	}
+
+	if _, err := mgr.Sync("nobody"); err != ErrCrewNotFound {
+		t.Errorf("Sync(nobody) error = %v, want ErrCrewNotFound", err)
+	}
+}
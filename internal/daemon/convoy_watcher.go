@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // ConvoyWatcher monitors bd activity for issue closes and triggers convoy completion checks.
@@ -161,7 +163,7 @@ func (w *ConvoyWatcher) getTrackingConvoys(issueID string) []string {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return nil
 	}
 
@@ -189,7 +191,7 @@ func (w *ConvoyWatcher) checkConvoyCompletion(convoyID string) {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := cmdtrace.Run(showCmd); err != nil {
 		return
 	}
 
@@ -215,7 +217,7 @@ func (w *ConvoyWatcher) checkConvoyCompletion(convoyID string) {
 	checkCmd.Stdout = &checkStdout
 	checkCmd.Stderr = &checkStderr
 
-	if err := checkCmd.Run(); err != nil {
+	if err := cmdtrace.Run(checkCmd); err != nil {
 		w.logger("convoy watcher: gt convoy check failed: %v: %s", err, checkStderr.String())
 		return
 	}
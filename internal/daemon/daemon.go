@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -12,17 +13,23 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/logs"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -48,6 +55,11 @@ type Daemon struct {
 	convoyWatcher *ConvoyWatcher
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	healthServer  *http.Server
+
+	// lastTick is the last completed heartbeat (UnixNano), read by the
+	// health endpoint goroutine.
+	lastTick atomic.Int64
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -192,6 +204,9 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Serve /healthz and /readyz if configured
+	d.startHealthServer()
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -242,6 +257,18 @@ func (d *Daemon) heartbeat(state *State) {
 		return
 	}
 
+	// Skip patrols during a maintenance window (settings/config.json).
+	// The heartbeat is still recorded so liveness checks stay green.
+	if w, end := maintenance.Active(d.config.TownRoot, time.Now()); w != nil {
+		if end.IsZero() {
+			d.logger.Printf("Maintenance window %s open, skipping heartbeat", w.Label())
+		} else {
+			d.logger.Printf("Maintenance window %s open until %s, skipping heartbeat", w.Label(), end.Format(time.RFC3339))
+		}
+		d.recordHeartbeat(state)
+		return
+	}
+
 	d.logger.Println("Heartbeat starting (recovery-focused)")
 
 	// 0. Ensure Dolt server is running (if configured)
@@ -324,14 +351,73 @@ func (d *Daemon) heartbeat(state *State) {
 	// If they have local .beads with databases, bd uses the wrong database.
 	d.cleanupTownServiceBeads()
 
-	// Update state
+	// 14. Refill worktree pools so new polecats skip cold checkouts.
+	d.fillWorktreePools()
+
+	// 15. Start runs queued during the last maintenance window.
+	d.runMaintenanceQueue()
+
+	// 16. Hand free polecat slots to queued formula legs.
+	d.dispatchLegQueue()
+
+	d.recordHeartbeat(state)
+	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
+}
+
+// recordHeartbeat updates and saves the heartbeat state.
+func (d *Daemon) recordHeartbeat(state *State) {
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
+	d.tick(state.LastHeartbeat)
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
+}
 
-	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
+// runMaintenanceQueue starts the runs queued during a maintenance window,
+// each in the directory it was queued from. Runs stay queued while the
+// town is paused (gt pause).
+func (d *Daemon) runMaintenanceQueue() {
+	if paused, _, _ := mayor.IsTownPaused(d.config.TownRoot); paused {
+		return
+	}
+	runs, err := maintenance.Take(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Error reading maintenance queue: %v", err)
+		return
+	}
+	for _, run := range runs {
+		cmd := exec.Command("gt", run.Args...) //nolint:gosec // G204: args were queued by gt itself
+		cmd.Dir = run.Dir
+		if err := cmd.Start(); err != nil {
+			d.logger.Printf("Error starting queued run gt %s: %v", strings.Join(run.Args, " "), err)
+			continue
+		}
+		d.logger.Printf("Started run queued during maintenance window %s: gt %s", run.Window, strings.Join(run.Args, " "))
+		go func() { _ = cmd.Wait() }()
+	}
+}
+
+// dispatchLegQueue runs gt convoy dispatch when formula legs are waiting
+// for polecats, so legs queued behind finished work don't wait for the
+// next convoy check. Legs stay queued while the town is paused.
+func (d *Daemon) dispatchLegQueue() {
+	if paused, _, _ := mayor.IsTownPaused(d.config.TownRoot); paused {
+		return
+	}
+	q, err := convoy.LoadQueue(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Error reading leg queue: %v", err)
+		return
+	}
+	if q.Empty() {
+		return
+	}
+	cmd := exec.Command("gt", "convoy", "dispatch")
+	cmd.Dir = d.config.TownRoot
+	if out, err := cmdtrace.CombinedOutput(cmd); err != nil {
+		d.logger.Printf("Error dispatching leg queue: %v: %s", err, strings.TrimSpace(string(out)))
+	}
 }
 
 // ensureDoltServerRunning ensures the Dolt SQL server is running if configured.
@@ -657,6 +743,28 @@ func (d *Daemon) getKnownRigs() []string {
 	return rigs
 }
 
+// fillWorktreePools tops up each operational rig's pool of ready polecat
+// worktrees (see polecat.WorktreePool). Rigs without a pool are skipped.
+func (d *Daemon) fillWorktreePools() {
+	for _, rigName := range d.getKnownRigs() {
+		pool := polecat.NewWorktreePool(filepath.Join(d.config.TownRoot, rigName))
+		if pool.Size() == 0 && len(pool.Ready()) == 0 {
+			continue
+		}
+		if ok, _ := d.isRigOperational(rigName); !ok {
+			continue
+		}
+		added, err := pool.Fill()
+		if err != nil {
+			d.logger.Printf("Warning: filling worktree pool for %s: %v", rigName, err)
+			continue
+		}
+		if added > 0 {
+			d.logger.Printf("Added %d worktree(s) to %s's pool", added, rigName)
+		}
+	}
+}
+
 // getPatrolRigs returns the list of rigs for a patrol.
 // If the patrol config specifies a rigs filter, only those rigs are returned.
 // Otherwise, all known rigs are returned.
@@ -948,6 +1056,8 @@ func (d *Daemon) killBeadsDaemon(beadsDir string) {
 func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return kept for future use
 	d.logger.Println("Daemon shutting down")
 
+	d.stopHealthServer()
+
 	// Stop feed curator
 	if d.curator != nil {
 		d.curator.Stop()
@@ -1247,14 +1357,31 @@ func listPolecatWorktrees(polecatsDir string) ([]string, error) {
 	return polecats, nil
 }
 
+// polecatSessions returns the session backend polecats run in.
+func (d *Daemon) polecatSessions() session.Backend {
+	return session.OpenBackend(d.config.TownRoot, d.tmux)
+}
+
+// polecatAgentAlive reports whether a polecat session is running its agent.
+// Only tmux can see the agent process in the pane; other backends' sessions
+// end with the agent, so a live session is a live agent.
+func (d *Daemon) polecatAgentAlive(sessionName string) bool {
+	backend := d.polecatSessions()
+	if backend.Type() == session.BackendTmux {
+		return d.tmux.IsAgentAlive(sessionName)
+	}
+	alive, err := backend.HasSession(sessionName)
+	return err == nil && alive
+}
+
 // checkPolecatHealth checks a single polecat's session health.
-// If the polecat has work-on-hook but the tmux session is dead, it's restarted.
+// If the polecat has work-on-hook but its session is dead, it's restarted.
 func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
-	// Build the expected tmux session name
+	// Build the expected session name
 	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
 
-	// Check if tmux session exists
-	sessionAlive, err := d.tmux.HasSession(sessionName)
+	// Check if the session exists in the configured backend
+	sessionAlive, err := d.polecatSessions().HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking session %s: %v", sessionName, err)
 		return
@@ -1377,6 +1504,24 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Pre-sync workspace (ensure beads are current)
 	d.syncWorkspace(workDir)
 
+	// Other backends have no session env, themes, or pane hooks; the
+	// startup command carries the env.
+	if backend := d.polecatSessions(); backend.Type() != session.BackendTmux {
+		envVars := config.AgentEnv(config.AgentEnvConfig{
+			Role:          "polecat",
+			Rig:           rigName,
+			AgentName:     polecatName,
+			TownRoot:      d.config.TownRoot,
+			BeadsNoDaemon: true,
+		})
+		startCmd := config.BuildStartupCommand(envVars, rigPath, "")
+		logPath := logs.SessionLogPath(d.config.TownRoot, fmt.Sprintf("%s/polecats/%s", rigName, polecatName))
+		if err := backend.NewSession(sessionName, workDir, startCmd, logPath); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		return nil
+	}
+
 	// Create new tmux session
 	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
 	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
@@ -1437,7 +1582,7 @@ Manual intervention may be required.`,
 	cmd := exec.Command("gt", "mail", "send", witnessAddr, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find gt executable
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		d.logger.Printf("Warning: failed to notify witness of crashed polecat: %v", err)
 	}
 }
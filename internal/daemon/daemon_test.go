@@ -2,13 +2,19 @@ package daemon
 
 import (
 	"encoding/json"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"testing"
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/session"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -351,3 +357,77 @@ func TestIsShutdownInProgress_ActiveLock(t *testing.T) {
 		t.Errorf("lock file should still exist: %v", err)
 	}
 }
+
+func TestRunMaintenanceQueue(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as gt")
+	}
+	townRoot := t.TempDir()
+	binDir := t.TempDir()
+	marker := filepath.Join(townRoot, "ran")
+	script := "#!/bin/sh\necho \"$@\" > " + marker + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "gt"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	d := &Daemon{
+		config: &Config{TownRoot: townRoot},
+		logger: log.New(io.Discard, "", 0),
+	}
+	if err := maintenance.Enqueue(townRoot, maintenance.Run{Args: []string{"plugin", "run", "rebuild-gt"}, Dir: townRoot}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A paused town keeps its queue.
+	if err := mayor.PauseTown(townRoot, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	d.runMaintenanceQueue()
+	if runs, _ := maintenance.Pending(townRoot); len(runs) != 1 {
+		t.Fatalf("queue while paused = %v, want 1 run", runs)
+	}
+
+	if err := mayor.ResumeTown(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	d.runMaintenanceQueue()
+	if runs, _ := maintenance.Pending(townRoot); len(runs) != 0 {
+		t.Errorf("queue after run = %v, want empty", runs)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(marker)
+		if err == nil && string(data) == "plugin run rebuild-gt\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queued run did not execute: %q, %v", data, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestPolecatAgentAliveUsesSessionBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	townRoot := t.TempDir()
+	t.Setenv("GT_SESSION_BACKEND", "process")
+	d := &Daemon{config: &Config{TownRoot: townRoot}}
+
+	sessions := d.polecatSessions()
+	if sessions.Type() != session.BackendProcess {
+		t.Fatalf("backend = %s, want process", sessions.Type())
+	}
+	if d.polecatAgentAlive("gt-rig-toast") {
+		t.Fatal("no session started, want not alive")
+	}
+	if err := sessions.NewSession("gt-rig-toast", townRoot, "exec sleep 30", ""); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	t.Cleanup(func() { _ = sessions.KillSession("gt-rig-toast") })
+	if !d.polecatAgentAlive("gt-rig-toast") {
+		t.Error("process session running, want alive")
+	}
+}
@@ -0,0 +1,246 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// HealthConfig exposes /healthz and /readyz over HTTP so the daemon can be
+// supervised by systemd, Kubernetes, or a load balancer.
+type HealthConfig struct {
+	// Listen is the address to serve on, e.g. "127.0.0.1:9464".
+	Listen string `json:"listen"`
+
+	// MaxQueueDepth fails readiness when more dispatched work than this is
+	// waiting for an agent to pick it up. 0 means no limit.
+	MaxQueueDepth int `json:"max_queue_depth,omitempty"`
+}
+
+// schedulerStaleAfter is how long without a completed heartbeat before the
+// scheduler is considered stuck: two missed intervals plus slack for a
+// slow heartbeat.
+const schedulerStaleAfter = 2*recoveryHeartbeatInterval + time.Minute
+
+// HealthCheck is the result of one health probe.
+type HealthCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// HealthReport is the body of a /healthz or /readyz response.
+type HealthReport struct {
+	Status string        `json:"status"` // "ok" or "fail"
+	Checks []HealthCheck `json:"checks"`
+}
+
+// OK reports whether every check passed.
+func (r HealthReport) OK() bool {
+	return r.Status == "ok"
+}
+
+// HealthChecker runs the daemon's liveness and readiness probes.
+type HealthChecker struct {
+	TownRoot      string
+	MaxQueueDepth int
+
+	// LastTick returns when the scheduler last completed a heartbeat; zero
+	// if it has not run yet.
+	LastTick func() time.Time
+
+	// Started, if set, gives the scheduler until schedulerStaleAfter past
+	// this time to complete its first heartbeat.
+	Started time.Time
+
+	// Probes, replaceable in tests.
+	queueDepth   func() (int, error)
+	resolveAgent func() error
+}
+
+// NewHealthChecker creates a checker for a town. lastTick reports the
+// scheduler's last completed heartbeat.
+func NewHealthChecker(townRoot string, maxQueueDepth int, lastTick func() time.Time) *HealthChecker {
+	h := &HealthChecker{TownRoot: townRoot, MaxQueueDepth: maxQueueDepth, LastTick: lastTick}
+	h.queueDepth = h.hookedWork
+	h.resolveAgent = h.agentResolvable
+	return h
+}
+
+// Liveness reports whether the scheduler is still ticking. A stuck
+// scheduler means the process should be restarted.
+func (h *HealthChecker) Liveness() HealthReport {
+	return newHealthReport(h.checkScheduler())
+}
+
+// Readiness reports whether the town can accept work: the scheduler is
+// ticking, beads are reachable, the default agent resolves, and the
+// dispatch queue is within bounds.
+func (h *HealthChecker) Readiness() HealthReport {
+	checks := []HealthCheck{h.checkScheduler()}
+
+	depth, err := h.queueDepth()
+	if err != nil {
+		checks = append(checks,
+			HealthCheck{Name: "beads", Detail: err.Error()},
+			HealthCheck{Name: "queue", Detail: "unknown: beads unreachable"})
+	} else {
+		queue := HealthCheck{Name: "queue", OK: true, Detail: fmt.Sprintf("%d dispatched, waiting", depth)}
+		if h.MaxQueueDepth > 0 && depth > h.MaxQueueDepth {
+			queue.OK = false
+			queue.Detail = fmt.Sprintf("%d dispatched, waiting (max %d)", depth, h.MaxQueueDepth)
+		}
+		checks = append(checks, HealthCheck{Name: "beads", OK: true}, queue)
+	}
+
+	agent := HealthCheck{Name: "agent", OK: true}
+	if err := h.resolveAgent(); err != nil {
+		agent.Detail = err.Error()
+		agent.OK = false
+	}
+	checks = append(checks, agent)
+
+	return newHealthReport(checks...)
+}
+
+func newHealthReport(checks ...HealthCheck) HealthReport {
+	r := HealthReport{Status: "ok", Checks: checks}
+	for _, c := range checks {
+		if !c.OK {
+			r.Status = "fail"
+		}
+	}
+	return r
+}
+
+func (h *HealthChecker) checkScheduler() HealthCheck {
+	check := HealthCheck{Name: "scheduler"}
+	last := time.Time{}
+	if h.LastTick != nil {
+		last = h.LastTick()
+	}
+	if last.IsZero() {
+		check.Detail = "no heartbeat yet"
+		check.OK = !h.Started.IsZero() && time.Since(h.Started) <= schedulerStaleAfter
+		return check
+	}
+	age := time.Since(last)
+	check.Detail = fmt.Sprintf("last heartbeat %s ago", age.Round(time.Second))
+	check.OK = age <= schedulerStaleAfter
+	return check
+}
+
+// hookedWork counts town beads dispatched to an agent but not yet started.
+// Doubles as the beads reachability probe.
+func (h *HealthChecker) hookedWork() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "bd", "--no-daemon", "list", "--status=hooked", "--json")
+	cmd.Dir = h.TownRoot
+	out, err := cmdtrace.Output(cmd)
+	if err != nil {
+		return 0, fmt.Errorf("bd list: %w", err)
+	}
+	var issues []json.RawMessage
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return 0, fmt.Errorf("parsing bd list: %w", err)
+	}
+	return len(issues), nil
+}
+
+// agentResolvable checks that the town's default agent resolves to an
+// installed command.
+func (h *HealthChecker) agentResolvable() error {
+	rc := config.ResolveAgentConfig(h.TownRoot, "")
+	if rc == nil || rc.Command == "" {
+		return errors.New("no agent configured")
+	}
+	if _, err := exec.LookPath(rc.Command); err != nil {
+		return fmt.Errorf("agent command %q not found", rc.Command)
+	}
+	return nil
+}
+
+// Handler serves /healthz (liveness) and /readyz (readiness) as JSON,
+// with status 200 when healthy and 503 otherwise.
+func (h *HealthChecker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, h.Liveness())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, h.Readiness())
+	})
+	return mux
+}
+
+func writeHealthReport(w http.ResponseWriter, r HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !r.OK() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(r)
+}
+
+// StateLastTick reads the last heartbeat from the daemon state file, for
+// health checks served outside the daemon process (e.g. gt dashboard).
+func StateLastTick(townRoot string) func() time.Time {
+	return func() time.Time {
+		state, err := LoadState(townRoot)
+		if err != nil || !state.Running {
+			return time.Time{}
+		}
+		return state.LastHeartbeat
+	}
+}
+
+// startHealthServer serves health endpoints if configured in daemon.json.
+func (d *Daemon) startHealthServer() {
+	if d.patrolConfig == nil || d.patrolConfig.Health == nil || d.patrolConfig.Health.Listen == "" {
+		return
+	}
+	cfg := d.patrolConfig.Health
+	checker := NewHealthChecker(d.config.TownRoot, cfg.MaxQueueDepth, func() time.Time {
+		if n := d.lastTick.Load(); n != 0 {
+			return time.Unix(0, n)
+		}
+		return time.Time{}
+	})
+	checker.Started = time.Now()
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		d.logger.Printf("Warning: health endpoint disabled: %v", err)
+		return
+	}
+	d.healthServer = &http.Server{Handler: checker.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := d.healthServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("Health endpoint stopped: %v", err)
+		}
+	}()
+	d.logger.Printf("Health endpoints on http://%s/healthz and /readyz", ln.Addr())
+}
+
+// tick records a completed heartbeat for the liveness probe.
+func (d *Daemon) tick(at time.Time) {
+	d.lastTick.Store(at.UnixNano())
+}
+
+// stopHealthServer shuts down the health endpoint, if running.
+func (d *Daemon) stopHealthServer() {
+	if d.healthServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = d.healthServer.Shutdown(ctx)
+}
@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testHealthChecker returns a checker with stubbed probes.
+func testHealthChecker(lastTick time.Time, depth int, depthErr, agentErr error) *HealthChecker {
+	h := NewHealthChecker("/tmp/town", 10, func() time.Time { return lastTick })
+	h.queueDepth = func() (int, error) { return depth, depthErr }
+	h.resolveAgent = func() error { return agentErr }
+	return h
+}
+
+func TestHealthLiveness(t *testing.T) {
+	if r := testHealthChecker(time.Now().Add(-time.Minute), 0, nil, nil).Liveness(); !r.OK() {
+		t.Errorf("recent heartbeat: %+v", r)
+	}
+	if r := testHealthChecker(time.Now().Add(-time.Hour), 0, nil, nil).Liveness(); r.OK() {
+		t.Errorf("stale heartbeat should fail: %+v", r)
+	}
+
+	h := testHealthChecker(time.Time{}, 0, nil, nil)
+	if r := h.Liveness(); r.OK() {
+		t.Errorf("no heartbeat and no start time should fail: %+v", r)
+	}
+	h.Started = time.Now()
+	if r := h.Liveness(); !r.OK() {
+		t.Errorf("just started should pass: %+v", r)
+	}
+}
+
+func TestHealthReadiness(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		h        *HealthChecker
+		ok       bool
+		failName string
+	}{
+		{"healthy", testHealthChecker(now, 3, nil, nil), true, ""},
+		{"beads down", testHealthChecker(now, 0, errors.New("bd list: exit 1"), nil), false, "beads"},
+		{"queue backed up", testHealthChecker(now, 11, nil, nil), false, "queue"},
+		{"agent missing", testHealthChecker(now, 0, nil, errors.New(`agent command "claude" not found`)), false, "agent"},
+	}
+	for _, tt := range tests {
+		r := tt.h.Readiness()
+		if r.OK() != tt.ok {
+			t.Errorf("%s: status = %s, want ok=%v", tt.name, r.Status, tt.ok)
+		}
+		for _, c := range r.Checks {
+			if c.Name == tt.failName && c.OK {
+				t.Errorf("%s: check %s passed, want failure", tt.name, c.Name)
+			}
+		}
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	h := testHealthChecker(time.Now(), 0, errors.New("unreachable"), nil)
+	srv := httptest.NewServer(h.Handler())
+	defer srv.Close()
+
+	for path, want := range map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusServiceUnavailable} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report HealthReport
+		err = json.NewDecoder(resp.Body).Decode(&report)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: decoding: %v", path, err)
+		}
+		if resp.StatusCode != want || len(report.Checks) == 0 {
+			t.Errorf("%s: status %d, report %+v; want %d", path, resp.StatusCode, report, want)
+		}
+	}
+}
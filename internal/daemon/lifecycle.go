@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -42,7 +43,7 @@ func (d *Daemon) ProcessLifecycleRequests() {
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find gt executable
 
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		d.logger.Printf("Warning: failed to fetch deacon inbox: %v", err)
 		return
@@ -171,8 +172,15 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 		}
 	}
 
-	// Check if session exists (tmux detection still needed for lifecycle actions)
-	running, err := d.tmux.HasSession(sessionName)
+	// Polecats run in the town's session backend; other agents in tmux
+	var sessions session.Backend = &session.TmuxBackend{Tmux: d.tmux}
+	var polecat *ParsedIdentity
+	if parsed, err := parseIdentity(request.From); err == nil && parsed.RoleType == "polecat" {
+		sessions, polecat = d.polecatSessions(), parsed
+	}
+
+	// Check if session exists (session detection still needed for lifecycle actions)
+	running, err := sessions.HasSession(sessionName)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -180,9 +188,9 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 	switch request.Action {
 	case ActionShutdown:
 		if running {
-			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
+			// KillSession kills all descendant processes too.
 			// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-			if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+			if err := sessions.KillSession(sessionName); err != nil {
 				return fmt.Errorf("killing session: %w", err)
 			}
 			d.logger.Printf("Killed session %s", sessionName)
@@ -191,8 +199,8 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 
 	case ActionCycle, ActionRestart:
 		if running {
-			// Kill the session first - use KillSessionWithProcesses to prevent orphan processes.
-			if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+			// Kill the session first, with its processes, to prevent orphans.
+			if err := sessions.KillSession(sessionName); err != nil {
 				return fmt.Errorf("killing session: %w", err)
 			}
 			d.logger.Printf("Killed session %s for restart", sessionName)
@@ -202,7 +210,12 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 		}
 
 		// Restart the session
-		if err := d.restartSession(sessionName, request.From); err != nil {
+		if polecat != nil && sessions.Type() != session.BackendTmux {
+			err = d.restartPolecatSession(polecat.RigName, polecat.AgentName, sessionName)
+		} else {
+			err = d.restartSession(sessionName, request.From)
+		}
+		if err != nil {
 			return fmt.Errorf("restarting session: %w", err)
 		}
 		d.logger.Printf("Restarted session %s", sessionName)
@@ -617,7 +630,7 @@ func (d *Daemon) closeMessage(id string) error {
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find gt executable
 
-	output, err := cmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("gt mail delete %s: %v (output: %s)", id, err, string(output))
 	}
@@ -655,7 +668,7 @@ func (d *Daemon) getAgentBeadInfo(agentBeadID string) (*AgentBeadInfo, error) {
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find bd executable
 
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("bd show %s: %w", agentBeadID, err)
 	}
@@ -793,7 +806,7 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find bd executable
 
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		d.logger.Printf("Warning: bd list failed for GUPP check: %v", err)
 		return
@@ -833,8 +846,8 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 		polecatName := strings.TrimPrefix(agent.ID, prefix)
 		sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
 
-		// Check if tmux session exists and agent is running
-		if d.tmux.IsAgentAlive(sessionName) {
+		// Check if the session exists and agent is running
+		if d.polecatAgentAlive(sessionName) {
 			// Session is alive - check if it's been stuck too long
 			updatedAt, err := time.Parse(time.RFC3339, agent.UpdatedAt)
 			if err != nil {
@@ -869,7 +882,7 @@ Action needed: Check if agent is alive and responsive. Consider restarting if st
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find gt executable
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		d.logger.Printf("Warning: failed to notify witness of GUPP violation: %v", err)
 	} else {
 		d.logger.Printf("Notified %s of GUPP violation for %s", witnessAddr, agentID)
@@ -893,7 +906,7 @@ func (d *Daemon) checkRigOrphanedWork(rigName string) {
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find bd executable
 
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		d.logger.Printf("Warning: bd list failed for orphaned work check: %v", err)
 		return
@@ -928,7 +941,7 @@ func (d *Daemon) checkRigOrphanedWork(rigName string) {
 		sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
 
 		// Session running = not orphaned (work is being processed)
-		if d.tmux.IsAgentAlive(sessionName) {
+		if d.polecatAgentAlive(sessionName) {
 			continue
 		}
 
@@ -967,7 +980,7 @@ Action needed: Either restart the agent or reassign the work.`,
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find gt executable
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		d.logger.Printf("Warning: failed to notify witness of orphaned work: %v", err)
 	} else {
 		d.logger.Printf("Notified %s of orphaned work for %s", witnessAddr, agentID)
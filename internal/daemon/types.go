@@ -125,6 +125,7 @@ type DaemonPatrolConfig struct {
 	Version   int            `json:"version"`
 	Heartbeat *PatrolConfig  `json:"heartbeat,omitempty"`
 	Patrols   *PatrolsConfig `json:"patrols,omitempty"`
+	Health    *HealthConfig  `json:"health,omitempty"`
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -125,7 +126,7 @@ func listHookedBeads(townRoot string) ([]*HookedBead, error) {
 	cmd := exec.Command("bd", "list", "--status=hooked", "--json", "--limit=0")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		// No hooked beads is not an error
 		if strings.Contains(string(output), "no issues found") {
@@ -191,5 +192,5 @@ func assigneeToSessionName(assignee string) string {
 func unhookBead(townRoot, beadID string) error {
 	cmd := exec.Command("bd", "update", beadID, "--status=open")
 	cmd.Dir = townRoot
-	return cmd.Run()
+	return cmdtrace.Run(cmd)
 }
@@ -59,3 +59,22 @@ func TestCheckBeads(t *testing.T) {
 
 	t.Logf("CheckBeads: status=%d, version=%s", status, version)
 }
+
+func TestCheckFormulaFeatures(t *testing.T) {
+	tests := []struct {
+		version string
+		schema  int
+		want    int
+	}{
+		{"0.46.0", 2, 0},
+		{"0.45.0", 1, 0},
+		{"0.45.0", 2, 1},
+		{"0.42.0", 1, 2},
+		{"1.0.0", 2, 1},
+	}
+	for _, tt := range tests {
+		if got := CheckFormulaFeatures(tt.version, tt.schema); len(got) != tt.want {
+			t.Errorf("CheckFormulaFeatures(%q, %d) = %v, want %d mismatch(es)", tt.version, tt.schema, got, tt.want)
+		}
+	}
+}
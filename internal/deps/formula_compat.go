@@ -0,0 +1,74 @@
+package deps
+
+import "fmt"
+
+// FormulaFeature is something gt relies on bd for when it hands a formula
+// over to bd instead of running it natively, and the bd releases that
+// provide it.
+type FormulaFeature struct {
+	Name     string   // What gt needs from bd
+	MinBD    string   // Oldest bd that provides it; "" for any
+	BeforeBD string   // First bd that no longer provides it; "" for none
+	Schema   int      // Only needed for formulas on this schema or newer; 0 for all
+	Commands []string // gt commands that hand formulas to bd this way
+}
+
+// FormulaFeatures is the compatibility table between gt's formula handling
+// and bd. gt formula run/show/list/render parse formulas natively, but
+// gt sling and patrol spawning delegate to bd cook and bd mol wisp, so a bd
+// that is too old or too new for gt's formulas breaks only those commands.
+// Add a row when a bd release changes what those commands depend on.
+var FormulaFeatures = []FormulaFeature{
+	{
+		Name:     "bd cook compiles formulas into protos",
+		MinBD:    "0.43.0",
+		Commands: []string{"gt sling <formula>", "gt sling <formula> --on <bead>"},
+	},
+	{
+		Name:     "bd mol wisp instantiates formulas as wisps",
+		MinBD:    "0.44.0",
+		Commands: []string{"gt sling <formula>", "gt sling <formula> --on <bead>", "gt prime (patrol wisps)"},
+	},
+	{
+		Name:     "bd cook reads schema 2 formulas",
+		MinBD:    "0.46.0",
+		Schema:   2,
+		Commands: []string{"gt sling <formula>", "gt sling <formula> --on <bead>", "gt prime (patrol wisps)"},
+	},
+	{
+		Name:     "bd cook emits the proto format gt reads back",
+		BeforeBD: "1.0.0",
+		Commands: []string{"gt sling <formula> --on <bead>", "gt prime (patrol wisps)"},
+	},
+}
+
+// FormulaMismatch is a FormulaFeature the installed bd doesn't provide.
+type FormulaMismatch struct {
+	Feature FormulaFeature
+	Reason  string
+}
+
+// CheckFormulaFeatures returns the features of FormulaFeatures that bd
+// version does not provide, where schema is the newest formula schema in
+// use.
+func CheckFormulaFeatures(version string, schema int) []FormulaMismatch {
+	var mismatches []FormulaMismatch
+	for _, f := range FormulaFeatures {
+		if f.Schema > 0 && schema < f.Schema {
+			continue
+		}
+		switch {
+		case f.MinBD != "" && compareVersions(version, f.MinBD) < 0:
+			mismatches = append(mismatches, FormulaMismatch{
+				Feature: f,
+				Reason:  fmt.Sprintf("bd %s is older than %s", version, f.MinBD),
+			})
+		case f.BeforeBD != "" && compareVersions(version, f.BeforeBD) >= 0:
+			mismatches = append(mismatches, FormulaMismatch{
+				Feature: f,
+				Reason:  fmt.Sprintf("bd %s is newer than this gt supports (before %s)", version, f.BeforeBD),
+			})
+		}
+	}
+	return mismatches
+}
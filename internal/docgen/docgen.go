@@ -0,0 +1,266 @@
+// Package docgen renders gt's offline documentation, man pages and a
+// browsable markdown reference, from the cobra command tree and from the
+// Go types behind formula and config files.
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/fsx"
+)
+
+// Reference is a non-command page, such as a file schema. It becomes a
+// section 5 man page and a markdown page.
+type Reference struct {
+	Name     string // page name, e.g. "gt-formula-schema"; must not match a command
+	Title    string // one-line summary
+	Intro    string
+	Sections []Section
+}
+
+// Section is one part of a Reference: free text followed by a field table.
+type Section struct {
+	Heading string
+	Text    string
+	Fields  []Field
+}
+
+// Format selects which documentation Generate writes.
+type Format int
+
+const (
+	// FormatMan writes man1/ and man5/ pages.
+	FormatMan Format = 1 << iota
+	// FormatMarkdown writes markdown/ pages and an index.
+	FormatMarkdown
+
+	// FormatAll writes everything.
+	FormatAll = FormatMan | FormatMarkdown
+)
+
+// Commands returns root and every available (non-hidden, non-help)
+// command beneath it, depth first.
+func Commands(root *cobra.Command) []*cobra.Command {
+	cmds := []*cobra.Command{root}
+	for _, c := range root.Commands() {
+		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		cmds = append(cmds, Commands(c)...)
+	}
+	return cmds
+}
+
+// PageName returns the man page name of a command, e.g. "gt-formula-list".
+func PageName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+}
+
+// Generate writes the documentation for root and refs under dir and
+// returns the paths written. version is shown in man page footers.
+func Generate(root *cobra.Command, refs []Reference, dir string, format Format, version string) ([]string, error) {
+	var written []string
+	write := func(path string, render func(io.Writer)) error {
+		var buf bytes.Buffer
+		render(&buf)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := fsx.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		written = append(written, path)
+		return nil
+	}
+
+	cmds := Commands(root)
+	for _, r := range refs {
+		for _, c := range cmds {
+			if PageName(c) == r.Name {
+				return nil, fmt.Errorf("reference page %s has the same name as a command page", r.Name)
+			}
+		}
+	}
+	if format&FormatMan != 0 {
+		for _, c := range cmds {
+			path := filepath.Join(dir, "man1", PageName(c)+".1")
+			if err := write(path, func(w io.Writer) { CommandMan(w, c, version) }); err != nil {
+				return written, err
+			}
+		}
+		for _, r := range refs {
+			path := filepath.Join(dir, "man5", r.Name+".5")
+			if err := write(path, func(w io.Writer) { ReferenceMan(w, r, version) }); err != nil {
+				return written, err
+			}
+		}
+	}
+	if format&FormatMarkdown != 0 {
+		for _, c := range cmds {
+			path := filepath.Join(dir, "markdown", PageName(c)+".md")
+			if err := write(path, func(w io.Writer) { CommandMarkdown(w, c) }); err != nil {
+				return written, err
+			}
+		}
+		for _, r := range refs {
+			path := filepath.Join(dir, "markdown", r.Name+".md")
+			if err := write(path, func(w io.Writer) { ReferenceMarkdown(w, r) }); err != nil {
+				return written, err
+			}
+		}
+		path := filepath.Join(dir, "markdown", "README.md")
+		if err := write(path, func(w io.Writer) { indexMarkdown(w, root, cmds, refs) }); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// CommandMarkdown renders one command's markdown page.
+func CommandMarkdown(w io.Writer, cmd *cobra.Command) {
+	fmt.Fprintf(w, "# %s\n\n", cmd.CommandPath())
+	if cmd.Short != "" {
+		fmt.Fprintf(w, "%s\n\n", cmd.Short)
+	}
+	fmt.Fprintf(w, "## Synopsis\n\n```\n%s\n```\n\n", cmd.UseLine())
+	if long := strings.TrimSpace(cmd.Long); long != "" {
+		fmt.Fprintf(w, "```text\n%s\n```\n\n", long)
+	}
+	if flags := cmd.NonInheritedFlags().FlagUsages(); flags != "" {
+		fmt.Fprintf(w, "## Options\n\n```\n%s```\n\n", flags)
+	}
+	if flags := cmd.InheritedFlags().FlagUsages(); flags != "" {
+		fmt.Fprintf(w, "## Options inherited from parent commands\n\n```\n%s```\n\n", flags)
+	}
+	if subs := availableChildren(cmd); len(subs) > 0 {
+		fmt.Fprintf(w, "## Subcommands\n\n")
+		for _, c := range subs {
+			fmt.Fprintf(w, "- [%s](%s.md) — %s\n", c.CommandPath(), PageName(c), c.Short)
+		}
+		fmt.Fprintln(w)
+	}
+	if cmd.HasParent() {
+		p := cmd.Parent()
+		fmt.Fprintf(w, "## See also\n\n- [%s](%s.md) — %s\n", p.CommandPath(), PageName(p), p.Short)
+	}
+}
+
+// CommandMan renders one command's roff man page (section 1).
+func CommandMan(w io.Writer, cmd *cobra.Command, version string) {
+	name := PageName(cmd)
+	manHeader(w, name, 1, version)
+	fmt.Fprintf(w, ".SH NAME\n%s \\- %s\n", name, roffEscape(cmd.Short))
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.nf\n%s\n.fi\n", roffEscape(cmd.UseLine()))
+	if long := strings.TrimSpace(cmd.Long); long != "" {
+		fmt.Fprintf(w, ".SH DESCRIPTION\n.nf\n%s\n.fi\n", roffEscape(long))
+	}
+	if flags := cmd.NonInheritedFlags().FlagUsages(); flags != "" {
+		fmt.Fprintf(w, ".SH OPTIONS\n.nf\n%s.fi\n", roffEscape(flags))
+	}
+	if flags := cmd.InheritedFlags().FlagUsages(); flags != "" {
+		fmt.Fprintf(w, ".SH \"OPTIONS INHERITED FROM PARENT COMMANDS\"\n.nf\n%s.fi\n", roffEscape(flags))
+	}
+
+	var seeAlso []string
+	if cmd.HasParent() {
+		seeAlso = append(seeAlso, manRef(PageName(cmd.Parent()), 1))
+	}
+	for _, c := range availableChildren(cmd) {
+		seeAlso = append(seeAlso, manRef(PageName(c), 1))
+	}
+	if len(seeAlso) > 0 {
+		fmt.Fprintf(w, ".SH \"SEE ALSO\"\n%s\n", strings.Join(seeAlso, ", "))
+	}
+}
+
+// ReferenceMarkdown renders a reference page as markdown.
+func ReferenceMarkdown(w io.Writer, r Reference) {
+	fmt.Fprintf(w, "# %s\n\n%s\n\n", r.Name, r.Title)
+	if r.Intro != "" {
+		fmt.Fprintf(w, "%s\n\n", strings.TrimSpace(r.Intro))
+	}
+	for _, s := range r.Sections {
+		fmt.Fprintf(w, "## %s\n\n", s.Heading)
+		if s.Text != "" {
+			fmt.Fprintf(w, "%s\n\n", strings.TrimSpace(s.Text))
+		}
+		if len(s.Fields) > 0 {
+			fmt.Fprintf(w, "| Key | Type |\n|-----|------|\n")
+			for _, f := range s.Fields {
+				fmt.Fprintf(w, "| `%s` | %s |\n", f.Key, f.Type)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// ReferenceMan renders a reference page as a roff man page (section 5).
+func ReferenceMan(w io.Writer, r Reference, version string) {
+	manHeader(w, r.Name, 5, version)
+	fmt.Fprintf(w, ".SH NAME\n%s \\- %s\n", r.Name, roffEscape(r.Title))
+	if r.Intro != "" {
+		fmt.Fprintf(w, ".SH DESCRIPTION\n%s\n", roffEscape(strings.TrimSpace(r.Intro)))
+	}
+	for _, s := range r.Sections {
+		fmt.Fprintf(w, ".SH \"%s\"\n", roffEscape(strings.ToUpper(s.Heading)))
+		if s.Text != "" {
+			fmt.Fprintf(w, "%s\n", roffEscape(strings.TrimSpace(s.Text)))
+		}
+		for _, f := range s.Fields {
+			fmt.Fprintf(w, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(f.Key), roffEscape(f.Type))
+		}
+	}
+}
+
+func indexMarkdown(w io.Writer, root *cobra.Command, cmds []*cobra.Command, refs []Reference) {
+	fmt.Fprintf(w, "# %s reference\n\n", root.Name())
+	fmt.Fprintf(w, "Generated by `%s docs`; do not edit.\n\n## Commands\n\n", root.Name())
+	for _, c := range cmds {
+		fmt.Fprintf(w, "- [%s](%s.md) — %s\n", c.CommandPath(), PageName(c), c.Short)
+	}
+	if len(refs) > 0 {
+		fmt.Fprintf(w, "\n## Files and templates\n\n")
+		for _, r := range refs {
+			fmt.Fprintf(w, "- [%s](%s.md) — %s\n", r.Name, r.Name, r.Title)
+		}
+	}
+}
+
+func availableChildren(cmd *cobra.Command) []*cobra.Command {
+	var out []*cobra.Command
+	for _, c := range cmd.Commands() {
+		if c.IsAvailableCommand() && !c.IsAdditionalHelpTopicCommand() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func manHeader(w io.Writer, name string, section int, version string) {
+	fmt.Fprintf(w, ".TH \"%s\" \"%d\" \"\" \"gt %s\" \"Gas Town Manual\"\n", strings.ToUpper(name), section, version)
+}
+
+func manRef(name string, section int) string {
+	return fmt.Sprintf("\\fB%s\\fR(%d)", name, section)
+}
+
+// roffEscape makes text safe to embed in a roff document: backslashes and
+// hyphens are escaped, and lines that would start a roff request are
+// prefixed with a zero-width escape.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
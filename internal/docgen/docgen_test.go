@@ -0,0 +1,138 @@
+package docgen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func testTree() *cobra.Command {
+	root := &cobra.Command{Use: "gt", Short: "Gas Town"}
+	formula := &cobra.Command{Use: "formula", Short: "Manage formulas"}
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List formulas",
+		Long:  "List formulas.\n.beads/formulas is searched first.",
+		Run:   func(*cobra.Command, []string) {},
+	}
+	list.Flags().Bool("json", false, "Output as JSON")
+	hidden := &cobra.Command{Use: "secret", Hidden: true, Run: func(*cobra.Command, []string) {}}
+	formula.AddCommand(list, hidden)
+	root.AddCommand(formula)
+	return root
+}
+
+func TestCommands(t *testing.T) {
+	var names []string
+	for _, c := range Commands(testTree()) {
+		names = append(names, PageName(c))
+	}
+	if got, want := strings.Join(names, " "), "gt gt-formula gt-formula-list"; got != want {
+		t.Errorf("Commands = %q, want %q", got, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	refs := []Reference{{Name: "gt-thing-schema", Title: "thing files", Sections: []Section{{
+		Heading: "Keys",
+		Fields:  []Field{{Key: "name", Type: "string"}},
+	}}}}
+	written, err := Generate(testTree(), refs, dir, FormatAll, "1.2.3")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	// 3 commands x (man + markdown) + 1 reference x 2 + index
+	if len(written) != 9 {
+		t.Errorf("wrote %d files, want 9: %v", len(written), written)
+	}
+
+	man, err := os.ReadFile(filepath.Join(dir, "man1", "gt-formula-list.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`.TH "GT-FORMULA-LIST" "1"`, `gt-formula-list \- List formulas`, `\&.beads/formulas`, `\-\-json`, `\fBgt-formula\fR(1)`} {
+		if !strings.Contains(string(man), want) {
+			t.Errorf("man page missing %q:\n%s", want, man)
+		}
+	}
+
+	md, err := os.ReadFile(filepath.Join(dir, "markdown", "gt-formula.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(md), "[gt formula list](gt-formula-list.md)") {
+		t.Errorf("markdown page missing subcommand link:\n%s", md)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "man5", "gt-thing-schema.5")); err != nil {
+		t.Errorf("reference man page: %v", err)
+	}
+
+	refs[0].Name = "gt-formula"
+	if _, err := Generate(testTree(), refs, t.TempDir(), FormatMarkdown, "1.2.3"); err == nil {
+		t.Error("expected error when a reference page shadows a command page")
+	}
+}
+
+func TestFields(t *testing.T) {
+	type leaf struct {
+		ID string `toml:"id"`
+	}
+	type sample struct {
+		Name    string            `toml:"name"`
+		Timeout time.Duration     `toml:"timeout"`
+		Legs    []leaf            `toml:"legs"`
+		Inputs  map[string]*leaf  `toml:"inputs"`
+		Env     map[string]string `toml:"env"`
+		Skip    string            `toml:"-"`
+		private string
+	}
+	var got []string
+	for _, f := range Fields(sample{}, "toml") {
+		got = append(got, f.Key+":"+f.Type)
+	}
+	want := []string{
+		"name:string",
+		"timeout:duration",
+		"legs:list of object",
+		"legs[].id:string",
+		"inputs:map of object",
+		"inputs.<name>.id:string",
+		"env:map of string",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Fields = %v, want %v", got, want)
+	}
+}
+
+func TestMapFields(t *testing.T) {
+	ctx := map[string]any{
+		"review_id": "",
+		"leg":       map[string]any{"id": ""},
+		"files":     []map[string]any{{"path": "", "additions": 0}},
+	}
+	var got []string
+	for _, f := range MapFields(ctx) {
+		got = append(got, f.Key+":"+f.Type)
+	}
+	want := "files:list of object files[].additions:int files[].path:string leg:object leg.id:string review_id:string"
+	if strings.Join(got, " ") != want {
+		t.Errorf("MapFields = %v, want %s", got, want)
+	}
+}
+
+func TestReferenceMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	ReferenceMarkdown(&buf, Reference{Name: "gt-x", Title: "x files", Sections: []Section{{
+		Heading: "Keys",
+		Fields:  []Field{{Key: "a.b", Type: "int"}},
+	}}})
+	if !strings.Contains(buf.String(), "| `a.b` | int |") {
+		t.Errorf("missing field row:\n%s", buf.String())
+	}
+}
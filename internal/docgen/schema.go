@@ -0,0 +1,144 @@
+package docgen
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Field is one key of a file schema, e.g. "merge_queue.test_command".
+type Field struct {
+	Key  string
+	Type string
+}
+
+// Fields walks the struct type of v and lists its keys as named by the given
+// struct tag ("json" or "toml"), depth first. Nested structs contribute
+// dotted keys; list elements are written "key[].child" and map values
+// "key.<name>.child". Fields tagged "-" and unexported fields are skipped.
+func Fields(v any, tag string) []Field {
+	var out []Field
+	walkFields(reflect.TypeOf(v), tag, "", map[reflect.Type]bool{}, &out)
+	return out
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func walkFields(t reflect.Type, tag, prefix string, seen map[reflect.Type]bool, out *[]Field) {
+	t = deref(t)
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := tagName(sf, tag)
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			walkFields(sf.Type, tag, prefix, seen, out)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		key := prefix + name
+		*out = append(*out, Field{Key: key, Type: typeName(sf.Type)})
+
+		ft := deref(sf.Type)
+		switch ft.Kind() {
+		case reflect.Struct:
+			walkFields(ft, tag, key+".", seen, out)
+		case reflect.Slice, reflect.Array:
+			walkFields(ft.Elem(), tag, key+"[].", seen, out)
+		case reflect.Map:
+			walkFields(ft.Elem(), tag, key+".<name>.", seen, out)
+		}
+	}
+}
+
+func tagName(sf reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(sf.Tag.Get(tag), ",")
+	return name
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// typeName describes a Go type in schema terms: string, int, bool, list,
+// map, object, duration, or time.
+func typeName(t reflect.Type) string {
+	t = deref(t)
+	switch t {
+	case timeType:
+		return "time"
+	case durationType:
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list of " + typeName(t.Elem())
+	case reflect.Map:
+		return "map of " + typeName(t.Elem())
+	case reflect.Struct:
+		return "object"
+	}
+	return "any"
+}
+
+// MapFields lists the keys of a template context map, e.g. the one a
+// formula's prompts are rendered with. Nested maps contribute dotted keys
+// and lists of maps contribute "key[].child" keys. Keys are sorted.
+func MapFields(m map[string]any) []Field {
+	var out []Field
+	walkMap(m, "", &out)
+	return out
+}
+
+func walkMap(m map[string]any, prefix string, out *[]Field) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := prefix + k
+		switch v := m[k].(type) {
+		case map[string]any:
+			*out = append(*out, Field{Key: key, Type: "object"})
+			walkMap(v, key+".", out)
+		case []map[string]any:
+			*out = append(*out, Field{Key: key, Type: "list of object"})
+			if len(v) > 0 {
+				walkMap(v[0], key+"[].", out)
+			}
+		default:
+			t := "any"
+			if v != nil {
+				t = typeName(reflect.TypeOf(v))
+			}
+			*out = append(*out, Field{Key: key, Type: t})
+		}
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // BeadsDatabaseCheck verifies that the beads database is properly initialized.
@@ -135,7 +137,7 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 		cmd.Dir = ctx.TownRoot
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
-		if err := cmd.Run(); err != nil {
+		if err := cmdtrace.Run(cmd); err != nil {
 			return err
 		}
 	}
@@ -158,7 +160,7 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 			cmd.Dir = ctx.RigPath()
 			var stderr bytes.Buffer
 			cmd.Stderr = &stderr
-			if err := cmd.Run(); err != nil {
+			if err := cmdtrace.Run(cmd); err != nil {
 				return err
 			}
 		}
@@ -248,6 +250,7 @@ func NewPrefixMismatchCheck() *PrefixMismatchCheck {
 				CheckName:        "prefix-mismatch",
 				CheckDescription: "Check for prefix mismatches between rigs.json and routes.jsonl",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"routes-config"},
 			},
 		},
 	}
@@ -436,7 +439,7 @@ func saveRigsConfig(path string, cfg *rigsConfigFile) error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return fsx.WriteFile(path, data, 0644)
 }
 
 // beadShower is an interface for fetching bead information.
@@ -457,7 +460,7 @@ type realLabelAdder struct{}
 func (r *realLabelAdder) AddLabel(townRoot, id, label string) error {
 	cmd := exec.Command("bd", "label", "add", id, label)
 	cmd.Dir = townRoot
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("adding %s label to %s: %s", label, id, strings.TrimSpace(string(output)))
 	}
 	return nil
@@ -605,6 +608,7 @@ func NewDatabasePrefixCheck() *DatabasePrefixCheck {
 				CheckName:        "database-prefix",
 				CheckDescription: "Check rig database issue_prefix matches routes.jsonl",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"routes-config"},
 			},
 		},
 	}
@@ -707,7 +711,7 @@ func (c *DatabasePrefixCheck) Run(ctx *CheckContext) *CheckResult {
 // getDBPrefix queries the database for issue_prefix config value.
 func (c *DatabasePrefixCheck) getDBPrefix(beadsDir string) (string, error) {
 	cmd := exec.Command("bd", "config", "get", "issue_prefix", "--db", beadsDir)
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return "", err
 	}
@@ -726,7 +730,7 @@ func (c *DatabasePrefixCheck) Fix(ctx *CheckContext) error {
 
 	for _, m := range c.mismatches {
 		cmd := exec.Command("bd", "config", "set", "issue_prefix", m.routesPrefix, "--db", m.beadsDir)
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 			return fmt.Errorf("updating %s: %s", m.rigPath, strings.TrimSpace(string(output)))
 		}
 	}
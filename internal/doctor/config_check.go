@@ -1,7 +1,6 @@
 package doctor
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,7 +8,11 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/gitignore"
+	"github.com/steveyegge/gastown/internal/optree"
 )
 
 // SettingsCheck verifies each rig has a settings/ directory.
@@ -96,87 +99,71 @@ func (c *SettingsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
-// RuntimeGitignoreCheck verifies .runtime/ is gitignored at town and rig levels.
+// RuntimeGitignoreCheck verifies Gas Town runtime directories are gitignored
+// at town, rig, and crew levels. Fix writes the gt-managed .gitignore block.
 type RuntimeGitignoreCheck struct {
-	BaseCheck
+	FixableCheck
+	needsSync []string // .gitignore paths cached during Run for use in Fix
 }
 
 // NewRuntimeGitignoreCheck creates a new runtime gitignore check.
 func NewRuntimeGitignoreCheck() *RuntimeGitignoreCheck {
 	return &RuntimeGitignoreCheck{
-		BaseCheck: BaseCheck{
-			CheckName:        "runtime-gitignore",
-			CheckDescription: "Check that .runtime/ directories are gitignored",
-			CheckCategory:    CategoryConfig,
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "runtime-gitignore",
+				CheckDescription: "Check that .runtime/ and other Gas Town directories are gitignored",
+				CheckCategory:    CategoryConfig,
+			},
 		},
 	}
 }
 
-// Run checks if .runtime/ is properly gitignored.
+// Run checks if the required patterns are present in each managed .gitignore.
 func (c *RuntimeGitignoreCheck) Run(ctx *CheckContext) *CheckResult {
 	var issues []string
+	c.needsSync = nil
 
-	// Check town-level .gitignore
-	townGitignore := filepath.Join(ctx.TownRoot, ".gitignore")
-	if !c.containsPattern(townGitignore, ".runtime") {
-		issues = append(issues, "Town .gitignore missing .runtime/ pattern")
-	}
+	for _, target := range gitignore.Targets(ctx.TownRoot, c.findRigs(ctx.TownRoot)) {
+		missing := gitignore.Missing(target.Path(), gitignore.RequiredPatterns)
+		if len(missing) == 0 {
+			continue
+		}
+		c.needsSync = append(c.needsSync, target.Path())
 
-	// Check each rig's .gitignore (in their git worktrees)
-	rigs := c.findRigs(ctx.TownRoot)
-	for _, rig := range rigs {
-		// Check crew members
-		crewPath := filepath.Join(rig, "crew")
-		if crewEntries, err := os.ReadDir(crewPath); err == nil {
-			for _, crew := range crewEntries {
-				if crew.IsDir() && !strings.HasPrefix(crew.Name(), ".") {
-					crewGitignore := filepath.Join(crewPath, crew.Name(), ".gitignore")
-					if !c.containsPattern(crewGitignore, ".runtime") {
-						relPath, _ := filepath.Rel(ctx.TownRoot, filepath.Join(crewPath, crew.Name()))
-						issues = append(issues, fmt.Sprintf("%s .gitignore missing .runtime/ pattern", relPath))
-					}
-				}
-			}
+		label := "Town"
+		if target.Scope != gitignore.ScopeTown {
+			relPath, _ := filepath.Rel(ctx.TownRoot, target.Dir)
+			label = relPath
 		}
+		issues = append(issues, fmt.Sprintf("%s .gitignore missing %s", label, strings.Join(missing, ", ")))
 	}
 
 	if len(issues) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
-			Message: ".runtime/ properly gitignored",
+			Message: "Gas Town directories properly gitignored",
 		}
 	}
 
 	return &CheckResult{
 		Name:    c.Name(),
 		Status:  StatusWarning,
-		Message: fmt.Sprintf("%d location(s) missing .runtime gitignore", len(issues)),
+		Message: fmt.Sprintf("%d location(s) missing gitignore patterns", len(issues)),
 		Details: issues,
-		FixHint: "Add '.runtime/' to .gitignore files",
+		FixHint: "Run 'gt doctor --fix' or 'gt gitignore sync'",
 	}
 }
 
-// containsPattern checks if a gitignore file contains a pattern.
-func (c *RuntimeGitignoreCheck) containsPattern(gitignorePath, pattern string) bool {
-	file, err := os.Open(gitignorePath)
-	if err != nil {
-		return false // File doesn't exist
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		// Check for pattern match (with or without trailing slash, with or without glob prefix)
-		// Accept: .runtime, .runtime/, /.runtime, /.runtime/, **/.runtime, **/.runtime/
-		if line == pattern || line == pattern+"/" ||
-			line == "/"+pattern || line == "/"+pattern+"/" ||
-			line == "**/"+pattern || line == "**/"+pattern+"/" {
-			return true
+// Fix writes the managed block into each .gitignore found lacking patterns.
+func (c *RuntimeGitignoreCheck) Fix(ctx *CheckContext) error {
+	for _, path := range c.needsSync {
+		if _, err := gitignore.Sync(path, gitignore.RequiredPatterns); err != nil {
+			return err
 		}
 	}
-	return false
+	return nil
 }
 
 // findRigs returns rig directories within the town.
@@ -248,10 +235,13 @@ func (c *LegacyGastownCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d legacy .gastown/ directory(ies) found", len(found)),
 		Details: found,
-		FixHint: "Run 'gt doctor --fix' to remove after verifying migration is complete",
+		FixHint: "Run 'gt migrate apply' to archive them, or 'gt doctor --fix' to remove after verifying migration is complete",
 	}
 }
 
+// DestructiveFix reports that fix deletes .gastown/ directories.
+func (c *LegacyGastownCheck) DestructiveFix() bool { return true }
+
 // Fix removes legacy .gastown/ directories.
 func (c *LegacyGastownCheck) Fix(ctx *CheckContext) error {
 	for _, dir := range c.legacyDirs {
@@ -423,7 +413,7 @@ func (c *SessionHookCheck) fixSettingsFile(path string) error {
 	newData := []byte(buf.String())
 
 	// Write back
-	if err := os.WriteFile(path, newData, 0644); err != nil {
+	if err := fsx.WriteFile(path, newData, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -696,7 +686,7 @@ func (c *CustomTypesCheck) Run(ctx *CheckContext) *CheckResult {
 	// Use Output() not CombinedOutput() to avoid capturing bd's stderr messages
 	cmd := exec.Command("bd", "--no-daemon", "config", "get", "types.custom")
 	cmd.Dir = ctx.TownRoot
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		// If config key doesn't exist, types are not configured
 		c.townRoot = ctx.TownRoot
@@ -769,9 +759,14 @@ func parseConfigOutput(output []byte) string {
 func (c *CustomTypesCheck) Fix(ctx *CheckContext) error {
 	cmd := exec.Command("bd", "--no-daemon", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = c.townRoot
-	output, err := cmd.CombinedOutput()
+	output, err := cmdtrace.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom: %s", strings.TrimSpace(string(output)))
 	}
 	return nil
 }
+
+// ExplainFix lists the bd command Fix runs.
+func (c *CustomTypesCheck) ExplainFix(ctx *CheckContext) []optree.Command {
+	return []optree.Command{optree.In(c.townRoot, "bd", "--no-daemon", "config", "set", "types.custom", constants.BeadsCustomTypes)}
+}
@@ -0,0 +1,435 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/suggest"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// configSchema describes one kind of config file the schema check validates.
+type configSchema struct {
+	RelPath    string                 // Relative to the town or rig root
+	Type       reflect.Type           // Go type the file unmarshals into
+	Current    int                    // Current schema version
+	Required   map[string]interface{} // Required top-level keys -> default (nil: no default)
+	Deprecated map[string]string      // Deprecated top-level keys -> replacement hint
+}
+
+var (
+	townConfigSchemas = []configSchema{
+		{
+			RelPath:  filepath.Join("settings", "config.json"),
+			Type:     reflect.TypeOf(config.TownSettings{}),
+			Current:  config.CurrentTownSettingsVersion,
+			Required: map[string]interface{}{"type": "town-settings", "version": config.CurrentTownSettingsVersion},
+		},
+		{
+			RelPath:  filepath.Join("mayor", "town.json"),
+			Type:     reflect.TypeOf(config.TownConfig{}),
+			Current:  config.CurrentTownVersion,
+			Required: map[string]interface{}{"type": "town", "version": config.CurrentTownVersion, "name": nil},
+		},
+	}
+
+	rigConfigSchemas = []configSchema{
+		{
+			RelPath:    filepath.Join("settings", "config.json"),
+			Type:       reflect.TypeOf(config.RigSettings{}),
+			Current:    config.CurrentRigSettingsVersion,
+			Required:   map[string]interface{}{"type": "rig-settings", "version": config.CurrentRigSettingsVersion},
+			Deprecated: map[string]string{"runtime": `use "agent" (and "agents" for custom commands)`},
+		},
+		{
+			RelPath:  "config.json",
+			Type:     reflect.TypeOf(rig.RigConfig{}),
+			Current:  config.CurrentRigConfigVersion,
+			Required: map[string]interface{}{"type": "rig", "version": config.CurrentRigConfigVersion, "name": nil, "git_url": nil},
+		},
+	}
+)
+
+// schemaFinding is one problem found in a config file.
+type schemaFinding struct {
+	Message string
+	Fixable bool
+}
+
+// ConfigSchemaCheck validates town and rig config files against the schema
+// of the running gt: version, unknown keys, deprecated keys, and missing
+// required fields. Fix migrates keys that are spelling variants of known
+// keys (e.g. "default-agent" → "default_agent"), fills required fields that
+// have defaults, and bumps older schema versions. Deprecated keys need a
+// hand migration, so a file's version is left alone until they are gone.
+type ConfigSchemaCheck struct {
+	FixableCheck
+	fixable []string // Files with fixable findings, cached during Run for Fix
+}
+
+// NewConfigSchemaCheck creates a new config schema drift check.
+func NewConfigSchemaCheck() *ConfigSchemaCheck {
+	return &ConfigSchemaCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "config-schema",
+				CheckDescription: "Check config files for schema drift after upgrades",
+				CheckCategory:    CategoryConfig,
+			},
+		},
+	}
+}
+
+// Run validates every known config file in the town and its rigs.
+func (c *ConfigSchemaCheck) Run(ctx *CheckContext) *CheckResult {
+	c.fixable = nil
+	var details []string
+	status := StatusOK
+	checked := 0
+
+	check := func(root string, schemas []configSchema) {
+		for _, schema := range schemas {
+			path := filepath.Join(root, schema.RelPath)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			checked++
+			findings, fileStatus := checkConfigSchema(path, schema)
+			if len(findings) == 0 {
+				continue
+			}
+			if fileStatus > status {
+				status = fileStatus
+			}
+			relPath, _ := filepath.Rel(ctx.TownRoot, path)
+			anyFixable := false
+			for _, f := range findings {
+				details = append(details, fmt.Sprintf("%s: %s", relPath, f.Message))
+				anyFixable = anyFixable || f.Fixable
+			}
+			if anyFixable {
+				c.fixable = append(c.fixable, path)
+			}
+		}
+	}
+
+	check(ctx.TownRoot, townConfigSchemas)
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		check(rigPath, rigConfigSchemas)
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%d config file(s) match the current schema", checked),
+		}
+	}
+
+	result := &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: fmt.Sprintf("%d schema issue(s) in config files", len(details)),
+		Details: details,
+		FixHint: "Fix keys by hand, or run 'gt doctor --fix' to migrate renamed keys and fill defaults",
+	}
+	if len(c.fixable) == 0 {
+		result.FixHint = "Edit the listed files by hand"
+	}
+	return result
+}
+
+// Fix migrates renamed keys, fills defaulted required fields, and bumps
+// schema versions in the files found by Run.
+func (c *ConfigSchemaCheck) Fix(ctx *CheckContext) error {
+	for _, path := range c.fixable {
+		schema, ok := schemaForPath(ctx.TownRoot, path)
+		if !ok {
+			continue
+		}
+		if err := migrateConfigSchema(path, schema); err != nil {
+			return fmt.Errorf("migrating %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// schemaForPath finds the schema that produced a cached file path.
+func schemaForPath(townRoot, path string) (configSchema, bool) {
+	for _, s := range townConfigSchemas {
+		if filepath.Join(townRoot, s.RelPath) == path {
+			return s, true
+		}
+	}
+	for _, s := range rigConfigSchemas {
+		if strings.HasSuffix(path, string(filepath.Separator)+s.RelPath) {
+			return s, true
+		}
+	}
+	return configSchema{}, false
+}
+
+// checkConfigSchema reports the findings for one file and the worst status.
+func checkConfigSchema(path string, schema configSchema) ([]schemaFinding, CheckStatus) {
+	raw, err := readRawConfig(path)
+	if err != nil {
+		return []schemaFinding{{Message: err.Error()}}, StatusError
+	}
+
+	var findings []schemaFinding
+	status := StatusOK
+	warn := func(f schemaFinding) {
+		findings = append(findings, f)
+		if status < StatusWarning {
+			status = StatusWarning
+		}
+	}
+
+	deprecated := deprecatedKeys(raw, schema)
+	if v, ok := raw["version"].(float64); ok {
+		switch {
+		case int(v) > schema.Current:
+			findings = append(findings, schemaFinding{
+				Message: fmt.Sprintf("schema version %d is newer than this gt supports (%d); upgrade gt", int(v), schema.Current),
+			})
+			status = StatusError
+		case int(v) < schema.Current:
+			msg := fmt.Sprintf("schema version %d is older than current (%d)", int(v), schema.Current)
+			if len(deprecated) > 0 {
+				msg += fmt.Sprintf("; migrate deprecated %q by hand first", strings.Join(deprecated, ", "))
+			}
+			warn(schemaFinding{Message: msg, Fixable: len(deprecated) == 0})
+		}
+	}
+
+	// Required fields may come from included files.
+	effective := raw
+	if _, ok := raw["include"]; ok {
+		if merged, _, err := config.ResolveLayeredConfig(path); err == nil {
+			effective = merged
+		}
+	}
+	keys := make([]string, 0, len(schema.Required))
+	for k := range schema.Required {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := effective[k]; ok {
+			continue
+		}
+		def := schema.Required[k]
+		warn(schemaFinding{
+			Message: fmt.Sprintf("missing required field %q", k),
+			Fixable: def != nil && (k != "version" || len(deprecated) == 0),
+		})
+	}
+
+	for _, k := range deprecated {
+		warn(schemaFinding{Message: fmt.Sprintf("deprecated key %q: %s", k, schema.Deprecated[k])})
+	}
+
+	for _, u := range walkConfigKeys(raw, schema.Type, "", false) {
+		warn(u)
+	}
+	return findings, status
+}
+
+// migrateConfigSchema applies the automatic fixes to one file.
+func migrateConfigSchema(path string, schema configSchema) error {
+	raw, err := readRawConfig(path)
+	if err != nil {
+		return err
+	}
+
+	effective := raw
+	if _, ok := raw["include"]; ok {
+		if merged, _, err := config.ResolveLayeredConfig(path); err == nil {
+			effective = merged
+		}
+	}
+
+	// A file still using deprecated keys isn't at the current schema, so
+	// its version stays put until they are migrated by hand.
+	current := len(deprecatedKeys(raw, schema)) == 0
+
+	walkConfigKeys(raw, schema.Type, "", true)
+	for k, def := range schema.Required {
+		if k == "version" && !current {
+			continue
+		}
+		if _, ok := effective[k]; !ok && def != nil {
+			raw[k] = def
+		}
+	}
+	if v, ok := raw["version"].(float64); ok && int(v) < schema.Current && current {
+		raw["version"] = schema.Current
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(path, append(data, '\n'), info.Mode().Perm())
+}
+
+// deprecatedKeys returns the deprecated top-level keys raw still uses.
+func deprecatedKeys(raw map[string]interface{}, schema configSchema) []string {
+	var keys []string
+	for _, k := range sortedKeys(raw) {
+		if _, ok := schema.Deprecated[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// readRawConfig parses a config file as a generic JSON object.
+func readRawConfig(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a known config location
+	if err != nil {
+		return nil, fmt.Errorf("reading: %w", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return raw, nil
+}
+
+// walkConfigKeys compares the keys of raw against the JSON fields of t,
+// recursing into nested objects. Keys that only differ from a known key in
+// case or separators are renames; with fix set they are migrated in place.
+func walkConfigKeys(raw map[string]interface{}, t reflect.Type, prefix string, fix bool) []schemaFinding {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := jsonFields(t)
+	known := make([]string, 0, len(fields))
+	for name := range fields {
+		known = append(known, name)
+	}
+
+	var findings []schemaFinding
+	for _, key := range sortedKeys(raw) {
+		name, value := key, raw[key]
+		if _, ok := fields[key]; !ok {
+			target := renameTarget(key, known)
+			switch {
+			case target != "" && fix:
+				if _, taken := raw[target]; !taken {
+					raw[target] = value
+				}
+				delete(raw, key)
+				name = target
+			case target != "":
+				findings = append(findings, schemaFinding{
+					Message: fmt.Sprintf("key %q should be %q", prefix+key, prefix+target),
+					Fixable: true,
+				})
+				name = target
+			default:
+				msg := fmt.Sprintf("unknown key %q", prefix+key)
+				if similar := suggest.FindSimilar(key, known, 1); len(similar) > 0 {
+					msg += fmt.Sprintf(" (did you mean %q?)", prefix+similar[0])
+				}
+				findings = append(findings, schemaFinding{Message: msg})
+				continue
+			}
+		}
+
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ft := fields[name]
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch ft.Kind() {
+		case reflect.Struct:
+			findings = append(findings, walkConfigKeys(obj, ft, prefix+name+".", fix)...)
+		case reflect.Map:
+			for _, entry := range sortedKeys(obj) {
+				if sub, ok := obj[entry].(map[string]interface{}); ok {
+					findings = append(findings, walkConfigKeys(sub, ft.Elem(), prefix+name+"."+entry+".", fix)...)
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// jsonFields maps the JSON names of a struct's fields to their types,
+// flattening embedded structs the way encoding/json does.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					fields[k] = v
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// renameTarget returns the known key that key is a spelling variant of
+// (case, hyphens, or camelCase), or "" if there is none.
+func renameTarget(key string, known []string) string {
+	norm := normalizeConfigKey(key)
+	for _, k := range known {
+		if normalizeConfigKey(k) == norm {
+			return k
+		}
+	}
+	return ""
+}
+
+// normalizeConfigKey lowercases a key and drops separators.
+func normalizeConfigKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,143 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeJSONFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigSchemaCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	writeJSONFile(t, filepath.Join(townRoot, "settings", "config.json"),
+		`{"type": "town-settings", "version": 1, "default-agent": "codex", "role_agnets": {"witness": "claude"}}`)
+	writeJSONFile(t, filepath.Join(townRoot, "mayor", "town.json"),
+		`{"type": "town", "version": 1, "name": "test"}`)
+
+	rigPath := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(filepath.Join(rigPath, "crew"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeJSONFile(t, filepath.Join(rigPath, "settings", "config.json"),
+		`{"merge_queue": {"enabled": true, "runTests": false}, "runtime": {"command": "claude"}}`)
+	writeJSONFile(t, filepath.Join(rigPath, "config.json"),
+		`{"type": "rig", "version": 1, "name": "gastown", "git_url": "https://example.com/repo.git"}`)
+
+	check := NewConfigSchemaCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("status = %v, want warning: %v", result.Status, result.Details)
+	}
+
+	details := strings.Join(result.Details, "\n")
+	for _, want := range []string{
+		`settings/config.json: key "default-agent" should be "default_agent"`,
+		`unknown key "role_agnets" (did you mean "role_agents"?)`,
+		`mayor/town.json: schema version 1 is older than current (2)`,
+		`gastown/settings/config.json: missing required field "type"`,
+		`key "merge_queue.runTests" should be "merge_queue.run_tests"`,
+		`deprecated key "runtime"`,
+	} {
+		if !strings.Contains(details, want) {
+			t.Errorf("details missing %q:\n%s", want, details)
+		}
+	}
+	if strings.Contains(details, "gastown/config.json") {
+		t.Errorf("rig config.json should be clean:\n%s", details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(rigPath, "settings", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rigSettings map[string]interface{}
+	if err := json.Unmarshal(data, &rigSettings); err != nil {
+		t.Fatal(err)
+	}
+	if rigSettings["type"] != "rig-settings" {
+		t.Errorf("type not filled: %s", data)
+	}
+	if mq := rigSettings["merge_queue"].(map[string]interface{}); mq["run_tests"] != false {
+		t.Errorf("nested rename not migrated: %s", data)
+	}
+	if _, ok := rigSettings["version"]; ok {
+		t.Errorf("version stamped while deprecated \"runtime\" remains: %s", data)
+	}
+
+	result = check.Run(ctx)
+	details = strings.Join(result.Details, "\n")
+	if strings.Contains(details, "should be") || strings.Contains(details, "older than") || strings.Contains(details, `missing required field "type"`) {
+		t.Errorf("fixable findings remain after Fix:\n%s", details)
+	}
+	if !strings.Contains(details, "role_agnets") || !strings.Contains(details, `deprecated key "runtime"`) ||
+		!strings.Contains(details, `missing required field "version"`) {
+		t.Errorf("unfixable findings should remain:\n%s", details)
+	}
+}
+
+func TestMigrateConfigSchemaKeepsVersionWithDeprecatedKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeJSONFile(t, path, `{"type": "rig-settings", "version": 1, "runtime": {"command": "claude"}}`)
+	schema := rigConfigSchemas[0]
+	schema.Current = 2
+
+	findings, _ := checkConfigSchema(path, schema)
+	for _, f := range findings {
+		if strings.Contains(f.Message, "older than") && f.Fixable {
+			t.Errorf("version finding should not be fixable while \"runtime\" remains: %s", f.Message)
+		}
+	}
+
+	if err := migrateConfigSchema(path, schema); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw["version"] != float64(1) {
+		t.Errorf("version bumped past deprecated \"runtime\": %s", data)
+	}
+
+	writeJSONFile(t, path, `{"type": "rig-settings", "version": 1, "agent": "claude"}`)
+	if err := migrateConfigSchema(path, schema); err != nil {
+		t.Fatal(err)
+	}
+	if raw, err = readRawConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if raw["version"] != float64(2) {
+		t.Errorf("version = %v, want 2 once no deprecated keys remain", raw["version"])
+	}
+}
+
+func TestConfigSchemaCheckNewerVersion(t *testing.T) {
+	townRoot := t.TempDir()
+	writeJSONFile(t, filepath.Join(townRoot, "settings", "config.json"),
+		`{"type": "town-settings", "version": 99}`)
+
+	result := NewConfigSchemaCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusError {
+		t.Errorf("status = %v, want error for newer schema", result.Status)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // CrewStateCheck validates crew worker state.json files for completeness.
@@ -164,7 +166,7 @@ func (c *CrewStateCheck) Fix(ctx *CheckContext) error {
 			continue
 		}
 
-		if err := os.WriteFile(ic.stateFile, data, 0644); err != nil {
+		if err := fsx.WriteFile(ic.stateFile, data, 0644); err != nil {
 			lastErr = fmt.Errorf("%s/%s: %w", ic.rigName, ic.crewName, err)
 			continue
 		}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/optree"
 )
 
 // DaemonCheck verifies the daemon is running.
@@ -91,6 +92,11 @@ func (c *DaemonCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// ExplainFix lists the daemon Fix starts in the background.
+func (c *DaemonCheck) ExplainFix(ctx *CheckContext) []optree.Command {
+	return []optree.Command{optree.In(ctx.TownRoot, "gt", "daemon", "run")}
+}
+
 // itoa is a simple int to string helper
 func itoa(i int) string {
 	if i == 0 {
@@ -0,0 +1,69 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/du"
+)
+
+// DiskUsageCheck warns when a disk usage category of the town or a rig
+// exceeds its limit in the town's disk_usage settings.
+type DiskUsageCheck struct {
+	BaseCheck
+}
+
+// NewDiskUsageCheck creates a new disk usage check.
+func NewDiskUsageCheck() *DiskUsageCheck {
+	return &DiskUsageCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "disk-usage",
+			CheckDescription: "Check disk usage against configured limits",
+			CheckCategory:    CategoryCleanup,
+		},
+	}
+}
+
+// Run measures the town and its rigs if any limits are configured.
+func (c *DiskUsageCheck) Run(ctx *CheckContext) *CheckResult {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+	if err != nil || settings.DiskUsage == nil || len(settings.DiskUsage.LimitsMB) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No disk usage limits configured",
+		}
+	}
+
+	rigs, err := discoverRigs(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Failed to discover rigs",
+			Details: []string{err.Error()},
+		}
+	}
+
+	over := du.Exceeded(du.Measure(ctx.TownRoot, rigs), settings.DiskUsage)
+	if len(over) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Disk usage within limits",
+		}
+	}
+
+	var details []string
+	for _, e := range over {
+		details = append(details, fmt.Sprintf("%s %s: %s (limit %s)",
+			e.Scope, e.Category, du.FormatBytes(e.Bytes), du.FormatBytes(e.Limit)))
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d disk usage limit(s) exceeded", len(over)),
+		Details: details,
+		FixHint: "Run 'gt du' for a breakdown and 'gt gc' to remove runtime debris",
+	}
+}
@@ -0,0 +1,42 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDiskUsageCheck(t *testing.T) {
+	town := t.TempDir()
+	check := NewDiskUsageCheck()
+
+	if result := check.Run(&CheckContext{TownRoot: town}); result.Status != StatusOK {
+		t.Errorf("without limits: status = %v, want OK", result.Status)
+	}
+
+	settings := config.NewTownSettings()
+	settings.DiskUsage = &config.DiskUsageConfig{LimitsMB: map[string]int{config.DiskRuntime: 1}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(town, "logs", "town.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath, make([]byte, 512<<10), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(&CheckContext{TownRoot: town}); result.Status != StatusOK {
+		t.Errorf("under the limit: status = %v (%s), want OK", result.Status, result.Message)
+	}
+
+	if err := os.WriteFile(logPath, make([]byte, 2<<20), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := check.Run(&CheckContext{TownRoot: town})
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Errorf("over the limit: status = %v, details %q; want one warning", result.Status, result.Details)
+	}
+}
@@ -35,6 +35,16 @@ func (d *Doctor) Checks() []Check {
 	return d.checks
 }
 
+// fixConfirmed reports whether a check's fix may run. Only destructive
+// fixes consult ctx.ConfirmFix.
+func fixConfirmed(ctx *CheckContext, check Check) bool {
+	df, ok := check.(DestructiveFixer)
+	if !ok || !df.DestructiveFix() || ctx.ConfirmFix == nil {
+		return true
+	}
+	return ctx.ConfirmFix(check.Name())
+}
+
 // categoryGetter interface for checks that provide a category
 type categoryGetter interface {
 	Category() string
@@ -51,7 +61,7 @@ func (d *Doctor) Run(ctx *CheckContext) *Report {
 func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
 	report := NewReport()
 
-	for _, check := range d.checks {
+	for _, check := range d.orderedChecks() {
 		// Stream: print check name before running
 		if w != nil {
 			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
@@ -110,102 +120,234 @@ func (d *Doctor) Fix(ctx *CheckContext) *Report {
 	return d.FixStreaming(ctx, nil, 0)
 }
 
+// maxFixPasses bounds how many times dependent checks are re-fixed after
+// their dependencies change, so a single --fix invocation converges.
+const maxFixPasses = 3
+
 // FixStreaming runs all checks with auto-fix and optional real-time output.
+// Checks run in dependency order (see DependsOn), and a check whose
+// dependency is still failing is not fixed. After the first pass, checks
+// that are still failing are re-run and re-fixed when one of their
+// dependencies was fixed in the previous pass.
 // If w is non-nil, prints each check name as it starts and result when done.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
 func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
 	report := NewReport()
 
-	for _, check := range d.checks {
-		// Stream: print check name before running
-		if w != nil {
-			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
-		}
+	checks := d.orderedChecks()
+	results := make([]*CheckResult, len(checks))
+	failing := make(map[string]bool)
+	fixed := make(map[string]bool)
 
-		start := time.Now()
-		result := check.Run(ctx)
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
-		}
+	for i, check := range checks {
+		results[i] = d.fixCheck(ctx, check, failing, fixed, w, slowThreshold, report)
+	}
 
-		// Attempt fix if check failed and is fixable
-		if result.Status != StatusOK && check.CanFix() {
-			// Stream: show the problem with fixing indicator (all on same line)
-			if w != nil {
-				var problemIcon string
-				if result.Status == StatusError {
-					problemIcon = ui.RenderFailIcon()
-				} else {
-					problemIcon = ui.RenderWarnIcon()
-				}
-				// Overwrite the "checking" line with problem status + fixing indicator
-				fmt.Fprintf(w, "\r  %s  %s", problemIcon, check.Name())
-				if result.Message != "" {
-					fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
-				}
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" (fixing)..."))
+	for pass := 1; pass < maxFixPasses && len(fixed) > 0; pass++ {
+		prevFixed := fixed
+		fixed = make(map[string]bool)
+		for i, check := range checks {
+			if results[i].Status == StatusOK || !check.CanFix() || !dependsOnAny(check, prevFixed) {
+				continue
 			}
-
-			err := check.Fix(ctx)
-			if err == nil {
-				// Re-run check to verify fix worked
-				result = check.Run(ctx)
-				if result.Name == "" {
-					result.Name = check.Name()
-				}
-				// Set category again after re-run
-				if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-					result.Category = cg.Category()
-				}
-				// Update message to indicate fix was applied
-				if result.Status == StatusOK {
-					result.Message = result.Message + " (fixed)"
-				}
-			} else {
-				// Fix failed, add error to details
-				result.Details = append(result.Details, "Fix failed: "+err.Error())
+			if w != nil {
+				fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("↻"), check.Name())
 			}
+			results[i] = d.fixCheck(ctx, check, failing, fixed, w, slowThreshold, report)
 		}
+	}
 
-		// Record total elapsed time including any fix attempts
-		result.Elapsed = time.Since(start)
+	for _, result := range results {
+		report.Add(result)
+	}
+
+	return report
+}
+
+// fixCheck runs a single check, attempts a fix if needed, and streams the
+// outcome. failing and fixed are updated with the check's final state.
+func (d *Doctor) fixCheck(ctx *CheckContext, check Check, failing, fixed map[string]bool,
+	w io.Writer, slowThreshold time.Duration, report *Report) *CheckResult {
+	// Stream: print check name before running
+	if w != nil {
+		fmt.Fprintf(w, "\r  %s  %s...", ui.RenderMuted("○"), check.Name())
+	}
+
+	start := time.Now()
+	result := check.Run(ctx)
+	if result.Name == "" {
+		result.Name = check.Name()
+	}
+	// Set category from check if available
+	if cg, ok := check.(categoryGetter); ok && result.Category == "" {
+		result.Category = cg.Category()
+	}
 
-		// Stream: overwrite line with final result
+	// Don't fix on top of a broken dependency; the fix would likely fail or
+	// be undone once the dependency is repaired.
+	blockedBy := failingDependency(check, failing)
+
+	// Attempt fix if check failed and is fixable
+	if result.Status != StatusOK && check.CanFix() && blockedBy != "" {
+		result.Details = append(result.Details, fmt.Sprintf("Fix skipped: depends on %s, which is still failing", blockedBy))
+	} else if result.Status != StatusOK && check.CanFix() && !fixConfirmed(ctx, check) {
+		result.Details = append(result.Details, "Fix skipped: protected operation not confirmed (rerun with --force)")
+	} else if result.Status != StatusOK && check.CanFix() {
+		// Stream: show the problem with fixing indicator (all on same line)
 		if w != nil {
-			var statusIcon string
-			switch result.Status {
-			case StatusOK:
-				statusIcon = ui.RenderPassIcon()
-			case StatusWarning:
-				statusIcon = ui.RenderWarnIcon()
-			case StatusError:
-				statusIcon = ui.RenderFailIcon()
-			}
-			// Check if slow (hourglass replaces spaces to maintain alignment)
-			isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
-			slowIndicator := "  "
-			if isSlow {
-				report.Summary.Slow++
-				slowIndicator = "⏳"
+			var problemIcon string
+			if result.Status == StatusError {
+				problemIcon = ui.RenderFailIcon()
+			} else {
+				problemIcon = ui.RenderWarnIcon()
 			}
-			fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
+			// Overwrite the "checking" line with problem status + fixing indicator
+			fmt.Fprintf(w, "\r  %s  %s", problemIcon, check.Name())
 			if result.Message != "" {
 				fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
 			}
-			if isSlow {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
+			fmt.Fprintf(w, "%s", ui.RenderMuted(" (fixing)..."))
+		}
+
+		err := check.Fix(ctx)
+		if err == nil {
+			// Re-run check to verify fix worked
+			result = check.Run(ctx)
+			if result.Name == "" {
+				result.Name = check.Name()
 			}
-			fmt.Fprintln(w)
+			// Set category again after re-run
+			if cg, ok := check.(categoryGetter); ok && result.Category == "" {
+				result.Category = cg.Category()
+			}
+			// Update message to indicate fix was applied
+			if result.Status == StatusOK {
+				result.Message = result.Message + " (fixed)"
+				fixed[check.Name()] = true
+			}
+		} else {
+			// Fix failed, add error to details
+			result.Details = append(result.Details, "Fix failed: "+err.Error())
 		}
+	}
 
-		report.Add(result)
+	failing[check.Name()] = result.Status != StatusOK
+
+	// Record total elapsed time including any fix attempts
+	result.Elapsed = time.Since(start)
+
+	// Stream: overwrite line with final result
+	if w != nil {
+		var statusIcon string
+		switch result.Status {
+		case StatusOK:
+			statusIcon = ui.RenderPassIcon()
+		case StatusWarning:
+			statusIcon = ui.RenderWarnIcon()
+		case StatusError:
+			statusIcon = ui.RenderFailIcon()
+		}
+		// Check if slow (hourglass replaces spaces to maintain alignment)
+		isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
+		slowIndicator := "  "
+		if isSlow {
+			report.Summary.Slow++
+			slowIndicator = "⏳"
+		}
+		fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
+		if result.Message != "" {
+			fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
+		}
+		if isSlow {
+			fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
+		}
+		fmt.Fprintln(w)
 	}
 
-	return report
+	return result
+}
+
+// dependencyGetter interface for checks that depend on other checks.
+type dependencyGetter interface {
+	DependsOn() []string
+}
+
+// checkDependencies returns the declared dependencies of a check, if any.
+func checkDependencies(check Check) []string {
+	if dg, ok := check.(dependencyGetter); ok {
+		return dg.DependsOn()
+	}
+	return nil
+}
+
+// failingDependency returns the first dependency of check that is currently
+// failing, or "" if none are.
+func failingDependency(check Check, failing map[string]bool) string {
+	for _, dep := range checkDependencies(check) {
+		if failing[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+// dependsOnAny returns true if check directly depends on any named check.
+func dependsOnAny(check Check, names map[string]bool) bool {
+	for _, dep := range checkDependencies(check) {
+		if names[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+// orderedChecks returns the registered checks sorted so that every check
+// comes after the checks it depends on. Registration order is preserved
+// wherever dependencies allow. Dependencies on unregistered checks are
+// ignored, and checks involved in a cycle keep their registration order.
+func (d *Doctor) orderedChecks() []Check {
+	index := make(map[string]int, len(d.checks))
+	for i, check := range d.checks {
+		index[check.Name()] = i
+	}
+
+	placed := make([]bool, len(d.checks))
+	ordered := make([]Check, 0, len(d.checks))
+
+	for len(ordered) < len(d.checks) {
+		progress := false
+		for i, check := range d.checks {
+			if placed[i] {
+				continue
+			}
+			ready := true
+			for _, dep := range checkDependencies(check) {
+				if j, ok := index[dep]; ok && j != i && !placed[j] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				placed[i] = true
+				ordered = append(ordered, check)
+				progress = true
+				// Restart so earlier-registered checks unblocked by this one
+				// keep their relative position.
+				break
+			}
+		}
+		if !progress {
+			// Cycle: fall back to registration order for what's left
+			for i, check := range d.checks {
+				if !placed[i] {
+					placed[i] = true
+					ordered = append(ordered, check)
+				}
+			}
+		}
+	}
+
+	return ordered
 }
 
 // BaseCheck provides a base implementation for checks that don't support auto-fix.
@@ -213,7 +355,15 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 type BaseCheck struct {
 	CheckName        string
 	CheckDescription string
-	CheckCategory    string // Category for grouping (e.g., CategoryCore)
+	CheckCategory    string   // Category for grouping (e.g., CategoryCore)
+	CheckDependsOn   []string // Names of checks that must pass (or be fixed) first
+}
+
+// DependsOn returns the names of checks this check depends on.
+// With --fix, dependencies are fixed first and a check is not fixed
+// while any of its dependencies is still failing.
+func (b *BaseCheck) DependsOn() []string {
+	return b.CheckDependsOn
 }
 
 // Category returns the check's category for grouping in output.
@@ -330,6 +330,154 @@ func TestDoctor_Fix(t *testing.T) {
 	}
 }
 
+func TestDoctor_OrderedChecks(t *testing.T) {
+	d := NewDoctor()
+
+	config := newMockCheck("config", StatusOK)
+	config.CheckDependsOn = []string{"settings"}
+	d.Register(config)
+	d.Register(newMockCheck("unrelated", StatusOK))
+	d.Register(newMockCheck("settings", StatusOK))
+
+	var names []string
+	for _, c := range d.orderedChecks() {
+		names = append(names, c.Name())
+	}
+
+	want := []string{"unrelated", "settings", "config"}
+	if len(names) != len(want) {
+		t.Fatalf("orderedChecks() = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("orderedChecks() = %v, want %v", names, want)
+		}
+	}
+}
+
+func TestDoctor_OrderedChecksCycle(t *testing.T) {
+	d := NewDoctor()
+
+	a := newMockCheck("a", StatusOK)
+	a.CheckDependsOn = []string{"b"}
+	b := newMockCheck("b", StatusOK)
+	b.CheckDependsOn = []string{"a"}
+	d.RegisterAll(a, b)
+
+	if got := len(d.orderedChecks()); got != 2 {
+		t.Errorf("orderedChecks() returned %d checks, want 2", got)
+	}
+}
+
+func TestDoctor_FixSkipsWhenDependencyFailing(t *testing.T) {
+	d := NewDoctor()
+
+	settings := newMockCheck("settings", StatusError)
+	settings.fixable = true
+	settings.fixError = ErrCannotFix
+
+	config := newMockCheck("config", StatusError)
+	config.fixable = true
+	config.CheckDependsOn = []string{"settings"}
+
+	d.RegisterAll(config, settings)
+	report := d.Fix(&CheckContext{TownRoot: "/test"})
+
+	if config.fixCount != 0 {
+		t.Errorf("dependent Fix() called %d times, want 0", config.fixCount)
+	}
+	var found bool
+	for _, r := range report.Checks {
+		if r.Name != "config" {
+			continue
+		}
+		for _, detail := range r.Details {
+			if detail == "Fix skipped: depends on settings, which is still failing" {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("expected skipped-fix detail on dependent check")
+	}
+}
+
+// destructiveCheck is a mock check whose fix deletes data.
+type destructiveCheck struct {
+	mockCheck
+}
+
+func (d *destructiveCheck) DestructiveFix() bool { return true }
+
+func TestDoctor_FixRequiresConfirmationForDestructiveFixes(t *testing.T) {
+	for _, confirm := range []bool{false, true} {
+		d := NewDoctor()
+		check := &destructiveCheck{mockCheck: *newMockCheck("legacy", StatusWarning)}
+		check.fixable = true
+		plain := newMockCheck("plain", StatusWarning)
+		plain.fixable = true
+		d.RegisterAll(check, plain)
+
+		var asked []string
+		d.Fix(&CheckContext{TownRoot: "/test", ConfirmFix: func(name string) bool {
+			asked = append(asked, name)
+			return confirm
+		}})
+
+		if len(asked) != 1 || asked[0] != "legacy" {
+			t.Errorf("ConfirmFix asked for %v, want only [legacy]", asked)
+		}
+		wantFixes := 0
+		if confirm {
+			wantFixes = 1
+		}
+		if check.fixCount != wantFixes {
+			t.Errorf("confirm=%v: destructive Fix() called %d times, want %d", confirm, check.fixCount, wantFixes)
+		}
+		if plain.fixCount != 1 {
+			t.Errorf("confirm=%v: non-destructive Fix() called %d times, want 1", confirm, plain.fixCount)
+		}
+	}
+}
+
+// flakyFixCheck fails its first fix attempt and succeeds afterwards.
+type flakyFixCheck struct {
+	mockCheck
+}
+
+func (f *flakyFixCheck) Fix(ctx *CheckContext) error {
+	f.fixCount++
+	if f.fixCount == 1 {
+		return ErrCannotFix
+	}
+	f.status = StatusOK
+	return nil
+}
+
+func TestDoctor_FixRerunsDependentsAfterDependencyFixed(t *testing.T) {
+	d := NewDoctor()
+
+	settings := newMockCheck("settings", StatusError)
+	settings.fixable = true
+
+	config := &flakyFixCheck{mockCheck: *newMockCheck("config", StatusError)}
+	config.fixable = true
+	config.CheckDependsOn = []string{"settings"}
+
+	d.RegisterAll(settings, config)
+	report := d.Fix(&CheckContext{TownRoot: "/test"})
+
+	if config.fixCount != 2 {
+		t.Errorf("dependent Fix() called %d times, want 2", config.fixCount)
+	}
+	if !report.IsHealthy() {
+		t.Errorf("report should be healthy after convergence, got %+v", report.Summary)
+	}
+	if report.Summary.Total != 2 {
+		t.Errorf("Total = %d, want 2 (re-runs must not duplicate results)", report.Summary.Total)
+	}
+}
+
 func TestBaseCheck(t *testing.T) {
 	b := &BaseCheck{
 		CheckName:        "test",
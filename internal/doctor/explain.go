@@ -0,0 +1,74 @@
+package doctor
+
+import "github.com/steveyegge/gastown/internal/optree"
+
+// FixExplainer is implemented by checks that can list the subprocesses
+// their Fix runs, for gt doctor --fix --explain. ExplainFix is called after
+// Run, so it can use what Run found.
+type FixExplainer interface {
+	ExplainFix(ctx *CheckContext) []optree.Command
+}
+
+// PlannedFix is a fix gt doctor --fix would attempt.
+type PlannedFix struct {
+	Check       string
+	Message     string
+	Details     []string
+	Commands    []optree.Command // Subprocesses the fix runs, when the check lists them
+	Destructive bool             // The fix asks for confirmation before running
+	BlockedBy   string           // Failing dependency that would skip the fix
+}
+
+// PlanFix runs every check without fixing anything and returns the fixes
+// FixStreaming would attempt on its first pass, in the order it would
+// attempt them.
+func (d *Doctor) PlanFix(ctx *CheckContext) []PlannedFix {
+	var plan []PlannedFix
+	failing := make(map[string]bool)
+	for _, check := range d.orderedChecks() {
+		result := check.Run(ctx)
+		failing[check.Name()] = result.Status != StatusOK
+		if result.Status == StatusOK || !check.CanFix() {
+			continue
+		}
+		p := PlannedFix{
+			Check:     check.Name(),
+			Message:   result.Message,
+			Details:   result.Details,
+			BlockedBy: failingDependency(check, failing),
+		}
+		if df, ok := check.(DestructiveFixer); ok {
+			p.Destructive = df.DestructiveFix()
+		}
+		if fe, ok := check.(FixExplainer); ok {
+			p.Commands = fe.ExplainFix(ctx)
+		}
+		plan = append(plan, p)
+	}
+	return plan
+}
+
+// ExplainPlan renders planned fixes as an explain tree under root.
+func ExplainPlan(root *optree.Node, plan []PlannedFix) {
+	for _, p := range plan {
+		node := root.Step("Fix %s: %s", p.Check, p.Message)
+		if p.BlockedBy != "" {
+			node.Step("skipped while %s is failing", p.BlockedBy)
+			continue
+		}
+		if p.Destructive {
+			node.Step("asks for confirmation first (destructive)")
+		}
+		for _, c := range p.Commands {
+			node.Run(c)
+		}
+		if len(p.Commands) == 0 {
+			for _, d := range p.Details {
+				node.Step("%s", d)
+			}
+		}
+	}
+	if len(plan) == 0 {
+		root.Step("nothing to fix")
+	}
+}
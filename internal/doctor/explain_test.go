@@ -0,0 +1,65 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/optree"
+)
+
+// explainedCheck is a mock check that lists the commands its fix runs.
+type explainedCheck struct {
+	mockCheck
+}
+
+func (e *explainedCheck) ExplainFix(ctx *CheckContext) []optree.Command {
+	return []optree.Command{optree.In(ctx.TownRoot, "bd", "close", "gt-1")}
+}
+
+func TestDoctor_PlanFix(t *testing.T) {
+	d := NewDoctor()
+
+	settings := &explainedCheck{mockCheck: *newMockCheck("settings", StatusError)}
+	settings.fixable = true
+	config := newMockCheck("config", StatusWarning)
+	config.fixable = true
+	config.CheckDependsOn = []string{"settings"}
+	healthy := newMockCheck("healthy", StatusOK)
+	healthy.fixable = true
+	unfixable := newMockCheck("unfixable", StatusError)
+	legacy := &destructiveCheck{mockCheck: *newMockCheck("legacy", StatusWarning)}
+	legacy.fixable = true
+
+	d.RegisterAll(config, settings, healthy, unfixable, legacy)
+	plan := d.PlanFix(&CheckContext{TownRoot: "/town"})
+
+	if settings.fixCount+config.fixCount+legacy.fixCount != 0 {
+		t.Fatal("PlanFix() ran a fix")
+	}
+	var names []string
+	for _, p := range plan {
+		names = append(names, p.Check)
+	}
+	if got := strings.Join(names, ","); got != "settings,config,legacy" {
+		t.Fatalf("PlanFix() planned %s, want settings,config,legacy", got)
+	}
+	if len(plan[0].Commands) != 1 || plan[0].Commands[0].Dir != "/town" {
+		t.Errorf("settings commands = %+v", plan[0].Commands)
+	}
+	if plan[1].BlockedBy != "settings" {
+		t.Errorf("config BlockedBy = %q, want settings", plan[1].BlockedBy)
+	}
+	if !plan[2].Destructive {
+		t.Error("legacy not marked destructive")
+	}
+
+	root := optree.New("gt doctor --fix")
+	ExplainPlan(root, plan)
+	var b strings.Builder
+	root.Render(&b)
+	for _, want := range []string{"$ bd close gt-1  (in /town)", "skipped while settings is failing", "asks for confirmation"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("plan missing %q:\n%s", want, b.String())
+		}
+	}
+}
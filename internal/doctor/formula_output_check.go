@@ -0,0 +1,60 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// FormulaOutputCheck flags formula output path templates that could write
+// outside the output root: absolute or ".." paths, and paths built from
+// free-form text such as PR titles.
+type FormulaOutputCheck struct {
+	BaseCheck
+}
+
+// NewFormulaOutputCheck creates a new formula output path check.
+func NewFormulaOutputCheck() *FormulaOutputCheck {
+	return &FormulaOutputCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "formula-output-paths",
+			CheckDescription: "Check formula output paths stay inside the output root",
+			CheckCategory:    CategoryConfig,
+		},
+	}
+}
+
+// Run lints the output templates of the formulas in the town's .beads/formulas.
+func (c *FormulaOutputCheck) Run(ctx *CheckContext) *CheckResult {
+	paths, _ := filepath.Glob(filepath.Join(ctx.TownRoot, ".beads", "formulas", "*.formula.toml"))
+	sort.Strings(paths)
+
+	var details []string
+	for _, path := range paths {
+		f, err := formula.ParseFile(path)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".formula.toml")
+		for _, problem := range formula.LintOutputPaths(f) {
+			details = append(details, fmt.Sprintf("%s: %s", name, problem))
+		}
+	}
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Formula output paths stay inside the output root",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d formula output path problem(s)", len(details)),
+		Details: details,
+		FixHint: "Build output paths from review_id, formula_name and leg.id; gt refuses to write outside the rig or the town's output_root",
+	}
+}
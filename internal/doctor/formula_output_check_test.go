@@ -0,0 +1,38 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormulaOutputCheck(t *testing.T) {
+	town := t.TempDir()
+	dir := filepath.Join(town, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, directory string) {
+		t.Helper()
+		content := "formula = \"" + name + "\"\ntype = \"convoy\"\n\n[output]\ndirectory = \"" + directory + "\"\n\n[[legs]]\nid = \"a\"\ntitle = \"A\"\n"
+		if err := os.WriteFile(filepath.Join(dir, name+".formula.toml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := NewFormulaOutputCheck()
+
+	write("safe", ".reviews/{{.review_id}}")
+	if result := check.Run(&CheckContext{TownRoot: town}); result.Status != StatusOK {
+		t.Errorf("safe: status = %v (%v), want OK", result.Status, result.Details)
+	}
+
+	write("escapes", "../../{{.pr_title}}")
+	result := check.Run(&CheckContext{TownRoot: town})
+	if result.Status != StatusWarning || len(result.Details) != 2 {
+		t.Fatalf("escapes: status = %v, details %q; want two warnings", result.Status, result.Details)
+	}
+	if !strings.HasPrefix(result.Details[0], "escapes: output.directory") {
+		t.Errorf("detail = %q", result.Details[0])
+	}
+}
@@ -0,0 +1,127 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// FormulaParityCheck verifies that the installed bd handles the formulas
+// gt hands over to it. gt runs some formula commands natively and
+// delegates others to bd cook and bd mol wisp, so a bd that is too old or
+// too new for gt's formulas breaks only the delegated commands.
+type FormulaParityCheck struct {
+	BaseCheck
+	beads func() (deps.BeadsStatus, string) // Empty uses deps.CheckBeads
+}
+
+// NewFormulaParityCheck creates a new formula parity check.
+func NewFormulaParityCheck() *FormulaParityCheck {
+	return &FormulaParityCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "formula-bd-parity",
+			CheckDescription: "Check bd handles the formulas gt delegates to it",
+			CheckCategory:    CategoryConfig,
+		},
+	}
+}
+
+// Run compares the installed bd against deps.FormulaFeatures.
+func (c *FormulaParityCheck) Run(ctx *CheckContext) *CheckResult {
+	beadsFn := c.beads
+	if beadsFn == nil {
+		beadsFn = deps.CheckBeads
+	}
+	status, version := beadsFn()
+	switch status {
+	case deps.BeadsNotFound:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "bd not found; gt sling and patrol wisps can't cook formulas",
+			FixHint: "Install bd: go install " + deps.BeadsInstallPath,
+		}
+	case deps.BeadsUnknown:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not determine the bd version; formula parity unknown",
+		}
+	}
+
+	schema, from := townFormulaSchema(ctx.TownRoot)
+	mismatches := deps.CheckFormulaFeatures(version, schema)
+	if len(mismatches) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("bd %s handles schema %d formulas", version, schema),
+		}
+	}
+
+	var details []string
+	affected := make(map[string]bool)
+	for _, m := range mismatches {
+		detail := fmt.Sprintf("%s: %s", m.Feature.Name, m.Reason)
+		if m.Feature.Schema > 0 && len(from) > 0 {
+			detail += fmt.Sprintf(" (needed by %s)", strings.Join(from, ", "))
+		}
+		details = append(details, detail, "  affects: "+strings.Join(m.Feature.Commands, ", "))
+		for _, cmd := range m.Feature.Commands {
+			affected[cmd] = true
+		}
+	}
+	commands := make([]string, 0, len(affected))
+	for cmd := range affected {
+		commands = append(commands, cmd)
+	}
+	sort.Strings(commands)
+
+	hint := "Upgrade bd: go install " + deps.BeadsInstallPath
+	for _, m := range mismatches {
+		if m.Feature.BeforeBD != "" {
+			hint = "Upgrade gt, or install a bd older than " + m.Feature.BeforeBD
+			break
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("bd %s mismatches %d formula feature(s); %d gt command(s) affected", version, len(mismatches), len(commands)),
+		Details: details,
+		FixHint: hint + "; gt formula run/show/list are unaffected",
+	}
+}
+
+// townFormulaSchema returns the newest schema among the town's formulas
+// (at least 1), and the formulas that declare it when it's newer than 1.
+func townFormulaSchema(townRoot string) (int, []string) {
+	paths, _ := filepath.Glob(filepath.Join(townRoot, ".beads", "formulas", "*.formula.toml"))
+	sort.Strings(paths)
+
+	newest := 1
+	var names []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from a glob of the town's formula dir
+		if err != nil {
+			continue
+		}
+		schema, err := formula.DeclaredSchemaTOML(data)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".formula.toml")
+		switch {
+		case schema > newest:
+			newest, names = schema, []string{name}
+		case schema == newest && schema > 1:
+			names = append(names, name)
+		}
+	}
+	return newest, names
+}
@@ -0,0 +1,59 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/deps"
+)
+
+func TestFormulaParityCheck(t *testing.T) {
+	town := t.TempDir()
+	dir := filepath.Join(town, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name+".formula.toml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(status deps.BeadsStatus, version string) *CheckResult {
+		check := NewFormulaParityCheck()
+		check.beads = func() (deps.BeadsStatus, string) { return status, version }
+		return check.Run(&CheckContext{TownRoot: town})
+	}
+
+	write("legacy", "formula = \"legacy\"\n")
+	if result := run(deps.BeadsOK, "0.45.0"); result.Status != StatusOK {
+		t.Errorf("schema 1 on bd 0.45.0: status = %v (%v), want OK", result.Status, result.Details)
+	}
+
+	write("modern", "schema = 2\nformula = \"modern\"\n")
+	result := run(deps.BeadsOK, "0.45.0")
+	if result.Status != StatusWarning {
+		t.Fatalf("schema 2 on bd 0.45.0: status = %v, want warning", result.Status)
+	}
+	details := strings.Join(result.Details, "\n")
+	for _, want := range []string{"schema 2", "needed by modern", "gt sling <formula>"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("details missing %q:\n%s", want, details)
+		}
+	}
+
+	if result := run(deps.BeadsOK, "0.46.0"); result.Status != StatusOK {
+		t.Errorf("schema 2 on bd 0.46.0: status = %v (%v), want OK", result.Status, result.Details)
+	}
+
+	result = run(deps.BeadsOK, "1.0.0")
+	if result.Status != StatusWarning || !strings.Contains(result.FixHint, "Upgrade gt") {
+		t.Errorf("bd 1.0.0: status = %v, hint %q; want warning to upgrade gt", result.Status, result.FixHint)
+	}
+
+	if result := run(deps.BeadsNotFound, ""); result.Status != StatusWarning {
+		t.Errorf("bd missing: status = %v, want warning", result.Status)
+	}
+}
@@ -0,0 +1,112 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// FormulaRequiresCheck verifies that the tools the town's formulas declare
+// in requires exist on every machine legs are dispatched to: the local host
+// and the hosts of remote rigs.
+type FormulaRequiresCheck struct {
+	BaseCheck
+}
+
+// NewFormulaRequiresCheck creates a new formula requirements check.
+func NewFormulaRequiresCheck() *FormulaRequiresCheck {
+	return &FormulaRequiresCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "formula-requires",
+			CheckDescription: "Check tools required by formulas are installed",
+			CheckCategory:    CategoryConfig,
+		},
+	}
+}
+
+// Run checks the requirements of the formulas in the town's .beads/formulas.
+func (c *FormulaRequiresCheck) Run(ctx *CheckContext) *CheckResult {
+	paths, _ := filepath.Glob(filepath.Join(ctx.TownRoot, ".beads", "formulas", "*.formula.toml"))
+	sort.Strings(paths)
+
+	requires := make(map[string][]formula.Requirement)
+	var names []string
+	var details []string
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".formula.toml")
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from a glob of the town's formula dir
+		if err != nil {
+			continue
+		}
+		reqs, err := formula.ParseRequiresTOML(data)
+		if err != nil {
+			details = append(details, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if len(reqs) > 0 {
+			requires[name] = reqs
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 && len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No formulas declare required tools",
+		}
+	}
+
+	for _, target := range dispatchTargets(ctx.TownRoot) {
+		for _, name := range names {
+			for _, p := range formula.CheckRequirements(target.conn, requires[name]) {
+				details = append(details, fmt.Sprintf("%s on %s: %v", name, target.description, p))
+			}
+		}
+	}
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Tools required by %d formula(s) are installed", len(names)),
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d formula requirement(s) not met", len(details)),
+		Details: details,
+		FixHint: "Install the missing tools on the listed hosts; gt formula run refuses formulas whose tools are missing",
+	}
+}
+
+// dispatchTarget is a machine formula legs may run on.
+type dispatchTarget struct {
+	conn        connection.Connection
+	description string
+}
+
+// dispatchTargets returns the local host and the distinct hosts of the
+// town's remote rigs.
+func dispatchTargets(townRoot string) []dispatchTarget {
+	targets := []dispatchTarget{{conn: connection.NewLocalConnection(), description: "local host"}}
+	rigs, err := discoverRigs(townRoot)
+	if err != nil {
+		return targets
+	}
+	seen := map[string]bool{"local host": true}
+	for _, name := range rigs {
+		conn, description, err := rig.DispatchTarget(filepath.Join(townRoot, name))
+		if err != nil || seen[description] {
+			continue
+		}
+		seen[description] = true
+		targets = append(targets, dispatchTarget{conn: conn, description: description})
+	}
+	return targets
+}
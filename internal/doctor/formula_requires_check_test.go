@@ -0,0 +1,43 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormulaRequiresCheck(t *testing.T) {
+	town := t.TempDir()
+	check := NewFormulaRequiresCheck()
+
+	if result := check.Run(&CheckContext{TownRoot: town}); result.Status != StatusOK {
+		t.Errorf("without formulas: status = %v, want OK", result.Status)
+	}
+
+	dir := filepath.Join(town, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, requires string) {
+		t.Helper()
+		content := "formula = \"" + name + "\"\ntype = \"convoy\"\nrequires = " + requires + "\n"
+		if err := os.WriteFile(filepath.Join(dir, name+".formula.toml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("shell-only", `["sh"]`)
+	if result := check.Run(&CheckContext{TownRoot: town}); result.Status != StatusOK {
+		t.Errorf("installed tools: status = %v (%v), want OK", result.Status, result.Details)
+	}
+
+	write("exotic", `["gt-test-missing-tool"]`)
+	result := check.Run(&CheckContext{TownRoot: town})
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Fatalf("missing tool: status = %v, details %q; want one warning", result.Status, result.Details)
+	}
+	if !strings.Contains(result.Details[0], "exotic on local host") || !strings.Contains(result.Details[0], "gt-test-missing-tool") {
+		t.Errorf("detail = %q", result.Details[0])
+	}
+}
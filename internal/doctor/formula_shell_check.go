@@ -0,0 +1,59 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// FormulaShellCheck flags formulas whose prompts build shell commands by
+// concatenating untrusted context, such as PR titles, into them.
+type FormulaShellCheck struct {
+	BaseCheck
+}
+
+// NewFormulaShellCheck creates a new formula shell interpolation check.
+func NewFormulaShellCheck() *FormulaShellCheck {
+	return &FormulaShellCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "formula-shell-interpolation",
+			CheckDescription: "Check formulas don't put untrusted text into shell commands",
+			CheckCategory:    CategoryConfig,
+		},
+	}
+}
+
+// Run lints the formulas in the town's .beads/formulas.
+func (c *FormulaShellCheck) Run(ctx *CheckContext) *CheckResult {
+	paths, _ := filepath.Glob(filepath.Join(ctx.TownRoot, ".beads", "formulas", "*.formula.toml"))
+	sort.Strings(paths)
+
+	var details []string
+	for _, path := range paths {
+		f, err := formula.ParseFile(path)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".formula.toml")
+		for _, finding := range formula.LintShell(f) {
+			details = append(details, fmt.Sprintf("%s: %s", name, finding))
+		}
+	}
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "no unquoted interpolation found",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d unquoted interpolation(s) of untrusted text into shell commands", len(details)),
+		Details: details,
+		FixHint: "Pipe the values through shellquote ({{.pr_title | shellquote}}) or have the agent read them from a file; gt refuses to render such templates when the value contains shell metacharacters",
+	}
+}
@@ -0,0 +1,38 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormulaShellCheck(t *testing.T) {
+	town := t.TempDir()
+	dir := filepath.Join(town, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, prompt string) {
+		t.Helper()
+		content := "formula = \"" + name + "\"\ntype = \"convoy\"\n\n[prompts]\nbase = '''\n" + prompt + "\n'''\n\n[[legs]]\nid = \"a\"\ntitle = \"A\"\n"
+		if err := os.WriteFile(filepath.Join(dir, name+".formula.toml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := NewFormulaShellCheck()
+
+	write("quoted", "Run `gh pr comment {{.pr_number}} --body {{.pr_title | shellquote}}`.")
+	if result := check.Run(&CheckContext{TownRoot: town}); result.Status != StatusOK {
+		t.Errorf("quoted: status = %v (%v), want OK", result.Status, result.Details)
+	}
+
+	write("unquoted", "Run `gh pr comment {{.pr_number}} --body \"{{.pr_title}}\"`.")
+	result := check.Run(&CheckContext{TownRoot: town})
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Fatalf("unquoted: status = %v, details %q; want one warning", result.Status, result.Details)
+	}
+	if !strings.Contains(result.Details[0], "unquoted: prompts.base line 1") {
+		t.Errorf("detail = %q", result.Details[0])
+	}
+}
@@ -0,0 +1,155 @@
+package doctor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/readonly"
+)
+
+// MaxHistoryRuns is the number of doctor runs kept in the history log.
+const MaxHistoryRuns = 200
+
+// Score rates the report from 0 to 100: passing checks count fully,
+// warnings count half, errors count nothing. An empty report scores 100.
+func (r *Report) Score() int {
+	if r.Summary.Total == 0 {
+		return 100
+	}
+	healthy := float64(r.Summary.OK) + float64(r.Summary.Warnings)/2
+	return int(math.Round(100 * healthy / float64(r.Summary.Total)))
+}
+
+// HistoryIssue is a warning or error recorded in a doctor run.
+type HistoryIssue struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"` // Warning or Error
+	Message string `json:"message"`
+}
+
+// HistoryEntry is the persisted summary of one doctor run.
+type HistoryEntry struct {
+	Time     time.Time      `json:"time"`
+	Rig      string         `json:"rig,omitempty"`
+	Fix      bool           `json:"fix,omitempty"`
+	Score    int            `json:"score"`
+	Total    int            `json:"total"`
+	OK       int            `json:"ok"`
+	Warnings int            `json:"warnings"`
+	Errors   int            `json:"errors"`
+	Issues   []HistoryIssue `json:"issues,omitempty"`
+}
+
+// NewHistoryEntry summarizes a report for the history log.
+func NewHistoryEntry(r *Report, rig string, fix bool) HistoryEntry {
+	e := HistoryEntry{
+		Time:     r.Timestamp,
+		Rig:      rig,
+		Fix:      fix,
+		Score:    r.Score(),
+		Total:    r.Summary.Total,
+		OK:       r.Summary.OK,
+		Warnings: r.Summary.Warnings,
+		Errors:   r.Summary.Errors,
+	}
+	for _, check := range r.Checks {
+		if check.Status == StatusOK {
+			continue
+		}
+		e.Issues = append(e.Issues, HistoryIssue{
+			Check:   check.Name,
+			Status:  check.Status.String(),
+			Message: check.Message,
+		})
+	}
+	return e
+}
+
+// NewIssues returns the issues in e that prev did not have, or that got
+// worse (warning to error) since prev.
+func (e HistoryEntry) NewIssues(prev HistoryEntry) []HistoryIssue {
+	before := make(map[string]string, len(prev.Issues))
+	for _, issue := range prev.Issues {
+		before[issue.Check] = issue.Status
+	}
+	var added []HistoryIssue
+	for _, issue := range e.Issues {
+		status, ok := before[issue.Check]
+		if !ok || (status != issue.Status && issue.Status == StatusError.String()) {
+			added = append(added, issue)
+		}
+	}
+	return added
+}
+
+// HistoryPath returns the doctor history log for a town.
+func HistoryPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "doctor", "history.jsonl")
+}
+
+// AppendHistory records a doctor run, trimming the log to the most recent
+// MaxHistoryRuns entries. In read-only mode it records nothing.
+func AppendHistory(townRoot string, entry HistoryEntry) error {
+	if readonly.Enabled() {
+		return nil
+	}
+	path := HistoryPath(townRoot)
+	unlock, err := fsx.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := ReadHistory(townRoot)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+	if len(entries) > MaxHistoryRuns {
+		entries = entries[len(entries)-MaxHistoryRuns:]
+	}
+
+	var buf []byte
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	if err := fsx.WriteFile(path, buf, 0644); err != nil {
+		return fmt.Errorf("writing doctor history: %w", err)
+	}
+	return nil
+}
+
+// ReadHistory returns recorded doctor runs, oldest first. A missing log is
+// empty; malformed lines are skipped.
+func ReadHistory(townRoot string) ([]HistoryEntry, error) {
+	f, err := os.Open(HistoryPath(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading doctor history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e HistoryEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
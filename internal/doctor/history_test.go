@@ -0,0 +1,68 @@
+package doctor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportScore(t *testing.T) {
+	r := NewReport()
+	if got := r.Score(); got != 100 {
+		t.Errorf("empty report Score = %d, want 100", got)
+	}
+	r.Add(&CheckResult{Name: "a", Status: StatusOK})
+	r.Add(&CheckResult{Name: "b", Status: StatusOK})
+	r.Add(&CheckResult{Name: "c", Status: StatusWarning})
+	r.Add(&CheckResult{Name: "d", Status: StatusError})
+	if got := r.Score(); got != 63 {
+		t.Errorf("Score = %d, want 63", got)
+	}
+}
+
+func TestHistoryEntryNewIssues(t *testing.T) {
+	prev := HistoryEntry{Issues: []HistoryIssue{
+		{Check: "daemon", Status: "Warning"},
+		{Check: "routes-config", Status: "Warning"},
+	}}
+	cur := HistoryEntry{Issues: []HistoryIssue{
+		{Check: "daemon", Status: "Warning"},
+		{Check: "routes-config", Status: "Error"},
+		{Check: "wisp-gc", Status: "Warning"},
+	}}
+	got := cur.NewIssues(prev)
+	if len(got) != 2 || got[0].Check != "routes-config" || got[1].Check != "wisp-gc" {
+		t.Errorf("NewIssues = %+v, want routes-config (worsened) and wisp-gc", got)
+	}
+}
+
+func TestAppendHistory(t *testing.T) {
+	townRoot := t.TempDir()
+	if entries, err := ReadHistory(townRoot); err != nil || entries != nil {
+		t.Fatalf("ReadHistory on empty town = %v, %v", entries, err)
+	}
+
+	r := NewReport()
+	r.Add(&CheckResult{Name: "daemon", Status: StatusWarning, Message: "not running"})
+	for i := 0; i < MaxHistoryRuns+5; i++ {
+		e := NewHistoryEntry(r, "", false)
+		e.Time = time.Unix(int64(i), 0)
+		if err := AppendHistory(townRoot, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := ReadHistory(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxHistoryRuns {
+		t.Fatalf("kept %d runs, want %d", len(entries), MaxHistoryRuns)
+	}
+	if entries[0].Time.Unix() != 5 {
+		t.Errorf("oldest kept run = %v, want the 6th", entries[0].Time.Unix())
+	}
+	last := entries[len(entries)-1]
+	if last.Score != 50 || len(last.Issues) != 1 || last.Issues[0].Message != "not running" {
+		t.Errorf("last entry = %+v", last)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // LifecycleHygieneCheck detects and cleans up stale lifecycle state.
@@ -62,7 +64,7 @@ func (c *LifecycleHygieneCheck) checkDeaconInbox(ctx *CheckContext) int {
 	cmd.Dir = ctx.TownRoot
 	cmd.Env = append(cmd.Environ(), "BEADS_NO_DAEMON=1")
 
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return 0 // Can't check, assume OK
 	}
@@ -103,7 +105,7 @@ func (c *LifecycleHygieneCheck) Fix(ctx *CheckContext) error {
 		cmd := exec.Command("gt", "mail", "delete", msg.ID) //nolint:gosec // G204: msg.ID is from internal state, not user input
 		cmd.Dir = ctx.TownRoot
 		cmd.Env = append(cmd.Environ(), "BEADS_NO_DAEMON=1")
-		if err := cmd.Run(); err != nil {
+		if err := cmdtrace.Run(cmd); err != nil {
 			errors = append(errors, fmt.Sprintf("failed to delete message %s: %v", msg.ID, err))
 		}
 	}
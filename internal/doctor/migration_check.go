@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // MigrationReadiness represents the overall migration readiness status.
@@ -147,12 +149,12 @@ func (c *MigrationReadinessCheck) checkVersions() MigrationVersions {
 	}
 
 	// Get gt version
-	if output, err := exec.Command("gt", "version").Output(); err == nil {
+	if output, err := cmdtrace.Output(exec.Command("gt", "version")); err == nil {
 		versions.GT = strings.TrimSpace(string(output))
 	}
 
 	// Get bd version
-	if output, err := exec.Command("bd", "version").Output(); err == nil {
+	if output, err := cmdtrace.Output(exec.Command("bd", "version")); err == nil {
 		versionStr := strings.TrimSpace(string(output))
 		versions.BD = versionStr
 		// Check if bd supports Dolt (version 0.40.0+ supports Dolt)
@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/optree"
 )
 
 // CheckMisclassifiedWisps detects issues that should be marked as wisps but aren't.
@@ -218,10 +220,24 @@ func (c *CheckMisclassifiedWisps) Fix(ctx *CheckContext) error {
 		closeReason := fmt.Sprintf("Closed by doctor: %s (should have been ephemeral wisp)", wisp.reason)
 		cmd := exec.Command("bd", "close", wisp.id, "--reason", closeReason)
 		cmd.Dir = workDir
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 			lastErr = fmt.Errorf("%s/%s: %v (%s)", wisp.rigName, wisp.id, err, string(output))
 		}
 	}
 
 	return lastErr
 }
+
+// ExplainFix lists the bd close commands Fix runs.
+func (c *CheckMisclassifiedWisps) ExplainFix(ctx *CheckContext) []optree.Command {
+	var cmds []optree.Command
+	for _, wisp := range c.misclassified {
+		workDir := ctx.TownRoot
+		if wisp.rigName != "town" {
+			workDir = filepath.Join(ctx.TownRoot, wisp.rigName)
+		}
+		closeReason := fmt.Sprintf("Closed by doctor: %s (should have been ephemeral wisp)", wisp.reason)
+		cmds = append(cmds, optree.In(workDir, "bd", "close", wisp.id, "--reason", closeReason))
+	}
+	return cmds
+}
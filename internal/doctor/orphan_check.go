@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/optree"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -158,6 +159,18 @@ func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// ExplainFix lists the sessions Fix kills, with their processes.
+func (c *OrphanSessionCheck) ExplainFix(ctx *CheckContext) []optree.Command {
+	var cmds []optree.Command
+	for _, sess := range c.orphanSessions {
+		if isCrewSession(sess) {
+			continue
+		}
+		cmds = append(cmds, optree.Cmd("tmux", "kill-session", "-t", sess))
+	}
+	return cmds
+}
+
 // isCrewSession returns true if the session name matches the crew pattern.
 // Crew sessions are gt-<rig>-crew-<name> and are protected from auto-cleanup.
 func isCrewSession(session string) bool {
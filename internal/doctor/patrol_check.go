@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/fsx"
 	"github.com/steveyegge/gastown/internal/templates"
 )
 
@@ -96,7 +98,7 @@ func (c *PatrolMoleculesExistCheck) checkPatrolFormulas(rigPath string) []string
 	// This checks .beads/formulas/, ~/.beads/formulas/, and $GT_ROOT/.beads/formulas/
 	cmd := exec.Command("bd", "formula", "list")
 	cmd.Dir = rigPath
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		// Can't check formulas, assume all missing
 		return patrolFormulas
@@ -500,7 +502,7 @@ func (c *PatrolRolesHavePromptsCheck) Fix(ctx *CheckContext) error {
 			}
 
 			destPath := filepath.Join(templatesDir, roleFile)
-			if err := os.WriteFile(destPath, content, 0644); err != nil {
+			if err := fsx.WriteFile(destPath, content, 0644); err != nil {
 				return fmt.Errorf("writing %s in %s: %w", roleFile, rigName, err)
 			}
 		}
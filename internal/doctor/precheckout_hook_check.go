@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // BranchProtectionCheck verifies that the post-checkout hook includes branch
@@ -25,6 +27,7 @@ func NewBranchProtectionCheck() *BranchProtectionCheck {
 				CheckName:        "branch-protection",
 				CheckDescription: "Verify post-checkout hook protects town root branch",
 				CheckCategory:    CategoryHooks,
+				CheckDependsOn:   []string{"town-git"},
 			},
 		},
 	}
@@ -211,7 +214,7 @@ func (c *BranchProtectionCheck) Fix(ctx *CheckContext) error {
 	}
 
 	// Write the hook
-	if err := os.WriteFile(hookPath, []byte(newContent), 0755); err != nil {
+	if err := fsx.WriteFile(hookPath, []byte(newContent), 0755); err != nil {
 		return fmt.Errorf("writing hook: %w", err)
 	}
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/daemon"
 )
 
@@ -91,7 +92,7 @@ func (c *RepoFingerprintCheck) checkBeadsDir(workDir, location string) *CheckRes
 	cmd.Stderr = &stderr
 
 	// bd doctor exits with non-zero if there are warnings, so ignore exit code
-	_ = cmd.Run()
+	_ = cmdtrace.Run(cmd)
 
 	// Parse JSON output
 	var result bdDoctorResult
@@ -167,7 +168,7 @@ func (c *RepoFingerprintCheck) Fix(ctx *CheckContext) error {
 	cmd.Dir = filepath.Dir(c.beadsDir) // Parent of .beads directory
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return fmt.Errorf("bd migrate --update-repo-id failed: %v: %s", err, stderr.String())
 	}
 
@@ -177,7 +178,7 @@ func (c *RepoFingerprintCheck) Fix(ctx *CheckContext) error {
 		// Stop daemon
 		stopCmd := exec.Command("gt", "daemon", "stop")
 		stopCmd.Dir = ctx.TownRoot
-		_ = stopCmd.Run() // Ignore errors
+		_ = cmdtrace.Run(stopCmd) // Ignore errors
 
 		// Wait a moment
 		time.Sleep(500 * time.Millisecond)
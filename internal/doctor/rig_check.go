@@ -9,8 +9,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // RigIsGitRepoCheck verifies the rig has a valid mayor/rig git clone.
@@ -834,7 +836,7 @@ func (c *BeadsConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check if bd command works
 	cmd := exec.Command("bd", "stats", "--json")
 	cmd.Dir = c.rigPath
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusError,
@@ -982,6 +984,9 @@ func (c *BeadsRedirectCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// DestructiveFix reports that fix may delete a conflicting local .beads/ directory.
+func (c *BeadsRedirectCheck) DestructiveFix() bool { return true }
+
 // Fix creates or corrects the rig-level beads redirect, or initializes beads if missing.
 func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 	if ctx.RigName == "" {
@@ -1018,11 +1023,11 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		// Run bd init with the configured prefix
 		cmd := exec.Command("bd", "init", "--prefix", prefix)
 		cmd.Dir = rigPath
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 			// bd might not be installed - create minimal config.yaml
 			configPath := filepath.Join(rigBeadsDir, "config.yaml")
 			configContent := fmt.Sprintf("prefix: %s\n", prefix)
-			if writeErr := os.WriteFile(configPath, []byte(configContent), 0644); writeErr != nil {
+			if writeErr := fsx.WriteFile(configPath, []byte(configContent), 0644); writeErr != nil {
 				return fmt.Errorf("bd init failed (%v) and fallback config creation failed: %w", err, writeErr)
 			}
 			// Continue - minimal config created
@@ -1031,7 +1036,7 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 			// Configure custom types for Gas Town (beads v0.46.0+)
 			configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 			configCmd.Dir = rigPath
-			_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+			_, _ = cmdtrace.CombinedOutput(configCmd) // Ignore errors - older beads don't need this
 		}
 		return nil
 	}
@@ -1052,7 +1057,7 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		}
 
 		// Write redirect file
-		if err := os.WriteFile(redirectPath, []byte("mayor/rig/.beads\n"), 0644); err != nil {
+		if err := fsx.WriteFile(redirectPath, []byte("mayor/rig/.beads\n"), 0644); err != nil {
 			return fmt.Errorf("writing redirect file: %w", err)
 		}
 	}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // RigNameMismatchCheck detects when a rig's config.json has a name or beads
@@ -66,7 +68,7 @@ func saveRigConfigLocal(rigPath string, cfg *rigConfigLocal) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, data, 0644)
+	return fsx.WriteFile(configPath, data, 0644)
 }
 
 // Run checks for name/prefix mismatches between config.json and the
@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // RoutingModeCheck detects when beads routing.mode is set to "auto", which can
@@ -67,7 +69,7 @@ func (c *RoutingModeCheck) checkRoutingMode(beadsDir, location string) *CheckRes
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		// If the config key doesn't exist, that means it defaults to "auto"
 		if strings.Contains(stderr.String(), "not found") || strings.Contains(stderr.String(), "not set") {
 			return &CheckResult{
@@ -139,7 +141,7 @@ func (c *RoutingModeCheck) setRoutingMode(beadsDir string) error {
 	cmd.Dir = filepath.Dir(beadsDir)
 	cmd.Env = append(cmd.Environ(), "BEADS_DIR="+beadsDir)
 
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("bd config set failed: %s", strings.TrimSpace(string(output)))
 	}
 
@@ -0,0 +1,236 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// minTmuxVersion is the oldest tmux gt dispatches to. 3.2 added
+// display-popup, which the mail and agent bindings use.
+var minTmuxVersion = [2]int{3, 2}
+
+var tmuxVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// SessionBackendCheck verifies the tmux session backend that polecat
+// dispatch depends on: tmux is installed and recent enough, its server
+// answers, its socket directory has the permissions tmux insists on, and
+// no orphaned gt-/hq- sessions are left behind.
+type SessionBackendCheck struct {
+	FixableCheck
+	backend       string                 // Empty reads the town config
+	tmuxVersion   func() (string, error) // Output of tmux -V
+	sessionLister SessionLister
+	socketDir     string              // Empty derives it from TMUX_TMPDIR and the uid
+	orphans       *OrphanSessionCheck // Orphan detection, cached for Fix
+	badSocketDir  string              // Socket dir with unsafe permissions, cached for Fix
+}
+
+// NewSessionBackendCheck creates a new session backend check.
+func NewSessionBackendCheck() *SessionBackendCheck {
+	return &SessionBackendCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "session-backend",
+				CheckDescription: "Check tmux session backend health",
+				CheckCategory:    CategoryInfrastructure,
+			},
+		},
+	}
+}
+
+// sessionBackend returns the town's configured session backend.
+func (c *SessionBackendCheck) sessionBackend(townRoot string) session.BackendType {
+	if c.backend != "" {
+		return session.BackendType(c.backend)
+	}
+	return session.BackendTypeFor(townRoot)
+}
+
+// Run checks the tmux binary, server, socket directory, and sessions.
+func (c *SessionBackendCheck) Run(ctx *CheckContext) *CheckResult {
+	c.badSocketDir = ""
+	c.orphans = nil
+
+	if backend := c.sessionBackend(ctx.TownRoot); backend != session.BackendTmux {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Skipped: session backend is %q, not tmux", backend),
+		}
+	}
+
+	versionFn := c.tmuxVersion
+	if versionFn == nil {
+		versionFn = func() (string, error) {
+			out, err := exec.Command("tmux", "-V").Output()
+			return strings.TrimSpace(string(out)), err
+		}
+	}
+	version, err := versionFn()
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "tmux is not installed or not runnable",
+			Details: []string{err.Error()},
+			FixHint: "Install tmux, or switch backends with 'gt config session-backend screen|process'",
+		}
+	}
+
+	var details []string
+	status := StatusOK
+	var problems []string
+	fixHint := ""
+
+	if ok, known := tmuxVersionAtLeast(version, minTmuxVersion); !known {
+		details = append(details, fmt.Sprintf("Could not parse tmux version %q", version))
+	} else if !ok {
+		status = StatusError
+		problems = append(problems, "tmux too old")
+		details = append(details, fmt.Sprintf("%s is older than the required tmux %d.%d", version, minTmuxVersion[0], minTmuxVersion[1]))
+		fixHint = fmt.Sprintf("Upgrade tmux to %d.%d or newer", minTmuxVersion[0], minTmuxVersion[1])
+	} else {
+		details = append(details, version)
+	}
+
+	socketDir := c.socketDir
+	if socketDir == "" {
+		socketDir = defaultTmuxSocketDir()
+	}
+	if info, err := os.Stat(socketDir); err == nil {
+		if !info.IsDir() {
+			status = StatusError
+			problems = append(problems, "socket path is not a directory")
+			details = append(details, fmt.Sprintf("%s is not a directory", socketDir))
+		} else if perm := info.Mode().Perm(); perm&0077 != 0 {
+			// tmux refuses to use a socket directory others can access.
+			status = StatusError
+			problems = append(problems, "unsafe socket permissions")
+			details = append(details, fmt.Sprintf("%s has mode %04o, tmux requires 0700", socketDir, perm))
+			c.badSocketDir = socketDir
+			fixHint = "Run 'gt doctor --fix' to restore socket directory permissions"
+		}
+	} else if !os.IsNotExist(err) {
+		status = StatusError
+		problems = append(problems, "socket directory unreadable")
+		details = append(details, err.Error())
+	}
+
+	lister := c.sessionLister
+	if lister == nil {
+		lister = &realSessionLister{t: tmux.NewTmux()}
+	}
+	sessions, err := lister.ListSessions()
+	if err != nil {
+		status = StatusError
+		problems = append(problems, "tmux server unreachable")
+		details = append(details, fmt.Sprintf("tmux server: %v", err))
+		if fixHint == "" {
+			fixHint = "Check $TMUX_TMPDIR and the socket directory, or kill the stale server with 'tmux kill-server'"
+		}
+	} else {
+		if len(sessions) == 0 {
+			details = append(details, "No tmux server running (started on first dispatch)")
+		} else {
+			details = append(details, fmt.Sprintf("tmux server reachable (%d session(s))", len(sessions)))
+		}
+
+		orphans := NewOrphanSessionCheckWithSessionLister(staticSessionLister(sessions))
+		orphans.Run(ctx)
+		if len(orphans.orphanSessions) > 0 {
+			c.orphans = orphans
+			if status == StatusOK {
+				status = StatusWarning
+			}
+			problems = append(problems, fmt.Sprintf("%d orphaned session(s)", len(orphans.orphanSessions)))
+			for _, sess := range orphans.orphanSessions {
+				details = append(details, fmt.Sprintf("Orphan: %s", sess))
+			}
+			if fixHint == "" {
+				fixHint = "Run 'gt doctor --fix' to prune orphaned sessions"
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "tmux session backend healthy",
+			Details: details,
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: "tmux session backend: " + strings.Join(problems, ", "),
+		Details: details,
+		FixHint: fixHint,
+	}
+}
+
+// Fix restores the socket directory's permissions and prunes orphaned
+// sessions. Crew sessions are left alone, as in orphan-sessions.
+func (c *SessionBackendCheck) Fix(ctx *CheckContext) error {
+	var lastErr error
+	if c.badSocketDir != "" {
+		if err := os.Chmod(c.badSocketDir, 0700); err != nil {
+			lastErr = fmt.Errorf("restoring %s permissions: %w", c.badSocketDir, err)
+		}
+	}
+	if c.orphans != nil {
+		// orphan-sessions may already have pruned some of these.
+		var remaining []string
+		t := tmux.NewTmux()
+		for _, sess := range c.orphans.orphanSessions {
+			if alive, err := t.HasSession(sess); err != nil || alive {
+				remaining = append(remaining, sess)
+			}
+		}
+		c.orphans.orphanSessions = remaining
+		if err := c.orphans.Fix(ctx); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// tmuxVersionAtLeast reports whether a 'tmux -V' string is at least min.
+// known is false when no version number can be found (e.g. "tmux master").
+func tmuxVersionAtLeast(version string, min [2]int) (ok, known bool) {
+	m := tmuxVersionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return false, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	if major != min[0] {
+		return major > min[0], true
+	}
+	return minor >= min[1], true
+}
+
+// defaultTmuxSocketDir returns the directory holding the default tmux
+// server's socket: $TMUX_TMPDIR (or /tmp) plus tmux-<uid>.
+func defaultTmuxSocketDir() string {
+	base := os.Getenv("TMUX_TMPDIR")
+	if base == "" {
+		base = "/tmp"
+	}
+	return filepath.Join(base, fmt.Sprintf("tmux-%d", os.Getuid()))
+}
+
+// staticSessionLister serves an already-fetched session list.
+type staticSessionLister []string
+
+func (s staticSessionLister) ListSessions() ([]string, error) {
+	return s, nil
+}
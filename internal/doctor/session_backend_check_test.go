@@ -0,0 +1,160 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newTestSessionBackendCheck returns a tmux-backed check that never runs tmux.
+func newTestSessionBackendCheck(t *testing.T, version string, lister SessionLister) *SessionBackendCheck {
+	t.Helper()
+	check := NewSessionBackendCheck()
+	check.backend = "tmux"
+	check.tmuxVersion = func() (string, error) { return version, nil }
+	check.sessionLister = lister
+	check.socketDir = filepath.Join(t.TempDir(), "tmux-1000")
+	return check
+}
+
+func TestSessionBackendCheck_SkipsNonTmuxBackend(t *testing.T) {
+	t.Setenv("GT_SESSION_BACKEND", "process")
+	check := NewSessionBackendCheck()
+	check.tmuxVersion = func() (string, error) {
+		t.Fatal("tmux should not be probed for a non-tmux backend")
+		return "", nil
+	}
+
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK || !strings.HasPrefix(result.Message, "Skipped") {
+		t.Fatalf("expected a skip, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestSessionBackendCheck_Healthy(t *testing.T) {
+	check := newTestSessionBackendCheck(t, "tmux 3.3a", &mockSessionLister{})
+	if err := os.Mkdir(check.socketDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Fatalf("expected StatusOK, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestSessionBackendCheck_NotInstalled(t *testing.T) {
+	check := newTestSessionBackendCheck(t, "", &mockSessionLister{})
+	check.tmuxVersion = func() (string, error) { return "", errors.New("executable file not found") }
+
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusError {
+		t.Fatalf("expected StatusError, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestSessionBackendCheck_OldTmux(t *testing.T) {
+	check := newTestSessionBackendCheck(t, "tmux 2.9a", &mockSessionLister{})
+
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusError || !strings.Contains(result.Message, "too old") {
+		t.Fatalf("expected too-old error, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestSessionBackendCheck_ServerUnreachable(t *testing.T) {
+	check := newTestSessionBackendCheck(t, "tmux 3.4", &mockSessionLister{err: errors.New("tmux list-sessions: lost server")})
+
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusError || !strings.Contains(result.Message, "unreachable") {
+		t.Fatalf("expected unreachable error, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestSessionBackendCheck_SocketPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix permissions")
+	}
+	check := newTestSessionBackendCheck(t, "tmux 3.4", &mockSessionLister{})
+	if err := os.Mkdir(check.socketDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(check.socketDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &CheckContext{TownRoot: t.TempDir()}
+	result := check.Run(ctx)
+	if result.Status != StatusError || !strings.Contains(result.Message, "socket") {
+		t.Fatalf("expected socket permission error, got %v: %s", result.Status, result.Message)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	info, err := os.Stat(check.socketDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("socket dir mode = %04o, want 0700", perm)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("expected StatusOK after fix, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestSessionBackendCheck_Orphans(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "polecats"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	check := newTestSessionBackendCheck(t, "tmux 3.4", &mockSessionLister{
+		sessions: []string{"gt-gastown-witness", "gt-unknown-toast", "dev"},
+	})
+
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning {
+		t.Fatalf("expected StatusWarning, got %v: %s", result.Status, result.Message)
+	}
+	if !strings.Contains(result.Message, "1 orphaned session(s)") {
+		t.Errorf("unexpected message: %q", result.Message)
+	}
+	if check.orphans == nil || len(check.orphans.orphanSessions) != 1 || check.orphans.orphanSessions[0] != "gt-unknown-toast" {
+		t.Errorf("cached orphans = %+v, want [gt-unknown-toast]", check.orphans)
+	}
+	if result.FixHint == "" {
+		t.Error("expected a FixHint for orphan sessions")
+	}
+}
+
+func TestTmuxVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version   string
+		ok, known bool
+	}{
+		{"tmux 3.3a", true, true},
+		{"tmux 3.2", true, true},
+		{"tmux 3.1c", false, true},
+		{"tmux 2.9", false, true},
+		{"tmux next-3.5", true, true},
+		{"tmux 10.0", true, true},
+		{"tmux master", false, false},
+	}
+	for _, tt := range tests {
+		ok, known := tmuxVersionAtLeast(tt.version, [2]int{3, 2})
+		if ok != tt.ok || known != tt.known {
+			t.Errorf("tmuxVersionAtLeast(%q) = %v, %v; want %v, %v", tt.version, ok, known, tt.ok, tt.known)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/optree"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -95,7 +97,12 @@ func (c *ThemeCheck) Run(ctx *CheckContext) *CheckResult {
 func (c *ThemeCheck) Fix(ctx *CheckContext) error {
 	cmd := exec.Command("gt", "theme", "apply", "--all")
 	cmd.Dir = ctx.TownRoot
-	return cmd.Run()
+	return cmdtrace.Run(cmd)
+}
+
+// ExplainFix lists the gt command Fix runs.
+func (c *ThemeCheck) ExplainFix(ctx *CheckContext) []optree.Command {
+	return []optree.Command{optree.In(ctx.TownRoot, "gt", "theme", "apply", "--all")}
 }
 
 // getSessionStatusLeft retrieves the status-left setting for a tmux session.
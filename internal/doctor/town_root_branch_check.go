@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/optree"
 )
 
 // TownRootBranchCheck verifies that the town root directory is on the main branch.
@@ -22,6 +24,7 @@ func NewTownRootBranchCheck() *TownRootBranchCheck {
 				CheckName:        "town-root-branch",
 				CheckDescription: "Verify town root is on main branch",
 				CheckCategory:    CategoryCore,
+				CheckDependsOn:   []string{"town-git"},
 			},
 		},
 	}
@@ -116,3 +119,14 @@ func (c *TownRootBranchCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// ExplainFix lists the git commands Fix runs.
+func (c *TownRootBranchCheck) ExplainFix(ctx *CheckContext) []optree.Command {
+	if c.currentBranch == "main" || c.currentBranch == "master" {
+		return nil
+	}
+	return []optree.Command{
+		optree.In(ctx.TownRoot, "git", "status", "--porcelain"),
+		optree.In(ctx.TownRoot, "git", "checkout", "main"),
+	}
+}
@@ -0,0 +1,155 @@
+package doctor
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/gitignore"
+)
+
+// trackedOutputDirs are the Gas Town directories that must never be
+// committed: a .gitignore entry doesn't untrack files already in the index.
+var trackedOutputDirs = []string{".reviews", constants.DirRuntime}
+
+// trackedOutputRepo is a git checkout with Gas Town output in its index.
+type trackedOutputRepo struct {
+	Dir   string         // Checkout directory
+	Roots map[string]int // Tracked output directory -> file count
+}
+
+// TrackedOutputsCheck finds review outputs and runtime state committed to
+// the town's or a rig's git index by mistake. Fix untracks them with
+// git rm --cached, leaving the files on disk.
+type TrackedOutputsCheck struct {
+	FixableCheck
+	repos []trackedOutputRepo // Cached during Run for use in Fix
+}
+
+// NewTrackedOutputsCheck creates a new tracked outputs check.
+func NewTrackedOutputsCheck() *TrackedOutputsCheck {
+	return &TrackedOutputsCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "tracked-outputs",
+				CheckDescription: "Check .reviews/ and .runtime/ are not tracked in git",
+				CheckCategory:    CategoryConfig,
+			},
+		},
+	}
+}
+
+// Run lists tracked output files in the town root, each rig's canonical
+// clone, and each crew clone.
+func (c *TrackedOutputsCheck) Run(ctx *CheckContext) *CheckResult {
+	c.repos = nil
+	var details []string
+	for _, target := range gitignore.Targets(ctx.TownRoot, findAllRigs(ctx.TownRoot)) {
+		files, err := trackedOutputFiles(target.Dir)
+		if err != nil || len(files) == 0 {
+			continue
+		}
+		repo := trackedOutputRepo{Dir: target.Dir, Roots: trackedOutputRoots(files)}
+		c.repos = append(c.repos, repo)
+
+		label := "town root"
+		if rel, err := filepath.Rel(ctx.TownRoot, target.Dir); err == nil && rel != "." {
+			label = rel
+		}
+		var counts []string
+		for _, root := range repo.sortedRoots() {
+			counts = append(counts, fmt.Sprintf("%s (%d file(s))", root, repo.Roots[root]))
+		}
+		details = append(details, label+": "+strings.Join(counts, ", "))
+		details = append(details, "  "+repo.untrackCommand())
+	}
+
+	if len(c.repos) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No review outputs or runtime state tracked in git",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d checkout(s) track review outputs or runtime state", len(c.repos)),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' (asks first) or the git rm --cached commands above, then commit the removal",
+	}
+}
+
+// DestructiveFix reports that fix changes git indexes.
+func (c *TrackedOutputsCheck) DestructiveFix() bool { return true }
+
+// Fix removes the tracked output directories from each index. The files
+// stay on disk; the removal is staged for the user to commit.
+func (c *TrackedOutputsCheck) Fix(ctx *CheckContext) error {
+	for _, repo := range c.repos {
+		args := append([]string{"-C", repo.Dir, "rm", "-r", "--cached", "--quiet", "--"}, repo.sortedRoots()...)
+		cmd := exec.Command("git", args...) //nolint:gosec // G204: paths come from git ls-files
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("untracking outputs in %s: %s", repo.Dir, strings.TrimSpace(stderr.String()))
+		}
+	}
+	return nil
+}
+
+// sortedRoots returns the repo's tracked output directories in order.
+func (r trackedOutputRepo) sortedRoots() []string {
+	roots := make([]string, 0, len(r.Roots))
+	for root := range r.Roots {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// untrackCommand is the git command that untracks the repo's outputs.
+func (r trackedOutputRepo) untrackCommand() string {
+	return fmt.Sprintf("git -C %s rm -r --cached -- %s", r.Dir, strings.Join(r.sortedRoots(), " "))
+}
+
+// trackedOutputFiles lists files under a .reviews/ or .runtime/ directory,
+// at any depth, in dir's git index. A dir that isn't a git checkout has none.
+func trackedOutputFiles(dir string) ([]string, error) {
+	args := []string{"-C", dir, "ls-files", "-z", "--"}
+	for _, name := range trackedOutputDirs {
+		args = append(args, ":(glob)**/"+name+"/**")
+	}
+	out, err := exec.Command("git", args...).Output() //nolint:gosec // G204: fixed arguments
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// trackedOutputRoots groups tracked files by the output directory that
+// contains them, e.g. "docs/.reviews/" for "docs/.reviews/ab12/x.md".
+func trackedOutputRoots(files []string) map[string]int {
+	roots := make(map[string]int)
+	for _, f := range files {
+		parts := strings.Split(f, "/")
+		for i, part := range parts[:len(parts)-1] {
+			if slices.Contains(trackedOutputDirs, part) {
+				roots[strings.Join(parts[:i+1], "/")+"/"]++
+				break
+			}
+		}
+	}
+	return roots
+}
@@ -0,0 +1,81 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTrackedOutputRoots(t *testing.T) {
+	roots := trackedOutputRoots([]string{
+		".reviews/ab12/security-findings.md",
+		".reviews/ab12/synthesis.md",
+		"docs/.reviews/cd34/x.md",
+		".runtime/locks/x.lock",
+		".reviews", // A file named .reviews isn't a directory
+	})
+	want := map[string]int{".reviews/": 2, "docs/.reviews/": 1, ".runtime/": 1}
+	if len(roots) != len(want) {
+		t.Fatalf("roots = %v, want %v", roots, want)
+	}
+	for root, n := range want {
+		if roots[root] != n {
+			t.Errorf("roots[%q] = %d, want %d", root, roots[root], n)
+		}
+	}
+}
+
+func TestTrackedOutputsCheck(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	town := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", town}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+	git("init", "-q")
+	for _, name := range []string{"README.md", ".reviews/ab12/synthesis.md", ".runtime/state.json"} {
+		path := filepath.Join(town, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("add", ".")
+	git("commit", "-qm", "init")
+
+	check := NewTrackedOutputsCheck()
+	ctx := &CheckContext{TownRoot: town}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("Status = %v, want warning: %s", result.Status, result.Message)
+	}
+	if details := strings.Join(result.Details, "\n"); !strings.Contains(details, ".reviews/ (1 file(s))") ||
+		!strings.Contains(details, "rm -r --cached -- .reviews/ .runtime/") {
+		t.Errorf("Details = %q", details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after Fix, Status = %v: %v", result.Status, result.Details)
+	}
+	if _, err := os.Stat(filepath.Join(town, ".reviews", "ab12", "synthesis.md")); err != nil {
+		t.Errorf("Fix removed the file from disk: %v", err)
+	}
+	if tracked := git("ls-files"); tracked != "README.md\n" {
+		t.Errorf("tracked after Fix = %q", tracked)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/i18n"
 	"github.com/steveyegge/gastown/internal/ui"
 )
 
@@ -64,6 +65,10 @@ type CheckContext struct {
 	RigName         string // Rig name (empty for town-level checks)
 	Verbose         bool   // Enable verbose output
 	RestartSessions bool   // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
+
+	// ConfirmFix gates fixes of checks that implement DestructiveFixer.
+	// It returns false to skip the fix. Nil allows every fix.
+	ConfirmFix func(checkName string) bool
 }
 
 // RigPath returns the full path to the rig directory.
@@ -89,6 +94,12 @@ type CheckResult struct {
 	Elapsed  time.Duration // How long the check took to run
 }
 
+// DestructiveFixer is implemented by checks whose Fix deletes directories
+// or data. Their fixes run only if CheckContext.ConfirmFix allows them.
+type DestructiveFixer interface {
+	DestructiveFix() bool
+}
+
 // Check defines the interface for a health check.
 type Check interface {
 	// Name returns the check identifier.
@@ -230,7 +241,7 @@ func (r *Report) Print(w io.Writer, verbose bool, slowThreshold time.Duration) {
 		}
 
 		// Print category header
-		_, _ = fmt.Fprintln(w, ui.RenderCategory(category))
+		_, _ = fmt.Fprintln(w, ui.RenderCategory(i18n.Sprintf(category)))
 
 		// Print each check in this category
 		for _, check := range checks {
@@ -244,7 +255,7 @@ func (r *Report) Print(w io.Writer, verbose bool, slowThreshold time.Duration) {
 
 	// Print any checks without a category
 	if otherChecks, exists := checksByCategory["Other"]; exists && len(otherChecks) > 0 {
-		_, _ = fmt.Fprintln(w, ui.RenderCategory("Other"))
+		_, _ = fmt.Fprintln(w, ui.RenderCategory(i18n.Sprintf("Other")))
 		for _, check := range otherChecks {
 			r.printCheck(w, check, verbose, slowThreshold)
 			if check.Status != StatusOK {
@@ -327,13 +338,13 @@ func formatDuration(d time.Duration) string {
 
 // printSummary outputs the summary line with semantic icons.
 func (r *Report) printSummary(w io.Writer, slowThreshold time.Duration) {
-	summary := fmt.Sprintf("%s %d passed  %s %d warnings  %s %d failed",
+	summary := i18n.Sprintf("%s %d passed  %s %d warnings  %s %d failed",
 		ui.RenderPassIcon(), r.Summary.OK,
 		ui.RenderWarnIcon(), r.Summary.Warnings,
 		ui.RenderFailIcon(), r.Summary.Errors,
 	)
 	if slowThreshold > 0 && r.Summary.Slow > 0 {
-		summary += fmt.Sprintf("  ⏳ %d slow (slowest: %s %s)",
+		summary += i18n.Sprintf("  ⏳ %d slow (slowest: %s %s)",
 			r.Summary.Slow,
 			r.Summary.SlowestName,
 			formatDuration(r.Summary.SlowestTime),
@@ -346,12 +357,12 @@ func (r *Report) printSummary(w io.Writer, slowThreshold time.Duration) {
 func (r *Report) printWarningsSection(w io.Writer, warnings []*CheckResult) {
 	if len(warnings) == 0 {
 		_, _ = fmt.Fprintln(w)
-		_, _ = fmt.Fprintln(w, ui.RenderPass(ui.IconPass+" All checks passed"))
+		_, _ = fmt.Fprintln(w, ui.RenderPass(ui.IconPass+" "+i18n.Sprintf("All checks passed")))
 		return
 	}
 
 	_, _ = fmt.Fprintln(w)
-	_, _ = fmt.Fprintln(w, ui.RenderWarn(ui.IconWarn+"  WARNINGS"))
+	_, _ = fmt.Fprintln(w, ui.RenderWarn(ui.IconWarn+"  "+i18n.Sprintf("WARNINGS")))
 
 	// Sort by severity: errors first, then warnings
 	slices.SortStableFunc(warnings, func(a, b *CheckResult) int {
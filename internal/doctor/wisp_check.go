@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // WispGCCheck detects and cleans orphaned wisps that are older than a threshold.
@@ -138,7 +139,7 @@ func (c *WispGCCheck) Fix(ctx *CheckContext) error {
 		// Run bd --no-daemon mol wisp gc
 		cmd := exec.Command("bd", "--no-daemon", "mol", "wisp", "gc")
 		cmd.Dir = rigPath
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 			lastErr = fmt.Errorf("%s: %v (%s)", rigName, err, string(output))
 		}
 	}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // TownConfigExistsCheck verifies mayor/town.json exists.
@@ -55,6 +58,7 @@ func NewTownConfigValidCheck() *TownConfigValidCheck {
 			CheckName:        "town-config-valid",
 			CheckDescription: "Check that mayor/town.json is valid with required fields",
 			CheckCategory:    CategoryCore,
+			CheckDependsOn:   []string{"town-config-exists"},
 		},
 	}
 }
@@ -120,6 +124,70 @@ func (c *TownConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// TownMarkerCheck verifies the town root carries the explicit town marker
+// that workspace detection prefers over the mayor/ heuristics. Towns
+// created before gt install wrote it are migrated by --fix.
+type TownMarkerCheck struct {
+	FixableCheck
+}
+
+// NewTownMarkerCheck creates a new town marker check.
+func NewTownMarkerCheck() *TownMarkerCheck {
+	return &TownMarkerCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "town-marker",
+				CheckDescription: "Check that the town root has " + workspace.TownMarker,
+				CheckCategory:    CategoryCore,
+				CheckDependsOn:   []string{"town-config-exists"},
+			},
+		},
+	}
+}
+
+// Run checks the town marker exists and is readable by this gt.
+func (c *TownMarkerCheck) Run(ctx *CheckContext) *CheckResult {
+	if !workspace.HasMarker(ctx.TownRoot) {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: workspace.TownMarker + " not found; town detection relies on the mayor/ heuristics",
+			FixHint: "Run 'gt doctor --fix' to create it",
+		}
+	}
+	m, err := workspace.ReadMarker(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: workspace.TownMarker + " is not usable",
+			Details: []string{err.Error()},
+			FixHint: "Fix or remove " + workspace.TownMarker + ", then run 'gt doctor --fix'",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%s present (schema %d)", workspace.TownMarker, m.Schema),
+	}
+}
+
+// Fix writes the marker, named after mayor/town.json. An existing marker
+// is left alone, since it may come from a newer gt.
+func (c *TownMarkerCheck) Fix(ctx *CheckContext) error {
+	if workspace.HasMarker(ctx.TownRoot) {
+		return fmt.Errorf("%s exists; fix it by hand", workspace.TownMarker)
+	}
+	var name string
+	if data, err := os.ReadFile(filepath.Join(ctx.TownRoot, "mayor", "town.json")); err == nil {
+		var cfg townConfig
+		if json.Unmarshal(data, &cfg) == nil {
+			name = cfg.Name
+		}
+	}
+	return workspace.WriteMarker(ctx.TownRoot, name)
+}
+
 // RigsRegistryExistsCheck verifies mayor/rigs.json exists.
 type RigsRegistryExistsCheck struct {
 	FixableCheck
@@ -175,7 +243,7 @@ func (c *RigsRegistryExistsCheck) Fix(ctx *CheckContext) error {
 		return fmt.Errorf("marshaling empty rigs.json: %w", err)
 	}
 
-	return os.WriteFile(rigsPath, data, 0644)
+	return fsx.WriteFile(rigsPath, data, 0644)
 }
 
 // RigsRegistryValidCheck verifies mayor/rigs.json is valid and rigs exist.
@@ -192,6 +260,7 @@ func NewRigsRegistryValidCheck() *RigsRegistryValidCheck {
 				CheckName:        "rigs-registry-valid",
 				CheckDescription: "Check that registered rigs exist on disk",
 				CheckCategory:    CategoryCore,
+				CheckDependsOn:   []string{"rigs-registry-exists"},
 			},
 		},
 	}
@@ -310,7 +379,7 @@ func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
 		return fmt.Errorf("marshaling rigs.json: %w", err)
 	}
 
-	return os.WriteFile(rigsPath, newData, 0644)
+	return fsx.WriteFile(rigsPath, newData, 0644)
 }
 
 // MayorExistsCheck verifies the mayor/ directory structure.
@@ -383,6 +452,7 @@ func WorkspaceChecks() []Check {
 	return []Check{
 		NewTownConfigExistsCheck(),
 		NewTownConfigValidCheck(),
+		NewTownMarkerCheck(),
 		NewRigsRegistryExistsCheck(),
 		NewRigsRegistryValidCheck(),
 		NewMayorExistsCheck(),
@@ -0,0 +1,45 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+func TestTownMarkerCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","version":2,"name":"gt"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := &CheckContext{TownRoot: townRoot}
+	check := NewTownMarkerCheck()
+
+	if result := check.Run(ctx); result.Status != StatusWarning {
+		t.Fatalf("Run() without marker = %v (%s), want warning", result.Status, result.Message)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix() = %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("Run() after fix = %v (%s), want OK", result.Status, result.Message)
+	}
+	m, err := workspace.ReadMarker(townRoot)
+	if err != nil || m.Name != "gt" {
+		t.Errorf("marker = %+v, %v; want it named after town.json", m, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(townRoot, workspace.TownMarker), []byte(`{"schema":99}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(ctx); result.Status != StatusError {
+		t.Errorf("Run() with a newer marker = %v, want error", result.Status)
+	}
+	if err := check.Fix(ctx); err == nil {
+		t.Error("Fix() overwrote an existing marker")
+	}
+}
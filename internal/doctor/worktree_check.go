@@ -87,6 +87,9 @@ func (c *BeadsSyncWorktreeCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// DestructiveFix reports that fix deletes worktree directories.
+func (c *BeadsSyncWorktreeCheck) DestructiveFix() bool { return true }
+
 // Fix removes orphaned beads-sync worktrees.
 func (c *BeadsSyncWorktreeCheck) Fix(ctx *CheckContext) error {
 	for _, worktreePath := range c.orphanedWorktrees {
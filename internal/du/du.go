@@ -0,0 +1,144 @@
+// Package du measures the disk space a town and its rigs use, by
+// subsystem (see config.DiskUsageCategories).
+package du
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// TownScope is the Scope of the town's own usage (outside its rigs).
+const TownScope = "town"
+
+// Usage is the space one scope (the town or a rig) uses per category.
+type Usage struct {
+	Scope string           `json:"scope"`
+	Bytes map[string]int64 `json:"bytes"`
+}
+
+// Total returns the scope's usage over all categories.
+func (u Usage) Total() int64 {
+	var n int64
+	for _, b := range u.Bytes {
+		n += b
+	}
+	return n
+}
+
+// Measure returns the usage of the town (excluding its rigs) followed by
+// the given rigs, in name order.
+func Measure(townRoot string, rigs []string) []Usage {
+	rigs = append([]string(nil), rigs...)
+	sort.Strings(rigs)
+	skip := make(map[string]bool, len(rigs))
+	for _, rig := range rigs {
+		skip[rig] = true
+	}
+
+	usages := []Usage{measure(TownScope, townRoot, skip)}
+	for _, rig := range rigs {
+		usages = append(usages, MeasureRig(townRoot, rig))
+	}
+	return usages
+}
+
+// MeasureRig returns the usage of one rig.
+func MeasureRig(townRoot, rig string) Usage {
+	return measure(rig, filepath.Join(townRoot, rig), nil)
+}
+
+// measure sums the files under root by category, skipping the top-level
+// directories in skip.
+func measure(scope, root string, skip map[string]bool) Usage {
+	u := Usage{Scope: scope, Bytes: make(map[string]int64)}
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() {
+			if skip[rel] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			u.Bytes[Classify(filepath.ToSlash(rel))] += info.Size()
+		}
+		return nil
+	})
+	return u
+}
+
+// Classify returns the category of a file, given its slash-separated path
+// relative to the town or rig root.
+func Classify(rel string) string {
+	parts := strings.Split(rel, "/")
+	for i, p := range parts {
+		switch p {
+		case ".reviews":
+			return config.DiskReviews
+		case ".beads":
+			if i+1 < len(parts)-1 && parts[i+1] == "formulas" {
+				return config.DiskFormulas
+			}
+			return config.DiskBeads
+		}
+	}
+	switch parts[0] {
+	case ".repo.git":
+		return config.DiskRepo
+	case "crew":
+		return config.DiskCrew
+	case "polecats":
+		return config.DiskPolecats
+	case ".runtime":
+		if len(parts) > 1 && parts[1] == "worktree-pool" {
+			return config.DiskPolecats
+		}
+		return config.DiskRuntime
+	case "logs":
+		return config.DiskRuntime
+	}
+	return config.DiskOther
+}
+
+// Excess is a category over its limit in one scope.
+type Excess struct {
+	Scope    string `json:"scope"`
+	Category string `json:"category"`
+	Bytes    int64  `json:"bytes"`
+	Limit    int64  `json:"limit"`
+}
+
+// Exceeded returns the categories over their limit in cfg.
+func Exceeded(usages []Usage, cfg *config.DiskUsageConfig) []Excess {
+	var over []Excess
+	for _, u := range usages {
+		for _, category := range config.DiskUsageCategories {
+			if limit := cfg.Limit(category); limit > 0 && u.Bytes[category] > limit {
+				over = append(over, Excess{Scope: u.Scope, Category: category, Bytes: u.Bytes[category], Limit: limit})
+			}
+		}
+	}
+	return over
+}
+
+// FormatBytes formats b in human-readable binary units.
+func FormatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,72 @@
+package du
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestClassify(t *testing.T) {
+	for rel, want := range map[string]string{
+		".beads/issues.jsonl":                     config.DiskBeads,
+		".beads/formulas/code-review.toml":        config.DiskFormulas,
+		"mayor/rig/.beads/beads.db":               config.DiskBeads,
+		".repo.git/objects/pack/p.pack":           config.DiskRepo,
+		"crew/max/main.go":                        config.DiskCrew,
+		"crew/max/.reviews/r-1/synthesis.md":      config.DiskReviews,
+		"polecats/toast/gastown/main.go":          config.DiskPolecats,
+		".runtime/worktree-pool/wt-1/main.go":     config.DiskPolecats,
+		".runtime/cache/formulas.json":            config.DiskRuntime,
+		"logs/town.log":                           config.DiskRuntime,
+		"mayor/rig/main.go":                       config.DiskOther,
+		"refinery/rig/.beads/formulas/local.toml": config.DiskFormulas,
+	} {
+		if got := Classify(rel); got != want {
+			t.Errorf("Classify(%q) = %q, want %q", rel, got, want)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	town := t.TempDir()
+	write := func(rel string, size int) {
+		t.Helper()
+		path := filepath.Join(town, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".beads/issues.jsonl", 100)
+	write("logs/town.log", 50)
+	write("gastown/.repo.git/objects/p.pack", 4000)
+	write("gastown/crew/max/main.go", 300)
+	write("gastown/.beads/issues.jsonl", 20)
+
+	usages := Measure(town, []string{"gastown"})
+	if len(usages) != 2 || usages[0].Scope != TownScope || usages[1].Scope != "gastown" {
+		t.Fatalf("Measure scopes = %+v, want town then gastown", usages)
+	}
+	townUsage, rigUsage := usages[0], usages[1]
+	if townUsage.Bytes[config.DiskBeads] != 100 || townUsage.Bytes[config.DiskRuntime] != 50 || townUsage.Total() != 150 {
+		t.Errorf("town usage = %v, want beads 100 and runtime 50 only", townUsage.Bytes)
+	}
+	if rigUsage.Bytes[config.DiskRepo] != 4000 || rigUsage.Bytes[config.DiskCrew] != 300 || rigUsage.Bytes[config.DiskBeads] != 20 {
+		t.Errorf("rig usage = %v", rigUsage.Bytes)
+	}
+
+	limits := &config.DiskUsageConfig{LimitsMB: map[string]int{config.DiskRepo: 1}}
+	if over := Exceeded(usages, limits); len(over) != 0 {
+		t.Errorf("Exceeded under a 1 MB limit = %+v, want none", over)
+	}
+	rigUsage.Bytes[config.DiskRepo] = 2 << 20
+	over := Exceeded(usages, limits)
+	if len(over) != 1 || over[0].Scope != "gastown" || over[0].Category != config.DiskRepo || over[0].Limit != 1<<20 {
+		t.Errorf("Exceeded = %+v, want gastown repo over 1 MB", over)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/readonly"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -103,6 +104,11 @@ func LogAudit(eventType, actor string, payload map[string]interface{}) error {
 
 // write appends an event to the events file.
 func write(event Event) error {
+	// Read-only mode records nothing
+	if readonly.Enabled() {
+		return nil
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil || townRoot == "" {
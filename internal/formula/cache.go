@@ -0,0 +1,144 @@
+package formula
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/readonly"
+)
+
+// cacheVersion is bumped whenever Metadata changes shape, discarding caches
+// written by older binaries.
+const cacheVersion = 3
+
+// CachePath returns the town's formula metadata cache file.
+func CachePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "cache", "formulas.json")
+}
+
+// cacheEntry is what the cache knows about one formula file. An entry is
+// trusted without reading the file while its mtime and size are unchanged.
+type cacheEntry struct {
+	ModTime  time.Time `json:"mtime"`
+	Size     int64     `json:"size"`
+	Hash     string    `json:"hash"`
+	Metadata *Metadata `json:"metadata,omitempty"` // nil if the file did not parse
+}
+
+type cacheFile struct {
+	Version int                   `json:"version"`
+	Entries map[string]cacheEntry `json:"entries"` // absolute path -> entry
+}
+
+// MetadataCache memoizes formula content hashes and catalog metadata by
+// file, so listing and resolving formulas does not re-read and re-parse
+// every file on every run. It is safe for concurrent use.
+type MetadataCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]cacheEntry
+	dirty   bool
+}
+
+// LoadMetadataCache opens the cache at path. A missing, unreadable, or
+// outdated cache starts empty. An empty path gives an in-memory cache that
+// is never saved.
+func LoadMetadataCache(path string) *MetadataCache {
+	c := &MetadataCache{path: path, entries: make(map[string]cacheEntry)}
+	if path == "" {
+		return c
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the town's cache file
+	if err != nil {
+		return c
+	}
+	var f cacheFile
+	if err := json.Unmarshal(data, &f); err != nil || f.Version != cacheVersion || f.Entries == nil {
+		return c
+	}
+	c.entries = f.Entries
+	return c
+}
+
+// Lookup returns the content hash and metadata of the formula file at path.
+// Metadata is nil if the file is not parseable TOML. The file is re-read
+// only when its mtime or size changed, and re-parsed only when its content
+// hash changed.
+func (c *MetadataCache) Lookup(path string) (string, *Metadata, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && entry.ModTime.Equal(info.ModTime()) && entry.Size == info.Size() {
+		return entry.Hash, copyMetadata(entry.Metadata), nil
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a formula file
+	if err != nil {
+		return "", nil, err
+	}
+	hash := computeHash(data)
+	if !ok || entry.Hash != hash {
+		entry = cacheEntry{Hash: hash}
+		if m, err := ParseMetadata(data); err == nil {
+			entry.Metadata = m
+		}
+	}
+	entry.ModTime = info.ModTime()
+	entry.Size = info.Size()
+
+	c.mu.Lock()
+	c.entries[path] = entry
+	c.dirty = true
+	c.mu.Unlock()
+	return hash, copyMetadata(entry.Metadata), nil
+}
+
+// Save writes the cache if anything changed, dropping entries for files
+// that no longer exist. In read-only mode it writes nothing.
+func (c *MetadataCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.path == "" || !c.dirty || readonly.Enabled() {
+		return nil
+	}
+	for path := range c.entries {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(c.entries, path)
+		}
+	}
+
+	data, err := json.MarshalIndent(cacheFile{Version: cacheVersion, Entries: c.entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	if err := fsx.WriteFile(c.path, data, 0644); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// copyMetadata returns a copy callers may modify without touching the cache.
+func copyMetadata(m *Metadata) *Metadata {
+	if m == nil {
+		return nil
+	}
+	cp := *m
+	cp.Tags = append([]string(nil), m.Tags...)
+	return &cp
+}
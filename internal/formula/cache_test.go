@@ -0,0 +1,90 @@
+package formula
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMetadataCache(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "review.formula.toml")
+	cachePath := CachePath(dir)
+	if err := os.WriteFile(file, []byte("formula = \"review\"\n[[legs]]\nid = \"a\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := LoadMetadataCache(cachePath)
+	hash, m, err := c.Lookup(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || m.Type != TypeConvoy || m.Legs != 1 || hash == "" {
+		t.Fatalf("Lookup = %q, %+v", hash, m)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A reloaded cache trusts the entry while mtime and size are unchanged,
+	// even though the content differs.
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(file, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	c = LoadMetadataCache(cachePath)
+	if _, _, err := c.Lookup(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("formula = \"review\"\n[[legs]]\nid = \"b\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	c = LoadMetadataCache(cachePath)
+	if h, _, _ := c.Lookup(file); h != hash {
+		t.Errorf("unchanged mtime/size: hash = %q, want cached %q", h, hash)
+	}
+
+	// Touching the file invalidates the entry.
+	if err := os.Chtimes(file, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if h, _, _ := c.Lookup(file); h == hash {
+		t.Error("modified file: got stale cached hash")
+	}
+}
+
+func TestMetadataCache_PrunesAndIgnoresCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := CachePath(dir)
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachePath, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "gone.formula.toml")
+	if err := os.WriteFile(file, []byte("not = [valid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := LoadMetadataCache(cachePath)
+	if _, m, err := c.Lookup(file); err != nil || m != nil {
+		t.Fatalf("unparseable file: metadata = %+v, err = %v; want nil, nil", m, err)
+	}
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(LoadMetadataCache(cachePath).entries); n != 0 {
+		t.Errorf("saved %d entries for deleted files, want 0", n)
+	}
+}
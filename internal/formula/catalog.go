@@ -0,0 +1,150 @@
+package formula
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// maxSummaryLen bounds the one-line description shown in catalogs.
+const maxSummaryLen = 72
+
+// Metadata is the catalog view of a formula: enough to list and filter it
+// without fully parsing or validating it.
+type Metadata struct {
+	Name      string      `json:"name"`
+	Type      FormulaType `json:"type,omitempty"`
+	Category  string      `json:"category"`
+	Summary   string      `json:"summary,omitempty"`
+	Tags      []string    `json:"tags,omitempty"`
+	Legs      int         `json:"legs,omitempty"`
+	Steps     int         `json:"steps,omitempty"`
+	Templates int         `json:"templates,omitempty"`
+	Aspects   int         `json:"aspects,omitempty"`
+	Schema    int         `json:"schema"` // Declared file format version (1 if absent)
+	Owner     string      `json:"owner,omitempty"`
+	Reason    string      `json:"reason,omitempty"`
+	Source    string      `json:"source"`         // embedded, project, rig, town, user
+	Path      string      `json:"path,omitempty"` // empty for embedded formulas
+
+	// Disabled is set by gt formula list when the formula is disabled in
+	// the town or the current rig; DisabledReason says why.
+	Disabled       bool   `json:"disabled,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+}
+
+// metadataFields decodes only the keys the catalog needs, so formulas that
+// fail strict parsing (e.g. non-string vars, unresolved extends) still list.
+type metadataFields struct {
+	Name        string      `toml:"formula"`
+	Description string      `toml:"description"`
+	Type        FormulaType `toml:"type"`
+	Category    string      `toml:"category"`
+	Tags        []string    `toml:"tags"`
+	Legs        []struct{}  `toml:"legs"`
+	Steps       []struct{}  `toml:"steps"`
+	Template    []struct{}  `toml:"template"`
+	Aspects     []struct{}  `toml:"aspects"`
+	Schema      int         `toml:"schema"`
+}
+
+// ParseMetadata extracts catalog metadata from formula TOML. The type is
+// inferred from content when not declared, and the category defaults to the
+// type.
+func ParseMetadata(data []byte) (*Metadata, error) {
+	var raw metadataFields
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, err
+	}
+	f := Formula{
+		Type:     raw.Type,
+		Legs:     make([]Leg, len(raw.Legs)),
+		Steps:    make([]Step, len(raw.Steps)),
+		Template: make([]Template, len(raw.Template)),
+		Aspects:  make([]Aspect, len(raw.Aspects)),
+	}
+	f.inferType()
+
+	m := &Metadata{
+		Name:      raw.Name,
+		Type:      f.Type,
+		Category:  raw.Category,
+		Summary:   summarize(raw.Description),
+		Tags:      raw.Tags,
+		Legs:      len(raw.Legs),
+		Steps:     len(raw.Steps),
+		Templates: len(raw.Template),
+		Aspects:   len(raw.Aspects),
+		Schema:    raw.Schema,
+	}
+	if m.Schema == 0 {
+		m.Schema = 1
+	}
+	o := ParseOverride(data)
+	m.Owner, m.Reason = o.Owner, o.Reason
+	if m.Category == "" {
+		m.Category = string(m.Type)
+	}
+	if m.Category == "" {
+		m.Category = "other"
+	}
+	return m, nil
+}
+
+// summarize returns the first non-empty line of a description, truncated
+// to maxSummaryLen.
+func summarize(desc string) string {
+	for _, line := range strings.Split(desc, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if r := []rune(line); len(r) > maxSummaryLen {
+			line = strings.TrimSpace(string(r[:maxSummaryLen-3])) + "..."
+		}
+		return line
+	}
+	return ""
+}
+
+var (
+	embeddedCatalogOnce sync.Once
+	embeddedCatalog     []Metadata
+)
+
+// EmbeddedCatalog returns metadata for every formula shipped with gt, sorted
+// by name. Embedded content is fixed at build time, so it is parsed once per
+// process. Formulas that fail to parse are listed by name only.
+func EmbeddedCatalog() []Metadata {
+	embeddedCatalogOnce.Do(func() {
+		entries, err := formulasFS.ReadDir("formulas")
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			name := strings.TrimSuffix(entry.Name(), ".formula.toml")
+			if entry.IsDir() || name == entry.Name() {
+				continue
+			}
+			m := &Metadata{Name: name, Category: "other"}
+			if content, err := formulasFS.ReadFile("formulas/" + entry.Name()); err == nil {
+				if parsed, err := ParseMetadata(content); err == nil {
+					m = parsed
+				}
+			}
+			if m.Name == "" {
+				m.Name = name
+			}
+			m.Source = "embedded"
+			embeddedCatalog = append(embeddedCatalog, *m)
+		}
+		sort.Slice(embeddedCatalog, func(i, j int) bool {
+			return embeddedCatalog[i].Name < embeddedCatalog[j].Name
+		})
+	})
+	out := make([]Metadata, len(embeddedCatalog))
+	copy(out, embeddedCatalog)
+	return out
+}
@@ -0,0 +1,80 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMetadata(t *testing.T) {
+	data := []byte(`
+formula = "review"
+category = "quality"
+tags = ["pr", "review"]
+description = """
+
+Parallel review of a pull request.
+Each leg covers one concern.
+"""
+
+[vars.depth]
+default = 3
+
+[[legs]]
+id = "a"
+
+[[legs]]
+id = "b"
+`)
+	m, err := ParseMetadata(data)
+	if err != nil {
+		t.Fatalf("ParseMetadata: %v", err)
+	}
+	if m.Name != "review" || m.Type != TypeConvoy || m.Category != "quality" {
+		t.Errorf("got name=%q type=%q category=%q", m.Name, m.Type, m.Category)
+	}
+	if m.Summary != "Parallel review of a pull request." {
+		t.Errorf("Summary = %q", m.Summary)
+	}
+	if m.Legs != 2 || len(m.Tags) != 2 {
+		t.Errorf("Legs = %d, Tags = %v", m.Legs, m.Tags)
+	}
+}
+
+func TestParseMetadata_CategoryDefaultsToType(t *testing.T) {
+	m, err := ParseMetadata([]byte("formula = \"w\"\n[[steps]]\nid = \"s\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != TypeWorkflow || m.Category != "workflow" || m.Steps != 1 {
+		t.Errorf("got %+v", m)
+	}
+}
+
+func TestSummarize_Truncates(t *testing.T) {
+	got := summarize(strings.Repeat("word ", 40))
+	if len(got) > maxSummaryLen || !strings.HasSuffix(got, "...") {
+		t.Errorf("summarize = %q (%d chars)", got, len(got))
+	}
+}
+
+func TestEmbeddedCatalog(t *testing.T) {
+	catalog := EmbeddedCatalog()
+	if len(catalog) == 0 {
+		t.Fatal("EmbeddedCatalog returned no formulas")
+	}
+	var found bool
+	for _, m := range catalog {
+		if m.Source != "embedded" {
+			t.Errorf("%s: Source = %q", m.Name, m.Source)
+		}
+		if m.Name == "code-review" {
+			found = true
+			if m.Type != TypeConvoy || m.Legs == 0 || m.Summary == "" {
+				t.Errorf("code-review metadata = %+v", m)
+			}
+		}
+	}
+	if !found {
+		t.Error("code-review not in embedded catalog")
+	}
+}
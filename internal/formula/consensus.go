@@ -0,0 +1,134 @@
+package formula
+
+import "fmt"
+
+// ConsensusStrategy decides how many of a consensus leg's agents must
+// report a finding for it to count.
+type ConsensusStrategy string
+
+// Consensus strategies.
+const (
+	ConsensusIntersect ConsensusStrategy = "intersect" // Every agent (the default)
+	ConsensusMajority  ConsensusStrategy = "majority"  // More than half of the agents
+	ConsensusUnion     ConsensusStrategy = "union"     // Any agent; agreement is still noted
+)
+
+// Consensus sends a leg's prompt to several agents and merges their
+// findings by agreement. Declared in a formula as:
+//
+//	[[legs]]
+//	id = "security"
+//	consensus = { agents = ["claude", "gemini"], strategy = "intersect" }
+//
+// Findings that fall short of the strategy's quorum are kept in the leg's
+// output as disputed, but don't count toward gates such as --fail-on.
+type Consensus struct {
+	Agents   []string          `toml:"agents" json:"agents"`
+	Strategy ConsensusStrategy `toml:"strategy" json:"strategy,omitempty"`
+}
+
+// Validate checks the agents and strategy.
+func (c *Consensus) Validate() error {
+	if len(c.Agents) < 2 {
+		return fmt.Errorf(`consensus needs at least two agents: consensus = { agents = ["claude", "gemini"] }`)
+	}
+	seen := make(map[string]bool, len(c.Agents))
+	for _, a := range c.Agents {
+		if a == "" {
+			return fmt.Errorf("consensus agent names must not be empty")
+		}
+		if seen[a] {
+			return fmt.Errorf("consensus agent %s is listed twice", a)
+		}
+		seen[a] = true
+	}
+	switch c.Strategy {
+	case "", ConsensusIntersect, ConsensusMajority, ConsensusUnion:
+		return nil
+	}
+	return fmt.Errorf("invalid consensus strategy %q (must be intersect, majority, or union)", c.Strategy)
+}
+
+// StrategyOrDefault returns the strategy, or intersect if unset.
+func (c *Consensus) StrategyOrDefault() ConsensusStrategy {
+	if c.Strategy == "" {
+		return ConsensusIntersect
+	}
+	return c.Strategy
+}
+
+// Quorum returns how many of n replying agents must report a finding.
+func (c *Consensus) Quorum(n int) int {
+	switch c.StrategyOrDefault() {
+	case ConsensusUnion:
+		return 1
+	case ConsensusMajority:
+		return n/2 + 1
+	}
+	return n
+}
+
+// ConsensusLegID is the ID of the per-agent leg a consensus leg runs as
+// when dispatched to polecats, and names that agent's output file.
+func ConsensusLegID(legID, agent string) string {
+	return legID + "@" + agent
+}
+
+// ExpandConsensus returns the formula as dispatched to polecats: each
+// consensus leg becomes one leg per agent (see ConsensusLegID), and needs
+// on a consensus leg become needs on all of its agents' legs. Formulas
+// without consensus legs are returned as is.
+func (f *Formula) ExpandConsensus() *Formula {
+	subLegs := make(map[string][]string)
+	var legs []Leg
+	for _, leg := range f.Legs {
+		if leg.Consensus == nil {
+			legs = append(legs, leg)
+			continue
+		}
+		for _, agent := range leg.Consensus.Agents {
+			sub := leg
+			sub.ID = ConsensusLegID(leg.ID, agent)
+			sub.Title = ConsensusLegTitle(leg.Title, leg.ID, agent)
+			sub.Consensus = nil
+			legs = append(legs, sub)
+			subLegs[leg.ID] = append(subLegs[leg.ID], sub.ID)
+		}
+	}
+	if len(subLegs) == 0 {
+		return f
+	}
+	for i := range legs {
+		legs[i].Needs = ExpandConsensusNeeds(legs[i].Needs, subLegs)
+	}
+	expanded := *f
+	expanded.Legs = legs
+	if f.Synthesis != nil {
+		syn := *f.Synthesis
+		syn.DependsOn = ExpandConsensusNeeds(syn.DependsOn, subLegs)
+		expanded.Synthesis = &syn
+	}
+	return &expanded
+}
+
+// ConsensusLegTitle is the title of an agent's leg of a consensus leg.
+func ConsensusLegTitle(title, legID, agent string) string {
+	if title == "" {
+		title = legID
+	}
+	return fmt.Sprintf("%s (%s)", title, agent)
+}
+
+// ExpandConsensusNeeds replaces needed consensus legs with their agents'
+// legs.
+func ExpandConsensusNeeds(needs []string, subLegs map[string][]string) []string {
+	var out []string
+	for _, need := range needs {
+		if subs, ok := subLegs[need]; ok {
+			out = append(out, subs...)
+		} else {
+			out = append(out, need)
+		}
+	}
+	return out
+}
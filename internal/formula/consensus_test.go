@@ -0,0 +1,95 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_LegConsensus(t *testing.T) {
+	data := []byte(`
+formula = "review"
+type = "convoy"
+
+[[legs]]
+id = "security"
+consensus = { agents = ["claude", "gemini", "codex"], strategy = "majority" }
+`)
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	c := f.Legs[0].Consensus
+	if c == nil || len(c.Agents) != 3 || c.StrategyOrDefault() != ConsensusMajority {
+		t.Fatalf("consensus = %+v", c)
+	}
+	if q := c.Quorum(3); q != 2 {
+		t.Errorf("majority Quorum(3) = %d, want 2", q)
+	}
+}
+
+func TestConsensusQuorum(t *testing.T) {
+	c := &Consensus{Agents: []string{"a", "b", "c", "d"}}
+	if q := c.Quorum(4); q != 4 {
+		t.Errorf("intersect Quorum(4) = %d, want 4", q)
+	}
+	c.Strategy = ConsensusMajority
+	if q := c.Quorum(4); q != 3 {
+		t.Errorf("majority Quorum(4) = %d, want 3", q)
+	}
+	c.Strategy = ConsensusUnion
+	if q := c.Quorum(4); q != 1 {
+		t.Errorf("union Quorum(4) = %d, want 1", q)
+	}
+}
+
+func TestConsensusValidate(t *testing.T) {
+	tests := []struct {
+		c    Consensus
+		want string
+	}{
+		{Consensus{Agents: []string{"claude"}}, "at least two agents"},
+		{Consensus{Agents: []string{"claude", "claude"}}, "listed twice"},
+		{Consensus{Agents: []string{"claude", "gemini"}, Strategy: "vote"}, "invalid consensus strategy"},
+	}
+	for _, tt := range tests {
+		if err := tt.c.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.c, err, tt.want)
+		}
+	}
+	if err := (&Consensus{Agents: []string{"claude", "gemini"}}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestExpandConsensus(t *testing.T) {
+	f := &Formula{
+		Name: "review",
+		Type: TypeConvoy,
+		Legs: []Leg{
+			{ID: "security", Title: "Security", Consensus: &Consensus{Agents: []string{"claude", "gemini"}}},
+			{ID: "summary", Needs: []string{"security"}},
+		},
+		Synthesis: &Synthesis{Title: "Report", DependsOn: []string{"security", "summary"}},
+	}
+
+	e := f.ExpandConsensus()
+	var ids []string
+	for _, leg := range e.Legs {
+		ids = append(ids, leg.ID)
+	}
+	if got := strings.Join(ids, " "); got != "security@claude security@gemini summary" {
+		t.Errorf("expanded legs = %s", got)
+	}
+	if e.Legs[1].Title != "Security (gemini)" || e.Legs[1].Consensus != nil {
+		t.Errorf("agent leg = %+v", e.Legs[1])
+	}
+	if got := strings.Join(e.Legs[2].Needs, " "); got != "security@claude security@gemini" {
+		t.Errorf("summary needs = %s", got)
+	}
+	if got := strings.Join(e.Synthesis.DependsOn, " "); got != "security@claude security@gemini summary" {
+		t.Errorf("synthesis depends_on = %s", got)
+	}
+	if len(f.Legs) != 2 || f.Synthesis.DependsOn[0] != "security" {
+		t.Error("ExpandConsensus() changed the original formula")
+	}
+}
@@ -0,0 +1,79 @@
+package formula
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Leg runners.
+const (
+	// RunnerHost runs the leg's agent directly on the host (the default).
+	RunnerHost = "host"
+	// RunnerDocker runs the leg's agent inside a container.
+	RunnerDocker = "docker"
+)
+
+// LegContainer is where a leg's agent runs when the leg opts into a
+// container. Declared in a formula as:
+//
+//	[[legs]]
+//	id = "build-check"
+//	runner = "docker"
+//	image = "ghcr.io/example/agent-go:1.22"
+//	mounts = ["/var/cache/go-build:/root/.cache/go-build", "/etc/ssl/certs:/etc/ssl/certs:ro"]
+//
+// The polecat's worktree is always bind-mounted at its host path and used
+// as the working directory, so the agent sees the same paths as on the host.
+type LegContainer struct {
+	// Image must provide the agent CLI.
+	Image string `toml:"image" json:"image"`
+
+	// Mounts are extra bind mounts as "src:dst" or "src:dst:ro". A relative
+	// src is resolved against the polecat's worktree.
+	Mounts []string `toml:"mounts" json:"mounts,omitempty"`
+}
+
+// NewLegContainer validates a leg's runner settings and returns its
+// container, or nil if the leg runs on the host.
+func NewLegContainer(runner, image string, mounts []string) (*LegContainer, error) {
+	switch runner {
+	case "", RunnerHost:
+		if image != "" || len(mounts) > 0 {
+			return nil, fmt.Errorf("image and mounts require runner = %q", RunnerDocker)
+		}
+		return nil, nil
+	case RunnerDocker:
+	default:
+		return nil, fmt.Errorf("invalid runner %q (must be %s or %s)", runner, RunnerHost, RunnerDocker)
+	}
+
+	if image == "" {
+		return nil, fmt.Errorf("runner = %q requires image", RunnerDocker)
+	}
+	for _, m := range mounts {
+		if _, _, _, err := ParseMount(m); err != nil {
+			return nil, err
+		}
+	}
+	return &LegContainer{Image: image, Mounts: mounts}, nil
+}
+
+// ParseMount splits a "src:dst[:ro|rw]" mount. dst must be absolute.
+func ParseMount(m string) (src, dst, mode string, err error) {
+	parts := strings.Split(m, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("invalid mount %q (want src:dst or src:dst:ro)", m)
+	}
+	src, dst = parts[0], parts[1]
+	if !path.IsAbs(dst) {
+		return "", "", "", fmt.Errorf("invalid mount %q: container path must be absolute", m)
+	}
+	if len(parts) == 3 {
+		mode = parts[2]
+		if mode != "ro" && mode != "rw" {
+			return "", "", "", fmt.Errorf("invalid mount %q: mode must be ro or rw", m)
+		}
+	}
+	return src, dst, mode, nil
+}
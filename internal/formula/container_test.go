@@ -0,0 +1,52 @@
+package formula
+
+import "testing"
+
+func TestParse_LegRunner(t *testing.T) {
+	data := []byte(`
+formula = "review"
+type = "convoy"
+
+[[legs]]
+id = "build"
+runner = "docker"
+image = "golang:1.22"
+mounts = ["/var/cache/go:/go/pkg/mod:ro"]
+
+[[legs]]
+id = "docs"
+`)
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	leg := f.Legs[0]
+	c, err := NewLegContainer(leg.Runner, leg.Image, leg.Mounts)
+	if err != nil || c == nil || c.Image != "golang:1.22" || len(c.Mounts) != 1 {
+		t.Errorf("container = %+v, %v", c, err)
+	}
+	if c, err := NewLegContainer(f.Legs[1].Runner, f.Legs[1].Image, f.Legs[1].Mounts); c != nil || err != nil {
+		t.Errorf("host leg container = %+v, %v", c, err)
+	}
+}
+
+func TestNewLegContainerErrors(t *testing.T) {
+	tests := []struct {
+		runner, image string
+		mounts        []string
+	}{
+		{"podman", "img", nil},
+		{RunnerDocker, "", nil},
+		{"", "img", nil},
+		{RunnerHost, "", []string{"/a:/b"}},
+		{RunnerDocker, "img", []string{"/a"}},
+		{RunnerDocker, "img", []string{"/a:b"}},
+		{RunnerDocker, "img", []string{"/a:/b:rx"}},
+		{RunnerDocker, "img", []string{"/a:/b:ro:x"}},
+	}
+	for _, tt := range tests {
+		if _, err := NewLegContainer(tt.runner, tt.image, tt.mounts); err == nil {
+			t.Errorf("NewLegContainer(%q, %q, %v): expected error", tt.runner, tt.image, tt.mounts)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContextStrategy declares how a formula trims inputs that don't fit the
+// agent's context window.
+type ContextStrategy string
+
+const (
+	// ContextNone sends context as-is (the default).
+	ContextNone ContextStrategy = ""
+	// ContextSummarizeDiff replaces the diff with an agent-written summary.
+	ContextSummarizeDiff ContextStrategy = "summarize_diff"
+	// ContextDropFileBodies replaces the diff with per-file change stats.
+	ContextDropFileBodies ContextStrategy = "drop_file_bodies"
+	// ContextChunk splits the diff into chunks that each fit the budget.
+	ContextChunk ContextStrategy = "chunk"
+)
+
+// IsValid returns true if the strategy is recognized.
+func (s ContextStrategy) IsValid() bool {
+	switch s {
+	case ContextNone, ContextSummarizeDiff, ContextDropFileBodies, ContextChunk:
+		return true
+	}
+	return false
+}
+
+// FileDiff is the portion of a unified diff that touches one file.
+type FileDiff struct {
+	Path      string
+	Body      string
+	Additions int
+	Deletions int
+}
+
+// SplitDiff splits a git-style unified diff into per-file sections.
+func SplitDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var cur *FileDiff
+	var body strings.Builder
+
+	flush := func() {
+		if cur != nil {
+			cur.Body = body.String()
+			files = append(files, *cur)
+		}
+		body.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			cur = &FileDiff{Path: diffPath(line)}
+		} else if cur == nil {
+			cur = &FileDiff{}
+		}
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			if p := strings.TrimPrefix(strings.TrimSpace(line[4:]), "b/"); p != "/dev/null" && cur.Path == "" {
+				cur.Path = p
+			}
+		case strings.HasPrefix(line, "--- "):
+		case strings.HasPrefix(line, "+"):
+			cur.Additions++
+		case strings.HasPrefix(line, "-"):
+			cur.Deletions++
+		}
+		body.WriteString(line)
+	}
+	flush()
+	return files
+}
+
+// diffPath extracts the new path from a "diff --git a/x b/y" header.
+func diffPath(header string) string {
+	fields := strings.Fields(strings.TrimSpace(header))
+	if len(fields) >= 4 {
+		return strings.TrimPrefix(fields[3], "b/")
+	}
+	return ""
+}
+
+// FileStats renders per-file change counts, dropping the diff bodies.
+func FileStats(files []FileDiff) string {
+	var b strings.Builder
+	adds, dels := 0, 0
+	for _, f := range files {
+		fmt.Fprintf(&b, "%s (+%d -%d)\n", f.Path, f.Additions, f.Deletions)
+		adds += f.Additions
+		dels += f.Deletions
+	}
+	fmt.Fprintf(&b, "%d file(s) changed, +%d -%d\n", len(files), adds, dels)
+	return b.String()
+}
+
+// ChunkDiff groups per-file diffs into chunks whose estimated size stays
+// within maxTokens. A single file larger than the budget is split on hunk
+// boundaries; a single hunk larger than the budget becomes its own chunk.
+func ChunkDiff(files []FileDiff, maxTokens int, estimate func(string) int) []string {
+	var chunks []string
+	var cur strings.Builder
+	curTokens := 0
+
+	add := func(piece string) {
+		n := estimate(piece)
+		if curTokens > 0 && curTokens+n > maxTokens {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+			curTokens = 0
+		}
+		cur.WriteString(piece)
+		curTokens += n
+	}
+
+	for _, f := range files {
+		if estimate(f.Body) <= maxTokens {
+			add(f.Body)
+			continue
+		}
+		for _, hunk := range splitHunks(f.Body) {
+			add(hunk)
+		}
+	}
+	if cur.Len() > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// splitHunks splits one file's diff into its header+first hunk and each
+// following "@@" hunk.
+func splitHunks(body string) []string {
+	var hunks []string
+	var cur strings.Builder
+	inHunk := false
+	for _, line := range strings.SplitAfter(body, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			if inHunk {
+				hunks = append(hunks, cur.String())
+				cur.Reset()
+			}
+			inHunk = true
+		}
+		cur.WriteString(line)
+	}
+	if cur.Len() > 0 {
+		hunks = append(hunks, cur.String())
+	}
+	return hunks
+}
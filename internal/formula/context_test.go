@@ -0,0 +1,76 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@
+ package main
++import "fmt"
+-var x = 1
+@@ -10,2 +11,3 @@
+ func main() {
++	fmt.Println("hi")
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-old
++new
+`
+
+func TestSplitDiff(t *testing.T) {
+	files := SplitDiff(sampleDiff)
+	if len(files) != 2 {
+		t.Fatalf("SplitDiff() returned %d files, want 2", len(files))
+	}
+	if files[0].Path != "main.go" || files[0].Additions != 2 || files[0].Deletions != 1 {
+		t.Errorf("files[0] = %+v, want main.go +2 -1", files[0])
+	}
+	if files[1].Path != "README.md" || files[1].Additions != 1 || files[1].Deletions != 1 {
+		t.Errorf("files[1] = %+v, want README.md +1 -1", files[1])
+	}
+	if got := files[0].Body + files[1].Body; got != sampleDiff {
+		t.Errorf("file bodies do not reassemble the diff")
+	}
+
+	stats := FileStats(files)
+	if !strings.Contains(stats, "main.go (+2 -1)") || !strings.Contains(stats, "2 file(s) changed, +3 -2") {
+		t.Errorf("FileStats() = %q", stats)
+	}
+}
+
+func TestChunkDiff(t *testing.T) {
+	files := SplitDiff(sampleDiff)
+	lines := func(s string) int { return strings.Count(s, "\n") }
+
+	// Budget fits everything: one chunk.
+	if chunks := ChunkDiff(files, 100, lines); len(chunks) != 1 {
+		t.Errorf("ChunkDiff(large budget) = %d chunks, want 1", len(chunks))
+	}
+
+	// main.go (10 lines) exceeds a 6-line budget and is split on hunks.
+	chunks := ChunkDiff(files, 6, lines)
+	if len(chunks) != 3 {
+		t.Fatalf("ChunkDiff(6) = %d chunks, want 3: %q", len(chunks), chunks)
+	}
+	if strings.Join(chunks, "") != sampleDiff {
+		t.Errorf("chunks do not reassemble the diff")
+	}
+}
+
+func TestContextStrategyValidation(t *testing.T) {
+	f := &Formula{Name: "x", Type: TypeConvoy, Legs: []Leg{{ID: "a"}}, ContextStrategy: "squash"}
+	if err := f.Validate(); err == nil {
+		t.Error("Validate() should reject unknown context_strategy")
+	}
+	f.ContextStrategy = ContextChunk
+	if err := f.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
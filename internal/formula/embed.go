@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // Generate formulas directory from canonical source at .beads/formulas/
-//go:generate sh -c "rm -rf formulas && mkdir -p formulas && cp ../../.beads/formulas/*.formula.toml formulas/"
+//go:generate sh -c "rm -rf formulas && mkdir -p formulas/fragments && cp ../../.beads/formulas/*.formula.toml formulas/ && cp ../../.beads/formulas/fragments/*.toml formulas/fragments/"
 
-//go:embed formulas/*.formula.toml
+//go:embed formulas/*.formula.toml formulas/fragments/*.toml
 var formulasFS embed.FS
 
 // InstalledRecord tracks which formulas were installed and their checksums.
@@ -70,6 +72,118 @@ func getEmbeddedFormulas() (map[string]string, error) {
 	return result, nil
 }
 
+// EmbeddedFormula returns the content of the formula shipped with gt, or an
+// error if no embedded formula has that name.
+func EmbeddedFormula(name string) ([]byte, error) {
+	content, err := formulasFS.ReadFile("formulas/" + name + ".formula.toml")
+	if err != nil {
+		return nil, fmt.Errorf("no embedded formula %q", name)
+	}
+	return content, nil
+}
+
+// ResetFormula overwrites the installed copy of an embedded formula in
+// beadsPath/.beads/formulas with the shipped version, discarding local
+// edits, and records it as installed.
+func ResetFormula(beadsPath, name string) error {
+	content, err := EmbeddedFormula(name)
+	if err != nil {
+		return err
+	}
+	formulasDir := filepath.Join(beadsPath, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		return fmt.Errorf("creating formulas directory: %w", err)
+	}
+	unlock, err := lockInstalled(formulasDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	installed, err := loadInstalledRecord(formulasDir)
+	if err != nil {
+		return err
+	}
+	filename := name + ".formula.toml"
+	if err := fsx.WriteFile(filepath.Join(formulasDir, filename), content, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	installed.Formulas[filename] = computeHash(content)
+	return saveInstalledRecord(formulasDir, installed)
+}
+
+// TransferInstalled re-stamps .installed.json after filename moved from the
+// formulas directory srcDir to dstDir, so gt doctor --fix treats the copy
+// at its new level the way it treated the original. A copy of an embedded
+// formula keeps its recorded hash; an unrecorded copy that differs from the
+// embedded version is recorded as modified, so updates never overwrite it.
+// Formulas that gt does not ship are not recorded.
+func TransferInstalled(srcDir, dstDir, filename string) error {
+	// Lock both records in a fixed order so opposite moves can't deadlock.
+	first, second := srcDir, dstDir
+	if second < first {
+		first, second = second, first
+	}
+	unlockFirst, err := lockInstalled(first)
+	if err != nil {
+		return err
+	}
+	defer unlockFirst()
+	if second != first {
+		unlockSecond, err := lockInstalled(second)
+		if err != nil {
+			return err
+		}
+		defer unlockSecond()
+	}
+
+	src, err := loadInstalledRecord(srcDir)
+	if err != nil {
+		return err
+	}
+	dst, err := loadInstalledRecord(dstDir)
+	if err != nil {
+		return err
+	}
+	srcHash, srcRecorded := src.Formulas[filename]
+
+	_, dstChanged := dst.Formulas[filename]
+	delete(dst.Formulas, filename)
+	if content, err := formulasFS.ReadFile("formulas/" + filename); err == nil {
+		embeddedHash := computeHash(content)
+		currentHash, err := computeFileHash(filepath.Join(dstDir, filename))
+		if err != nil {
+			return err
+		}
+		switch {
+		case currentHash == embeddedHash:
+			dst.Formulas[filename] = embeddedHash
+		case srcRecorded:
+			dst.Formulas[filename] = srcHash
+		default:
+			dst.Formulas[filename] = embeddedHash
+		}
+		dstChanged = true
+	}
+	if dstChanged {
+		if err := saveInstalledRecord(dstDir, dst); err != nil {
+			return err
+		}
+	}
+
+	if srcRecorded {
+		delete(src.Formulas, filename)
+		return saveInstalledRecord(srcDir, src)
+	}
+	return nil
+}
+
+// lockInstalled locks formulasDir's install record so concurrent update,
+// reset, and promote runs don't drop each other's entries.
+func lockInstalled(formulasDir string) (func(), error) {
+	return fsx.Lock(filepath.Join(formulasDir, ".installed.json"))
+}
+
 // loadInstalledRecord loads the installed record from disk.
 func loadInstalledRecord(formulasDir string) (*InstalledRecord, error) {
 	path := filepath.Join(formulasDir, ".installed.json")
@@ -97,7 +211,7 @@ func saveInstalledRecord(formulasDir string, record *InstalledRecord) error {
 	if err != nil {
 		return fmt.Errorf("encoding installed record: %w", err)
 	}
-	return os.WriteFile(path, data, 0644)
+	return fsx.WriteFile(path, data, 0644)
 }
 
 // computeFileHash computes SHA256 hash of a file.
@@ -130,6 +244,12 @@ func ProvisionFormulas(beadsPath string) (int, error) {
 		return 0, fmt.Errorf("creating formulas directory: %w", err)
 	}
 
+	unlock, err := lockInstalled(formulasDir)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
 	// Load existing installed record (or create new)
 	installed, err := loadInstalledRecord(formulasDir)
 	if err != nil {
@@ -157,7 +277,7 @@ func ProvisionFormulas(beadsPath string) (int, error) {
 			return count, fmt.Errorf("reading %s: %w", entry.Name(), err)
 		}
 
-		if err := os.WriteFile(destPath, content, 0644); err != nil {
+		if err := fsx.WriteFile(destPath, content, 0644); err != nil {
 			return count, fmt.Errorf("writing %s: %w", entry.Name(), err)
 		}
 
@@ -262,6 +382,12 @@ func UpdateFormulas(beadsPath string) (updated, skipped, reinstalled int, err er
 		return 0, 0, 0, fmt.Errorf("creating formulas directory: %w", err)
 	}
 
+	unlock, err := lockInstalled(formulasDir)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer unlock()
+
 	installed, err := loadInstalledRecord(formulasDir)
 	if err != nil {
 		return 0, 0, 0, err
@@ -310,7 +436,7 @@ func UpdateFormulas(beadsPath string) (updated, skipped, reinstalled int, err er
 				return updated, skipped, reinstalled, fmt.Errorf("reading %s: %w", filename, err)
 			}
 
-			if err := os.WriteFile(destPath, content, 0644); err != nil {
+			if err := fsx.WriteFile(destPath, content, 0644); err != nil {
 				return updated, skipped, reinstalled, fmt.Errorf("writing %s: %w", filename, err)
 			}
 
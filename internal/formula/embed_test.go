@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -32,6 +33,80 @@ func TestGetEmbeddedFormulas(t *testing.T) {
 	}
 }
 
+// TestEmbeddedFormulasMatchSource verifies the embedded formulas were
+// regenerated (go generate) from the canonical copies in .beads/formulas.
+func TestEmbeddedFormulasMatchSource(t *testing.T) {
+	for _, pattern := range []string{"*.formula.toml", "fragments/*.toml"} {
+		sources, err := filepath.Glob(filepath.Join("..", "..", ".beads", "formulas", pattern))
+		if err != nil {
+			t.Fatal(err)
+		}
+		embedded, err := fs.Glob(formulasFS, "formulas/"+pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sources) != len(embedded) {
+			t.Errorf("%s: %d source files, %d embedded; run go generate", pattern, len(sources), len(embedded))
+		}
+		for _, src := range sources {
+			rel, _ := filepath.Rel(filepath.Join("..", "..", ".beads", "formulas"), src)
+			want, err := os.ReadFile(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := formulasFS.ReadFile("formulas/" + filepath.ToSlash(rel))
+			if err != nil || string(got) != string(want) {
+				t.Errorf("embedded %s differs from .beads/formulas; run go generate", rel)
+			}
+		}
+	}
+}
+
+func TestEmbeddedFormula(t *testing.T) {
+	content, err := EmbeddedFormula("mol-deacon-patrol")
+	if err != nil {
+		t.Fatalf("EmbeddedFormula() error: %v", err)
+	}
+	if len(content) == 0 {
+		t.Error("embedded formula should have content")
+	}
+	if _, err := EmbeddedFormula("no-such-formula"); err == nil {
+		t.Error("expected error for unknown formula")
+	}
+}
+
+func TestResetFormula(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := ProvisionFormulas(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(tmpDir, ".beads", "formulas", "mol-deacon-patrol.formula.toml")
+	if err := os.WriteFile(path, []byte("# local edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ResetFormula(tmpDir, "mol-deacon-patrol"); err != nil {
+		t.Fatalf("ResetFormula() error: %v", err)
+	}
+	want, _ := EmbeddedFormula("mol-deacon-patrol")
+	got, _ := os.ReadFile(path)
+	if string(got) != string(want) {
+		t.Error("ResetFormula did not restore embedded content")
+	}
+
+	report, err := CheckFormulaHealth(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Modified != 0 {
+		t.Errorf("Modified = %d after reset, want 0", report.Modified)
+	}
+
+	if err := ResetFormula(tmpDir, "no-such-formula"); err == nil {
+		t.Error("expected error for unknown formula")
+	}
+}
+
 // TestProvisionFormulas_FreshInstall tests provisioning to an empty directory.
 func TestProvisionFormulas_FreshInstall(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -746,3 +821,41 @@ func TestCheckFormulaHealth_MixedScenarios(t *testing.T) {
 		t.Errorf("formula %s status = %q, want %q", modifiedFormula, statusMap[modifiedFormula], "modified")
 	}
 }
+
+func TestTransferInstalled(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := ProvisionFormulas(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	srcDir := filepath.Join(tmpDir, ".beads", "formulas")
+	dstRoot := t.TempDir()
+	dstDir := filepath.Join(dstRoot, ".beads", "formulas")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Move a locally edited copy of a shipped formula.
+	filename := "mol-deacon-patrol.formula.toml"
+	if err := os.WriteFile(filepath.Join(dstDir, filename), []byte("# local edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(srcDir, filename)); err != nil {
+		t.Fatal(err)
+	}
+	if err := TransferInstalled(srcDir, dstDir, filename); err != nil {
+		t.Fatalf("TransferInstalled() error: %v", err)
+	}
+
+	src, _ := loadInstalledRecord(srcDir)
+	if _, ok := src.Formulas[filename]; ok {
+		t.Error("source record should be removed")
+	}
+	// The edit must survive gt doctor --fix at the new level.
+	if _, skipped, _, err := UpdateFormulas(dstRoot); err != nil || skipped != 1 {
+		t.Errorf("UpdateFormulas skipped = %d, %v; want the moved edit skipped", skipped, err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dstDir, filename))
+	if string(got) != "# local edit\n" {
+		t.Error("update overwrote the moved formula")
+	}
+}
@@ -0,0 +1,185 @@
+package formula
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LegExpect is a leg's output contract: the files its polecat must produce
+// and what they must contain. Declared in a formula as:
+//
+//	[[legs]]
+//	id = "security"
+//	expect.files = ["{{ .output_path }}"]
+//	expect.min_bytes = 200
+//	expect.json_schema = "schemas/findings.json"
+type LegExpect struct {
+	// Files are the paths the leg must write. Formula files may use the
+	// leg's prompt template variables; gt formula run renders them.
+	Files []string `toml:"files" json:"files,omitempty"`
+
+	// MinBytes is the minimum size of each file. Zero still rejects empty files.
+	MinBytes int `toml:"min_bytes" json:"min_bytes,omitempty"`
+
+	// JSONSchema is a schema every file must satisfy, either inline JSON or
+	// a path relative to the formula file.
+	JSONSchema string `toml:"json_schema" json:"json_schema,omitempty"`
+}
+
+// IsInlineSchema reports whether JSONSchema holds a schema document rather
+// than a path to one.
+func (e *LegExpect) IsInlineSchema() bool {
+	return strings.HasPrefix(strings.TrimSpace(e.JSONSchema), "{")
+}
+
+// Check validates the contract against the files on disk and returns one
+// message per violation. Relative file paths are resolved against baseDir.
+func (e *LegExpect) Check(baseDir string) []string {
+	if e == nil {
+		return nil
+	}
+
+	var schema map[string]interface{}
+	var violations []string
+	if e.JSONSchema != "" {
+		if !e.IsInlineSchema() {
+			return []string{"json_schema was not resolved to a schema document"}
+		}
+		if err := json.Unmarshal([]byte(e.JSONSchema), &schema); err != nil {
+			return []string{fmt.Sprintf("json_schema is not valid JSON: %v", err)}
+		}
+	}
+
+	minBytes := e.MinBytes
+	if minBytes < 1 {
+		minBytes = 1
+	}
+	for _, file := range e.Files {
+		path := file
+		if !filepath.IsAbs(path) && baseDir != "" {
+			path = filepath.Join(baseDir, path)
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: paths come from the formula contract
+		if os.IsNotExist(err) {
+			violations = append(violations, fmt.Sprintf("%s: missing", file))
+			continue
+		}
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		if len(data) < minBytes {
+			violations = append(violations, fmt.Sprintf("%s: %d bytes, expected at least %d", file, len(data), minBytes))
+			continue
+		}
+		if schema != nil {
+			var doc interface{}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				violations = append(violations, fmt.Sprintf("%s: not valid JSON: %v", file, err))
+				continue
+			}
+			for _, msg := range checkSchema(schema, doc, "$") {
+				violations = append(violations, fmt.Sprintf("%s: %s", file, msg))
+			}
+		}
+	}
+	return violations
+}
+
+// checkSchema validates v against the JSON Schema keywords gt supports:
+// type, enum, required, properties, items, minItems, and minLength.
+func checkSchema(schema map[string]interface{}, v interface{}, at string) []string {
+	var out []string
+
+	if want, ok := schema["type"].(string); ok && !schemaTypeMatches(want, v) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", at, want, schemaTypeOf(v))}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, fmt.Sprintf("%s: %v is not one of %v", at, v, enum))
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				name, _ := r.(string)
+				if _, present := val[name]; !present {
+					out = append(out, fmt.Sprintf("%s: missing required property %q", at, name))
+				}
+			}
+		}
+		if props, ok := schema["properties"].(map[string]interface{}); ok {
+			names := make([]string, 0, len(props))
+			for name := range props {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				sub, ok := props[name].(map[string]interface{})
+				child, present := val[name]
+				if ok && present {
+					out = append(out, checkSchema(sub, child, at+"."+name)...)
+				}
+			}
+		}
+	case []interface{}:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(val)) < min {
+			out = append(out, fmt.Sprintf("%s: %d items, expected at least %d", at, len(val), int(min)))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range val {
+				out = append(out, checkSchema(items, item, fmt.Sprintf("%s[%d]", at, i))...)
+			}
+		}
+	case string:
+		if min, ok := schema["minLength"].(float64); ok && float64(len(val)) < min {
+			out = append(out, fmt.Sprintf("%s: length %d, expected at least %d", at, len(val), int(min)))
+		}
+	}
+	return out
+}
+
+// schemaTypeMatches reports whether v is of JSON Schema type want.
+func schemaTypeMatches(want string, v interface{}) bool {
+	got := schemaTypeOf(v)
+	if want == "number" && got == "integer" {
+		return true
+	}
+	return want == got
+}
+
+// schemaTypeOf returns the JSON Schema type name of a decoded JSON value.
+func schemaTypeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == float64(int64(val)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,83 @@
+package formula
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse_LegExpect(t *testing.T) {
+	data := []byte(`
+formula = "review"
+type = "convoy"
+
+[[legs]]
+id = "security"
+title = "Security"
+expect.files = ["{{ .output_path }}"]
+expect.min_bytes = 200
+expect.json_schema = "schemas/findings.json"
+`)
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	e := f.Legs[0].Expect
+	if e == nil {
+		t.Fatal("expected leg contract")
+	}
+	if len(e.Files) != 1 || e.Files[0] != "{{ .output_path }}" || e.MinBytes != 200 || e.JSONSchema != "schemas/findings.json" {
+		t.Errorf("Expect = %+v", e)
+	}
+	if e.IsInlineSchema() {
+		t.Error("schema path reported as inline")
+	}
+}
+
+func TestLegExpectCheck(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("empty.md", "")
+	write("short.md", "tiny")
+	write("good.json", `{"findings": [{"severity": "high", "title": "SQL injection"}]}`)
+	write("bad.json", `{"findings": [{"severity": "urgent"}]}`)
+
+	schema := `{"type": "object", "required": ["findings"], "properties": {
+		"findings": {"type": "array", "minItems": 1, "items": {
+			"type": "object", "required": ["title"],
+			"properties": {"severity": {"enum": ["low", "medium", "high"]}}}}}}`
+
+	tests := []struct {
+		name   string
+		expect LegExpect
+		want   []string // substrings, one per expected violation
+	}{
+		{"missing file", LegExpect{Files: []string{"nope.md"}}, []string{"nope.md: missing"}},
+		{"empty file rejected by default", LegExpect{Files: []string{"empty.md"}}, []string{"0 bytes"}},
+		{"below min_bytes", LegExpect{Files: []string{"short.md"}, MinBytes: 10}, []string{"4 bytes, expected at least 10"}},
+		{"schema ok", LegExpect{Files: []string{"good.json"}, JSONSchema: schema}, nil},
+		{"schema violations", LegExpect{Files: []string{"bad.json"}, JSONSchema: schema}, []string{
+			`missing required property "title"`, "urgent is not one of",
+		}},
+		{"not json", LegExpect{Files: []string{"short.md"}, JSONSchema: schema}, []string{"not valid JSON"}},
+		{"unresolved schema path", LegExpect{Files: []string{"good.json"}, JSONSchema: "schema.json"}, []string{"not resolved"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.expect.Check(dir)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Check() = %q, want %d violations", got, len(tt.want))
+			}
+			for i, sub := range tt.want {
+				if !strings.Contains(got[i], sub) {
+					t.Errorf("violation %d = %q, want substring %q", i, got[i], sub)
+				}
+			}
+		})
+	}
+}
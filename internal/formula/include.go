@@ -0,0 +1,151 @@
+package formula
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// A formula can pull shared legs from fragment files with a top-level
+// include key:
+//
+//	include = "fragments/security-legs.toml"
+//	include = ["fragments/security-legs.toml", "fragments/license.toml"]
+//
+// A fragment holds only [[legs]] tables (and optionally its own include).
+// Paths are relative; each is tried in the directories passed to
+// ExpandIncludes, in order, and then among the fragments shipped with gt.
+
+// fragmentKeys are the top-level keys a fragment may define.
+var fragmentKeys = map[string]bool{"legs": true, "include": true}
+
+// ExpandIncludes returns formula TOML with its include key removed and the
+// legs of every included fragment appended. Content without an include key
+// is returned unchanged.
+func ExpandIncludes(data []byte, dirs []string) ([]byte, error) {
+	return expandIncludes(data, dirs, nil)
+}
+
+func expandIncludes(data []byte, dirs []string, stack []string) ([]byte, error) {
+	var head struct {
+		Include includeList `toml:"include"`
+	}
+	if _, err := toml.Decode(string(data), &head); err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	if len(head.Include) == 0 {
+		return data, nil
+	}
+
+	var out strings.Builder
+	out.WriteString(strings.TrimRight(stripInclude(string(data)), "\n"))
+	out.WriteString("\n")
+	for _, name := range head.Include {
+		for _, s := range stack {
+			if s == name {
+				return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), name)
+			}
+		}
+		fragment, err := readFragment(name, dirs)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkFragment(name, fragment); err != nil {
+			return nil, err
+		}
+		fragment, err = expandIncludes(fragment, dirs, append(stack, name))
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&out, "\n# included from %s\n%s\n", name, strings.TrimSpace(string(fragment)))
+	}
+	return []byte(out.String()), nil
+}
+
+// readFragment finds an included fragment in dirs, then among the embedded
+// fragments.
+func readFragment(name string, dirs []string) ([]byte, error) {
+	if !filepath.IsLocal(name) {
+		return nil, fmt.Errorf("include %q: path must be relative and stay inside the formulas directory", name)
+	}
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: name is local to a formula search path
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("include %q: %w", name, err)
+		}
+	}
+	if data, err := formulasFS.ReadFile(path.Join("formulas", filepath.ToSlash(name))); err == nil {
+		return data, nil
+	}
+	return nil, fmt.Errorf("include %q: fragment not found in %s or embedded formulas", name, strings.Join(dirs, ", "))
+}
+
+// checkFragment rejects fragments that set anything other than legs, since
+// appending them would override the including formula's own settings.
+func checkFragment(name string, data []byte) error {
+	var keys map[string]interface{}
+	if _, err := toml.Decode(string(data), &keys); err != nil {
+		return fmt.Errorf("include %q: parsing TOML: %w", name, err)
+	}
+	for key := range keys {
+		if !fragmentKeys[key] {
+			return fmt.Errorf("include %q: fragments may only define [[legs]], found %q", name, key)
+		}
+	}
+	return nil
+}
+
+// stripInclude removes the top-level include assignment, including the
+// continuation lines of a multi-line array.
+func stripInclude(content string) string {
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "[") {
+			// Past the top-level keys.
+			out = append(out, lines[i:]...)
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "include" {
+			out = append(out, lines[i])
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "[") {
+			for !strings.Contains(lines[i], "]") && i+1 < len(lines) {
+				i++
+			}
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// includeList accepts include as a single path or an array of paths.
+type includeList []string
+
+func (l *includeList) UnmarshalTOML(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		*l = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("include must be a path or an array of paths")
+			}
+			*l = append(*l, s)
+		}
+	default:
+		return fmt.Errorf("include must be a path or an array of paths")
+	}
+	return nil
+}
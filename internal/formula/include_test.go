@@ -0,0 +1,132 @@
+package formula
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseFileIncludes(t *testing.T) {
+	rigDir := t.TempDir()
+	townDir := t.TempDir()
+	writeFile(t, filepath.Join(rigDir, "review.formula.toml"), `formula = "review"
+type = "convoy"
+include = ["fragments/local.toml", "fragments/shared.toml", "fragments/security-legs.toml"]
+
+[[legs]]
+id = "correctness"
+title = "Correctness"
+
+[synthesis]
+title = "Report"
+`)
+	writeFile(t, filepath.Join(rigDir, "fragments", "local.toml"), `[[legs]]
+id = "style"
+title = "Style"
+`)
+	writeFile(t, filepath.Join(townDir, "fragments", "shared.toml"), `include = "fragments/nested.toml"
+
+[[legs]]
+id = "docs"
+title = "Docs"
+needs = ["correctness"]
+`)
+	writeFile(t, filepath.Join(townDir, "fragments", "nested.toml"), `[[legs]]
+id = "changelog"
+title = "Changelog"
+`)
+
+	f, err := ParseFile(filepath.Join(rigDir, "review.formula.toml"), townDir)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	var ids []string
+	for _, leg := range f.Legs {
+		ids = append(ids, leg.ID)
+	}
+	// security-legs.toml is embedded in gt.
+	want := "correctness,style,docs,changelog,license-check,dependency-audit"
+	if strings.Join(ids, ",") != want {
+		t.Errorf("legs = %v, want %s", ids, want)
+	}
+	if f.Synthesis == nil || f.Synthesis.Title != "Report" {
+		t.Errorf("synthesis = %+v, want the formula's own", f.Synthesis)
+	}
+}
+
+func TestExpandIncludesErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "settings.toml"), "type = \"workflow\"\n")
+	writeFile(t, filepath.Join(dir, "a.toml"), "include = \"b.toml\"\n")
+	writeFile(t, filepath.Join(dir, "b.toml"), "include = \"a.toml\"\n")
+	writeFile(t, filepath.Join(dir, "dup.toml"), "[[legs]]\nid = \"x\"\n")
+
+	tests := []struct {
+		name    string
+		include string
+		want    string
+	}{
+		{"missing", `"nope.toml"`, "fragment not found"},
+		{"escapes", `"../secrets.toml"`, "must be relative"},
+		{"absolute", `"/etc/passwd"`, "must be relative"},
+		{"not legs", `"settings.toml"`, `found "type"`},
+		{"cycle", `"a.toml"`, "include cycle"},
+		{"bad type", `3`, "path or an array"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ExpandIncludes([]byte("formula = \"f\"\ninclude = "+tc.include+"\n"), []string{dir})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ExpandIncludes() error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	data, err := ExpandIncludes([]byte("formula = \"f\"\ntype = \"convoy\"\ninclude = \"dup.toml\"\n[[legs]]\nid = \"x\"\n"), []string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Parse(data); err == nil || !strings.Contains(err.Error(), "duplicate leg id") {
+		t.Errorf("Parse() error = %v, want duplicate leg id", err)
+	}
+}
+
+func TestExpandIncludesMultilineArray(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.toml"), "[[legs]]\nid = \"a\"\n")
+	data := []byte("formula = \"f\"\ninclude = [\n  \"a.toml\",\n]\n\n[[legs]]\nid = \"b\"\n")
+
+	got, err := ExpandIncludes(data, []string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(got), "include =") {
+		t.Errorf("include key not removed:\n%s", got)
+	}
+	f, err := Parse(got)
+	if err != nil {
+		t.Fatalf("Parse() error = %v\n%s", err, got)
+	}
+	if len(f.Legs) != 2 || f.Legs[1].ID != "a" {
+		t.Errorf("legs = %+v", f.Legs)
+	}
+}
+
+func TestExpandIncludesNoInclude(t *testing.T) {
+	data := []byte("formula = \"f\"\n")
+	got, err := ExpandIncludes(data, nil)
+	if err != nil || string(got) != string(data) {
+		t.Errorf("ExpandIncludes() = %q, %v; want input unchanged", got, err)
+	}
+}
@@ -0,0 +1,69 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrOutputEscapes reports a rendered output path outside where formula
+// outputs may be written.
+var ErrOutputEscapes = errors.New("output path escapes the output root")
+
+// pathUnsafeRefRE finds template references to free-form text, which may
+// contain path separators or "..".
+var pathUnsafeRefRE = regexp.MustCompile(`\.(pr_title|changed_files|target_description|leg\.(title|focus|description))\b`)
+
+// CheckOutputPath verifies that a rendered output path stays where formula
+// outputs may be written. Relative paths, which are resolved against the
+// directory the run writes from, must not climb out of it with "..".
+// Absolute paths must lie within root.
+func CheckOutputPath(root, p string) error {
+	if p == "" {
+		return nil
+	}
+	if !filepath.IsAbs(p) {
+		if !filepath.IsLocal(p) {
+			return fmt.Errorf("%w: %s leaves the directory it is relative to", ErrOutputEscapes, p)
+		}
+		return nil
+	}
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(p))
+	if root == "" || err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("%w: %s is outside %s", ErrOutputEscapes, p, root)
+	}
+	return nil
+}
+
+// LintOutputPaths returns problems with f's output path templates: absolute
+// or ".." paths, and interpolation of free-form text such as PR titles.
+func LintOutputPaths(f *Formula) []string {
+	if f.Output == nil {
+		return nil
+	}
+	var problems []string
+	check := func(field, tmpl string, local bool) {
+		if tmpl == "" {
+			return
+		}
+		static := templateActionRE.ReplaceAllString(tmpl, "x")
+		switch {
+		case local && !filepath.IsLocal(static):
+			problems = append(problems, fmt.Sprintf("output.%s %q must be a relative path inside the output directory", field, tmpl))
+		case !local && !filepath.IsAbs(static) && !filepath.IsLocal(static):
+			problems = append(problems, fmt.Sprintf("output.%s %q climbs out of its directory with ..", field, tmpl))
+		}
+		for _, m := range templateActionRE.FindAllStringSubmatch(tmpl, -1) {
+			if ref := pathUnsafeRefRE.FindString(m[1]); ref != "" {
+				problems = append(problems, fmt.Sprintf("output.%s interpolates %s, which may contain path separators",
+					field, strings.TrimPrefix(ref, ".")))
+			}
+		}
+	}
+	check("directory", f.Output.Directory, false)
+	check("leg_pattern", f.Output.LegPattern, true)
+	check("synthesis", f.Output.Synthesis, true)
+	return problems
+}
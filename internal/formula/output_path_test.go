@@ -0,0 +1,70 @@
+package formula
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckOutputPath(t *testing.T) {
+	root := "/town/gastown"
+	for _, ok := range []string{"", ".reviews/abc12", "out/x.md", "/town/gastown/.reviews/abc12", "/town/gastown"} {
+		if err := CheckOutputPath(root, ok); err != nil {
+			t.Errorf("CheckOutputPath(%q) = %v, want nil", ok, err)
+		}
+	}
+	for _, bad := range []string{"../../etc", ".reviews/../../x", "/etc/cron.d", "/town/other", "/town/gastown/../other"} {
+		if err := CheckOutputPath(root, bad); !errors.Is(err, ErrOutputEscapes) {
+			t.Errorf("CheckOutputPath(%q) = %v, want ErrOutputEscapes", bad, err)
+		}
+	}
+	if err := CheckOutputPath("", "/tmp/x"); !errors.Is(err, ErrOutputEscapes) {
+		t.Errorf("absolute path without root: err = %v", err)
+	}
+}
+
+func TestLintOutputPaths(t *testing.T) {
+	f := &Formula{Output: &Output{
+		Directory:  ".reviews/{{.review_id}}",
+		LegPattern: "{{.leg.id}}-findings.md",
+		Synthesis:  "review-summary.md",
+	}}
+	if problems := LintOutputPaths(f); len(problems) != 0 {
+		t.Errorf("safe templates: %v", problems)
+	}
+
+	f.Output = &Output{
+		Directory:  "../{{.review_id}}",
+		LegPattern: "{{.leg.title}}.md",
+		Synthesis:  "/tmp/summary.md",
+	}
+	problems := strings.Join(LintOutputPaths(f), "\n")
+	for _, want := range []string{"output.directory", "leg.title", "output.synthesis"} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("problems missing %q:\n%s", want, problems)
+		}
+	}
+}
+
+func TestLintOutputPathsEmbeddedFormulas(t *testing.T) {
+	entries, err := formulasFS.ReadDir("formulas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := formulasFS.ReadFile("formulas/" + entry.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := Parse(data)
+		if err != nil {
+			continue
+		}
+		for _, problem := range LintOutputPaths(f) {
+			t.Errorf("%s: %s", entry.Name(), problem)
+		}
+	}
+}
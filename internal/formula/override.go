@@ -0,0 +1,102 @@
+package formula
+
+import (
+	"strings"
+)
+
+// Override annotates a customized formula file with who owns it and why it
+// differs, so others know who to ask before resetting or updating it. It is
+// stored as leading TOML comments:
+//
+//	# owner: alice
+//	# reason: tuned for monorepo
+type Override struct {
+	Owner  string `json:"owner,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// IsZero reports whether no override metadata is set.
+func (o Override) IsZero() bool {
+	return o.Owner == "" && o.Reason == ""
+}
+
+// overrideKeys are the header keys ParseOverride and SetOverride manage.
+var overrideKeys = []string{"owner", "reason"}
+
+// ParseOverride reads override metadata from the comment block at the top
+// of formula TOML. Parsing stops at the first line that is not a comment.
+func ParseOverride(data []byte) Override {
+	var o Override
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := overrideHeaderLine(line)
+		if !ok {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			break
+		}
+		switch key {
+		case "owner":
+			o.Owner = value
+		case "reason":
+			o.Reason = value
+		}
+	}
+	return o
+}
+
+// SetOverride returns formula TOML with its override header replaced by o.
+// Other leading comments and the body are kept; empty fields are removed.
+func SetOverride(data []byte, o Override) []byte {
+	lines := strings.Split(string(data), "\n")
+
+	// Drop existing override lines from the leading comment block.
+	var kept []string
+	i := 0
+	for ; i < len(lines); i++ {
+		if _, _, ok := overrideHeaderLine(lines[i]); ok {
+			continue
+		}
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), "#") {
+			break
+		}
+		kept = append(kept, lines[i])
+	}
+
+	var header []string
+	if o.Owner != "" {
+		header = append(header, "# owner: "+oneLine(o.Owner))
+	}
+	if o.Reason != "" {
+		header = append(header, "# reason: "+oneLine(o.Reason))
+	}
+
+	out := make([]string, 0, len(header)+len(kept)+len(lines)-i)
+	out = append(out, header...)
+	out = append(out, kept...)
+	out = append(out, lines[i:]...)
+	return []byte(strings.Join(out, "\n"))
+}
+
+// overrideHeaderLine parses a "# key: value" override comment.
+func overrideHeaderLine(line string) (key, value string, ok bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+	if !ok {
+		return "", "", false
+	}
+	key, value, ok = strings.Cut(rest, ":")
+	if !ok {
+		return "", "", false
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, k := range overrideKeys {
+		if key == k {
+			return key, strings.TrimSpace(value), true
+		}
+	}
+	return "", "", false
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,45 @@
+package formula
+
+import (
+	"testing"
+)
+
+func TestOverrideRoundTrip(t *testing.T) {
+	body := "# Code review formula\n\nformula = \"code-review\"\ntype = \"convoy\"\n"
+
+	got := SetOverride([]byte(body), Override{Owner: "alice", Reason: "tuned for\nmonorepo"})
+	want := "# owner: alice\n# reason: tuned for monorepo\n" + body
+	if string(got) != want {
+		t.Fatalf("SetOverride =\n%s\nwant\n%s", got, want)
+	}
+	if o := ParseOverride(got); o.Owner != "alice" || o.Reason != "tuned for monorepo" {
+		t.Errorf("ParseOverride = %+v", o)
+	}
+
+	// Replacing keeps unrelated comments; clearing a field removes its line.
+	got = SetOverride(got, Override{Owner: "bob"})
+	if want := "# owner: bob\n" + body; string(got) != want {
+		t.Errorf("SetOverride replace =\n%s\nwant\n%s", got, want)
+	}
+	if got := SetOverride(got, Override{}); string(got) != body {
+		t.Errorf("SetOverride clear =\n%s\nwant\n%s", got, body)
+	}
+}
+
+func TestParseOverrideHeaderOnly(t *testing.T) {
+	data := "formula = \"x\"\n# owner: mallory\n"
+	if o := ParseOverride([]byte(data)); !o.IsZero() {
+		t.Errorf("comments after the header should be ignored, got %+v", o)
+	}
+}
+
+func TestParseMetadataOverride(t *testing.T) {
+	data := "# owner: alice\n# reason: tuned\nformula = \"x\"\ntype = \"workflow\"\n"
+	m, err := ParseMetadata([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Owner != "alice" || m.Reason != "tuned" {
+		t.Errorf("Owner, Reason = %q, %q", m.Owner, m.Reason)
+	}
+}
@@ -3,33 +3,70 @@ package formula
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 
 	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/agentcache"
 )
 
-// ParseFile reads and parses a formula.toml file.
-func ParseFile(path string) (*Formula, error) {
+// ParseFile reads and parses a formula.toml file. Included fragments are
+// looked up next to the file, then in includeDirs.
+func ParseFile(path string, includeDirs ...string) (*Formula, error) {
+	f, err := DecodeFile(path, includeDirs...)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// DecodeFile reads a formula.toml file like ParseFile, without validating
+// it.
+func DecodeFile(path string, includeDirs ...string) (*Formula, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted formula directory
 	if err != nil {
 		return nil, fmt.Errorf("reading formula file: %w", err)
 	}
-	return Parse(data)
+	data, err = ExpandIncludes(data, append([]string{filepath.Dir(path)}, includeDirs...))
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
 }
 
-// Parse parses formula.toml content from bytes.
+// Parse parses formula.toml content from bytes. Formulas on an older
+// schema are upgraded first; newer schemas fail with a *SchemaError.
 func Parse(data []byte) (*Formula, error) {
+	f, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Decode parses formula.toml content like Parse, without validating it.
+// Formulas that extend others are only complete once bd resolves them.
+func Decode(data []byte) (*Formula, error) {
+	data, schema, err := upgradeSchema(data)
+	if err != nil {
+		return nil, err
+	}
+
 	var f Formula
 	if _, err := toml.Decode(string(data), &f); err != nil {
 		return nil, fmt.Errorf("parsing TOML: %w", err)
 	}
+	f.Schema = schema
 
 	// Infer type from content if not explicitly set
 	f.inferType()
 
-	if err := f.Validate(); err != nil {
-		return nil, err
-	}
-
 	return &f, nil
 }
 
@@ -53,13 +90,8 @@ func (f *Formula) inferType() {
 
 // Validate checks that the formula has all required fields and valid structure.
 func (f *Formula) Validate() error {
-	// Check required common fields
-	if f.Name == "" {
-		return fmt.Errorf("formula field is required")
-	}
-
-	if !f.Type.IsValid() {
-		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
+	if err := f.ValidateCommon(); err != nil {
+		return err
 	}
 
 	// Type-specific validation
@@ -77,6 +109,33 @@ func (f *Formula) Validate() error {
 	return nil
 }
 
+// ValidateCommon checks the fields every formula type shares.
+func (f *Formula) ValidateCommon() error {
+	if f.Name == "" {
+		return fmt.Errorf("formula field is required")
+	}
+
+	if !f.Type.IsValid() {
+		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
+	}
+
+	if !f.ContextStrategy.IsValid() {
+		return fmt.Errorf("invalid context_strategy %q (must be summarize_diff, drop_file_bodies, or chunk)", f.ContextStrategy)
+	}
+	if f.ContextLimit < 0 {
+		return fmt.Errorf("context_limit must not be negative")
+	}
+	if _, err := ParseRequirements(f.Requires); err != nil {
+		return fmt.Errorf("requires: %w", err)
+	}
+	if f.CacheTTL != "" {
+		if _, err := agentcache.ParseTTL(f.CacheTTL); err != nil {
+			return err
+		}
+	}
+	return ValidateConcurrencyClass(f.ConcurrencyClass)
+}
+
 func (f *Formula) validateConvoy() error {
 	if len(f.Legs) == 0 {
 		return fmt.Errorf("convoy formula requires at least one leg")
@@ -92,6 +151,33 @@ func (f *Formula) validateConvoy() error {
 			return fmt.Errorf("duplicate leg id: %s", leg.ID)
 		}
 		seen[leg.ID] = true
+		if leg.Expect != nil && leg.Expect.MinBytes < 0 {
+			return fmt.Errorf("leg %s: expect.min_bytes must be non-negative", leg.ID)
+		}
+		if _, err := NewLegContainer(leg.Runner, leg.Image, leg.Mounts); err != nil {
+			return fmt.Errorf("leg %s: %w", leg.ID, err)
+		}
+		if leg.Consensus != nil {
+			if err := leg.Consensus.Validate(); err != nil {
+				return fmt.Errorf("leg %s: %w", leg.ID, err)
+			}
+		}
+		if leg.Retry != nil {
+			if err := leg.Retry.Validate(); err != nil {
+				return fmt.Errorf("leg %s: %w", leg.ID, err)
+			}
+		}
+	}
+
+	// Validate leg needs form a DAG
+	ids := make([]string, 0, len(f.Legs))
+	needs := make(map[string][]string)
+	for _, leg := range f.Legs {
+		ids = append(ids, leg.ID)
+		needs[leg.ID] = leg.Needs
+	}
+	if _, err := LegOrder(ids, needs); err != nil {
+		return err
 	}
 
 	// Validate synthesis depends_on references valid legs
@@ -101,6 +187,9 @@ func (f *Formula) validateConvoy() error {
 				return fmt.Errorf("synthesis depends_on references unknown leg: %s", dep)
 			}
 		}
+		if err := f.Synthesis.Require.Validate(len(f.Legs)); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -256,11 +345,14 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 			deps[tmpl.ID] = tmpl.Needs
 		}
 	case TypeConvoy:
-		// Convoy legs are parallel; return all leg IDs
+		// Convoy legs are parallel unless they declare needs
 		for _, leg := range f.Legs {
 			items = append(items, leg.ID)
 		}
-		return items, nil
+		deps = make(map[string][]string)
+		for _, leg := range f.Legs {
+			deps[leg.ID] = leg.Needs
+		}
 	case TypeAspect:
 		// Aspect aspects are parallel; return all aspect IDs
 		for _, aspect := range f.Aspects {
@@ -271,17 +363,43 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 		return nil, fmt.Errorf("unsupported formula type for topological sort")
 	}
 
-	// Kahn's algorithm
-	inDegree := make(map[string]int)
-	for _, id := range items {
-		inDegree[id] = 0
+	return sortByNeeds(items, deps)
+}
+
+// LegOrder validates convoy leg needs and returns the leg IDs in dispatch
+// order: every leg after the legs it needs, ties broken by file order.
+func LegOrder(ids []string, needs map[string][]string) ([]string, error) {
+	known := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if known[id] {
+			return nil, fmt.Errorf("duplicate leg id: %s", id)
+		}
+		known[id] = true
 	}
-	for _, id := range items {
-		for _, dep := range deps[id] {
-			inDegree[id]++
-			_ = dep // dep already exists (validated)
+	for _, id := range ids {
+		for _, need := range needs[id] {
+			if need == id {
+				return nil, fmt.Errorf("leg %q needs itself", id)
+			}
+			if !known[need] {
+				return nil, fmt.Errorf("leg %q needs unknown leg: %s", id, need)
+			}
 		}
 	}
+	order, err := sortByNeeds(ids, needs)
+	if err != nil {
+		return nil, fmt.Errorf("cycle detected in leg needs")
+	}
+	return order, nil
+}
+
+// sortByNeeds orders items so each comes after everything it needs, using
+// Kahn's algorithm. Items with no ordering constraint keep their order.
+func sortByNeeds(items []string, deps map[string][]string) ([]string, error) {
+	inDegree := make(map[string]int)
+	for _, id := range items {
+		inDegree[id] = len(deps[id])
+	}
 
 	// Find all nodes with no dependencies
 	var queue []string
@@ -361,9 +479,18 @@ func (f *Formula) ReadySteps(completed map[string]bool) []string {
 			}
 		}
 	case TypeConvoy:
-		// All legs are ready unless already completed
 		for _, leg := range f.Legs {
-			if !completed[leg.ID] {
+			if completed[leg.ID] {
+				continue
+			}
+			allMet := true
+			for _, need := range leg.Needs {
+				if !completed[need] {
+					allMet = false
+					break
+				}
+			}
+			if allMet {
 				ready = append(ready, leg.ID)
 			}
 		}
@@ -459,3 +586,16 @@ func (f *Formula) GetAspect(id string) *Aspect {
 	}
 	return nil
 }
+
+// concurrencyClassPattern is the form of a concurrency class name: it is
+// recorded on a convoy description line, so it may not contain spaces.
+var concurrencyClassPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateConcurrencyClass checks a formula's concurrency_class. Empty
+// means the formula has no class.
+func ValidateConcurrencyClass(class string) error {
+	if class != "" && !concurrencyClassPattern.MatchString(class) {
+		return fmt.Errorf("invalid concurrency_class %q (letters, digits, '.', '_' and '-')", class)
+	}
+	return nil
+}
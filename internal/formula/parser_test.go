@@ -1,6 +1,7 @@
 package formula
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -165,6 +166,27 @@ id = "step1"
 	}
 }
 
+func TestValidate_ConcurrencyClass(t *testing.T) {
+	for class, ok := range map[string]bool{"heavy-build": true, "gpu.v2": true, "heavy build": false, "-x": false} {
+		data := []byte(`
+formula = "test"
+type = "convoy"
+version = 1
+concurrency_class = "` + class + `"
+[[legs]]
+id = "a"
+title = "A"
+`)
+		f, err := Parse(data)
+		if ok && (err != nil || f.ConcurrencyClass != class) {
+			t.Errorf("class %q: got %v, want it accepted", class, err)
+		}
+		if !ok && err == nil {
+			t.Errorf("class %q: expected an error", class)
+		}
+	}
+}
+
 func TestValidate_DuplicateStepID(t *testing.T) {
 	data := []byte(`
 formula = "test"
@@ -353,3 +375,84 @@ title = "Leg 3"
 		t.Errorf("ReadySteps({leg1}) = %v, want 2 legs", ready)
 	}
 }
+
+func TestConvoyLegNeeds(t *testing.T) {
+	data := []byte(`
+formula = "test"
+type = "convoy"
+version = 1
+[[legs]]
+id = "impl"
+title = "Implementation"
+needs = ["api-design"]
+[[legs]]
+id = "api-design"
+title = "API design"
+[[legs]]
+id = "docs"
+title = "Docs"
+needs = ["api-design", "impl"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	order, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	if strings.Join(order, ",") != "api-design,impl,docs" {
+		t.Errorf("TopologicalSort() = %v, want [api-design impl docs]", order)
+	}
+
+	ready := f.ReadySteps(map[string]bool{})
+	if len(ready) != 1 || ready[0] != "api-design" {
+		t.Errorf("ReadySteps({}) = %v, want [api-design]", ready)
+	}
+	ready = f.ReadySteps(map[string]bool{"api-design": true})
+	if len(ready) != 1 || ready[0] != "impl" {
+		t.Errorf("ReadySteps({api-design}) = %v, want [impl]", ready)
+	}
+}
+
+func TestConvoyLegNeedsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		legs string
+		want string
+	}{
+		{"unknown", `[[legs]]
+id = "a"
+needs = ["missing"]`, "needs unknown leg"},
+		{"self", `[[legs]]
+id = "a"
+needs = ["a"]`, "needs itself"},
+		{"cycle", `[[legs]]
+id = "a"
+needs = ["b"]
+[[legs]]
+id = "b"
+needs = ["a"]`, "cycle"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte("formula = \"test\"\ntype = \"convoy\"\n" + tc.legs + "\n"))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestCacheTTLValidation(t *testing.T) {
+	f := &Formula{Name: "x", Type: TypeConvoy, Legs: []Leg{{ID: "a"}}, CacheTTL: "often"}
+	if err := f.Validate(); err == nil {
+		t.Error("Validate() should reject an invalid cache_ttl")
+	}
+	f.CacheTTL = "6h"
+	if err := f.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
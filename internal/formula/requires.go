@@ -0,0 +1,155 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// requirementRE matches a requires entry: a tool name, optionally followed
+// by a comparison against a dotted version ("golangci-lint>=1.55").
+var requirementRE = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_.+-]*)\s*(?:(>=|<=|==|=|>|<)\s*(\d+(?:\.\d+)*))?$`)
+
+// versionRE finds the first dotted version in a tool's --version output.
+var versionRE = regexp.MustCompile(`\d+(?:\.\d+)+`)
+
+// Requirement is an external tool a formula needs on its dispatch target,
+// declared as requires = ["gh", "golangci-lint>=1.55"].
+type Requirement struct {
+	Tool    string
+	Op      string // Comparison operator; empty when any version will do
+	Version string
+}
+
+// ParseRequirement parses a requires entry.
+func ParseRequirement(s string) (Requirement, error) {
+	m := requirementRE.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Requirement{}, fmt.Errorf("invalid requirement %q: want a tool name, optionally with a version like tool>=1.2", s)
+	}
+	op := m[2]
+	if op == "==" {
+		op = "="
+	}
+	return Requirement{Tool: m[1], Op: op, Version: m[3]}, nil
+}
+
+// ParseRequirements parses a formula's requires list.
+func ParseRequirements(entries []string) ([]Requirement, error) {
+	reqs := make([]Requirement, 0, len(entries))
+	for _, entry := range entries {
+		r, err := ParseRequirement(entry)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, r)
+	}
+	return reqs, nil
+}
+
+// ParseRequiresTOML parses the requires list of formula TOML without
+// decoding or validating the rest of the formula.
+func ParseRequiresTOML(data []byte) ([]Requirement, error) {
+	var raw struct {
+		Requires []string `toml:"requires"`
+	}
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, err
+	}
+	return ParseRequirements(raw.Requires)
+}
+
+// String returns the requirement as written in a formula.
+func (r Requirement) String() string {
+	return r.Tool + r.Op + r.Version
+}
+
+// Satisfied reports whether version meets the requirement's constraint.
+func (r Requirement) Satisfied(version string) bool {
+	if r.Op == "" {
+		return true
+	}
+	c := compareVersions(version, r.Version)
+	switch r.Op {
+	case ">=":
+		return c >= 0
+	case ">":
+		return c > 0
+	case "<=":
+		return c <= 0
+	case "<":
+		return c < 0
+	default:
+		return c == 0
+	}
+}
+
+// compareVersions compares dotted numeric versions; missing components
+// count as zero, so 1.55 equals 1.55.0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// CommandRunner runs commands on the machine requirements are checked on;
+// connection.Connection satisfies it for local hosts and remote rigs.
+type CommandRunner interface {
+	Exec(cmd string, args ...string) ([]byte, error)
+}
+
+// CheckRequirements verifies each requirement against the tools installed
+// where runner executes. It returns one error per unmet requirement, saying
+// what is missing or which version was found.
+func CheckRequirements(runner CommandRunner, reqs []Requirement) []error {
+	var problems []error
+	for _, r := range reqs {
+		if err := checkRequirement(runner, r); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// checkRequirement verifies a single requirement.
+func checkRequirement(runner CommandRunner, r Requirement) error {
+	// Tool names are restricted by requirementRE, so they are safe to
+	// embed in the shell line.
+	if _, err := runner.Exec("sh", "-c", "command -v "+r.Tool); err != nil {
+		return fmt.Errorf("%s: not found on PATH", r.Tool)
+	}
+	if r.Op == "" {
+		return nil
+	}
+	version := ""
+	for _, arg := range []string{"--version", "version"} {
+		out, _ := runner.Exec(r.Tool, arg)
+		if version = versionRE.FindString(string(out)); version != "" {
+			break
+		}
+	}
+	if version == "" {
+		return fmt.Errorf("%s: could not determine version (need %s%s)", r.Tool, r.Op, r.Version)
+	}
+	if !r.Satisfied(version) {
+		return fmt.Errorf("%s: version %s found, need %s%s", r.Tool, version, r.Op, r.Version)
+	}
+	return nil
+}
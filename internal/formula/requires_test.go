@@ -0,0 +1,115 @@
+package formula
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseRequirement(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Requirement
+	}{
+		{"gh", Requirement{Tool: "gh"}},
+		{"golangci-lint>=1.55", Requirement{Tool: "golangci-lint", Op: ">=", Version: "1.55"}},
+		{"docker >= 24", Requirement{Tool: "docker", Op: ">=", Version: "24"}},
+		{"node==20.1.0", Requirement{Tool: "node", Op: "=", Version: "20.1.0"}},
+		{"python3<4", Requirement{Tool: "python3", Op: "<", Version: "4"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRequirement(tt.in)
+		if err != nil {
+			t.Errorf("ParseRequirement(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRequirement(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "gh>=", "rm -rf /", "tool>=1.x", "$(id)"} {
+		if _, err := ParseRequirement(bad); err == nil {
+			t.Errorf("ParseRequirement(%q) accepted an invalid entry", bad)
+		}
+	}
+}
+
+func TestRequirementSatisfied(t *testing.T) {
+	tests := []struct {
+		req, version string
+		want         bool
+	}{
+		{"lint>=1.55", "1.55.2", true},
+		{"lint>=1.55", "1.55", true},
+		{"lint>=1.55", "1.9", false},
+		{"lint>=1.55", "2.0.0", true},
+		{"lint>1.55", "1.55.0", false},
+		{"lint<2", "1.99", true},
+		{"lint=1.2", "1.2.0", true},
+		{"lint", "0.1", true},
+	}
+	for _, tt := range tests {
+		r, err := ParseRequirement(tt.req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Satisfied(tt.version); got != tt.want {
+			t.Errorf("%s satisfied by %s = %v, want %v", tt.req, tt.version, got, tt.want)
+		}
+	}
+}
+
+// fakeRunner answers commands from a table keyed by the joined command line.
+type fakeRunner map[string]string
+
+func (f fakeRunner) Exec(cmd string, args ...string) ([]byte, error) {
+	out, ok := f[strings.Join(append([]string{cmd}, args...), " ")]
+	if !ok {
+		return nil, errors.New("exit status 127")
+	}
+	return []byte(out), nil
+}
+
+func TestCheckRequirements(t *testing.T) {
+	runner := fakeRunner{
+		"sh -c command -v gh":            "/usr/bin/gh",
+		"sh -c command -v golangci-lint": "/usr/bin/golangci-lint",
+		"golangci-lint --version":        "golangci-lint has version 1.54.2 built with go1.21",
+		"sh -c command -v go":            "/usr/bin/go",
+		"go version":                     "go version go1.22.1 linux/amd64",
+	}
+	reqs, err := ParseRequirements([]string{"gh", "golangci-lint>=1.55", "docker", "go>=1.21"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems := CheckRequirements(runner, reqs)
+	if len(problems) != 2 {
+		t.Fatalf("CheckRequirements() = %v, want 2 problems", problems)
+	}
+	if msg := problems[0].Error(); !strings.Contains(msg, "golangci-lint") || !strings.Contains(msg, "1.54.2") || !strings.Contains(msg, ">=1.55") {
+		t.Errorf("version problem = %q", msg)
+	}
+	if msg := problems[1].Error(); !strings.Contains(msg, "docker") || !strings.Contains(msg, "not found") {
+		t.Errorf("missing tool problem = %q", msg)
+	}
+}
+
+func TestValidate_Requires(t *testing.T) {
+	f := &Formula{Name: "lint", Type: TypeConvoy, Legs: []Leg{{ID: "a"}}, Requires: []string{"gh", "bad tool"}}
+	if err := f.Validate(); err == nil || !strings.Contains(err.Error(), "requires") {
+		t.Errorf("Validate() = %v, want requires error", err)
+	}
+}
+
+func TestParseRequiresTOML(t *testing.T) {
+	data := []byte("formula = \"lint\"\nrequires = [\"gh\", \"golangci-lint>=1.55\"]\n\n[[legs]]\nid = \"a\"\n")
+	reqs, err := ParseRequiresTOML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 2 || reqs[1].Tool != "golangci-lint" || reqs[1].Version != "1.55" {
+		t.Errorf("ParseRequiresTOML() = %+v", reqs)
+	}
+}
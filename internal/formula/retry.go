@@ -0,0 +1,83 @@
+package formula
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Kinds of leg failure, which a retry policy names in its on list.
+const (
+	FailureTimeout     = "timeout"      // The agent ran past its time limit
+	FailureEmptyOutput = "empty_output" // An output is missing, empty, or below expect.min_bytes
+	FailureContract    = "contract"     // Outputs exist but break the rest of the expect contract
+	FailureError       = "error"        // The agent failed to run
+)
+
+// FailureKinds are the failure kinds, in documentation order.
+var FailureKinds = []string{FailureTimeout, FailureEmptyOutput, FailureContract, FailureError}
+
+// MaxLegRetries caps retry.max, so a broken leg can't run indefinitely.
+const MaxLegRetries = 10
+
+// LegRetry is a leg's retry policy: a leg that fails in one of the listed
+// ways runs again, up to Max more times, after waiting Backoff. Declared
+// in a formula as:
+//
+//	[[legs]]
+//	id = "security"
+//	retry = { max = 2, backoff = "30s", on = ["timeout", "empty_output"] }
+//
+// An empty on list retries every kind of failure.
+type LegRetry struct {
+	Max     int      `toml:"max" json:"max"`
+	Backoff string   `toml:"backoff" json:"backoff,omitempty"`
+	On      []string `toml:"on" json:"on,omitempty"`
+}
+
+// Validate checks the retry count, backoff, and failure kinds.
+func (r *LegRetry) Validate() error {
+	if r.Max < 1 || r.Max > MaxLegRetries {
+		return fmt.Errorf(`retry.max must be between 1 and %d: retry = { max = 2, backoff = "30s" }`, MaxLegRetries)
+	}
+	if r.Backoff != "" {
+		d, err := time.ParseDuration(r.Backoff)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid retry.backoff %q (e.g. \"30s\" or \"2m\")", r.Backoff)
+		}
+	}
+	for _, kind := range r.On {
+		if !slices.Contains(FailureKinds, kind) {
+			return fmt.Errorf("invalid retry.on %q (must be %s)", kind, strings.Join(FailureKinds, ", "))
+		}
+	}
+	return nil
+}
+
+// BackoffDuration returns how long to wait before each retry.
+func (r *LegRetry) BackoffDuration() time.Duration {
+	d, _ := time.ParseDuration(r.Backoff)
+	return d
+}
+
+// Allows reports whether a leg that failed with kind after retries
+// earlier retries may run again.
+func (r *LegRetry) Allows(kind string, retries int) bool {
+	if r == nil || retries >= r.Max {
+		return false
+	}
+	return len(r.On) == 0 || slices.Contains(r.On, kind)
+}
+
+// ViolationsKind returns the failure kind of an expect contract's
+// violations: empty_output if any output is missing or too short,
+// contract otherwise.
+func ViolationsKind(violations []string) string {
+	for _, v := range violations {
+		if strings.HasSuffix(v, ": missing") || strings.Contains(v, " bytes, expected at least ") {
+			return FailureEmptyOutput
+		}
+	}
+	return FailureContract
+}
@@ -0,0 +1,79 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_LegRetry(t *testing.T) {
+	data := []byte(`
+formula = "review"
+type = "convoy"
+
+[[legs]]
+id = "security"
+retry = { max = 2, backoff = "30s", on = ["timeout", "empty_output"] }
+`)
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	r := f.Legs[0].Retry
+	if r == nil || r.Max != 2 || r.BackoffDuration() != 30*time.Second {
+		t.Fatalf("retry = %+v", r)
+	}
+	if !r.Allows(FailureTimeout, 0) || !r.Allows(FailureEmptyOutput, 1) {
+		t.Error("retry should allow listed failures within max")
+	}
+	if r.Allows(FailureTimeout, 2) {
+		t.Error("retry should stop after max retries")
+	}
+	if r.Allows(FailureContract, 0) {
+		t.Error("retry should not allow unlisted failures")
+	}
+	if (&LegRetry{Max: 1}).Allows(FailureError, 0) != true {
+		t.Error("an empty on list should retry every failure")
+	}
+	var none *LegRetry
+	if none.Allows(FailureTimeout, 0) {
+		t.Error("a leg without a policy should never retry")
+	}
+}
+
+func TestLegRetryValidate(t *testing.T) {
+	tests := []struct {
+		retry LegRetry
+		want  string
+	}{
+		{LegRetry{Max: 0}, "retry.max"},
+		{LegRetry{Max: MaxLegRetries + 1}, "retry.max"},
+		{LegRetry{Max: 1, Backoff: "soon"}, "retry.backoff"},
+		{LegRetry{Max: 1, On: []string{"flaky"}}, "retry.on"},
+	}
+	for _, tt := range tests {
+		err := tt.retry.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%+v) = %v, want error about %s", tt.retry, err, tt.want)
+		}
+	}
+	if err := (&LegRetry{Max: 3, Backoff: "1m", On: FailureKinds}).Validate(); err != nil {
+		t.Errorf("Validate(valid) = %v", err)
+	}
+}
+
+func TestViolationsKind(t *testing.T) {
+	dir := t.TempDir()
+	if got := ViolationsKind((&LegExpect{Files: []string{"out.md"}}).Check(dir)); got != FailureEmptyOutput {
+		t.Errorf("Check of a missing file kind = %s", got)
+	}
+	if got := ViolationsKind([]string{"out.json: not valid JSON: eof", "out.md: missing"}); got != FailureEmptyOutput {
+		t.Errorf("missing file kind = %s", got)
+	}
+	if got := ViolationsKind([]string{"out.md: 3 bytes, expected at least 200"}); got != FailureEmptyOutput {
+		t.Errorf("short file kind = %s", got)
+	}
+	if got := ViolationsKind([]string{"out.json: $.findings: required"}); got != FailureContract {
+		t.Errorf("schema violation kind = %s", got)
+	}
+}
@@ -0,0 +1,113 @@
+package formula
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SchemaVersion is the newest formula file format this gt understands.
+// Formulas declare theirs with a top-level "schema = N" key; files without
+// one are schema 1.
+//
+// Bump it when the format changes incompatibly, and add an adapter to
+// schemaAdapters that rewrites the previous schema into the new one, so
+// town overrides written for older gt releases keep working.
+const SchemaVersion = 2
+
+// schemaAdapters upgrade decoded formula TOML one schema at a time:
+// schemaAdapters[i] rewrites schema i+1 into schema i+2.
+var schemaAdapters = []func(raw map[string]any){
+	adaptSchema1,
+}
+
+// SchemaError reports a formula written for a newer gt than this one.
+type SchemaError struct {
+	Schema int
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("formula uses schema %d, but this gt only understands schemas up to %d; upgrade gt to use it", e.Schema, SchemaVersion)
+}
+
+// CheckSchema validates a declared schema version, where 0 means the file
+// declares none (schema 1). It returns a *SchemaError for schemas newer
+// than SchemaVersion, and reports whether the file is on an older schema
+// that is still read through adapters.
+func CheckSchema(declared int) (outdated bool, err error) {
+	if declared == 0 {
+		declared = 1
+	}
+	switch {
+	case declared < 0:
+		return false, fmt.Errorf("invalid schema %d", declared)
+	case declared > SchemaVersion:
+		return false, &SchemaError{Schema: declared}
+	}
+	return declared < SchemaVersion, nil
+}
+
+// DeclaredSchemaTOML returns the schema a formula file declares, 1 if it
+// declares none.
+func DeclaredSchemaTOML(data []byte) (int, error) {
+	var raw struct {
+		Schema int `toml:"schema"`
+	}
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return 0, err
+	}
+	if raw.Schema == 0 {
+		return 1, nil
+	}
+	return raw.Schema, nil
+}
+
+// upgradeSchema returns formula TOML rewritten to SchemaVersion and the
+// schema the file declared. Current-schema files are returned unchanged.
+func upgradeSchema(data []byte) ([]byte, int, error) {
+	var raw map[string]any
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, 0, fmt.Errorf("parsing TOML: %w", err)
+	}
+	schema := 1
+	if v, ok := raw["schema"]; ok {
+		n, ok := v.(int64)
+		if !ok {
+			return nil, 0, fmt.Errorf("schema must be an integer, got %v", v)
+		}
+		schema = int(n)
+	}
+	outdated, err := CheckSchema(schema)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !outdated {
+		return data, schema, nil
+	}
+
+	for v := schema; v < SchemaVersion; v++ {
+		schemaAdapters[v-1](raw)
+	}
+	raw["schema"] = int64(SchemaVersion)
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return nil, 0, fmt.Errorf("upgrading schema %d formula: %w", schema, err)
+	}
+	return buf.Bytes(), schema, nil
+}
+
+// adaptSchema1 upgrades schema 1, which allowed a variable to be declared
+// by its default value alone ([vars] name = "value"); schema 2 requires
+// a table ([vars.name] default = "value").
+func adaptSchema1(raw map[string]any) {
+	vars, ok := raw["vars"].(map[string]any)
+	if !ok {
+		return
+	}
+	for name, v := range vars {
+		if s, ok := v.(string); ok {
+			vars[name] = map[string]any{"default": s}
+		}
+	}
+}
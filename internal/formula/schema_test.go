@@ -0,0 +1,91 @@
+package formula
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSchema1Adapter(t *testing.T) {
+	data := []byte(`formula = "patrol"
+version = 3
+
+[vars]
+wisp_type = "patrol"
+
+[vars.rig]
+description = "Rig to patrol"
+required = true
+
+[[steps]]
+id = "a"
+title = "A"
+`)
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if f.Schema != 1 {
+		t.Errorf("Schema = %d, want 1", f.Schema)
+	}
+	if f.Version != 3 {
+		t.Errorf("Version = %d, want 3", f.Version)
+	}
+	if got := f.Vars["wisp_type"].Default; got != "patrol" {
+		t.Errorf("wisp_type default = %q, want patrol", got)
+	}
+	if !f.Vars["rig"].Required {
+		t.Error("table vars should be kept")
+	}
+
+	// Schema 2 no longer accepts the shorthand.
+	if _, err := Parse(append([]byte("schema = 2\n"), data...)); err == nil {
+		t.Error("expected schema 2 to reject [vars] shorthand")
+	}
+}
+
+func TestParseNewerSchema(t *testing.T) {
+	_, err := Parse([]byte("formula = \"x\"\nschema = 99\n\n[[steps]]\nid = \"a\"\n"))
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || schemaErr.Schema != 99 {
+		t.Fatalf("Parse error = %v, want *SchemaError for 99", err)
+	}
+	if !strings.Contains(err.Error(), "upgrade gt") {
+		t.Errorf("error should tell the user to upgrade gt: %v", err)
+	}
+
+	if _, err := Parse([]byte("formula = \"x\"\nschema = \"two\"\n")); err == nil {
+		t.Error("expected error for non-integer schema")
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		declared int
+		outdated bool
+		wantErr  bool
+	}{
+		{0, true, false},
+		{1, true, false},
+		{SchemaVersion, false, false},
+		{SchemaVersion + 1, false, true},
+		{-1, false, true},
+	}
+	for _, tt := range tests {
+		outdated, err := CheckSchema(tt.declared)
+		if outdated != tt.outdated || (err != nil) != tt.wantErr {
+			t.Errorf("CheckSchema(%d) = %v, %v; want %v, err=%v", tt.declared, outdated, err, tt.outdated, tt.wantErr)
+		}
+	}
+}
+
+func TestParseMetadataSchema(t *testing.T) {
+	m, err := ParseMetadata([]byte("formula = \"x\"\n"))
+	if err != nil || m.Schema != 1 {
+		t.Errorf("ParseMetadata without schema = %+v, %v; want schema 1", m, err)
+	}
+	m, err = ParseMetadata([]byte("formula = \"x\"\nschema = 7\n"))
+	if err != nil || m.Schema != 7 {
+		t.Errorf("ParseMetadata schema 7 = %+v, %v", m, err)
+	}
+}
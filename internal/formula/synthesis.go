@@ -0,0 +1,70 @@
+package formula
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SynthesisRequire declares how many legs must complete before synthesis
+// may run: "all" (the default), "majority", or a leg count.
+type SynthesisRequire string
+
+const (
+	// RequireAll waits for every leg.
+	RequireAll SynthesisRequire = "all"
+	// RequireMajority waits for more than half of the legs.
+	RequireMajority SynthesisRequire = "majority"
+)
+
+// UnmarshalTOML accepts both require = "majority" and require = 3.
+func (r *SynthesisRequire) UnmarshalTOML(v interface{}) error {
+	switch val := v.(type) {
+	case string:
+		*r = SynthesisRequire(val)
+	case int64:
+		*r = SynthesisRequire(strconv.FormatInt(val, 10))
+	default:
+		return fmt.Errorf("synthesis.require must be \"all\", \"majority\", or a number, got %v", v)
+	}
+	return nil
+}
+
+// Validate checks the policy against the formula's leg count.
+func (r SynthesisRequire) Validate(legs int) error {
+	switch r {
+	case "", RequireAll, RequireMajority:
+		return nil
+	}
+	n, err := strconv.Atoi(string(r))
+	if err != nil {
+		return fmt.Errorf("invalid synthesis.require %q (must be all, majority, or a number)", r)
+	}
+	if n < 1 || n > legs {
+		return fmt.Errorf("synthesis.require = %d must be between 1 and the number of legs (%d)", n, legs)
+	}
+	return nil
+}
+
+// IsPartial reports whether synthesis may run before every leg completes.
+func (r SynthesisRequire) IsPartial() bool {
+	return r != "" && r != RequireAll
+}
+
+// Threshold returns how many of total legs must complete. Invalid values
+// fall back to all.
+func (r SynthesisRequire) Threshold(total int) int {
+	switch r {
+	case "", RequireAll:
+		return total
+	case RequireMajority:
+		return total/2 + 1
+	}
+	n, err := strconv.Atoi(string(r))
+	if err != nil || n > total {
+		return total
+	}
+	if n < 1 {
+		return 1
+	}
+	return n
+}
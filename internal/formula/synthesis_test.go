@@ -0,0 +1,73 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSynthesisRequireThreshold(t *testing.T) {
+	tests := []struct {
+		require SynthesisRequire
+		total   int
+		want    int
+	}{
+		{"", 5, 5},
+		{RequireAll, 5, 5},
+		{RequireMajority, 5, 3},
+		{RequireMajority, 4, 3},
+		{RequireMajority, 1, 1},
+		{"2", 5, 2},
+		{"9", 5, 5},
+		{"bogus", 5, 5},
+	}
+	for _, tt := range tests {
+		if got := tt.require.Threshold(tt.total); got != tt.want {
+			t.Errorf("%q.Threshold(%d) = %d, want %d", tt.require, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestParse_SynthesisRequire(t *testing.T) {
+	base := `
+formula = "review"
+type = "convoy"
+
+[[legs]]
+id = "a"
+
+[[legs]]
+id = "b"
+
+[[legs]]
+id = "c"
+
+[synthesis]
+title = "Report"
+`
+	tests := []struct {
+		line    string
+		want    SynthesisRequire
+		wantErr string
+	}{
+		{`require = "majority"`, RequireMajority, ""},
+		{`require = 2`, "2", ""},
+		{`require = 4`, "", "between 1 and the number of legs"},
+		{`require = "most"`, "", "invalid synthesis.require"},
+		{``, "", ""},
+	}
+	for _, tt := range tests {
+		f, err := Parse([]byte(base + tt.line + "\n"))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tt.line, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Parse failed: %v", tt.line, err)
+		}
+		if f.Synthesis.Require != tt.want {
+			t.Errorf("%s: Require = %q, want %q", tt.line, f.Synthesis.Require, tt.want)
+		}
+	}
+}
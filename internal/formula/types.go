@@ -29,6 +29,21 @@ type Formula struct {
 	Description string      `toml:"description"`
 	Type        FormulaType `toml:"type"`
 	Version     int         `toml:"version"`
+	Schema      int         `toml:"schema"`   // File format version as declared (1 if absent); see SchemaVersion
+	Category    string      `toml:"category"` // Catalog grouping (default: type)
+	Tags        []string    `toml:"tags"`
+
+	// Env is injected into every polecat the formula dispatches, over the
+	// town/rig settings env. Values may be secret references.
+	Env map[string]string `toml:"env"`
+
+	// Agent runs the formula's legs instead of the rig's default agent.
+	Agent string `toml:"agent"`
+
+	// Requires lists external tools the formula needs on its dispatch
+	// target, optionally with a version ("golangci-lint>=1.55"). They are
+	// checked before any beads are created.
+	Requires []string `toml:"requires"`
 
 	// Convoy-specific
 	Inputs    map[string]Input `toml:"inputs"`
@@ -37,6 +52,28 @@ type Formula struct {
 	Legs      []Leg             `toml:"legs"`
 	Synthesis *Synthesis        `toml:"synthesis"`
 
+	// Context trimming for large inputs (convoy)
+	ContextStrategy ContextStrategy `toml:"context_strategy"`
+	ContextLimit    int             `toml:"context_limit"` // Token budget for prompt plus context (0 = model default)
+	ContextAgent    string          `toml:"context_agent"` // Agent for summarize_diff (default: rig agent)
+
+	// CacheTTL opts the formula into agent reply caching for --local-agent
+	// runs: identical prompts reuse replies younger than this ("6h", "7d").
+	CacheTTL string `toml:"cache_ttl"`
+
+	// ConcurrencyClass names a town-wide class ("heavy-build") whose open
+	// convoys are capped by the town's concurrency settings.
+	ConcurrencyClass string `toml:"concurrency_class"`
+
+	// Approval holds convoy runs until gt convoy approve releases their
+	// legs (rig settings can require it for every formula).
+	Approval bool `toml:"approval"`
+
+	// UntrustedMaxLength and UntrustedDelimiters set how PR titles and
+	// changed file names are placed in prompts (see UntrustedText).
+	UntrustedMaxLength  int  `toml:"untrusted_max_length"`
+	UntrustedDelimiters bool `toml:"untrusted_delimiters"`
+
 	// Workflow-specific
 	Steps []Step           `toml:"steps"`
 	Vars  map[string]Var   `toml:"vars"`
@@ -78,6 +115,31 @@ type Leg struct {
 	Title       string `toml:"title"`
 	Focus       string `toml:"focus"`
 	Description string `toml:"description"`
+	Workdir     string `toml:"workdir"` // Rig-relative worktree path template for this leg
+	Branch      string `toml:"branch"`  // Branch template checked out in the leg's worktree
+
+	// Needs lists legs that must finish before this one is dispatched. Their
+	// outputs are available to its prompt as .upstream.<leg-id>.
+	Needs []string `toml:"needs"`
+
+	// Expect is the leg's output contract, checked when its polecat runs gt done.
+	Expect *LegExpect `toml:"expect"`
+
+	// Env is injected into this leg's polecat, over the formula's env.
+	Env map[string]string `toml:"env"`
+
+	// Runner is "host" (default) or "docker"; docker runs the leg's agent
+	// in Image with the polecat's worktree and Mounts bound (see LegContainer).
+	Runner string   `toml:"runner"`
+	Image  string   `toml:"image"`
+	Mounts []string `toml:"mounts"`
+
+	// Consensus runs the leg's prompt through several agents and merges
+	// their findings by agreement.
+	Consensus *Consensus `toml:"consensus"`
+
+	// Retry runs the leg again when it fails in a listed way.
+	Retry *LegRetry `toml:"retry"`
 }
 
 // Synthesis represents the synthesis step that combines leg outputs.
@@ -85,6 +147,9 @@ type Synthesis struct {
 	Title       string   `toml:"title"`
 	Description string   `toml:"description"`
 	DependsOn   []string `toml:"depends_on"`
+
+	// Require is how many legs must complete before synthesis runs.
+	Require SynthesisRequire `toml:"require"`
 }
 
 // Step represents a sequential step in a workflow formula.
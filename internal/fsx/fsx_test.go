@@ -0,0 +1,109 @@
+package fsx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/readonly"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+
+	if err := WriteFile(path, []byte("first"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("second"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != "second" {
+		t.Errorf("content = %q, %v", got, err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm()&0644 != 0644 {
+		t.Errorf("mode = %v", info.Mode())
+	}
+	assertNoTemps(t, dir)
+}
+
+func TestWriteStreamFailureKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.tgz")
+	if err := WriteFile(path, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteStream(path, 0644, func(w io.Writer) error {
+		_, _ = w.Write([]byte("partial"))
+		return fmt.Errorf("interrupted")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got, _ := os.ReadFile(path); string(got) != "original" {
+		t.Errorf("content = %q, want original", got)
+	}
+	assertNoTemps(t, dir)
+}
+
+func TestLockReadOnlyCreatesNothing(t *testing.T) {
+	t.Setenv(readonly.EnvVar, "1")
+	dir := filepath.Join(t.TempDir(), "state")
+
+	if _, err := Lock(filepath.Join(dir, "queue.json")); err == nil {
+		t.Fatal("expected Lock to fail in read-only mode")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Lock created %s in read-only mode", dir)
+	}
+}
+
+func TestWithLockSerializesReadModifyWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "counter")
+	if err := WriteFile(path, []byte("0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := WithLock(path, func() error {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				n, _ := strconv.Atoi(string(data))
+				return WriteFile(path, []byte(strconv.Itoa(n+1)), 0644)
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, _ := os.ReadFile(path); string(got) != strconv.Itoa(workers) {
+		t.Errorf("counter = %s, want %d", got, workers)
+	}
+}
+
+func assertNoTemps(t *testing.T, dir string) {
+	t.Helper()
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
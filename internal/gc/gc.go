@@ -0,0 +1,330 @@
+// Package gc finds and removes runtime debris that long-lived towns
+// accumulate: expired caches, orphaned worktrees, dead session records,
+// old review outputs, and closed wisps.
+package gc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/logs"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// Kinds of debris.
+const (
+	KindCache    = "cache"
+	KindWorktree = "worktree"
+	KindSession  = "session"
+	KindReview   = "review"
+	KindWisp     = "wisp"
+)
+
+// Item is one piece of debris. File and directory items are removed by
+// path; wisps are deleted by ID from the beads database at Path.
+type Item struct {
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	ID     string `json:"id,omitempty"`
+	Bytes  int64  `json:"bytes"`
+	Reason string `json:"reason"`
+}
+
+// Collector finds and removes debris in a town.
+type Collector struct {
+	townRoot string
+	rigs     []string
+	policy   *config.GCConfig
+	now      time.Time
+
+	// Warnings collects problems that skipped part of a scan.
+	Warnings []string
+}
+
+// New returns a collector for the town at townRoot and its rigs, keeping
+// debris younger than policy's retention periods (nil uses the defaults).
+func New(townRoot string, rigs []string, policy *config.GCConfig) *Collector {
+	return &Collector{townRoot: townRoot, rigs: rigs, policy: policy, now: time.Now()}
+}
+
+// Scan returns the town's debris, without removing anything.
+func (c *Collector) Scan() []Item {
+	c.Warnings = nil
+	var items []Item
+	items = append(items, c.scanCaches()...)
+	items = append(items, c.scanWorktrees()...)
+	items = append(items, c.scanSessions()...)
+	items = append(items, c.scanReviews()...)
+	items = append(items, c.scanWisps()...)
+	return items
+}
+
+// Remove removes items and returns how many bytes were reclaimed.
+// Failures are joined into the returned error; the rest is still removed.
+func (c *Collector) Remove(items []Item) (int64, error) {
+	var reclaimed int64
+	var errs []error
+	wisps := make(map[string][]string) // beads dir -> IDs
+	for _, it := range items {
+		if it.Kind == KindWisp {
+			wisps[it.Path] = append(wisps[it.Path], it.ID)
+			continue
+		}
+		if err := os.RemoveAll(it.Path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reclaimed += it.Bytes
+	}
+	for dir, ids := range wisps {
+		if err := beads.New(dir).Delete(ids...); err != nil {
+			errs = append(errs, fmt.Errorf("deleting wisps in %s: %w", dir, err))
+		}
+	}
+	return reclaimed, errors.Join(errs...)
+}
+
+// Total returns the bytes held by items.
+func Total(items []Item) int64 {
+	var n int64
+	for _, it := range items {
+		n += it.Bytes
+	}
+	return n
+}
+
+// scanCaches finds files under .runtime/cache/ of the town and its rigs
+// that were not written within the cache retention.
+func (c *Collector) scanCaches() []Item {
+	var items []Item
+	cutoff := c.now.Add(-c.policy.CacheRetention())
+	for _, root := range c.roots() {
+		dir := filepath.Join(root, constants.DirRuntime, "cache")
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			if latestModTime(path).Before(cutoff) {
+				items = append(items, Item{Kind: KindCache, Path: path, Bytes: diskUsage(path), Reason: "not written in " + formatAge(c.policy.CacheRetention())})
+			}
+		}
+	}
+	return items
+}
+
+// scanWorktrees finds worktree metadata whose checkout is gone, and
+// polecat or pooled checkouts whose metadata is gone.
+func (c *Collector) scanWorktrees() []Item {
+	var items []Item
+	for _, rig := range c.rigs {
+		rigPath := filepath.Join(c.townRoot, rig)
+		for _, gitDir := range []string{filepath.Join(rigPath, ".repo.git"), filepath.Join(rigPath, "mayor", "rig", ".git")} {
+			admin, _ := filepath.Glob(filepath.Join(gitDir, "worktrees", "*"))
+			for _, dir := range admin {
+				if _, err := os.Stat(filepath.Join(dir, "locked")); err == nil {
+					continue
+				}
+				data, err := os.ReadFile(filepath.Join(dir, "gitdir")) //nolint:gosec // G304: git's own metadata
+				if err != nil {
+					continue
+				}
+				if _, err := os.Stat(strings.TrimSpace(string(data))); os.IsNotExist(err) {
+					items = append(items, Item{Kind: KindWorktree, Path: dir, Bytes: diskUsage(dir), Reason: "checkout was removed"})
+				}
+			}
+		}
+
+		var checkouts []string
+		for _, pattern := range []string{"polecats/*", "polecats/*/*", ".runtime/worktree-pool/wt-*"} {
+			matches, _ := filepath.Glob(filepath.Join(rigPath, pattern))
+			checkouts = append(checkouts, matches...)
+		}
+		for _, dir := range checkouts {
+			if orphanedCheckout(dir) {
+				items = append(items, Item{Kind: KindWorktree, Path: dir, Bytes: diskUsage(dir), Reason: "not a registered worktree"})
+			}
+		}
+	}
+	return items
+}
+
+// orphanedCheckout reports whether dir is a worktree checkout whose
+// metadata in the shared repo no longer exists.
+func orphanedCheckout(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, ".git")) //nolint:gosec // G304: worktree .git file
+	if err != nil {
+		return false // not a worktree (or a full clone)
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return false
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	_, err = os.Stat(gitDir)
+	return os.IsNotExist(err)
+}
+
+// scanSessions finds state files of process-backend sessions that have
+// exited, and their logs and convoy leg logs once past the session log
+// retention.
+func (c *Collector) scanSessions() []Item {
+	cutoff := c.now.Add(-c.policy.SessionLogRetention())
+	items := c.scanLegLogs(cutoff)
+	dir := filepath.Join(c.townRoot, constants.DirRuntime, "sessions")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return items
+	}
+	backend := session.NewProcessBackend(c.townRoot)
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		name := strings.TrimSuffix(e.Name(), ext)
+		if running, _ := backend.HasSession(name); running {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		switch ext {
+		case ".pid", ".logpath":
+			items = append(items, Item{Kind: KindSession, Path: path, Bytes: diskUsage(path), Reason: "session " + name + " has exited"})
+		case ".log":
+			if latestModTime(path).Before(cutoff) {
+				items = append(items, Item{Kind: KindSession, Path: path, Bytes: diskUsage(path), Reason: "log of ended session older than " + formatAge(c.policy.SessionLogRetention())})
+			}
+		}
+	}
+	return items
+}
+
+// scanLegLogs finds convoy leg log directories (.runtime/convoys/<id>)
+// not written since cutoff.
+func (c *Collector) scanLegLogs(cutoff time.Time) []Item {
+	dirs, _ := filepath.Glob(logs.ConvoyDir(c.townRoot, "*"))
+	var items []Item
+	for _, path := range dirs {
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		if latestModTime(path).Before(cutoff) {
+			items = append(items, Item{Kind: KindSession, Path: path, Bytes: diskUsage(path), Reason: "convoy leg logs older than " + formatAge(c.policy.SessionLogRetention())})
+		}
+	}
+	return items
+}
+
+// scanReviews finds review output directories (.reviews/<id>) in the
+// town, the rigs, and their clones not modified within the review
+// retention.
+func (c *Collector) scanReviews() []Item {
+	dirs := []string{c.townRoot}
+	for _, rig := range c.rigs {
+		rigPath := filepath.Join(c.townRoot, rig)
+		dirs = append(dirs, rigPath, filepath.Join(rigPath, "mayor", "rig"), filepath.Join(rigPath, "refinery", "rig"))
+		crew, _ := filepath.Glob(filepath.Join(rigPath, "crew", "*"))
+		dirs = append(dirs, crew...)
+	}
+	cutoff := c.now.Add(-c.policy.ReviewRetention())
+	var items []Item
+	for _, dir := range dirs {
+		reviews, _ := filepath.Glob(filepath.Join(dir, ".reviews", "*"))
+		for _, path := range reviews {
+			if info, err := os.Stat(path); err != nil || !info.IsDir() {
+				continue
+			}
+			if latestModTime(path).Before(cutoff) {
+				items = append(items, Item{Kind: KindReview, Path: path, Bytes: diskUsage(path), Reason: "unchanged for " + formatAge(c.policy.ReviewRetention())})
+			}
+		}
+	}
+	return items
+}
+
+// scanWisps finds wisps in the town's and rigs' beads that closed before
+// the wisp retention.
+func (c *Collector) scanWisps() []Item {
+	cutoff := c.now.Add(-c.policy.WispRetention())
+	var items []Item
+	for _, root := range c.roots() {
+		if _, err := os.Stat(filepath.Join(root, ".beads")); err != nil {
+			continue
+		}
+		issues, err := beads.New(root).List(beads.ListOptions{Status: "closed", Priority: -1})
+		if err != nil {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("listing wisps in %s: %v", root, err))
+			continue
+		}
+		for _, issue := range issues {
+			if !issue.Ephemeral {
+				continue
+			}
+			closed, err := time.Parse(time.RFC3339, issue.ClosedAt)
+			if err != nil || closed.After(cutoff) {
+				continue
+			}
+			items = append(items, Item{Kind: KindWisp, Path: root, ID: issue.ID, Reason: "closed " + closed.Format("2006-01-02")})
+		}
+	}
+	return items
+}
+
+// roots returns the town root followed by the rig directories.
+func (c *Collector) roots() []string {
+	roots := []string{c.townRoot}
+	rigs := append([]string(nil), c.rigs...)
+	sort.Strings(rigs)
+	for _, rig := range rigs {
+		roots = append(roots, filepath.Join(c.townRoot, rig))
+	}
+	return roots
+}
+
+// diskUsage returns the bytes held by the files under path.
+func diskUsage(path string) int64 {
+	var n int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			n += info.Size()
+		}
+		return nil
+	})
+	return n
+}
+
+// latestModTime returns the newest modification time of the files under
+// path. Directory times are ignored: creating a directory is not a write.
+func latestModTime(path string) time.Time {
+	var latest time.Time
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest
+}
+
+// formatAge formats a retention period in days or hours.
+func formatAge(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return fmt.Sprintf("%dh", d/time.Hour)
+}
@@ -0,0 +1,123 @@
+package gc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// deadPID returns the pid of a process that has exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot run true: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestScanAndRemove(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+	old := 90 * 24 * time.Hour
+
+	// Caches: one expired, one fresh.
+	writeFile(t, filepath.Join(town, ".runtime", "cache", "formulas.json"), "{}", old)
+	writeFile(t, filepath.Join(rig, ".runtime", "cache", "fresh.json"), "{}", 0)
+
+	// Worktrees: metadata without a checkout, a checkout without metadata,
+	// and a healthy polecat.
+	writeFile(t, filepath.Join(rig, ".repo.git", "worktrees", "gone", "gitdir"), filepath.Join(rig, "polecats", "gone", "gastown", ".git")+"\n", 0)
+	writeFile(t, filepath.Join(rig, "polecats", "stray", "gastown", ".git"), "gitdir: "+filepath.Join(rig, ".repo.git", "worktrees", "stray")+"\n", 0)
+	writeFile(t, filepath.Join(rig, "polecats", "stray", "gastown", "main.go"), "package main\n", 0)
+	writeFile(t, filepath.Join(rig, ".repo.git", "worktrees", "ok", "gitdir"), filepath.Join(rig, "polecats", "ok", "gastown", ".git")+"\n", 0)
+	writeFile(t, filepath.Join(rig, "polecats", "ok", "gastown", ".git"), "gitdir: "+filepath.Join(rig, ".repo.git", "worktrees", "ok")+"\n", 0)
+
+	// Sessions: a dead one with an old log, a live one (this process).
+	sessions := filepath.Join(town, ".runtime", "sessions")
+	writeFile(t, filepath.Join(sessions, "gt-dead.pid"), strconv.Itoa(deadPID(t))+"\n", 0)
+	writeFile(t, filepath.Join(sessions, "gt-dead.log"), "bye\n", old)
+	writeFile(t, filepath.Join(sessions, "gt-live.pid"), strconv.Itoa(os.Getpid())+"\n", 0)
+	writeFile(t, filepath.Join(sessions, "gt-live.log"), "hi\n", old)
+
+	// Reviews: an old one in the mayor clone, a recent one in the town.
+	writeFile(t, filepath.Join(rig, "mayor", "rig", ".reviews", "r-old", "synthesis.md"), "# old\n", old)
+	writeFile(t, filepath.Join(town, ".reviews", "r-new", "synthesis.md"), "# new\n", 0)
+
+	c := New(town, []string{"gastown"}, &config.GCConfig{})
+	items := c.Scan()
+
+	var got []string
+	for _, it := range items {
+		rel, _ := filepath.Rel(town, it.Path)
+		got = append(got, it.Kind+" "+rel)
+	}
+	sort.Strings(got)
+	want := []string{
+		"cache .runtime/cache/formulas.json",
+		"review gastown/mayor/rig/.reviews/r-old",
+		"session .runtime/sessions/gt-dead.log",
+		"session .runtime/sessions/gt-dead.pid",
+		"worktree gastown/.repo.git/worktrees/gone",
+		"worktree gastown/polecats/stray/gastown",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Scan() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Scan()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	reclaimed, err := c.Remove(items)
+	if err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if reclaimed != Total(items) || reclaimed == 0 {
+		t.Errorf("Remove reclaimed %d bytes, want %d", reclaimed, Total(items))
+	}
+	for _, it := range items {
+		if _, err := os.Stat(it.Path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after Remove", it.Path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(rig, "polecats", "ok", "gastown", ".git")); err != nil {
+		t.Errorf("healthy worktree removed: %v", err)
+	}
+	if again := c.Scan(); len(again) != 0 {
+		t.Errorf("Scan after Remove = %+v, want nothing", again)
+	}
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, ".reviews", "r-1", "synthesis.md"), "# r\n", 10*24*time.Hour)
+
+	if items := New(town, nil, nil).Scan(); len(items) != 0 {
+		t.Errorf("default 30d retention: Scan() = %+v, want nothing", items)
+	}
+	if items := New(town, nil, &config.GCConfig{ReviewDays: 7}).Scan(); len(items) != 1 {
+		t.Errorf("7d retention: Scan() = %+v, want the review", items)
+	}
+}
@@ -0,0 +1,212 @@
+// Package gh runs the GitHub CLI, backing off when GitHub rate limits it.
+// gh inherits the environment, so HTTPS_PROXY and NO_PROXY apply to every
+// call.
+package gh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/offline"
+)
+
+// Backoff settings for rate-limited calls. A call is retried up to
+// MaxAttempts times, waiting InitialBackoff and doubling after each try.
+// When GitHub reports a reset further away than MaxWait the call fails
+// at once instead.
+var (
+	MaxAttempts    = 4
+	InitialBackoff = 2 * time.Second
+	MaxWait        = 2 * time.Minute
+)
+
+// Hooks for tests.
+var (
+	execGH = func(ctx context.Context, dir string, env, args []string) (stdout, stderr []byte, err error) {
+		cmd := exec.CommandContext(ctx, "gh", args...)
+		cmd.Dir = dir
+		if len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+		var out, errOut bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &errOut
+		err = cmdtrace.Run(cmd)
+		return out.Bytes(), errOut.Bytes(), err
+	}
+	now = time.Now
+)
+
+// DefaultHost is the public GitHub host.
+const DefaultHost = "github.com"
+
+// Host is the GitHub host gh talks to and the token it uses there. The
+// zero Host is github.com with gh's own credentials.
+type Host struct {
+	Name  string // Hostname, e.g. "github.example.com"; empty means github.com
+	Token string // Empty uses gh's stored login for the host
+}
+
+// IsEnterprise reports whether h is a GitHub Enterprise Server host.
+func (h Host) IsEnterprise() bool {
+	return h.Name != "" && h.Name != DefaultHost
+}
+
+// env returns the variables pointing gh at h. gh reads the token for an
+// enterprise host from GH_ENTERPRISE_TOKEN and for github.com from GH_TOKEN.
+func (h Host) env() []string {
+	var env []string
+	if h.IsEnterprise() {
+		env = append(env, "GH_HOST="+h.Name)
+		if h.Token != "" {
+			env = append(env, "GH_ENTERPRISE_TOKEN="+h.Token)
+		}
+	} else if h.Token != "" {
+		env = append(env, "GH_TOKEN="+h.Token)
+	}
+	return env
+}
+
+// Error is a failed gh command with its stderr.
+type Error struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("gh %s: %s", command(e.Args), e.Stderr)
+	}
+	return fmt.Sprintf("gh %s: %v", command(e.Args), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when GitHub keeps rate limiting a call.
+// Reset is when the limit lifts, or zero if GitHub didn't say (secondary
+// limits).
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return "GitHub API rate limited; try again in a few minutes"
+	}
+	return "GitHub API rate limited until " + e.Reset.Local().Format("15:04")
+}
+
+// IsRateLimited reports whether err is or wraps a RateLimitError.
+func IsRateLimited(err error) bool {
+	var rl *RateLimitError
+	return errors.As(err, &rl)
+}
+
+// Run runs gh with args and returns its stdout.
+func Run(args ...string) ([]byte, error) {
+	return RunContext(context.Background(), "", args...)
+}
+
+// RunContext runs gh with args in dir and returns its stdout.
+func RunContext(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	return RunHost(ctx, Host{}, dir, args...)
+}
+
+// RunHost runs gh against host with args in dir and returns its stdout.
+// Calls that GitHub rate limits are retried with exponential backoff; once
+// the attempts run out, or the limit resets too far in the future, it
+// returns a *RateLimitError. In offline mode it fails without running gh.
+func RunHost(ctx context.Context, host Host, dir string, args ...string) ([]byte, error) {
+	if err := offline.Check("run gh " + command(args)); err != nil {
+		return nil, &Error{Args: args, Err: err}
+	}
+	env := host.env()
+	delay := InitialBackoff
+	for attempt := 1; ; attempt++ {
+		stdout, stderr, err := execGH(ctx, dir, env, args)
+		if err == nil {
+			return stdout, nil
+		}
+		msg := strings.TrimSpace(string(stderr))
+		if !isRateLimitMessage(msg) {
+			return stdout, &Error{Args: args, Stderr: msg, Err: err}
+		}
+
+		reset := rateLimitReset(ctx, dir, env)
+		if attempt >= MaxAttempts || (!reset.IsZero() && reset.Sub(now()) > MaxWait) {
+			return nil, &RateLimitError{Reset: reset}
+		}
+		wait := delay
+		if !reset.IsZero() && reset.After(now()) {
+			// Waiting past the reset is pointless; waiting less is wasted.
+			wait = reset.Sub(now()) + time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// isRateLimitMessage reports whether gh's stderr says GitHub refused the
+// call for exceeding a primary or secondary rate limit.
+func isRateLimitMessage(stderr string) bool {
+	s := strings.ToLower(stderr)
+	for _, marker := range []string{
+		"rate limit exceeded",
+		"secondary rate limit",
+		"abuse detection",
+		"http 429",
+	} {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitReset asks GitHub when the exhausted rate limit resets. Querying
+// the limits doesn't count against them. Returns zero if no limit is
+// exhausted (a secondary limit) or the query fails.
+func rateLimitReset(ctx context.Context, dir string, env []string) time.Time {
+	stdout, _, err := execGH(ctx, dir, env, []string{"api", "rate_limit", "--jq",
+		"[.resources.core, .resources.graphql] | map(select(.remaining == 0) | .reset) | max // empty"})
+	if err != nil {
+		return time.Time{}
+	}
+	return parseReset(string(stdout))
+}
+
+// parseReset parses a Unix timestamp as printed by rateLimitReset's query.
+func parseReset(out string) time.Time {
+	secs, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil || secs <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+// command returns the gh subcommand of args for messages, e.g. "pr view".
+func command(args []string) string {
+	var words []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") || len(words) == 2 {
+			break
+		}
+		words = append(words, a)
+	}
+	return strings.Join(words, " ")
+}
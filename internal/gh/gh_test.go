@@ -0,0 +1,129 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGH replaces execGH, answering pr calls with replies in order and
+// rate_limit queries with reset.
+func fakeGH(t *testing.T, reset string, replies ...error) *int {
+	t.Helper()
+	calls := 0
+	oldExec, oldBackoff := execGH, InitialBackoff
+	InitialBackoff = time.Millisecond
+	execGH = func(_ context.Context, _ string, _, args []string) ([]byte, []byte, error) {
+		if args[0] == "api" && args[1] == "rate_limit" {
+			return []byte(reset), nil, nil
+		}
+		err := replies[calls]
+		calls++
+		if err != nil {
+			return nil, []byte(err.Error()), errors.New("exit status 1")
+		}
+		return []byte("ok\n"), nil, nil
+	}
+	t.Cleanup(func() { execGH, InitialBackoff = oldExec, oldBackoff })
+	return &calls
+}
+
+var errRateLimited = errors.New("GraphQL: API rate limit exceeded for user ID 42.")
+
+func TestRunRetriesRateLimitedCalls(t *testing.T) {
+	calls := fakeGH(t, "", errRateLimited, errRateLimited, nil)
+	out, err := Run("pr", "view", "1")
+	if err != nil || string(out) != "ok\n" {
+		t.Fatalf("Run = %q, %v", out, err)
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3", *calls)
+	}
+}
+
+func TestRunGivesUpAfterMaxAttempts(t *testing.T) {
+	calls := fakeGH(t, "", errRateLimited, errRateLimited, errRateLimited, errRateLimited)
+	_, err := Run("pr", "diff", "1")
+	if !IsRateLimited(err) {
+		t.Fatalf("err = %v, want a rate limit error", err)
+	}
+	if *calls != MaxAttempts {
+		t.Errorf("calls = %d, want %d", *calls, MaxAttempts)
+	}
+	if !strings.Contains(err.Error(), "try again") {
+		t.Errorf("message = %q", err.Error())
+	}
+}
+
+func TestRunFailsFastOnDistantReset(t *testing.T) {
+	reset := time.Now().Add(40 * time.Minute).Truncate(time.Second)
+	calls := fakeGH(t, strconv.FormatInt(reset.Unix(), 10), errRateLimited)
+	_, err := Run("pr", "view", "1")
+	var rl *RateLimitError
+	if !errors.As(err, &rl) {
+		t.Fatalf("err = %v, want *RateLimitError", err)
+	}
+	if !rl.Reset.Equal(reset) || *calls != 1 {
+		t.Errorf("Reset = %v (want %v), calls = %d", rl.Reset, reset, *calls)
+	}
+	if want := "rate limited until " + reset.Local().Format("15:04"); !strings.Contains(err.Error(), want) {
+		t.Errorf("message = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRunReturnsOtherErrors(t *testing.T) {
+	calls := fakeGH(t, "", errors.New("no pull requests found for branch"))
+	_, err := Run("pr", "view", "1")
+	var ghErr *Error
+	if !errors.As(err, &ghErr) || IsRateLimited(err) {
+		t.Fatalf("err = %v, want *Error", err)
+	}
+	if err.Error() != "gh pr view: no pull requests found for branch" || *calls != 1 {
+		t.Errorf("err = %q, calls = %d", err.Error(), *calls)
+	}
+}
+
+func TestIsRateLimitMessage(t *testing.T) {
+	for msg, want := range map[string]bool{
+		"HTTP 403: API rate limit exceeded for 1.2.3.4":                       true,
+		"You have exceeded a secondary rate limit. Please wait a few minutes": true,
+		"HTTP 429: Too Many Requests":                                         true,
+		"HTTP 404: Not Found":                                                 false,
+		"":                                                                    false,
+	} {
+		if got := isRateLimitMessage(msg); got != want {
+			t.Errorf("isRateLimitMessage(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
+
+func TestRunHostSetsEnterpriseEnv(t *testing.T) {
+	var gotEnv []string
+	oldExec := execGH
+	execGH = func(_ context.Context, _ string, env, _ []string) ([]byte, []byte, error) {
+		gotEnv = env
+		return nil, nil, nil
+	}
+	t.Cleanup(func() { execGH = oldExec })
+
+	for _, tc := range []struct {
+		host Host
+		want []string
+	}{
+		{Host{}, nil},
+		{Host{Name: "github.com", Token: "t1"}, []string{"GH_TOKEN=t1"}},
+		{Host{Name: "ghe.example.com"}, []string{"GH_HOST=ghe.example.com"}},
+		{Host{Name: "ghe.example.com", Token: "t2"}, []string{"GH_HOST=ghe.example.com", "GH_ENTERPRISE_TOKEN=t2"}},
+	} {
+		if _, err := RunHost(context.Background(), tc.host, "", "pr", "view", "1"); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(gotEnv, tc.want) {
+			t.Errorf("RunHost(%+v) env = %v, want %v", tc.host, gotEnv, tc.want)
+		}
+	}
+}
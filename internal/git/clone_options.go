@@ -0,0 +1,148 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sparsePathKey is the repo config key listing the directories sparse
+// checkouts of the repo include. Worktrees of a bare repo share its
+// config, so every worktree checks out the same subset.
+const sparsePathKey = "gt.sparsePath"
+
+// CloneOptions limits what clones fetch and check out.
+type CloneOptions struct {
+	Depth  int      // Truncate history to this many commits (0 = full)
+	Filter string   // Partial clone filter, e.g. "blob:none"
+	Sparse []string // Directories to check out (empty = everything)
+}
+
+// SetCloneOptions applies opts to subsequent clones made through g.
+func (g *Git) SetCloneOptions(opts CloneOptions) {
+	g.cloneOpts = opts
+}
+
+// cloneArgs returns the clone flags for g's clone options.
+func (g *Git) cloneArgs(bare bool) []string {
+	var args []string
+	if g.cloneOpts.Depth > 0 {
+		// --depth implies --single-branch; keep every branch so the
+		// default branch can be checked out and worktrees can branch
+		// from any of them.
+		args = append(args, "--depth", strconv.Itoa(g.cloneOpts.Depth), "--no-single-branch")
+	}
+	if g.cloneOpts.Filter != "" {
+		args = append(args, "--filter="+g.cloneOpts.Filter)
+	}
+	if !bare && len(g.cloneOpts.Sparse) > 0 {
+		// ConfigureSparseCheckout checks out the subset afterwards.
+		args = append(args, "--no-checkout")
+	}
+	return args
+}
+
+// SetSparsePaths records the directories sparse checkouts of the repo
+// include; see ConfigureSparseCheckout. Empty paths restore full
+// checkouts. Existing checkouts change on their next ConfigureSparseCheckout.
+func (g *Git) SetSparsePaths(paths []string) error {
+	if _, err := g.run("config", "--unset-all", sparsePathKey); err != nil {
+		// Exit code 5: the key was not set.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 5 {
+			return err
+		}
+	}
+	for _, p := range paths {
+		if _, err := g.run("config", "--add", sparsePathKey, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SparsePaths returns the directories recorded by SetSparsePaths.
+func (g *Git) SparsePaths() []string {
+	out, err := g.run("config", "--get-all", sparsePathKey)
+	if err != nil || out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// worktreeAdd runs git worktree add. With sparse paths recorded, the
+// worktree is created without a checkout and populated by
+// ConfigureSparseCheckout, so excluded directories are never written.
+func (g *Git) worktreeAdd(path string, args ...string) error {
+	if len(g.SparsePaths()) > 0 {
+		args = append([]string{"--no-checkout"}, args...)
+	}
+	if _, err := g.run(append([]string{"worktree", "add"}, args...)...); err != nil {
+		return err
+	}
+	return ConfigureSparseCheckout(path)
+}
+
+// repoSparsePaths returns the sparse paths recorded in the config of the
+// clone or worktree at repoPath.
+func repoSparsePaths(repoPath string) []string {
+	cmd := exec.Command("git", "-C", repoPath, "config", "--get-all", sparsePathKey)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil
+	}
+	out := strings.TrimSpace(stdout.String())
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// sparseIncludePatterns returns sparse-checkout patterns that include the
+// files at the root, the root dot-directories (.beads/, .githooks/, ...),
+// and the given directories with their parents' files, like cone mode.
+// Without paths everything is included.
+func sparseIncludePatterns(paths []string) string {
+	if len(paths) == 0 {
+		return "/*\n"
+	}
+	// Drop paths inside another listed path; the parent includes them.
+	var dirs []string
+	for _, p := range paths {
+		dirs = append(dirs, strings.Trim(p, "/"))
+	}
+	sort.Strings(dirs)
+	var kept []string
+next:
+	for _, d := range dirs {
+		for _, k := range kept {
+			if d == k || strings.HasPrefix(d, k+"/") {
+				continue next
+			}
+		}
+		kept = append(kept, d)
+	}
+
+	var b strings.Builder
+	b.WriteString("/*\n!/*/\n/.*/\n")
+	seen := make(map[string]bool)
+	for _, d := range kept {
+		parts := strings.Split(d, "/")
+		for i := range parts {
+			dir := "/" + strings.Join(parts[:i+1], "/") + "/"
+			if seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			b.WriteString(dir + "\n")
+			if i < len(parts)-1 {
+				b.WriteString("!" + dir + "*/\n")
+			}
+		}
+	}
+	return b.String()
+}
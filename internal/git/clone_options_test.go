@@ -0,0 +1,129 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSparseIncludePatterns(t *testing.T) {
+	for _, tc := range []struct {
+		paths []string
+		want  string
+	}{
+		{nil, "/*\n"},
+		{[]string{"lib"}, "/*\n!/*/\n/.*/\n/lib/\n"},
+		{[]string{"svc/api/", "svc/web", "lib"},
+			"/*\n!/*/\n/.*/\n/lib/\n/svc/\n!/svc/*/\n/svc/api/\n/svc/web/\n"},
+		// A listed parent covers its subdirectories.
+		{[]string{"svc/api", "svc"}, "/*\n!/*/\n/.*/\n/svc/\n"},
+		{[]string{"a/b", "a-b", "a"}, "/*\n!/*/\n/.*/\n/a/\n/a-b/\n"},
+	} {
+		if got := sparseIncludePatterns(tc.paths); got != tc.want {
+			t.Errorf("sparseIncludePatterns(%q) = %q, want %q", tc.paths, got, tc.want)
+		}
+	}
+}
+
+// initMonorepo creates a repo with files in several directories.
+func initMonorepo(t *testing.T) string {
+	t.Helper()
+	dir := initTestRepo(t)
+	for _, f := range []string{"svc/api/main.go", "svc/web/index.html", "lib/util.go", ".beads/config.yaml"} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "tree"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func assertCheckedOut(t *testing.T, dir string, want map[string]bool) {
+	t.Helper()
+	for f, present := range want {
+		_, err := os.Stat(filepath.Join(dir, f))
+		if present && err != nil {
+			t.Errorf("%s: want checked out, got %v", f, err)
+		}
+		if !present && err == nil {
+			t.Errorf("%s: want excluded by sparse checkout", f)
+		}
+	}
+}
+
+func TestCloneOptionsSparseWorktree(t *testing.T) {
+	src := initMonorepo(t)
+	bare := filepath.Join(t.TempDir(), ".repo.git")
+
+	g := NewGit(t.TempDir())
+	g.SetCloneOptions(CloneOptions{Depth: 1, Sparse: []string{"svc/api"}})
+	if err := g.CloneBare("file://"+src, bare); err != nil {
+		t.Fatalf("CloneBare: %v", err)
+	}
+	repo := NewGitWithDir(bare, "")
+	if got := repo.SparsePaths(); len(got) != 1 || got[0] != "svc/api" {
+		t.Fatalf("SparsePaths = %q, want [svc/api]", got)
+	}
+	if out, _ := repo.run("rev-list", "--count", "HEAD"); out != "1" {
+		t.Errorf("commits in shallow clone = %s, want 1", out)
+	}
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	if err := repo.WorktreeAddFromRef(wt, "polecat/test", "HEAD"); err != nil {
+		t.Fatalf("WorktreeAddFromRef: %v", err)
+	}
+	subset := map[string]bool{
+		"README.md":          true,
+		".beads/config.yaml": true,
+		"svc/api/main.go":    true,
+		"svc/web/index.html": false,
+		"lib/util.go":        false,
+	}
+	assertCheckedOut(t, wt, subset)
+
+	// Re-applying (as gt doctor does) keeps the subset.
+	if err := ConfigureSparseCheckout(wt); err != nil {
+		t.Fatalf("ConfigureSparseCheckout: %v", err)
+	}
+	assertCheckedOut(t, wt, subset)
+
+	// Clearing the paths restores the full tree on the next apply.
+	if err := repo.SetSparsePaths(nil); err != nil {
+		t.Fatalf("SetSparsePaths: %v", err)
+	}
+	if err := ConfigureSparseCheckout(wt); err != nil {
+		t.Fatalf("ConfigureSparseCheckout: %v", err)
+	}
+	assertCheckedOut(t, wt, map[string]bool{"svc/web/index.html": true, "lib/util.go": true})
+}
+
+func TestCloneOptionsSparseClone(t *testing.T) {
+	src := initMonorepo(t)
+	dest := filepath.Join(t.TempDir(), "crew")
+
+	g := NewGit(t.TempDir())
+	g.SetCloneOptions(CloneOptions{Sparse: []string{"lib"}})
+	if err := g.Clone(src, dest); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	assertCheckedOut(t, dest, map[string]bool{
+		"README.md":          true,
+		"lib/util.go":        true,
+		"svc/api/main.go":    false,
+		"svc/web/index.html": false,
+	})
+	if status, err := NewGit(dest).Status(); err != nil || !status.Clean {
+		t.Errorf("sparse clone status = %+v, %v; want clean", status, err)
+	}
+}
@@ -55,8 +55,9 @@ func moveDir(src, dest string) error {
 
 // Git wraps git operations for a working directory.
 type Git struct {
-	workDir string
-	gitDir  string // Optional: explicit git directory (for bare repos)
+	workDir   string
+	gitDir    string       // Optional: explicit git directory (for bare repos)
+	cloneOpts CloneOptions // Applied by the Clone* methods
 }
 
 // NewGit creates a new Git wrapper for the given directory.
@@ -150,7 +151,8 @@ func (g *Git) Clone(url, dest string) error {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
-	cmd := exec.Command("git", "clone", url, tmpDest)
+	args := append(append([]string{"clone"}, g.cloneArgs(false)...), url, tmpDest)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = tmpDir
 	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
 	var stdout, stderr bytes.Buffer
@@ -165,12 +167,23 @@ func (g *Git) Clone(url, dest string) error {
 		return fmt.Errorf("moving clone to destination: %w", err)
 	}
 
-	// Configure hooks path for Gas Town clones
-	if err := configureHooksPath(dest); err != nil {
-		return err
+	return g.finishClone(dest)
+}
+
+// finishClone configures a fresh non-bare clone: it records the sparse
+// paths, checks out (the subset of) the tree, and sets the hooks path.
+func (g *Git) finishClone(dest string) error {
+	if len(g.cloneOpts.Sparse) > 0 {
+		if err := NewGit(dest).SetSparsePaths(g.cloneOpts.Sparse); err != nil {
+			return fmt.Errorf("recording sparse paths: %w", err)
+		}
 	}
 	// Configure sparse checkout to exclude .claude/ from source repo
-	return ConfigureSparseCheckout(dest)
+	if err := ConfigureSparseCheckout(dest); err != nil {
+		return err
+	}
+	// Configure hooks path for Gas Town clones
+	return configureHooksPath(dest)
 }
 
 // CloneWithReference clones a repository using a local repo as an object reference.
@@ -190,7 +203,7 @@ func (g *Git) CloneWithReference(url, dest, reference string) error {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
-	args := []string{"clone", "--reference-if-able", reference, url, tmpDest}
+	args := append(append([]string{"clone"}, g.cloneArgs(false)...), "--reference-if-able", reference, url, tmpDest)
 	if runtime.GOOS == "windows" {
 		args = append([]string{"-c", "core.symlinks=true"}, args...)
 	}
@@ -209,12 +222,7 @@ func (g *Git) CloneWithReference(url, dest, reference string) error {
 		return fmt.Errorf("moving clone to destination: %w", err)
 	}
 
-	// Configure hooks path for Gas Town clones
-	if err := configureHooksPath(dest); err != nil {
-		return err
-	}
-	// Configure sparse checkout to exclude .claude/ from source repo
-	return ConfigureSparseCheckout(dest)
+	return g.finishClone(dest)
 }
 
 // CloneBare clones a repository as a bare repo (no working directory).
@@ -234,7 +242,8 @@ func (g *Git) CloneBare(url, dest string) error {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
-	cmd := exec.Command("git", "clone", "--bare", url, tmpDest)
+	args := append(append([]string{"clone", "--bare"}, g.cloneArgs(true)...), url, tmpDest)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = tmpDir
 	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
 	var stdout, stderr bytes.Buffer
@@ -249,6 +258,11 @@ func (g *Git) CloneBare(url, dest string) error {
 		return fmt.Errorf("moving clone to destination: %w", err)
 	}
 
+	if len(g.cloneOpts.Sparse) > 0 {
+		if err := NewGitWithDir(dest, "").SetSparsePaths(g.cloneOpts.Sparse); err != nil {
+			return fmt.Errorf("recording sparse paths: %w", err)
+		}
+	}
 	// Configure refspec so worktrees can fetch and see origin/* refs
 	return configureRefspec(dest)
 }
@@ -316,7 +330,8 @@ func (g *Git) CloneBareWithReference(url, dest, reference string) error {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
-	cmd := exec.Command("git", "clone", "--bare", "--reference-if-able", reference, url, tmpDest)
+	args := append(append([]string{"clone", "--bare"}, g.cloneArgs(true)...), "--reference-if-able", reference, url, tmpDest)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = tmpDir
 	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
 	var stdout, stderr bytes.Buffer
@@ -331,6 +346,11 @@ func (g *Git) CloneBareWithReference(url, dest, reference string) error {
 		return fmt.Errorf("moving clone to destination: %w", err)
 	}
 
+	if len(g.cloneOpts.Sparse) > 0 {
+		if err := NewGitWithDir(dest, "").SetSparsePaths(g.cloneOpts.Sparse); err != nil {
+			return fmt.Errorf("recording sparse paths: %w", err)
+		}
+	}
 	// Configure refspec so worktrees can fetch and see origin/* refs
 	return configureRefspec(dest)
 }
@@ -667,6 +687,26 @@ func (g *Git) CreateBranchFrom(name, ref string) error {
 	return err
 }
 
+// CheckoutResetBranch checks out branch, creating it or resetting it to ref
+// (git checkout -B).
+func (g *Git) CheckoutResetBranch(branch, ref string) error {
+	_, err := g.run("checkout", "-B", branch, ref)
+	return err
+}
+
+// ResetToDetached discards all changes, including untracked and ignored
+// files, and detaches HEAD at ref.
+func (g *Git) ResetToDetached(ref string) error {
+	if _, err := g.run("checkout", "--force", "--detach", ref); err != nil {
+		return err
+	}
+	if _, err := g.run("reset", "--hard", ref); err != nil {
+		return err
+	}
+	_, err := g.run("clean", "-ffdx")
+	return err
+}
+
 // BranchExists checks if a branch exists locally.
 func (g *Git) BranchExists(name string) (bool, error) {
 	_, err := g.run("show-ref", "--verify", "--quiet", "refs/heads/"+name)
@@ -751,48 +791,33 @@ func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 // The new branch is created from the current HEAD.
 // Sparse checkout is enabled to exclude .claude/ from source repos.
 func (g *Git) WorktreeAdd(path, branch string) error {
-	if _, err := g.run("worktree", "add", "-b", branch, path); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
+	return g.worktreeAdd(path, "-b", branch, path)
 }
 
 // WorktreeAddFromRef creates a new worktree at the given path with a new branch
 // starting from the specified ref (e.g., "origin/main").
 // Sparse checkout is enabled to exclude .claude/ from source repos.
 func (g *Git) WorktreeAddFromRef(path, branch, startPoint string) error {
-	if _, err := g.run("worktree", "add", "-b", branch, path, startPoint); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
+	return g.worktreeAdd(path, "-b", branch, path, startPoint)
 }
 
 // WorktreeAddDetached creates a new worktree at the given path with a detached HEAD.
 // Sparse checkout is enabled to exclude .claude/ from source repos.
 func (g *Git) WorktreeAddDetached(path, ref string) error {
-	if _, err := g.run("worktree", "add", "--detach", path, ref); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
+	return g.worktreeAdd(path, "--detach", path, ref)
 }
 
 // WorktreeAddExisting creates a new worktree at the given path for an existing branch.
 // Sparse checkout is enabled to exclude .claude/ from source repos.
 func (g *Git) WorktreeAddExisting(path, branch string) error {
-	if _, err := g.run("worktree", "add", path, branch); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
+	return g.worktreeAdd(path, path, branch)
 }
 
 // WorktreeAddExistingForce creates a new worktree even if the branch is already checked out elsewhere.
 // This is useful for cross-rig worktrees where multiple clones need to be on main.
 // Sparse checkout is enabled to exclude .claude/ from source repos.
 func (g *Git) WorktreeAddExistingForce(path, branch string) error {
-	if _, err := g.run("worktree", "add", "--force", path, branch); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
+	return g.worktreeAdd(path, "--force", path, branch)
 }
 
 // ConfigureSparseCheckout sets up sparse checkout for a clone or worktree to exclude .claude/.
@@ -834,7 +859,8 @@ func ConfigureSparseCheckout(repoPath string) error {
 		return fmt.Errorf("creating info dir: %w", err)
 	}
 	sparseFile := filepath.Join(infoDir, "sparse-checkout")
-	sparsePatterns := "/*\n!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n"
+	// Sparse paths recorded by SetSparsePaths narrow the checkout further.
+	sparsePatterns := sparseIncludePatterns(repoSparsePaths(repoPath)) + "!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n"
 	if err := os.WriteFile(sparseFile, []byte(sparsePatterns), 0644); err != nil {
 		return fmt.Errorf("writing sparse-checkout: %w", err)
 	}
@@ -936,6 +962,12 @@ func (g *Git) WorktreeRemove(path string, force bool) error {
 	return err
 }
 
+// WorktreeMove moves a worktree to a new path.
+func (g *Git) WorktreeMove(path, newPath string) error {
+	_, err := g.run("worktree", "move", path, newPath)
+	return err
+}
+
 // WorktreePrune removes worktree entries for deleted paths.
 func (g *Git) WorktreePrune() error {
 	_, err := g.run("worktree", "prune")
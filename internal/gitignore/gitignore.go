@@ -0,0 +1,190 @@
+// Package gitignore manages the gt-owned block of patterns in .gitignore files.
+//
+// Gas Town writes its required patterns between two marker comments:
+//
+//	# gastown:begin
+//	.runtime/
+//	.reviews/
+//	# gastown:end
+//
+// Everything outside the markers belongs to the user and is never modified.
+// Syncing replaces the block in place (or appends it) so repeated runs are
+// idempotent and pattern changes in new gt versions propagate cleanly.
+package gitignore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+const (
+	// BeginMarker opens the managed block.
+	BeginMarker = "# gastown:begin"
+	// EndMarker closes the managed block.
+	EndMarker = "# gastown:end"
+)
+
+// RequiredPatterns are the patterns every Gas Town .gitignore must contain.
+var RequiredPatterns = []string{
+	constants.DirRuntime + "/", // runtime state, logs, caches
+	".reviews/",                // convoy review outputs
+	".beads-wisp/",             // ephemeral wisp storage
+}
+
+// Scope describes where a managed .gitignore lives.
+type Scope string
+
+const (
+	// ScopeTown is the town root .gitignore.
+	ScopeTown Scope = "town"
+	// ScopeRig is a rig's canonical clone (<rig>/mayor/rig).
+	ScopeRig Scope = "rig"
+	// ScopeCrew is a crew member's clone (<rig>/crew/<name>).
+	ScopeCrew Scope = "crew"
+)
+
+// Target is a directory whose .gitignore is managed by gt.
+type Target struct {
+	Dir   string // Directory containing the .gitignore
+	Scope Scope
+}
+
+// Path returns the .gitignore path for the target.
+func (t Target) Path() string {
+	return filepath.Join(t.Dir, ".gitignore")
+}
+
+// Targets returns the managed .gitignore locations for a town: the town
+// root, each rig's canonical clone, and each crew member's clone.
+// rigPaths are absolute rig directories.
+func Targets(townRoot string, rigPaths []string) []Target {
+	targets := []Target{{Dir: townRoot, Scope: ScopeTown}}
+
+	for _, rigPath := range rigPaths {
+		rigClone := filepath.Join(rigPath, "mayor", constants.DirRig)
+		if info, err := os.Stat(rigClone); err == nil && info.IsDir() {
+			targets = append(targets, Target{Dir: rigClone, Scope: ScopeRig})
+		}
+
+		crewPath := filepath.Join(rigPath, constants.DirCrew)
+		entries, err := os.ReadDir(crewPath)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				targets = append(targets, Target{Dir: filepath.Join(crewPath, entry.Name()), Scope: ScopeCrew})
+			}
+		}
+	}
+
+	return targets
+}
+
+// RenderBlock returns the managed block for the given patterns, including markers.
+func RenderBlock(patterns []string) string {
+	var b strings.Builder
+	b.WriteString(BeginMarker + "\n")
+	b.WriteString("# Managed by gt - edits inside this block are overwritten by 'gt gitignore sync'\n")
+	for _, p := range patterns {
+		b.WriteString(p + "\n")
+	}
+	b.WriteString(EndMarker + "\n")
+	return b.String()
+}
+
+// Apply inserts or replaces the managed block in content.
+// Returns the new content and whether it changed.
+func Apply(content string, patterns []string) (string, bool) {
+	block := RenderBlock(patterns)
+
+	begin := strings.Index(content, BeginMarker)
+	if begin != -1 {
+		end := strings.Index(content[begin:], EndMarker)
+		if end != -1 {
+			end = begin + end + len(EndMarker)
+			// Consume the newline following the end marker
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			updated := content[:begin] + block + content[end:]
+			return updated, updated != content
+		}
+		// Unterminated block: drop everything from the begin marker and rewrite
+		content = strings.TrimRight(content[:begin], "\n")
+		if content != "" {
+			content += "\n"
+		}
+	}
+
+	if content == "" {
+		return block, true
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\n" + block, true
+}
+
+// Sync writes the managed block into the .gitignore at path, creating the
+// file if needed. Returns true if the file was modified.
+func Sync(path string, patterns []string) (bool, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a managed .gitignore location
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	updated, changed := Apply(string(data), patterns)
+	if !changed {
+		return false, nil
+	}
+
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil { //nolint:gosec // G306: .gitignore should be readable by git tools
+		return false, fmt.Errorf("writing %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// Missing returns the patterns not ignored by the .gitignore at path.
+// A pattern counts as present in any of its common forms (with or without
+// trailing slash, anchored with "/", or prefixed with "**/"), whether inside
+// or outside the managed block.
+func Missing(path string, patterns []string) []string {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a managed .gitignore location
+	if err != nil {
+		return append([]string(nil), patterns...)
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "**/")
+		line = strings.TrimPrefix(line, "/")
+		line = strings.TrimSuffix(line, "/")
+		present[line] = true
+	}
+
+	var missing []string
+	for _, p := range patterns {
+		if !present[strings.TrimSuffix(p, "/")] {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// HasBlock reports whether the .gitignore at path contains a managed block.
+func HasBlock(path string) bool {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a managed .gitignore location
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), BeginMarker) && strings.Contains(string(data), EndMarker)
+}
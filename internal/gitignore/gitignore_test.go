@@ -0,0 +1,106 @@
+package gitignore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	patterns := []string{".runtime/", ".reviews/"}
+	block := RenderBlock(patterns)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		changed bool
+	}{
+		{"empty", "", block, true},
+		{"append", "node_modules/", "node_modules/\n\n" + block, true},
+		{"idempotent", "node_modules/\n\n" + block, "node_modules/\n\n" + block, false},
+		{
+			"replace stale block",
+			"a\n" + BeginMarker + "\n.old/\n" + EndMarker + "\nb\n",
+			"a\n" + block + "b\n",
+			true,
+		},
+		{
+			"unterminated block",
+			"a\n\n" + BeginMarker + "\n.old/\n",
+			"a\n\n" + block,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := Apply(tt.content, patterns)
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+			if changed != tt.changed {
+				t.Errorf("Apply() changed = %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
+
+func TestSyncAndMissing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gitignore")
+	if err := os.WriteFile(path, []byte("/.runtime\n**/.reviews\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := Missing(path, RequiredPatterns)
+	if len(missing) != 1 || missing[0] != ".beads-wisp/" {
+		t.Errorf("Missing() = %v, want [.beads-wisp/]", missing)
+	}
+
+	changed, err := Sync(path, RequiredPatterns)
+	if err != nil || !changed {
+		t.Fatalf("Sync() = %v, %v; want true, nil", changed, err)
+	}
+	if !HasBlock(path) {
+		t.Error("HasBlock() = false after Sync")
+	}
+	if m := Missing(path, RequiredPatterns); len(m) != 0 {
+		t.Errorf("Missing() after Sync = %v", m)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "/.runtime\n**/.reviews\n") {
+		t.Errorf("user lines not preserved:\n%s", data)
+	}
+
+	changed, err = Sync(path, RequiredPatterns)
+	if err != nil || changed {
+		t.Errorf("second Sync() = %v, %v; want false, nil", changed, err)
+	}
+}
+
+func TestTargets(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "myrig")
+	for _, d := range []string{
+		filepath.Join(rig, "mayor", "rig"),
+		filepath.Join(rig, "crew", "alice"),
+		filepath.Join(rig, "crew", ".hidden"),
+	} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	targets := Targets(town, []string{rig, filepath.Join(town, "missing")})
+	if len(targets) != 3 {
+		t.Fatalf("Targets() returned %d targets, want 3: %+v", len(targets), targets)
+	}
+	wantScopes := []Scope{ScopeTown, ScopeRig, ScopeCrew}
+	for i, s := range wantScopes {
+		if targets[i].Scope != s {
+			t.Errorf("targets[%d].Scope = %s, want %s", i, targets[i].Scope, s)
+		}
+	}
+}
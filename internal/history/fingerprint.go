@@ -0,0 +1,63 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Fingerprint is the environment a formula run ran in, so a run that
+// behaves differently from last week's can be traced to what changed.
+type Fingerprint struct {
+	GT      string            `json:"gt"`                 // gt version and commit
+	BD      string            `json:"bd,omitempty"`       // bd version
+	Agents  map[string]string `json:"agents,omitempty"`   // Agent name -> reported version
+	OS      string            `json:"os"`                 // GOOS/GOARCH
+	RigHead string            `json:"rig_head,omitempty"` // Git SHA of the rig's HEAD
+}
+
+// Lines renders the fingerprint as "name: value" lines, agents sorted.
+func (f *Fingerprint) Lines() []string {
+	lines := []string{"gt: " + orUnknown(f.GT), "bd: " + orUnknown(f.BD)}
+	for _, name := range sortedKeys(f.Agents) {
+		lines = append(lines, name+": "+orUnknown(f.Agents[name]))
+	}
+	lines = append(lines, "os: "+orUnknown(f.OS), "rig HEAD: "+orUnknown(f.RigHead))
+	return lines
+}
+
+// Changes lists what differs from an earlier fingerprint, as
+// "name: old → new". Agents only one of the runs used are not compared.
+func (f *Fingerprint) Changes(prev *Fingerprint) []string {
+	var changes []string
+	diff := func(name, old, cur string) {
+		if old != cur {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", name, orUnknown(old), orUnknown(cur)))
+		}
+	}
+	diff("gt", prev.GT, f.GT)
+	diff("bd", prev.BD, f.BD)
+	for _, name := range sortedKeys(f.Agents) {
+		if old, ok := prev.Agents[name]; ok {
+			diff(name, old, f.Agents[name])
+		}
+	}
+	diff("os", prev.OS, f.OS)
+	diff("rig HEAD", prev.RigHead, f.RigHead)
+	return changes
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,29 @@
+package history
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprintChanges(t *testing.T) {
+	prev := &Fingerprint{GT: "0.5.0", BD: "0.44.0", Agents: map[string]string{"claude": "2.0.1", "gemini": "0.9"}, OS: "linux/amd64", RigHead: "abc"}
+	cur := &Fingerprint{GT: "0.5.0", BD: "0.45.1", Agents: map[string]string{"claude": "2.0.3", "codex": "1.0"}, OS: "linux/amd64"}
+
+	got := strings.Join(cur.Changes(prev), "; ")
+	want := "bd: 0.44.0 → 0.45.1; claude: 2.0.1 → 2.0.3; rig HEAD: abc → unknown"
+	if got != want {
+		t.Errorf("Changes() = %q, want %q", got, want)
+	}
+	if changes := cur.Changes(cur); len(changes) != 0 {
+		t.Errorf("Changes(self) = %q, want none", changes)
+	}
+}
+
+func TestFingerprintLines(t *testing.T) {
+	fp := &Fingerprint{GT: "0.5.0", Agents: map[string]string{"gemini": "0.9", "claude": ""}, OS: "darwin/arm64"}
+	got := strings.Join(fp.Lines(), "\n")
+	want := "gt: 0.5.0\nbd: unknown\nclaude: unknown\ngemini: 0.9\nos: darwin/arm64\nrig HEAD: unknown"
+	if got != want {
+		t.Errorf("Lines() =\n%s\nwant\n%s", got, want)
+	}
+}
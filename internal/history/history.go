@@ -0,0 +1,150 @@
+// Package history records gt command invocations in a town's
+// .runtime/history.jsonl so operators on shared machines can see who ran
+// what against which rig.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/fsx"
+	"github.com/steveyegge/gastown/internal/readonly"
+)
+
+const (
+	// FileName is the history log within the town's .runtime directory.
+	FileName = "history.jsonl"
+
+	// MaxBytes is the size at which the log is rotated.
+	MaxBytes = 5 * 1024 * 1024
+
+	// MaxRotated is the number of rotated logs kept (history.jsonl.1 ...).
+	MaxRotated = 3
+)
+
+// Entry is one recorded gt invocation.
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"` // Resolved command path, e.g. "gt rig add"
+	Args       []string  `json:"args"`    // Raw arguments after the binary name
+	Cwd        string    `json:"cwd"`
+	Town       string    `json:"town"`
+	Rig        string    `json:"rig,omitempty"`
+	User       string    `json:"user,omitempty"`
+	Role       string    `json:"role,omitempty"` // GT_ROLE when run by an agent
+	DurationMs int64     `json:"duration_ms"`
+	ExitCode   int       `json:"exit_code"`
+	Error      string    `json:"error,omitempty"`
+
+	// Agent reply cache lookups, for formula runs with cache_ttl
+	CacheHits   int `json:"cache_hits,omitempty"`
+	CacheMisses int `json:"cache_misses,omitempty"`
+
+	// Formula runs: the formula and the environment it ran in
+	Formula string       `json:"formula,omitempty"`
+	Env     *Fingerprint `json:"env,omitempty"`
+	Labels  []string     `json:"labels,omitempty"` // gt formula run --label, as key:value
+
+	// Formula legs that ran more than once, by leg ID, with the attempts
+	// each took. A leg retried by the convoy monitor is recorded as its own
+	// entry naming the convoy, one per retry.
+	LegAttempts map[string]int `json:"leg_attempts,omitempty"`
+	Convoy      string         `json:"convoy,omitempty"`
+}
+
+// IsLegRetry reports whether the entry records a convoy monitor's leg
+// retry rather than a command run.
+func (e Entry) IsLegRetry() bool {
+	return e.Convoy != "" && len(e.LegAttempts) > 0
+}
+
+// Failed reports whether the command exited non-zero.
+func (e Entry) Failed() bool {
+	return e.ExitCode != 0
+}
+
+// Path returns the history log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, FileName)
+}
+
+// Append writes an entry to the town's history log, rotating it first if
+// it has grown past MaxBytes. Safe to call from concurrent processes.
+// Nothing is recorded in read-only mode.
+func Append(townRoot string, entry Entry) error {
+	if readonly.Enabled() {
+		return nil
+	}
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Hold the lock across rotation so concurrent gt commands neither rotate
+	// twice nor append to a file that is being renamed away.
+	unlock, err := fsx.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if info, err := os.Stat(path); err == nil && info.Size() >= MaxBytes {
+		rotate(path)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: history is not secret
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// rotate shifts history.jsonl -> .1 -> .2 ..., dropping the oldest.
+func rotate(path string) {
+	_ = os.Remove(fmt.Sprintf("%s.%d", path, MaxRotated))
+	for i := MaxRotated - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+	}
+	_ = os.Rename(path, path+".1")
+}
+
+// Read returns all recorded entries, oldest first, including rotated logs.
+// Malformed lines are skipped.
+func Read(townRoot string) ([]Entry, error) {
+	path := Path(townRoot)
+	var files []string
+	for i := MaxRotated; i >= 1; i-- {
+		files = append(files, fmt.Sprintf("%s.%d", path, i))
+	}
+	files = append(files, path)
+
+	var entries []Entry
+	for _, file := range files {
+		f, err := os.Open(file) //nolint:gosec // G304: path is within the town runtime dir
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return entries, fmt.Errorf("reading history: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var e Entry
+			if json.Unmarshal(scanner.Bytes(), &e) == nil {
+				entries = append(entries, e)
+			}
+		}
+		_ = f.Close()
+	}
+	return entries, nil
+}
@@ -0,0 +1,72 @@
+package history
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	town := t.TempDir()
+	for i, code := range []int{0, 1, 0} {
+		err := Append(town, Entry{
+			Time:     time.Now(),
+			Command:  "gt status",
+			Args:     []string{"status", strings.Repeat("x", i)},
+			ExitCode: code,
+		})
+		if err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	entries, err := Read(town)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if !entries[1].Failed() || entries[0].Failed() {
+		t.Errorf("Failed() wrong: %+v", entries)
+	}
+}
+
+func TestReadMissing(t *testing.T) {
+	entries, err := Read(t.TempDir())
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Read on empty town = %v, %v; want none, nil", entries, err)
+	}
+}
+
+func TestAppendRotates(t *testing.T) {
+	town := t.TempDir()
+	if err := Append(town, Entry{Command: "gt first"}); err != nil {
+		t.Fatal(err)
+	}
+	// Pad the log past the rotation threshold.
+	f, err := os.OpenFile(Path(town), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(strings.Repeat("\n", MaxBytes)); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	if err := Append(town, Entry{Command: "gt second"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Path(town) + ".1"); err != nil {
+		t.Fatalf("expected rotated log: %v", err)
+	}
+
+	entries, err := Read(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Command != "gt first" || entries[1].Command != "gt second" {
+		t.Errorf("entries after rotation = %+v", entries)
+	}
+}
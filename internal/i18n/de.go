@@ -0,0 +1,47 @@
+package i18n
+
+// de holds the German translations.
+var de = map[string]string{
+	// doctor
+	"%s %d passed  %s %d warnings  %s %d failed": "%s %d bestanden  %s %d Warnungen  %s %d fehlgeschlagen",
+	"  ⏳ %d slow (slowest: %s %s)":               "  ⏳ %d langsam (langsamste: %s %s)",
+	"All checks passed":                          "Alle Prüfungen bestanden",
+	"WARNINGS":                                   "WARNUNGEN",
+	"Core":                                       "Kern",
+	"Infrastructure":                             "Infrastruktur",
+	"Rig":                                        "Rig",
+	"Patrol":                                     "Patrouille",
+	"Configuration":                              "Konfiguration",
+	"Cleanup":                                    "Bereinigung",
+	"Hooks":                                      "Hooks",
+	"Other":                                      "Sonstiges",
+	"Health score: %s":                           "Gesundheitswert: %s",
+	"%s  %s since last run":                      "%s  %s seit dem letzten Lauf",
+	"  %s new: %s: %s":                           "  %s neu: %s: %s",
+	"No doctor runs recorded yet. Run gt doctor to start tracking.": "Noch keine Doctor-Läufe aufgezeichnet. Starte die Aufzeichnung mit gt doctor.",
+	"%s Improving: %d → %d over %d runs":                            "%s Verbesserung: %d → %d über %d Läufe",
+	"%s Declining: %d → %d over %d runs":                            "%s Verschlechterung: %d → %d über %d Läufe",
+	"%s Steady at %d over %d runs":                                  "%s Stabil bei %d über %d Läufe",
+	"  %s warnings accumulating: %d → %d":                           "  %s Warnungen nehmen zu: %d → %d",
+
+	// formula
+	"%d formulas":                       "%d Formeln",
+	"NAME":                              "NAME",
+	"TYPE":                              "TYP",
+	"SIZE":                              "UMFANG",
+	"SOURCE":                            "QUELLE",
+	"DESCRIPTION":                       "BESCHREIBUNG",
+	"Reset %s to the embedded version":  "%s auf die mitgelieferte Version zurückgesetzt",
+	"%s is a customized override (%s)":  "%s ist eine angepasste Überschreibung (%s)",
+	"Updated %s":                        "%s aktualisiert",
+	"no owner or reason recorded":       "kein Besitzer und kein Grund hinterlegt",
+	"Moved %s from %s to %s":            "%s von %s nach %s verschoben",
+	"now shadows the %s copy at %s":     "verdeckt jetzt die %s-Kopie unter %s",
+	"the %s copy at %s shadows it here": "die %s-Kopie unter %s verdeckt sie hier",
+	"rig %s keeps its own copy at %s":   "Rig %s hat eine eigene Kopie unter %s",
+	"No differences":                    "Keine Unterschiede",
+	"Asking agent to edit %s...":        "Agent bearbeitet %s...",
+	"Agent made no changes":             "Der Agent hat nichts geändert",
+	"Write changes to %s?":              "Änderungen in %s schreiben?",
+	"Aborted; formula unchanged":        "Abgebrochen; Formel unverändert",
+}
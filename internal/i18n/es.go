@@ -0,0 +1,47 @@
+package i18n
+
+// es holds the Spanish translations.
+var es = map[string]string{
+	// doctor
+	"%s %d passed  %s %d warnings  %s %d failed": "%s %d correctas  %s %d advertencias  %s %d fallidas",
+	"  ⏳ %d slow (slowest: %s %s)":               "  ⏳ %d lentas (la más lenta: %s %s)",
+	"All checks passed":                          "Todas las comprobaciones son correctas",
+	"WARNINGS":                                   "ADVERTENCIAS",
+	"Core":                                       "Núcleo",
+	"Infrastructure":                             "Infraestructura",
+	"Rig":                                        "Rig",
+	"Patrol":                                     "Patrulla",
+	"Configuration":                              "Configuración",
+	"Cleanup":                                    "Limpieza",
+	"Hooks":                                      "Hooks",
+	"Other":                                      "Otros",
+	"Health score: %s":                           "Puntuación de salud: %s",
+	"%s  %s since last run":                      "%s  %s desde la última ejecución",
+	"  %s new: %s: %s":                           "  %s nuevo: %s: %s",
+	"No doctor runs recorded yet. Run gt doctor to start tracking.": "Aún no hay ejecuciones de doctor registradas. Ejecuta gt doctor para empezar a registrarlas.",
+	"%s Improving: %d → %d over %d runs":                            "%s Mejorando: %d → %d en %d ejecuciones",
+	"%s Declining: %d → %d over %d runs":                            "%s Empeorando: %d → %d en %d ejecuciones",
+	"%s Steady at %d over %d runs":                                  "%s Estable en %d durante %d ejecuciones",
+	"  %s warnings accumulating: %d → %d":                           "  %s las advertencias se acumulan: %d → %d",
+
+	// formula
+	"%d formulas":                       "%d fórmulas",
+	"NAME":                              "NOMBRE",
+	"TYPE":                              "TIPO",
+	"SIZE":                              "TAMAÑO",
+	"SOURCE":                            "ORIGEN",
+	"DESCRIPTION":                       "DESCRIPCIÓN",
+	"Reset %s to the embedded version":  "%s restablecida a la versión incluida",
+	"%s is a customized override (%s)":  "%s es una personalización (%s)",
+	"Updated %s":                        "%s actualizado",
+	"no owner or reason recorded":       "sin responsable ni motivo registrados",
+	"Moved %s from %s to %s":            "%s movida de %s a %s",
+	"now shadows the %s copy at %s":     "ahora oculta la copia de %s en %s",
+	"the %s copy at %s shadows it here": "la copia de %s en %s la oculta aquí",
+	"rig %s keeps its own copy at %s":   "el rig %s tiene su propia copia en %s",
+	"No differences":                    "Sin diferencias",
+	"Asking agent to edit %s...":        "Pidiendo al agente que edite %s...",
+	"Agent made no changes":             "El agente no hizo cambios",
+	"Write changes to %s?":              "¿Escribir los cambios en %s?",
+	"Aborted; formula unchanged":        "Cancelado; la fórmula no cambió",
+}
@@ -0,0 +1,137 @@
+// Package i18n translates gt's user-facing output.
+//
+// Messages are keyed by their English format string, so call sites read
+// like fmt calls and any message without a translation falls back to the
+// English text unchanged:
+//
+//	fmt.Println(i18n.Sprintf("%d formulas", n))
+//
+// Translations live in one file per language (de.go, es.go). The language
+// is chosen once at startup with Init.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// translations maps each supported language to its messages, keyed by the
+// English format string.
+var translations = map[language.Tag]map[string]string{
+	language.German:  de,
+	language.Spanish: es,
+}
+
+// supported lists the selectable languages; English comes first so it is
+// the matcher's fallback.
+var supported = []language.Tag{language.English, language.German, language.Spanish}
+
+var (
+	cat     = buildCatalog()
+	matcher = language.NewMatcher(supported)
+
+	mu      sync.RWMutex
+	current = language.English
+	printer = message.NewPrinter(language.English, message.Catalog(cat))
+)
+
+func buildCatalog() catalog.Catalog {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	for tag, msgs := range translations {
+		for key, msg := range msgs {
+			_ = b.SetString(tag, key, msg)
+		}
+	}
+	return b
+}
+
+// Init selects the output language from, in order:
+//  1. GT_LOCALE environment variable
+//  2. configLocale (TownSettings.Locale)
+//  3. LC_ALL, LC_MESSAGES, or LANG
+//  4. English
+func Init(configLocale string) language.Tag {
+	return SetLocale(Resolve(configLocale))
+}
+
+// Resolve returns the locale string Init would use.
+func Resolve(configLocale string) string {
+	for _, v := range []string{os.Getenv("GT_LOCALE"), configLocale, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// SetLocale selects the supported language closest to locale, which may be
+// a BCP 47 tag ("de-AT") or a POSIX locale ("de_AT.UTF-8"). Empty, "C",
+// and unsupported locales select English. It returns the language chosen.
+func SetLocale(locale string) language.Tag {
+	tag := Match(locale)
+	mu.Lock()
+	defer mu.Unlock()
+	current = tag
+	printer = message.NewPrinter(tag, message.Catalog(cat))
+	return tag
+}
+
+// Match returns the supported language closest to locale.
+func Match(locale string) language.Tag {
+	tag, err := Parse(locale)
+	if err != nil {
+		return language.English
+	}
+	_, idx, conf := matcher.Match(tag)
+	if conf == language.No {
+		return language.English
+	}
+	return supported[idx]
+}
+
+// Parse parses a BCP 47 tag or POSIX locale name.
+func Parse(locale string) (language.Tag, error) {
+	locale, _, _ = strings.Cut(locale, ".") // de_DE.UTF-8
+	locale, _, _ = strings.Cut(locale, "@") // de_DE@euro
+	switch locale {
+	case "", "C", "POSIX":
+		return language.English, nil
+	}
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return language.Und, fmt.Errorf("invalid locale %q: %w", locale, err)
+	}
+	return tag, nil
+}
+
+// Supported returns the languages gt has translations for.
+func Supported() []language.Tag {
+	return append([]language.Tag(nil), supported...)
+}
+
+// Locale returns the current output language.
+func Locale() language.Tag {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Sprintf formats the current language's translation of format.
+func Sprintf(format string, args ...any) string {
+	mu.RLock()
+	p := printer
+	mu.RUnlock()
+	return p.Sprintf(format, args...)
+}
+
+// Fprintf writes the current language's translation of format to w.
+func Fprintf(w io.Writer, format string, args ...any) (int, error) {
+	return io.WriteString(w, Sprintf(format, args...))
+}
@@ -0,0 +1,105 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   language.Tag
+	}{
+		{"", language.English},
+		{"C", language.English},
+		{"POSIX", language.English},
+		{"en_US.UTF-8", language.English},
+		{"de_DE.UTF-8", language.German},
+		{"de_AT@euro", language.German},
+		{"de-CH", language.German},
+		{"es_MX", language.Spanish},
+		{"fr_FR.UTF-8", language.English},
+		{"not a locale!", language.English},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.locale); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestResolvePriority(t *testing.T) {
+	t.Setenv("GT_LOCALE", "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "es_ES.UTF-8")
+
+	if got := Resolve(""); got != "es_ES.UTF-8" {
+		t.Errorf("LANG: Resolve = %q", got)
+	}
+	t.Setenv("LC_MESSAGES", "en_GB")
+	if got := Resolve(""); got != "en_GB" {
+		t.Errorf("LC_MESSAGES over LANG: Resolve = %q", got)
+	}
+	if got := Resolve("de"); got != "de" {
+		t.Errorf("config over environment locale: Resolve = %q", got)
+	}
+	t.Setenv("GT_LOCALE", "es")
+	if got := Resolve("de"); got != "es" {
+		t.Errorf("GT_LOCALE over config: Resolve = %q", got)
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	defer SetLocale("")
+
+	SetLocale("de_DE.UTF-8")
+	if got := Sprintf("%d formulas", 3); got != "3 Formeln" {
+		t.Errorf("German: %q", got)
+	}
+	if got := Sprintf("untranslated %s", "x"); got != "untranslated x" {
+		t.Errorf("fallback: %q", got)
+	}
+
+	SetLocale("")
+	if got := Sprintf("%d formulas", 3); got != "3 formulas" {
+		t.Errorf("English: %q", got)
+	}
+}
+
+var verbRE = regexp.MustCompile(`%[-+# 0]*\d*(?:\.\d+)?[a-zA-Z%]`)
+
+// TestTranslationsKeepVerbs guards against translations that drop, add, or
+// reorder format verbs, which would garble output at runtime.
+func TestTranslationsKeepVerbs(t *testing.T) {
+	for tag, msgs := range translations {
+		for key, msg := range msgs {
+			want, got := verbRE.FindAllString(key, -1), verbRE.FindAllString(msg, -1)
+			if len(want) != len(got) {
+				t.Errorf("%v: %q has verbs %v, translation has %v", tag, key, want, got)
+				continue
+			}
+			for i := range want {
+				if want[i] != got[i] {
+					t.Errorf("%v: %q has verbs %v, translation has %v", tag, key, want, got)
+					break
+				}
+			}
+		}
+	}
+}
+
+// TestTranslationsComplete keeps every language covering the same messages.
+func TestTranslationsComplete(t *testing.T) {
+	for tag, msgs := range translations {
+		for other, otherMsgs := range translations {
+			for key := range otherMsgs {
+				if _, ok := msgs[key]; !ok {
+					t.Errorf("%v is missing %q (translated in %v)", tag, key, other)
+				}
+			}
+		}
+	}
+}
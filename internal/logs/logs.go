@@ -0,0 +1,356 @@
+// Package logs locates and reads the log files of a Gas Town: agent session
+// captures under .runtime/logs/ plus the daemon, Dolt server, and town logs,
+// and the per-leg copies of convoy sessions under .runtime/convoys/.
+package logs
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Dir returns the runtime log directory of a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "logs")
+}
+
+// SessionLogPath returns where an agent's session output is captured.
+// agentID is the agent address, e.g. "gastown/polecats/Toast".
+func SessionLogPath(townRoot, agentID string) string {
+	return filepath.Join(Dir(townRoot), filepath.FromSlash(agentID)+".log")
+}
+
+// ConvoyDir returns where the leg logs of a convoy are written.
+func ConvoyDir(townRoot, convoyID string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "convoys", convoyID)
+}
+
+// LegLogPath returns where the session output of a convoy leg is copied.
+// legID is the formula's leg ID, e.g. "security".
+func LegLogPath(townRoot, convoyID, legID string) string {
+	return filepath.Join(ConvoyDir(townRoot, convoyID), legID+".log")
+}
+
+// LegSources returns the leg logs of a convoy, named by leg ID.
+func LegSources(townRoot, convoyID string) ([]Source, error) {
+	paths, err := filepath.Glob(filepath.Join(ConvoyDir(townRoot, convoyID), "*.log"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	sources := make([]Source, 0, len(paths))
+	for _, path := range paths {
+		sources = append(sources, Source{Name: strings.TrimSuffix(filepath.Base(path), ".log"), Path: path})
+	}
+	return sources, nil
+}
+
+// Source is one log file.
+type Source struct {
+	Name string // Display prefix: agent ID, or "daemon", "dolt", "town"
+	Rig  string // Owning rig; empty for town-level logs
+	Path string
+}
+
+// Sources returns the town's log files: session logs under .runtime/logs
+// followed by the daemon, Dolt server, and town logs. Missing files are
+// omitted.
+func Sources(townRoot string) ([]Source, error) {
+	var sources []Source
+	dir := Dir(townRoot)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".log") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(strings.TrimSuffix(rel, ".log"))
+		src := Source{Name: name, Path: path}
+		if i := strings.Index(name, "/"); i > 0 {
+			src.Rig = name[:i]
+		}
+		sources = append(sources, src)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+
+	for _, src := range []Source{
+		{Name: "daemon", Path: filepath.Join(townRoot, "daemon", "daemon.log")},
+		{Name: "dolt", Path: filepath.Join(townRoot, "daemon", "dolt-server.log")},
+		{Name: "town", Path: filepath.Join(townRoot, "logs", "town.log")},
+	} {
+		if _, err := os.Stat(src.Path); err == nil {
+			sources = append(sources, src)
+		}
+	}
+	return sources, nil
+}
+
+// Line is one line of a log file.
+type Line struct {
+	Source string
+	Time   time.Time // Parsed from the line, or inherited (see Read)
+	Text   string
+}
+
+// timeLayouts are the timestamp prefixes found in town logs: RFC 3339
+// (structured logs), Go's log.LstdFlags (daemon), and the town log format.
+var timeLayouts = []struct {
+	pattern *regexp.Regexp
+	layout  string
+}{
+	{regexp.MustCompile(`^\[?(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}))`), time.RFC3339Nano},
+	{regexp.MustCompile(`^\[?(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`), "2006/01/02 15:04:05"},
+	{regexp.MustCompile(`^\[?(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})`), "2006-01-02 15:04:05"},
+}
+
+// ParseTime extracts a leading timestamp from a log line. Timestamps
+// without a zone are local time.
+func ParseTime(text string) (time.Time, bool) {
+	for _, tl := range timeLayouts {
+		m := tl.pattern.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		if t, err := time.ParseInLocation(tl.layout, m[1], time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Filter selects log lines.
+type Filter struct {
+	Rig    string         // Only sources of this rig
+	Agents []string       // Only these sources (by name prefix); see MentionAny
+	Since  time.Time      // Only lines at or after Since
+	Match  *regexp.Regexp // Only lines matching Match
+
+	// MentionAny also admits lines of sources outside Agents that contain
+	// one of these strings (e.g. a convoy ID in the daemon log).
+	MentionAny []string
+}
+
+// sourceSelected reports whether all lines of src are candidates, and
+// whether src is read at all.
+func (f Filter) sourceSelected(src Source) (all, read bool) {
+	if f.Rig != "" && src.Rig != f.Rig {
+		return false, false
+	}
+	if len(f.Agents) == 0 {
+		return true, true
+	}
+	for _, a := range f.Agents {
+		if src.Name == a || strings.HasPrefix(src.Name, strings.TrimSuffix(a, "/")+"/") {
+			return true, true
+		}
+	}
+	return false, len(f.MentionAny) > 0
+}
+
+// keep reports whether a line of a source passes the filter.
+func (f Filter) keep(line Line, all bool) bool {
+	if !f.Since.IsZero() && line.Time.Before(f.Since) {
+		return false
+	}
+	if f.Match != nil && !f.Match.MatchString(line.Text) {
+		return false
+	}
+	if all {
+		return true
+	}
+	for _, s := range f.MentionAny {
+		if strings.Contains(line.Text, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Read returns the filtered lines of all sources merged in time order.
+// A line without a timestamp inherits the previous line's; leading
+// untimestamped lines get the file's modification time.
+func Read(sources []Source, f Filter) ([]Line, error) {
+	var lines []Line
+	for _, src := range sources {
+		all, read := f.sourceSelected(src)
+		if !read {
+			continue
+		}
+		info, err := os.Stat(src.Path)
+		if err != nil {
+			continue
+		}
+		if !f.Since.IsZero() && info.ModTime().Before(f.Since) {
+			continue // Nothing written since
+		}
+		file, err := os.Open(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		last := time.Time{}
+		var pending []Line // Lines before the first timestamp
+		err = scanLines(file, func(text string) {
+			line := Line{Source: src.Name, Text: text}
+			if t, ok := ParseTime(text); ok {
+				last = t
+			}
+			line.Time = last
+			if last.IsZero() {
+				pending = append(pending, line)
+				return
+			}
+			if f.keep(line, all) {
+				lines = append(lines, line)
+			}
+		})
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range pending {
+			line.Time = info.ModTime()
+			if f.keep(line, all) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	return lines, nil
+}
+
+// scanLines calls fn for each line of r, tolerating long lines.
+func scanLines(r io.Reader, fn func(string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		fn(strings.TrimRight(scanner.Text(), "\r"))
+	}
+	return scanner.Err()
+}
+
+// Tailer follows log files, returning lines appended since the last poll.
+type Tailer struct {
+	sources func() ([]Source, error)
+	filter  Filter
+	offsets map[string]int64
+}
+
+// NewTailer starts following the town's logs from their current ends.
+func NewTailer(townRoot string, f Filter) (*Tailer, error) {
+	return newTailer(func() ([]Source, error) { return Sources(townRoot) }, f)
+}
+
+// NewLegTailer starts following a convoy's leg logs from their current
+// ends. Legs that start later are followed from their first line.
+func NewLegTailer(townRoot, convoyID string, f Filter) (*Tailer, error) {
+	return newTailer(func() ([]Source, error) { return LegSources(townRoot, convoyID) }, f)
+}
+
+func newTailer(sources func() ([]Source, error), f Filter) (*Tailer, error) {
+	t := &Tailer{sources: sources, filter: f, offsets: make(map[string]int64)}
+	current, err := sources()
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range current {
+		if info, err := os.Stat(src.Path); err == nil {
+			t.offsets[src.Path] = info.Size()
+		}
+	}
+	return t, nil
+}
+
+// Poll returns complete lines appended to any log since the last poll,
+// including logs created since. Untimestamped lines are stamped now.
+func (t *Tailer) Poll() ([]Line, error) {
+	sources, err := t.sources()
+	if err != nil {
+		return nil, err
+	}
+	var lines []Line
+	now := time.Now()
+	for _, src := range sources {
+		all, read := t.filter.sourceSelected(src)
+		if !read {
+			continue
+		}
+		text, err := t.readNew(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range text {
+			line := Line{Source: src.Name, Time: now, Text: s}
+			if ts, ok := ParseTime(s); ok {
+				line.Time = ts
+			}
+			// Since has already passed for new lines; only match filters apply.
+			f := t.filter
+			f.Since = time.Time{}
+			if f.keep(line, all) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines, nil
+}
+
+// readNew returns the complete lines appended to path since the last read.
+// A truncated file is read from the start.
+func (t *Tailer) readNew(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := t.offsets[path]
+	if info.Size() < offset {
+		offset = 0
+	}
+	if info.Size() == offset {
+		return nil, nil
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, info.Size()-offset))
+	if err != nil {
+		return nil, err
+	}
+	end := strings.LastIndexByte(string(data), '\n')
+	if end < 0 {
+		return nil, nil // Wait for the line to complete
+	}
+	t.offsets[path] = offset + int64(end) + 1
+	lines := strings.Split(string(data[:end]), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+	return lines, nil
+}
@@ -0,0 +1,131 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func writeLog(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSources(t *testing.T) {
+	town := t.TempDir()
+	writeLog(t, SessionLogPath(town, "gastown/polecats/Toast"), "")
+	writeLog(t, filepath.Join(town, "daemon", "daemon.log"), "")
+
+	sources, err := Sources(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("got %d sources, want 2: %+v", len(sources), sources)
+	}
+	if sources[0].Name != "gastown/polecats/Toast" || sources[0].Rig != "gastown" {
+		t.Errorf("session source = %+v", sources[0])
+	}
+	if sources[1].Name != "daemon" || sources[1].Rig != "" {
+		t.Errorf("daemon source = %+v", sources[1])
+	}
+
+	if sources, err := Sources(t.TempDir()); err != nil || len(sources) != 0 {
+		t.Errorf("empty town: %v, %v", sources, err)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	for _, line := range []string{
+		"2026/01/02 15:04:05 heartbeat",
+		"2026-01-02 15:04:05 [spawn] gastown/Toast",
+		"2026-01-02T15:04:05Z level=info",
+	} {
+		if _, ok := ParseTime(line); !ok {
+			t.Errorf("ParseTime(%q) found no timestamp", line)
+		}
+	}
+	if _, ok := ParseTime("Running tests..."); ok {
+		t.Error("ParseTime matched a line without a timestamp")
+	}
+}
+
+func TestReadMergesAndFilters(t *testing.T) {
+	town := t.TempDir()
+	writeLog(t, filepath.Join(town, "daemon", "daemon.log"),
+		"2026/01/02 10:00:00 daemon started\n2026/01/02 10:02:00 slung hq-leg-1\n2026/01/02 10:03:00 heartbeat\n")
+	writeLog(t, SessionLogPath(town, "gastown/polecats/Toast"),
+		"2026-01-02 10:01:00 session start\nerror: build failed\n")
+	writeLog(t, SessionLogPath(town, "other/polecats/Nux"), "2026-01-02 10:01:30 hello\n")
+
+	sources, err := Sources(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := Read(sources, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"daemon started", "session start", "error: build failed", "hello", "slung hq-leg-1", "heartbeat"}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(lines), len(want), lines)
+	}
+	for i, w := range want {
+		if !regexp.MustCompile(regexp.QuoteMeta(w) + "$").MatchString(lines[i].Text) {
+			t.Errorf("line %d = %q, want %q", i, lines[i].Text, w)
+		}
+	}
+
+	lines, _ = Read(sources, Filter{Rig: "gastown", Match: regexp.MustCompile("error")})
+	if len(lines) != 1 || lines[0].Source != "gastown/polecats/Toast" {
+		t.Errorf("rig+grep filter = %+v", lines)
+	}
+
+	lines, _ = Read(sources, Filter{Agents: []string{"gastown/polecats/Toast"}, MentionAny: []string{"hq-leg-1"}})
+	if len(lines) != 3 {
+		t.Errorf("convoy filter = %+v, want Toast's 2 lines and the daemon mention", lines)
+	}
+
+	since := time.Date(2026, 1, 2, 10, 2, 30, 0, time.Local)
+	lines, _ = Read(sources, Filter{Since: since})
+	if len(lines) != 1 || lines[0].Source != "daemon" {
+		t.Errorf("since filter = %+v", lines)
+	}
+}
+
+func TestTailerPoll(t *testing.T) {
+	town := t.TempDir()
+	path := SessionLogPath(town, "gastown/polecats/Toast")
+	writeLog(t, path, "old line\n")
+
+	tailer, err := NewTailer(town, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("new line\npartial")
+	_ = f.Close()
+	writeLog(t, filepath.Join(town, "daemon", "daemon.log"), "2026/01/02 10:00:00 started\n")
+
+	lines, err := tailer.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 || lines[0].Text != "new line" || lines[1].Source != "daemon" {
+		t.Errorf("Poll = %+v, want the new complete lines", lines)
+	}
+	if lines, _ := tailer.Poll(); len(lines) != 0 {
+		t.Errorf("second Poll = %+v, want nothing new", lines)
+	}
+}
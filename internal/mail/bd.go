@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // bdError represents an error from running a bd command.
@@ -55,7 +56,7 @@ func runBdCommand(args []string, workDir, beadsDir string, extraEnv ...string) (
 
 	// Limit concurrent bd processes to prevent dolt embedded lock contention.
 	beads.AcquireBd()
-	runErr := cmd.Run()
+	runErr := cmdtrace.Run(cmd)
 	beads.ReleaseBd()
 
 	if runErr != nil {
@@ -0,0 +1,125 @@
+// Package maintenance tracks the town's maintenance windows and the runs
+// queued while one is open.
+//
+// Windows are configured in the town's settings/config.json (see
+// config.MaintenanceConfig). Commands that would start work during a
+// window append themselves to the queue instead; the daemon replays the
+// queue on its first heartbeat after the window closes.
+package maintenance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Run is a command queued during a maintenance window.
+type Run struct {
+	// Args are the gt arguments to replay, without the program name.
+	Args []string `json:"args"`
+
+	// Dir is the working directory the command was run from.
+	Dir string `json:"dir"`
+
+	// Window names the window the run was queued in.
+	Window string `json:"window,omitempty"`
+
+	// QueuedAt is when the run was queued.
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// Active returns the maintenance window open at now in the town, and when
+// it closes (zero if unknown). It returns nil outside windows or if the
+// town settings can't be read.
+func Active(townRoot string, now time.Time) (*config.MaintenanceWindow, time.Time) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, time.Time{}
+	}
+	return settings.Maintenance.ActiveWindow(now)
+}
+
+// QueueFile returns the path of the town's queue of deferred runs.
+func QueueFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "maintenance-queue.jsonl")
+}
+
+// Enqueue appends a run to the town's queue.
+func Enqueue(townRoot string, run Run) error {
+	if run.QueuedAt.IsZero() {
+		run.QueuedAt = time.Now().UTC()
+	}
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	path := QueueFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Pending returns the queued runs, oldest first.
+func Pending(townRoot string) ([]Run, error) {
+	return readQueue(QueueFile(townRoot))
+}
+
+// Take removes the queue and returns its runs, oldest first. Runs queued
+// while Take is reading start a new queue.
+func Take(townRoot string) ([]Run, error) {
+	path := QueueFile(townRoot)
+	taken := path + ".taken"
+	if err := os.Rename(path, taken); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	runs, err := readQueue(taken)
+	if err != nil {
+		return nil, err
+	}
+	return runs, os.Remove(taken)
+}
+
+// readQueue reads a queue file. A missing file is an empty queue; lines
+// that don't parse are skipped.
+func readQueue(path string) ([]Run, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil || len(run.Args) == 0 {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,69 @@
+package maintenance
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestQueueRoundTrip(t *testing.T) {
+	town := t.TempDir()
+
+	if runs, err := Take(town); err != nil || runs != nil {
+		t.Fatalf("Take() on empty queue = %v, %v", runs, err)
+	}
+
+	first := Run{Args: []string{"formula", "run", "code-review", "--pr=1"}, Dir: town, Window: "weekly"}
+	second := Run{Args: []string{"plugin", "run", "rebuild-gt"}, Dir: town}
+	for _, run := range []Run{first, second} {
+		if err := Enqueue(town, run); err != nil {
+			t.Fatalf("Enqueue() error: %v", err)
+		}
+	}
+
+	pending, err := Pending(town)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("Pending() = %v, %v; want 2 runs", pending, err)
+	}
+	if !reflect.DeepEqual(pending[0].Args, first.Args) || pending[0].QueuedAt.IsZero() {
+		t.Errorf("first run = %+v", pending[0])
+	}
+
+	taken, err := Take(town)
+	if err != nil || len(taken) != 2 || !reflect.DeepEqual(taken[1].Args, second.Args) {
+		t.Fatalf("Take() = %v, %v", taken, err)
+	}
+	if _, err := os.Stat(QueueFile(town)); !os.IsNotExist(err) {
+		t.Errorf("queue file still present after Take: %v", err)
+	}
+	if pending, _ := Pending(town); len(pending) != 0 {
+		t.Errorf("Pending() after Take = %v", pending)
+	}
+}
+
+func TestActive(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC) // a Saturday
+
+	if w, _ := Active(town, now); w != nil {
+		t.Fatalf("Active() without settings = %v", w)
+	}
+
+	settings := config.NewTownSettings()
+	settings.Maintenance = &config.MaintenanceConfig{Windows: []config.MaintenanceWindow{
+		{Name: "weekly", Cron: "* 2-4 * * SAT", Timezone: "UTC"},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+	w, end := Active(town, now)
+	if w == nil || w.Name != "weekly" {
+		t.Fatalf("Active() = %v, want weekly", w)
+	}
+	if want := time.Date(2026, 10, 17, 5, 0, 0, 0, time.UTC); !end.Equal(want) {
+		t.Errorf("end = %v, want %v", end, want)
+	}
+}
@@ -0,0 +1,81 @@
+package mayor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TownPause represents the town pause file contents. While the town is
+// paused, no new work is dispatched: gt sling, gt formula run, scheduled
+// plugin runs, and PR watch re-runs refuse unless explicitly overridden.
+// Running agents and infrastructure are left alone.
+type TownPause struct {
+	// Paused is true if dispatch is currently paused.
+	Paused bool `json:"paused"`
+
+	// Reason explains why the town was paused (e.g., an incident link).
+	Reason string `json:"reason,omitempty"`
+
+	// PausedAt is when the town was paused.
+	PausedAt time.Time `json:"paused_at"`
+
+	// PausedBy identifies who paused the town (e.g., "human", "mayor").
+	PausedBy string `json:"paused_by,omitempty"`
+}
+
+// GetTownPauseFile returns the path to the town pause file.
+func GetTownPauseFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "paused.json")
+}
+
+// IsTownPaused checks if dispatch is paused town-wide.
+// Returns (isPaused, pauseState, error).
+// If the pause file doesn't exist, returns (false, nil, nil).
+func IsTownPaused(townRoot string) (bool, *TownPause, error) {
+	data, err := os.ReadFile(GetTownPauseFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+
+	var state TownPause
+	if err := json.Unmarshal(data, &state); err != nil {
+		return false, nil, err
+	}
+
+	return state.Paused, &state, nil
+}
+
+// PauseTown pauses dispatch town-wide by creating the pause file.
+func PauseTown(townRoot, reason, pausedBy string) error {
+	pauseFile := GetTownPauseFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(pauseFile), 0755); err != nil {
+		return err
+	}
+
+	state := TownPause{
+		Paused:   true,
+		Reason:   reason,
+		PausedAt: time.Now().UTC(),
+		PausedBy: pausedBy,
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(pauseFile, data, 0600)
+}
+
+// ResumeTown resumes dispatch by removing the pause file.
+func ResumeTown(townRoot string) error {
+	err := os.Remove(GetTownPauseFile(townRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
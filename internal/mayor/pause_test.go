@@ -0,0 +1,51 @@
+package mayor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTownPauseRoundTrip(t *testing.T) {
+	town := t.TempDir()
+
+	paused, state, err := IsTownPaused(town)
+	if err != nil || paused || state != nil {
+		t.Fatalf("IsTownPaused() on fresh town = %v, %v, %v; want false, nil, nil", paused, state, err)
+	}
+
+	if err := PauseTown(town, "incident 42", "human"); err != nil {
+		t.Fatalf("PauseTown() error: %v", err)
+	}
+	paused, state, err = IsTownPaused(town)
+	if err != nil || !paused {
+		t.Fatalf("IsTownPaused() after pause = %v, %v; want true, nil", paused, err)
+	}
+	if state.Reason != "incident 42" || state.PausedBy != "human" || state.PausedAt.IsZero() {
+		t.Errorf("pause state = %+v", state)
+	}
+
+	if err := ResumeTown(town); err != nil {
+		t.Fatalf("ResumeTown() error: %v", err)
+	}
+	if paused, _, _ := IsTownPaused(town); paused {
+		t.Error("IsTownPaused() after resume = true")
+	}
+	if err := ResumeTown(town); err != nil {
+		t.Errorf("ResumeTown() on unpaused town: %v", err)
+	}
+}
+
+func TestIsTownPausedCorruptFile(t *testing.T) {
+	town := t.TempDir()
+	path := GetTownPauseFile(town)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := IsTownPaused(town); err == nil {
+		t.Error("IsTownPaused() with corrupt file: want error")
+	}
+}
@@ -0,0 +1,99 @@
+package mayor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// rigMarkers are subdirectories whose presence suggests a directory is a rig.
+var rigMarkers = []string{"crew", "polecats", "witness", "refinery"}
+
+// townDirs are top-level town directories that are never rigs.
+var townDirs = map[string]bool{
+	"mayor":    true,
+	"deacon":   true,
+	"settings": true,
+	"plugins":  true,
+	"docs":     true,
+}
+
+// RigCandidate is a town directory that looks like a rig but isn't registered.
+type RigCandidate struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Reasons []string `json:"reasons"`
+}
+
+// FindUnregisteredRigs scans the town root for directories that look like
+// rigs (rig markers, a rig config.json, or a git clone) but are not in the
+// registered set. Results are sorted by name.
+func FindUnregisteredRigs(townRoot string, registered map[string]bool) ([]RigCandidate, error) {
+	entries, err := os.ReadDir(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []RigCandidate
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || townDirs[name] || registered[name] {
+			continue
+		}
+
+		path := filepath.Join(townRoot, name)
+		if reasons := rigReasons(path); len(reasons) > 0 {
+			candidates = append(candidates, RigCandidate{Name: name, Path: path, Reasons: reasons})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+	return candidates, nil
+}
+
+// rigReasons explains why a directory looks like a rig.
+func rigReasons(path string) []string {
+	var reasons []string
+
+	if isRigConfig(filepath.Join(path, "config.json")) {
+		reasons = append(reasons, "has rig config.json")
+	}
+	for _, marker := range rigMarkers {
+		if info, err := os.Stat(filepath.Join(path, marker)); err == nil && info.IsDir() {
+			reasons = append(reasons, "has "+marker+"/")
+		}
+	}
+	if _, err := os.Stat(filepath.Join(path, "mayor", "rig", ".git")); err == nil {
+		reasons = append(reasons, "has mayor/rig clone")
+	} else if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		reasons = append(reasons, "is a git repository")
+	}
+
+	return reasons
+}
+
+// isRigConfig reports whether path is a config.json with type "rig".
+func isRigConfig(path string) bool {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town root
+	if err != nil {
+		return false
+	}
+	var cfg struct {
+		Type string `json:"type"`
+	}
+	return json.Unmarshal(data, &cfg) == nil && cfg.Type == "rig"
+}
+
+// MissingRigs returns registered rig names whose directories don't exist.
+func MissingRigs(townRoot string, registered []string) []string {
+	var missing []string
+	for _, name := range registered {
+		if info, err := os.Stat(filepath.Join(townRoot, name)); err != nil || !info.IsDir() {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
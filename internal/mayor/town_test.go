@@ -0,0 +1,60 @@
+package mayor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindUnregisteredRigs(t *testing.T) {
+	town := t.TempDir()
+	mkdir := func(parts ...string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(append([]string{town}, parts...)...), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mkdir("registered", "crew")
+	mkdir("orphan", "polecats")
+	mkdir("orphan", "witness")
+	mkdir("plainrepo", ".git")
+	mkdir("mayor", "rig", ".git") // town dir, never a rig
+	mkdir(".hidden", "crew")
+	mkdir("notes")
+	mkdir("configured")
+	if err := os.WriteFile(filepath.Join(town, "configured", "config.json"), []byte(`{"type":"rig"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FindUnregisteredRigs(town, map[string]bool{"registered": true})
+	if err != nil {
+		t.Fatalf("FindUnregisteredRigs() error = %v", err)
+	}
+
+	var names []string
+	for _, c := range got {
+		names = append(names, c.Name)
+	}
+	want := []string{"configured", "orphan", "plainrepo"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("candidates = %v, want %v", names, want)
+	}
+	if len(got[1].Reasons) != 2 {
+		t.Errorf("orphan reasons = %v, want polecats/ and witness/", got[1].Reasons)
+	}
+}
+
+func TestMissingRigs(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "present"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := MissingRigs(town, []string{"present", "gone_b", "gone_a"})
+	want := []string{"gone_a", "gone_b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingRigs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,175 @@
+// Package migrate applies versioned town layout migrations.
+//
+// Each town records the layout version it has been migrated to in
+// settings/config.json (layout_version). Steps are ordered by version and
+// applied one at a time; the recorded version is bumped after each step so
+// an interrupted run resumes where it stopped. Steps must be idempotent and
+// should move rather than delete user data.
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Context is passed to each migration step.
+type Context struct {
+	TownRoot string
+	RigPaths []string // Absolute paths of registered rigs
+	DryRun   bool     // Report actions without changing anything
+}
+
+// Step is one layout migration.
+type Step struct {
+	Version     int
+	Name        string
+	Description string
+	// Up applies the step and returns a description of each action taken
+	// (or, with DryRun, that would be taken). No actions means the town
+	// already matched.
+	Up func(ctx *Context) ([]string, error)
+}
+
+// Result is the outcome of one applied step.
+type Result struct {
+	Step    Step
+	Actions []string
+}
+
+// steps is the ordered list of migrations. Append new steps with the next
+// version number; never renumber or remove a released step.
+var steps = []Step{
+	{
+		Version:     1,
+		Name:        "archive-legacy-gastown",
+		Description: "Move legacy .gastown/ directories into .runtime/legacy-gastown/",
+		Up:          archiveLegacyGastown,
+	},
+	{
+		Version:     2,
+		Name:        "runtime-gitignore",
+		Description: "Write the gt-managed .gitignore block in the town, rig, and crew clones",
+		Up:          syncRuntimeGitignore,
+	},
+}
+
+// Steps returns all migration steps in order.
+func Steps() []Step {
+	out := append([]Step(nil), steps...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
+
+// Latest returns the version a fully migrated town is at.
+func Latest() int {
+	latest := 0
+	for _, s := range steps {
+		if s.Version > latest {
+			latest = s.Version
+		}
+	}
+	return latest
+}
+
+// CurrentVersion returns the layout version recorded for the town.
+// Towns that predate the migration framework are at version 0.
+func CurrentVersion(townRoot string) (int, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return 0, fmt.Errorf("loading town settings: %w", err)
+	}
+	return settings.LayoutVersion, nil
+}
+
+// Pending returns the steps not yet applied to the town.
+func Pending(townRoot string) ([]Step, error) {
+	current, err := CurrentVersion(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Step
+	for _, s := range Steps() {
+		if s.Version > current {
+			pending = append(pending, s)
+		}
+	}
+	return pending, nil
+}
+
+// Apply runs pending steps up to and including version to (0 means all).
+// Each step's version is recorded as soon as it succeeds. With dryRun,
+// steps report their actions and nothing is recorded.
+func Apply(townRoot string, to int, dryRun bool) ([]Result, error) {
+	pending, err := Pending(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	ctx := &Context{TownRoot: townRoot, RigPaths: registeredRigPaths(townRoot), DryRun: dryRun}
+
+	var results []Result
+	for _, s := range pending {
+		if to > 0 && s.Version > to {
+			break
+		}
+		actions, err := s.Up(ctx)
+		if err != nil {
+			return results, fmt.Errorf("migration %d (%s): %w", s.Version, s.Name, err)
+		}
+		results = append(results, Result{Step: s, Actions: actions})
+		if dryRun {
+			continue
+		}
+		if err := recordVersion(townRoot, s.Version); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// MarkCurrent records the latest layout version, for freshly created towns
+// that already have the current layout.
+func MarkCurrent(townRoot string) error {
+	return recordVersion(townRoot, Latest())
+}
+
+// recordVersion stores the layout version in town settings.
+func recordVersion(townRoot string, version int) error {
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	settings.LayoutVersion = version
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return fmt.Errorf("recording layout version %d: %w", version, err)
+	}
+	return nil
+}
+
+// registeredRigPaths returns the absolute paths of rigs in mayor/rigs.json
+// that exist on disk.
+func registeredRigPaths(townRoot string) []string {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var paths []string
+	for _, name := range names {
+		path := filepath.Join(townRoot, name)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStepsOrderedAndUnique(t *testing.T) {
+	seen := make(map[int]bool)
+	prev := 0
+	for _, s := range Steps() {
+		if s.Version <= prev {
+			t.Errorf("step %s: version %d not after %d", s.Name, s.Version, prev)
+		}
+		if seen[s.Version] {
+			t.Errorf("duplicate version %d", s.Version)
+		}
+		seen[s.Version] = true
+		prev = s.Version
+	}
+	if Latest() != prev {
+		t.Errorf("Latest() = %d, want %d", Latest(), prev)
+	}
+}
+
+func TestApply(t *testing.T) {
+	townRoot := t.TempDir()
+	legacy := filepath.Join(townRoot, ".gastown")
+	if err := os.MkdirAll(legacy, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "state.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, err := CurrentVersion(townRoot); err != nil || v != 0 {
+		t.Fatalf("CurrentVersion() = %d, %v; want 0", v, err)
+	}
+
+	// Dry run reports actions but changes nothing.
+	results, err := Apply(townRoot, 0, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(results) != len(Steps()) || len(results[0].Actions) != 1 {
+		t.Fatalf("dry run results = %+v", results)
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		t.Error("dry run moved .gastown/")
+	}
+	if v, _ := CurrentVersion(townRoot); v != 0 {
+		t.Errorf("dry run recorded version %d", v)
+	}
+
+	// --to stops after the given version.
+	if _, err := Apply(townRoot, 1, false); err != nil {
+		t.Fatalf("apply --to 1: %v", err)
+	}
+	if v, _ := CurrentVersion(townRoot); v != 1 {
+		t.Errorf("version after --to 1 = %d", v)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, ".runtime", "legacy-gastown", "state.json")); err != nil {
+		t.Errorf("legacy dir not archived: %v", err)
+	}
+
+	if _, err := Apply(townRoot, 0, false); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if v, _ := CurrentVersion(townRoot); v != Latest() {
+		t.Errorf("version after apply = %d, want %d", v, Latest())
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, ".gitignore")); err != nil {
+		t.Errorf("gitignore not written: %v", err)
+	}
+
+	pending, err := Pending(townRoot)
+	if err != nil || len(pending) != 0 {
+		t.Errorf("Pending() = %v, %v; want none", pending, err)
+	}
+}
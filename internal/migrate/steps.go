@@ -0,0 +1,67 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/gitignore"
+)
+
+// archiveLegacyGastown moves .gastown/ directories left by early versions
+// into .runtime/legacy-gastown/ at the same level, out of the way of the
+// current layout but still recoverable.
+func archiveLegacyGastown(ctx *Context) ([]string, error) {
+	var actions []string
+	for _, dir := range append([]string{ctx.TownRoot}, ctx.RigPaths...) {
+		legacy := filepath.Join(dir, ".gastown")
+		info, err := os.Stat(legacy)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		dest := filepath.Join(dir, constants.DirRuntime, "legacy-gastown")
+		if _, err := os.Stat(dest); err == nil {
+			return actions, fmt.Errorf("%s already exists; move %s by hand", dest, legacy)
+		}
+		actions = append(actions, fmt.Sprintf("move %s → %s", relTo(ctx.TownRoot, legacy), relTo(ctx.TownRoot, dest)))
+		if ctx.DryRun {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return actions, fmt.Errorf("creating %s: %w", filepath.Dir(dest), err)
+		}
+		if err := os.Rename(legacy, dest); err != nil {
+			return actions, fmt.Errorf("moving %s: %w", legacy, err)
+		}
+	}
+	return actions, nil
+}
+
+// syncRuntimeGitignore writes the gt-managed .gitignore block wherever
+// required patterns are missing.
+func syncRuntimeGitignore(ctx *Context) ([]string, error) {
+	var actions []string
+	for _, target := range gitignore.Targets(ctx.TownRoot, ctx.RigPaths) {
+		missing := gitignore.Missing(target.Path(), gitignore.RequiredPatterns)
+		if len(missing) == 0 {
+			continue
+		}
+		actions = append(actions, fmt.Sprintf("update %s", relTo(ctx.TownRoot, target.Path())))
+		if ctx.DryRun {
+			continue
+		}
+		if _, err := gitignore.Sync(target.Path(), gitignore.RequiredPatterns); err != nil {
+			return actions, err
+		}
+	}
+	return actions, nil
+}
+
+// relTo returns path relative to the town root for display.
+func relTo(townRoot, path string) string {
+	if rel, err := filepath.Rel(townRoot, path); err == nil {
+		return rel
+	}
+	return path
+}
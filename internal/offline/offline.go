@@ -0,0 +1,43 @@
+// Package offline implements gt's offline mode, turned on by the global
+// --offline flag or GT_OFFLINE. In offline mode gt makes no calls to
+// GitHub or other source hosts: gh and the REST providers fail at once
+// instead of waiting on network timeouts, and commands fall back to what
+// the local clone has, noting what context was unavailable.
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvVar turns on offline mode. gt sets it when --offline is given, so gt
+// processes started from an offline command inherit it.
+const EnvVar = "GT_OFFLINE"
+
+// ErrOffline is returned for operations that need the network.
+var ErrOffline = errors.New("offline mode")
+
+// Enabled reports whether offline mode is on.
+func Enabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvVar))) {
+	case "", "0", "false", "no", "off":
+		return false
+	}
+	return true
+}
+
+// Enable turns offline mode on for this process and its children.
+func Enable() {
+	_ = os.Setenv(EnvVar, "1")
+}
+
+// Check returns an ErrOffline error describing action if offline mode is
+// on, and nil otherwise.
+func Check(action string) error {
+	if !Enabled() {
+		return nil
+	}
+	return fmt.Errorf("%w: not trying to %s", ErrOffline, action)
+}
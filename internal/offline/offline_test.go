@@ -0,0 +1,24 @@
+package offline
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "0": false, "off": false, "1": true, "true": true, "yes": true} {
+		t.Setenv(EnvVar, value)
+		if got := Enabled(); got != want {
+			t.Errorf("Enabled() with %s=%q = %v, want %v", EnvVar, value, got, want)
+		}
+	}
+
+	t.Setenv(EnvVar, "")
+	if err := Check("call GitHub"); err != nil {
+		t.Errorf("Check() = %v outside offline mode", err)
+	}
+	Enable()
+	if err := Check("call GitHub"); !errors.Is(err, ErrOffline) {
+		t.Errorf("Check() = %v, want ErrOffline", err)
+	}
+}
@@ -0,0 +1,140 @@
+// Package optree describes what a command is about to do as a tree of
+// operations and the subprocesses they run, for the global --explain flag.
+// It sits between --dry-run, which says what will happen without detail,
+// and verbose output, which says what happened after the fact.
+package optree
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// maxArgLen bounds how much of a single argument is shown; long values
+// such as bead descriptions are elided.
+const maxArgLen = 60
+
+// Command is a subprocess an operation runs.
+type Command struct {
+	Args []string
+	Dir  string // Working directory; empty for the current one
+}
+
+// Cmd returns a Command running args in the current directory.
+func Cmd(args ...string) Command {
+	return Command{Args: args}
+}
+
+// In returns a Command running args in dir.
+func In(dir string, args ...string) Command {
+	return Command{Args: args, Dir: dir}
+}
+
+// placeholderRE matches a <placeholder> standing in for a value only known
+// once the command runs, such as a generated bead ID.
+var placeholderRE = regexp.MustCompile(`<[A-Za-z0-9_.-]+>`)
+
+// String returns the command as a shell line, with arguments quoted and
+// long or multi-line arguments shortened. Placeholders are left unquoted.
+func (c Command) String() string {
+	parts := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		arg = shorten(arg)
+		if bare := placeholderRE.ReplaceAllString(arg, "x"); bare != arg && config.ShellQuote(bare) == bare {
+			parts[i] = arg
+			continue
+		}
+		parts[i] = config.ShellQuote(arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// shorten cuts an argument at its first newline or maxArgLen runes.
+func shorten(arg string) string {
+	cut := false
+	if i := strings.IndexByte(arg, '\n'); i >= 0 {
+		arg, cut = arg[:i], true
+	}
+	if utf8.RuneCountInString(arg) > maxArgLen {
+		arg, cut = string([]rune(arg)[:maxArgLen]), true
+	}
+	if cut {
+		arg += "…"
+	}
+	return arg
+}
+
+// Node is an operation in a plan. Its children are the steps it takes
+// and the subprocesses it runs.
+type Node struct {
+	Label    string
+	Command  *Command // Set when the node is a subprocess invocation
+	Children []*Node
+}
+
+// New starts a plan with the given root label, typically the command line.
+func New(format string, args ...interface{}) *Node {
+	return &Node{Label: fmt.Sprintf(format, args...)}
+}
+
+// Step adds an operation under n and returns it.
+func (n *Node) Step(format string, args ...interface{}) *Node {
+	child := &Node{Label: fmt.Sprintf(format, args...)}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// Run adds a subprocess invocation under n and returns it.
+func (n *Node) Run(c Command) *Node {
+	child := &Node{Command: &c}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// Render writes the plan as a tree.
+func (n *Node) Render(w io.Writer) {
+	fmt.Fprintln(w, n.line())
+	n.renderChildren(w, "")
+}
+
+func (n *Node) renderChildren(w io.Writer, prefix string) {
+	for i, child := range n.Children {
+		connector, indent := "├── ", "│   "
+		if i == len(n.Children)-1 {
+			connector, indent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s\n", prefix, connector, child.line())
+		child.renderChildren(w, prefix+indent)
+	}
+}
+
+// line is the node's own text: its label, or the command line it runs.
+func (n *Node) line() string {
+	if n.Command == nil {
+		return n.Label
+	}
+	s := "$ " + n.Command.String()
+	if n.Command.Dir != "" {
+		s += "  (in " + n.Command.Dir + ")"
+	}
+	if n.Label != "" {
+		s += "  # " + n.Label
+	}
+	return s
+}
+
+// Commands returns the number of subprocess invocations in the plan.
+func (n *Node) Commands() int {
+	count := 0
+	if n.Command != nil {
+		count++
+	}
+	for _, child := range n.Children {
+		count += child.Commands()
+	}
+	return count
+}
@@ -0,0 +1,48 @@
+package optree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	plan := New("gt rig restart %s", "gastown")
+	stop := plan.Step("Stop")
+	stop.Run(Cmd("tmux", "kill-session", "-t", "gt-gastown-witness"))
+	stop.Run(In("/town/.beads", "bd", "close", "gt-1", "--reason", "it's done"))
+	plan.Step("Start").Step("Start witness")
+
+	var b strings.Builder
+	plan.Render(&b)
+	want := `gt rig restart gastown
+├── Stop
+│   ├── $ tmux kill-session -t gt-gastown-witness
+│   └── $ bd close gt-1 --reason 'it'\''s done'  (in /town/.beads)
+└── Start
+    └── Start witness
+`
+	if b.String() != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", b.String(), want)
+	}
+	if n := plan.Commands(); n != 2 {
+		t.Errorf("Commands() = %d, want 2", n)
+	}
+}
+
+func TestCommandString_ShortensLongArgs(t *testing.T) {
+	c := Cmd("bd", "create", "--description=first line\nsecond line", "--title="+strings.Repeat("x", 80))
+	got := c.String()
+	if strings.Contains(got, "second line") {
+		t.Errorf("String() = %q, want multi-line argument cut at the newline", got)
+	}
+	if !strings.Contains(got, "'--description=first line…'") {
+		t.Errorf("String() = %q, want shortened description", got)
+	}
+	if strings.Contains(got, strings.Repeat("x", 80)) {
+		t.Errorf("String() = %q, want long title elided", got)
+	}
+
+	if got := Cmd("gt", "sling", "hq-leg-<a>", "--file", "<dir>/<a>.json", "<a b>").String(); got != "gt sling hq-leg-<a> --file <dir>/<a>.json '<a b>'" {
+		t.Errorf("String() = %q, want placeholders unquoted", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // RunResult represents the outcome of a plugin execution.
@@ -87,7 +88,7 @@ func (r *Recorder) RecordRun(record PluginRunRecord) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return "", fmt.Errorf("creating plugin run bead: %s: %w", stderr.String(), err)
 	}
 
@@ -153,7 +154,7 @@ func (r *Recorder) queryRuns(pluginName string, limit int, since string) ([]*Plu
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		// Empty result is OK (no runs found)
 		if stderr.Len() == 0 || stdout.String() == "[]\n" {
 			return nil, nil
@@ -0,0 +1,96 @@
+package polecat
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+)
+
+// containerBinDir is where host gt and bd binaries are mounted in a leg
+// container; it is on PATH in common base images.
+const containerBinDir = "/usr/local/bin"
+
+// containerHostMounts returns the host paths an agent needs inside its
+// container besides its worktree, as "src:dst[:ro]" binds: the repository's
+// shared git directory (a worktree's .git file points into it), the town
+// and rig beads that gt and bd read and write, and, on Linux hosts, the gt
+// and bd binaries. It also returns the binaries it couldn't mount, which
+// the image itself must provide.
+//
+// The shared git directory is writable, since commits land in it, but its
+// hooks/ and config are mounted read-only over it so an agent can't plant
+// a hook or alias that the host's git would later run outside the
+// container.
+func containerHostMounts(townRoot, rigPath, workDir string) (mounts, unmounted []string) {
+	seen := make(map[string]bool)
+	bind := func(src, dst, mode string) {
+		if src == "" || seen[dst] || dst == workDir {
+			return
+		}
+		if _, err := os.Stat(src); err != nil {
+			return
+		}
+		seen[dst] = true
+		m := src + ":" + dst
+		if mode != "" {
+			m += ":" + mode
+		}
+		mounts = append(mounts, m)
+	}
+
+	gitCmd := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-common-dir")
+	gitCmd.Dir = workDir
+	if out, err := cmdtrace.Output(gitCmd); err == nil {
+		dir := strings.TrimSpace(string(out))
+		if !strings.HasPrefix(dir+string(filepath.Separator), workDir+string(filepath.Separator)) {
+			bind(dir, dir, "")
+			for _, name := range []string{"hooks", "config"} {
+				bind(filepath.Join(dir, name), filepath.Join(dir, name), "ro")
+			}
+		}
+	}
+
+	for _, dir := range []string{
+		filepath.Join(townRoot, ".beads"),
+		filepath.Join(rigPath, ".beads"),
+		filepath.Join(rigPath, "mayor", "rig", ".beads"),
+	} {
+		bind(dir, dir, "")
+	}
+
+	for _, bin := range []string{"gt", "bd"} {
+		path, err := exec.LookPath(bin)
+		if err != nil || goruntime.GOOS != "linux" {
+			unmounted = append(unmounted, bin)
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		bind(path, filepath.Join(containerBinDir, bin), "ro")
+	}
+	return mounts, unmounted
+}
+
+// checkContainerImage verifies image provides the binaries that couldn't be
+// mounted from the host, so a leg fails at start instead of mid-run.
+func checkContainerImage(image string, bins []string) error {
+	if len(bins) == 0 {
+		return nil
+	}
+	var checks []string
+	for _, bin := range bins {
+		checks = append(checks, "command -v "+bin+" >/dev/null")
+	}
+	cmd := exec.Command("docker", "run", "--rm", "--entrypoint", "sh", image, "-c", strings.Join(checks, " && "))
+	if err := cmdtrace.Run(cmd); err != nil {
+		return fmt.Errorf("image %s must provide %s (the host binaries can't be mounted here): %w",
+			image, strings.Join(bins, " and "), err)
+	}
+	return nil
+}
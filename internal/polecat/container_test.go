@@ -0,0 +1,64 @@
+package polecat
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContainerHostMounts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	rigPath := setupPoolRig(t, "0")
+	townRoot := filepath.Dir(rigPath)
+	if err := os.MkdirAll(filepath.Join(townRoot, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	workDir := filepath.Join(rigPath, "polecats", "Toast", "rig")
+	add := exec.Command("git", "--git-dir=.repo.git", "worktree", "add", "--detach", workDir, "origin/main")
+	add.Dir = rigPath
+	if out, err := add.CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, out)
+	}
+
+	binDir := t.TempDir()
+	for _, bin := range []string{"gt", "bd"} {
+		if err := os.WriteFile(filepath.Join(binDir, bin), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	mounts, unmounted := containerHostMounts(townRoot, rigPath, workDir)
+	got := strings.Join(mounts, "\n")
+
+	repoGit, err := filepath.EvalSymlinks(filepath.Join(rigPath, ".repo.git"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	beadsDir := filepath.Join(townRoot, ".beads")
+	repoConfig := filepath.Join(repoGit, "config")
+	for _, want := range []string{repoGit + ":" + repoGit, repoConfig + ":" + repoConfig + ":ro", beadsDir + ":" + beadsDir} {
+		if !strings.Contains(got, want) {
+			t.Errorf("mounts missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, filepath.Join(rigPath, "mayor")) {
+		t.Errorf("mounted a path that doesn't exist:\n%s", got)
+	}
+
+	for _, bin := range []string{"gt", "bd"} {
+		mounted := strings.Contains(got, ":/usr/local/bin/"+bin+":ro")
+		skipped := false
+		for _, u := range unmounted {
+			skipped = skipped || u == bin
+		}
+		if mounted == skipped {
+			t.Errorf("%s: mounted = %v, unmounted = %v; want exactly one", bin, mounted, skipped)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -16,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -169,15 +169,23 @@ func (m *Manager) checkCleanupStatus(name string, status CleanupStatus, force bo
 // Prefers the shared bare repo (.repo.git) if it exists, otherwise falls back to mayor/rig.
 // The bare repo architecture allows all worktrees (refinery, polecats) to share branch visibility.
 func (m *Manager) repoBase() (*git.Git, error) {
+	return repoBaseAt(m.rig.Path)
+}
+
+// repoBaseAt is repoBase for the rig at rigPath.
+func repoBaseAt(rigPath string) (*git.Git, error) {
 	// First check for shared bare repo (new architecture)
-	bareRepoPath := filepath.Join(m.rig.Path, ".repo.git")
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
 	if info, err := os.Stat(bareRepoPath); err == nil && info.IsDir() {
-		// Bare repo exists - use it
-		return git.NewGitWithDir(bareRepoPath, ""), nil
+		// Bare repo exists - use it. Its worktrees check out the rig's
+		// clone.sparse subset; best-effort, a stale subset is harmless.
+		repo := git.NewGitWithDir(bareRepoPath, "")
+		_ = rig.SyncSparsePaths(rigPath, repo)
+		return repo, nil
 	}
 
 	// Fall back to mayor/rig (legacy architecture)
-	mayorPath := filepath.Join(m.rig.Path, "mayor", "rig")
+	mayorPath := filepath.Join(rigPath, "mayor", "rig")
 	if _, err := os.Stat(mayorPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("no repo base found (neither .repo.git nor mayor/rig exists)")
 	}
@@ -394,10 +402,13 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
-	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
-		cleanupOnError()
-		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics.
+	// A ready worktree from the rig's pool skips the checkout.
+	if !NewWorktreePool(m.rig.Path).Take(clonePath, branchName, startPoint) {
+		if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+			cleanupOnError()
+			return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+		}
 	}
 
 	// Ensure AGENTS.md exists - critical for polecats to "land the plane"
@@ -592,18 +603,23 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear, selfNuke bool)
 		return os.RemoveAll(polecatDir)
 	}
 
-	// Try to remove as a worktree first (use force flag for worktree removal too)
-	if err := repoGit.WorktreeRemove(clonePath, force); err != nil {
-		// Fall back to direct removal if worktree removal fails
-		// (e.g., if this is an old-style clone, not a worktree)
-		if removeErr := os.RemoveAll(clonePath); removeErr != nil {
-			return fmt.Errorf("removing clone path: %w", removeErr)
+	// Return the worktree to the rig's pool if it has room
+	pooled := polecatDir != clonePath && NewWorktreePool(m.rig.Path).Recycle(clonePath)
+
+	// Otherwise remove it as a worktree (use force flag for worktree removal too)
+	if !pooled {
+		if err := repoGit.WorktreeRemove(clonePath, force); err != nil {
+			// Fall back to direct removal if worktree removal fails
+			// (e.g., if this is an old-style clone, not a worktree)
+			if removeErr := os.RemoveAll(clonePath); removeErr != nil {
+				return fmt.Errorf("removing clone path: %w", removeErr)
+			}
+		} else {
+			// GT-1L3MY9: git worktree remove may leave untracked directories behind.
+			// Clean up any leftover files (overlay files, .beads/, setup hook outputs, etc.)
+			// Use RemoveAll to handle non-empty directories with untracked files.
+			_ = os.RemoveAll(clonePath)
 		}
-	} else {
-		// GT-1L3MY9: git worktree remove may leave untracked directories behind.
-		// Clean up any leftover files (overlay files, .beads/, setup hook outputs, etc.)
-		// Use RemoveAll to handle non-empty directories with untracked files.
-		_ = os.RemoveAll(clonePath)
 	}
 
 	// Also remove the parent polecat directory
@@ -865,7 +881,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 // Called before each allocation to ensure InUse reflects reality.
 //
 // In addition to directory checks, this also:
-// - Kills orphaned tmux sessions (sessions without directories are broken)
+// - Kills orphaned sessions (sessions without directories are broken)
 func (m *Manager) ReconcilePool() {
 	// Get polecats with existing directories
 	polecats, err := m.List()
@@ -878,13 +894,14 @@ func (m *Manager) ReconcilePool() {
 		namesWithDirs = append(namesWithDirs, p.Name)
 	}
 
-	// Get names with tmux sessions
+	// Get names with sessions
 	var namesWithSessions []string
 	if m.tmux != nil {
+		sessions := m.sessions()
 		poolNames := m.namePool.getNames()
 		for _, name := range poolNames {
 			sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, name)
-			hasSession, _ := m.tmux.HasSession(sessionName)
+			hasSession, _ := sessions.HasSession(sessionName)
 			if hasSession {
 				namesWithSessions = append(namesWithSessions, name)
 			}
@@ -914,12 +931,13 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 	}
 
 	// Kill orphaned sessions (session exists but no directory).
-	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
+	// KillSession ensures all descendant processes are killed.
 	if m.tmux != nil {
+		sessions := m.sessions()
 		for _, name := range namesWithSessions {
 			if !dirSet[name] {
 				sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, name)
-				_ = m.tmux.KillSessionWithProcesses(sessionName)
+				_ = sessions.KillSession(sessionName)
 			}
 		}
 	}
@@ -1271,16 +1289,17 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 		defaultBranch = rigCfg.DefaultBranch
 	}
 
+	sessions := m.sessions()
 	var results []*StalenessInfo
 	for _, p := range polecats {
 		info := &StalenessInfo{
 			Name: p.Name,
 		}
 
-		// Check for active tmux session
+		// Check for an active session
 		// Session name follows pattern: gt-<rig>-<polecat>
 		sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, p.Name)
-		info.HasActiveSession = checkTmuxSession(sessionName)
+		info.HasActiveSession, _ = sessions.HasSession(sessionName)
 
 		// Check how far behind main
 		polecatGit := git.NewGit(p.ClonePath)
@@ -1307,11 +1326,9 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 	return results, nil
 }
 
-// checkTmuxSession checks if a tmux session exists.
-func checkTmuxSession(sessionName string) bool {
-	// Use has-session command which returns 0 if session exists
-	cmd := exec.Command("tmux", "has-session", "-t", sessionName) //nolint:gosec // G204: sessionName is constructed internally
-	return cmd.Run() == nil
+// sessions returns the session backend the rig's polecats run in.
+func (m *Manager) sessions() session.Backend {
+	return session.OpenBackend(filepath.Dir(m.rig.Path), m.tmux)
 }
 
 // countCommitsBehind counts how many commits a worktree is behind origin/<defaultBranch>.
@@ -0,0 +1,62 @@
+package polecat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// remoteSessionCommand prepares the polecat's worktree in the rig's remote
+// working copy and wraps command to run there over ssh. The remote worktree
+// is copied back to workDir when the agent exits.
+func (m *SessionManager) remoteSessionCommand(polecat, workDir, command string) (string, error) {
+	remote, err := connection.ParseRemote(m.rig.Remote)
+	if err != nil {
+		return "", err
+	}
+	local := git.NewGit(workDir)
+	branch, err := local.CurrentBranch()
+	if err != nil {
+		return "", fmt.Errorf("reading polecat branch: %w", err)
+	}
+	base, err := local.Rev("HEAD")
+	if err != nil {
+		return "", fmt.Errorf("reading polecat base commit: %w", err)
+	}
+
+	conn := remote.Connection()
+	remoteDir := remote.WorktreePath(polecat)
+	if err := prepareRemoteWorktree(conn, remote.Path, remoteDir, branch, base); err != nil {
+		return "", fmt.Errorf("preparing worktree on %s: %w", remote.Host, err)
+	}
+	return conn.SessionCommand(remoteDir, command, workDir), nil
+}
+
+// prepareRemoteWorktree ensures dir is a worktree of repo on branch. An
+// existing worktree on the same branch is kept (a restarted polecat resumes
+// its work); one left on another branch by an earlier polecat is replaced
+// with branch starting at base.
+func prepareRemoteWorktree(conn connection.Connection, repo, dir, branch, base string) error {
+	exists, err := conn.Exists(dir)
+	if err != nil {
+		return err
+	}
+	if exists {
+		out, err := conn.ExecDir(dir, "git", "rev-parse", "--abbrev-ref", "HEAD")
+		if err == nil && strings.TrimSpace(string(out)) == branch {
+			return nil
+		}
+		if out, err := conn.ExecDir(repo, "git", "worktree", "remove", "--force", dir); err != nil {
+			return fmt.Errorf("removing stale worktree: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	if out, err := conn.ExecDir(repo, "git", "fetch", "--quiet", "origin"); err != nil {
+		return fmt.Errorf("git fetch: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if out, err := conn.ExecDir(repo, "git", "worktree", "add", "-B", branch, dir, base); err != nil {
+		return fmt.Errorf("git worktree add: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package polecat
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/connection"
+)
+
+func TestPrepareRemoteWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	// The "remote" working copy, with itself as origin so fetch succeeds.
+	repo := filepath.Join(t.TempDir(), "mono")
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	git(repo, "commit", "-q", "--allow-empty", "-m", "init")
+	git(repo, "remote", "add", "origin", repo)
+	base := git(repo, "rev-parse", "HEAD")
+
+	conn := connection.NewLocalConnection()
+	dir := filepath.Join(filepath.Dir(repo), "mono-polecats", "Toast")
+	if err := prepareRemoteWorktree(conn, repo, dir, "polecat/Toast", base); err != nil {
+		t.Fatal(err)
+	}
+	if got := git(dir, "rev-parse", "--abbrev-ref", "HEAD"); got != "polecat/Toast" {
+		t.Errorf("worktree branch = %q", got)
+	}
+
+	// A restart on the same branch keeps the worktree.
+	git(dir, "commit", "-q", "--allow-empty", "-m", "work")
+	if err := prepareRemoteWorktree(conn, repo, dir, "polecat/Toast", base); err != nil {
+		t.Fatal(err)
+	}
+	if git(dir, "rev-parse", "HEAD") == base {
+		t.Error("restart discarded the polecat's commits")
+	}
+
+	// A new polecat with the same name replaces it.
+	if err := prepareRemoteWorktree(conn, repo, dir, "polecat/Toast-2", base); err != nil {
+		t.Fatal(err)
+	}
+	if got := git(dir, "rev-parse", "--abbrev-ref", "HEAD"); got != "polecat/Toast-2" {
+		t.Errorf("replaced worktree branch = %q", got)
+	}
+}
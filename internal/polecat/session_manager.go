@@ -12,8 +12,11 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/logs"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -36,18 +39,33 @@ var (
 
 // SessionManager handles polecat session lifecycle.
 type SessionManager struct {
-	tmux *tmux.Tmux
-	rig  *rig.Rig
+	tmux    *tmux.Tmux
+	backend session.Backend
+	rig     *rig.Rig
 }
 
 // NewSessionManager creates a new polecat session manager for a rig.
+// Sessions run in the town's configured session backend; t is used when
+// that is tmux.
 func NewSessionManager(t *tmux.Tmux, r *rig.Rig) *SessionManager {
 	return &SessionManager{
-		tmux: t,
-		rig:  r,
+		tmux:    t,
+		backend: session.OpenBackend(filepath.Dir(r.Path), t),
+		rig:     r,
 	}
 }
 
+// Backend returns the session backend polecat sessions run in.
+func (m *SessionManager) Backend() session.Backend {
+	return m.backend
+}
+
+// usesTmux reports whether sessions run in tmux, which alone supports
+// session env, themes, crash hooks, and pane-based readiness checks.
+func (m *SessionManager) usesTmux() bool {
+	return m.backend.Type() == session.BackendTmux
+}
+
 // SessionStartOptions configures polecat session startup.
 type SessionStartOptions struct {
 	// WorkDir overrides the default working directory (polecat clone dir).
@@ -65,6 +83,14 @@ type SessionStartOptions struct {
 	// RuntimeConfigDir is resolved config directory for the runtime account.
 	// If set, this is injected as an environment variable.
 	RuntimeConfigDir string
+
+	// Env is injected into the agent process over the town/rig settings env
+	// (e.g., per-formula overrides). Ignored when Command is set.
+	Env map[string]string
+
+	// Container, if set, runs the agent command inside this container with
+	// the working directory bind-mounted at the same path.
+	Container *formula.LegContainer
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -72,7 +98,7 @@ type SessionInfo struct {
 	// Polecat is the polecat name.
 	Polecat string `json:"polecat"`
 
-	// SessionID is the tmux session identifier.
+	// SessionID is the session identifier.
 	SessionID string `json:"session_id"`
 
 	// Running indicates if the session is currently active.
@@ -87,14 +113,14 @@ type SessionInfo struct {
 	// Created is when the session was created.
 	Created time.Time `json:"created,omitempty"`
 
-	// Windows is the number of tmux windows.
+	// Windows is the number of tmux windows (tmux backend only).
 	Windows int `json:"windows,omitempty"`
 
 	// LastActivity is when the session last had activity.
 	LastActivity time.Time `json:"last_activity,omitempty"`
 }
 
-// SessionName generates the tmux session name for a polecat.
+// SessionName generates the session name for a polecat.
 func (m *SessionManager) SessionName(polecat string) string {
 	return fmt.Sprintf("gt-%s-%s", m.rig.Name, polecat)
 }
@@ -150,7 +176,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Check if session already exists
 	// Note: Orphan sessions are cleaned up by ReconcilePool during AllocateName,
 	// so by this point, any existing session should be legitimately in use.
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -202,32 +228,63 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	command := opts.Command
 	if command == "" {
-		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon)
+		command = config.BuildPolecatStartupCommandWithEnv(m.rig.Name, polecat, m.rig.Path, beacon, opts.Env)
 	}
 	// Prepend runtime config dir env if needed
 	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
 		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
 	}
+	if m.rig.Remote != "" {
+		if opts.Container != nil {
+			return fmt.Errorf("container runner is not supported on remote rig %s", m.rig.Name)
+		}
+		if command, err = m.remoteSessionCommand(polecat, workDir, command); err != nil {
+			return err
+		}
+	}
+	if opts.Container != nil {
+		// The agent also needs its runtime settings (polecat home) and
+		// account config, the shared git dir, beads, and gt/bd from the host.
+		mounts := []string{polecatHomeDir + ":" + polecatHomeDir}
+		if opts.RuntimeConfigDir != "" {
+			mounts = append(mounts, opts.RuntimeConfigDir+":"+opts.RuntimeConfigDir)
+		}
+		hostMounts, unmounted := containerHostMounts(filepath.Dir(m.rig.Path), m.rig.Path, workDir)
+		if err := checkContainerImage(opts.Container.Image, unmounted); err != nil {
+			return err
+		}
+		mounts = append(append(mounts, hostMounts...), opts.Container.Mounts...)
+		command = config.ContainerCommand(command, workDir, opts.Container.Image, mounts)
+	}
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
+	// Session output is captured for gt logs, and a convoy leg's is also
+	// copied under its convoy for gt convoy watch.
+	townRoot := filepath.Dir(m.rig.Path)
+	logPaths := []string{logs.SessionLogPath(townRoot, address)}
+	if convoy, leg := opts.Env["GT_CONVOY"], opts.Env["GT_LEG"]; convoy != "" && leg != "" {
+		logPaths = append(logPaths, logs.LegLogPath(townRoot, convoy, leg))
+	}
+	if err := m.backend.NewSession(sessionID, workDir, command, logPaths...); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
 	// Set environment (non-fatal: session works without these)
-	// Use centralized AgentEnv for consistency across all role startup paths
-	townRoot := filepath.Dir(m.rig.Path)
-	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:             "polecat",
-		Rig:              m.rig.Name,
-		AgentName:        polecat,
-		TownRoot:         townRoot,
-		RuntimeConfigDir: opts.RuntimeConfigDir,
-		BeadsNoDaemon:    true,
-	})
-	for k, v := range envVars {
-		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
+	// Use centralized AgentEnv for consistency across all role startup paths.
+	// Other backends have no session env; the startup command carries it.
+	if m.usesTmux() {
+		envVars := config.AgentEnv(config.AgentEnvConfig{
+			Role:             "polecat",
+			Rig:              m.rig.Name,
+			AgentName:        polecat,
+			TownRoot:         townRoot,
+			RuntimeConfigDir: opts.RuntimeConfigDir,
+			BeadsNoDaemon:    true,
+		})
+		for k, v := range envVars {
+			debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
+		}
 	}
 
 	// Hook the issue to the polecat if provided via --issue flag
@@ -238,19 +295,21 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		}
 	}
 
-	// Apply theme (non-fatal)
-	theme := tmux.AssignTheme(m.rig.Name)
-	debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
+	if m.usesTmux() {
+		// Apply theme (non-fatal)
+		theme := tmux.AssignTheme(m.rig.Name)
+		debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
 
-	// Set pane-died hook for crash detection (non-fatal)
-	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
-	debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
+		// Set pane-died hook for crash detection (non-fatal)
+		agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
+		debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
 
-	// Wait for Claude to start (non-fatal)
-	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
+		// Wait for Claude to start (non-fatal)
+		debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
 
-	// Accept bypass permissions warning dialog if it appears
-	debugSession("AcceptBypassPermissionsWarning", m.tmux.AcceptBypassPermissionsWarning(sessionID))
+		// Accept bypass permissions warning dialog if it appears
+		debugSession("AcceptBypassPermissionsWarning", m.tmux.AcceptBypassPermissionsWarning(sessionID))
+	}
 
 	// Wait for runtime to be fully ready at the prompt (not just started)
 	runtime.SleepForReadyDelay(runtimeConfig)
@@ -260,11 +319,11 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if fallbackInfo.SendBeaconNudge && fallbackInfo.SendStartupNudge && fallbackInfo.StartupNudgeDelayMs == 0 {
 		// Hooks + no prompt: Single combined nudge (hook already ran gt prime synchronously)
 		combined := beacon + "\n\n" + runtime.StartupNudgeContent()
-		debugSession("SendCombinedNudge", m.tmux.NudgeSession(sessionID, combined))
+		debugSession("SendCombinedNudge", m.backend.SendText(sessionID, combined))
 	} else {
 		if fallbackInfo.SendBeaconNudge {
 			// Agent doesn't support CLI prompt - send beacon via nudge
-			debugSession("SendBeaconNudge", m.tmux.NudgeSession(sessionID, beacon))
+			debugSession("SendBeaconNudge", m.backend.SendText(sessionID, beacon))
 		}
 
 		if fallbackInfo.StartupNudgeDelayMs > 0 {
@@ -274,16 +333,18 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 		if fallbackInfo.SendStartupNudge {
 			// Send work instructions via nudge
-			debugSession("SendStartupNudge", m.tmux.NudgeSession(sessionID, runtime.StartupNudgeContent()))
+			debugSession("SendStartupNudge", m.backend.SendText(sessionID, runtime.StartupNudgeContent()))
 		}
 	}
 
 	// Legacy fallback for other startup paths (non-fatal)
-	_ = runtime.RunStartupFallback(m.tmux, sessionID, "polecat", runtimeConfig)
+	if m.usesTmux() {
+		_ = runtime.RunStartupFallback(m.tmux, sessionID, "polecat", runtimeConfig)
+	}
 
 	// Verify session survived startup - if the command crashed, the session may have died.
 	// Without this check, Start() would return success even if the pane died during initialization.
-	running, err = m.tmux.HasSession(sessionID)
+	running, err = m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("verifying session: %w", err)
 	}
@@ -298,7 +359,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -307,14 +368,14 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 	}
 
 	// Try graceful shutdown first
-	if !force {
+	if !force && m.usesTmux() {
 		_ = m.tmux.SendKeysRaw(sessionID, "C-c")
 		time.Sleep(100 * time.Millisecond)
 	}
 
 	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 	// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
+	if err := m.backend.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
@@ -324,14 +385,14 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 // IsRunning checks if a polecat session is active.
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
-	return m.tmux.HasSession(sessionID)
+	return m.backend.HasSession(sessionID)
 }
 
 // Status returns detailed status for a polecat session.
 func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -343,7 +404,7 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 		RigName:   m.rig.Name,
 	}
 
-	if !running {
+	if !running || !m.usesTmux() {
 		return info, nil
 	}
 
@@ -383,7 +444,7 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 
 // List returns information about all polecat sessions for this rig.
 func (m *SessionManager) List() ([]SessionInfo, error) {
-	sessions, err := m.tmux.ListSessions()
+	sessions, err := m.backend.ListSessions()
 	if err != nil {
 		return nil, err
 	}
@@ -412,7 +473,7 @@ func (m *SessionManager) List() ([]SessionInfo, error) {
 func (m *SessionManager) Attach(polecat string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -420,14 +481,14 @@ func (m *SessionManager) Attach(polecat string) error {
 		return ErrSessionNotFound
 	}
 
-	return m.tmux.AttachSession(sessionID)
+	return m.backend.Attach(sessionID)
 }
 
 // Capture returns the recent output from a polecat session.
 func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -435,12 +496,12 @@ func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 		return "", ErrSessionNotFound
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return m.backend.Capture(sessionID, lines)
 }
 
 // CaptureSession returns the recent output from a session by raw session ID.
 func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, error) {
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -448,14 +509,14 @@ func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, er
 		return "", ErrSessionNotFound
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return m.backend.Capture(sessionID, lines)
 }
 
 // Inject sends a message to a polecat session.
 func (m *SessionManager) Inject(polecat, message string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -463,6 +524,10 @@ func (m *SessionManager) Inject(polecat, message string) error {
 		return ErrSessionNotFound
 	}
 
+	if !m.usesTmux() {
+		return m.backend.SendText(sessionID, message)
+	}
+
 	debounceMs := 200 + (len(message)/1024)*100
 	if debounceMs > 1500 {
 		debounceMs = 1500
@@ -504,7 +569,7 @@ func (m *SessionManager) validateIssue(issueID, workDir string) error {
 
 	cmd := exec.Command("bd", "show", issueID, "--json") //nolint:gosec
 	cmd.Dir = bdWorkDir
-	output, err := cmd.Output()
+	output, err := cmdtrace.Output(cmd)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrIssueInvalid, issueID)
 	}
@@ -531,7 +596,7 @@ func (m *SessionManager) hookIssue(issueID, agentID, workDir string) error {
 	cmd := exec.Command("bd", "update", issueID, "--status=hooked", "--assignee="+agentID) //nolint:gosec
 	cmd.Dir = bdWorkDir
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return fmt.Errorf("bd update failed: %w", err)
 	}
 	fmt.Printf("✓ Hooked issue %s to %s\n", issueID, agentID)
@@ -1,6 +1,7 @@
 package polecat
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -290,3 +292,36 @@ func TestSessionManager_resolveBeadsDir(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionManagerProcessBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("GT_SESSION_BACKEND", "process")
+	townRoot := t.TempDir()
+	r := &rig.Rig{Name: "gastown", Path: filepath.Join(townRoot, "gastown")}
+	m := NewSessionManager(tmux.NewTmux(), r)
+	if got := m.Backend().Type(); got != session.BackendProcess {
+		t.Fatalf("backend = %q, want process", got)
+	}
+
+	if err := m.Backend().NewSession(m.SessionName("Toast"), townRoot, "exec sleep 30", ""); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if running, err := m.IsRunning("Toast"); err != nil || !running {
+		t.Fatalf("IsRunning = %v, %v; want true", running, err)
+	}
+	infos, err := m.List()
+	if err != nil || len(infos) != 1 || infos[0].Polecat != "Toast" {
+		t.Fatalf("List = %+v, %v; want Toast", infos, err)
+	}
+	if err := m.Attach("Toast"); !errors.Is(err, session.ErrNotSupported) {
+		t.Errorf("Attach error = %v, want ErrNotSupported", err)
+	}
+	if err := m.Stop("Toast", false); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if running, _ := m.IsRunning("Toast"); running {
+		t.Error("session should be stopped")
+	}
+}
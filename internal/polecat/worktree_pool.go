@@ -0,0 +1,177 @@
+package polecat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// WorktreePool keeps clean worktrees of a rig's repository checked out at
+// the default branch under .runtime/worktree-pool/, so a new polecat gets
+// its worktree by a rename instead of a full checkout. Removed polecats'
+// worktrees are cleaned and returned to the pool while it has room.
+//
+// The pool is sized by the rig's worktree_pool settings; without them it
+// is disabled and Take always misses.
+type WorktreePool struct {
+	rigPath string
+}
+
+// NewWorktreePool returns the worktree pool of the rig at rigPath.
+func NewWorktreePool(rigPath string) *WorktreePool {
+	return &WorktreePool{rigPath: rigPath}
+}
+
+// Dir returns the directory holding the pooled worktrees.
+func (p *WorktreePool) Dir() string {
+	return filepath.Join(p.rigPath, ".runtime", "worktree-pool")
+}
+
+// Size returns how many worktrees the rig's settings keep ready.
+func (p *WorktreePool) Size() int {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(p.rigPath))
+	if err != nil || settings.WorktreePool == nil {
+		return 0
+	}
+	return settings.WorktreePool.Size
+}
+
+// Ready returns the paths of the pooled worktrees, oldest first.
+func (p *WorktreePool) Ready() []string {
+	entries, err := os.ReadDir(p.Dir())
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "wt-") {
+			paths = append(paths, filepath.Join(p.Dir(), e.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Take moves a pooled worktree to path and checks out a new branch there
+// from startPoint. It reports false when the pool is empty or no pooled
+// worktree could be used; the caller then creates the worktree itself.
+func (p *WorktreePool) Take(path, branch, startPoint string) bool {
+	repo, err := repoBaseAt(p.rigPath)
+	if err != nil {
+		return false
+	}
+	for _, entry := range p.Ready() {
+		// A concurrent Take may have moved this entry already.
+		if err := repo.WorktreeMove(entry, path); err != nil {
+			continue
+		}
+		if err := git.NewGit(path).CheckoutResetBranch(branch, startPoint); err != nil {
+			_ = repo.WorktreeRemove(path, true)
+			_ = os.RemoveAll(path)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// Recycle cleans the worktree at path and moves it into the pool, if the
+// pool has room. It reports false if the worktree was left in place.
+func (p *WorktreePool) Recycle(path string) bool {
+	if len(p.Ready()) >= p.Size() {
+		return false
+	}
+	repo, err := repoBaseAt(p.rigPath)
+	if err != nil {
+		return false
+	}
+	if err := git.NewGit(path).ResetToDetached(p.startPoint()); err != nil {
+		return false
+	}
+	if err := os.MkdirAll(p.Dir(), 0755); err != nil {
+		return false
+	}
+	return repo.WorktreeMove(path, p.newEntryPath()) == nil
+}
+
+// Fill fetches origin, resets the pooled worktrees to the default branch,
+// and checks out new ones until the pool reaches its configured size.
+// Worktrees beyond the size (after it shrank) are removed. It returns how
+// many worktrees were added.
+func (p *WorktreePool) Fill() (int, error) {
+	size := p.Size()
+	ready := p.Ready()
+	if size == 0 && len(ready) == 0 {
+		return 0, nil
+	}
+	repo, err := repoBaseAt(p.rigPath)
+	if err != nil {
+		return 0, err
+	}
+	if err := repo.Fetch("origin"); err != nil {
+		return 0, fmt.Errorf("fetching origin: %w", err)
+	}
+	startPoint := p.startPoint()
+
+	kept := 0
+	for _, entry := range ready {
+		// Re-applying sparse checkout picks up changed clone.sparse settings.
+		if kept < size && git.NewGit(entry).ResetToDetached(startPoint) == nil && git.ConfigureSparseCheckout(entry) == nil {
+			kept++
+			continue
+		}
+		_ = repo.WorktreeRemove(entry, true)
+		_ = os.RemoveAll(entry)
+	}
+	_ = repo.WorktreePrune()
+
+	if err := os.MkdirAll(p.Dir(), 0755); err != nil {
+		return 0, fmt.Errorf("creating pool dir: %w", err)
+	}
+	added := 0
+	for ; kept+added < size; added++ {
+		if err := repo.WorktreeAddDetached(p.newEntryPath(), startPoint); err != nil {
+			return added, fmt.Errorf("checking out pooled worktree: %w", err)
+		}
+	}
+	return added, nil
+}
+
+// Drain removes every pooled worktree.
+func (p *WorktreePool) Drain() error {
+	repo, err := repoBaseAt(p.rigPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range p.Ready() {
+		_ = repo.WorktreeRemove(entry, true)
+		if err := os.RemoveAll(entry); err != nil {
+			return err
+		}
+	}
+	return repo.WorktreePrune()
+}
+
+// startPoint returns the ref pooled worktrees are checked out at:
+// origin/<default branch>.
+func (p *WorktreePool) startPoint() string {
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(p.rigPath); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	return "origin/" + defaultBranch
+}
+
+// newEntryPath returns a fresh path for a pooled worktree. Names sort by
+// creation time so Take hands out the oldest first.
+func (p *WorktreePool) newEntryPath() string {
+	return filepath.Join(p.Dir(), "wt-"+strconv.FormatInt(time.Now().UnixNano(), 36))
+}
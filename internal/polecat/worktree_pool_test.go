@@ -0,0 +1,131 @@
+package polecat
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupPoolRig creates a rig at a temp dir whose .repo.git is a bare clone
+// of a one-commit origin, with a worktree pool of the given size.
+func setupPoolRig(t *testing.T, size string) string {
+	t.Helper()
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin")
+	rigPath := filepath.Join(root, "rig")
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	if err := os.MkdirAll(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(origin, "init", "-b", "main")
+	if err := os.WriteFile(filepath.Join(origin, "README.md"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(origin, "add", ".")
+	run(origin, "commit", "-m", "init")
+
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(rigPath, "clone", "--bare", origin, ".repo.git")
+	run(rigPath, "--git-dir=.repo.git", "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	run(rigPath, "--git-dir=.repo.git", "fetch", "origin")
+	setPoolSize(t, rigPath, size)
+	return rigPath
+}
+
+func setPoolSize(t *testing.T, rigPath, size string) {
+	t.Helper()
+	settings := `{"type": "rig-settings", "version": 1, "worktree_pool": {"size": ` + size + `}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorktreePool(t *testing.T) {
+	rigPath := setupPoolRig(t, "2")
+	pool := NewWorktreePool(rigPath)
+
+	if added, err := pool.Fill(); err != nil || added != 2 {
+		t.Fatalf("Fill = %d, %v; want 2 added", added, err)
+	}
+
+	// Take hands out a ready worktree on a new branch.
+	path := filepath.Join(rigPath, "polecats", "Toast", "rig")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if !pool.Take(path, "polecat/Toast-1", "origin/main") {
+		t.Fatal("Take missed a filled pool")
+	}
+	if got := len(pool.Ready()); got != 1 {
+		t.Errorf("ready after Take = %d, want 1", got)
+	}
+	if _, err := os.Stat(filepath.Join(path, "README.md")); err != nil {
+		t.Errorf("taken worktree is not checked out: %v", err)
+	}
+	out, _ := exec.Command("git", "-C", path, "branch", "--show-current").Output()
+	if branch := strings.TrimSpace(string(out)); branch != "polecat/Toast-1" {
+		t.Errorf("branch = %q, want polecat/Toast-1", branch)
+	}
+
+	// Recycle cleans the worktree and returns it while the pool has room.
+	if err := os.WriteFile(filepath.Join(path, "scratch.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !pool.Recycle(path) {
+		t.Fatal("Recycle refused with room in the pool")
+	}
+	ready := pool.Ready()
+	if len(ready) != 2 {
+		t.Fatalf("ready after Recycle = %d, want 2", len(ready))
+	}
+	for _, p := range ready {
+		if _, err := os.Stat(filepath.Join(p, "scratch.txt")); err == nil {
+			t.Errorf("recycled worktree %s kept untracked files", p)
+		}
+	}
+
+	// A full pool leaves worktrees where they are.
+	if !pool.Take(path, "polecat/Toast-2", "origin/main") {
+		t.Fatal("second Take missed")
+	}
+	if _, err := pool.Fill(); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Recycle(path) {
+		t.Error("Recycle into a full pool succeeded")
+	}
+
+	// Shrinking the pool trims it on the next Fill.
+	setPoolSize(t, rigPath, "1")
+	if _, err := pool.Fill(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(pool.Ready()); got != 1 {
+		t.Errorf("ready after shrink = %d, want 1", got)
+	}
+}
+
+func TestWorktreePoolDisabled(t *testing.T) {
+	rigPath := setupPoolRig(t, "0")
+	pool := NewWorktreePool(rigPath)
+	if added, err := pool.Fill(); err != nil || added != 0 {
+		t.Errorf("Fill = %d, %v; want nothing", added, err)
+	}
+	if pool.Take(filepath.Join(rigPath, "polecats", "Toast", "rig"), "polecat/Toast-1", "origin/main") {
+		t.Error("Take succeeded on an empty pool")
+	}
+}
@@ -0,0 +1,44 @@
+// Package readonly implements gt's read-only mode, turned on by the global
+// --read-only flag or GT_READ_ONLY. In read-only mode gt refuses anything
+// with side effects: creating or changing beads, writing town state, and
+// calling agents. Commands decide up front whether they can run read-only;
+// the low-level writers (fsx, beads, events, history) check it again as a
+// backstop.
+package readonly
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvVar turns on read-only mode. gt sets it when --read-only is given, so
+// gt and bd processes started from a read-only command inherit it.
+const EnvVar = "GT_READ_ONLY"
+
+// ErrReadOnly is returned for operations refused in read-only mode.
+var ErrReadOnly = errors.New("read-only mode")
+
+// Enabled reports whether read-only mode is on.
+func Enabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvVar))) {
+	case "", "0", "false", "no", "off":
+		return false
+	}
+	return true
+}
+
+// Enable turns read-only mode on for this process and its children.
+func Enable() {
+	_ = os.Setenv(EnvVar, "1")
+}
+
+// Check returns an ErrReadOnly error describing action if read-only mode
+// is on, and nil otherwise.
+func Check(action string) error {
+	if !Enabled() {
+		return nil
+	}
+	return fmt.Errorf("%w: refusing to %s", ErrReadOnly, action)
+}
@@ -0,0 +1,24 @@
+package readonly
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "0": false, "false": false, "1": true, "true": true, "yes": true} {
+		t.Setenv(EnvVar, value)
+		if got := Enabled(); got != want {
+			t.Errorf("Enabled() with %s=%q = %v, want %v", EnvVar, value, got, want)
+		}
+	}
+
+	t.Setenv(EnvVar, "")
+	if err := Check("write x"); err != nil {
+		t.Errorf("Check() = %v outside read-only mode", err)
+	}
+	Enable()
+	if err := Check("write x"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Check() = %v, want ErrReadOnly", err)
+	}
+}
@@ -0,0 +1,159 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultStepTimeout bounds a pipeline step that doesn't set its own timeout.
+const DefaultStepTimeout = 10 * time.Minute
+
+// ErrPipelineFailed indicates at least one required pipeline step failed.
+var ErrPipelineFailed = errors.New("refinery pipeline failed")
+
+// reviewIDRE matches the review IDs gt formula run generates: five
+// lowercase base32 characters.
+var reviewIDRE = regexp.MustCompile(`^[a-z2-7]{5}$`)
+
+// ValidateReviewID rejects anything but a generated review ID, so an ID
+// can't name a path outside the rig's refinery outputs.
+func ValidateReviewID(id string) error {
+	if !reviewIDRE.MatchString(id) {
+		return fmt.Errorf("invalid review ID %q: want the 5-character ID gt formula run printed", id)
+	}
+	return nil
+}
+
+// PipelineRun describes one invocation of the post-synthesis pipeline.
+type PipelineRun struct {
+	// ReviewID identifies the convoy review whose outputs are refined.
+	ReviewID string
+
+	// ReviewDir holds the leg and synthesis outputs (e.g., .reviews/<id>).
+	// Steps run with this as their working directory.
+	ReviewDir string
+
+	// RigName and RigPath identify the rig whose settings define the steps.
+	RigName string
+	RigPath string
+
+	// Stdout and Stderr receive step output. Nil discards it.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// OutputDir returns the per-review artifact directory under the rig's refinery/.
+func (r *PipelineRun) OutputDir() string {
+	return filepath.Join(r.RigPath, "refinery", "outputs", r.ReviewID)
+}
+
+// StepResult records the outcome of a single pipeline step.
+type StepResult struct {
+	Name     string        `json:"name"`
+	Success  bool          `json:"success"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// PipelineResult records the outcome of a pipeline run.
+// It is written to <output-dir>/pipeline.json for later inspection.
+type PipelineResult struct {
+	ReviewID  string       `json:"review_id"`
+	Rig       string       `json:"rig"`
+	StartedAt time.Time    `json:"started_at"`
+	Steps     []StepResult `json:"steps"`
+	Success   bool         `json:"success"`
+}
+
+// RunPipeline runs the configured steps in order for a review.
+// A failing step stops the pipeline unless it sets ContinueOnError.
+// Returns ErrPipelineFailed (wrapped) if any step without ContinueOnError failed.
+func RunPipeline(ctx context.Context, steps []config.RefineryStep, run *PipelineRun) (*PipelineResult, error) {
+	if err := ValidateReviewID(run.ReviewID); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(run.ReviewDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("review directory not found: %s", run.ReviewDir)
+	}
+
+	outputDir := run.OutputDir()
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating refinery output dir: %w", err)
+	}
+
+	result := &PipelineResult{
+		ReviewID:  run.ReviewID,
+		Rig:       run.RigName,
+		StartedAt: time.Now(),
+		Success:   true,
+	}
+
+	for _, step := range steps {
+		stepResult := runStep(ctx, step, run, outputDir)
+		result.Steps = append(result.Steps, stepResult)
+		if !stepResult.Success && !step.ContinueOnError {
+			result.Success = false
+			break
+		}
+	}
+
+	if err := util.AtomicWriteJSON(filepath.Join(outputDir, "pipeline.json"), result); err != nil {
+		return result, fmt.Errorf("writing pipeline result: %w", err)
+	}
+
+	if !result.Success {
+		last := result.Steps[len(result.Steps)-1]
+		return result, fmt.Errorf("%w: step %q: %s", ErrPipelineFailed, last.Name, last.Error)
+	}
+	return result, nil
+}
+
+// runStep executes one step with sh -c from the review directory.
+func runStep(ctx context.Context, step config.RefineryStep, run *PipelineRun, outputDir string) StepResult {
+	timeout := DefaultStepTimeout
+	if step.Timeout != "" {
+		if d, err := time.ParseDuration(step.Timeout); err == nil {
+			timeout = d
+		}
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(stepCtx, "sh", "-c", step.Run) //nolint:gosec // G204: steps come from rig settings
+	cmd.Dir = run.ReviewDir
+	cmd.Env = append(os.Environ(),
+		"GT_REVIEW_ID="+run.ReviewID,
+		"GT_REVIEW_DIR="+run.ReviewDir,
+		"GT_REFINERY_OUTPUT="+outputDir,
+		"GT_RIG="+run.RigName,
+	)
+	cmd.Stdout = run.Stdout
+	cmd.Stderr = run.Stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result := StepResult{
+		Name:     step.Name,
+		Success:  err == nil,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		if stepCtx.Err() == context.DeadlineExceeded {
+			result.Error = fmt.Sprintf("timed out after %s", timeout)
+		} else {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
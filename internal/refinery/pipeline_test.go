@@ -0,0 +1,92 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func newTestPipelineRun(t *testing.T) *PipelineRun {
+	t.Helper()
+	tmp := t.TempDir()
+	reviewDir := filepath.Join(tmp, ".reviews", "abc23")
+	if err := os.MkdirAll(reviewDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(reviewDir, "synthesis.md"), []byte("# Review\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &PipelineRun{
+		ReviewID:  "abc23",
+		ReviewDir: reviewDir,
+		RigName:   "testrig",
+		RigPath:   filepath.Join(tmp, "testrig"),
+	}
+}
+
+func TestRunPipeline(t *testing.T) {
+	run := newTestPipelineRun(t)
+	steps := []config.RefineryStep{
+		{Name: "copy", Run: `cp synthesis.md "$GT_REFINERY_OUTPUT/report.md"`},
+		{Name: "stamp", Run: `echo "$GT_REVIEW_ID $GT_RIG" > "$GT_REFINERY_OUTPUT/stamp.txt"`},
+	}
+
+	result, err := RunPipeline(context.Background(), steps, run)
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+	if !result.Success || len(result.Steps) != 2 {
+		t.Fatalf("result = %+v, want 2 successful steps", result)
+	}
+
+	if _, err := os.Stat(filepath.Join(run.OutputDir(), "report.md")); err != nil {
+		t.Errorf("report.md not produced: %v", err)
+	}
+	stamp, err := os.ReadFile(filepath.Join(run.OutputDir(), "stamp.txt"))
+	if err != nil || strings.TrimSpace(string(stamp)) != "abc23 testrig" {
+		t.Errorf("stamp.txt = %q, %v; want env vars expanded", stamp, err)
+	}
+	if _, err := os.Stat(filepath.Join(run.OutputDir(), "pipeline.json")); err != nil {
+		t.Errorf("pipeline.json not written: %v", err)
+	}
+}
+
+func TestRunPipelineStopsOnFailure(t *testing.T) {
+	run := newTestPipelineRun(t)
+	steps := []config.RefineryStep{
+		{Name: "optional", Run: "exit 1", ContinueOnError: true},
+		{Name: "required", Run: "exit 2"},
+		{Name: "never", Run: `touch "$GT_REFINERY_OUTPUT/never"`},
+	}
+
+	result, err := RunPipeline(context.Background(), steps, run)
+	if !errors.Is(err, ErrPipelineFailed) {
+		t.Fatalf("RunPipeline() error = %v, want ErrPipelineFailed", err)
+	}
+	if len(result.Steps) != 2 {
+		t.Errorf("ran %d steps, want 2 (stop after required failure)", len(result.Steps))
+	}
+	if _, err := os.Stat(filepath.Join(run.OutputDir(), "never")); err == nil {
+		t.Error("step after failure should not run")
+	}
+}
+
+func TestRunPipelineMissingReviewDir(t *testing.T) {
+	run := &PipelineRun{ReviewID: "xyz23", ReviewDir: filepath.Join(t.TempDir(), "missing")}
+	if _, err := RunPipeline(context.Background(), nil, run); err == nil {
+		t.Error("expected error for missing review directory")
+	}
+}
+
+func TestRunPipelineRejectsPathReviewID(t *testing.T) {
+	run := newTestPipelineRun(t)
+	run.ReviewID = "../.."
+	if _, err := RunPipeline(context.Background(), nil, run); err == nil {
+		t.Error("expected error for a review ID that escapes the outputs directory")
+	}
+}
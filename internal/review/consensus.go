@@ -0,0 +1,174 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AgentFindings are the findings one agent reported for a consensus leg.
+type AgentFindings struct {
+	Agent    string
+	Findings []Finding
+}
+
+// ConsensusFinding is a finding with the agents that reported it. Its
+// severity is the highest any of them gave.
+type ConsensusFinding struct {
+	Finding
+	Agents []string `json:"agents"`
+}
+
+// Consensus is the merged result of a consensus leg.
+type Consensus struct {
+	Agents   []string           `json:"agents"` // Agents that replied
+	Quorum   int                `json:"quorum"` // Agents needed to agree
+	Agreed   []ConsensusFinding `json:"agreed"`
+	Disputed []ConsensusFinding `json:"disputed,omitempty"` // Below quorum
+}
+
+// MergeConsensus groups the agents' findings that describe the same issue
+// and splits them by whether at least quorum agents reported them.
+// Findings match when they cite the same file:line or share most of their
+// words; each agent contributes at most one finding to a group.
+func MergeConsensus(reports []AgentFindings, quorum int) *Consensus {
+	c := &Consensus{Quorum: quorum}
+	var groups []*ConsensusFinding
+	var groupWords [][]map[string]bool
+	for _, r := range reports {
+		c.Agents = append(c.Agents, r.Agent)
+		for _, f := range r.Findings {
+			words := findingWords(f.Text)
+			var match *ConsensusFinding
+			for i, g := range groups {
+				if containsAgent(g.Agents, r.Agent) {
+					continue
+				}
+				if sameIssue(f.Text, words, g.Text, groupWords[i]) {
+					match = g
+					groupWords[i] = append(groupWords[i], words)
+					break
+				}
+			}
+			if match == nil {
+				groups = append(groups, &ConsensusFinding{Finding: f})
+				groupWords = append(groupWords, []map[string]bool{words})
+				match = groups[len(groups)-1]
+			}
+			match.Agents = append(match.Agents, r.Agent)
+			if f.Severity > match.Severity {
+				match.Severity = f.Severity
+			}
+		}
+	}
+	for _, g := range groups {
+		if len(g.Agents) >= quorum {
+			c.Agreed = append(c.Agreed, *g)
+		} else {
+			c.Disputed = append(c.Disputed, *g)
+		}
+	}
+	return c
+}
+
+// Markdown renders the merged findings as a leg output. Agreed findings go
+// under severity headings, so ParseFindings and the gates see them;
+// disputed ones are listed under a heading gates ignore.
+func (c *Consensus) Markdown(title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\nConsensus of %s: findings reported by at least %d of %d agents.\n",
+		title, strings.Join(c.Agents, ", "), c.Quorum, len(c.Agents))
+	for sev := SeverityCritical; sev >= SeverityInfo; sev-- {
+		var items []ConsensusFinding
+		for _, f := range c.Agreed {
+			if f.Severity == sev {
+				items = append(items, f)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", capitalize(sev.String()))
+		for _, f := range items {
+			fmt.Fprintf(&b, "- %s _(%s)_\n", f.Text, strings.Join(f.Agents, ", "))
+		}
+	}
+	if len(c.Agreed) == 0 {
+		b.WriteString("\nNo findings reached consensus.\n")
+	}
+	if len(c.Disputed) > 0 {
+		b.WriteString("\n## Disputed\n\nReported by too few agents to count; review by hand.\n\n")
+		for _, f := range c.Disputed {
+			fmt.Fprintf(&b, "- %s _(%s: %s)_\n", f.Text, f.Severity, strings.Join(f.Agents, ", "))
+		}
+	}
+	return b.String()
+}
+
+// locationRe matches file references such as internal/auth/token.go:42.
+var locationRe = regexp.MustCompile(`[A-Za-z0-9_./-]+\.[A-Za-z0-9]+:\d+`)
+
+// consensusStopWords are too common in findings to show agreement.
+var consensusStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "this": true, "with": true,
+	"are": true, "not": true, "can": true, "should": true, "could": true, "from": true,
+	"when": true, "which": true, "into": true, "its": true, "has": true, "have": true,
+}
+
+// findingWords returns the distinctive words of a finding.
+func findingWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	}) {
+		if len(w) >= 3 && !consensusStopWords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// sameIssue reports whether a finding describes the same issue as a group:
+// they cite a common file:line, or its words overlap any member's by half.
+func sameIssue(text string, words map[string]bool, groupText string, group []map[string]bool) bool {
+	for _, loc := range locationRe.FindAllString(text, -1) {
+		if strings.Contains(groupText, loc) {
+			return true
+		}
+	}
+	for _, other := range group {
+		if jaccard(words, other) >= 0.5 {
+			return true
+		}
+	}
+	return false
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func containsAgent(agents []string, agent string) bool {
+	for _, a := range agents {
+		if a == agent {
+			return true
+		}
+	}
+	return false
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
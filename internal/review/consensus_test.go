@@ -0,0 +1,52 @@
+package review
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeConsensus(t *testing.T) {
+	claude := ParseFindings(`## High
+- SQL injection in user lookup at internal/db/users.go:42
+- Session tokens never expire after logout
+
+## Low
+- Unused import in main.go
+`, "security")
+	gemini := ParseFindings(`## Critical
+- internal/db/users.go:42 builds the query by string concatenation
+## Medium
+- Session tokens do not expire after logout
+`, "security")
+
+	c := MergeConsensus([]AgentFindings{{Agent: "claude", Findings: claude}, {Agent: "gemini", Findings: gemini}}, 2)
+	if len(c.Agreed) != 2 {
+		t.Fatalf("Agreed = %+v, want the injection and session findings", c.Agreed)
+	}
+	if c.Agreed[0].Severity != SeverityCritical || strings.Join(c.Agreed[0].Agents, ",") != "claude,gemini" {
+		t.Errorf("injection finding = %+v, want critical from both agents", c.Agreed[0])
+	}
+	if len(c.Disputed) != 1 || !strings.Contains(c.Disputed[0].Text, "Unused import") {
+		t.Errorf("Disputed = %+v, want the unused import", c.Disputed)
+	}
+
+	// The merged output counts agreed findings only
+	md := c.Markdown("Security")
+	findings := ParseFindings(md, "security")
+	if len(findings) != 2 {
+		t.Errorf("ParseFindings(merged) = %+v, want 2 agreed findings\n%s", findings, md)
+	}
+	if !strings.Contains(md, "## Disputed") || !strings.Contains(md, "_(low: claude)_") {
+		t.Errorf("merged output doesn't flag the disputed finding:\n%s", md)
+	}
+}
+
+func TestMergeConsensusUnion(t *testing.T) {
+	a := []Finding{{Severity: SeverityHigh, Text: "Race on cache map"}}
+	b := []Finding{{Severity: SeverityLow, Text: "Typo in README"}}
+
+	c := MergeConsensus([]AgentFindings{{Agent: "a", Findings: a}, {Agent: "b", Findings: b}}, 1)
+	if len(c.Agreed) != 2 || len(c.Disputed) != 0 {
+		t.Errorf("union merge = %+v, want every finding agreed", c)
+	}
+}
@@ -0,0 +1,123 @@
+// Package review renders convoy review outputs (leg findings and synthesis)
+// into a single shareable report.
+package review
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Report formats.
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Report is the data a report template renders.
+type Report struct {
+	ReviewID     string
+	Title        string
+	Formula      string
+	Description  string
+	Rig          string
+	Convoy       string
+	ConvoyStatus string
+	PR           int
+	PRURL        string
+	HeadSHA      string
+	Generated    time.Time
+	Synthesis    *Section
+	Legs         []Section
+}
+
+// Section is one leg's findings, or the synthesis.
+type Section struct {
+	ID      string
+	Title   string
+	Status  string // complete | missing
+	Content string // markdown
+}
+
+// Status values for a Section.
+const (
+	StatusComplete = "complete"
+	StatusMissing  = "missing"
+)
+
+// md converts agent-written markdown for HTML reports. Raw HTML in the
+// input is not passed through.
+var md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// markdownHTML renders markdown to HTML for use in html/template.
+func markdownHTML(s string) (htmltemplate.HTML, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(s), &buf); err != nil {
+		return "", err
+	}
+	return htmltemplate.HTML(buf.String()), nil //nolint:gosec // G203: goldmark escapes raw HTML by default
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// Render writes r to w in format ("md" or "html"). A non-empty templatePath
+// replaces the embedded default template; it is executed with the same data
+// and functions (markdown, shortsha), and with html/template escaping for
+// the html format.
+func Render(w io.Writer, r *Report, format, templatePath string) error {
+	var src []byte
+	var err error
+	switch format {
+	case FormatMarkdown, FormatHTML:
+	default:
+		return fmt.Errorf("unknown format %q (want %s or %s)", format, FormatMarkdown, FormatHTML)
+	}
+	if templatePath != "" {
+		src, err = os.ReadFile(templatePath) //nolint:gosec // G304: user-supplied template
+	} else {
+		src, err = templateFS.ReadFile("templates/report." + format + ".tmpl")
+	}
+	if err != nil {
+		return fmt.Errorf("reading template: %w", err)
+	}
+	name := "report"
+	if templatePath != "" {
+		name = filepath.Base(templatePath)
+	}
+
+	if format == FormatHTML {
+		t, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap{
+			"markdown": markdownHTML,
+			"shortsha": shortSHA,
+		}).Parse(string(src))
+		if err != nil {
+			return fmt.Errorf("parsing template: %w", err)
+		}
+		return t.Execute(w, r)
+	}
+	t, err := texttemplate.New(name).Funcs(texttemplate.FuncMap{
+		"markdown": func(s string) string { return s },
+		"shortsha": shortSHA,
+	}).Parse(string(src))
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+	return t.Execute(w, r)
+}
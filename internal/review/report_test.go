@@ -0,0 +1,90 @@
+package review
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testReport() *Report {
+	return &Report{
+		ReviewID:  "abc123",
+		Title:     "code-review: PR #12",
+		Formula:   "code-review",
+		PR:        12,
+		PRURL:     "https://github.com/o/r/pull/12",
+		HeadSHA:   "3f2c9a1b7d5e",
+		Generated: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+		Synthesis: &Section{ID: "synthesis", Title: "Review Summary", Status: StatusComplete, Content: "All good."},
+		Legs: []Section{
+			{ID: "security", Title: "Security", Status: StatusComplete, Content: "- <script>alert(1)</script>\n- fine"},
+			{ID: "perf", Title: "Performance", Status: StatusMissing},
+		},
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, testReport(), FormatMarkdown, ""); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# code-review: PR #12\n",
+		"| PR | [#12](https://github.com/o/r/pull/12) at `3f2c9a1b` |",
+		"## Review Summary\n\nAll good.\n",
+		"## Security\n\n- <script>",
+		"## Performance\n\n_No findings (missing)._",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "Review Summary") > strings.Index(out, "## Security") {
+		t.Error("synthesis should come before legs")
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, testReport(), FormatHTML, ""); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<a href="https://github.com/o/r/pull/12">#12</a>`,
+		`<section id="leg-security">`,
+		"<li>fine</li>",
+		`<p class="missing">No findings (missing).</p>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script>alert") {
+		t.Error("raw HTML from findings must not pass through")
+	}
+}
+
+func TestRenderCustomTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "short.tmpl")
+	if err := os.WriteFile(path, []byte(`{{ .ReviewID }}{{ range .Legs }} {{ .ID }}={{ .Status }}{{ end }}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, testReport(), FormatMarkdown, path); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got, want := buf.String(), "abc123 security=complete perf=missing"; got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if err := Render(&bytes.Buffer{}, testReport(), "pdf", ""); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,156 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Severity ranks a finding. Higher values are more severe.
+type Severity int
+
+// Standard severity levels, least to most severe.
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = [...]string{"info", "low", "medium", "high", "critical"}
+
+func (s Severity) String() string {
+	if s < SeverityInfo || s > SeverityCritical {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// MarshalText encodes a severity by name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name.
+func (s *Severity) UnmarshalText(b []byte) error {
+	v, err := ParseSeverity(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// severityWords maps the words findings use for severity to a level. The
+// code-review formula's Critical/Major/Minor/Observations sections and
+// P0-P3 priorities are accepted alongside the standard names.
+var severityWords = map[string]Severity{
+	"critical": SeverityCritical, "p0": SeverityCritical, "blocker": SeverityCritical,
+	"high": SeverityHigh, "major": SeverityHigh, "p1": SeverityHigh,
+	"medium": SeverityMedium, "minor": SeverityMedium, "moderate": SeverityMedium, "p2": SeverityMedium,
+	"low": SeverityLow, "p3": SeverityLow, "nit": SeverityLow, "nits": SeverityLow,
+	"info": SeverityInfo, "informational": SeverityInfo, "observation": SeverityInfo, "observations": SeverityInfo,
+}
+
+// ParseSeverity parses a severity name such as "high" or "P1".
+func ParseSeverity(s string) (Severity, error) {
+	if sev, ok := severityWords[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return sev, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q (want one of %s)", s, strings.Join(severityNames[:], ", "))
+}
+
+// Finding is one issue reported by a leg.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Source   string   `json:"source,omitempty"` // leg ID
+	Text     string   `json:"text"`
+}
+
+var (
+	listItemRe = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.*)$`)
+	// A leading [high], **high**, or **high:** tag on a list item.
+	itemTagRe = regexp.MustCompile(`^(?:\[([A-Za-z0-9]+)\]|\*\*([A-Za-z0-9]+):?\*\*:?)\s*(.*)$`)
+)
+
+// ParseFindings extracts findings from a leg's markdown. A finding is a
+// top-level list item under a heading whose first word is a severity
+// ("## Critical Issues", "## High", "### P1"), or any top-level list item
+// tagged with one ("- [high] ...", "- **high:** ..."). Placeholder items
+// such as "..." or "None" are ignored.
+func ParseFindings(markdown, source string) []Finding {
+	var findings []Finding
+	current, inSection := SeverityInfo, false
+	inFence := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			current, inSection = headingSeverity(strings.TrimLeft(line, "#"))
+			continue
+		}
+		m := listItemRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		text := strings.TrimSpace(m[1])
+		sev, ok := current, inSection
+		if t := itemTagRe.FindStringSubmatch(text); t != nil {
+			if tagged, err := ParseSeverity(t[1] + t[2]); err == nil {
+				sev, ok, text = tagged, true, strings.TrimSpace(t[3])
+			}
+		}
+		if !ok || isPlaceholder(text) {
+			continue
+		}
+		findings = append(findings, Finding{Severity: sev, Source: source, Text: text})
+	}
+	return findings
+}
+
+// headingSeverity returns the severity named by a heading's first word.
+func headingSeverity(heading string) (Severity, bool) {
+	fields := strings.Fields(heading)
+	if len(fields) == 0 {
+		return SeverityInfo, false
+	}
+	word := strings.Trim(fields[0], ":()[]*_")
+	sev, err := ParseSeverity(word)
+	return sev, err == nil
+}
+
+func isPlaceholder(text string) bool {
+	switch strings.ToLower(strings.Trim(text, " .…_*()")) {
+	case "", "none", "n/a", "na", "no issues", "nothing", "none found":
+		return true
+	}
+	return false
+}
+
+// AtOrAbove returns the findings at or above min severity.
+func AtOrAbove(findings []Finding, min Severity) []Finding {
+	var out []Finding
+	for _, f := range findings {
+		if f.Severity >= min {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// CountBySeverity counts findings per severity, indexed by Severity.
+func CountBySeverity(findings []Finding) [SeverityCritical + 1]int {
+	var counts [SeverityCritical + 1]int
+	for _, f := range findings {
+		if f.Severity >= SeverityInfo && f.Severity <= SeverityCritical {
+			counts[f.Severity]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,71 @@
+package review
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for in, want := range map[string]Severity{
+		"high": SeverityHigh, "HIGH": SeverityHigh, "P0": SeverityCritical,
+		"major": SeverityHigh, "minor": SeverityMedium, "observations": SeverityInfo,
+	} {
+		if got, err := ParseSeverity(in); err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseSeverity("urgent"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
+
+func TestParseFindings(t *testing.T) {
+	md := "# Security Review\n\n## Summary\n- overall fine\n\n" +
+		"## Critical Issues\n(P0 - Must fix before merge)\n- SQL injection in db.go:12\n  - impact: data loss\n\n" +
+		"## Major Issues\n- ...\n\n" +
+		"## Minor Issues\n1. Missing timeout\n\n" +
+		"## Observations\n- None\n- [high] Token logged in auth.go:40\n\n" +
+		"## High-level notes\n- not a finding\n\n" +
+		"```\n## Critical\n- inside a code block\n```\n"
+	got := ParseFindings(md, "security")
+	want := []Finding{
+		{SeverityCritical, "security", "SQL injection in db.go:12"},
+		{SeverityMedium, "security", "Missing timeout"},
+		{SeverityHigh, "security", "Token logged in auth.go:40"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseFindings = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	tagged := ParseFindings("Findings:\n- **critical:** RCE\n- untagged", "x")
+	if len(tagged) != 1 || tagged[0].Severity != SeverityCritical || tagged[0].Text != "RCE" {
+		t.Errorf("tagged findings = %+v", tagged)
+	}
+}
+
+func TestAtOrAbove(t *testing.T) {
+	findings := []Finding{{Severity: SeverityLow}, {Severity: SeverityHigh}, {Severity: SeverityCritical}}
+	if got := AtOrAbove(findings, SeverityHigh); len(got) != 2 {
+		t.Errorf("AtOrAbove(high) = %+v", got)
+	}
+	counts := CountBySeverity(findings)
+	if counts[SeverityLow] != 1 || counts[SeverityHigh] != 1 || counts[SeverityMedium] != 0 {
+		t.Errorf("CountBySeverity = %v", counts)
+	}
+}
+
+func TestSeverityJSON(t *testing.T) {
+	data, err := json.Marshal(Finding{Severity: SeverityHigh, Text: "x"})
+	if err != nil || string(data) != `{"severity":"high","text":"x"}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+	var f Finding
+	if err := json.Unmarshal([]byte(`{"severity":"P0"}`), &f); err != nil || f.Severity != SeverityCritical {
+		t.Errorf("Unmarshal = %+v, %v", f, err)
+	}
+}
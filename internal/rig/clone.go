@@ -0,0 +1,48 @@
+package rig
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// CloneOptions returns the clone options of the rig at rigPath from its
+// clone settings. Rigs without them get full clones.
+func CloneOptions(rigPath string) git.CloneOptions {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.Clone == nil {
+		return git.CloneOptions{}
+	}
+	return git.CloneOptions{
+		Depth:  settings.Clone.Depth,
+		Filter: settings.Clone.Filter,
+		Sparse: settings.Clone.Sparse,
+	}
+}
+
+// SyncSparsePaths records the rig's clone.sparse settings in repo's config
+// if they changed, so worktrees created from repo check out that subset.
+func SyncSparsePaths(rigPath string, repo *git.Git) error {
+	want := CloneOptions(rigPath).Sparse
+	if slices.Equal(repo.SparsePaths(), want) {
+		return nil
+	}
+	return repo.SetSparsePaths(want)
+}
+
+// saveCloneSettings writes clone into the rig's settings/config.json,
+// creating it from defaults if absent.
+func saveCloneSettings(rigPath string, clone *config.CloneConfig) error {
+	path := config.RigSettingsPath(rigPath)
+	settings, err := config.LoadRigSettings(path)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			return err
+		}
+		settings = config.NewRigSettings()
+	}
+	settings.Clone = clone
+	return config.SaveRigSettings(path, settings)
+}
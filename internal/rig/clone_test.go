@@ -0,0 +1,48 @@
+package rig
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestSyncSparsePaths(t *testing.T) {
+	rigPath := t.TempDir()
+	bare := t.TempDir()
+	if out, err := exec.Command("git", "init", "--bare", bare).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	repo := git.NewGitWithDir(bare, "")
+
+	if got := CloneOptions(rigPath); got.Depth != 0 || got.Filter != "" || got.Sparse != nil {
+		t.Errorf("CloneOptions without settings = %+v, want zero", got)
+	}
+
+	clone := &config.CloneConfig{Depth: 10, Filter: "blob:none", Sparse: []string{"svc/api", "libs"}}
+	if err := saveCloneSettings(rigPath, clone); err != nil {
+		t.Fatalf("saveCloneSettings: %v", err)
+	}
+	if got := CloneOptions(rigPath); got.Depth != 10 || got.Filter != "blob:none" {
+		t.Errorf("CloneOptions = %+v, want depth 10 and filter blob:none", got)
+	}
+	if err := SyncSparsePaths(rigPath, repo); err != nil {
+		t.Fatalf("SyncSparsePaths: %v", err)
+	}
+	if got := repo.SparsePaths(); !slices.Equal(got, clone.Sparse) {
+		t.Errorf("SparsePaths = %q, want %q", got, clone.Sparse)
+	}
+
+	// Removing the setting restores full checkouts.
+	if err := saveCloneSettings(rigPath, nil); err != nil {
+		t.Fatalf("saveCloneSettings: %v", err)
+	}
+	if err := SyncSparsePaths(rigPath, repo); err != nil {
+		t.Fatalf("SyncSparsePaths: %v", err)
+	}
+	if got := repo.SparsePaths(); got != nil {
+		t.Errorf("SparsePaths after clearing = %q, want none", got)
+	}
+}
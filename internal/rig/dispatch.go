@@ -0,0 +1,20 @@
+package rig
+
+import (
+	"github.com/steveyegge/gastown/internal/connection"
+)
+
+// DispatchTarget returns the connection the polecats of the rig at rigPath
+// run on: the host of its ssh remote, or the local host. The description
+// names the machine in messages.
+func DispatchTarget(rigPath string) (conn connection.Connection, description string, err error) {
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil || cfg.Remote == "" {
+		return connection.NewLocalConnection(), "local host", nil
+	}
+	remote, err := connection.ParseRemote(cfg.Remote)
+	if err != nil {
+		return nil, "", err
+	}
+	return remote.Connection(), remote.Host, nil
+}
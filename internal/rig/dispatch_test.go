@@ -0,0 +1,33 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/connection"
+)
+
+func TestDispatchTarget(t *testing.T) {
+	rigPath := t.TempDir()
+
+	conn, desc, err := DispatchTarget(rigPath)
+	if err != nil {
+		t.Fatalf("DispatchTarget() without config: %v", err)
+	}
+	if _, ok := conn.(*connection.LocalConnection); !ok || desc != "local host" {
+		t.Errorf("DispatchTarget() = %T, %q; want local host", conn, desc)
+	}
+
+	cfg := `{"type":"rig","version":1,"name":"build","remote":"ssh://ci@build.example.com/srv/build"}`
+	if err := os.WriteFile(filepath.Join(rigPath, "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	conn, desc, err = DispatchTarget(rigPath)
+	if err != nil {
+		t.Fatalf("DispatchTarget() with remote: %v", err)
+	}
+	if _, ok := conn.(*connection.SSHConnection); !ok || desc != "ci@build.example.com" {
+		t.Errorf("DispatchTarget() = %T, %q; want ssh to ci@build.example.com", conn, desc)
+	}
+}
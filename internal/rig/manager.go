@@ -13,7 +13,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/runtime"
@@ -81,6 +83,7 @@ type RigConfig struct {
 	DefaultBranch string       `json:"default_branch,omitempty"` // main, master, etc.
 	CreatedAt     time.Time    `json:"created_at"`               // when rig was created
 	Beads         *BeadsConfig `json:"beads,omitempty"`
+	Remote        string       `json:"remote,omitempty"` // ssh:// working copy polecats run in
 }
 
 // BeadsConfig represents beads configuration for the rig.
@@ -205,6 +208,10 @@ func (m *Manager) loadRig(name string, entry config.RigEntry) (*Rig, error) {
 		rig.HasMayor = true
 	}
 
+	if cfg, err := LoadRigConfig(rigPath); err == nil {
+		rig.Remote = cfg.Remote
+	}
+
 	return rig, nil
 }
 
@@ -215,6 +222,11 @@ type AddRigOptions struct {
 	BeadsPrefix   string // Beads issue prefix (defaults to derived from name)
 	LocalRepo     string // Optional local repo for reference clones
 	DefaultBranch string // Default branch (defaults to auto-detected from remote)
+	Remote        string // Optional ssh:// working copy to run polecats in
+
+	// Clone makes the rig's repo shallow, partial, or sparse. It is saved
+	// to the rig's settings so later crew clones and worktrees follow it.
+	Clone *config.CloneConfig
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -272,6 +284,12 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		return nil, fmt.Errorf("rig name %q contains invalid characters; hyphens, dots, and spaces are reserved for agent ID parsing. Try %q instead (underscores are allowed)", opts.Name, sanitized)
 	}
 
+	if opts.Remote != "" {
+		if _, err := connection.ParseRemote(opts.Remote); err != nil {
+			return nil, err
+		}
+	}
+
 	rigPath := filepath.Join(m.townRoot, opts.Name)
 
 	// Check if directory already exists
@@ -318,6 +336,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		Beads: &BeadsConfig{
 			Prefix: opts.BeadsPrefix,
 		},
+		Remote: opts.Remote,
 	}
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
 		return nil, fmt.Errorf("saving rig config: %w", err)
@@ -328,6 +347,15 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// Mayor remains a separate clone (doesn't need branch visibility).
 	fmt.Printf("  Cloning repository (this may take a moment)...\n")
 	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	var cloneOpts git.CloneOptions
+	if opts.Clone != nil {
+		if err := saveCloneSettings(rigPath, opts.Clone); err != nil {
+			return nil, fmt.Errorf("saving clone settings: %w", err)
+		}
+		cloneOpts = CloneOptions(rigPath)
+	}
+	m.git.SetCloneOptions(cloneOpts)
+	defer m.git.SetCloneOptions(git.CloneOptions{})
 	if localRepo != "" {
 		if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
@@ -364,7 +392,9 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// Create mayor as regular clone (separate from bare repo).
 	// Mayor doesn't need to see polecat branches - that's refinery's job.
 	// This also allows mayor to stay on the default branch without conflicting with refinery.
+	// Mayor gets the whole tree; sparse paths only narrow worker checkouts.
 	fmt.Printf("  Creating mayor clone...\n")
+	m.git.SetCloneOptions(git.CloneOptions{Depth: cloneOpts.Depth, Filter: cloneOpts.Filter})
 	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
 	if err := os.MkdirAll(filepath.Dir(mayorRigPath), 0755); err != nil {
 		return nil, fmt.Errorf("creating mayor dir: %w", err)
@@ -427,13 +457,13 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		if _, err := os.Stat(sourceBeadsDB); os.IsNotExist(err) {
 			cmd := exec.Command("bd", "--no-daemon", "init", "--prefix", opts.BeadsPrefix) // opts.BeadsPrefix validated earlier
 			cmd.Dir = mayorRigPath
-			if output, err := cmd.CombinedOutput(); err != nil {
+			if output, err := cmdtrace.CombinedOutput(cmd); err != nil {
 				fmt.Printf("  Warning: Could not init bd database: %v (%s)\n", err, strings.TrimSpace(string(output)))
 			}
 			// Configure custom types for Gas Town (beads v0.46.0+)
 			configCmd := exec.Command("bd", "--no-daemon", "config", "set", "types.custom", constants.BeadsCustomTypes)
 			configCmd.Dir = mayorRigPath
-			_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+			_, _ = cmdtrace.CombinedOutput(configCmd) // Ignore errors - older beads don't need this
 		}
 	}
 
@@ -665,7 +695,7 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 	cmd := exec.Command("bd", "--no-daemon", "init", "--prefix", prefix)
 	cmd.Dir = rigPath
 	cmd.Env = filteredEnv
-	_, err := cmd.CombinedOutput()
+	_, err := cmdtrace.CombinedOutput(cmd)
 	if err != nil {
 		// bd might not be installed or failed, create minimal structure
 		// Note: beads currently expects YAML format for config
@@ -682,7 +712,7 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 	configCmd.Dir = rigPath
 	configCmd.Env = filteredEnv
 	// Ignore errors - older beads versions don't need this
-	_, _ = configCmd.CombinedOutput()
+	_, _ = cmdtrace.CombinedOutput(configCmd)
 
 	// Ensure database has repository fingerprint (GH #25).
 	// This is idempotent - safe on both new and legacy (pre-0.17.5) databases.
@@ -691,7 +721,7 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 	migrateCmd.Dir = rigPath
 	migrateCmd.Env = filteredEnv
 	// Ignore errors - fingerprint is optional for functionality
-	_, _ = migrateCmd.CombinedOutput()
+	_, _ = cmdtrace.CombinedOutput(migrateCmd)
 
 	// Ensure issues.jsonl exists to prevent bd auto-export from corrupting other files.
 	// bd init creates beads.db but not issues.jsonl in SQLite mode.
@@ -1223,7 +1253,7 @@ func (m *Manager) seedPatrolMolecules(rigPath string) error {
 	// Use bd command to seed molecules (more reliable than internal API)
 	cmd := exec.Command("bd", "--no-daemon", "mol", "seed", "--patrol")
 	cmd.Dir = rigPath
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		// Fallback: bd mol seed might not support --patrol yet
 		// Try creating them individually via bd create
 		return m.seedPatrolMoleculesManually(rigPath)
@@ -1256,7 +1286,7 @@ func (m *Manager) seedPatrolMoleculesManually(rigPath string) error {
 		// Check if already exists by title
 		checkCmd := exec.Command("bd", "--no-daemon", "list", "--type=molecule", "--format=json")
 		checkCmd.Dir = rigPath
-		output, _ := checkCmd.Output()
+		output, _ := cmdtrace.Output(checkCmd)
 		if strings.Contains(string(output), mol.title) {
 			continue // Already exists
 		}
@@ -1269,7 +1299,7 @@ func (m *Manager) seedPatrolMoleculesManually(rigPath string) error {
 			"--priority=2",
 		)
 		cmd.Dir = rigPath
-		if err := cmd.Run(); err != nil {
+		if err := cmdtrace.Run(cmd); err != nil {
 			// Non-fatal, continue with others
 			continue
 		}
@@ -0,0 +1,162 @@
+package rig
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/gitignore"
+)
+
+//go:embed templates/*.json
+var templatesFS embed.FS
+
+// ErrTemplateNotFound is returned when no built-in or town template has the
+// requested name.
+var ErrTemplateNotFound = errors.New("rig template not found")
+
+// Template scaffolds a new rig: settings defaults (including formula
+// bindings), the crew workspaces to create, and .gitignore entries for the
+// project's build outputs.
+type Template struct {
+	Name        string `json:"-"`
+	Source      string `json:"-"` // "built-in" or the template file path
+	Description string `json:"description,omitempty"`
+
+	// Settings is overlaid onto the rig's settings/config.json; it has the
+	// same shape as that file.
+	Settings json.RawMessage `json:"settings,omitempty"`
+
+	// Crew lists crew workspaces to create.
+	Crew []string `json:"crew,omitempty"`
+
+	// Gitignore lists project patterns added to the rig clone's .gitignore,
+	// outside the gt-managed block.
+	Gitignore []string `json:"gitignore,omitempty"`
+}
+
+// TemplatesDir returns the directory of a town's custom rig templates.
+func TemplatesDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "rig-templates")
+}
+
+// LoadTemplate returns the named template. A town template
+// (settings/rig-templates/<name>.json) takes precedence over a built-in
+// template of the same name.
+func LoadTemplate(townRoot, name string) (*Template, error) {
+	path := filepath.Join(TemplatesDir(townRoot), name+".json")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town settings dir
+	source := path
+	if os.IsNotExist(err) {
+		data, err = templatesFS.ReadFile("templates/" + name + ".json")
+		source = "built-in"
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("reading template %s: %w", path, err)
+	}
+	return parseTemplate(name, source, data)
+}
+
+// ListTemplates returns all templates available to a town, sorted by name.
+func ListTemplates(townRoot string) ([]*Template, error) {
+	names := make(map[string]bool)
+	entries, _ := templatesFS.ReadDir("templates")
+	for _, e := range entries {
+		names[strings.TrimSuffix(e.Name(), ".json")] = true
+	}
+	if entries, err := os.ReadDir(TemplatesDir(townRoot)); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+				names[strings.TrimSuffix(e.Name(), ".json")] = true
+			}
+		}
+	}
+
+	var templates []*Template
+	for name := range names {
+		t, err := LoadTemplate(townRoot, name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// parseTemplate decodes a template and checks that its settings overlay
+// produces valid rig settings.
+func parseTemplate(name, source string, data []byte) (*Template, error) {
+	t := &Template{Name: name, Source: source}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", name, err)
+	}
+	if _, err := t.overlay(config.NewRigSettings()); err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// overlay applies the template's settings on top of settings.
+func (t *Template) overlay(settings *config.RigSettings) (*config.RigSettings, error) {
+	if len(t.Settings) > 0 {
+		if err := json.Unmarshal(t.Settings, settings); err != nil {
+			return nil, fmt.Errorf("invalid settings: %w", err)
+		}
+	}
+	if err := config.ValidateRigSettings(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ApplySettings overlays the template's settings onto the rig's
+// settings/config.json, creating it from defaults if absent.
+func (t *Template) ApplySettings(rigPath string) error {
+	path := config.RigSettingsPath(rigPath)
+	settings, err := config.LoadRigSettings(path)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			return err
+		}
+		settings = config.NewRigSettings()
+	}
+	if settings, err = t.overlay(settings); err != nil {
+		return err
+	}
+	return config.SaveRigSettings(path, settings)
+}
+
+// ApplyGitignore adds the template's patterns to the .gitignore in dir
+// (outside the managed block) and syncs the gt-managed block, so the clone
+// passes gt doctor's runtime-gitignore check.
+func (t *Template) ApplyGitignore(dir string) error {
+	path := filepath.Join(dir, ".gitignore")
+	if missing := gitignore.Missing(path, t.Gitignore); len(missing) > 0 {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is a rig clone's .gitignore
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		content := string(data)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		content += fmt.Sprintf("# %s rig template\n%s\n", t.Name, strings.Join(missing, "\n"))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil { //nolint:gosec // G306: .gitignore should be readable by git tools
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+	_, err := gitignore.Sync(path, gitignore.RequiredPatterns)
+	return err
+}
@@ -0,0 +1,111 @@
+package rig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/gitignore"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	townRoot := t.TempDir()
+	templates, err := ListTemplates(townRoot)
+	if err != nil {
+		t.Fatalf("ListTemplates: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, tmpl := range templates {
+		names[tmpl.Name] = true
+		if tmpl.Source != "built-in" || tmpl.Description == "" {
+			t.Errorf("%s: source=%q description=%q", tmpl.Name, tmpl.Source, tmpl.Description)
+		}
+	}
+	for _, want := range []string{"go-service", "node-app", "docs"} {
+		if !names[want] {
+			t.Errorf("built-in template %s missing", want)
+		}
+	}
+}
+
+func TestLoadTemplate_TownOverride(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := TemplatesDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	custom := `{"description": "Ours", "settings": {"workflow": {"default_formula": "shiny"}}, "crew": ["ops"]}`
+	if err := os.WriteFile(filepath.Join(dir, "go-service.json"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := LoadTemplate(townRoot, "go-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Description != "Ours" || len(tmpl.Crew) != 1 || tmpl.Crew[0] != "ops" {
+		t.Errorf("town template not preferred: %+v", tmpl)
+	}
+
+	if _, err := LoadTemplate(townRoot, "nope"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("LoadTemplate(nope) error = %v, want ErrTemplateNotFound", err)
+	}
+
+	bad := `{"settings": {"merge_queue": {"on_conflict": "explode"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTemplate(townRoot, "bad"); err == nil {
+		t.Error("expected invalid settings to be rejected")
+	}
+}
+
+func TestTemplateApply(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "api")
+	tmpl, err := LoadTemplate(townRoot, "go-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tmpl.ApplySettings(rigPath); err != nil {
+		t.Fatalf("ApplySettings: %v", err)
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Workflow == nil || settings.Workflow.DefaultFormula != "code-review" {
+		t.Errorf("Workflow = %+v", settings.Workflow)
+	}
+	if settings.MergeQueue == nil || settings.MergeQueue.TestCommand != "go test ./..." || settings.MergeQueue.TargetBranch == "" {
+		t.Errorf("MergeQueue = %+v, want template test command over defaults", settings.MergeQueue)
+	}
+
+	clone := filepath.Join(rigPath, "mayor", "rig")
+	if err := os.MkdirAll(clone, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(clone, ".gitignore"), []byte("bin/\nvendor/"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := tmpl.ApplyGitignore(clone); err != nil {
+			t.Fatalf("ApplyGitignore: %v", err)
+		}
+	}
+	path := filepath.Join(clone, ".gitignore")
+	if missing := gitignore.Missing(path, append(tmpl.Gitignore, gitignore.RequiredPatterns...)); len(missing) > 0 {
+		t.Errorf(".gitignore missing %v", missing)
+	}
+	data, _ := os.ReadFile(path)
+	if n := strings.Count(string(data), "coverage.out"); n != 1 {
+		t.Errorf("coverage.out appears %d times after re-apply:\n%s", n, data)
+	}
+	if n := strings.Count(string(data), "bin/"); n != 1 {
+		t.Errorf("existing bin/ duplicated:\n%s", data)
+	}
+}
@@ -37,6 +37,10 @@ type Rig struct {
 
 	// HasMayor indicates if the rig has a mayor clone.
 	HasMayor bool `json:"has_mayor"`
+
+	// Remote is the ssh:// working copy the rig's polecats run in, if the
+	// rig executes on another machine.
+	Remote string `json:"remote,omitempty"`
 }
 
 // AgentDirs are the standard agent directories in a rig.
@@ -0,0 +1,103 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Bitbucket reads pull requests through the Bitbucket Cloud API.
+type Bitbucket struct {
+	BaseURL   string // https://api.bitbucket.org
+	Workspace string
+	Slug      string
+	Token     string // Repository, project, or workspace access token
+}
+
+func (b *Bitbucket) Name() string { return ProviderBitbucket }
+
+func (b *Bitbucket) auth() string {
+	if b.Token == "" {
+		return ""
+	}
+	return "Bearer " + b.Token
+}
+
+func (b *Bitbucket) prURL(number int) string {
+	return fmt.Sprintf("%s/2.0/repositories/%s/%s/pullrequests/%d", b.BaseURL, b.Workspace, b.Slug, number)
+}
+
+func (b *Bitbucket) PR(ctx context.Context, number int) (*PR, error) {
+	var raw struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+		State string `json:"state"` // OPEN, MERGED, DECLINED, SUPERSEDED
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+		Source struct {
+			Commit struct {
+				Hash string `json:"hash"`
+			} `json:"commit"`
+		} `json:"source"`
+	}
+	if err := getJSON(ctx, b.prURL(number), b.auth(), &raw); err != nil {
+		return nil, err
+	}
+	pr := &PR{Number: raw.ID, Title: raw.Title, URL: raw.Links.HTML.Href, HeadSHA: raw.Source.Commit.Hash}
+	switch raw.State {
+	case "OPEN":
+		pr.State = StateOpen
+	case "MERGED":
+		pr.State = StateMerged
+	default:
+		pr.State = StateClosed
+	}
+
+	// The diffstat is paginated; each page links the next.
+	for url := b.prURL(number) + "/diffstat"; url != ""; {
+		var page struct {
+			Values []struct {
+				LinesAdded   int `json:"lines_added"`
+				LinesRemoved int `json:"lines_removed"`
+				New          *struct {
+					Path string `json:"path"`
+				} `json:"new"`
+				Old *struct {
+					Path string `json:"path"`
+				} `json:"old"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if err := getJSON(ctx, url, b.auth(), &page); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			f := ChangedFile{Additions: v.LinesAdded, Deletions: v.LinesRemoved}
+			if v.New != nil {
+				f.Path = v.New.Path
+			} else if v.Old != nil {
+				f.Path = v.Old.Path // Deleted file
+			}
+			pr.Files = append(pr.Files, f)
+		}
+		url = page.Next
+	}
+	return pr, nil
+}
+
+func (b *Bitbucket) Diff(ctx context.Context, number int) (string, error) {
+	data, err := apiRequest(ctx, http.MethodGet, b.prURL(number)+"/diff", b.auth(), nil)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (b *Bitbucket) Comment(ctx context.Context, number int, body string) error {
+	_, err := apiRequest(ctx, http.MethodPost, b.prURL(number)+"/comments", b.auth(),
+		map[string]interface{}{"content": map[string]string{"raw": body}})
+	return err
+}
@@ -0,0 +1,88 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// giteaPageSize is the page size for listing a Gitea PR's files.
+const giteaPageSize = 50
+
+// Gitea reads pull requests through a Gitea (or Forgejo) server's API.
+type Gitea struct {
+	BaseURL string // e.g. https://gitea.example.com
+	Owner   string
+	Repo    string
+	Token   string
+}
+
+func (g *Gitea) Name() string { return ProviderGitea }
+
+func (g *Gitea) auth() string {
+	if g.Token == "" {
+		return ""
+	}
+	return "token " + g.Token
+}
+
+func (g *Gitea) repoURL() string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/%s", g.BaseURL, g.Owner, g.Repo)
+}
+
+func (g *Gitea) PR(ctx context.Context, number int) (*PR, error) {
+	var raw struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		State   string `json:"state"` // open or closed
+		Merged  bool   `json:"merged"`
+		Head    struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/pulls/%d", g.repoURL(), number), g.auth(), &raw); err != nil {
+		return nil, err
+	}
+	pr := &PR{Number: raw.Number, Title: raw.Title, URL: raw.HTMLURL, HeadSHA: raw.Head.SHA, State: StateOpen}
+	switch {
+	case raw.Merged:
+		pr.State = StateMerged
+	case raw.State == "closed":
+		pr.State = StateClosed
+	}
+
+	for page := 1; ; page++ {
+		var files []struct {
+			Filename  string `json:"filename"`
+			Additions int    `json:"additions"`
+			Deletions int    `json:"deletions"`
+		}
+		url := fmt.Sprintf("%s/pulls/%d/files?limit=%d&page=%d", g.repoURL(), number, giteaPageSize, page)
+		if err := getJSON(ctx, url, g.auth(), &files); err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			pr.Files = append(pr.Files, ChangedFile{Path: f.Filename, Additions: f.Additions, Deletions: f.Deletions})
+		}
+		if len(files) < giteaPageSize {
+			break
+		}
+	}
+	return pr, nil
+}
+
+func (g *Gitea) Diff(ctx context.Context, number int) (string, error) {
+	data, err := apiRequest(ctx, http.MethodGet, fmt.Sprintf("%s/pulls/%d.diff", g.repoURL(), number), g.auth(), nil)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (g *Gitea) Comment(ctx context.Context, number int, body string) error {
+	// PR comments are issue comments in Gitea.
+	_, err := apiRequest(ctx, http.MethodPost, fmt.Sprintf("%s/issues/%d/comments", g.repoURL(), number), g.auth(),
+		map[string]string{"body": body})
+	return err
+}
@@ -0,0 +1,71 @@
+package scm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/steveyegge/gastown/internal/gh"
+)
+
+// GitHub reads pull requests through the gh CLI, so gh's login, host
+// configuration, and rate-limit backoff apply.
+type GitHub struct {
+	Host  string // Empty means github.com
+	Repo  string // owner/name; empty means the repository in the working directory
+	Token string
+}
+
+func (g *GitHub) Name() string { return ProviderGitHub }
+
+func (g *GitHub) run(ctx context.Context, args ...string) ([]byte, error) {
+	if g.Repo != "" {
+		repo := g.Repo
+		if g.Host != "" {
+			repo = g.Host + "/" + repo
+		}
+		args = append(args, "--repo", repo)
+	}
+	return gh.RunHost(ctx, gh.Host{Name: g.Host, Token: g.Token}, "", args...)
+}
+
+func (g *GitHub) PR(ctx context.Context, number int) (*PR, error) {
+	out, err := g.run(ctx, "pr", "view", strconv.Itoa(number), "--json", "number,title,url,state,headRefOid,files")
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		Number     int    `json:"number"`
+		Title      string `json:"title"`
+		URL        string `json:"url"`
+		State      string `json:"state"`
+		HeadRefOid string `json:"headRefOid"`
+		Files      []struct {
+			Path      string `json:"path"`
+			Additions int    `json:"additions"`
+			Deletions int    `json:"deletions"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("parsing gh pr view: %w", err)
+	}
+	pr := &PR{Number: raw.Number, Title: raw.Title, URL: raw.URL, State: raw.State, HeadSHA: raw.HeadRefOid}
+	for _, f := range raw.Files {
+		pr.Files = append(pr.Files, ChangedFile{Path: f.Path, Additions: f.Additions, Deletions: f.Deletions})
+	}
+	return pr, nil
+}
+
+func (g *GitHub) Diff(ctx context.Context, number int) (string, error) {
+	out, err := g.run(ctx, "pr", "diff", strconv.Itoa(number))
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (g *GitHub) Comment(ctx context.Context, number int, body string) error {
+	_, err := g.run(ctx, "pr", "comment", strconv.Itoa(number), "--body", body)
+	return err
+}
@@ -0,0 +1,95 @@
+package scm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/gh"
+	"github.com/steveyegge/gastown/internal/offline"
+)
+
+// httpClient serves the REST providers. Its transport is the default one,
+// which honors HTTPS_PROXY and NO_PROXY.
+var httpClient = &http.Client{Timeout: 60 * time.Second}
+
+// apiRequest sends a request to a REST API and returns the response body.
+// in, if non-nil, is sent as JSON. Rate-limit responses become a
+// *gh.RateLimitError so callers treat every host's limits alike. In
+// offline mode it fails without sending anything.
+func apiRequest(ctx context.Context, method, url, auth string, in interface{}) ([]byte, error) {
+	if err := offline.Check(method + " " + url); err != nil {
+		return nil, err
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", url, err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0") {
+		return nil, &gh.RateLimitError{Reset: rateLimitReset(resp.Header)}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200] + "..."
+		}
+		return nil, fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, msg)
+	}
+	return data, nil
+}
+
+// getJSON GETs url and decodes the JSON response into out.
+func getJSON(ctx context.Context, url, auth string, out interface{}) error {
+	data, err := apiRequest(ctx, http.MethodGet, url, auth, nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parsing %s: %w", url, err)
+	}
+	return nil
+}
+
+// rateLimitReset reads when a rate limit lifts from Retry-After (seconds)
+// or X-RateLimit-Reset (Unix time). Zero if neither is present.
+func rateLimitReset(h http.Header) time.Time {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	if unix, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil && unix > 0 {
+		return time.Unix(unix, 0)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,130 @@
+package scm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/offline"
+)
+
+// ProviderLocal names the Local provider.
+const ProviderLocal = "local"
+
+// Local reads pull requests from refs already fetched into a git clone, so
+// PR runs work offline. It knows only what git has: the head commit's
+// subject stands in for the title, and the URL and state are unknown.
+type Local struct {
+	Dir string // Clone to read
+}
+
+func (l *Local) Name() string { return ProviderLocal }
+
+// localPRRefs are where a PR's head is found in a clone, in order: gh and
+// GitLab-style fetch refspecs, and a plain fetch of pull/N/head.
+var localPRRefs = []string{
+	"refs/remotes/origin/pr/%d",
+	"refs/remotes/origin/pull/%d/head",
+	"refs/remotes/upstream/pr/%d",
+	"refs/pull/%d/head",
+}
+
+// localBaseRefs are the branches a PR's changes are measured against.
+var localBaseRefs = []string{"refs/remotes/origin/HEAD", "refs/remotes/origin/main", "refs/remotes/origin/master", "refs/heads/main", "refs/heads/master"}
+
+// HeadRef returns the ref holding PR number's head, or an error saying how
+// to fetch it.
+func (l *Local) HeadRef(ctx context.Context, number int) (string, error) {
+	for _, pattern := range localPRRefs {
+		ref := fmt.Sprintf(pattern, number)
+		if _, err := l.git(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err == nil {
+			return ref, nil
+		}
+	}
+	return "", fmt.Errorf("PR #%d isn't in the local clone %s; before going offline fetch it with: git fetch origin pull/%d/head:refs/remotes/origin/pr/%d",
+		number, l.Dir, number, number)
+}
+
+// mergeBase returns the PR head's ref and the commit it branched from.
+func (l *Local) mergeBase(ctx context.Context, number int) (head, base string, err error) {
+	head, err = l.HeadRef(ctx, number)
+	if err != nil {
+		return "", "", err
+	}
+	for _, ref := range localBaseRefs {
+		if out, err := l.git(ctx, "merge-base", ref, head); err == nil {
+			return head, strings.TrimSpace(out), nil
+		}
+	}
+	return "", "", fmt.Errorf("no base branch (origin/HEAD, main, or master) in %s to compare PR #%d with", l.Dir, number)
+}
+
+func (l *Local) PR(ctx context.Context, number int) (*PR, error) {
+	head, base, err := l.mergeBase(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	sha, err := l.git(ctx, "rev-parse", head)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := l.git(ctx, "log", "-1", "--format=%s", head)
+	if err != nil {
+		return nil, err
+	}
+	numstat, err := l.git(ctx, "diff", "--numstat", base, head)
+	if err != nil {
+		return nil, err
+	}
+	return &PR{
+		Number:  number,
+		Title:   strings.TrimSpace(subject),
+		HeadSHA: strings.TrimSpace(sha),
+		Files:   parseNumstat(numstat),
+	}, nil
+}
+
+func (l *Local) Diff(ctx context.Context, number int) (string, error) {
+	head, base, err := l.mergeBase(ctx, number)
+	if err != nil {
+		return "", err
+	}
+	return l.git(ctx, "diff", base, head)
+}
+
+func (l *Local) Comment(ctx context.Context, number int, body string) error {
+	if err := offline.Check("comment on PR #" + strconv.Itoa(number)); err != nil {
+		return err
+	}
+	return fmt.Errorf("the local provider can't comment on PRs")
+}
+
+func (l *Local) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", l.Dir}, args...)...) //nolint:gosec // G204: fixed git subcommands
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// parseNumstat parses git diff --numstat output. Binary files, shown with
+// "-" counts, count as zero lines.
+func parseNumstat(out string) []ChangedFile {
+	var files []ChangedFile
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		add, _ := strconv.Atoi(fields[0])
+		del, _ := strconv.Atoi(fields[1])
+		files = append(files, ChangedFile{Path: fields[2], Additions: add, Deletions: del})
+	}
+	return files
+}
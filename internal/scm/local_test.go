@@ -0,0 +1,72 @@
+package scm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/offline"
+)
+
+func TestLocalPR(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	write("a.go", "package a\n")
+	git("add", ".")
+	git("commit", "-qm", "base")
+	git("checkout", "-qb", "feature")
+	write("a.go", "package a\n\nfunc A() {}\n")
+	write("b.go", "package a\n")
+	git("add", ".")
+	git("commit", "-qm", "Add A and b.go")
+	git("update-ref", "refs/remotes/origin/pr/7", "HEAD")
+	git("checkout", "-q", "main")
+
+	l := &Local{Dir: dir}
+	pr, err := l.PR(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("PR: %v", err)
+	}
+	if pr.Title != "Add A and b.go" || pr.HeadSHA == "" || len(pr.Files) != 2 {
+		t.Errorf("PR = %+v", pr)
+	}
+	if pr.Files[0].Path != "a.go" || pr.Files[0].Additions != 2 {
+		t.Errorf("Files[0] = %+v, want a.go +2", pr.Files[0])
+	}
+	diff, err := l.Diff(context.Background(), 7)
+	if err != nil || !strings.Contains(diff, "+func A() {}") {
+		t.Errorf("Diff = %q, %v", diff, err)
+	}
+
+	if _, err := l.PR(context.Background(), 8); err == nil || !strings.Contains(err.Error(), "git fetch origin pull/8/head") {
+		t.Errorf("missing PR: err = %v, want a fetch hint", err)
+	}
+}
+
+func TestAPIRequestOffline(t *testing.T) {
+	t.Setenv(offline.EnvVar, "1")
+	if _, err := apiRequest(context.Background(), "GET", "http://127.0.0.1:1/never", "", nil); !errors.Is(err, offline.ErrOffline) {
+		t.Errorf("apiRequest offline: err = %v, want ErrOffline", err)
+	}
+}
@@ -0,0 +1,110 @@
+// Package scm reads pull requests from the source hosts rigs live on:
+// GitHub (through gh), Gitea, and Bitbucket Cloud (through their REST
+// APIs).
+package scm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Provider names.
+const (
+	ProviderGitHub    = "github"
+	ProviderGitea     = "gitea"
+	ProviderBitbucket = "bitbucket"
+)
+
+// PR states, normalized across providers.
+const (
+	StateOpen   = "OPEN"
+	StateClosed = "CLOSED"
+	StateMerged = "MERGED"
+)
+
+// PR is a pull request's metadata.
+type PR struct {
+	Number  int
+	Title   string
+	URL     string
+	State   string // StateOpen, StateClosed, or StateMerged
+	HeadSHA string
+	Files   []ChangedFile
+}
+
+// ChangedFile is a file a pull request touches.
+type ChangedFile struct {
+	Path      string
+	Additions int
+	Deletions int
+}
+
+// Provider reads and comments on a repository's pull requests.
+type Provider interface {
+	// Name returns the provider name, e.g. ProviderGitea.
+	Name() string
+
+	// PR returns a pull request's metadata and changed files.
+	PR(ctx context.Context, number int) (*PR, error)
+
+	// Diff returns a pull request's unified diff.
+	Diff(ctx context.Context, number int) (string, error)
+
+	// Comment posts body (markdown) as a comment on a pull request.
+	Comment(ctx context.Context, number int, body string) error
+}
+
+// Config selects and configures a provider.
+type Config struct {
+	Provider string // ProviderGitHub (default), ProviderGitea, or ProviderBitbucket
+	Host     string // Server hostname; empty means the provider's public host
+	Repo     string // owner/name (workspace/slug on Bitbucket)
+	Token    string // API token; empty means anonymous (GitHub: gh's login)
+}
+
+// New returns the provider cfg describes.
+func New(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case "", ProviderGitHub:
+		return &GitHub{Host: cfg.Host, Repo: cfg.Repo, Token: cfg.Token}, nil
+	case ProviderGitea:
+		owner, name, err := splitRepo(cfg.Repo)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Host == "" {
+			return nil, fmt.Errorf("gitea needs a host")
+		}
+		return &Gitea{BaseURL: baseURL(cfg.Host), Owner: owner, Repo: name, Token: cfg.Token}, nil
+	case ProviderBitbucket:
+		workspace, slug, err := splitRepo(cfg.Repo)
+		if err != nil {
+			return nil, err
+		}
+		api := "https://api.bitbucket.org"
+		if cfg.Host != "" && cfg.Host != "bitbucket.org" {
+			api = baseURL(cfg.Host)
+		}
+		return &Bitbucket{BaseURL: api, Workspace: workspace, Slug: slug, Token: cfg.Token}, nil
+	}
+	return nil, fmt.Errorf("unknown scm provider %q (want github, gitea, or bitbucket)", cfg.Provider)
+}
+
+// splitRepo splits "owner/name".
+func splitRepo(repo string) (owner, name string, err error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("repo must be owner/name, got %q", repo)
+	}
+	return owner, name, nil
+}
+
+// baseURL turns a hostname into an https URL, keeping an explicit scheme
+// (for plain-http test servers and internal hosts).
+func baseURL(host string) string {
+	if strings.Contains(host, "://") {
+		return strings.TrimRight(host, "/")
+	}
+	return "https://" + strings.TrimRight(host, "/")
+}
@@ -0,0 +1,159 @@
+package scm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/gh"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		cfg     Config
+		want    string
+		wantErr bool
+	}{
+		{Config{}, ProviderGitHub, false},
+		{Config{Provider: "gitea", Host: "gitea.example.com", Repo: "org/app"}, ProviderGitea, false},
+		{Config{Provider: "gitea", Repo: "org/app"}, "", true},
+		{Config{Provider: "bitbucket", Repo: "team/app"}, ProviderBitbucket, false},
+		{Config{Provider: "bitbucket", Repo: "app"}, "", true},
+		{Config{Provider: "svn"}, "", true},
+	} {
+		p, err := New(tc.cfg)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("New(%+v) error = %v, wantErr %v", tc.cfg, err, tc.wantErr)
+			continue
+		}
+		if err == nil && p.Name() != tc.want {
+			t.Errorf("New(%+v) = %s, want %s", tc.cfg, p.Name(), tc.want)
+		}
+	}
+}
+
+func TestGitea(t *testing.T) {
+	var comment map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/org/app/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token t0k" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"number": 7, "title": "Add cache", "html_url": "https://gitea.example.com/org/app/pulls/7",
+			"state": "closed", "merged": true, "head": {"sha": "abc123"}}`)
+	})
+	mux.HandleFunc("/api/v1/repos/org/app/pulls/7/files", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "1" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[{"filename": "cache.go", "additions": 40, "deletions": 2}]`)
+	})
+	mux.HandleFunc("/api/v1/repos/org/app/pulls/7.diff", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "diff --git a/cache.go b/cache.go\n")
+	})
+	mux.HandleFunc("/api/v1/repos/org/app/issues/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&comment)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p, err := New(Config{Provider: ProviderGitea, Host: srv.URL, Repo: "org/app", Token: "t0k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	pr, err := p.PR(ctx, 7)
+	if err != nil {
+		t.Fatalf("PR: %v", err)
+	}
+	want := &PR{Number: 7, Title: "Add cache", URL: "https://gitea.example.com/org/app/pulls/7", State: StateMerged,
+		HeadSHA: "abc123", Files: []ChangedFile{{Path: "cache.go", Additions: 40, Deletions: 2}}}
+	if !reflect.DeepEqual(pr, want) {
+		t.Errorf("PR = %+v, want %+v", pr, want)
+	}
+	if diff, err := p.Diff(ctx, 7); err != nil || diff != "diff --git a/cache.go b/cache.go\n" {
+		t.Errorf("Diff = %q, %v", diff, err)
+	}
+	if err := p.Comment(ctx, 7, "LGTM"); err != nil || comment["body"] != "LGTM" {
+		t.Errorf("Comment posted %v, err %v", comment, err)
+	}
+}
+
+func TestBitbucket(t *testing.T) {
+	var comment map[string]map[string]string
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2.0/repositories/team/app/pullrequests/3", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0k" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"id": 3, "title": "Fix login", "state": "DECLINED",
+			"links": {"html": {"href": "https://bitbucket.org/team/app/pull-requests/3"}},
+			"source": {"commit": {"hash": "def456"}}}`)
+	})
+	mux.HandleFunc("/2.0/repositories/team/app/pullrequests/3/diffstat", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"values": [{"lines_added": 0, "lines_removed": 9, "old": {"path": "legacy.go"}}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"values": [{"lines_added": 5, "lines_removed": 1, "new": {"path": "login.go"}}], "next": "%s/2.0/repositories/team/app/pullrequests/3/diffstat?page=2"}`, srv.URL)
+	})
+	mux.HandleFunc("/2.0/repositories/team/app/pullrequests/3/diff", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "diff --git a/login.go b/login.go\n")
+	})
+	mux.HandleFunc("/2.0/repositories/team/app/pullrequests/3/comments", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&comment)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{}`)
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	p, err := New(Config{Provider: ProviderBitbucket, Host: srv.URL, Repo: "team/app", Token: "t0k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	pr, err := p.PR(ctx, 3)
+	if err != nil {
+		t.Fatalf("PR: %v", err)
+	}
+	want := &PR{Number: 3, Title: "Fix login", URL: "https://bitbucket.org/team/app/pull-requests/3", State: StateClosed,
+		HeadSHA: "def456", Files: []ChangedFile{{Path: "login.go", Additions: 5, Deletions: 1}, {Path: "legacy.go", Deletions: 9}}}
+	if !reflect.DeepEqual(pr, want) {
+		t.Errorf("PR = %+v, want %+v", pr, want)
+	}
+	if diff, err := p.Diff(ctx, 3); err != nil || diff != "diff --git a/login.go b/login.go\n" {
+		t.Errorf("Diff = %q, %v", diff, err)
+	}
+	if err := p.Comment(ctx, 3, "Looks good"); err != nil || comment["content"]["raw"] != "Looks good" {
+		t.Errorf("Comment posted %v, err %v", comment, err)
+	}
+}
+
+func TestAPIRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, "slow down")
+	}))
+	defer srv.Close()
+
+	p, _ := New(Config{Provider: ProviderGitea, Host: srv.URL, Repo: "org/app"})
+	_, err := p.PR(context.Background(), 1)
+	var rl *gh.RateLimitError
+	if !errors.As(err, &rl) || rl.Reset.IsZero() {
+		t.Fatalf("err = %v, want a rate limit error with a reset time", err)
+	}
+}
@@ -0,0 +1,110 @@
+package scm
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PRRef identifies a pull request by its web URL.
+type PRRef struct {
+	Provider string
+	Host     string // Hostname, prefixed with http:// for plain-http servers
+	Repo     string // owner/name (workspace/slug on Bitbucket)
+	Number   int
+}
+
+// ParsePRURL parses a pull request's web URL, as copied from a browser:
+//
+//	https://github.com/org/app/pull/123         (GitHub, GitHub Enterprise)
+//	https://gitea.example.com/org/app/pulls/123 (Gitea, Forgejo)
+//	https://bitbucket.org/team/app/pull-requests/123
+//
+// Trailing path segments (/files, /commits), queries, and fragments are
+// ignored.
+func ParsePRURL(s string) (PRRef, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return PRRef{}, fmt.Errorf("not a PR URL: %q", s)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) >= 3 && parts[2] == "-" {
+		return PRRef{}, fmt.Errorf("GitLab merge requests are not supported: %s", s)
+	}
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" {
+		return PRRef{}, fmt.Errorf("not a PR URL: %q", s)
+	}
+
+	ref := PRRef{Host: strings.ToLower(u.Host), Repo: parts[0] + "/" + strings.TrimSuffix(parts[1], ".git")}
+	if u.Scheme == "http" {
+		ref.Host = "http://" + ref.Host
+	}
+	switch parts[2] {
+	case "pull":
+		ref.Provider = ProviderGitHub
+	case "pulls":
+		ref.Provider = ProviderGitea
+	case "pull-requests":
+		ref.Provider = ProviderBitbucket
+	default:
+		return PRRef{}, fmt.Errorf("not a PR URL: %q", s)
+	}
+	ref.Number, err = strconv.Atoi(parts[3])
+	if err != nil || ref.Number <= 0 {
+		return PRRef{}, fmt.Errorf("bad PR number %q in %s", parts[3], s)
+	}
+	return ref, nil
+}
+
+// ParseRepoURL returns the host and owner/name of a git remote URL, in
+// https (https://host/owner/name.git), ssh (ssh://git@host/owner/name),
+// or scp-like (git@host:owner/name.git) form. ok is false for other
+// forms, such as local paths.
+func ParseRepoURL(gitURL string) (host, repo string, ok bool) {
+	s := strings.TrimSpace(gitURL)
+	if !strings.Contains(s, "://") {
+		// scp-like: [user@]host:path
+		at := strings.LastIndex(s, "@")
+		colon := strings.Index(s, ":")
+		if colon < 0 || colon < at || strings.HasPrefix(s, "/") {
+			return "", "", false
+		}
+		host, s = s[at+1:colon], s[colon+1:]
+	} else {
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" || u.Scheme == "file" {
+			return "", "", false
+		}
+		host, s = u.Hostname(), u.Path
+	}
+	parts := strings.Split(strings.Trim(s, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", false
+	}
+	// The repository is the last two segments; GitLab-style subgroups and
+	// path prefixes are not part of owner/name.
+	owner, name := parts[len(parts)-2], strings.TrimSuffix(parts[len(parts)-1], ".git")
+	if owner == "" || name == "" {
+		return "", "", false
+	}
+	return strings.ToLower(host), owner + "/" + name, true
+}
+
+// Hostname returns the lowercase hostname of the server cfg talks to,
+// without scheme: the provider's public host when Host is empty.
+func (cfg Config) Hostname() string {
+	host := cfg.Host
+	if host == "" {
+		switch cfg.Provider {
+		case "", ProviderGitHub:
+			host = "github.com"
+		case ProviderBitbucket:
+			host = "bitbucket.org"
+		}
+	}
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	return strings.ToLower(strings.TrimRight(host, "/"))
+}
@@ -0,0 +1,69 @@
+package scm
+
+import "testing"
+
+func TestParsePRURL(t *testing.T) {
+	for _, tc := range []struct {
+		url  string
+		want PRRef
+	}{
+		{"https://github.com/org/app/pull/123", PRRef{ProviderGitHub, "github.com", "org/app", 123}},
+		{"https://GHE.example.com/org/app/pull/9/files#diff", PRRef{ProviderGitHub, "ghe.example.com", "org/app", 9}},
+		{"https://gitea.example.com/org/app/pulls/4", PRRef{ProviderGitea, "gitea.example.com", "org/app", 4}},
+		{"http://gitea.lan:3000/org/app/pulls/4", PRRef{ProviderGitea, "http://gitea.lan:3000", "org/app", 4}},
+		{"https://bitbucket.org/team/app/pull-requests/77/overview", PRRef{ProviderBitbucket, "bitbucket.org", "team/app", 77}},
+	} {
+		got, err := ParsePRURL(tc.url)
+		if err != nil || got != tc.want {
+			t.Errorf("ParsePRURL(%q) = %+v, %v; want %+v", tc.url, got, err, tc.want)
+		}
+	}
+	for _, bad := range []string{
+		"123",
+		"github.com/org/app/pull/1",
+		"https://github.com/org/app",
+		"https://github.com/org/app/issues/1",
+		"https://github.com/org/app/pull/abc",
+		"https://gitlab.com/org/app/-/merge_requests/5",
+	} {
+		if _, err := ParsePRURL(bad); err == nil {
+			t.Errorf("ParsePRURL(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestParseRepoURL(t *testing.T) {
+	for _, tc := range []struct {
+		url, host, repo string
+	}{
+		{"https://github.com/steveyegge/gastown.git", "github.com", "steveyegge/gastown"},
+		{"git@github.com:steveyegge/gastown.git", "github.com", "steveyegge/gastown"},
+		{"ssh://git@Gitea.example.com:2222/org/app", "gitea.example.com", "org/app"},
+		{"https://bitbucket.org/team/app", "bitbucket.org", "team/app"},
+	} {
+		host, repo, ok := ParseRepoURL(tc.url)
+		if !ok || host != tc.host || repo != tc.repo {
+			t.Errorf("ParseRepoURL(%q) = %q, %q, %v", tc.url, host, repo, ok)
+		}
+	}
+	for _, bad := range []string{"/srv/git/app", "file:///srv/git/app.git", "app"} {
+		if _, _, ok := ParseRepoURL(bad); ok {
+			t.Errorf("ParseRepoURL(%q) matched", bad)
+		}
+	}
+}
+
+func TestConfigHostname(t *testing.T) {
+	for _, tc := range []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{}, "github.com"},
+		{Config{Provider: ProviderBitbucket}, "bitbucket.org"},
+		{Config{Provider: ProviderGitea, Host: "http://Gitea.lan:3000/"}, "gitea.lan:3000"},
+	} {
+		if got := tc.cfg.Hostname(); got != tc.want {
+			t.Errorf("%+v.Hostname() = %q, want %q", tc.cfg, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// ErrNotSupported is returned for operations a backend cannot perform,
+// such as typing into or attaching to a plain background process.
+var ErrNotSupported = errors.New("not supported by this session backend")
+
+// Backend runs agent sessions. tmux is the default; screen serves hosts
+// without tmux, and process runs the agent as a detached background
+// process for containers and servers where nobody attaches.
+type Backend interface {
+	// Type returns the backend's configured name.
+	Type() BackendType
+
+	// NewSession starts command detached in workDir as session name.
+	// Output is appended to each non-empty log path; the first is the
+	// session's own log and the rest are copies, such as a convoy leg's.
+	NewSession(name, workDir, command string, logPaths ...string) error
+
+	HasSession(name string) (bool, error)
+	ListSessions() ([]string, error)
+
+	// KillSession ends the session and every process it started.
+	KillSession(name string) error
+
+	// SendText types text into the session and presses Enter.
+	SendText(name, text string) error
+
+	// Capture returns the last lines of the session's output.
+	Capture(name string, lines int) (string, error)
+
+	// Attach connects the terminal to the session until it detaches.
+	Attach(name string) error
+
+	// SuspendSession stops the session's processes until ResumeSession
+	// continues them.
+	SuspendSession(name string) error
+	ResumeSession(name string) error
+}
+
+// BackendType names a session backend.
+type BackendType string
+
+const (
+	// BackendTmux runs sessions in tmux (the default).
+	BackendTmux BackendType = "tmux"
+	// BackendScreen runs sessions in GNU screen.
+	BackendScreen BackendType = "screen"
+	// BackendProcess runs the agent as a detached process with its output
+	// in a log file. Sessions can't be typed into or attached.
+	BackendProcess BackendType = "process"
+)
+
+var (
+	_ Backend = (*TmuxBackend)(nil)
+	_ Backend = (*ScreenBackend)(nil)
+	_ Backend = (*ProcessBackend)(nil)
+)
+
+// ParseBackend validates a backend name. Empty means BackendTmux.
+func ParseBackend(s string) (BackendType, error) {
+	switch BackendType(s) {
+	case "", BackendTmux:
+		return BackendTmux, nil
+	case BackendScreen, BackendProcess:
+		return BackendType(s), nil
+	}
+	return "", fmt.Errorf("unknown session backend %q (want tmux, screen, or process)", s)
+}
+
+// CanSuspend reports whether sessions of the given backend can be
+// suspended and resumed on this host.
+func CanSuspend(backend BackendType) bool {
+	switch backend {
+	case BackendScreen:
+		return false
+	case BackendProcess:
+		return runtime.GOOS != "windows"
+	}
+	return true
+}
+
+// BackendTypeFor returns the session backend configured for the town at
+// townRoot: GT_SESSION_BACKEND if set, else the town's session_backend
+// setting. GT_DEGRADED=true (the daemon's no-tmux mode) selects
+// BackendProcess when neither is set. Unknown values fall back to
+// BackendTmux.
+func BackendTypeFor(townRoot string) BackendType {
+	name := os.Getenv("GT_SESSION_BACKEND")
+	if name == "" && townRoot != "" {
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			name = settings.SessionBackend
+		}
+	}
+	if name == "" && os.Getenv("GT_DEGRADED") == "true" {
+		return BackendProcess
+	}
+	backend, err := ParseBackend(name)
+	if err != nil {
+		return BackendTmux
+	}
+	return backend
+}
+
+// NewBackend returns the backend of the given type. t is reused by the
+// tmux backend and may be nil.
+func NewBackend(backend BackendType, townRoot string, t *tmux.Tmux) Backend {
+	switch backend {
+	case BackendScreen:
+		return &ScreenBackend{}
+	case BackendProcess:
+		return NewProcessBackend(townRoot)
+	}
+	if t == nil {
+		t = tmux.NewTmux()
+	}
+	return &TmuxBackend{Tmux: t}
+}
+
+// OpenBackend returns the session backend configured for townRoot.
+func OpenBackend(townRoot string, t *tmux.Tmux) Backend {
+	return NewBackend(BackendTypeFor(townRoot), townRoot, t)
+}
+
+// TmuxBackend runs sessions in tmux.
+type TmuxBackend struct {
+	Tmux *tmux.Tmux
+}
+
+func (b *TmuxBackend) Type() BackendType { return BackendTmux }
+
+func (b *TmuxBackend) NewSession(name, workDir, command string, logPaths ...string) error {
+	if err := b.Tmux.NewSessionWithCommand(name, workDir, command); err != nil {
+		return err
+	}
+	if paths := nonEmpty(logPaths); len(paths) > 0 {
+		// Non-fatal: the session works without its log.
+		_ = b.Tmux.PipePaneToFile(name, paths[0], paths[1:]...)
+	}
+	return nil
+}
+
+// nonEmpty returns paths without its empty entries.
+func nonEmpty(paths []string) []string {
+	var out []string
+	for _, p := range paths {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (b *TmuxBackend) HasSession(name string) (bool, error) { return b.Tmux.HasSession(name) }
+
+func (b *TmuxBackend) ListSessions() ([]string, error) { return b.Tmux.ListSessions() }
+
+func (b *TmuxBackend) KillSession(name string) error { return b.Tmux.KillSessionWithProcesses(name) }
+
+func (b *TmuxBackend) SendText(name, text string) error { return b.Tmux.NudgeSession(name, text) }
+
+func (b *TmuxBackend) Capture(name string, lines int) (string, error) {
+	return b.Tmux.CapturePane(name, lines)
+}
+
+func (b *TmuxBackend) Attach(name string) error { return b.Tmux.AttachSession(name) }
+
+func (b *TmuxBackend) SuspendSession(name string) error { return b.Tmux.SuspendSession(name) }
+
+func (b *TmuxBackend) ResumeSession(name string) error { return b.Tmux.ResumeSession(name) }
@@ -0,0 +1,187 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// ProcessBackend runs each session as a detached background process. The
+// pid is kept in <town>/.runtime/sessions/<name>.pid and the output goes to
+// the session's log (or <name>.log beside the pid file).
+type ProcessBackend struct {
+	dir string
+}
+
+// NewProcessBackend returns a process backend keeping state in townRoot.
+func NewProcessBackend(townRoot string) *ProcessBackend {
+	return &ProcessBackend{dir: filepath.Join(townRoot, constants.DirRuntime, "sessions")}
+}
+
+func (b *ProcessBackend) Type() BackendType { return BackendProcess }
+
+func (b *ProcessBackend) pidPath(name string) string {
+	return filepath.Join(b.dir, name+".pid")
+}
+
+func (b *ProcessBackend) logPath(name string) string {
+	if data, err := os.ReadFile(filepath.Join(b.dir, name+".logpath")); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	return filepath.Join(b.dir, name+".log")
+}
+
+// NewSession writes the process's output to the first log path. Copies
+// for the other paths are made by piping the command through tee.
+func (b *ProcessBackend) NewSession(name, workDir, command string, logPaths ...string) error {
+	if running, _ := b.HasSession(name); running {
+		return fmt.Errorf("session %s already exists", name)
+	}
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return fmt.Errorf("creating session dir: %w", err)
+	}
+	paths := nonEmpty(logPaths)
+	logPath := filepath.Join(b.dir, name+".log")
+	if len(paths) > 0 {
+		logPath = paths[0]
+	}
+	for _, p := range paths {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+	}
+	if len(paths) > 1 {
+		tee := "tee -a"
+		for _, p := range paths[1:] {
+			tee += " " + config.ShellQuote(p)
+		}
+		command = "{ " + command + "\n} 2>&1 | " + tee
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G304: path is ours
+	if err != nil {
+		return fmt.Errorf("opening session log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = workDir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detachProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting session: %w", err)
+	}
+	// Reap the process if this gt outlives it (e.g. the daemon).
+	go func() { _ = cmd.Wait() }()
+
+	if err := os.WriteFile(b.pidPath(name), []byte(strconv.Itoa(cmd.Process.Pid)+"\n"), 0644); err != nil {
+		killProcessTree(cmd.Process.Pid)
+		return fmt.Errorf("writing session pid: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(b.dir, name+".logpath"), []byte(logPath+"\n"), 0644); err != nil {
+		return fmt.Errorf("writing session log path: %w", err)
+	}
+	return nil
+}
+
+// pid returns the session's pid, or 0 if it isn't running.
+func (b *ProcessBackend) pid(name string) int {
+	data, err := os.ReadFile(b.pidPath(name))
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 || !processAlive(pid) {
+		return 0
+	}
+	return pid
+}
+
+func (b *ProcessBackend) HasSession(name string) (bool, error) {
+	return b.pid(name) != 0, nil
+}
+
+func (b *ProcessBackend) ListSessions() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var sessions []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".pid")
+		if !ok {
+			continue
+		}
+		if b.pid(name) != 0 {
+			sessions = append(sessions, name)
+		} else {
+			b.forget(name)
+		}
+	}
+	sort.Strings(sessions)
+	return sessions, nil
+}
+
+// forget removes a dead session's state, keeping its log.
+func (b *ProcessBackend) forget(name string) {
+	_ = os.Remove(b.pidPath(name))
+	_ = os.Remove(filepath.Join(b.dir, name+".logpath"))
+}
+
+func (b *ProcessBackend) KillSession(name string) error {
+	pid := b.pid(name)
+	if pid == 0 {
+		b.forget(name)
+		return fmt.Errorf("session %s not found", name)
+	}
+	killProcessTree(pid)
+	b.forget(name)
+	return nil
+}
+
+func (b *ProcessBackend) SendText(name, text string) error {
+	return fmt.Errorf("typing into %s: %w", name, ErrNotSupported)
+}
+
+func (b *ProcessBackend) Capture(name string, lines int) (string, error) {
+	data, err := os.ReadFile(b.logPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return lastLines(string(data), lines), nil
+}
+
+func (b *ProcessBackend) Attach(name string) error {
+	return fmt.Errorf("attaching to %s: %w (read its output with gt logs)", name, ErrNotSupported)
+}
+
+// SuspendSession stops the session's process group.
+func (b *ProcessBackend) SuspendSession(name string) error {
+	return b.signal(name, stopProcessTree)
+}
+
+// ResumeSession continues a process group stopped by SuspendSession.
+func (b *ProcessBackend) ResumeSession(name string) error {
+	return b.signal(name, continueProcessTree)
+}
+
+func (b *ProcessBackend) signal(name string, fn func(pid int) error) error {
+	pid := b.pid(name)
+	if pid == 0 {
+		return fmt.Errorf("session %s not found", name)
+	}
+	return fn(pid)
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+package session
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// detachProcess starts cmd in its own session so it outlives gt and can
+// be killed as a group.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+// killProcessTree terminates the process group led by pid.
+func killProcessTree(pid int) {
+	_ = syscall.Kill(-pid, syscall.SIGTERM)
+	for i := 0; i < 20 && processAlive(pid); i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	_ = syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// stopProcessTree stops the process group led by pid.
+func stopProcessTree(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGSTOP)
+}
+
+// continueProcessTree continues the process group led by pid.
+func continueProcessTree(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGCONT)
+}
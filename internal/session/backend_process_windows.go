@@ -0,0 +1,35 @@
+//go:build windows
+
+package session
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+func processAlive(pid int) bool {
+	out, err := exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/FO", "CSV", "/NH").Output()
+	if err != nil {
+		return false
+	}
+	text := strings.TrimSpace(string(out))
+	return text != "" && !strings.HasPrefix(text, "INFO:")
+}
+
+func killProcessTree(pid int) {
+	_ = exec.Command("taskkill", "/T", "/F", "/PID", fmt.Sprint(pid)).Run()
+}
+
+func stopProcessTree(pid int) error {
+	return fmt.Errorf("suspending pid %d: %w", pid, ErrNotSupported)
+}
+
+func continueProcessTree(pid int) error {
+	return fmt.Errorf("resuming pid %d: %w", pid, ErrNotSupported)
+}
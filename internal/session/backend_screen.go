@@ -0,0 +1,147 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ScreenBackend runs sessions in GNU screen (4.06 or newer for -Logfile).
+type ScreenBackend struct{}
+
+func (b *ScreenBackend) Type() BackendType { return BackendScreen }
+
+// NewSession logs to the first log path only: screen keeps a single log
+// per window, so the other paths are not written.
+func (b *ScreenBackend) NewSession(name, workDir, command string, logPaths ...string) error {
+	args := []string{"-dmS", name}
+	if paths := nonEmpty(logPaths); len(paths) > 0 {
+		logPath := paths[0]
+		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+			return err
+		}
+		args = append(args, "-L", "-Logfile", logPath)
+	}
+	args = append(args, "sh", "-c", command)
+	cmd := exec.Command("screen", args...)
+	cmd.Dir = workDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("screen: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (b *ScreenBackend) HasSession(name string) (bool, error) {
+	sessions, err := b.ListSessions()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range sessions {
+		if s == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (b *ScreenBackend) ListSessions() ([]string, error) {
+	// screen -ls exits non-zero both with and without sessions, so only a
+	// failure to run it counts.
+	out, err := exec.Command("screen", "-ls").CombinedOutput()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("screen -ls: %w", err)
+		}
+	}
+	return parseScreenList(string(out)), nil
+}
+
+// parseScreenList extracts session names from 'screen -ls' output, where
+// each session is a tab-indented "<pid>.<name>" line.
+func parseScreenList(out string) []string {
+	var sessions []string
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "\t") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if _, name, ok := strings.Cut(fields[0], "."); ok && name != "" {
+			sessions = append(sessions, name)
+		}
+	}
+	return sessions
+}
+
+func (b *ScreenBackend) KillSession(name string) error {
+	return b.command(name, "quit")
+}
+
+func (b *ScreenBackend) SendText(name, text string) error {
+	// stuff interprets backslash and caret escapes.
+	text = strings.NewReplacer(`\`, `\\`, `^`, `\^`).Replace(text)
+	return b.command(name, "stuff", text+"\r")
+}
+
+func (b *ScreenBackend) Capture(name string, lines int) (string, error) {
+	f, err := os.CreateTemp("", "gt-screen-*.txt")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	_ = f.Close()
+	defer os.Remove(path)
+
+	if err := b.command(name, "hardcopy", "-h", path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: our own temp file
+	if err != nil {
+		return "", err
+	}
+	return lastLines(string(data), lines), nil
+}
+
+func (b *ScreenBackend) Attach(name string) error {
+	cmd := exec.Command("screen", "-r", name)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// SuspendSession is not supported: the window's processes run in their
+// own terminal session under the screen server.
+func (b *ScreenBackend) SuspendSession(name string) error {
+	return fmt.Errorf("suspending %s: %w", name, ErrNotSupported)
+}
+
+func (b *ScreenBackend) ResumeSession(name string) error {
+	return fmt.Errorf("resuming %s: %w", name, ErrNotSupported)
+}
+
+// command runs a screen command in session name.
+func (b *ScreenBackend) command(name string, args ...string) error {
+	out, err := exec.Command("screen", append([]string{"-S", name, "-X"}, args...)...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if strings.Contains(msg, "No screen session found") {
+			return fmt.Errorf("screen session %s not found", name)
+		}
+		return fmt.Errorf("screen %s: %s", args[0], msg)
+	}
+	return nil
+}
+
+// lastLines returns the last n non-trailing-blank lines of s.
+func lastLines(s string, n int) string {
+	all := strings.Split(strings.TrimRight(s, "\n "), "\n")
+	if n > 0 && len(all) > n {
+		all = all[len(all)-n:]
+	}
+	return strings.Join(all, "\n")
+}
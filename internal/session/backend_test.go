@@ -0,0 +1,200 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseBackend(t *testing.T) {
+	for in, want := range map[string]BackendType{
+		"":        BackendTmux,
+		"tmux":    BackendTmux,
+		"screen":  BackendScreen,
+		"process": BackendProcess,
+	} {
+		got, err := ParseBackend(in)
+		if err != nil || got != want {
+			t.Errorf("ParseBackend(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseBackend("zellij"); err == nil {
+		t.Error("ParseBackend(zellij) should fail")
+	}
+}
+
+func TestBackendTypeFor(t *testing.T) {
+	t.Setenv("GT_SESSION_BACKEND", "")
+	t.Setenv("GT_DEGRADED", "")
+	townRoot := t.TempDir()
+
+	if got := BackendTypeFor(townRoot); got != BackendTmux {
+		t.Errorf("default = %q, want tmux", got)
+	}
+
+	t.Setenv("GT_DEGRADED", "true")
+	if got := BackendTypeFor(townRoot); got != BackendProcess {
+		t.Errorf("degraded = %q, want process", got)
+	}
+
+	settings := config.NewTownSettings()
+	settings.SessionBackend = "screen"
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := BackendTypeFor(townRoot); got != BackendScreen {
+		t.Errorf("configured = %q, want screen", got)
+	}
+
+	t.Setenv("GT_SESSION_BACKEND", "process")
+	if got := BackendTypeFor(townRoot); got != BackendProcess {
+		t.Errorf("env override = %q, want process", got)
+	}
+
+	t.Setenv("GT_SESSION_BACKEND", "bogus")
+	if got := BackendTypeFor(townRoot); got != BackendTmux {
+		t.Errorf("unknown = %q, want tmux fallback", got)
+	}
+}
+
+func TestCanSuspend(t *testing.T) {
+	if !CanSuspend(BackendTmux) {
+		t.Error("tmux sessions should suspend")
+	}
+	if CanSuspend(BackendScreen) {
+		t.Error("screen sessions should not suspend")
+	}
+	if got, want := CanSuspend(BackendProcess), runtime.GOOS != "windows"; got != want {
+		t.Errorf("CanSuspend(process) = %v, want %v", got, want)
+	}
+}
+
+func TestParseScreenList(t *testing.T) {
+	out := "There are screens on:\n" +
+		"\t4242.gt-gastown-Toast\t(10/16/2026 09:12:01 AM)\t(Detached)\n" +
+		"\t4300.gt-gastown-Nux\t(Attached)\n" +
+		"2 Sockets in /run/screen/S-dev.\n"
+	want := []string{"gt-gastown-Toast", "gt-gastown-Nux"}
+	if got := parseScreenList(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseScreenList = %v, want %v", got, want)
+	}
+	if got := parseScreenList("No Sockets found in /run/screen/S-dev.\n"); len(got) != 0 {
+		t.Errorf("parseScreenList(none) = %v", got)
+	}
+}
+
+func TestProcessBackendLifecycle(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	townRoot := t.TempDir()
+	b := NewProcessBackend(townRoot)
+	logPath := filepath.Join(townRoot, "logs", "toast.log")
+
+	if err := b.NewSession("gt-rig-toast", townRoot, "echo started; exec sleep 30", logPath); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if running, _ := b.HasSession("gt-rig-toast"); !running {
+		t.Fatal("session should be running")
+	}
+	if err := b.NewSession("gt-rig-toast", townRoot, "true", ""); err == nil {
+		t.Error("starting a running session should fail")
+	}
+	if sessions, _ := b.ListSessions(); !reflect.DeepEqual(sessions, []string{"gt-rig-toast"}) {
+		t.Errorf("ListSessions = %v", sessions)
+	}
+
+	var out string
+	for i := 0; i < 50; i++ {
+		out, _ = b.Capture("gt-rig-toast", 10)
+		if out != "" {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !strings.Contains(out, "started") {
+		t.Errorf("Capture = %q, want the command's output", out)
+	}
+
+	if err := b.SendText("gt-rig-toast", "hi"); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("SendText error = %v, want ErrNotSupported", err)
+	}
+	if err := b.Attach("gt-rig-toast"); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Attach error = %v, want ErrNotSupported", err)
+	}
+	if err := b.SuspendSession("gt-rig-toast"); err != nil {
+		t.Errorf("SuspendSession: %v", err)
+	}
+	if err := b.ResumeSession("gt-rig-toast"); err != nil {
+		t.Errorf("ResumeSession: %v", err)
+	}
+	if err := b.SuspendSession("gt-rig-missing"); err == nil {
+		t.Error("suspending a missing session should fail")
+	}
+
+	if err := b.KillSession("gt-rig-toast"); err != nil {
+		t.Fatalf("KillSession: %v", err)
+	}
+	if running, _ := b.HasSession("gt-rig-toast"); running {
+		t.Error("session should be gone after KillSession")
+	}
+	if sessions, _ := b.ListSessions(); len(sessions) != 0 {
+		t.Errorf("ListSessions after kill = %v", sessions)
+	}
+	if _, err := os.Stat(logPath); err != nil {
+		t.Errorf("log should survive the session: %v", err)
+	}
+}
+
+func TestProcessBackendCopiesLog(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	townRoot := t.TempDir()
+	b := NewProcessBackend(townRoot)
+	logPath := filepath.Join(townRoot, "logs", "toast.log")
+	legPath := filepath.Join(townRoot, "convoys", "hq-cv-x", "security.log")
+	if err := b.NewSession("gt-rig-toast", townRoot, "echo started", logPath, legPath); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if running, _ := b.HasSession("gt-rig-toast"); !running {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	for _, path := range []string{logPath, legPath} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != "started\n" {
+			t.Errorf("%s = %q, %v; want the command's output", path, data, err)
+		}
+	}
+}
+
+func TestProcessBackendForgetsExitedSessions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	b := NewProcessBackend(t.TempDir())
+	if err := b.NewSession("gt-rig-quick", "", "true", ""); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if running, _ := b.HasSession("gt-rig-quick"); !running {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if sessions, _ := b.ListSessions(); len(sessions) != 0 {
+		t.Errorf("ListSessions = %v, want exited session dropped", sessions)
+	}
+	if _, err := os.Stat(b.pidPath("gt-rig-quick")); !os.IsNotExist(err) {
+		t.Errorf("pid file should be removed, stat err = %v", err)
+	}
+}
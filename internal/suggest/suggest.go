@@ -83,6 +83,49 @@ func FindSimilar(target string, candidates []string, maxResults int) []string {
 	return result
 }
 
+// Nearest returns up to maxResults candidates that look like typos of
+// target, closest first: those within a small Levenshtein distance (a third
+// of target's length, at least 2), then those that start with target.
+// Unlike FindSimilar it returns nothing when no candidate is close.
+func Nearest(target string, candidates []string, maxResults int) []string {
+	if maxResults <= 0 {
+		return nil
+	}
+	target = strings.ToLower(target)
+	limit := max(2, len(target)/3)
+
+	type near struct {
+		value string
+		dist  int
+	}
+	var found []near
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		if lc == target {
+			continue
+		}
+		if d := levenshteinDistance(target, lc); d <= limit {
+			found = append(found, near{c, d})
+		} else if len(target) >= 3 && strings.HasPrefix(lc, target) {
+			found = append(found, near{c, limit + 1})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].dist != found[j].dist {
+			return found[i].dist < found[j].dist
+		}
+		return found[i].value < found[j].value
+	})
+	if len(found) > maxResults {
+		found = found[:maxResults]
+	}
+	result := make([]string, len(found))
+	for i, n := range found {
+		result[i] = n.value
+	}
+	return result
+}
+
 // similarity calculates a similarity score between two strings.
 // Higher is more similar. Uses a combination of techniques:
 // - Prefix matching (high weight)
@@ -74,6 +74,35 @@ func TestFindSimilar(t *testing.T) {
 	}
 }
 
+func TestNearest(t *testing.T) {
+	formulas := []string{"shiny", "shiny-secure", "code-review", "mol-polecat-work", "design"}
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"shniy", []string{"shiny"}},
+		{"code-reveiw", []string{"code-review"}},
+		{"shin", []string{"shiny", "shiny-secure"}},
+		{"Desgin", []string{"design"}},
+		{"zzzzzz", []string{}},
+		{"shiny", []string{"shiny-secure"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got := Nearest(tt.target, formulas, 3)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Nearest(%q) = %v, want %v", tt.target, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Nearest(%q) = %v, want %v", tt.target, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
 func TestLevenshteinDistance(t *testing.T) {
 	tests := []struct {
 		a, b string
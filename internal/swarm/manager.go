@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -48,7 +49,7 @@ func (m *Manager) LoadSwarm(epicID string) (*Swarm, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return nil, fmt.Errorf("bd show: %s", strings.TrimSpace(stderr.String()))
 	}
 
@@ -133,7 +134,7 @@ func (m *Manager) GetReadyTasks(swarmID string) ([]SwarmTask, error) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return nil, ErrSwarmNotFound
 	}
 
@@ -170,7 +171,7 @@ func (m *Manager) IsComplete(swarmID string) (bool, error) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return false, ErrSwarmNotFound
 	}
 
@@ -221,7 +222,7 @@ func (m *Manager) loadTasksFromBeads(epicID string) ([]SwarmTask, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return nil, fmt.Errorf("bd show: %s", strings.TrimSpace(stderr.String()))
 	}
 
@@ -225,6 +225,34 @@ func (t *Tmux) KillSessionWithProcesses(name string) error {
 	return err
 }
 
+// SuspendSession stops a session's pane process and all its descendants
+// with SIGSTOP, pausing the agent without losing its state. ResumeSession
+// continues it.
+func (t *Tmux) SuspendSession(name string) error {
+	return t.signalSessionProcesses(name, "STOP")
+}
+
+// ResumeSession continues a session suspended with SuspendSession.
+func (t *Tmux) ResumeSession(name string) error {
+	return t.signalSessionProcesses(name, "CONT")
+}
+
+// signalSessionProcesses sends sig to a session's pane process and its
+// descendants.
+func (t *Tmux) signalSessionProcesses(name, sig string) error {
+	pid, err := t.GetPanePID(name)
+	if err != nil {
+		return err
+	}
+	if pid == "" {
+		return ErrSessionNotFound
+	}
+	for _, p := range append([]string{pid}, getAllDescendants(pid)...) {
+		_ = exec.Command("kill", "-"+sig, p).Run()
+	}
+	return nil
+}
+
 // KillSessionWithProcessesExcluding is like KillSessionWithProcesses but excludes
 // specified PIDs from being killed. This is essential for self-kill scenarios where
 // the calling process (e.g., gt done) is running inside the session it's terminating.
@@ -1658,6 +1686,26 @@ func (t *Tmux) CleanupOrphanedSessions() (cleaned int, err error) {
 	return cleaned, nil
 }
 
+// PipePaneToFile appends everything a session's pane prints to path, so the
+// output can be read after the session is gone (gt logs). A pane has one
+// pipe, so further paths get a copy through tee.
+func (t *Tmux) PipePaneToFile(session, path string, more ...string) error {
+	pipe := "cat"
+	if len(more) > 0 {
+		pipe = "tee -a"
+		for _, p := range more {
+			pipe += " " + config.ShellQuote(p)
+		}
+	}
+	for _, p := range append([]string{path}, more...) {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+	}
+	_, err := t.run("pipe-pane", "-o", "-t", session, pipe+" >> "+config.ShellQuote(path))
+	return err
+}
+
 // SetPaneDiedHook sets a pane-died hook on a session to detect crashes.
 // When the pane exits, tmux runs the hook command with exit status info.
 // The agentID is used to identify the agent in crash logs (e.g., "gastown/Toast").
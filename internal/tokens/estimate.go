@@ -0,0 +1,200 @@
+// Package tokens estimates prompt sizes for the model families Gas Town
+// agents run on, so oversized prompts can be flagged before dispatch.
+package tokens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Family identifies a model family with its own tokenizer and context window.
+type Family string
+
+// Known model families.
+const (
+	FamilyClaude  Family = "claude"
+	FamilyGPT     Family = "gpt"
+	FamilyGemini  Family = "gemini"
+	FamilyUnknown Family = "unknown"
+)
+
+// familyProfile holds the tokenizer characteristics of a family.
+type familyProfile struct {
+	// wordChars is roughly how many letters of a single word one BPE
+	// token covers; common words are one token, long identifiers several.
+	wordChars float64
+	// contextWindow is the model's input context size in tokens.
+	contextWindow int
+}
+
+var profiles = map[Family]familyProfile{
+	FamilyClaude:  {wordChars: 6, contextWindow: 200_000},
+	FamilyGPT:     {wordChars: 7, contextWindow: 128_000},
+	FamilyGemini:  {wordChars: 7, contextWindow: 1_000_000},
+	FamilyUnknown: {wordChars: 6, contextWindow: 128_000},
+}
+
+// agentFamilies maps agent preset names to the model family they run.
+var agentFamilies = map[string]Family{
+	"claude":   FamilyClaude,
+	"codex":    FamilyGPT,
+	"cursor":   FamilyGPT,
+	"gemini":   FamilyGemini,
+	"opencode": FamilyClaude,
+	"auggie":   FamilyClaude,
+	"amp":      FamilyClaude,
+}
+
+// FamilyForAgent returns the model family for an agent preset name or
+// command path. Unknown agents return FamilyUnknown.
+func FamilyForAgent(agent string) Family {
+	name := strings.TrimSuffix(filepath.Base(agent), "-agent")
+	if f, ok := agentFamilies[name]; ok {
+		return f
+	}
+	return FamilyUnknown
+}
+
+// ContextWindow returns the context window size in tokens for a family.
+func ContextWindow(f Family) int {
+	if p, ok := profiles[f]; ok {
+		return p.contextWindow
+	}
+	return profiles[FamilyUnknown].contextWindow
+}
+
+// Estimate returns the approximate number of tokens text occupies for the
+// given family. Text is pre-tokenized the way BPE tokenizers split input
+// (runs of letters, runs of digits, individual symbols, whitespace), and
+// each piece is costed by the family's tokenizer profile.
+// Estimates are deliberately rounded up: they exist to warn early.
+func Estimate(text string, f Family) int {
+	p, ok := profiles[f]
+	if !ok {
+		p = profiles[FamilyUnknown]
+	}
+
+	total := 0
+	for _, piece := range pretokenize(text) {
+		// A single space merges into the following word's token.
+		if piece == " " {
+			continue
+		}
+		total += pieceTokens(piece, p.wordChars)
+	}
+	return total
+}
+
+// pieceTokens costs a single pre-token.
+func pieceTokens(piece string, wordChars float64) int {
+	r, _ := utf8.DecodeRuneInString(piece)
+	switch {
+	case unicode.IsSpace(r):
+		// Runs of spaces/newlines usually merge into one or two tokens.
+		return 1 + utf8.RuneCountInString(piece)/8
+	case r > unicode.MaxASCII:
+		// Non-ASCII text tends to tokenize close to one token per rune.
+		return utf8.RuneCountInString(piece)
+	case unicode.IsDigit(r):
+		// Digits are commonly grouped in threes.
+		return (len(piece) + 2) / 3
+	case unicode.IsLetter(r):
+		n := int(float64(len(piece))/wordChars + 0.999)
+		if n < 1 {
+			n = 1
+		}
+		return n
+	default:
+		return 1
+	}
+}
+
+// pretokenize splits text into letter runs, digit runs, whitespace runs,
+// and single symbol characters.
+func pretokenize(text string) []string {
+	var pieces []string
+	start := 0
+	class := -1
+	for i, r := range text {
+		c := runeClass(r)
+		if i > start && (c != class || c == classSymbol) {
+			pieces = append(pieces, text[start:i])
+			start = i
+		}
+		class = c
+	}
+	if start < len(text) {
+		pieces = append(pieces, text[start:])
+	}
+	return pieces
+}
+
+const (
+	classLetter = iota
+	classDigit
+	classSpace
+	classSymbol
+)
+
+func runeClass(r rune) int {
+	switch {
+	case unicode.IsLetter(r):
+		return classLetter
+	case unicode.IsDigit(r):
+		return classDigit
+	case unicode.IsSpace(r):
+		return classSpace
+	default:
+		return classSymbol
+	}
+}
+
+// Level classifies how close an estimate is to a context window.
+type Level int
+
+// Budget levels.
+const (
+	LevelOK Level = iota
+	LevelNear
+	LevelOver
+)
+
+// nearFraction is the share of the context window that triggers LevelNear.
+// Agents need headroom for system prompts, tool output, and their reply.
+const nearFraction = 0.75
+
+// DefaultBudget returns the token budget for a prompt and its context,
+// leaving headroom in the family's context window.
+func DefaultBudget(f Family) int {
+	return int(nearFraction * float64(ContextWindow(f)))
+}
+
+// Check classifies n tokens against the family's context window.
+func Check(n int, f Family) Level {
+	window := ContextWindow(f)
+	switch {
+	case n >= window:
+		return LevelOver
+	case float64(n) >= nearFraction*float64(window):
+		return LevelNear
+	default:
+		return LevelOK
+	}
+}
+
+// Format renders a token count compactly (e.g., 850, 12.3k, 1.2M).
+func Format(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 10_000:
+		return fmt.Sprintf("%dk", n/1000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
@@ -0,0 +1,71 @@
+package tokens
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPretokenize(t *testing.T) {
+	got := pretokenize("Hello, world 2024!\n")
+	want := []string{"Hello", ",", " ", "world", " ", "2024", "!", "\n"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pretokenize() = %q, want %q", got, want)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	if got := Estimate("", FamilyClaude); got != 0 {
+		t.Errorf("Estimate(\"\") = %d, want 0", got)
+	}
+
+	// Ordinary English prose runs roughly 0.2-0.35 tokens per character.
+	prose := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 100)
+	n := Estimate(prose, FamilyClaude)
+	if ratio := float64(n) / float64(len(prose)); ratio < 0.2 || ratio > 0.4 {
+		t.Errorf("Estimate(prose) = %d tokens for %d chars (ratio %.2f), want ratio in [0.2, 0.4]", n, len(prose), ratio)
+	}
+
+	// GPT tokenizers pack more characters per token than Claude's.
+	if gpt := Estimate(prose, FamilyGPT); gpt > n {
+		t.Errorf("Estimate(gpt) = %d, want <= claude estimate %d", gpt, n)
+	}
+}
+
+func TestFamilyForAgent(t *testing.T) {
+	tests := map[string]Family{
+		"claude":               FamilyClaude,
+		"/usr/local/bin/codex": FamilyGPT,
+		"cursor-agent":         FamilyGPT,
+		"gemini":               FamilyGemini,
+		"my-custom-wrapper":    FamilyUnknown,
+		"":                     FamilyUnknown,
+	}
+	for agent, want := range tests {
+		if got := FamilyForAgent(agent); got != want {
+			t.Errorf("FamilyForAgent(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	window := ContextWindow(FamilyClaude)
+	if got := Check(window/2, FamilyClaude); got != LevelOK {
+		t.Errorf("Check(half) = %v, want LevelOK", got)
+	}
+	if got := Check(window*9/10, FamilyClaude); got != LevelNear {
+		t.Errorf("Check(90%%) = %v, want LevelNear", got)
+	}
+	if got := Check(window+1, FamilyClaude); got != LevelOver {
+		t.Errorf("Check(over) = %v, want LevelOver", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[int]string{850: "850", 1234: "1.2k", 45678: "45k", 1_500_000: "1.5M"}
+	for n, want := range tests {
+		if got := Format(n); got != want {
+			t.Errorf("Format(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -52,8 +52,12 @@ type Event struct {
 
 // Logger handles writing events to the town log file.
 type Logger struct {
-	logPath string
-	mu      sync.Mutex
+	logPath  string
+	townRoot string
+	mu       sync.Mutex
+
+	sinksOnce sync.Once
+	sinks     []Sink // From town settings logging.sinks, loaded on first event
 }
 
 // logDir returns the directory for town logs.
@@ -69,8 +73,28 @@ func logPath(townRoot string) string {
 // NewLogger creates a new Logger for the given town root.
 func NewLogger(townRoot string) *Logger {
 	return &Logger{
-		logPath: logPath(townRoot),
+		logPath:  logPath(townRoot),
+		townRoot: townRoot,
+	}
+}
+
+// Close flushes and releases the logger's sinks. Call it before the
+// process exits, or buffered events may never be shipped. Like write
+// failures, flush failures are reported on stderr and returned.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var firstErr error
+	for _, sink := range l.sinks {
+		if err := sink.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: shipping log events: %v\n", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
 	}
+	l.sinks = nil
+	return firstErr
 }
 
 // LogEvent logs a single event to the town log.
@@ -96,6 +120,15 @@ func (l *Logger) LogEvent(event Event) error {
 		return fmt.Errorf("writing log line: %w", err)
 	}
 
+	// Ship to configured sinks. town.log is the record of truth, so sink
+	// failures are reported but do not fail the event.
+	l.sinksOnce.Do(func() { l.sinks = loadSinks(l.townRoot) })
+	for _, sink := range l.sinks {
+		if err := sink.Write(event); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: shipping log event: %v\n", err)
+		}
+	}
+
 	return nil
 }
 
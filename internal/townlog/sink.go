@@ -0,0 +1,303 @@
+package townlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/offline"
+)
+
+// Sink ships town log events to external log infrastructure.
+type Sink interface {
+	Write(e Event) error
+	Close() error
+}
+
+// record is the structured form of an event shipped to sinks.
+type record struct {
+	Event
+	Message string `json:"message"`
+}
+
+func newRecord(e Event) record {
+	return record{Event: e, Message: formatLogLine(e)}
+}
+
+// NewSink creates the sink described by cfg. Relative file paths are
+// resolved against townRoot.
+func NewSink(townRoot string, cfg config.LogSinkConfig) (Sink, error) {
+	switch cfg.Type {
+	case config.LogSinkSyslog:
+		tag := cfg.Tag
+		if tag == "" {
+			tag = "gastown"
+		}
+		return newSyslogSink(cfg.Network, cfg.Address, tag)
+	case config.LogSinkLoki:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("loki sink requires url")
+		}
+		if err := offline.Check("push log events to " + cfg.URL); err != nil {
+			return nil, err
+		}
+		return newLokiSink(cfg.URL, cfg.Labels), nil
+	case config.LogSinkFile:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("file sink requires path")
+		}
+		path := cfg.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(townRoot, path)
+		}
+		return newFileSink(path, cfg.MaxSizeMB, cfg.MaxFiles), nil
+	default:
+		return nil, fmt.Errorf("unknown log sink type %q", cfg.Type)
+	}
+}
+
+// loadSinks creates the sinks configured in the town settings. Sinks that
+// cannot be created are reported and skipped.
+func loadSinks(townRoot string) []Sink {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Logging == nil {
+		return nil
+	}
+	var sinks []Sink
+	for _, cfg := range settings.Logging.Sinks {
+		sink, err := NewSink(townRoot, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: log sink %s disabled: %v\n", cfg.Type, err)
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks
+}
+
+// Loki pushes happen off the caller's path: Write queues the event and a
+// goroutine ships queued events in batches. Close flushes what is left,
+// waiting at most lokiFlushTimeout.
+const (
+	lokiQueueSize    = 256
+	lokiMaxBatch     = 100
+	lokiFlushTimeout = 5 * time.Second
+)
+
+// lokiEntry is one queued event in Loki's stream form.
+type lokiEntry struct {
+	stream map[string]string
+	value  [2]string
+}
+
+// lokiSink pushes events to a Loki push API endpoint.
+type lokiSink struct {
+	url    string
+	labels map[string]string
+	client *http.Client
+
+	queue  chan lokiEntry
+	done   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int // Events that found the queue full
+
+	// Set by run only, and read once done is closed
+	failed  int
+	lastErr error
+}
+
+func newLokiSink(baseURL string, labels map[string]string) *lokiSink {
+	all := map[string]string{"job": "gastown"}
+	for k, v := range labels {
+		all[k] = v
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &lokiSink{
+		url:    strings.TrimSuffix(baseURL, "/") + "/loki/api/v1/push",
+		labels: all,
+		client: &http.Client{Timeout: 5 * time.Second},
+		queue:  make(chan lokiEntry, lokiQueueSize),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	go s.run()
+	return s
+}
+
+// Write queues e for the next push. It never waits on the network; an
+// event that finds the queue full is dropped and counted in Close's error.
+func (s *lokiSink) Write(e Event) error {
+	line, err := json.Marshal(newRecord(e))
+	if err != nil {
+		return err
+	}
+	stream := map[string]string{"event": string(e.Type)}
+	for k, v := range s.labels {
+		stream[k] = v
+	}
+	entry := lokiEntry{stream: stream, value: [2]string{strconv.FormatInt(e.Timestamp.UnixNano(), 10), string(line)}}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("loki sink is closed")
+	}
+	select {
+	case s.queue <- entry:
+	default:
+		s.dropped++
+	}
+	return nil
+}
+
+// run ships queued events until the queue is closed and drained.
+func (s *lokiSink) run() {
+	defer close(s.done)
+	for entry := range s.queue {
+		batch := []lokiEntry{entry}
+	fill:
+		for len(batch) < lokiMaxBatch {
+			select {
+			case next, ok := <-s.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, next)
+			default:
+				break fill
+			}
+		}
+		if err := s.push(batch); err != nil {
+			s.failed += len(batch)
+			s.lastErr = err
+		}
+	}
+}
+
+func (s *lokiSink) push(batch []lokiEntry) error {
+	streams := make([]map[string]interface{}, 0, len(batch))
+	for _, entry := range batch {
+		streams = append(streams, map[string]interface{}{
+			"stream": entry.stream,
+			"values": [][2]string{entry.value},
+		})
+	}
+	body, err := json.Marshal(map[string]interface{}{"streams": streams})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("loki push: %s", resp.Status)
+	}
+	return nil
+}
+
+// Close flushes queued events and stops the push goroutine. Pushes still
+// pending after lokiFlushTimeout are abandoned. The error, if any, counts
+// every event that never reached Loki.
+func (s *lokiSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	dropped := s.dropped
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(lokiFlushTimeout):
+		s.cancel()
+		<-s.done
+	}
+	s.cancel()
+
+	switch {
+	case s.failed > 0:
+		return fmt.Errorf("loki: %d event(s) not delivered: %w", s.failed+dropped, s.lastErr)
+	case dropped > 0:
+		return fmt.Errorf("loki: %d event(s) dropped: push queue full", dropped)
+	}
+	return nil
+}
+
+// fileSink appends events as JSON lines, rotating the file by size:
+// path -> path.1 -> ... -> path.<maxFiles>.
+type fileSink struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+	mu       sync.Mutex
+}
+
+func newFileSink(path string, maxSizeMB, maxFiles int) *fileSink {
+	if maxSizeMB <= 0 {
+		maxSizeMB = 10
+	}
+	if maxFiles <= 0 {
+		maxFiles = 5
+	}
+	return &fileSink{path: path, maxSize: int64(maxSizeMB) << 20, maxFiles: maxFiles}
+}
+
+func (s *fileSink) Write(e Event) error {
+	data, err := json.Marshal(newRecord(e))
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if info, err := os.Stat(s.path); err == nil && info.Size()+int64(len(data)) > s.maxSize {
+		s.rotate()
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return err
+}
+
+// rotate shifts path.N to path.N+1, dropping the oldest, and moves path to
+// path.1.
+func (s *fileSink) rotate() {
+	_ = os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxFiles))
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+	}
+	_ = os.Rename(s.path, s.path+".1")
+}
+
+func (s *fileSink) Close() error { return nil }
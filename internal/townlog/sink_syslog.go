@@ -0,0 +1,39 @@
+//go:build !windows
+
+package townlog
+
+import (
+	"encoding/json"
+	"log/syslog"
+)
+
+// syslogSink writes events to syslog as JSON, at a priority derived from
+// the event type.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink(network, address, tag string) (Sink, error) {
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) Write(e Event) error {
+	data, err := json.Marshal(newRecord(e))
+	if err != nil {
+		return err
+	}
+	switch e.Type {
+	case EventCrash, EventMassDeath:
+		return s.w.Err(string(data))
+	case EventSessionDeath, EventEscalationSent:
+		return s.w.Warning(string(data))
+	default:
+		return s.w.Info(string(data))
+	}
+}
+
+func (s *syslogSink) Close() error { return s.w.Close() }
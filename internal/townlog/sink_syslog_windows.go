@@ -0,0 +1,9 @@
+//go:build windows
+
+package townlog
+
+import "errors"
+
+func newSyslogSink(network, address, tag string) (Sink, error) {
+	return nil, errors.New("syslog is not supported on Windows")
+}
@@ -0,0 +1,143 @@
+package townlog
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/offline"
+)
+
+func TestFileSinkRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := newFileSink(path, 1, 2)
+	sink.maxSize = 200 // Force rotation after a couple of events
+
+	for i := 0; i < 6; i++ {
+		if err := sink.Write(Event{Timestamp: time.Now(), Type: EventSpawn, Agent: "gastown/polecats/Toast"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("expected %s: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 rotated files, found %s", path+".3")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.SplitN(string(data), "\n", 2)[0]), &rec); err != nil {
+		t.Fatalf("sink line is not JSON: %v", err)
+	}
+	if rec["type"] != "spawn" || rec["agent"] != "gastown/polecats/Toast" || rec["message"] == "" {
+		t.Errorf("record = %v", rec)
+	}
+}
+
+func TestLokiSink(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/push" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sink, err := NewSink("", config.LogSinkConfig{Type: config.LogSinkLoki, URL: srv.URL, Labels: map[string]string{"host": "h1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(Event{Timestamp: time.Now(), Type: EventCrash, Agent: "gastown/polecats/Toast"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	body := <-bodies
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(body, &push); err != nil || len(push.Streams) != 1 {
+		t.Fatalf("push body = %s (%v)", body, err)
+	}
+	s := push.Streams[0]
+	if s.Stream["job"] != "gastown" || s.Stream["host"] != "h1" || s.Stream["event"] != "crash" {
+		t.Errorf("labels = %v", s.Stream)
+	}
+	if len(s.Values) != 1 || !strings.Contains(s.Values[0][1], `"agent":"gastown/polecats/Toast"`) {
+		t.Errorf("values = %v", s.Values)
+	}
+}
+
+func TestLokiSinkReportsUndelivered(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	sink := newLokiSink(srv.URL, nil)
+	for i := 0; i < 3; i++ {
+		if err := sink.Write(Event{Timestamp: time.Now(), Type: EventSpawn, Agent: "gastown/polecats/Toast"}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	err := sink.Close()
+	if err == nil || !strings.Contains(err.Error(), "3 event(s) not delivered") {
+		t.Errorf("Close = %v, want 3 undelivered events", err)
+	}
+	if err := sink.Write(Event{Timestamp: time.Now(), Type: EventSpawn}); err == nil {
+		t.Error("Write after Close should fail")
+	}
+}
+
+func TestLokiSinkOffline(t *testing.T) {
+	t.Setenv(offline.EnvVar, "1")
+	_, err := NewSink("", config.LogSinkConfig{Type: config.LogSinkLoki, URL: "http://loki:3100"})
+	if !errors.Is(err, offline.ErrOffline) {
+		t.Errorf("NewSink = %v, want an offline error", err)
+	}
+}
+
+func TestLoggerShipsToConfiguredSinks(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Logging = &config.LoggingConfig{Sinks: []config.LogSinkConfig{
+		{Type: config.LogSinkFile, Path: "logs/events.jsonl"},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := NewLogger(townRoot)
+	defer logger.Close()
+	if err := logger.Log(EventDone, "gastown/polecats/Toast", "gt-abc"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, "logs", "events.jsonl"))
+	if err != nil {
+		t.Fatalf("file sink not written: %v", err)
+	}
+	if !strings.Contains(string(data), `"context":"gt-abc"`) {
+		t.Errorf("file sink = %s", data)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // convoyIDPattern validates convoy IDs.
@@ -92,7 +94,7 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := cmdtrace.Run(listCmd); err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -137,7 +139,7 @@ func loadTrackedIssues(townBeads, convoyID string) ([]IssueItem, int, int) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return nil, 0, 0
 	}
 
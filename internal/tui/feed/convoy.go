@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // convoyIDPattern validates convoy IDs.
@@ -98,7 +100,7 @@ func listConvoys(beadsDir, status string) ([]convoyListItem, error) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return nil, err
 	}
 
@@ -169,7 +171,7 @@ func getTrackedIssueStatus(beadsDir, convoyID string) []trackedStatus {
 	cmd.Dir = beadsDir
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
-	if err := cmd.Run(); err != nil {
+	if err := cmdtrace.Run(cmd); err != nil {
 		return nil
 	}
 
@@ -0,0 +1,95 @@
+// Package userhooks runs the lifecycle hooks a town configures in its
+// settings (lifecycle_hooks): user commands that gt starts on town events
+// such as a convoy landing, with a JSON description of the event on stdin.
+package userhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/readonly"
+)
+
+// Payload is the JSON written to a hook's stdin.
+type Payload struct {
+	Event    string      `json:"event"`
+	TownRoot string      `json:"town_root"`
+	Time     time.Time   `json:"time"`
+	Data     interface{} `json:"data"`
+}
+
+// Fire runs the hooks townRoot's settings configure for event, one after
+// another, and returns an error for each that could not start, failed, or
+// timed out. Hooks run in the town root with GT_HOOK_EVENT and
+// GT_TOWN_ROOT set. Nothing runs in read-only mode, since hooks may have
+// side effects gt can't see.
+func Fire(townRoot, event string, data interface{}) []error {
+	if townRoot == "" || readonly.Enabled() {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return []error{fmt.Errorf("%s hooks: %w", event, err)}
+	}
+	hooks := settings.LifecycleHooks.For(event)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(Payload{Event: event, TownRoot: townRoot, Time: time.Now().UTC(), Data: data})
+	if err != nil {
+		return []error{fmt.Errorf("%s hooks: encoding payload: %w", event, err)}
+	}
+	var errs []error
+	for _, h := range hooks {
+		if err := run(townRoot, event, h, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// run starts one hook and waits for it.
+func run(townRoot, event string, h config.LifecycleHook, payload []byte) error {
+	if len(h.Command) == 0 {
+		return nil
+	}
+	timeout := h.TimeoutDuration()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...) //nolint:gosec // G204: hooks come from town settings
+	cmd.Dir = townRoot
+	cmd.Env = append(os.Environ(), "GT_HOOK_EVENT="+event, "GT_TOWN_ROOT="+townRoot)
+	cmd.Stdin = bytes.NewReader(payload)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	name := strings.Join(h.Command, " ")
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%s hook %q timed out after %s", event, name, timeout)
+	}
+	if last := lastLine(output.String()); last != "" {
+		return fmt.Errorf("%s hook %q: %w: %s", event, name, err, last)
+	}
+	return fmt.Errorf("%s hook %q: %w", event, name, err)
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,99 @@
+package userhooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/readonly"
+)
+
+func writeHooks(t *testing.T, townRoot string, hooks *config.LifecycleHooksConfig) {
+	t.Helper()
+	settings := config.NewTownSettings()
+	settings.LifecycleHooks = hooks
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFire(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	townRoot := t.TempDir()
+	out := filepath.Join(townRoot, "payload.json")
+	writeHooks(t, townRoot, &config.LifecycleHooksConfig{
+		OnConvoyComplete: []config.LifecycleHook{
+			{Command: []string{"sh", "-c", `cat > payload.json; echo "$GT_HOOK_EVENT" > event`}},
+			{Command: []string{"sh", "-c", "echo broken >&2; exit 3"}},
+		},
+	})
+
+	errs := Fire(townRoot, config.HookConvoyComplete, map[string]string{"convoy_id": "hq-cv-1"})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken") {
+		t.Errorf("Fire() errors = %v, want the failing hook's output", errs)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p struct {
+		Event    string            `json:"event"`
+		TownRoot string            `json:"town_root"`
+		Data     map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("payload %s: %v", data, err)
+	}
+	if p.Event != config.HookConvoyComplete || p.TownRoot != townRoot || p.Data["convoy_id"] != "hq-cv-1" {
+		t.Errorf("payload = %+v", p)
+	}
+	if event, _ := os.ReadFile(filepath.Join(townRoot, "event")); strings.TrimSpace(string(event)) != config.HookConvoyComplete {
+		t.Errorf("GT_HOOK_EVENT = %q", event)
+	}
+
+	if errs := Fire(townRoot, config.HookDoctorWarning, nil); len(errs) != 0 {
+		t.Errorf("Fire() with no hooks = %v", errs)
+	}
+}
+
+func TestFireTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	townRoot := t.TempDir()
+	writeHooks(t, townRoot, &config.LifecycleHooksConfig{
+		OnDoctorWarning: []config.LifecycleHook{{Command: []string{"sleep", "5"}, Timeout: "100ms"}},
+	})
+	errs := Fire(townRoot, config.HookDoctorWarning, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "timed out") {
+		t.Errorf("Fire() = %v, want a timeout", errs)
+	}
+}
+
+func TestFireSkippedReadOnly(t *testing.T) {
+	townRoot := t.TempDir()
+	writeHooks(t, townRoot, &config.LifecycleHooksConfig{
+		OnDoctorWarning: []config.LifecycleHook{{Command: []string{"false"}}},
+	})
+	t.Setenv(readonly.EnvVar, "1")
+	if errs := Fire(townRoot, config.HookDoctorWarning, nil); len(errs) != 0 {
+		t.Errorf("Fire() in read-only mode = %v, want nothing run", errs)
+	}
+}
+
+func TestSaveTownSettingsRejectsBadHooks(t *testing.T) {
+	settings := config.NewTownSettings()
+	settings.LifecycleHooks = &config.LifecycleHooksConfig{
+		OnConvoyComplete: []config.LifecycleHook{{Command: nil}},
+	}
+	if err := config.SaveTownSettings(filepath.Join(t.TempDir(), "config.json"), settings); err == nil {
+		t.Error("SaveTownSettings() accepted a hook without a command")
+	}
+}
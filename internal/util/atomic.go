@@ -4,12 +4,12 @@ package util
 import (
 	"encoding/json"
 	"os"
+
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // AtomicWriteJSON writes JSON data to a file atomically.
-// It first writes to a temporary file, then renames it to the target path.
-// This prevents data corruption if the process crashes during write.
-// The rename operation is atomic on POSIX systems.
+// See AtomicWriteFile.
 func AtomicWriteJSON(path string, v interface{}) error {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
@@ -19,23 +19,9 @@ func AtomicWriteJSON(path string, v interface{}) error {
 }
 
 // AtomicWriteFile writes data to a file atomically.
-// It first writes to a temporary file, then renames it to the target path.
-// This prevents data corruption if the process crashes during write.
-// The rename operation is atomic on POSIX systems.
+// It writes to a unique temporary file, fsyncs it, then renames it over
+// the target path, so concurrent writers never tear the file.
+// See fsx.WriteFile.
 func AtomicWriteFile(path string, data []byte, perm os.FileMode) error {
-	tmpFile := path + ".tmp"
-
-	// Write to temp file
-	if err := os.WriteFile(tmpFile, data, perm); err != nil {
-		return err
-	}
-
-	// Atomic rename (on POSIX systems)
-	if err := os.Rename(tmpFile, path); err != nil {
-		// Clean up temp file on failure
-		_ = os.Remove(tmpFile)
-		return err
-	}
-
-	return nil
+	return fsx.WriteFile(path, data, perm)
 }
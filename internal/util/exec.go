@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // ExecWithOutput runs a command in the specified directory and returns stdout.
@@ -17,7 +19,7 @@ func ExecWithOutput(workDir, cmd string, args ...string) (string, error) {
 	c.Stdout = &stdout
 	c.Stderr = &stderr
 
-	if err := c.Run(); err != nil {
+	if err := cmdtrace.Run(c); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return "", fmt.Errorf("%s", errMsg)
@@ -37,7 +39,7 @@ func ExecRun(workDir, cmd string, args ...string) error {
 	var stderr bytes.Buffer
 	c.Stderr = &stderr
 
-	if err := c.Run(); err != nil {
+	if err := cmdtrace.Run(c); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return fmt.Errorf("%s", errMsg)
@@ -11,6 +11,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
+	"github.com/steveyegge/gastown/internal/gh"
 )
 
 const (
@@ -892,7 +895,7 @@ func (h *APIHandler) runBdCommand(ctx context.Context, timeout time.Duration, ar
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := cmdtrace.Run(cmd)
 
 	output := stdout.String()
 	if stderr.Len() > 0 {
@@ -1054,7 +1057,11 @@ func (h *APIHandler) handlePRShow(w http.ResponseWriter, r *http.Request) {
 
 	output, err := h.runGhCommand(r.Context(), 15*time.Second, args)
 	if err != nil {
-		h.sendError(w, "Failed to fetch PR: "+err.Error(), http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if gh.IsRateLimited(err) {
+			status = http.StatusTooManyRequests
+		}
+		h.sendError(w, "Failed to fetch PR: "+err.Error(), status)
 		return
 	}
 
@@ -1065,40 +1072,20 @@ func (h *APIHandler) handlePRShow(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-// runGhCommand executes a gh command with the given args.
+// runGhCommand executes a gh command with the given args, backing off
+// if GitHub rate limits it.
 func (h *APIHandler) runGhCommand(ctx context.Context, timeout time.Duration, args []string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "gh", args...)
-	if h.workDir != "" {
-		cmd.Dir = h.workDir
-	}
-	cmd.Stdin = nil
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	output := stdout.String()
-	if stderr.Len() > 0 {
-		if output != "" {
-			output += "\n"
-		}
-		output += stderr.String()
-	}
-
+	out, err := gh.RunContext(ctx, h.workDir, args...)
 	if ctx.Err() == context.DeadlineExceeded {
-		return output, fmt.Errorf("command timed out after %v", timeout)
+		return string(out), fmt.Errorf("command timed out after %v", timeout)
 	}
-
 	if err != nil {
-		return output, fmt.Errorf("command failed: %v", err)
+		return string(out), err
 	}
-
-	return output, nil
+	return string(out), nil
 }
 
 // parsePRShowOutput parses the JSON output from "gh pr view --json".
@@ -13,7 +13,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/gh"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -54,7 +56,7 @@ func runBdCmd(beadsDir string, args ...string) (*bytes.Buffer, error) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	err := cmd.Run()
+	err := cmdtrace.Run(cmd)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("bd timed out after %v", cmdTimeout)
@@ -531,7 +533,9 @@ type prResponse struct {
 
 // fetchPRsForRepo fetches open PRs for a single repo.
 func (f *LiveConvoyFetcher) fetchPRsForRepo(repoFull, repoShort string) ([]MergeQueueRow, error) {
-	stdout, err := runCmd(ghCmdTimeout, "gh", "pr", "list",
+	ctx, cancel := context.WithTimeout(context.Background(), ghCmdTimeout)
+	defer cancel()
+	stdout, err := gh.RunContext(ctx, "", "pr", "list",
 		"--repo", repoFull,
 		"--state", "open",
 		"--json", "number,title,url,mergeable,statusCheckRollup")
@@ -540,7 +544,7 @@ func (f *LiveConvoyFetcher) fetchPRsForRepo(repoFull, repoShort string) ([]Merge
 	}
 
 	var prs []prResponse
-	if err := json.Unmarshal(stdout.Bytes(), &prs); err != nil {
+	if err := json.Unmarshal(stdout, &prs); err != nil {
 		return nil, fmt.Errorf("parsing PRs for %s: %w", repoFull, err)
 	}
 
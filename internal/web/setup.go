@@ -11,6 +11,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/cmdtrace"
 )
 
 // SetupHandler handles the setup flow when no workspace exists.
@@ -282,7 +284,7 @@ func (h *SetupAPIHandler) handleCheckWorkspace(w http.ResponseWriter, r *http.Re
 	var rigs []string
 	cmd := exec.CommandContext(r.Context(), "gt", "rig", "list", "--json")
 	cmd.Dir = path
-	if output, err := cmd.Output(); err == nil {
+	if output, err := cmdtrace.Output(cmd); err == nil {
 		// Parse JSON output for rig names
 		var rigList []struct {
 			Name string `json:"name"`
@@ -334,7 +336,7 @@ func (h *SetupAPIHandler) runGtCommand(ctx context.Context, timeout time.Duratio
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := cmdtrace.Run(cmd)
 	output := stdout.String()
 	if stderr.Len() > 0 {
 		if output != "" {
@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // WispConfigDir is the directory for wisp config storage (never synced via git).
@@ -94,17 +96,9 @@ func (c *Config) save(cfg *ConfigFile) error {
 		return fmt.Errorf("marshal config: %w", err)
 	}
 
-	// Write atomically via temp file
-	tmp := c.filePath + ".tmp"
-	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: wisp config is non-sensitive operational data
-		return fmt.Errorf("write temp: %w", err)
-	}
-
-	if err := os.Rename(tmp, c.filePath); err != nil {
-		_ = os.Remove(tmp) // cleanup on failure
-		return fmt.Errorf("rename: %w", err)
+	if err := fsx.WriteFile(c.filePath, data, 0644); err != nil {
+		return fmt.Errorf("write config: %w", err)
 	}
-
 	return nil
 }
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/fsx"
 )
 
 // EnsureDir ensures the .beads directory exists in the given root.
@@ -28,16 +30,8 @@ func writeJSON(path string, v interface{}) error {
 		return fmt.Errorf("marshal json: %w", err)
 	}
 
-	// Write to temp file then rename for atomicity
-	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: wisp messages are non-sensitive operational data
-		return fmt.Errorf("write temp: %w", err)
-	}
-
-	if err := os.Rename(tmp, path); err != nil {
-		_ = os.Remove(tmp) // cleanup on failure
-		return fmt.Errorf("rename: %w", err)
+	if err := fsx.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write json: %w", err)
 	}
-
 	return nil
 }
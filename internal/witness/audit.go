@@ -0,0 +1,205 @@
+package witness
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// AuditLogFile is the audit log filename inside a rig's witness/ directory.
+const AuditLogFile = "audit.jsonl"
+
+// auditLockTimeout bounds how long Record waits for another writer.
+const auditLockTimeout = 5 * time.Second
+
+// Audit actions recorded by gt.
+const (
+	ActionFormulaRun      = "formula.run"
+	ActionFormulaOverride = "formula.override"
+	ActionBeadCreate      = "bead.create"
+	ActionConfigChange    = "config.change"
+	ActionDoctorFix       = "doctor.fix"
+	ActionSynthesisClose  = "synthesis.close"
+	ActionConvoyApprove   = "convoy.approve"
+)
+
+// ErrAuditTampered indicates the audit log hash chain is broken.
+var ErrAuditTampered = errors.New("audit log tampered")
+
+// AuditEntry is a single record in the append-only audit log.
+// Each entry's Hash covers its content and the previous entry's hash,
+// so editing, reordering, or deleting entries breaks the chain.
+type AuditEntry struct {
+	Seq      int               `json:"seq"`
+	Time     time.Time         `json:"time"`
+	Actor    string            `json:"actor,omitempty"`
+	Action   string            `json:"action"`
+	Subject  string            `json:"subject,omitempty"`
+	Details  map[string]string `json:"details,omitempty"`
+	PrevHash string            `json:"prev_hash"`
+	Hash     string            `json:"hash"`
+}
+
+// computeHash returns the chained hash for an entry (ignoring its Hash field).
+func (e AuditEntry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(e.PrevHash+"\n"), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditLogPath returns the audit log path for a rig.
+func AuditLogPath(rigPath string) string {
+	return filepath.Join(rigPath, "witness", AuditLogFile)
+}
+
+// Record appends an entry to the rig's audit log, filling in Seq, Time,
+// PrevHash, and Hash. Concurrent writers are serialized with a file lock.
+func Record(rigPath string, entry AuditEntry) error {
+	path := AuditLogPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating witness dir: %w", err)
+	}
+
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), auditLockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("acquiring audit lock: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("timeout waiting for audit lock")
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	entries, err := ReadAuditLog(rigPath)
+	if err != nil {
+		return err
+	}
+
+	entry.Seq = 1
+	entry.PrevHash = ""
+	if n := len(entries); n > 0 {
+		entry.Seq = entries[n-1].Seq + 1
+		entry.PrevHash = entries[n-1].Hash
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	if entry.Actor == "" {
+		entry.Actor = auditActor()
+	}
+	entry.Hash, err = entry.computeHash()
+	if err != nil {
+		return fmt.Errorf("hashing audit entry: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding audit entry: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: audit log is read by humans and tools
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return nil
+}
+
+// ReadAuditLog returns all entries in the rig's audit log.
+// A missing log returns no entries and no error.
+func ReadAuditLog(rigPath string) ([]AuditEntry, error) {
+	f, err := os.Open(AuditLogPath(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return entries, fmt.Errorf("%w: line %d is not valid JSON", ErrAuditTampered, line)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("reading audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// VerifyAuditLog checks the hash chain of the rig's audit log.
+// Returns the number of verified entries, or ErrAuditTampered (wrapped)
+// identifying the first entry that fails verification.
+func VerifyAuditLog(rigPath string) (int, error) {
+	entries, err := ReadAuditLog(rigPath)
+	if err != nil {
+		return 0, err
+	}
+	return verifyEntries(entries)
+}
+
+func verifyEntries(entries []AuditEntry) (int, error) {
+	prevHash := ""
+	for i, e := range entries {
+		if e.Seq != i+1 {
+			return i, fmt.Errorf("%w: entry %d has seq %d (entries missing or reordered)", ErrAuditTampered, i+1, e.Seq)
+		}
+		if e.PrevHash != prevHash {
+			return i, fmt.Errorf("%w: entry %d does not chain to entry %d", ErrAuditTampered, e.Seq, e.Seq-1)
+		}
+		want, err := e.computeHash()
+		if err != nil {
+			return i, err
+		}
+		if e.Hash != want {
+			return i, fmt.Errorf("%w: entry %d content does not match its hash", ErrAuditTampered, e.Seq)
+		}
+		prevHash = e.Hash
+	}
+	return len(entries), nil
+}
+
+// auditActor identifies who performed an action: the Gas Town agent role
+// when running inside an agent session, otherwise the OS user.
+func auditActor() string {
+	if actor := os.Getenv("BD_ACTOR"); actor != "" {
+		return actor
+	}
+	if role := os.Getenv("GT_ROLE"); role != "" {
+		return role
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "unknown"
+}
@@ -0,0 +1,82 @@
+package witness
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAuditRecordAndVerify(t *testing.T) {
+	rigPath := t.TempDir()
+
+	for _, action := range []string{ActionFormulaRun, ActionBeadCreate, ActionDoctorFix} {
+		if err := Record(rigPath, AuditEntry{Action: action, Subject: "hq-cv-abc", Actor: "tester"}); err != nil {
+			t.Fatalf("Record(%s) error = %v", action, err)
+		}
+	}
+
+	entries, err := ReadAuditLog(rigPath)
+	if err != nil {
+		t.Fatalf("ReadAuditLog() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("len(entries) = %d, want 3", len(entries))
+	}
+	if entries[1].PrevHash != entries[0].Hash || entries[2].Seq != 3 {
+		t.Errorf("entries not chained: %+v", entries)
+	}
+
+	n, err := VerifyAuditLog(rigPath)
+	if err != nil || n != 3 {
+		t.Errorf("VerifyAuditLog() = %d, %v; want 3, nil", n, err)
+	}
+}
+
+func TestAuditVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(string) string
+	}{
+		{"edited entry", func(s string) string { return strings.Replace(s, "hq-cv-2", "hq-cv-X", 1) }},
+		{"deleted entry", func(s string) string {
+			lines := strings.SplitAfter(s, "\n")
+			return lines[0] + lines[2]
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rigPath := t.TempDir()
+			for _, subject := range []string{"hq-cv-1", "hq-cv-2", "hq-cv-3"} {
+				if err := Record(rigPath, AuditEntry{Action: ActionFormulaRun, Subject: subject}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			path := AuditLogPath(rigPath)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(tt.tamper(string(data))), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			n, err := VerifyAuditLog(rigPath)
+			if !errors.Is(err, ErrAuditTampered) {
+				t.Fatalf("VerifyAuditLog() error = %v, want ErrAuditTampered", err)
+			}
+			if n != 1 {
+				t.Errorf("verified %d entries before break, want 1", n)
+			}
+		})
+	}
+}
+
+func TestAuditMissingLog(t *testing.T) {
+	n, err := VerifyAuditLog(t.TempDir())
+	if err != nil || n != 0 {
+		t.Errorf("VerifyAuditLog(empty) = %d, %v; want 0, nil", n, err)
+	}
+}
@@ -678,25 +678,29 @@ func UpdateCleanupWispState(workDir, wispID, newState string) error {
 }
 
 // NukePolecat executes the actual nuke operation for a polecat.
-// This kills the tmux session, removes the worktree, and cleans up beads.
+// This kills the session, removes the worktree, and cleans up beads.
 // Should only be called after all safety checks pass.
 func NukePolecat(workDir, rigName, polecatName string) error {
-	// CRITICAL: Kill the tmux session FIRST and unconditionally.
+	// CRITICAL: Kill the session FIRST and unconditionally.
 	// The session name follows the pattern gt-<rig>-<polecat>.
 	// We do this explicitly here because gt polecat nuke may fail to kill the
 	// session due to rig loading issues or race conditions with IsRunning checks.
 	// See: gt-g9ft5 - sessions were piling up because nuke wasn't killing them.
 	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
 	t := tmux.NewTmux()
+	townRoot, _ := workspace.Find(workDir)
+	sessions := session.OpenBackend(townRoot, t)
 
 	// Check if session exists and kill it
-	if running, _ := t.HasSession(sessionName); running {
-		// Try graceful shutdown first (Ctrl-C), then force kill
-		_ = t.SendKeysRaw(sessionName, "C-c")
-		// Brief delay for graceful handling
-		time.Sleep(100 * time.Millisecond)
+	if running, _ := sessions.HasSession(sessionName); running {
+		if sessions.Type() == session.BackendTmux {
+			// Try graceful shutdown first (Ctrl-C)
+			_ = t.SendKeysRaw(sessionName, "C-c")
+			// Brief delay for graceful handling
+			time.Sleep(100 * time.Millisecond)
+		}
 		// Force kill the session
-		if err := t.KillSession(sessionName); err != nil {
+		if err := sessions.KillSession(sessionName); err != nil {
 			// Log but continue - session might already be dead
 			// The important thing is we tried
 		}
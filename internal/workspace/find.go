@@ -27,7 +27,10 @@ const (
 )
 
 // Find locates the town root by walking up from the given directory.
-// It prefers mayor/town.json over mayor/ directory as workspace marker.
+// The explicit TownMarker wins over the heuristics: the nearest directory
+// carrying one is the town root, even above a mayor/town.json a rig clone
+// happens to contain. Towns without the marker fall back to mayor/town.json,
+// then to a mayor/ directory.
 // When in a worktree path (polecats/ or crew/), continues to outermost workspace.
 // Does not resolve symlinks to stay consistent with os.Getwd().
 func Find(startDir string) (string, error) {
@@ -37,27 +40,38 @@ func Find(startDir string) (string, error) {
 	}
 
 	inWorktree := isInWorktreePath(absDir)
-	var primaryMatch, secondaryMatch string
+	var markerMatch, primaryMatch, secondaryMatch string
 
 	current := absDir
 	for {
-		if _, err := os.Stat(filepath.Join(current, PrimaryMarker)); err == nil {
+		if HasMarker(current) {
 			if !inWorktree {
 				return current, nil
 			}
-			primaryMatch = current
+			markerMatch = current
+		}
+
+		// Outside worktrees the nearest mayor/town.json is the root, unless
+		// a marker turns up further up.
+		if _, err := os.Stat(filepath.Join(current, PrimaryMarker)); err == nil {
+			if inWorktree || primaryMatch == "" {
+				primaryMatch = current
+			}
 		}
 
 		// Always keep updating secondaryMatch to find the outermost mayor/ directory.
 		// This handles nested structures where rigs have their own mayor/ directories
 		// but only the town root should be detected as the workspace.
-		// The primary marker (mayor/town.json) is authoritative and returns early above.
+		// The primary marker (mayor/town.json) takes precedence over it below.
 		if info, err := os.Stat(filepath.Join(current, SecondaryMarker)); err == nil && info.IsDir() {
 			secondaryMatch = current
 		}
 
 		parent := filepath.Dir(current)
 		if parent == current {
+			if markerMatch != "" {
+				return markerMatch, nil
+			}
 			if primaryMatch != "" {
 				return primaryMatch, nil
 			}
@@ -101,7 +115,7 @@ func FindFromCwdOrError() (string, error) {
 		// Fallback: try GT_TOWN_ROOT env var (set by polecat sessions)
 		if townRoot := os.Getenv("GT_TOWN_ROOT"); townRoot != "" {
 			// Verify it's actually a workspace
-			if _, statErr := os.Stat(filepath.Join(townRoot, PrimaryMarker)); statErr == nil {
+			if isTownRoot(townRoot) {
 				return townRoot, nil
 			}
 		}
@@ -120,7 +134,7 @@ func FindFromCwdWithFallback() (townRoot string, cwd string, err error) {
 		// Fallback: try GT_TOWN_ROOT env var
 		if townRoot = os.Getenv("GT_TOWN_ROOT"); townRoot != "" {
 			// Verify it's actually a workspace
-			if _, statErr := os.Stat(filepath.Join(townRoot, PrimaryMarker)); statErr == nil {
+			if isTownRoot(townRoot) {
 				return townRoot, "", nil // cwd is gone but townRoot is valid
 			}
 		}
@@ -135,14 +149,18 @@ func FindFromCwdWithFallback() (townRoot string, cwd string, err error) {
 }
 
 // IsWorkspace checks if the given directory is a Gas Town workspace root.
-// A directory is a workspace if it has a primary marker (mayor/town.json)
-// or a secondary marker (mayor/ directory).
+// A directory is a workspace if it has a TownMarker, a primary marker
+// (mayor/town.json) or a secondary marker (mayor/ directory).
 func IsWorkspace(dir string) (bool, error) {
 	absDir, err := filepath.Abs(dir)
 	if err != nil {
 		return false, fmt.Errorf("resolving path: %w", err)
 	}
 
+	if HasMarker(absDir) {
+		return true, nil
+	}
+
 	// Check for primary marker (mayor/town.json)
 	primaryPath := filepath.Join(absDir, PrimaryMarker)
 	if _, err := os.Stat(primaryPath); err == nil {
@@ -0,0 +1,81 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/fsx"
+)
+
+// TownMarker is the file at a town root that explicitly identifies it as
+// one. gt install writes it; Find prefers it over the mayor/ heuristics,
+// which rig clones can also match.
+const TownMarker = ".gastown-town.json"
+
+// CurrentMarkerSchema is the TownMarker schema this gt writes and reads.
+const CurrentMarkerSchema = 1
+
+// Marker is the content of a TownMarker file.
+type Marker struct {
+	Schema    int       `json:"schema"`
+	Name      string    `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HasMarker reports whether dir has a TownMarker file.
+func HasMarker(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, TownMarker))
+	return err == nil && !info.IsDir()
+}
+
+// ReadMarker reads townRoot's TownMarker. It fails if the marker is
+// missing, malformed, or from a newer gt.
+func ReadMarker(townRoot string) (*Marker, error) {
+	path := filepath.Join(townRoot, TownMarker)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the town root's marker
+	if err != nil {
+		return nil, fmt.Errorf("reading town marker: %w", err)
+	}
+	var m Marker
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if m.Schema < 1 {
+		return nil, fmt.Errorf("%s: schema is missing", path)
+	}
+	if m.Schema > CurrentMarkerSchema {
+		return nil, fmt.Errorf("%s: schema %d is newer than this gt supports (%d); upgrade gt", path, m.Schema, CurrentMarkerSchema)
+	}
+	return &m, nil
+}
+
+// WriteMarker writes townRoot's TownMarker for the town named name. It is
+// how gt install creates towns and how gt doctor --fix migrates towns
+// created before the marker existed.
+func WriteMarker(townRoot, name string) error {
+	data, err := json.MarshalIndent(Marker{
+		Schema:    CurrentMarkerSchema,
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding town marker: %w", err)
+	}
+	if err := fsx.WriteFile(filepath.Join(townRoot, TownMarker), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing town marker: %w", err)
+	}
+	return nil
+}
+
+// isTownRoot reports whether dir carries an authoritative town marker:
+// TownMarker, or for towns that predate it, mayor/town.json.
+func isTownRoot(dir string) bool {
+	if HasMarker(dir) {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(dir, PrimaryMarker))
+	return err == nil
+}
@@ -0,0 +1,106 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteAndReadMarker(t *testing.T) {
+	root := t.TempDir()
+	if HasMarker(root) {
+		t.Fatal("HasMarker() = true before writing")
+	}
+	if err := WriteMarker(root, "mytown"); err != nil {
+		t.Fatalf("WriteMarker: %v", err)
+	}
+	if !HasMarker(root) {
+		t.Fatal("HasMarker() = false after writing")
+	}
+	m, err := ReadMarker(root)
+	if err != nil {
+		t.Fatalf("ReadMarker: %v", err)
+	}
+	if m.Schema != CurrentMarkerSchema || m.Name != "mytown" || m.CreatedAt.IsZero() {
+		t.Errorf("ReadMarker = %+v", m)
+	}
+}
+
+func TestReadMarkerRejectsBadSchema(t *testing.T) {
+	tests := map[string]string{
+		"newer":   `{"schema": 99}`,
+		"missing": `{"name": "x"}`,
+		"garbled": `{schema`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, TownMarker), []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadMarker(root); err == nil {
+				t.Error("ReadMarker() succeeded")
+			}
+			if !HasMarker(root) {
+				t.Error("HasMarker() = false; detection shouldn't depend on the content")
+			}
+		})
+	}
+
+	root := t.TempDir()
+	_ = os.WriteFile(filepath.Join(root, TownMarker), []byte(`{"schema": 99}`), 0644)
+	if _, err := ReadMarker(root); err == nil || !strings.Contains(err.Error(), "upgrade gt") {
+		t.Errorf("ReadMarker() = %v, want an upgrade hint", err)
+	}
+}
+
+func TestFindPrefersMarker(t *testing.T) {
+	root := realPath(t, t.TempDir())
+	if err := WriteMarker(root, "town"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rig clone whose repo happens to contain mayor/town.json
+	clone := filepath.Join(root, "myrig", "mayor", "rig")
+	if err := os.MkdirAll(filepath.Join(clone, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(clone, "mayor", "town.json"), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := Find(filepath.Join(clone, "mayor"))
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if found != root {
+		t.Errorf("Find = %q, want the marked root %q", found, root)
+	}
+
+	ok, err := IsWorkspace(root)
+	if err != nil || !ok {
+		t.Errorf("IsWorkspace(marked root) = %v, %v", ok, err)
+	}
+}
+
+func TestFindWithoutMarkerKeepsNearestTownJSON(t *testing.T) {
+	root := realPath(t, t.TempDir())
+	inner := filepath.Join(root, "inner")
+	for _, dir := range []string{root, inner} {
+		if err := os.MkdirAll(filepath.Join(dir, "mayor"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, PrimaryMarker), []byte(`{"type":"town"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := Find(inner)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if found != inner {
+		t.Errorf("Find = %q, want the nearest town %q", found, inner)
+	}
+}